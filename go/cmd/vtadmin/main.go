@@ -44,9 +44,10 @@ var (
 	defaultClusterConfig  cluster.Config
 	enableDynamicClusters bool
 
-	rbacConfigPath string
-	enableRBAC     bool
-	disableRBAC    bool
+	rbacConfigPath           string
+	rbacConfigReloadInterval time.Duration
+	enableRBAC               bool
+	disableRBAC              bool
 
 	cacheRefreshKey string
 
@@ -116,6 +117,10 @@ func run(cmd *cobra.Command, args []string) {
 		}
 
 		rbacConfig = cfg
+
+		if rbacConfigReloadInterval > 0 {
+			go rbac.WatchConfig(context.Background(), rbacConfigPath, rbacConfigReloadInterval, cfg.GetAuthorizer())
+		}
 	} else if enableRBAC && rbacConfigPath == "" {
 		fatal("must pass --rbac-config path when enabling rbac")
 	} else {
@@ -193,6 +198,7 @@ func main() {
 
 	// RBAC flags
 	rootCmd.Flags().StringVar(&rbacConfigPath, "rbac-config", "", "path to an RBAC config file. must be set if passing --rbac")
+	rootCmd.Flags().DurationVar(&rbacConfigReloadInterval, "rbac-config-reload-interval", 0, "how often to check the RBAC config file for changes and reload its rules. omit to disable reloading")
 	rootCmd.Flags().BoolVar(&enableRBAC, "rbac", false, "whether to enable RBAC. must be set if not passing --rbac")
 	rootCmd.Flags().BoolVar(&disableRBAC, "no-rbac", false, "whether to disable RBAC. must be set if not passing --no-rbac")
 
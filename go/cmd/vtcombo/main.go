@@ -46,6 +46,7 @@ import (
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topotools"
 	"vitess.io/vitess/go/vt/vtcombo"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil"
 	"vitess.io/vitess/go/vt/vtctld"
 	"vitess.io/vitess/go/vt/vtgate"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
@@ -159,6 +160,7 @@ func main() {
 
 	servenv.Init()
 	tabletenv.Init()
+	reparentutil.LoadDurabilityPoliciesFile()
 
 	var mysqld *mysqlctl.Mysqld
 	var cnf *mysqlctl.Mycnf
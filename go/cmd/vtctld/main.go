@@ -20,6 +20,7 @@ import (
 	"vitess.io/vitess/go/exit"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil"
 	"vitess.io/vitess/go/vt/vtctld"
 )
 
@@ -37,6 +38,8 @@ func main() {
 	servenv.Init()
 	defer servenv.Close()
 
+	reparentutil.LoadDurabilityPoliciesFile()
+
 	ts = topo.Open()
 	defer ts.Close()
 
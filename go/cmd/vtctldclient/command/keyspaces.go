@@ -98,7 +98,8 @@ Otherwise, the keyspace must be empty (have no shards), or returns an error.`,
 		Short: "Sets the durability-policy used by the specified keyspace.",
 		Long: `Sets the durability-policy used by the specified keyspace. 
 Durability policy governs the durability of the keyspace by describing which tablets should be sending semi-sync acknowledgements to the primary.
-Possible values include 'semi_sync', 'none' and others as dictated by registered plugins.
+Possible values include 'semi_sync', 'none' and others as dictated by registered plugins,
+or declared in the --durability_policies_file of vtctld and vtorc.
 
 To set the durability policy of customer keyspace to semi_sync, you would use the following command:
 SetKeyspaceDurabilityPolicy --durability-policy='semi_sync' customer`,
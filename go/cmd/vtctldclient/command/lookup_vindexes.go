@@ -0,0 +1,228 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/json2"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// LookupVindexCreate makes a LookupVindexCreate gRPC call to a vtctld.
+	LookupVindexCreate = &cobra.Command{
+		Use:   "LookupVindexCreate [--cells=c1,c2,...] [--tablet-types=REPLICA,...] [--continue-after-copy-with-owner] <keyspace> <json_spec>",
+		Short: "Creates a write_only lookup vindex and backfills its lookup table with a VReplication workflow.",
+		Long: `Creates a write_only lookup vindex and backfills its lookup table with a VReplication workflow.
+
+The JSON spec holds exactly one lookup vindex and the one table of <keyspace> it is
+added to, in VSchema format. The lookup table is created in the keyspace named by the
+vindex's "table" param, where a workflow named <lookup_table>_vdx backfills it.
+
+Use LookupVindexShow to follow the backfill, LookupVindexVerify to compare the lookup
+table with its source, and LookupVindexExternalize to start using the vindex.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandLookupVindexCreate,
+	}
+	// LookupVindexExternalize makes a LookupVindexExternalize gRPC call to a vtctld.
+	LookupVindexExternalize = &cobra.Command{
+		Use:                   "LookupVindexExternalize <keyspace> <vindex>",
+		Short:                 "Makes a backfilled lookup vindex usable for queries, deleting its workflow if the vindex is owned.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandLookupVindexExternalize,
+	}
+	// LookupVindexShow makes a LookupVindexShow gRPC call to a vtctld.
+	LookupVindexShow = &cobra.Command{
+		Use:                   "LookupVindexShow <keyspace> <vindex>",
+		Short:                 "Shows a lookup vindex along with the progress and lag of the workflow backfilling it.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandLookupVindexShow,
+	}
+	// LookupVindexVerify makes a LookupVindexVerify gRPC call to a vtctld.
+	LookupVindexVerify = &cobra.Command{
+		Use:   "LookupVindexVerify [--show {<uuid>|last|all}] <keyspace> <vindex>",
+		Short: "Starts a VDiff of the workflow backfilling a lookup vindex, or shows its results.",
+		Long: `Starts a VDiff of the workflow backfilling a lookup vindex, comparing the lookup
+table with the rows of the table it indexes, and prints the UUID of the VDiff.
+
+With --show, prints the results of that VDiff instead.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandLookupVindexVerify,
+	}
+)
+
+var lookupVindexCreateOptions = struct {
+	Cells                      []string
+	TabletTypes                []string
+	ContinueAfterCopyWithOwner bool
+}{}
+
+func commandLookupVindexCreate(cmd *cobra.Command, args []string) error {
+	var specs vschemapb.Keyspace
+	if err := json2.Unmarshal([]byte(cmd.Flags().Arg(1)), &specs); err != nil {
+		return fmt.Errorf("cannot parse vindex spec: %w", err)
+	}
+
+	tabletTypes := make([]topodatapb.TabletType, 0, len(lookupVindexCreateOptions.TabletTypes))
+	for _, tt := range lookupVindexCreateOptions.TabletTypes {
+		tabletType, err := topoproto.ParseTabletType(tt)
+		if err != nil {
+			return err
+		}
+		tabletTypes = append(tabletTypes, tabletType)
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.LookupVindexCreate(commandCtx, &vtctldatapb.LookupVindexCreateRequest{
+		Keyspace:                   cmd.Flags().Arg(0),
+		Vindex:                     &specs,
+		Cells:                      lookupVindexCreateOptions.Cells,
+		TabletTypes:                tabletTypes,
+		ContinueAfterCopyWithOwner: lookupVindexCreateOptions.ContinueAfterCopyWithOwner,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+func commandLookupVindexExternalize(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.LookupVindexExternalize(commandCtx, &vtctldatapb.LookupVindexExternalizeRequest{
+		Keyspace: cmd.Flags().Arg(0),
+		Name:     cmd.Flags().Arg(1),
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+func commandLookupVindexShow(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.LookupVindexShow(commandCtx, &vtctldatapb.LookupVindexShowRequest{
+		Keyspace: cmd.Flags().Arg(0),
+		Name:     cmd.Flags().Arg(1),
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+var lookupVindexVerifyOptions = struct {
+	Show        string
+	TabletTypes string
+	Timeout     time.Duration
+}{}
+
+func commandLookupVindexVerify(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	req := &vtctldatapb.LookupVindexVerifyRequest{
+		Keyspace: cmd.Flags().Arg(0),
+		Name:     cmd.Flags().Arg(1),
+		Show:     lookupVindexVerifyOptions.Show,
+	}
+	if req.Show == "" {
+		req.Options = &tabletmanagerdatapb.VDiffOptions{
+			PickerOptions: &tabletmanagerdatapb.VDiffPickerOptions{
+				TabletTypes: lookupVindexVerifyOptions.TabletTypes,
+			},
+			CoreOptions: &tabletmanagerdatapb.VDiffCoreOptions{
+				MaxRows:        math.MaxInt64,
+				SamplePct:      100,
+				TimeoutSeconds: int64(lookupVindexVerifyOptions.Timeout.Seconds()),
+			},
+			ReportOptions: &tabletmanagerdatapb.VDiffReportOptions{
+				Format: "json",
+			},
+		}
+	}
+
+	resp, err := client.LookupVindexVerify(commandCtx, req)
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+func init() {
+	LookupVindexCreate.Flags().StringSliceVarP(&lookupVindexCreateOptions.Cells, "cells", "c", nil, "Cells to stream the backfill from. Defaults to the cell of each target primary.")
+	LookupVindexCreate.Flags().StringSliceVar(&lookupVindexCreateOptions.TabletTypes, "tablet-types", nil, "Source tablet types to stream the backfill from.")
+	LookupVindexCreate.Flags().BoolVar(&lookupVindexCreateOptions.ContinueAfterCopyWithOwner, "continue-after-copy-with-owner", false, "Keep the backfill of an owned vindex running after its copy phase, instead of stopping it.")
+	Root.AddCommand(LookupVindexCreate)
+
+	Root.AddCommand(LookupVindexExternalize)
+
+	Root.AddCommand(LookupVindexShow)
+
+	LookupVindexVerify.Flags().StringVar(&lookupVindexVerifyOptions.Show, "show", "", "Show the results of the VDiff with this UUID, or of the last or all VDiffs, instead of starting one.")
+	LookupVindexVerify.Flags().StringVar(&lookupVindexVerifyOptions.TabletTypes, "tablet-types", "in_order:RDONLY,REPLICA,PRIMARY", "Source tablet types to compare against.")
+	LookupVindexVerify.Flags().DurationVar(&lookupVindexVerifyOptions.Timeout, "filtered-replication-wait-time", 30*time.Second, "How long to wait for the backfill to catch up before comparing.")
+	Root.AddCommand(LookupVindexVerify)
+}
@@ -93,6 +93,7 @@ var emergencyReparentShardOptions = struct {
 	NewPrimaryAliasStr        string
 	IgnoreReplicaAliasStrList []string
 	PreventCrossCellPromotion bool
+	DryRun                    bool
 }{}
 
 func commandEmergencyReparentShard(cmd *cobra.Command, args []string) error {
@@ -131,6 +132,7 @@ func commandEmergencyReparentShard(cmd *cobra.Command, args []string) error {
 		IgnoreReplicas:            ignoreReplicaAliases,
 		WaitReplicasTimeout:       protoutil.DurationToProto(emergencyReparentShardOptions.WaitReplicasTimeout),
 		PreventCrossCellPromotion: emergencyReparentShardOptions.PreventCrossCellPromotion,
+		DryRun:                    emergencyReparentShardOptions.DryRun,
 	})
 	if err != nil {
 		return err
@@ -140,6 +142,16 @@ func commandEmergencyReparentShard(cmd *cobra.Command, args []string) error {
 		fmt.Println(logutil.EventString(event))
 	}
 
+	if emergencyReparentShardOptions.DryRun {
+		resp.Events = nil
+		data, err := cli.MarshalJSON(resp)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n", data)
+	}
+
 	return nil
 }
 
@@ -183,6 +195,7 @@ var plannedReparentShardOptions = struct {
 	NewPrimaryAliasStr   string
 	AvoidPrimaryAliasStr string
 	WaitReplicasTimeout  time.Duration
+	DryRun               bool
 }{}
 
 func commandPlannedReparentShard(cmd *cobra.Command, args []string) error {
@@ -218,6 +231,7 @@ func commandPlannedReparentShard(cmd *cobra.Command, args []string) error {
 		NewPrimary:          newPrimaryAlias,
 		AvoidPrimary:        avoidPrimaryAlias,
 		WaitReplicasTimeout: protoutil.DurationToProto(plannedReparentShardOptions.WaitReplicasTimeout),
+		DryRun:              plannedReparentShardOptions.DryRun,
 	})
 	if err != nil {
 		return err
@@ -227,6 +241,16 @@ func commandPlannedReparentShard(cmd *cobra.Command, args []string) error {
 		fmt.Println(logutil.EventString(event))
 	}
 
+	if plannedReparentShardOptions.DryRun {
+		resp.Events = nil
+		data, err := cli.MarshalJSON(resp)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n", data)
+	}
+
 	return nil
 }
 
@@ -281,6 +305,7 @@ func init() {
 	EmergencyReparentShard.Flags().StringVar(&emergencyReparentShardOptions.NewPrimaryAliasStr, "new-primary", "", "Alias of a tablet that should be the new primary. If not specified, the vtctld will select the best candidate to promote.")
 	EmergencyReparentShard.Flags().BoolVar(&emergencyReparentShardOptions.PreventCrossCellPromotion, "prevent-cross-cell-promotion", false, "Only promotes a new primary from the same cell as the previous primary.")
 	EmergencyReparentShard.Flags().StringSliceVarP(&emergencyReparentShardOptions.IgnoreReplicaAliasStrList, "ignore-replicas", "i", nil, "Comma-separated, repeated list of replica tablet aliases to ignore during the emergency reparent.")
+	EmergencyReparentShard.Flags().BoolVar(&emergencyReparentShardOptions.DryRun, "dry-run", false, "Only print the tablet that would be promoted, the tablets that would not be considered and why, and the steps of the reparent, without stopping replication or modifying anything.")
 	Root.AddCommand(EmergencyReparentShard)
 
 	InitShardPrimary.Flags().DurationVar(&initShardPrimaryOptions.WaitReplicasTimeout, "wait-replicas-timeout", 30*time.Second, "Time to wait for replicas to catch up in reparenting.")
@@ -290,6 +315,7 @@ func init() {
 	PlannedReparentShard.Flags().DurationVar(&plannedReparentShardOptions.WaitReplicasTimeout, "wait-replicas-timeout", *topo.RemoteOperationTimeout, "Time to wait for replicas to catch up on replication both before and after reparenting.")
 	PlannedReparentShard.Flags().StringVar(&plannedReparentShardOptions.NewPrimaryAliasStr, "new-primary", "", "Alias of a tablet that should be the new primary.")
 	PlannedReparentShard.Flags().StringVar(&plannedReparentShardOptions.AvoidPrimaryAliasStr, "avoid-primary", "", "Alias of a tablet that should not be the primary; i.e. \"reparent to any other tablet if this one is the primary\".")
+	PlannedReparentShard.Flags().BoolVar(&plannedReparentShardOptions.DryRun, "dry-run", false, "Only print the tablet that would be promoted, the tablets that would not be considered and why, and the steps of the reparent, without modifying anything.")
 	Root.AddCommand(PlannedReparentShard)
 
 	Root.AddCommand(ReparentTablet)
@@ -20,6 +20,8 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -40,7 +42,7 @@ import (
 var (
 	// ApplySchema makes an ApplySchema gRPC call to a vtctld.
 	ApplySchema = &cobra.Command{
-		Use:   "ApplySchema [--allow-long-unavailability] [--ddl-strategy <strategy>] [--uuid <uuid> ...] [--migration-context <context>] [--wait-replicas-timeout <duration>] [--skip-preflight] [--caller-id <caller_id>] {--sql-file <file> | --sql <sql>} <keyspace>",
+		Use:   "ApplySchema [--allow-long-unavailability] [--ddl-strategy <strategy>] [--uuid <uuid> ...] [--migration-context <context>] [--wait-replicas-timeout <duration>] [--skip-preflight] [--caller-id <caller_id>] [--dry-run] {--sql-file <file> | --sql <sql>} <keyspace>",
 		Short: "Applies the schema change to the specified keyspace on every primary, running in parallel on all shards. The changes are then propagated to replicas via replication.",
 		Long: `Applies the schema change to the specified keyspace on every primary, running in parallel on all shards. The changes are then propagated to replicas via replication.
 
@@ -48,6 +50,8 @@ If --allow-long-unavailability is set, schema changes affecting a large number o
 --ddl-strategy is used to instruct migrations via vreplication, gh-ost or pt-osc with optional parameters.
 --migration-context allows the user to specify a custom migration context for online DDL migrations.
 If --skip-preflight, SQL goes directly to shards without going through sanity checks.
+With --dry-run, the schema changes are not applied. Instead, they are checked against the current schema of the keyspace, and the findings are printed:
+destructive changes, changes that vreplication based Online DDL rejects for lack of a shared unique key, lossy column type changes, and changes that can run with ALGORITHM=INSTANT.
 
 The --uuid and --sql flags are repeatable, so they can be passed multiple times to build a list of values.
 For --uuid, this is used like "--uuid $first_uuid --uuid $second_uuid".
@@ -59,6 +63,22 @@ For --sql, semi-colons and repeated values may be mixed, for example:
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandApplySchema,
 	}
+	// DeploySchema makes a DeploySchema gRPC call to a vtctld.
+	DeploySchema = &cobra.Command{
+		Use:   "DeploySchema [--dry-run] [--allow-drops] [--heuristic-table-renames] [--heuristic-column-renames] [--ddl-strategy <strategy>] [--migration-context <context>] [--wait-replicas-timeout <duration>] [--skip-preflight] [--allow-long-unavailability] [--caller-id <caller_id>] --schema-dir <dir> <keyspace>",
+		Short: "Makes the schema of the specified keyspace match the CREATE TABLE and CREATE VIEW statements in a directory.",
+		Long: `Makes the schema of the specified keyspace match the CREATE TABLE and CREATE VIEW statements in a directory.
+
+The statements are read from all the .sql files in --schema-dir, and compared with the schema of the keyspace.
+The primaries of all the shards must have the same schema.
+The resulting schema changes are printed, and applied as with ApplySchema unless --dry-run is set.
+Tables, views, stored programs and columns that are not in --schema-dir are dropped, which requires --allow-drops.
+
+With --heuristic-table-renames and --heuristic-column-renames, tables and columns that look like they were renamed are renamed, instead of being dropped and created again.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandDeploySchema,
+	}
 	// GetSchema makes a GetSchema gRPC call to a vtctld.
 	GetSchema = &cobra.Command{
 		Use:                   "GetSchema [--tables TABLES ...] [--exclude-tables EXCLUDE_TABLES ...] [{--table-names-only | --table-sizes-only}] [--include-views] alias",
@@ -103,6 +123,7 @@ var applySchemaOptions = struct {
 	WaitReplicasTimeout     time.Duration
 	SkipPreflight           bool
 	CallerID                string
+	DryRun                  bool
 }{}
 
 func commandApplySchema(cmd *cobra.Command, args []string) error {
@@ -146,15 +167,112 @@ func commandApplySchema(cmd *cobra.Command, args []string) error {
 		MigrationContext:        applySchemaOptions.MigrationContext,
 		WaitReplicasTimeout:     protoutil.DurationToProto(applySchemaOptions.WaitReplicasTimeout),
 		CallerId:                cid,
+		DryRun:                  applySchemaOptions.DryRun,
 	})
 	if err != nil {
 		return err
 	}
 
+	if applySchemaOptions.DryRun {
+		data, err := cli.MarshalJSON(resp)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+
 	fmt.Println(strings.Join(resp.UuidList, "\n"))
 	return nil
 }
 
+var deploySchemaOptions = struct {
+	SchemaDir               string
+	DryRun                  bool
+	AllowDrops              bool
+	HeuristicTableRenames   bool
+	HeuristicColumnRenames  bool
+	DDLStrategy             string
+	MigrationContext        string
+	WaitReplicasTimeout     time.Duration
+	SkipPreflight           bool
+	AllowLongUnavailability bool
+	CallerID                string
+}{}
+
+func commandDeploySchema(cmd *cobra.Command, args []string) error {
+	sql, err := readSchemaDir(deploySchemaOptions.SchemaDir)
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	var cid *vtrpc.CallerID
+	if deploySchemaOptions.CallerID != "" {
+		cid = &vtrpc.CallerID{Principal: deploySchemaOptions.CallerID}
+	}
+
+	resp, err := client.DeploySchema(commandCtx, &vtctldatapb.DeploySchemaRequest{
+		Keyspace:                cmd.Flags().Arg(0),
+		Sql:                     sql,
+		DryRun:                  deploySchemaOptions.DryRun,
+		AllowDrops:              deploySchemaOptions.AllowDrops,
+		HeuristicTableRenames:   deploySchemaOptions.HeuristicTableRenames,
+		HeuristicColumnRenames:  deploySchemaOptions.HeuristicColumnRenames,
+		DdlStrategy:             deploySchemaOptions.DDLStrategy,
+		MigrationContext:        deploySchemaOptions.MigrationContext,
+		WaitReplicasTimeout:     protoutil.DurationToProto(deploySchemaOptions.WaitReplicasTimeout),
+		SkipPreflight:           deploySchemaOptions.SkipPreflight,
+		AllowLongUnavailability: deploySchemaOptions.AllowLongUnavailability,
+		CallerId:                cid,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
+// readSchemaDir returns the statements in all the .sql files of a directory,
+// in file name order.
+func readSchemaDir(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var sql []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		parts, err := sqlparser.SplitStatementToPieces(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		sql = append(sql, parts...)
+	}
+
+	if len(sql) == 0 {
+		return nil, fmt.Errorf("no statements found in the .sql files of %s", dir)
+	}
+	return sql, nil
+}
+
 var getSchemaOptions = struct {
 	Tables          []string
 	ExcludeTables   []string
@@ -294,10 +412,26 @@ func init() {
 	ApplySchema.Flags().BoolVar(&applySchemaOptions.SkipPreflight, "skip-preflight", false, "Skip pre-apply schema checks, and directly forward schema change query to shards.")
 	ApplySchema.Flags().StringVar(&applySchemaOptions.CallerID, "caller-id", "", "Effective caller ID used for the operation and should map to an ACL name which grants this identity the necessary permissions to perform the operation (this is only necessary when strict table ACLs are used).")
 	ApplySchema.Flags().StringSliceVar(&applySchemaOptions.SQL, "sql", nil, "Semicolon-delimited, repeatable SQL commands to apply. Exactly one of --sql|--sql-file is required.")
+	ApplySchema.Flags().BoolVar(&applySchemaOptions.DryRun, "dry-run", false, "Only check the schema changes against the current schema of the keyspace and print the findings, without applying them.")
 	ApplySchema.Flags().StringVar(&applySchemaOptions.SQLFile, "sql-file", "", "Path to a file containing semicolon-delimited SQL commands to apply. Exactly one of --sql|--sql-file is required.")
 
 	Root.AddCommand(ApplySchema)
 
+	DeploySchema.Flags().StringVar(&deploySchemaOptions.SchemaDir, "schema-dir", "", "Directory of .sql files containing the CREATE TABLE and CREATE VIEW statements of the desired schema.")
+	DeploySchema.MarkFlagRequired("schema-dir")
+	DeploySchema.Flags().BoolVar(&deploySchemaOptions.DryRun, "dry-run", false, "Only print the schema changes, without applying them.")
+	DeploySchema.Flags().BoolVar(&deploySchemaOptions.AllowDrops, "allow-drops", false, "Allow the schema changes to drop tables, views, stored programs and columns that are not in --schema-dir.")
+	DeploySchema.Flags().BoolVar(&deploySchemaOptions.HeuristicTableRenames, "heuristic-table-renames", false, "Rename tables that look like they were renamed, instead of dropping and creating them.")
+	DeploySchema.Flags().BoolVar(&deploySchemaOptions.HeuristicColumnRenames, "heuristic-column-renames", false, "Rename columns that look like they were renamed, instead of dropping and adding them.")
+	DeploySchema.Flags().StringVar(&deploySchemaOptions.DDLStrategy, "ddl-strategy", string(schema.DDLStrategyDirect), "Online DDL strategy, compatible with @@ddl_strategy session variable (examples: 'gh-ost', 'pt-osc', 'gh-ost --max-load=Threads_running=100'.")
+	DeploySchema.Flags().StringVar(&deploySchemaOptions.MigrationContext, "migration-context", "", "For Online DDL, optionally supply a custom unique string used as context for the migration(s) in this command. By default a unique context is auto-generated by Vitess.")
+	DeploySchema.Flags().DurationVar(&deploySchemaOptions.WaitReplicasTimeout, "wait-replicas-timeout", wrangler.DefaultWaitReplicasTimeout, "Amount of time to wait for replicas to receive the schema change via replication.")
+	DeploySchema.Flags().BoolVar(&deploySchemaOptions.SkipPreflight, "skip-preflight", false, "Skip pre-apply schema checks, and directly forward schema change query to shards.")
+	DeploySchema.Flags().BoolVar(&deploySchemaOptions.AllowLongUnavailability, "allow-long-unavailability", false, "Allow large schema changes which incur a longer unavailability of the database.")
+	DeploySchema.Flags().StringVar(&deploySchemaOptions.CallerID, "caller-id", "", "Effective caller ID used for the operation and should map to an ACL name which grants this identity the necessary permissions to perform the operation (this is only necessary when strict table ACLs are used).")
+
+	Root.AddCommand(DeploySchema)
+
 	GetSchema.Flags().StringSliceVar(&getSchemaOptions.Tables, "tables", nil, "List of tables to display the schema for. Each is either an exact match, or a regular expression of the form `/regexp/`.")
 	GetSchema.Flags().StringSliceVar(&getSchemaOptions.ExcludeTables, "exclude-tables", nil, "List of tables to exclude from the result. Each is either an exact match, or a regular expression of the form `/regexp/`.")
 	GetSchema.Flags().BoolVar(&getSchemaOptions.IncludeViews, "include-views", false, "Includes views in the output in addition to base tables.")
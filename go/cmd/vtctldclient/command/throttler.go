@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/protoutil"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// UpdateThrottlerConfig makes a UpdateThrottlerConfig gRPC call to a vtctld.
+	UpdateThrottlerConfig = &cobra.Command{
+		Use:   "UpdateThrottlerConfig [--enable|--disable] [--threshold=<float64>] [--custom-query=<query>] [--check-as-check-self|--check-as-check-shard] [--throttle-app|--unthrottle-app|--exempt-app=<name>] [--throttle-app-ratio=<float64>] [--throttle-app-duration=<duration>] <keyspace>",
+		Short: "Updates the tablet throttler configuration and app throttling rules of a keyspace.",
+		Long: `Updates the tablet throttler configuration and app throttling rules of a keyspace.
+
+The configuration is persisted in the keyspace record and in all of the keyspace's
+SrvKeyspace records. All tablets of the keyspace watch their SrvKeyspace and apply
+the configuration as it changes. Once a keyspace has a throttler configuration in
+the topo, it takes precedence over the tablets' throttler command line flags.
+The tablets keep their --enable_lag_throttler setting until the throttler is
+enabled or disabled with --enable or --disable.
+
+Enabling the throttler this way requires heartbeats to be enabled on the tablets,
+or a custom query to be set.`,
+		Example: `UpdateThrottlerConfig --enable --threshold=2.5 commerce
+UpdateThrottlerConfig --throttle-app=online-ddl --throttle-app-ratio=0.5 --throttle-app-duration=2h commerce
+UpdateThrottlerConfig --exempt-app=critical_workflow --throttle-app-duration=24h commerce
+UpdateThrottlerConfig --unthrottle-app=online-ddl commerce`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandUpdateThrottlerConfig,
+	}
+)
+
+var updateThrottlerConfigOptions = struct {
+	Enable              bool
+	Disable             bool
+	Threshold           float64
+	CustomQuery         string
+	CheckAsCheckSelf    bool
+	CheckAsCheckShard   bool
+	ThrottleApp         string
+	UnthrottleApp       string
+	ExemptApp           string
+	ThrottleAppRatio    float64
+	ThrottleAppDuration time.Duration
+}{}
+
+func commandUpdateThrottlerConfig(cmd *cobra.Command, args []string) error {
+	keyspace := cmd.Flags().Arg(0)
+
+	appNames := 0
+	for _, appName := range []string{updateThrottlerConfigOptions.ThrottleApp, updateThrottlerConfigOptions.UnthrottleApp, updateThrottlerConfigOptions.ExemptApp} {
+		if appName != "" {
+			appNames++
+		}
+	}
+	if appNames > 1 {
+		return fmt.Errorf("--throttle-app, --unthrottle-app and --exempt-app are mutually exclusive")
+	}
+	if cmd.Flags().Changed("threshold") && updateThrottlerConfigOptions.Threshold <= 0 {
+		return fmt.Errorf("--threshold must be positive, got %v", updateThrottlerConfigOptions.Threshold)
+	}
+
+	cli.FinishedParsing(cmd)
+
+	req := &vtctldatapb.UpdateThrottlerConfigRequest{
+		Keyspace:          keyspace,
+		Enable:            updateThrottlerConfigOptions.Enable,
+		Disable:           updateThrottlerConfigOptions.Disable,
+		Threshold:         updateThrottlerConfigOptions.Threshold,
+		CustomQuery:       updateThrottlerConfigOptions.CustomQuery,
+		CustomQuerySet:    cmd.Flags().Changed("custom-query"),
+		CheckAsCheckSelf:  updateThrottlerConfigOptions.CheckAsCheckSelf,
+		CheckAsCheckShard: updateThrottlerConfigOptions.CheckAsCheckShard,
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(updateThrottlerConfigOptions.ThrottleAppDuration)
+	switch {
+	case updateThrottlerConfigOptions.ThrottleApp != "":
+		req.ThrottledApp = &topodatapb.ThrottledAppRule{
+			Name:      updateThrottlerConfigOptions.ThrottleApp,
+			Ratio:     updateThrottlerConfigOptions.ThrottleAppRatio,
+			ExpiresAt: protoutil.TimeToProto(expiresAt),
+		}
+	case updateThrottlerConfigOptions.ExemptApp != "":
+		req.ThrottledApp = &topodatapb.ThrottledAppRule{
+			Name:      updateThrottlerConfigOptions.ExemptApp,
+			Exempt:    true,
+			ExpiresAt: protoutil.TimeToProto(expiresAt),
+		}
+	case updateThrottlerConfigOptions.UnthrottleApp != "":
+		// An expired rule removes any existing rule for the app.
+		req.ThrottledApp = &topodatapb.ThrottledAppRule{
+			Name:      updateThrottlerConfigOptions.UnthrottleApp,
+			ExpiresAt: protoutil.TimeToProto(now),
+		}
+	}
+
+	resp, err := client.UpdateThrottlerConfig(commandCtx, req)
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.ThrottlerConfig)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
+func init() {
+	UpdateThrottlerConfig.Flags().BoolVar(&updateThrottlerConfigOptions.Enable, "enable", false, "Enables the throttler.")
+	UpdateThrottlerConfig.Flags().BoolVar(&updateThrottlerConfigOptions.Disable, "disable", false, "Disables the throttler. A disabled throttler responds to all checks with OK.")
+	UpdateThrottlerConfig.Flags().Float64Var(&updateThrottlerConfigOptions.Threshold, "threshold", 0, "The threshold for the default replication lag check (in seconds), or for the custom query, if set. Must be positive, and is required with a custom query.")
+	UpdateThrottlerConfig.Flags().StringVar(&updateThrottlerConfigOptions.CustomQuery, "custom-query", "", "Custom throttler check query, replacing the default replication lag query. Use an empty value to restore the default.")
+	UpdateThrottlerConfig.Flags().BoolVar(&updateThrottlerConfigOptions.CheckAsCheckSelf, "check-as-check-self", false, "Respond to /throttler/check requests by checking the tablet's own health.")
+	UpdateThrottlerConfig.Flags().BoolVar(&updateThrottlerConfigOptions.CheckAsCheckShard, "check-as-check-shard", false, "Respond to /throttler/check requests by checking the shard's health (this is the default behavior).")
+	UpdateThrottlerConfig.Flags().StringVar(&updateThrottlerConfigOptions.ThrottleApp, "throttle-app", "", "Name of an app to throttle, e.g. online-ddl or a vreplication workflow name.")
+	UpdateThrottlerConfig.Flags().StringVar(&updateThrottlerConfigOptions.UnthrottleApp, "unthrottle-app", "", "Name of an app to remove any throttling rule (including exemption) for.")
+	UpdateThrottlerConfig.Flags().StringVar(&updateThrottlerConfigOptions.ExemptApp, "exempt-app", "", "Name of an app to exempt from throttling.")
+	UpdateThrottlerConfig.Flags().Float64Var(&updateThrottlerConfigOptions.ThrottleAppRatio, "throttle-app-ratio", 1.0, "Ratio of checks to reject for the throttled app, in the range [0..1]. 1.0 means fully throttled.")
+	UpdateThrottlerConfig.Flags().DurationVar(&updateThrottlerConfigOptions.ThrottleAppDuration, "throttle-app-duration", time.Hour, "Duration after which the app throttling or exemption rule expires.")
+	Root.AddCommand(UpdateThrottlerConfig)
+}
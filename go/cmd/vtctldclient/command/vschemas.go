@@ -40,12 +40,31 @@ var (
 	}
 	// ApplyVSchema makes an ApplyVSchema gRPC call to a vtctld.
 	ApplyVSchema = &cobra.Command{
-		Use:                   "ApplyVSchema {--vschema=<vschema> || --vschema-file=<vschema file> || --sql=<sql> || --sql-file=<sql file>} [--cells=c1,c2,...] [--skip-rebuild] [--dry-run] <keyspace>",
-		Short:                 "Applies the VTGate routing schema to the provided keyspace. Shows the result after application.",
+		Use:   "ApplyVSchema {--vschema=<vschema> || --vschema-file=<vschema file> || --sql=<sql> || --sql-file=<sql file>} [--cells=c1,c2,...] [--skip-rebuild] [--dry-run] [--validate-schema] <keyspace>",
+		Short: "Applies the VTGate routing schema to the provided keyspace. Shows the result after application.",
+		Long: `Applies the VTGate routing schema to the provided keyspace. Shows the result after application.
+
+The VSchema is validated like DiffVSchema does, and it is only saved when it has no validation errors.
+The changes to the current VSchema, the validation errors and the validations that could not be run,
+for example because a keyspace has no primary, are shown. Use --dry-run to only preview them.
+Use --validate-schema to also validate the VSchema against the schema of the tables.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandApplyVSchema,
 	}
+	// DiffVSchema makes a DiffVSchema gRPC call to a vtctld.
+	DiffVSchema = &cobra.Command{
+		Use:   "DiffVSchema {--vschema=<vschema> || --vschema-file=<vschema file> || --sql=<sql> || --sql-file=<sql file>} [--validate-schema] <keyspace>",
+		Short: "Shows the changes between the VSchema of a keyspace and the provided VSchema, and validates the provided VSchema, without applying it.",
+		Long: `Shows the changes between the VSchema of a keyspace and the provided VSchema, and validates the provided VSchema, without applying it.
+
+With --validate-schema, the provided VSchema is also validated against the tables of the keyspace, and of the keyspaces
+of its lookup vindexes, as reported by the primary of their first shard. The checks against a schema that cannot be read,
+for example because a keyspace has no primary, are skipped and reported as warnings.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandDiffVSchema,
+	}
 )
 
 var applyVSchemaOptions = struct {
@@ -56,55 +75,67 @@ var applyVSchemaOptions = struct {
 	DryRun      bool
 	SkipRebuild bool
 	Cells       []string
+
+	ValidateSchema bool
 }{}
 
-func commandApplyVSchema(cmd *cobra.Command, args []string) error {
-	sqlMode := (applyVSchemaOptions.SQL != "") != (applyVSchemaOptions.SQLFile != "")
-	jsonMode := (applyVSchemaOptions.VSchema != "") != (applyVSchemaOptions.VSchemaFile != "")
+// readVSchemaInput returns the VSchema or the VSchema DDL statement given to
+// a command by its vschema, vschema-file, sql and sql-file flags. Exactly one
+// of them must be set.
+func readVSchemaInput(command string, vschema string, vschemaFile string, sql string, sqlFile string) (*vschemapb.Keyspace, string, error) {
+	sqlMode := (sql != "") != (sqlFile != "")
+	jsonMode := (vschema != "") != (vschemaFile != "")
 
 	if sqlMode && jsonMode {
-		return fmt.Errorf("only one of the sql, sql-file, vschema, or vschema-file flags may be specified when calling the ApplyVSchema command")
+		return nil, "", fmt.Errorf("only one of the sql, sql-file, vschema, or vschema-file flags may be specified when calling the %s command", command)
 	}
 
 	if !sqlMode && !jsonMode {
-		return fmt.Errorf("one of the sql, sql-file, vschema, or vschema-file flags must be specified when calling the ApplyVSchema command")
+		return nil, "", fmt.Errorf("one of the sql, sql-file, vschema, or vschema-file flags must be specified when calling the %s command", command)
 	}
 
-	req := &vtctldatapb.ApplyVSchemaRequest{
-		Keyspace:    cmd.Flags().Arg(0),
-		SkipRebuild: applyVSchemaOptions.SkipRebuild,
-		Cells:       applyVSchemaOptions.Cells,
-		DryRun:      applyVSchemaOptions.DryRun,
-	}
-
-	var err error
 	if sqlMode {
-		if applyVSchemaOptions.SQLFile != "" {
-			sqlBytes, err := os.ReadFile(applyVSchemaOptions.SQLFile)
-			if err != nil {
-				return err
-			}
-			req.Sql = string(sqlBytes)
-		} else {
-			req.Sql = applyVSchemaOptions.SQL
-		}
-	} else { // jsonMode
-		var schema []byte
-		if applyVSchemaOptions.VSchemaFile != "" {
-			schema, err = os.ReadFile(applyVSchemaOptions.VSchemaFile)
+		if sqlFile != "" {
+			sqlBytes, err := os.ReadFile(sqlFile)
 			if err != nil {
-				return err
+				return nil, "", err
 			}
-		} else {
-			schema = []byte(applyVSchemaOptions.VSchema)
+			return nil, string(sqlBytes), nil
 		}
+		return nil, sql, nil
+	}
 
-		var vs vschemapb.Keyspace
-		err = json2.Unmarshal(schema, &vs)
+	// jsonMode
+	schema := []byte(vschema)
+	if vschemaFile != "" {
+		var err error
+		schema, err = os.ReadFile(vschemaFile)
 		if err != nil {
-			return err
+			return nil, "", err
 		}
-		req.VSchema = &vs
+	}
+
+	var vs vschemapb.Keyspace
+	if err := json2.Unmarshal(schema, &vs); err != nil {
+		return nil, "", err
+	}
+	return &vs, "", nil
+}
+
+func commandApplyVSchema(cmd *cobra.Command, args []string) error {
+	vs, sql, err := readVSchemaInput("ApplyVSchema", applyVSchemaOptions.VSchema, applyVSchemaOptions.VSchemaFile, applyVSchemaOptions.SQL, applyVSchemaOptions.SQLFile)
+	if err != nil {
+		return err
+	}
+
+	req := &vtctldatapb.ApplyVSchemaRequest{
+		Keyspace:       cmd.Flags().Arg(0),
+		SkipRebuild:    applyVSchemaOptions.SkipRebuild,
+		Cells:          applyVSchemaOptions.Cells,
+		DryRun:         applyVSchemaOptions.DryRun,
+		VSchema:        vs,
+		Sql:            sql,
+		ValidateSchema: applyVSchemaOptions.ValidateSchema,
 	}
 
 	cli.FinishedParsing(cmd)
@@ -118,6 +149,54 @@ func commandApplyVSchema(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	fmt.Printf("New VSchema object:\n%s\nIf this is not what you expected, check the input data (as JSON parsing will skip unexpected fields).\n", data)
+
+	if len(res.Changes) > 0 || len(res.ValidationErrors) > 0 || len(res.ValidationWarnings) > 0 {
+		data, err = cli.MarshalJSON(&vtctldatapb.DiffVSchemaResponse{
+			Changes:            res.Changes,
+			ValidationErrors:   res.ValidationErrors,
+			ValidationWarnings: res.ValidationWarnings,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Changes and validation results:\n%s\n", data)
+	}
+	return nil
+}
+
+var diffVSchemaOptions = struct {
+	VSchema        string
+	VSchemaFile    string
+	SQL            string
+	SQLFile        string
+	ValidateSchema bool
+}{}
+
+func commandDiffVSchema(cmd *cobra.Command, args []string) error {
+	vs, sql, err := readVSchemaInput("DiffVSchema", diffVSchemaOptions.VSchema, diffVSchemaOptions.VSchemaFile, diffVSchemaOptions.SQL, diffVSchemaOptions.SQLFile)
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.DiffVSchema(commandCtx, &vtctldatapb.DiffVSchemaRequest{
+		Keyspace:       cmd.Flags().Arg(0),
+		VSchema:        vs,
+		Sql:            sql,
+		ValidateSchema: diffVSchemaOptions.ValidateSchema,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
 	return nil
 }
 
@@ -151,7 +230,15 @@ func init() {
 	ApplyVSchema.Flags().BoolVar(&applyVSchemaOptions.DryRun, "dry-run", false, "If set, do not save the altered vschema, simply echo to console.")
 	ApplyVSchema.Flags().BoolVar(&applyVSchemaOptions.SkipRebuild, "skip-rebuild", false, "Skip rebuilding the SrvSchema objects.")
 	ApplyVSchema.Flags().StringSliceVar(&applyVSchemaOptions.Cells, "cells", nil, "Limits the rebuild to the specified cells, after application. Ignored if --skip-rebuild is set.")
+	ApplyVSchema.Flags().BoolVar(&applyVSchemaOptions.ValidateSchema, "validate-schema", false, "Also validate the VSchema against the schema of the tables, as reported by the primary tablets.")
 	Root.AddCommand(ApplyVSchema)
 
+	DiffVSchema.Flags().StringVar(&diffVSchemaOptions.VSchema, "vschema", "", "VSchema to compare, in JSON form.")
+	DiffVSchema.Flags().StringVar(&diffVSchemaOptions.VSchemaFile, "vschema-file", "", "Path to a file containing the vschema to compare, in JSON form.")
+	DiffVSchema.Flags().StringVar(&diffVSchemaOptions.SQL, "sql", "", "A VSchema DDL SQL statement, e.g. `alter table t add vindex hash(id)`, applied to the current VSchema.")
+	DiffVSchema.Flags().StringVar(&diffVSchemaOptions.SQLFile, "sql-file", "", "Path to a file containing a VSchema DDL SQL.")
+	DiffVSchema.Flags().BoolVar(&diffVSchemaOptions.ValidateSchema, "validate-schema", false, "Also validate the VSchema against the schema of the tables, as reported by the primary tablets.")
+	Root.AddCommand(DiffVSchema)
+
 	Root.AddCommand(GetVSchema)
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -29,7 +30,9 @@ import (
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vtexplain"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 
@@ -50,10 +53,15 @@ var (
 	executionMode      = flag.String("execution-mode", "multi", "The execution mode to simulate -- must be set to multi, legacy-autocommit, or twopc")
 	replicationMode    = flag.String("replication-mode", "ROW", "The replication mode to simulate -- must be set to either ROW or STATEMENT")
 	normalize          = flag.Bool("normalize", false, "Whether to enable vtgate normalization")
-	outputMode         = flag.String("output-mode", "text", "Output in human-friendly text or json")
+	outputMode         = flag.String("output-mode", "text", "Output in human-friendly text, json, or cost (json with only the cost of each statement)")
 	dbName             = flag.String("dbname", "", "Optional database target to override normal routing")
 	plannerVersionStr  = flag.String("planner-version", "", "Sets the query planner version to use when generating the explain output. Valid values are V3 and Gen4")
 	badPlannerVersion  = flag.String("planner_version", "", "Deprecated flag. Use planner-version instead")
+	snapshotDir        = flag.String("snapshot-dir", "", "Directory of a cluster snapshot to explain against, instead of the schema, vschema and ks-shard-map flags")
+	fetchSnapshot      = flag.Bool("fetch-snapshot", false, "Fetch a snapshot of the cluster from the topo server into --snapshot-dir before explaining. Without --sql or --sql-file, only fetch it.")
+	snapshotCell       = flag.String("snapshot-cell", "", "The cell whose SrvVSchema and SrvKeyspaces --fetch-snapshot reads")
+	tableStatsFlag     = flag.String("table-stats", "", "JSON map of table name -> {\"rows\": N}, used to estimate the rows read by each statement. Table names may be qualified with their keyspace.")
+	tableStatsFileFlag = flag.String("table-stats-file", "", "Identifies the file that contains the table stats")
 
 	// vtexplainFlags lists all the flags that should show in usage
 	vtexplainFlags = []string{
@@ -72,6 +80,15 @@ var (
 		"ks-shard-map-file",
 		"dbname",
 		"queryserver-config-passthrough-dmls",
+		"snapshot-dir",
+		"fetch-snapshot",
+		"snapshot-cell",
+		"table-stats",
+		"table-stats-file",
+		"topo_implementation",
+		"topo_global_server_address",
+		"topo_global_root",
+		"tablet_manager_protocol",
 	}
 )
 
@@ -139,25 +156,36 @@ func parseAndRun() error {
 		return fmt.Errorf("invalid value specified for planner-version of '%s' -- valid values are V3 and Gen4", *plannerVersionStr)
 	}
 
-	sql, err := getFileParam(*sqlFlag, *sqlFileFlag, "sql", true)
-	if err != nil {
-		return err
+	if *fetchSnapshot {
+		if err := fetchAndWriteSnapshot(); err != nil {
+			return err
+		}
+		if *sqlFlag == "" && *sqlFileFlag == "" {
+			return nil
+		}
 	}
 
-	schema, err := getFileParam(*schemaFlag, *schemaFileFlag, "schema", true)
+	sql, err := getFileParam(*sqlFlag, *sqlFileFlag, "sql", true)
 	if err != nil {
 		return err
 	}
 
-	vschema, err := getFileParam(*vschemaFlag, *vschemaFileFlag, "vschema", true)
+	schema, vschema, ksShardMap, err := getTopology()
 	if err != nil {
 		return err
 	}
 
-	ksShardMap, err := getFileParam(*ksShardMapFlag, *ksShardMapFileFlag, "ks-shard-map", false)
+	tableStatsStr, err := getFileParam(*tableStatsFlag, *tableStatsFileFlag, "table-stats", false)
 	if err != nil {
 		return err
 	}
+	var tableStats map[string]*vtexplain.TableStats
+	if tableStatsStr != "" {
+		tableStats, err = vtexplain.ParseTableStats(tableStatsStr)
+		if err != nil {
+			return err
+		}
+	}
 
 	opts := &vtexplain.Options{
 		ExecutionMode:   *executionMode,
@@ -166,6 +194,8 @@ func parseAndRun() error {
 		NumShards:       *numShards,
 		Normalize:       *normalize,
 		Target:          *dbName,
+		Cost:            *outputMode == "cost",
+		TableStats:      tableStats,
 	}
 
 	log.V(100).Infof("sql %s\n", sql)
@@ -183,11 +213,75 @@ func parseAndRun() error {
 		return err
 	}
 
-	if *outputMode == "text" {
+	switch *outputMode {
+	case "text":
 		fmt.Print(vte.ExplainsAsText(plans))
-	} else {
+	case "cost":
+		fmt.Print(vtexplain.ExplainsAsCostJSON(plans))
+	default:
 		fmt.Print(vtexplain.ExplainsAsJSON(plans))
 	}
 
 	return nil
 }
+
+// getTopology returns the schema, vschema and keyspace shard map to explain
+// against, from either a snapshot or the corresponding flags.
+func getTopology() (schema, vschema, ksShardMap string, err error) {
+	if *snapshotDir != "" {
+		for _, f := range []string{*schemaFlag, *schemaFileFlag, *vschemaFlag, *vschemaFileFlag, *ksShardMapFlag, *ksShardMapFileFlag} {
+			if f != "" {
+				return "", "", "", fmt.Errorf("snapshot-dir cannot be combined with the schema, vschema or ks-shard-map flags")
+			}
+		}
+
+		snapshot, err := vtexplain.ReadSnapshot(*snapshotDir)
+		if err != nil {
+			return "", "", "", err
+		}
+		return snapshot.Schema, snapshot.VSchema, snapshot.KsShardMap, nil
+	}
+
+	schema, err = getFileParam(*schemaFlag, *schemaFileFlag, "schema", true)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	vschema, err = getFileParam(*vschemaFlag, *vschemaFileFlag, "vschema", true)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	ksShardMap, err = getFileParam(*ksShardMapFlag, *ksShardMapFileFlag, "ks-shard-map", false)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return schema, vschema, ksShardMap, nil
+}
+
+// fetchAndWriteSnapshot fetches a snapshot of the cluster from the topo server
+// and writes it to the snapshot directory.
+func fetchAndWriteSnapshot() error {
+	if *snapshotDir == "" {
+		return fmt.Errorf("fetch-snapshot requires snapshot-dir")
+	}
+	if *snapshotCell == "" {
+		return fmt.Errorf("fetch-snapshot requires snapshot-cell")
+	}
+
+	ts := topo.Open()
+	defer ts.Close()
+
+	tmc := tmclient.NewTabletManagerClient()
+	defer tmc.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *topo.RemoteOperationTimeout)
+	defer cancel()
+
+	snapshot, err := vtexplain.FetchSnapshot(ctx, ts, tmc, *snapshotCell)
+	if err != nil {
+		return err
+	}
+	return snapshot.Write(*snapshotDir)
+}
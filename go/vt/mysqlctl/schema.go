@@ -148,6 +148,13 @@ func (mysqld *Mysqld) GetSchema(ctx context.Context, dbName string, request *tab
 
 	sd.TableDefinitions = tds
 
+	if request.IncludeStoredPrograms {
+		sd.StoredProgramDefinitions, err = mysqld.collectStoredPrograms(ctx, dbName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	tmutils.GenerateSchemaVersion(sd)
 	return sd, nil
 }
@@ -252,6 +259,64 @@ func (mysqld *Mysqld) normalizedSchema(ctx context.Context, dbName, tableName, t
 	return norm, nil
 }
 
+// storedProgramQueries lists, per stored program type, the query that lists the stored programs of a
+// database, and the column of the SHOW CREATE output that holds the CREATE statement.
+var storedProgramQueries = []struct {
+	typ          string
+	listQuery    string
+	createColumn int
+}{
+	{
+		typ:          "FUNCTION",
+		listQuery:    "SELECT routine_name FROM information_schema.routines WHERE routine_schema = %s AND routine_type = 'FUNCTION' ORDER BY routine_name",
+		createColumn: 2,
+	},
+	{
+		typ:          "PROCEDURE",
+		listQuery:    "SELECT routine_name FROM information_schema.routines WHERE routine_schema = %s AND routine_type = 'PROCEDURE' ORDER BY routine_name",
+		createColumn: 2,
+	},
+	{
+		// triggers are listed in their order of execution
+		typ:          "TRIGGER",
+		listQuery:    "SELECT trigger_name FROM information_schema.triggers WHERE trigger_schema = %s ORDER BY event_object_table, action_timing, event_manipulation, action_order",
+		createColumn: 2,
+	},
+	{
+		typ:          "EVENT",
+		listQuery:    "SELECT event_name FROM information_schema.events WHERE event_schema = %s ORDER BY event_name",
+		createColumn: 3,
+	},
+}
+
+// collectStoredPrograms returns the definitions of the functions, procedures, triggers and events of the database.
+func (mysqld *Mysqld) collectStoredPrograms(ctx context.Context, dbName string) ([]*tabletmanagerdatapb.StoredProgramDefinition, error) {
+	backtickDBName := sqlescape.EscapeID(dbName)
+	var spds []*tabletmanagerdatapb.StoredProgramDefinition
+	for _, spq := range storedProgramQueries {
+		qr, err := mysqld.FetchSuperQuery(ctx, fmt.Sprintf(spq.listQuery, encodeTableName(dbName)))
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range qr.Rows {
+			name := row[0].ToString()
+			createQr, err := mysqld.FetchSuperQuery(ctx, fmt.Sprintf("SHOW CREATE %s %s.%s", spq.typ, backtickDBName, sqlescape.EscapeID(name)))
+			if err != nil {
+				return nil, err
+			}
+			if len(createQr.Rows) == 0 || len(createQr.Rows[0]) <= spq.createColumn {
+				return nil, fmt.Errorf("empty create %s statement for %v", strings.ToLower(spq.typ), name)
+			}
+			spds = append(spds, &tabletmanagerdatapb.StoredProgramDefinition{
+				Name:   name,
+				Type:   spq.typ,
+				Schema: createQr.Rows[0][spq.createColumn].ToString(),
+			})
+		}
+	}
+	return spds, nil
+}
+
 // ResolveTables returns a list of actual tables+views matching a list
 // of regexps
 func ResolveTables(ctx context.Context, mysqld MysqlDaemon, dbName string, tables []string) ([]string, error) {
@@ -202,7 +202,7 @@ func FilterTables(sd *tabletmanagerdatapb.SchemaDefinition, tables, excludeTable
 }
 
 // GenerateSchemaVersion return a unique schema version string based on
-// its TableDefinitions.
+// its TableDefinitions and StoredProgramDefinitions.
 func GenerateSchemaVersion(sd *tabletmanagerdatapb.SchemaDefinition) {
 	hasher := md5.New()
 	for _, td := range sd.TableDefinitions {
@@ -210,6 +210,11 @@ func GenerateSchemaVersion(sd *tabletmanagerdatapb.SchemaDefinition) {
 			panic(err) // extremely unlikely
 		}
 	}
+	for _, spd := range sd.StoredProgramDefinitions {
+		if _, err := hasher.Write([]byte(spd.Schema)); err != nil {
+			panic(err) // extremely unlikely
+		}
+	}
 	sd.Version = hex.EncodeToString(hasher.Sum(nil))
 }
 
@@ -330,6 +335,41 @@ func DiffSchema(leftName string, left *tabletmanagerdatapb.SchemaDefinition, rig
 		}
 		rightIndex++
 	}
+
+	diffStoredPrograms(leftName, left.StoredProgramDefinitions, rightName, right.StoredProgramDefinitions, er)
+}
+
+// diffStoredPrograms reports the stored programs that exist on only one side,
+// or whose definitions differ.
+func diffStoredPrograms(leftName string, left []*tabletmanagerdatapb.StoredProgramDefinition, rightName string, right []*tabletmanagerdatapb.StoredProgramDefinition, er concurrency.ErrorRecorder) {
+	key := func(spd *tabletmanagerdatapb.StoredProgramDefinition) string {
+		return spd.Type + " " + spd.Name
+	}
+
+	rightByKey := make(map[string]*tabletmanagerdatapb.StoredProgramDefinition, len(right))
+	for _, spd := range right {
+		rightByKey[key(spd)] = spd
+	}
+
+	for _, l := range left {
+		r, ok := rightByKey[key(l)]
+		if !ok {
+			er.RecordError(fmt.Errorf("%v has an extra %v named %v", leftName, strings.ToLower(l.Type), l.Name))
+			continue
+		}
+		delete(rightByKey, key(l))
+
+		if l.Schema != r.Schema {
+			er.RecordError(fmt.Errorf("schemas differ on %v %v:\n%s: %v\n differs from:\n%s: %v", strings.ToLower(l.Type), l.Name, leftName, l.Schema, rightName, r.Schema))
+		}
+	}
+
+	// report the programs only found on the right in their original order
+	for _, r := range right {
+		if _, ok := rightByKey[key(r)]; ok {
+			er.RecordError(fmt.Errorf("%v has an extra %v named %v", rightName, strings.ToLower(r.Type), r.Name))
+		}
+	}
 }
 
 // DiffSchemaToArray diffs two schemas and return the schema diffs if there is any.
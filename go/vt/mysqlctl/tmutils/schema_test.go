@@ -256,6 +256,55 @@ func TestSchemaDiff(t *testing.T) {
 	testDiff(t, sd1, sd2, "sd1", "sd2", []string{"schemas differ on table table2:\nsd1: schema2\n differs from:\nsd2: schema3"})
 }
 
+func TestSchemaDiffStoredPrograms(t *testing.T) {
+	sd1 := &tabletmanagerdatapb.SchemaDefinition{
+		StoredProgramDefinitions: []*tabletmanagerdatapb.StoredProgramDefinition{
+			{Name: "f1", Type: "FUNCTION", Schema: "create function f1"},
+			{Name: "p1", Type: "PROCEDURE", Schema: "create procedure p1"},
+		},
+	}
+	sd2 := &tabletmanagerdatapb.SchemaDefinition{
+		StoredProgramDefinitions: []*tabletmanagerdatapb.StoredProgramDefinition{
+			{Name: "f1", Type: "FUNCTION", Schema: "create function f1 v2"},
+			{Name: "p1", Type: "TRIGGER", Schema: "create trigger p1"},
+		},
+	}
+
+	testDiff(t, sd1, sd1, "sd1", "sd1", []string{})
+
+	testDiff(t, sd1, sd2, "sd1", "sd2", []string{
+		"schemas differ on function f1:\nsd1: create function f1\n differs from:\nsd2: create function f1 v2",
+		"sd1 has an extra procedure named p1",
+		"sd2 has an extra trigger named p1",
+	})
+
+	testDiff(t, &tabletmanagerdatapb.SchemaDefinition{}, sd1, "sd0", "sd1", []string{
+		"sd1 has an extra function named f1",
+		"sd1 has an extra procedure named p1",
+	})
+}
+
+func TestGenerateSchemaVersionStoredPrograms(t *testing.T) {
+	sd := &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{Name: "t1", Schema: "create table t1"}},
+	}
+	GenerateSchemaVersion(sd)
+	tablesOnly := sd.Version
+
+	sd.StoredProgramDefinitions = []*tabletmanagerdatapb.StoredProgramDefinition{{Name: "p1", Type: "PROCEDURE", Schema: "create procedure p1"}}
+	GenerateSchemaVersion(sd)
+	if sd.Version == tablesOnly {
+		t.Errorf("GenerateSchemaVersion() ignored a new stored program, got version %v", sd.Version)
+	}
+
+	sd.StoredProgramDefinitions[0].Schema = "create procedure p1 v2"
+	withProcedure := sd.Version
+	GenerateSchemaVersion(sd)
+	if sd.Version == withProcedure {
+		t.Errorf("GenerateSchemaVersion() ignored a changed stored program, got version %v", sd.Version)
+	}
+}
+
 func TestTableFilter(t *testing.T) {
 	includedTable := "t1"
 	includedTable2 := "t2"
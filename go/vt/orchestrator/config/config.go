@@ -40,6 +40,12 @@ const (
 
 var configurationLoaded = make(chan bool)
 
+// Supported values of ErrantGTIDRemediation
+const (
+	ErrantGTIDRemediationInjectEmpty = "inject-empty"
+	ErrantGTIDRemediationRestore     = "restore"
+)
+
 const (
 	HealthPollSeconds                     = 1
 	RaftHealthPollSeconds                 = 10
@@ -55,6 +61,7 @@ const (
 	DebugMetricsIntervalSeconds           = 10
 	StaleInstanceCoordinatesExpireSeconds = 60
 	SelectTrueQuery                       = "select 1"
+	DefaultRecoveryHookTimeoutSeconds     = 10
 )
 
 // Configuration makes for orchestrator configuration input, which can be provided by user via JSON formatted file.
@@ -206,6 +213,11 @@ type Configuration struct {
 	PostPrimaryFailoverProcesses                []string          // Processes to execute after doing a primary failover (order of execution undefined). Uses same placeholders as PostFailoverProcesses
 	PostIntermediatePrimaryFailoverProcesses    []string          // Processes to execute after doing a primary failover (order of execution undefined). Uses same placeholders as PostFailoverProcesses
 	PostTakePrimaryProcesses                    []string          // Processes to execute after a successful Take-Primary event has taken place
+	PreRecoveryHooks                            []string          // Executables (with optional arguments) or http(s) webhook URLs to run in order before a recovery changes anything, with the recovery context as JSON on stdin or in the POST body. A failing hook vetoes the recovery, which stays blocked like a failed recovery
+	PostRecoveryHooks                           []string          // Executables (with optional arguments) or http(s) webhook URLs to run in order after a recovery, successful or not, with the recovery context as JSON on stdin or in the POST body
+	RecoveryHookTimeoutSeconds                  int               // Timeout of each recovery hook, 10 seconds when 0. Pre-recovery hooks run while holding the shard lock, so they also count against LockShardTimeoutSeconds
+	ErrantGTIDRemediation                       string            // How to remediate replicas with errant GTIDs: "" (default) only reports them, "inject-empty" injects empty transactions on the shard primary, "restore" marks the replica DRAINED and restores it from backup
+	ErrantGTIDInjectEmptyMaxTransactions        int               // With ErrantGTIDRemediation "inject-empty", replicas with more errant transactions than this are considered not benign and are only reported
 	CoPrimaryRecoveryMustPromoteOtherCoPrimary  bool              // When 'false', anything can get promoted (and candidates are prefered over others). When 'true', orchestrator will promote the other co-primary or else fail
 	DetachLostReplicasAfterPrimaryFailover      bool              // Should replicas that are not to be lost in primary recovery (i.e. were more up-to-date than promoted replica) be forcibly detached
 	ApplyMySQLPromotionAfterPrimaryFailover     bool              // Should orchestrator take upon itself to apply MySQL primary promotion: set read_only=0, detach replication, etc.
@@ -359,6 +371,11 @@ func newConfiguration() *Configuration {
 		PostFailoverProcesses:                       []string{},
 		PostUnsuccessfulFailoverProcesses:           []string{},
 		PostTakePrimaryProcesses:                    []string{},
+		PreRecoveryHooks:                            []string{},
+		PostRecoveryHooks:                           []string{},
+		RecoveryHookTimeoutSeconds:                  DefaultRecoveryHookTimeoutSeconds,
+		ErrantGTIDRemediation:                       "",
+		ErrantGTIDInjectEmptyMaxTransactions:        10,
 		CoPrimaryRecoveryMustPromoteOtherCoPrimary:  true,
 		DetachLostReplicasAfterPrimaryFailover:      true,
 		ApplyMySQLPromotionAfterPrimaryFailover:     true,
@@ -445,6 +462,11 @@ func (config *Configuration) postReadAdjustments() error {
 		return fmt.Errorf("nonzero FailPrimaryPromotionOnLagMinutes requires ReplicationLagQuery to be set")
 	}
 
+	switch config.ErrantGTIDRemediation {
+	case "", ErrantGTIDRemediationInjectEmpty, ErrantGTIDRemediationRestore:
+	default:
+		return fmt.Errorf("ErrantGTIDRemediation must be one of \"\", %q or %q, got %q", ErrantGTIDRemediationInjectEmpty, ErrantGTIDRemediationRestore, config.ErrantGTIDRemediation)
+	}
 	if config.URLPrefix != "" {
 		// Ensure the prefix starts with "/" and has no trailing one.
 		config.URLPrefix = strings.TrimLeft(config.URLPrefix, "/")
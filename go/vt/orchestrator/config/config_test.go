@@ -111,3 +111,18 @@ func TestHttpAdvertise(t *testing.T) {
 		test.S(t).ExpectNotNil(err)
 	}
 }
+
+func TestErrantGTIDRemediation(t *testing.T) {
+	for _, remediation := range []string{"", ErrantGTIDRemediationInjectEmpty, ErrantGTIDRemediationRestore} {
+		c := newConfiguration()
+		c.ErrantGTIDRemediation = remediation
+		err := c.postReadAdjustments()
+		test.S(t).ExpectNil(err)
+	}
+	{
+		c := newConfiguration()
+		c.ErrantGTIDRemediation = "reset-primary"
+		err := c.postReadAdjustments()
+		test.S(t).ExpectNotNil(err)
+	}
+}
@@ -1706,6 +1706,18 @@ func (httpAPI *API) Problems(params martini.Params, r render.Render, req *http.R
 	r.JSON(http.StatusOK, instances)
 }
 
+// ErrantGTIDReplicas lists the replicas with errant GTIDs, in all keyspaces or in the given one
+func (httpAPI *API) ErrantGTIDReplicas(params martini.Params, r render.Render, req *http.Request) {
+	replicas, err := inst.ReadErrantGTIDReplicas(params["keyspace"])
+
+	if err != nil {
+		Respond(r, &APIResponse{Code: ERROR, Message: fmt.Sprintf("%+v", err)})
+		return
+	}
+
+	r.JSON(http.StatusOK, replicas)
+}
+
 // Audit provides list of audit entries by given page number
 func (httpAPI *API) Audit(params martini.Params, r render.Render, req *http.Request) {
 	page, err := strconv.Atoi(params["page"])
@@ -2806,6 +2818,8 @@ func (httpAPI *API) RegisterRequests(m *martini.ClassicMartini) {
 	httpAPI.registerAPIRequest(m, "locate-gtid-errant/:host/:port", httpAPI.LocateErrantGTID)
 	httpAPI.registerAPIRequest(m, "gtid-errant-reset-primary/:host/:port", httpAPI.ErrantGTIDResetPrimary)
 	httpAPI.registerAPIRequest(m, "gtid-errant-inject-empty/:host/:port", httpAPI.ErrantGTIDInjectEmpty)
+	httpAPI.registerAPIRequest(m, "errant-gtid-replicas", httpAPI.ErrantGTIDReplicas)
+	httpAPI.registerAPIRequest(m, "errant-gtid-replicas/:keyspace", httpAPI.ErrantGTIDReplicas)
 	httpAPI.registerAPIRequest(m, "skip-query/:host/:port", httpAPI.SkipQuery)
 	httpAPI.registerAPIRequest(m, "start-replica/:host/:port", httpAPI.StartReplication)
 	httpAPI.registerAPIRequest(m, "restart-replica/:host/:port", httpAPI.RestartReplication)
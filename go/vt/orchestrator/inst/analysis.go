@@ -46,6 +46,7 @@ const (
 	ReplicationStopped                     AnalysisCode = "ReplicationStopped"
 	ReplicaSemiSyncMustBeSet               AnalysisCode = "ReplicaSemiSyncMustBeSet"
 	ReplicaSemiSyncMustNotBeSet            AnalysisCode = "ReplicaSemiSyncMustNotBeSet"
+	ReplicaHasErrantGTID                   AnalysisCode = "ReplicaHasErrantGTID"
 	UnreachablePrimaryWithLaggingReplicas  AnalysisCode = "UnreachablePrimaryWithLaggingReplicas"
 	UnreachablePrimary                     AnalysisCode = "UnreachablePrimary"
 	PrimarySingleReplicaNotReplicating     AnalysisCode = "PrimarySingleReplicaNotReplicating"
@@ -170,6 +171,7 @@ type ReplicationAnalysis struct {
 	StartActivePeriod                         string
 	SkippableDueToDowntime                    bool
 	GTIDMode                                  string
+	GTIDErrant                                string
 	MinReplicaGTIDMode                        string
 	MaxReplicaGTIDMode                        string
 	MaxReplicaGTIDErrant                      string
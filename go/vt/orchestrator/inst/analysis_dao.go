@@ -136,6 +136,7 @@ func GetReplicationAnalysis(clusterName string, hints *ReplicationAnalysisHints)
 			) = primary_instance.cluster_name
 		) AS is_cluster_primary,
 		MIN(primary_instance.gtid_mode) AS gtid_mode,
+		MIN(primary_instance.gtid_errant) AS gtid_errant,
 		COUNT(replica_instance.server_id) AS count_replicas,
 		IFNULL(
 			SUM(
@@ -419,6 +420,7 @@ func GetReplicationAnalysis(clusterName string, hints *ReplicationAnalysisHints)
 		a.ClusterDetails.ClusterAlias = m.GetString("cluster_alias")
 		a.ClusterDetails.ClusterDomain = m.GetString("cluster_domain")
 		a.GTIDMode = m.GetString("gtid_mode")
+		a.GTIDErrant = m.GetString("gtid_errant")
 		a.LastCheckValid = m.GetBool("is_last_check_valid")
 		a.LastCheckPartialSuccess = m.GetBool("last_check_partial_success")
 		a.CountReplicas = m.GetUint("count_replicas")
@@ -568,6 +570,10 @@ func GetReplicationAnalysis(clusterName string, hints *ReplicationAnalysisHints)
 			a.Analysis = ReplicaSemiSyncMustNotBeSet
 			a.Description = "Replica semi-sync must not be set"
 			//
+		} else if topo.IsReplicaType(a.TabletType) && !a.IsPrimary && a.GTIDErrant != "" {
+			a.Analysis = ReplicaHasErrantGTID
+			a.Description = "Replica has errant GTIDs, which are not found on the primary"
+			//
 			// TODO(sougou): Events below here are either ignored or not possible.
 		} else if a.IsPrimary && !a.LastCheckValid && a.CountLaggingReplicas == a.CountReplicas && a.CountDelayedReplicas < a.CountReplicas && a.CountValidReplicatingReplicas > 0 {
 			a.Analysis = UnreachablePrimaryWithLaggingReplicas
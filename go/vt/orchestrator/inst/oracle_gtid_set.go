@@ -115,6 +115,14 @@ func (oracleGTIDSet *OracleGtidSet) Explode() (result [](*OracleGtidSetEntry)) {
 	return result
 }
 
+// Count returns the number of transactions in this set
+func (oracleGTIDSet *OracleGtidSet) Count() (count int64) {
+	for _, entry := range oracleGTIDSet.GtidEntries {
+		count += entry.Count()
+	}
+	return count
+}
+
 func (oracleGTIDSet *OracleGtidSet) String() string {
 	tokens := []string{}
 	for _, entry := range oracleGTIDSet.GtidEntries {
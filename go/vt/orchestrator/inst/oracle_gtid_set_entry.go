@@ -57,6 +57,23 @@ func (oracleGTIDSetEntry *OracleGtidSetEntry) String() string {
 	return fmt.Sprintf("%s:%s", oracleGTIDSetEntry.UUID, oracleGTIDSetEntry.Ranges)
 }
 
+// Count returns the number of transactions in this entry, without exploding it
+func (oracleGTIDSetEntry *OracleGtidSetEntry) Count() (count int64) {
+	intervals := strings.Split(oracleGTIDSetEntry.Ranges, ":")
+	for _, interval := range intervals {
+		if submatch := multiValueInterval.FindStringSubmatch(interval); submatch != nil {
+			intervalStart, _ := strconv.ParseInt(submatch[1], 10, 64)
+			intervalEnd, _ := strconv.ParseInt(submatch[2], 10, 64)
+			if intervalEnd >= intervalStart {
+				count += intervalEnd - intervalStart + 1
+			}
+		} else if singleValueInterval.MatchString(interval) {
+			count++
+		}
+	}
+	return count
+}
+
 // String returns a user-friendly string representation of this entry
 func (oracleGTIDSetEntry *OracleGtidSetEntry) Explode() (result [](*OracleGtidSetEntry)) {
 	intervals := strings.Split(oracleGTIDSetEntry.Ranges, ":")
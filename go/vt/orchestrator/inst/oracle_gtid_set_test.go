@@ -76,6 +76,24 @@ func TestExplode(t *testing.T) {
 	}
 }
 
+func TestCount(t *testing.T) {
+	{
+		entry, err := NewOracleGtidSetEntry("00020194-3333-3333-3333-333333333333:7")
+		test.S(t).ExpectNil(err)
+		test.S(t).ExpectEquals(entry.Count(), int64(1))
+	}
+	{
+		entry, err := NewOracleGtidSetEntry("00020194-3333-3333-3333-333333333333:1-3:6-7:9")
+		test.S(t).ExpectNil(err)
+		test.S(t).ExpectEquals(entry.Count(), int64(len(entry.Explode())))
+	}
+	{
+		gtidSet, err := NewOracleGtidSet("00020192-1111-1111-1111-111111111111:20-30, 00020194-3333-3333-3333-333333333333:1-1000000000")
+		test.S(t).ExpectNil(err)
+		test.S(t).ExpectEquals(gtidSet.Count(), int64(1000000011))
+	}
+}
+
 func TestNewOracleGtidSet(t *testing.T) {
 	{
 		gtidSetVal := "00020192-1111-1111-1111-111111111111:20-30, 00020194-3333-3333-3333-333333333333:7-8"
@@ -169,6 +169,62 @@ func ReadTablet(instanceKey InstanceKey) (*topodatapb.Tablet, error) {
 	return tablet, nil
 }
 
+// ErrantGTIDReplica is a replica with errant GTIDs: transactions executed on the replica which are not found on its primary.
+type ErrantGTIDReplica struct {
+	Key                     InstanceKey
+	TabletAlias             string
+	Keyspace                string
+	Shard                   string
+	GtidErrant              string
+	CountErrantTransactions int64
+}
+
+// ReadErrantGTIDReplicas reads the replicas with errant GTIDs, in all keyspaces or in the given one.
+func ReadErrantGTIDReplicas(keyspace string) ([]*ErrantGTIDReplica, error) {
+	query := `
+		select
+			vitess_tablet.info,
+			database_instance.hostname,
+			database_instance.port,
+			database_instance.gtid_errant
+		from
+			vitess_tablet
+			join database_instance on (
+				vitess_tablet.hostname = database_instance.hostname
+				and vitess_tablet.port = database_instance.port
+			)
+		where
+			database_instance.gtid_errant != ''
+			and vitess_tablet.keyspace LIKE (CASE WHEN ? = '' THEN '%' ELSE ? END)
+		order by
+			vitess_tablet.keyspace, vitess_tablet.shard, database_instance.hostname, database_instance.port
+		`
+	args := sqlutils.Args(keyspace, keyspace)
+	replicas := []*ErrantGTIDReplica{}
+	err := db.QueryOrchestrator(query, args, func(row sqlutils.RowMap) error {
+		tablet := &topodatapb.Tablet{}
+		if err := prototext.Unmarshal([]byte(row.GetString("info")), tablet); err != nil {
+			return err
+		}
+		replica := &ErrantGTIDReplica{
+			Key:         InstanceKey{Hostname: row.GetString("hostname"), Port: row.GetInt("port")},
+			TabletAlias: topoproto.TabletAliasString(tablet.Alias),
+			Keyspace:    tablet.Keyspace,
+			Shard:       tablet.Shard,
+			GtidErrant:  row.GetString("gtid_errant"),
+		}
+		if gtidSet, err := NewOracleGtidSet(replica.GtidErrant); err == nil {
+			replica.CountErrantTransactions = gtidSet.Count()
+		}
+		replicas = append(replicas, replica)
+		return nil
+	})
+	if err != nil {
+		return nil, log.Errore(err)
+	}
+	return replicas, nil
+}
+
 // SaveTablet saves the tablet record against the instanceKey.
 func SaveTablet(tablet *topodatapb.Tablet) error {
 	tabletp, err := prototext.Marshal(tablet)
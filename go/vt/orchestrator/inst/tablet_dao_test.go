@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inst
+
+import (
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/orchestrator/db"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestReadErrantGTIDReplicas(t *testing.T) {
+	orcDb, err := db.OpenOrchestrator()
+	require.NoError(t, err)
+	defer func() {
+		_, err = orcDb.Exec("delete from vitess_tablet")
+		require.NoError(t, err)
+		_, err = orcDb.Exec("delete from database_instance")
+		require.NoError(t, err)
+	}()
+
+	for i, gtidErrant := range []string{"", "00020194-3333-3333-3333-333333333333:1-3:7", ""} {
+		keyspace := "ks"
+		if i == 2 {
+			keyspace = "other"
+		}
+		tablet := &topodatapb.Tablet{
+			Alias:         &topodatapb.TabletAlias{Cell: "zone1", Uid: uint32(100 + i)},
+			Hostname:      "localhost",
+			MysqlHostname: "localhost",
+			MysqlPort:     int32(1300 + i),
+			Keyspace:      keyspace,
+			Shard:         "-",
+			Type:          topodatapb.TabletType_REPLICA,
+		}
+		require.NoError(t, SaveTablet(tablet))
+		instance := NewInstance()
+		instance.Key = InstanceKey{Hostname: tablet.MysqlHostname, Port: int(tablet.MysqlPort)}
+		instance.GtidErrant = gtidErrant
+		require.NoError(t, WriteInstance(instance, true, nil))
+	}
+
+	wanted := []*ErrantGTIDReplica{{
+		Key:                     InstanceKey{Hostname: "localhost", Port: 1301},
+		TabletAlias:             "zone1-0000000101",
+		Keyspace:                "ks",
+		Shard:                   "-",
+		GtidErrant:              "00020194-3333-3333-3333-333333333333:1-3:7",
+		CountErrantTransactions: 4,
+	}}
+	replicas, err := ReadErrantGTIDReplicas("")
+	require.NoError(t, err)
+	require.Equal(t, wanted, replicas)
+	replicas, err = ReadErrantGTIDReplicas("ks")
+	require.NoError(t, err)
+	require.Equal(t, wanted, replicas)
+	replicas, err = ReadErrantGTIDReplicas("other")
+	require.NoError(t, err)
+	require.Empty(t, replicas)
+}
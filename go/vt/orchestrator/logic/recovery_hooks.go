@@ -0,0 +1,326 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	goos "os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/orchestrator/config"
+	"vitess.io/vitess/go/vt/orchestrator/external/golib/log"
+	"vitess.io/vitess/go/vt/orchestrator/inst"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+)
+
+// RecoveryHookPhase tells whether a recovery hook runs before or after a recovery.
+type RecoveryHookPhase string
+
+const (
+	// PreRecoveryHookPhase hooks run before a recovery makes any change. A
+	// failing pre-recovery hook vetoes the recovery.
+	PreRecoveryHookPhase RecoveryHookPhase = "pre"
+	// PostRecoveryHookPhase hooks run after a recovery, whether it succeeded or
+	// not. Their errors are only logged.
+	PostRecoveryHookPhase RecoveryHookPhase = "post"
+)
+
+// RecoveryHookContext is the structured context of a recovery given to recovery hooks.
+type RecoveryHookContext struct {
+	Phase        RecoveryHookPhase `json:"phase"`
+	RecoveryUID  string            `json:"recovery_uid"`
+	AnalysisCode inst.AnalysisCode `json:"analysis_code"`
+	Description  string            `json:"description"`
+	Keyspace     string            `json:"keyspace"`
+	Shard        string            `json:"shard"`
+	// FailedTablet is the alias of the tablet the analysis is about.
+	FailedTablet string `json:"failed_tablet"`
+	// OldPrimary is the alias of the primary of the shard before the recovery.
+	OldPrimary string `json:"old_primary,omitempty"`
+	// NewPrimary is the alias of the primary of the shard after the recovery.
+	// It is only set in the post-recovery phase.
+	NewPrimary string `json:"new_primary,omitempty"`
+	// IsSuccessful and Error are only set in the post-recovery phase.
+	IsSuccessful bool   `json:"is_successful"`
+	Error        string `json:"error,omitempty"`
+}
+
+// environment returns the context as ORC_HOOK_* environment variables.
+func (hookCtx *RecoveryHookContext) environment() []string {
+	return []string{
+		fmt.Sprintf("ORC_HOOK_PHASE=%s", hookCtx.Phase),
+		fmt.Sprintf("ORC_HOOK_RECOVERY_UID=%s", hookCtx.RecoveryUID),
+		fmt.Sprintf("ORC_HOOK_ANALYSIS_CODE=%s", hookCtx.AnalysisCode),
+		fmt.Sprintf("ORC_HOOK_DESCRIPTION=%s", hookCtx.Description),
+		fmt.Sprintf("ORC_HOOK_KEYSPACE=%s", hookCtx.Keyspace),
+		fmt.Sprintf("ORC_HOOK_SHARD=%s", hookCtx.Shard),
+		fmt.Sprintf("ORC_HOOK_FAILED_TABLET=%s", hookCtx.FailedTablet),
+		fmt.Sprintf("ORC_HOOK_OLD_PRIMARY=%s", hookCtx.OldPrimary),
+		fmt.Sprintf("ORC_HOOK_NEW_PRIMARY=%s", hookCtx.NewPrimary),
+		fmt.Sprintf("ORC_HOOK_IS_SUCCESSFUL=%t", hookCtx.IsSuccessful),
+		fmt.Sprintf("ORC_HOOK_ERROR=%s", hookCtx.Error),
+	}
+}
+
+// RecoveryHook is a custom step of a recovery, such as draining a proxy,
+// paging someone or fencing the old primary.
+type RecoveryHook interface {
+	// Name identifies the hook in logs and audits.
+	Name() string
+	// Run runs the hook. In the pre-recovery phase, an error vetoes the recovery.
+	Run(ctx context.Context, hookCtx *RecoveryHookContext) error
+}
+
+var (
+	recoveryHooksMu sync.Mutex
+	recoveryHooks   = map[RecoveryHookPhase][]RecoveryHook{}
+)
+
+// RegisterRecoveryHook registers a hook to run in the given phase of every
+// recovery, after the hooks of the PreRecoveryHooks or PostRecoveryHooks
+// configuration.
+func RegisterRecoveryHook(phase RecoveryHookPhase, hook RecoveryHook) {
+	recoveryHooksMu.Lock()
+	defer recoveryHooksMu.Unlock()
+	recoveryHooks[phase] = append(recoveryHooks[phase], hook)
+}
+
+// getRecoveryHooks returns the configured hooks of a phase, followed by the registered ones.
+func getRecoveryHooks(phase RecoveryHookPhase) []RecoveryHook {
+	specs := config.Config.PreRecoveryHooks
+	if phase == PostRecoveryHookPhase {
+		specs = config.Config.PostRecoveryHooks
+	}
+	hooks := make([]RecoveryHook, 0, len(specs))
+	for _, spec := range specs {
+		hooks = append(hooks, newRecoveryHook(spec))
+	}
+
+	recoveryHooksMu.Lock()
+	defer recoveryHooksMu.Unlock()
+	return append(hooks, recoveryHooks[phase]...)
+}
+
+// newRecoveryHook returns a webhook for an http:// or https:// URL, and an
+// executable hook for anything else.
+func newRecoveryHook(spec string) RecoveryHook {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "http://") || strings.HasPrefix(spec, "https://") {
+		return &webhookRecoveryHook{url: spec}
+	}
+	return &executableRecoveryHook{command: spec}
+}
+
+// executableRecoveryHook runs a local executable, optionally followed by
+// arguments separated by spaces. The executable gets the hook context as JSON
+// on its standard input, and as ORC_HOOK_* environment variables. It fails
+// when it exits with a non-zero code.
+type executableRecoveryHook struct {
+	command string
+}
+
+// Name is part of the RecoveryHook interface.
+func (hook *executableRecoveryHook) Name() string {
+	return hook.command
+}
+
+// Run is part of the RecoveryHook interface.
+func (hook *executableRecoveryHook) Run(ctx context.Context, hookCtx *RecoveryHookContext) error {
+	args := strings.Fields(hook.command)
+	if len(args) == 0 {
+		return fmt.Errorf("empty recovery hook command")
+	}
+	input, err := json.Marshal(hookCtx)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = append(goos.Environ(), hookCtx.environment()...)
+	cmd.Stdin = bytes.NewReader(input)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// webhookRecoveryHook POSTs the hook context as JSON to a URL. It fails when
+// the response status is not 2xx.
+type webhookRecoveryHook struct {
+	url string
+}
+
+// Name is part of the RecoveryHook interface.
+func (hook *webhookRecoveryHook) Name() string {
+	return hook.url
+}
+
+// Run is part of the RecoveryHook interface.
+func (hook *webhookRecoveryHook) Run(ctx context.Context, hookCtx *RecoveryHookContext) error {
+	body, err := json.Marshal(hookCtx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// only keep the start of the response, it may be a whole error page
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// recoveryHookTimeout returns the timeout of each recovery hook.
+func recoveryHookTimeout() time.Duration {
+	if config.Config.RecoveryHookTimeoutSeconds <= 0 {
+		return config.DefaultRecoveryHookTimeoutSeconds * time.Second
+	}
+	return time.Duration(config.Config.RecoveryHookTimeoutSeconds) * time.Second
+}
+
+// runRecoveryHooks runs the hooks of a phase in order, each with a timeout of
+// RecoveryHookTimeoutSeconds. In the pre-recovery phase, it stops at the first
+// failing hook and returns its error.
+func runRecoveryHooks(ctx context.Context, topologyRecovery *TopologyRecovery, hookCtx *RecoveryHookContext) error {
+	hooks := getRecoveryHooks(hookCtx.Phase)
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("Running %d %s-recovery hooks", len(hooks), hookCtx.Phase))
+	for i, hook := range hooks {
+		description := fmt.Sprintf("%s-recovery hook %d of %d (%s)", hookCtx.Phase, i+1, len(hooks), hook.Name())
+		start := time.Now()
+		err := func() error {
+			runCtx, cancel := context.WithTimeout(ctx, recoveryHookTimeout())
+			defer cancel()
+			return hook.Run(runCtx, hookCtx)
+		}()
+		if err != nil {
+			err = fmt.Errorf("%s failed in %v: %v", description, time.Since(start), err)
+			log.Errorf("%v", err)
+			AuditTopologyRecovery(topologyRecovery, err.Error())
+			if hookCtx.Phase == PreRecoveryHookPhase {
+				return err
+			}
+			continue
+		}
+		AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("Completed %s in %v", description, time.Since(start)))
+	}
+	return nil
+}
+
+// newRecoveryHookContext returns the hook context of a recovery, with the
+// keyspace and shard of the analyzed tablet, and the current primary of that
+// shard.
+func newRecoveryHookContext(ctx context.Context, phase RecoveryHookPhase, topologyRecovery *TopologyRecovery) (*RecoveryHookContext, error) {
+	analysisEntry := topologyRecovery.AnalysisEntry
+	tablet, err := inst.ReadTablet(analysisEntry.AnalyzedInstanceKey)
+	if err != nil {
+		return nil, err
+	}
+	hookCtx := &RecoveryHookContext{
+		Phase:        phase,
+		RecoveryUID:  topologyRecovery.UID,
+		AnalysisCode: analysisEntry.Analysis,
+		Description:  analysisEntry.Description,
+		Keyspace:     tablet.Keyspace,
+		Shard:        tablet.Shard,
+		FailedTablet: topoproto.TabletAliasString(tablet.Alias),
+	}
+	si, err := ts.GetShard(ctx, tablet.Keyspace, tablet.Shard)
+	if err != nil {
+		return nil, err
+	}
+	if si.HasPrimary() {
+		hookCtx.OldPrimary = topoproto.TabletAliasString(si.PrimaryAlias)
+	}
+	return hookCtx, nil
+}
+
+// runPreRecoveryHooks runs the pre-recovery hooks of a registered recovery.
+// Recovery functions call it before making any change, and abort when it
+// returns an error. Without pre-recovery hooks, it never returns an error.
+func runPreRecoveryHooks(ctx context.Context, topologyRecovery *TopologyRecovery, skipProcesses bool) error {
+	if skipProcesses {
+		return nil
+	}
+	if len(getRecoveryHooks(PreRecoveryHookPhase)) == 0 {
+		if len(getRecoveryHooks(PostRecoveryHookPhase)) > 0 {
+			// the post-recovery hooks report the primary from before the
+			// recovery, but not knowing it must not block the recovery
+			if hookCtx, err := newRecoveryHookContext(ctx, PreRecoveryHookPhase, topologyRecovery); err != nil {
+				AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("cannot read the primary before the recovery for the post-recovery hooks: %v", err))
+			} else {
+				topologyRecovery.oldPrimaryAlias = hookCtx.OldPrimary
+			}
+		}
+		return nil
+	}
+	hookCtx, err := newRecoveryHookContext(ctx, PreRecoveryHookPhase, topologyRecovery)
+	if err != nil {
+		return topologyRecovery.AddError(fmt.Errorf("cannot run pre-recovery hooks: %v", err))
+	}
+	// the post-recovery hooks report the primary from before the recovery
+	topologyRecovery.oldPrimaryAlias = hookCtx.OldPrimary
+	if err := runRecoveryHooks(ctx, topologyRecovery, hookCtx); err != nil {
+		return topologyRecovery.AddError(fmt.Errorf("recovery vetoed by %v", err))
+	}
+	return nil
+}
+
+// runPostRecoveryHooks runs the post-recovery hooks of a recovery, given the
+// error of the recovery.
+func runPostRecoveryHooks(topologyRecovery *TopologyRecovery, recoveryErr error) {
+	if len(getRecoveryHooks(PostRecoveryHookPhase)) == 0 {
+		return
+	}
+	// the recovery may have used up the deadline of the shard lock
+	ctx := context.Background()
+	hookCtx, err := newRecoveryHookContext(ctx, PostRecoveryHookPhase, topologyRecovery)
+	if err != nil {
+		AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("cannot run post-recovery hooks: %v", err))
+		return
+	}
+	// after the recovery, the current primary is the new one
+	hookCtx.NewPrimary = hookCtx.OldPrimary
+	hookCtx.OldPrimary = topologyRecovery.oldPrimaryAlias
+	hookCtx.IsSuccessful = recoveryErr == nil
+	if recoveryErr != nil {
+		hookCtx.Error = recoveryErr.Error()
+	}
+	runRecoveryHooks(ctx, topologyRecovery, hookCtx)
+}
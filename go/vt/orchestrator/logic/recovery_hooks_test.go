@@ -0,0 +1,211 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/orchestrator/config"
+	"vitess.io/vitess/go/vt/orchestrator/db"
+	"vitess.io/vitess/go/vt/orchestrator/inst"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+// newTestWebhook returns a webhook server that answers with the given status,
+// and a function returning the hook contexts it received.
+func newTestWebhook(t *testing.T, status int) (*httptest.Server, func() []*RecoveryHookContext) {
+	var received []*RecoveryHookContext
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		hookCtx := &RecoveryHookContext{}
+		require.NoError(t, json.Unmarshal(body, hookCtx))
+		received = append(received, hookCtx)
+		w.WriteHeader(status)
+		w.Write([]byte("fencing failed"))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []*RecoveryHookContext { return received }
+}
+
+func TestExecutableRecoveryHook(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "hook.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\ncat > \"$1\"\necho \"$ORC_HOOK_KEYSPACE/$ORC_HOOK_SHARD\" > \"$1.env\"\necho fencing failed\nexit $2\n"), 0700))
+	hookCtx := &RecoveryHookContext{
+		Phase:        PreRecoveryHookPhase,
+		AnalysisCode: inst.DeadPrimary,
+		Keyspace:     "ks",
+		Shard:        "-80",
+		FailedTablet: "zone1-0000000100",
+		OldPrimary:   "zone1-0000000100",
+	}
+
+	out := filepath.Join(dir, "out.json")
+	err := newRecoveryHook(script+" "+out+" 0").Run(context.Background(), hookCtx)
+	require.NoError(t, err)
+	input, err := os.ReadFile(out)
+	require.NoError(t, err)
+	received := &RecoveryHookContext{}
+	require.NoError(t, json.Unmarshal(input, received))
+	assert.Equal(t, hookCtx, received)
+	env, err := os.ReadFile(out + ".env")
+	require.NoError(t, err)
+	assert.Equal(t, "ks/-80\n", string(env))
+
+	err = newRecoveryHook(script+" "+out+" 3").Run(context.Background(), hookCtx)
+	assert.EqualError(t, err, "exit status 3: fencing failed")
+}
+
+func TestWebhookRecoveryHook(t *testing.T) {
+	hookCtx := &RecoveryHookContext{
+		Phase:        PostRecoveryHookPhase,
+		AnalysisCode: inst.DeadPrimary,
+		Keyspace:     "ks",
+		Shard:        "-80",
+		FailedTablet: "zone1-0000000100",
+		OldPrimary:   "zone1-0000000100",
+		NewPrimary:   "zone1-0000000101",
+		IsSuccessful: true,
+	}
+
+	server, received := newTestWebhook(t, http.StatusOK)
+	err := newRecoveryHook(server.URL).Run(context.Background(), hookCtx)
+	require.NoError(t, err)
+	assert.Equal(t, []*RecoveryHookContext{hookCtx}, received())
+
+	server, _ = newTestWebhook(t, http.StatusServiceUnavailable)
+	err = newRecoveryHook(server.URL).Run(context.Background(), hookCtx)
+	assert.EqualError(t, err, "503 Service Unavailable: fencing failed")
+}
+
+func TestPreRecoveryHookVeto(t *testing.T) {
+	orcDb, err := db.OpenOrchestrator()
+	require.NoError(t, err)
+	oldTs := ts
+	oldPreRecoveryHooks := config.Config.PreRecoveryHooks
+	defer func() {
+		ts = oldTs
+		config.Config.PreRecoveryHooks = oldPreRecoveryHooks
+		_, err = orcDb.Exec("delete from vitess_tablet")
+		require.NoError(t, err)
+		_, err = orcDb.Exec("delete from topology_recovery")
+		require.NoError(t, err)
+	}()
+
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  101,
+		},
+		Hostname:      "localhost",
+		MysqlHostname: "localhost",
+		MysqlPort:     1201,
+		Keyspace:      "ks",
+		Shard:         "-",
+		Type:          topodatapb.TabletType_REPLICA,
+	}
+	require.NoError(t, inst.SaveTablet(tablet))
+	ts = memorytopo.NewServer("zone1")
+	require.NoError(t, ts.CreateKeyspace(context.Background(), "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(context.Background(), "ks", "-"))
+
+	server, received := newTestWebhook(t, http.StatusInternalServerError)
+	config.Config.PreRecoveryHooks = []string{server.URL}
+	analysisEntry := inst.ReplicationAnalysis{
+		AnalyzedInstanceKey: inst.InstanceKey{
+			Hostname: tablet.MysqlHostname,
+			Port:     int(tablet.MysqlPort),
+		},
+		Analysis: inst.ReplicationStopped,
+	}
+
+	// hooks do not run when processes are skipped; the recovery then fails as
+	// the shard has no primary
+	analysisEntry.ClusterDetails.ClusterName = "skip-processes"
+	_, _, err = fixReplica(context.Background(), analysisEntry, nil, false, true)
+	require.EqualError(t, err, "no primary tablet for shard ks/-")
+	assert.Empty(t, received())
+
+	analysisEntry.ClusterDetails.ClusterName = "veto"
+
+	recoveryAttempted, topologyRecovery, err := fixReplica(context.Background(), analysisEntry, nil, false, false)
+	require.False(t, recoveryAttempted)
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "recovery vetoed by pre-recovery hook 1 of 1"), err.Error())
+	assert.Contains(t, topologyRecovery.AllErrors, err.Error())
+	assert.Equal(t, []*RecoveryHookContext{{
+		Phase:        PreRecoveryHookPhase,
+		RecoveryUID:  topologyRecovery.UID,
+		AnalysisCode: inst.ReplicationStopped,
+		Keyspace:     "ks",
+		Shard:        "-",
+		FailedTablet: "zone1-0000000101",
+	}}, received())
+}
+
+func TestPostRecoveryHooksDoNotBlockRecovery(t *testing.T) {
+	_, err := db.OpenOrchestrator()
+	require.NoError(t, err)
+	oldPreRecoveryHooks := config.Config.PreRecoveryHooks
+	oldPostRecoveryHooks := config.Config.PostRecoveryHooks
+	defer func() {
+		config.Config.PreRecoveryHooks = oldPreRecoveryHooks
+		config.Config.PostRecoveryHooks = oldPostRecoveryHooks
+	}()
+
+	server, received := newTestWebhook(t, http.StatusOK)
+	config.Config.PreRecoveryHooks = nil
+	config.Config.PostRecoveryHooks = []string{server.URL}
+
+	// the analyzed tablet is unknown, so the primary from before the recovery
+	// cannot be read
+	topologyRecovery := NewTopologyRecovery(inst.ReplicationAnalysis{
+		AnalyzedInstanceKey: inst.InstanceKey{Hostname: "unknown", Port: 3306},
+		Analysis:            inst.DeadPrimary,
+	})
+	require.NoError(t, runPreRecoveryHooks(context.Background(), topologyRecovery, false))
+	assert.Empty(t, topologyRecovery.AllErrors)
+	assert.Empty(t, topologyRecovery.oldPrimaryAlias)
+	assert.Empty(t, received())
+}
+
+func TestRecoveryHookTimeout(t *testing.T) {
+	oldTimeout := config.Config.RecoveryHookTimeoutSeconds
+	defer func() {
+		config.Config.RecoveryHookTimeoutSeconds = oldTimeout
+	}()
+
+	config.Config.RecoveryHookTimeoutSeconds = 0
+	assert.Equal(t, config.DefaultRecoveryHookTimeoutSeconds*time.Second, recoveryHookTimeout())
+
+	config.Config.RecoveryHookTimeoutSeconds = 3
+	assert.Equal(t, 3*time.Second, recoveryHookTimeout())
+}
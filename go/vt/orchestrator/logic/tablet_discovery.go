@@ -21,6 +21,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -29,6 +30,7 @@ import (
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
 
+	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/orchestrator/config"
 
 	"vitess.io/vitess/go/vt/orchestrator/db"
@@ -37,6 +39,7 @@ import (
 	"vitess.io/vitess/go/vt/orchestrator/inst"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 )
@@ -302,6 +305,30 @@ func setReplicationSource(ctx context.Context, replica *topodatapb.Tablet, prima
 	return tmc.SetReplicationSource(ctx, replica, primary.Alias, 0, "", true, semiSync)
 }
 
+// changeTabletType calls the said RPC for the given tablet
+func changeTabletType(ctx context.Context, tablet *topodatapb.Tablet, tabletType topodatapb.TabletType) error {
+	return tmc.ChangeType(ctx, tablet, tabletType, false)
+}
+
+// restoreFromBackup calls the said RPC for the given tablet, restoring the latest backup, and waits for it to finish
+func restoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet) error {
+	stream, err := tmc.RestoreFromBackup(ctx, tablet, time.Time{})
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := stream.Recv()
+		switch err {
+		case nil:
+			log.Infof("restore of %v: %v", topoproto.TabletAliasString(tablet.Alias), logutil.EventString(event))
+		case io.EOF:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
 // shardPrimary finds the primary of the given keyspace-shard by reading the topo server
 func shardPrimary(ctx context.Context, keyspace string, shard string) (primary *topodatapb.Tablet, err error) {
 	si, err := ts.GetShard(ctx, keyspace, shard)
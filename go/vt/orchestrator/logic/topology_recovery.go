@@ -72,6 +72,7 @@ const (
 	electNewPrimaryFunc
 	fixPrimaryFunc
 	fixReplicaFunc
+	recoverErrantGTIDFunc
 )
 
 type RecoveryAcknowledgement struct {
@@ -137,6 +138,9 @@ type TopologyRecovery struct {
 	RelatedRecoveryID         int64
 	Type                      RecoveryType
 	RecoveryType              PrimaryRecoveryType
+
+	// oldPrimaryAlias is the primary of the shard when the pre-recovery hooks ran.
+	oldPrimaryAlias string
 }
 
 func NewTopologyRecovery(replicationAnalysis inst.ReplicationAnalysis) *TopologyRecovery {
@@ -640,6 +644,10 @@ func recoverPrimaryHasPrimary(ctx context.Context, analysisEntry inst.Replicatio
 		resolveRecovery(topologyRecovery, nil)
 	}()
 
+	if err := runPreRecoveryHooks(ctx, topologyRecovery, skipProcesses); err != nil {
+		return false, topologyRecovery, err
+	}
+
 	// Reset replication on current primary.
 	err = inst.ResetReplicationParameters(analysisEntry.AnalyzedInstanceKey)
 	if err != nil {
@@ -679,6 +687,10 @@ func recoverDeadPrimary(ctx context.Context, analysisEntry inst.ReplicationAnaly
 		resolveRecovery(topologyRecovery, promotedReplica)
 	}()
 
+	if err := runPreRecoveryHooks(ctx, topologyRecovery, skipProcesses); err != nil {
+		return false, topologyRecovery, err
+	}
+
 	ev, err := reparentutil.NewEmergencyReparenter(ts, tmclient.NewTabletManagerClient(), logutil.NewCallbackLogger(func(event *logutilpb.Event) {
 		level := event.GetLevel()
 		value := event.GetValue()
@@ -1244,6 +1256,11 @@ func getCheckAndRecoverFunction(analysisCode inst.AnalysisCode, analyzedInstance
 	case inst.NotConnectedToPrimary, inst.ConnectedToWrongPrimary, inst.ReplicationStopped, inst.ReplicaIsWritable,
 		inst.ReplicaSemiSyncMustBeSet, inst.ReplicaSemiSyncMustNotBeSet:
 		return fixReplica, fixReplicaFunc, true
+	case inst.ReplicaHasErrantGTID:
+		if config.Config.ErrantGTIDRemediation == "" {
+			return checkAndRecoverGenericProblem, recoverGenericProblemFunc, false
+		}
+		return recoverErrantGTID, recoverErrantGTIDFunc, true
 	// primary, non actionable
 	case inst.DeadPrimaryAndReplicas:
 		return checkAndRecoverGenericProblem, recoverGenericProblemFunc, false
@@ -1346,6 +1363,16 @@ func executeCheckAndRecoverFunction(analysisEntry inst.ReplicationAnalysis, cand
 			analysisEntry.Analysis, analysisEntry.AnalyzedInstanceKey, candidateInstanceKey, skipProcesses)
 	}
 
+	// The post-recovery hooks run once the shard is unlocked, so that slow
+	// hooks do not hold the shard lock. This is deferred before the unlock,
+	// so it runs after it.
+	var postRecoveryHooks func()
+	defer func() {
+		if postRecoveryHooks != nil {
+			postRecoveryHooks()
+		}
+	}()
+
 	// We lock the shard here and then refresh the tablets information
 	ctx, unlock, err := LockShard(context.Background(), analysisEntry.AnalyzedInstanceKey)
 	if err != nil {
@@ -1393,6 +1420,10 @@ func executeCheckAndRecoverFunction(analysisEntry inst.ReplicationAnalysis, cand
 		log.Infof("Topology recovery: %+v", topologyRecovery)
 	}
 	if !skipProcesses {
+		recoveryErr := err
+		postRecoveryHooks = func() {
+			runPostRecoveryHooks(topologyRecovery, recoveryErr)
+		}
 		if topologyRecovery.SuccessorKey == nil {
 			// Execute general unsuccessful post failover processes
 			executeProcesses(config.Config.PostUnsuccessfulFailoverProcesses, "PostUnsuccessfulFailoverProcesses", topologyRecovery, false)
@@ -1723,6 +1754,10 @@ func electNewPrimary(ctx context.Context, analysisEntry inst.ReplicationAnalysis
 		resolveRecovery(topologyRecovery, promotedReplica)
 	}()
 
+	if err := runPreRecoveryHooks(ctx, topologyRecovery, skipProcesses); err != nil {
+		return false, topologyRecovery, err
+	}
+
 	analyzedTablet, err := inst.ReadTablet(analysisEntry.AnalyzedInstanceKey)
 	if err != nil {
 		return false, topologyRecovery, err
@@ -1776,6 +1811,10 @@ func fixPrimary(ctx context.Context, analysisEntry inst.ReplicationAnalysis, can
 		resolveRecovery(topologyRecovery, nil)
 	}()
 
+	if err := runPreRecoveryHooks(ctx, topologyRecovery, skipProcesses); err != nil {
+		return false, topologyRecovery, err
+	}
+
 	analyzedTablet, err := inst.ReadTablet(analysisEntry.AnalyzedInstanceKey)
 	if err != nil {
 		return false, topologyRecovery, err
@@ -1807,6 +1846,10 @@ func fixReplica(ctx context.Context, analysisEntry inst.ReplicationAnalysis, can
 		resolveRecovery(topologyRecovery, nil)
 	}()
 
+	if err := runPreRecoveryHooks(ctx, topologyRecovery, skipProcesses); err != nil {
+		return false, topologyRecovery, err
+	}
+
 	analyzedTablet, err := inst.ReadTablet(analysisEntry.AnalyzedInstanceKey)
 	if err != nil {
 		return false, topologyRecovery, err
@@ -1833,3 +1876,64 @@ func fixReplica(ctx context.Context, analysisEntry inst.ReplicationAnalysis, can
 	err = setReplicationSource(ctx, analyzedTablet, primaryTablet, inst.IsReplicaSemiSync(durabilityPolicy, primaryTablet, analyzedTablet))
 	return true, topologyRecovery, err
 }
+
+// recoverErrantGTID remediates the errant GTIDs of a replica, as configured by ErrantGTIDRemediation:
+// either by injecting empty transactions on the primary, or by draining the replica and restoring it from backup.
+func recoverErrantGTID(ctx context.Context, analysisEntry inst.ReplicationAnalysis, candidateInstanceKey *inst.InstanceKey, forceInstanceRecovery bool, skipProcesses bool) (recoveryAttempted bool, topologyRecovery *TopologyRecovery, err error) {
+	topologyRecovery, err = AttemptRecoveryRegistration(&analysisEntry, false, true)
+	if topologyRecovery == nil {
+		AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("found an active or recent recovery on %+v. Will not issue another recoverErrantGTID.", analysisEntry.AnalyzedInstanceKey))
+		return false, nil, err
+	}
+	log.Infof("Analysis: %v, will remediate errant GTIDs %v on replica %+v", analysisEntry.Analysis, analysisEntry.GTIDErrant, analysisEntry.AnalyzedInstanceKey)
+	// This has to be done in the end; whether successful or not, we should mark that the recovery is done.
+	// So that after the active period passes, we are able to run other recoveries.
+	defer func() {
+		resolveRecovery(topologyRecovery, nil)
+	}()
+
+	if err := runPreRecoveryHooks(ctx, topologyRecovery, skipProcesses); err != nil {
+		return false, topologyRecovery, err
+	}
+
+	switch config.Config.ErrantGTIDRemediation {
+	case config.ErrantGTIDRemediationInjectEmpty:
+		gtidSet, err := inst.NewOracleGtidSet(analysisEntry.GTIDErrant)
+		if err != nil {
+			return false, topologyRecovery, topologyRecovery.AddError(err)
+		}
+		// Only a handful of errant transactions are considered benign. More than that usually means
+		// the replica was written to, and making the primary claim those writes would hide the drift.
+		if count := gtidSet.Count(); count > int64(config.Config.ErrantGTIDInjectEmptyMaxTransactions) {
+			err := fmt.Errorf("%+v has %d errant transactions, more than ErrantGTIDInjectEmptyMaxTransactions (%d); will not inject empty transactions",
+				analysisEntry.AnalyzedInstanceKey, count, config.Config.ErrantGTIDInjectEmptyMaxTransactions)
+			return false, topologyRecovery, topologyRecovery.AddError(err)
+		}
+		_, clusterPrimary, countInjectedTransactions, err := inst.ErrantGTIDInjectEmpty(&analysisEntry.AnalyzedInstanceKey)
+		if err != nil {
+			return true, topologyRecovery, topologyRecovery.AddError(err)
+		}
+		AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("injected %d empty transactions on primary %+v", countInjectedTransactions, clusterPrimary.Key))
+	case config.ErrantGTIDRemediationRestore:
+		analyzedTablet, err := inst.ReadTablet(analysisEntry.AnalyzedInstanceKey)
+		if err != nil {
+			return false, topologyRecovery, topologyRecovery.AddError(err)
+		}
+		alias := topoproto.TabletAliasString(analyzedTablet.Alias)
+		if err := changeTabletType(ctx, analyzedTablet, topodatapb.TabletType_DRAINED); err != nil {
+			return true, topologyRecovery, topologyRecovery.AddError(err)
+		}
+		AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("changed %v to DRAINED", alias))
+		AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("starting restore of %v from backup", alias))
+		// A restore takes far longer than we may hold the shard lock, so it runs in the background.
+		// The restored tablet stays DRAINED, for an operator to return it to service.
+		go func() {
+			if err := restoreFromBackup(context.Background(), analyzedTablet); err != nil {
+				AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("restore of %v from backup failed: %v", alias, err))
+				return
+			}
+			AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("restored %v from backup", alias))
+		}()
+	}
+	return true, topologyRecovery, nil
+}
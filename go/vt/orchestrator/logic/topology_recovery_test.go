@@ -18,16 +18,23 @@ package logic
 
 import (
 	"context"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/orchestrator/config"
 	"vitess.io/vitess/go/vt/orchestrator/db"
 	"vitess.io/vitess/go/vt/orchestrator/inst"
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
 	// import the gRPC client implementation for tablet manager
 	_ "vitess.io/vitess/go/vt/vttablet/grpctmclient"
@@ -128,3 +135,101 @@ func TestElectNewPrimaryPanic(t *testing.T) {
 	require.True(t, recoveryAttempted)
 	require.Error(t, err)
 }
+
+// errantGTIDTabletManagerClient records the tablet type changes and restores it is asked for.
+type errantGTIDTabletManagerClient struct {
+	tmclient.TabletManagerClient
+	changeTypes chan string
+	restores    chan string
+}
+
+func (fake *errantGTIDTabletManagerClient) ChangeType(ctx context.Context, tablet *topodatapb.Tablet, tabletType topodatapb.TabletType, semiSync bool) error {
+	fake.changeTypes <- topoproto.TabletAliasString(tablet.Alias) + " " + tabletType.String()
+	return nil
+}
+
+func (fake *errantGTIDTabletManagerClient) RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, backupTime time.Time) (logutil.EventStream, error) {
+	fake.restores <- topoproto.TabletAliasString(tablet.Alias)
+	return &restoreEventStream{}, nil
+}
+
+type restoreEventStream struct {
+	sent bool
+}
+
+func (stream *restoreEventStream) Recv() (*logutilpb.Event, error) {
+	if stream.sent {
+		return nil, io.EOF
+	}
+	stream.sent = true
+	return &logutilpb.Event{Value: "restore done"}, nil
+}
+
+func TestRecoverErrantGTID(t *testing.T) {
+	orcDb, err := db.OpenOrchestrator()
+	require.NoError(t, err)
+	oldTmc := tmc
+	oldErrantGTIDRemediation := config.Config.ErrantGTIDRemediation
+	defer func() {
+		tmc = oldTmc
+		config.Config.ErrantGTIDRemediation = oldErrantGTIDRemediation
+		_, err = orcDb.Exec("delete from vitess_tablet")
+		require.NoError(t, err)
+		_, err = orcDb.Exec("delete from topology_recovery")
+		require.NoError(t, err)
+	}()
+
+	fake := &errantGTIDTabletManagerClient{
+		changeTypes: make(chan string, 1),
+		restores:    make(chan string, 1),
+	}
+	tmc = fake
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  102,
+		},
+		Hostname:      "localhost",
+		MysqlHostname: "localhost",
+		MysqlPort:     1202,
+		Keyspace:      "ks",
+		Shard:         "-",
+		Type:          topodatapb.TabletType_REPLICA,
+	}
+	require.NoError(t, inst.SaveTablet(tablet))
+	analysisEntry := inst.ReplicationAnalysis{
+		AnalyzedInstanceKey: inst.InstanceKey{
+			Hostname: tablet.MysqlHostname,
+			Port:     int(tablet.MysqlPort),
+		},
+		Analysis:   inst.ReplicaHasErrantGTID,
+		GTIDErrant: "00020194-3333-3333-3333-333333333333:1-20",
+	}
+
+	// errant GTIDs are only reported unless a remediation is configured
+	config.Config.ErrantGTIDRemediation = ""
+	_, recoveryFunc, isActionable := getCheckAndRecoverFunction(inst.ReplicaHasErrantGTID, &analysisEntry.AnalyzedInstanceKey)
+	assert.Equal(t, recoverGenericProblemFunc, recoveryFunc)
+	assert.False(t, isActionable)
+
+	config.Config.ErrantGTIDRemediation = config.ErrantGTIDRemediationInjectEmpty
+	_, recoveryFunc, isActionable = getCheckAndRecoverFunction(inst.ReplicaHasErrantGTID, &analysisEntry.AnalyzedInstanceKey)
+	assert.Equal(t, recoverErrantGTIDFunc, recoveryFunc)
+	assert.True(t, isActionable)
+
+	// too many errant transactions to be benign
+	analysisEntry.ClusterDetails.ClusterName = "inject-empty"
+	recoveryAttempted, topologyRecovery, err := recoverErrantGTID(context.Background(), analysisEntry, nil, false, false)
+	require.False(t, recoveryAttempted)
+	require.EqualError(t, err, "localhost:1202 has 20 errant transactions, more than ErrantGTIDInjectEmptyMaxTransactions (10); will not inject empty transactions")
+	assert.Contains(t, topologyRecovery.AllErrors, err.Error())
+
+	config.Config.ErrantGTIDRemediation = config.ErrantGTIDRemediationRestore
+	analysisEntry.ClusterDetails.ClusterName = "restore"
+	recoveryAttempted, topologyRecovery, err = recoverErrantGTID(context.Background(), analysisEntry, nil, false, false)
+	require.True(t, recoveryAttempted)
+	require.NoError(t, err)
+	assert.Empty(t, topologyRecovery.AllErrors)
+	assert.Equal(t, "zone1-0000000102 DRAINED", <-fake.changeTypes)
+	assert.Equal(t, "zone1-0000000102", <-fake.restores)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemadiff
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// alterOptionCapableOfInstantDDL checks if the specific alter option is eligible to run via ALGORITHM=INSTANT
+// reorganization. The function does not check for the ALGORITHM=INSTANT capability of the server as a whole.
+func alterOptionCapableOfInstantDDL(alterOption sqlparser.AlterOption, createTable *sqlparser.CreateTable, capableOf mysql.CapableOf) (bool, error) {
+	findColumn := func(colName string) *sqlparser.ColumnDefinition {
+		if createTable == nil {
+			return nil
+		}
+		for _, col := range createTable.TableSpec.Columns {
+			if strings.EqualFold(colName, col.Name.String()) {
+				return col
+			}
+		}
+		return nil
+	}
+	isVirtualColumn := func(colName string) bool {
+		col := findColumn(colName)
+		if col == nil {
+			return false
+		}
+		if col.Type.Options == nil {
+			return false
+		}
+		if col.Type.Options.As == nil {
+			return false
+		}
+		return col.Type.Options.Storage == sqlparser.VirtualStorage
+	}
+	colStringWithoutDefault := func(col *sqlparser.ColumnDefinition) string {
+		colWithoutDefault := sqlparser.CloneRefOfColumnDefinition(col)
+		if colWithoutDefault.Type.Options != nil {
+			colWithoutDefault.Type.Options.Default = nil
+		}
+		return sqlparser.CanonicalString(colWithoutDefault)
+	}
+	// Up to 8.0.26 we could only ADD COLUMN as last column
+	switch opt := alterOption.(type) {
+	case *sqlparser.AddColumns:
+		if opt.First || opt.After != nil {
+			// not a "last" column. Only supported as of 8.0.29
+			return capableOf(mysql.InstantAddDropColumnFlavorCapability)
+		}
+		// Adding a *last* column is supported in 8.0
+		return capableOf(mysql.InstantAddLastColumnFlavorCapability)
+	case *sqlparser.DropColumn:
+		if isVirtualColumn(opt.Name.Name.String()) {
+			// supported by all 8.0 versions
+			return capableOf(mysql.InstantAddDropVirtualColumnFlavorCapability)
+		}
+		return capableOf(mysql.InstantAddDropColumnFlavorCapability)
+	case *sqlparser.ModifyColumn:
+		if col := findColumn(opt.NewColDefinition.Name.String()); col != nil {
+			// Check if only diff is change of default
+			// we temporarily remove the DEFAULT expression (if any) from both
+			// table and ALTER statement, and compare the columns: if they're otherwise equal,
+			// then the only change can be an addition/change/removal of DEFAULT, which
+			// is instant-table.
+			tableColDefinition := colStringWithoutDefault(col)
+			newColDefinition := colStringWithoutDefault(opt.NewColDefinition)
+			if tableColDefinition == newColDefinition {
+				return capableOf(mysql.InstantChangeColumnDefaultFlavorCapability)
+			}
+		}
+		return false, nil
+	default:
+		return false, nil
+	}
+}
+
+// AlterTableCapableOfInstantDDL checks if the given ALTER TABLE, applied on the given CREATE TABLE, can run
+// with ALGORITHM=INSTANT on a server with the given capabilities.
+func AlterTableCapableOfInstantDDL(alterTable *sqlparser.AlterTable, createTable *sqlparser.CreateTable, capableOf mysql.CapableOf) (bool, error) {
+	capable, err := capableOf(mysql.InstantDDLFlavorCapability)
+	if err != nil {
+		return false, err
+	}
+	if !capable {
+		return false, nil
+	}
+	if alterTable.PartitionOption != nil {
+		// no INSTANT for partitions
+		return false, nil
+	}
+	if alterTable.PartitionSpec != nil {
+		// no INSTANT for partitions
+		return false, nil
+	}
+	for _, alterOption := range alterTable.AlterOptions {
+		instantOK, err := alterOptionCapableOfInstantDDL(alterOption, createTable, capableOf)
+		if err != nil {
+			return false, err
+		}
+		if !instantOK {
+			return false, nil
+		}
+	}
+	return true, nil
+}
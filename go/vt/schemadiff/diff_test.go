@@ -615,6 +615,56 @@ func TestDiffSchemas(t *testing.T) {
 				"CREATE VIEW `v0` AS SELECT * FROM `v2`, `t2`",
 			},
 		},
+		{
+			name: "stored programs",
+			from: "create table t(id int primary key, i int); create trigger tr1 before insert on t for each row set new.i = 1; create trigger tr2 after insert on t for each row set @a = 1; create procedure p1() select 1; create function f1() returns int return 1; create event e1 on schedule every 1 hour do delete from t",
+			to:   "create table t(id int primary key, i int); create trigger tr1 before insert on t for each row set new.i = 2; create procedure p2() select 2; create function f1() returns int return 2; create event e1 on schedule every 2 hour do delete from t",
+			diffs: []string{
+				"drop trigger tr2",
+				"drop procedure p1",
+				"drop function f1",
+				"drop trigger tr1",
+				"drop event e1",
+				"create procedure p2() select 2",
+			},
+			cdiffs: []string{
+				"DROP TRIGGER `tr2`",
+				"DROP PROCEDURE `p1`",
+				"DROP FUNCTION `f1`",
+				"DROP TRIGGER `tr1`",
+				"DROP EVENT `e1`",
+				"CREATE PROCEDURE `p2`() select 2",
+			},
+		},
+		{
+			name: "dropped table and its trigger",
+			from: "create table t1(id int primary key); create table t2(id int primary key); create trigger tr1 before insert on t1 for each row set new.id = 1",
+			to:   "create table t2(id int primary key)",
+			diffs: []string{
+				"drop trigger tr1",
+				"drop table t1",
+			},
+			cdiffs: []string{
+				"DROP TRIGGER `tr1`",
+				"DROP TABLE `t1`",
+			},
+		},
+		{
+			name: "trigger on renamed table",
+			from: "create table t1(id int primary key); create trigger tr1 before insert on t1 for each row set new.id = 1",
+			to:   "create table t2(id int primary key); create trigger tr1 before insert on t2 for each row set new.id = 1",
+			diffs: []string{
+				"drop trigger tr1",
+				"create trigger tr1 before insert on t2 for each row set new.id = 1",
+				"rename table t1 to t2",
+			},
+			cdiffs: []string{
+				"DROP TRIGGER `tr1`",
+				"CREATE TRIGGER `tr1` BEFORE INSERT ON `t2` FOR EACH ROW set new.id = 1",
+				"RENAME TABLE `t1` TO `t2`",
+			},
+			tableRename: TableRenameHeuristicStatement,
+		},
 	}
 	for _, ts := range tt {
 		t.Run(ts.name, func(t *testing.T) {
@@ -707,6 +757,11 @@ func TestSchemaApplyError(t *testing.T) {
 			from: "create table t(id int); create view v1 as select * from t",
 			to:   "create table t(id int); create view v1 as select * from t; create view v2 as select * from t",
 		},
+		{
+			name: "added trigger",
+			from: "create table t(id int)",
+			to:   "create table t(id int); create trigger tr1 before insert on t for each row set new.id = 1",
+		},
 	}
 	hints := &DiffHints{}
 	for _, ts := range tt {
@@ -738,3 +793,140 @@ func TestSchemaApplyError(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderedDiffSchemas(t *testing.T) {
+	tt := []struct {
+		name        string
+		from        string
+		to          string
+		expectDiffs []string
+		expectErr   error
+	}{
+		{
+			name: "identical",
+			from: "create table t(id int primary key); create view v as select id from t",
+			to:   "create table t(id int primary key); create view v as select id from t",
+		},
+		{
+			name: "drop view before dropping the column it reads",
+			from: "create table t(id int primary key, i int); create view v as select id, i from t",
+			to:   "create table t(id int primary key)",
+			expectDiffs: []string{
+				"drop view v",
+				"alter table t drop column i",
+			},
+		},
+		{
+			name: "create table before the view reading from it",
+			from: "create table t1(id int primary key); create view v as select id from t1",
+			to:   "create table t1(id int primary key); create table t2(id int primary key); create view v as select t2.id from t1 join t2 on t1.id = t2.id",
+			expectDiffs: []string{
+				"create table t2 (\n\tid int primary key\n)",
+				"alter view v as select t2.id from t1 join t2 on t1.id = t2.id",
+			},
+		},
+		{
+			name: "add key before the foreign key that needs it",
+			from: "create table t1(id int primary key, r int); create table t2(id int primary key, x int)",
+			to:   "create table t1(id int primary key, r int, key r_idx (r), constraint f1 foreign key (r) references t2 (x)); create table t2(id int primary key, x int, key x_idx (x))",
+			expectDiffs: []string{
+				"alter table t2 add key x_idx (x)",
+				"alter table t1 add key r_idx (r), add constraint f1 foreign key (r) references t2 (x)",
+			},
+		},
+		{
+			name: "split alter view",
+			from: "create table t(id int primary key, a int); create view v as select id, a from t",
+			to:   "create table t(id int primary key, b int); create view v as select id, b from t",
+			expectDiffs: []string{
+				"drop view v",
+				"alter table t drop column a, add column b int",
+				"create view v as select id, b from t",
+			},
+		},
+		{
+			name: "alter view before dropping the function it calls",
+			from: "create table t(id int primary key); create function f1(x int) returns int return x; create view v as select f1(id) from t",
+			to:   "create table t(id int primary key); create view v as select id from t",
+			expectDiffs: []string{
+				"alter view v as select id from t",
+				"drop function f1",
+			},
+		},
+		{
+			name: "replace a function called by a view",
+			from: "create table t(id int primary key); create function f1(x int) returns int return x; create view v as select f1(id) from t",
+			to:   "create table t(id int primary key); create function f1(x int) returns int return x + 1; create view v as select f1(id) from t",
+			expectDiffs: []string{
+				"drop function f1",
+			},
+		},
+		{
+			name: "trigger moves to a new table",
+			from: "create table t1(id int primary key); create trigger tr1 before insert on t1 for each row set new.id = 1",
+			to:   "create table t2(id int primary key); create trigger tr1 before insert on t2 for each row set new.id = 1",
+			expectDiffs: []string{
+				"drop trigger tr1",
+				"drop table t1",
+				"create table t2 (\n\tid int primary key\n)",
+				"create trigger tr1 before insert on t2 for each row set new.id = 1",
+			},
+		},
+		{
+			name: "dangling foreign key",
+			from: "create table t1(id int primary key, r int, key r_idx (r), constraint f1 foreign key (r) references t2 (id)); create view v as select id from t1",
+			to:   "create table t1(id int primary key, r int, i int, key r_idx (r), constraint f1 foreign key (r) references t2 (id)); create view v as select id, i from t1",
+			expectDiffs: []string{
+				"alter table t1 add column i int",
+				"alter view v as select id, i from t1",
+			},
+		},
+		{
+			name: "drop foreign key before the table it references",
+			from: "create table t1(id int primary key, r int, key r_idx (r), constraint f1 foreign key (r) references t2 (id)); create table t2(id int primary key)",
+			to:   "create table t1(id int primary key, r int, key r_idx (r))",
+			expectDiffs: []string{
+				"alter table t1 drop foreign key f1",
+				"drop table t2",
+			},
+		},
+		{
+			name:      "conflicting foreign key and column",
+			from:      "create table t1(id int primary key, r int, key r_idx (r), constraint f1 foreign key (r) references t2 (a)); create table t2(id int primary key, a int, key a_idx (a))",
+			to:        "create table t1(id int primary key, r int, key r_idx (r), constraint f1 foreign key (r) references t2 (b)); create table t2(id int primary key, b int, key b_idx (b))",
+			expectErr: &ImpossibleApplyDiffOrderError{},
+		},
+	}
+	hints := &DiffHints{}
+	for _, ts := range tt {
+		t.Run(ts.name, func(t *testing.T) {
+			fromSchema, err := NewSchemaFromSQL(ts.from)
+			require.NoError(t, err)
+			toSchema, err := NewSchemaFromSQL(ts.to)
+			require.NoError(t, err)
+
+			diffs, err := fromSchema.OrderedDiff(toSchema, hints)
+			if ts.expectErr != nil {
+				require.Error(t, err)
+				assert.IsType(t, ts.expectErr, err)
+				return
+			}
+			require.NoError(t, err)
+			var statements []string
+			for _, diff := range diffs {
+				statements = append(statements, diff.StatementString())
+			}
+			assert.Equal(t, ts.expectDiffs, statements)
+
+			// applying the diffs one by one must always produce a valid schema
+			applied := fromSchema
+			for _, diff := range diffs {
+				applied, err = applied.Apply([]EntityDiff{diff})
+				require.NoError(t, err)
+			}
+			appliedDiffs, err := applied.Diff(toSchema, hints)
+			require.NoError(t, err)
+			assert.Empty(t, appliedDiffs)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package schemadiff
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"vitess.io/vitess/go/sqlescape"
 )
@@ -15,6 +16,8 @@ var (
 	ErrExpectedCreateTable            = errors.New("expected a CREATE TABLE statement")
 	ErrExpectedCreateView             = errors.New("expected a CREATE VIEW statement")
 	ErrViewDependencyUnresolved       = errors.New("views have unresolved/loop dependencies")
+	ErrUnexpectedApplyResult          = errors.New("applying diffs did not result in the expected schema")
+	ErrTriggerOrderUnresolved         = errors.New("triggers have unresolved/loop FOLLOWS/PRECEDES dependencies")
 )
 
 type UnsupportedEntityError struct {
@@ -76,6 +79,15 @@ func (e *ApplyViewNotFoundError) Error() string {
 	return fmt.Sprintf("view %s not found", sqlescape.EscapeID(e.View))
 }
 
+type ApplyProgramNotFoundError struct {
+	Type string
+	Name string
+}
+
+func (e *ApplyProgramNotFoundError) Error() string {
+	return fmt.Sprintf("%s %s not found", e.Type, sqlescape.EscapeID(e.Name))
+}
+
 type ApplyKeyNotFoundError struct {
 	Table string
 	Key   string
@@ -239,3 +251,95 @@ func (e *InvalidColumnInForeignKeyConstraintError) Error() string {
 	return fmt.Sprintf("invalid column %s referenced by foreign key constraint %s in table %s",
 		sqlescape.EscapeID(e.Column), sqlescape.EscapeID(e.Constraint), sqlescape.EscapeID(e.Table))
 }
+
+type ImpossibleApplyDiffOrderError struct {
+	UnorderedDiffs    []EntityDiff
+	ConflictingErrors []error
+}
+
+func (e *ImpossibleApplyDiffOrderError) Error() string {
+	var b strings.Builder
+	b.WriteString("no valid order to apply the schema diffs:")
+	for i, diff := range e.UnorderedDiffs {
+		if i > 0 {
+			b.WriteString(";")
+		}
+		fmt.Fprintf(&b, " %s", diff.CanonicalStatementString())
+		if i < len(e.ConflictingErrors) {
+			fmt.Fprintf(&b, ": %v", e.ConflictingErrors[i])
+		}
+	}
+	return b.String()
+}
+
+type InvalidReferencedTableInForeignKeyConstraintError struct {
+	Table           string
+	Constraint      string
+	ReferencedTable string
+}
+
+func (e *InvalidReferencedTableInForeignKeyConstraintError) Error() string {
+	return fmt.Sprintf("invalid table %s referenced by foreign key constraint %s in table %s",
+		sqlescape.EscapeID(e.ReferencedTable), sqlescape.EscapeID(e.Constraint), sqlescape.EscapeID(e.Table))
+}
+
+type InvalidReferencedColumnInForeignKeyConstraintError struct {
+	Table           string
+	Constraint      string
+	ReferencedTable string
+	Column          string
+}
+
+func (e *InvalidReferencedColumnInForeignKeyConstraintError) Error() string {
+	return fmt.Sprintf("invalid column %s in table %s referenced by foreign key constraint %s in table %s",
+		sqlescape.EscapeID(e.Column), sqlescape.EscapeID(e.ReferencedTable), sqlescape.EscapeID(e.Constraint), sqlescape.EscapeID(e.Table))
+}
+
+type MissingForeignKeyReferencedIndexError struct {
+	Table           string
+	Constraint      string
+	ReferencedTable string
+}
+
+func (e *MissingForeignKeyReferencedIndexError) Error() string {
+	return fmt.Sprintf("missing index in table %s for the columns referenced by foreign key constraint %s in table %s",
+		sqlescape.EscapeID(e.ReferencedTable), sqlescape.EscapeID(e.Constraint), sqlescape.EscapeID(e.Table))
+}
+
+type InvalidColumnReferencedByViewError struct {
+	View   string
+	Column string
+}
+
+func (e *InvalidColumnReferencedByViewError) Error() string {
+	return fmt.Sprintf("invalid column %s referenced by view %s", e.Column, sqlescape.EscapeID(e.View))
+}
+
+type InvalidTableInTriggerError struct {
+	Trigger string
+	Table   string
+}
+
+func (e *InvalidTableInTriggerError) Error() string {
+	return fmt.Sprintf("trigger %s is defined on nonexistent table %s", sqlescape.EscapeID(e.Trigger), sqlescape.EscapeID(e.Table))
+}
+
+type InvalidTriggerOrderError struct {
+	Trigger      string
+	Order        string
+	OtherTrigger string
+}
+
+func (e *InvalidTriggerOrderError) Error() string {
+	return fmt.Sprintf("trigger %s %s trigger %s, which is not defined for the same table, timing and event",
+		sqlescape.EscapeID(e.Trigger), strings.ToUpper(e.Order), sqlescape.EscapeID(e.OtherTrigger))
+}
+
+type ApplyFunctionReferencedByViewError struct {
+	Function string
+	View     string
+}
+
+func (e *ApplyFunctionReferencedByViewError) Error() string {
+	return fmt.Sprintf("function %s is referenced by view %s", sqlescape.EscapeID(e.Function), sqlescape.EscapeID(e.View))
+}
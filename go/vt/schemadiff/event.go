@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemadiff
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// CreateEventEntity stands for an EVENT construct. It contains the event's CREATE statement.
+type CreateEventEntity struct {
+	sqlparser.CreateEvent
+}
+
+func NewCreateEventEntity(c *sqlparser.CreateEvent) (*CreateEventEntity, error) {
+	entity := &CreateEventEntity{CreateEvent: *c}
+	entity.normalize()
+	return entity, nil
+}
+
+func (c *CreateEventEntity) normalize() {
+	// IF NOT EXISTS has no meaning in a schema
+	c.CreateEvent.IfNotExists = false
+	// Drop the default completion
+	if strings.EqualFold(c.CreateEvent.OnCompletion, "not preserve") {
+		c.CreateEvent.OnCompletion = ""
+	}
+	// Drop the default status
+	if strings.EqualFold(c.CreateEvent.Status, "enable") {
+		c.CreateEvent.Status = ""
+	}
+}
+
+// Name implements Entity interface
+func (c *CreateEventEntity) Name() string {
+	return c.CreateEvent.Name.Name.String()
+}
+
+// programType implements programEntity
+func (c *CreateEventEntity) programType() string {
+	return "event"
+}
+
+// Diff implements Entity interface function
+func (c *CreateEventEntity) Diff(other Entity, hints *DiffHints) (EntityDiff, error) {
+	otherCreateEvent, ok := other.(*CreateEventEntity)
+	if !ok {
+		return nil, ErrEntityTypeMismatch
+	}
+	return c.EventDiff(otherCreateEvent, hints)
+}
+
+// EventDiff compares this event statement with another event statement, and sees what it takes to
+// change this event to look like the other event.
+// It returns an AlterProgramEntityDiff, which drops and recreates the event, if changes are found, or nil if not.
+// The other event may be of different name; its name is ignored.
+func (c *CreateEventEntity) EventDiff(other *CreateEventEntity, hints *DiffHints) (*AlterProgramEntityDiff, error) {
+	otherStmt := other.CreateEvent
+	otherStmt.Name = c.CreateEvent.Name
+
+	if sqlparser.CanonicalString(&c.CreateEvent) == sqlparser.CanonicalString(&otherStmt) {
+		return nil, nil
+	}
+	return newAlterProgramEntityDiff(c, other), nil
+}
+
+// Create implements Entity interface
+func (c *CreateEventEntity) Create() EntityDiff {
+	return &CreateProgramEntityDiff{to: c, create: &c.CreateEvent}
+}
+
+// Drop implements Entity interface
+func (c *CreateEventEntity) Drop() EntityDiff {
+	dropEvent := &sqlparser.DropEvent{
+		Name: c.CreateEvent.Name,
+	}
+	return &DropProgramEntityDiff{from: c, drop: dropEvent}
+}
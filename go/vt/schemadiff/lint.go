@@ -0,0 +1,445 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemadiff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// LintCode identifies the kind of a lint finding
+type LintCode string
+
+const (
+	// DestructiveLintCode means the diff drops a table, a column or partitions, along with their data
+	DestructiveLintCode LintCode = "destructive"
+	// NoSharedUniqueKeyLintCode means vreplication based Online DDL rejects the ALTER TABLE, because the
+	// table before and after the change do not have suitable unique keys to iterate their rows
+	NoSharedUniqueKeyLintCode LintCode = "no_shared_unique_key"
+	// LossyColumnChangeLintCode means the diff changes the type of a column in a way that may lose or
+	// truncate some of its values
+	LossyColumnChangeLintCode LintCode = "lossy_column_change"
+	// InstantDDLLintCode means the ALTER TABLE can run with ALGORITHM=INSTANT on the given server
+	InstantDDLLintCode LintCode = "instant_ddl"
+)
+
+// LintFinding is something noteworthy about a diff
+type LintFinding struct {
+	Code LintCode
+	// Entity is the name of the entity the finding is about, e.g. a table name
+	Entity  string
+	Message string
+}
+
+// String returns a human readable representation of the finding
+func (f *LintFinding) String() string {
+	return fmt.Sprintf("%s: %s", f.Code, f.Message)
+}
+
+// DiffLint is the list of findings of a diff
+type DiffLint struct {
+	Diff     EntityDiff
+	Findings []*LintFinding
+}
+
+// LintDiffs lints the given diffs, along with their subsequent diffs, in order. See LintDiff.
+func LintDiffs(diffs []EntityDiff, capableOf mysql.CapableOf) ([]*DiffLint, error) {
+	var lints []*DiffLint
+	for _, diff := range diffs {
+		for _, d := range AllSubsequent(diff) {
+			findings, err := LintDiff(d, capableOf)
+			if err != nil {
+				return nil, err
+			}
+			lints = append(lints, &DiffLint{Diff: d, Findings: findings})
+		}
+	}
+	return lints, nil
+}
+
+// LintDiff returns the findings of the given diff, not including its subsequent diffs. It reports
+// whether the diff:
+// - drops a table, a column or partitions
+// - is an ALTER TABLE rejected by vreplication based Online DDL, for lack of suitable unique keys
+// - changes the type of a column in a way that may lose or truncate values
+// - is an ALTER TABLE that can run with ALGORITHM=INSTANT.
+// capableOf describes the server the diff is applied on. When nil, ALGORITHM=INSTANT is not analyzed.
+func LintDiff(diff EntityDiff, capableOf mysql.CapableOf) ([]*LintFinding, error) {
+	switch diff := diff.(type) {
+	case *DropTableEntityDiff:
+		return []*LintFinding{{
+			Code:    DestructiveLintCode,
+			Entity:  diff.from.Name(),
+			Message: fmt.Sprintf("table %s is dropped", sqlescape.EscapeID(diff.from.Name())),
+		}}, nil
+	case *AlterTableEntityDiff:
+		return lintAlterTable(diff, capableOf)
+	}
+	return nil, nil
+}
+
+// lintAlterTable returns the findings of an ALTER TABLE diff
+func lintAlterTable(diff *AlterTableEntityDiff, capableOf mysql.CapableOf) ([]*LintFinding, error) {
+	if diff.IsEmpty() {
+		return nil, nil
+	}
+	tableName := diff.from.Name()
+	var findings []*LintFinding
+	addFinding := func(code LintCode, format string, args ...any) {
+		findings = append(findings, &LintFinding{
+			Code:    code,
+			Entity:  tableName,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	fromColumns := map[string]*sqlparser.ColumnDefinition{}
+	for _, col := range diff.from.TableSpec.Columns {
+		fromColumns[col.Name.Lowered()] = col
+	}
+	lintColumnChange := func(oldName string, toCol *sqlparser.ColumnDefinition) {
+		fromCol, ok := fromColumns[strings.ToLower(oldName)]
+		if !ok {
+			return
+		}
+		if reason := lossyColumnChange(fromCol, toCol); reason != "" {
+			addFinding(LossyColumnChangeLintCode, "column %s of table %s changes from %s to %s, %s",
+				sqlescape.EscapeID(fromCol.Name.String()), sqlescape.EscapeID(tableName),
+				columnTypeString(fromCol), columnTypeString(toCol), reason)
+		}
+	}
+	for _, opt := range diff.alterTable.AlterOptions {
+		switch opt := opt.(type) {
+		case *sqlparser.DropColumn:
+			addFinding(DestructiveLintCode, "column %s of table %s is dropped",
+				sqlescape.EscapeID(opt.Name.Name.String()), sqlescape.EscapeID(tableName))
+		case *sqlparser.ModifyColumn:
+			lintColumnChange(opt.NewColDefinition.Name.String(), opt.NewColDefinition)
+		case *sqlparser.ChangeColumn:
+			lintColumnChange(opt.OldColumn.Name.String(), opt.NewColDefinition)
+		}
+	}
+	if spec := diff.alterTable.PartitionSpec; spec != nil {
+		switch spec.Action {
+		case sqlparser.DropAction, sqlparser.TruncateAction:
+			names := make([]string, 0, len(spec.Names))
+			for _, name := range spec.Names {
+				names = append(names, sqlescape.EscapeID(name.String()))
+			}
+			verb := "dropped"
+			if spec.Action == sqlparser.TruncateAction {
+				verb = "truncated"
+			}
+			addFinding(DestructiveLintCode, "partitions %s of table %s are %s", strings.Join(names, ", "), sqlescape.EscapeID(tableName), verb)
+		}
+	}
+
+	if diff.to != nil {
+		fromKey, toKey := sharedIterationKeys(diff.from, diff.to, diff.alterTable)
+		switch {
+		case fromKey == nil:
+			addFinding(NoSharedUniqueKeyLintCode, "table %s has no unique key of NOT NULL, non floating point columns that the change keeps; vreplication based Online DDL rejects the change",
+				sqlescape.EscapeID(tableName))
+		case toKey == nil:
+			addFinding(NoSharedUniqueKeyLintCode, "after the change, table %s has no unique key of NOT NULL, non floating point columns that exist before the change; vreplication based Online DDL rejects the change",
+				sqlescape.EscapeID(tableName))
+		}
+	}
+
+	if capableOf != nil {
+		instant, err := AlterTableCapableOfInstantDDL(diff.alterTable, &diff.from.CreateTable, capableOf)
+		if err != nil {
+			return nil, err
+		}
+		if instant {
+			addFinding(InstantDDLLintCode, "the change to table %s can run with ALGORITHM=INSTANT", sqlescape.EscapeID(tableName))
+		}
+	}
+	return findings, nil
+}
+
+// columnTypeString returns the type of a column, without its options, e.g. "varchar(64)"
+func columnTypeString(col *sqlparser.ColumnDefinition) string {
+	colType := col.Type
+	colType.Options = nil
+	return sqlparser.CanonicalString(&colType)
+}
+
+// integralTypeDigits is the number of decimal digits needed to represent any value of an integral type
+var integralTypeDigits = map[string]int{
+	"tinyint":   3,
+	"smallint":  5,
+	"mediumint": 8,
+	"int":       10,
+	"integer":   10,
+	"bigint":    20,
+}
+
+// floatTypes lists the approximate numeric types, from least to most precise
+var floatTypes = map[string]int{
+	"float":  1,
+	"real":   2,
+	"double": 2,
+}
+
+var decimalTypes = map[string]bool{
+	"decimal": true,
+	"numeric": true,
+}
+
+// textualCapacities is the maximum length, in characters, of the textual types with no explicit length
+var textualCapacities = map[string]int{
+	"char":       1,
+	"tinytext":   255,
+	"text":       65535,
+	"mediumtext": 16777215,
+	"longtext":   4294967295,
+}
+
+// binaryCapacities is the maximum length, in bytes, of the binary types with no explicit length
+var binaryCapacities = map[string]int{
+	"binary":     1,
+	"tinyblob":   255,
+	"blob":       65535,
+	"mediumblob": 16777215,
+	"longblob":   4294967295,
+}
+
+var temporalTypes = map[string]bool{
+	"date":      true,
+	"datetime":  true,
+	"timestamp": true,
+	"time":      true,
+	"year":      true,
+}
+
+// literalInt returns the integer value of a length or a scale, or the given default if there is none
+func literalInt(l *sqlparser.Literal, defaultValue int) int {
+	if l == nil {
+		return defaultValue
+	}
+	v, err := strconv.Atoi(l.Val)
+	if err != nil {
+		return defaultValue
+	}
+	return v
+}
+
+// textualCapacity returns the maximum length of a textual or a binary type, and whether the type is textual or binary
+func textualCapacity(colType *sqlparser.ColumnType) (capacity int, textual bool, binary bool) {
+	switch colType.Type {
+	case "varchar":
+		return literalInt(colType.Length, 0), true, false
+	case "varbinary":
+		return literalInt(colType.Length, 0), false, true
+	}
+	if defaultCapacity, ok := textualCapacities[colType.Type]; ok {
+		if colType.Type == "char" {
+			return literalInt(colType.Length, defaultCapacity), true, false
+		}
+		return defaultCapacity, true, false
+	}
+	if defaultCapacity, ok := binaryCapacities[colType.Type]; ok {
+		if colType.Type == "binary" {
+			return literalInt(colType.Length, defaultCapacity), false, true
+		}
+		return defaultCapacity, false, true
+	}
+	return 0, false, false
+}
+
+// decimalDigits returns the precision and scale of a DECIMAL type
+func decimalDigits(colType *sqlparser.ColumnType) (precision int, scale int) {
+	return literalInt(colType.Length, 10), literalInt(colType.Scale, 0)
+}
+
+// valuesLength returns the maximum length of the values of an ENUM or a SET
+func valuesLength(colType *sqlparser.ColumnType) int {
+	length := 0
+	for _, value := range colType.EnumValues {
+		valueLength := len(strings.Trim(value, "'"))
+		if colType.Type == "set" {
+			// all values, separated by commas
+			length += valueLength + 1
+		} else if valueLength > length {
+			length = valueLength
+		}
+	}
+	return length
+}
+
+// lossyColumnChange returns the reason why changing a column from the given definition to the other may
+// lose or truncate some of its values. It returns an empty string if all values are kept. The analysis is
+// conservative: a change between unrelated types is considered lossy.
+func lossyColumnChange(from, to *sqlparser.ColumnDefinition) string {
+	fromType, toType := &from.Type, &to.Type
+	if columnTypeString(from) == columnTypeString(to) {
+		return ""
+	}
+	fromName, toName := strings.ToLower(fromType.Type), strings.ToLower(toType.Type)
+
+	// integral types
+	if fromDigits, ok := integralTypeDigits[fromName]; ok {
+		if toDigits, ok := integralTypeDigits[toName]; ok {
+			switch {
+			case toDigits < fromDigits:
+				return "which may truncate values"
+			case !fromType.Unsigned && toType.Unsigned:
+				return "which cannot hold negative values"
+			case fromType.Unsigned && !toType.Unsigned && toDigits == fromDigits:
+				return "which cannot hold the largest unsigned values"
+			}
+			return ""
+		}
+		if decimalTypes[toName] {
+			precision, scale := decimalDigits(toType)
+			if precision-scale < fromDigits {
+				return "which may truncate values"
+			}
+			if !fromType.Unsigned && toType.Unsigned {
+				return "which cannot hold negative values"
+			}
+			return ""
+		}
+		if _, ok := floatTypes[toName]; ok {
+			// float holds integers up to 2^24 precisely, double up to 2^53
+			if (toName == "float" && fromDigits > 8) || fromName == "bigint" {
+				return "which may lose precision"
+			}
+			return ""
+		}
+		if capacity, textual, _ := textualCapacity(toType); textual {
+			if capacity < fromDigits+1 {
+				return "which may truncate values"
+			}
+			return ""
+		}
+		return "which may not hold all its values"
+	}
+
+	// fixed point types
+	if decimalTypes[fromName] {
+		fromPrecision, fromScale := decimalDigits(fromType)
+		if decimalTypes[toName] {
+			toPrecision, toScale := decimalDigits(toType)
+			switch {
+			case toScale < fromScale:
+				return "which may round values"
+			case toPrecision-toScale < fromPrecision-fromScale:
+				return "which may truncate values"
+			case !fromType.Unsigned && toType.Unsigned:
+				return "which cannot hold negative values"
+			}
+			return ""
+		}
+		if capacity, textual, _ := textualCapacity(toType); textual {
+			// digits, sign and decimal point
+			if capacity < fromPrecision+2 {
+				return "which may truncate values"
+			}
+			return ""
+		}
+		return "which may not hold all its values"
+	}
+
+	// floating point types
+	if fromRank, ok := floatTypes[fromName]; ok {
+		if toRank, ok := floatTypes[toName]; ok {
+			if toRank < fromRank {
+				return "which may lose precision"
+			}
+			return ""
+		}
+		return "which may not hold all its values"
+	}
+
+	// ENUM and SET
+	if fromName == "enum" || fromName == "set" {
+		if toName == fromName {
+			toValues := map[string]bool{}
+			for _, value := range toType.EnumValues {
+				toValues[value] = true
+			}
+			for _, value := range fromType.EnumValues {
+				if !toValues[value] {
+					return fmt.Sprintf("which removes the value %s", value)
+				}
+			}
+			return ""
+		}
+		if capacity, textual, _ := textualCapacity(toType); textual {
+			if capacity < valuesLength(fromType) {
+				return "which may truncate values"
+			}
+			return ""
+		}
+		return "which may not hold all its values"
+	}
+
+	// textual and binary types
+	if fromCapacity, fromTextual, fromBinary := textualCapacity(fromType); fromTextual || fromBinary {
+		toCapacity, toTextual, toBinary := textualCapacity(toType)
+		if fromTextual != toTextual || fromBinary != toBinary {
+			return "which may not hold all its values"
+		}
+		if toCapacity < fromCapacity {
+			return "which may truncate values"
+		}
+		if fromTextual {
+			toCharset := toType.Charset.Name
+			if toCharset != "" && toCharset != fromType.Charset.Name && toCharset != "utf8mb4" {
+				return fmt.Sprintf("whose character set %s may not represent all characters", toCharset)
+			}
+		}
+		return ""
+	}
+
+	// temporal types
+	if temporalTypes[fromName] {
+		if !temporalTypes[toName] {
+			return "which may not hold all its values"
+		}
+		switch {
+		case fromName == toName:
+		case fromName == "date" && toName == "datetime":
+		case fromName == "timestamp" && toName == "datetime":
+		case toName == "date" && (fromName == "datetime" || fromName == "timestamp"):
+			return "which drops the time of day"
+		default:
+			return "which may not hold all its values"
+		}
+		// fractional seconds precision
+		if literalInt(toType.Length, 0) < literalInt(fromType.Length, 0) {
+			return "which may round fractional seconds"
+		}
+		return ""
+	}
+
+	if fromName != toName {
+		return "which may not hold all its values"
+	}
+	if literalInt(toType.Length, 0) < literalInt(fromType.Length, 0) {
+		// e.g. BIT(M)
+		return "which may truncate values"
+	}
+	return ""
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemadiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestLintDiff(t *testing.T) {
+	tt := []struct {
+		name     string
+		from     string
+		to       string
+		version  string
+		findings []string
+	}{
+		{
+			name: "create table",
+			to:   "create table t (id int primary key)",
+		},
+		{
+			name:     "drop table",
+			from:     "create table t (id int primary key)",
+			findings: []string{"destructive: table `t` is dropped"},
+		},
+		{
+			name:     "drop column",
+			from:     "create table t (id int primary key, i int)",
+			to:       "create table t (id int primary key)",
+			findings: []string{"destructive: column `i` of table `t` is dropped"},
+		},
+		{
+			name: "add index",
+			from: "create table t (id int primary key, i int)",
+			to:   "create table t (id int primary key, i int, key i_idx (i))",
+		},
+		{
+			name:     "narrower integer",
+			from:     "create table t (id int primary key, i bigint)",
+			to:       "create table t (id int primary key, i int)",
+			findings: []string{"lossy_column_change: column `i` of table `t` changes from bigint to int, which may truncate values"},
+		},
+		{
+			name: "wider integer",
+			from: "create table t (id int primary key, i int)",
+			to:   "create table t (id int primary key, i bigint)",
+		},
+		{
+			name:     "signed to unsigned",
+			from:     "create table t (id int primary key, i int)",
+			to:       "create table t (id int primary key, i int unsigned)",
+			findings: []string{"lossy_column_change: column `i` of table `t` changes from int to int unsigned, which cannot hold negative values"},
+		},
+		{
+			name: "unsigned to wider signed",
+			from: "create table t (id int primary key, i int unsigned)",
+			to:   "create table t (id int primary key, i bigint)",
+		},
+		{
+			name:     "shorter varchar",
+			from:     "create table t (id int primary key, s varchar(64))",
+			to:       "create table t (id int primary key, s varchar(32))",
+			findings: []string{"lossy_column_change: column `s` of table `t` changes from varchar(64) to varchar(32), which may truncate values"},
+		},
+		{
+			name: "varchar to text",
+			from: "create table t (id int primary key, s varchar(64))",
+			to:   "create table t (id int primary key, s text)",
+		},
+		{
+			name:     "decimal scale",
+			from:     "create table t (id int primary key, d decimal(10,4))",
+			to:       "create table t (id int primary key, d decimal(10,2))",
+			findings: []string{"lossy_column_change: column `d` of table `t` changes from decimal(10,4) to decimal(10,2), which may round values"},
+		},
+		{
+			name: "int to decimal",
+			from: "create table t (id int primary key, i int)",
+			to:   "create table t (id int primary key, i decimal(12,2))",
+		},
+		{
+			name:     "removed enum value",
+			from:     "create table t (id int primary key, e enum('a', 'b'))",
+			to:       "create table t (id int primary key, e enum('a'))",
+			findings: []string{"lossy_column_change: column `e` of table `t` changes from enum('a', 'b') to enum('a'), which removes the value 'b'"},
+		},
+		{
+			name: "added enum value",
+			from: "create table t (id int primary key, e enum('a', 'b'))",
+			to:   "create table t (id int primary key, e enum('a', 'b', 'c'))",
+		},
+		{
+			name:     "datetime to date",
+			from:     "create table t (id int primary key, d datetime)",
+			to:       "create table t (id int primary key, d date)",
+			findings: []string{"lossy_column_change: column `d` of table `t` changes from datetime to date, which drops the time of day"},
+		},
+		{
+			name:     "text to int",
+			from:     "create table t (id int primary key, s varchar(10))",
+			to:       "create table t (id int primary key, s int)",
+			findings: []string{"lossy_column_change: column `s` of table `t` changes from varchar(10) to int, which may not hold all its values"},
+		},
+		{
+			name:     "no unique key",
+			from:     "create table t (id int, i int)",
+			to:       "create table t (id int, i int, j int)",
+			findings: []string{"no_shared_unique_key: table `t` has no unique key of NOT NULL, non floating point columns that the change keeps; vreplication based Online DDL rejects the change"},
+		},
+		{
+			name:     "nullable unique key",
+			from:     "create table t (id int, i int, unique key id_idx (id))",
+			to:       "create table t (id int, i int, j int, unique key id_idx (id))",
+			findings: []string{"no_shared_unique_key: table `t` has no unique key of NOT NULL, non floating point columns that the change keeps; vreplication based Online DDL rejects the change"},
+		},
+		{
+			name: "not null unique key",
+			from: "create table t (id int not null, i int, unique key id_idx (id))",
+			to:   "create table t (id int not null, i int, j int, unique key id_idx (id))",
+		},
+		{
+			name:     "float primary key",
+			from:     "create table t (id double, i int, primary key (id))",
+			to:       "create table t (id double, i int, j int, primary key (id))",
+			findings: []string{"no_shared_unique_key: table `t` has no unique key of NOT NULL, non floating point columns that the change keeps; vreplication based Online DDL rejects the change"},
+		},
+		{
+			name: "primary key replaced by a unique key of the same columns",
+			from: "create table t (id int, i int, primary key (id))",
+			to:   "create table t (id int not null, i int, unique key id_idx (id))",
+		},
+		{
+			name: "different keys covered by both tables",
+			from: "create table t (id int, i int not null, primary key (id))",
+			to:   "create table t (id int, i int not null, primary key (i))",
+		},
+		{
+			name: "primary key column dropped",
+			from: "create table t (id int, i int, primary key (id))",
+			to:   "create table t (i int not null, primary key (i))",
+			findings: []string{
+				"destructive: column `id` of table `t` is dropped",
+				"no_shared_unique_key: table `t` has no unique key of NOT NULL, non floating point columns that the change keeps; vreplication based Online DDL rejects the change",
+			},
+		},
+		{
+			name:     "new primary key on columns that did not exist",
+			from:     "create table t (id int, primary key (id))",
+			to:       "create table t (id int, i int not null, primary key (i))",
+			findings: []string{"no_shared_unique_key: after the change, table `t` has no unique key of NOT NULL, non floating point columns that exist before the change; vreplication based Online DDL rejects the change"},
+		},
+		{
+			name:     "instant add column",
+			from:     "create table t (id int primary key, i int)",
+			to:       "create table t (id int primary key, i int, j int)",
+			version:  "8.0.21",
+			findings: []string{"instant_ddl: the change to table `t` can run with ALGORITHM=INSTANT"},
+		},
+		{
+			name:    "add column not instant on 5.7",
+			from:    "create table t (id int primary key, i int)",
+			to:      "create table t (id int primary key, i int, j int)",
+			version: "5.7.28",
+		},
+		{
+			name:    "instant drop column",
+			from:    "create table t (id int primary key, i int)",
+			to:      "create table t (id int primary key)",
+			version: "8.0.29",
+			findings: []string{
+				"destructive: column `i` of table `t` is dropped",
+				"instant_ddl: the change to table `t` can run with ALGORITHM=INSTANT",
+			},
+		},
+	}
+	for _, ts := range tt {
+		t.Run(ts.name, func(t *testing.T) {
+			diff, err := DiffCreateTablesQueries(ts.from, ts.to, &DiffHints{})
+			require.NoError(t, err)
+
+			var capableOf mysql.CapableOf
+			if ts.version != "" {
+				_, capableOf, _ = mysql.GetFlavor(ts.version, nil)
+			}
+			findings, err := LintDiff(diff, capableOf)
+			require.NoError(t, err)
+			var findingStrings []string
+			for _, finding := range findings {
+				assert.Equal(t, "t", finding.Entity)
+				findingStrings = append(findingStrings, finding.String())
+			}
+			assert.Equal(t, ts.findings, findingStrings)
+		})
+	}
+}
+
+func TestLintDiffs(t *testing.T) {
+	from, err := NewSchemaFromSQL("create table t1 (id int primary key, i int); create table t2 (id int primary key); create view v1 as select id from t2")
+	require.NoError(t, err)
+	to, err := NewSchemaFromSQL("create table t1 (id int primary key, i int, j int)")
+	require.NoError(t, err)
+
+	diffs, err := from.OrderedDiff(to, &DiffHints{})
+	require.NoError(t, err)
+	_, capableOf, _ := mysql.GetFlavor("8.0.30", nil)
+	lints, err := LintDiffs(diffs, capableOf)
+	require.NoError(t, err)
+
+	lintStrings := map[string][]string{}
+	for _, lint := range lints {
+		var findingStrings []string
+		for _, finding := range lint.Findings {
+			findingStrings = append(findingStrings, finding.String())
+		}
+		lintStrings[lint.Diff.CanonicalStatementString()] = findingStrings
+	}
+	assert.Equal(t, map[string][]string{
+		"DROP VIEW `v1`":                      nil,
+		"DROP TABLE `t2`":                     {"destructive: table `t2` is dropped"},
+		"ALTER TABLE `t1` ADD COLUMN `j` int": {"instant_ddl: the change to table `t1` can run with ALGORITHM=INSTANT"},
+	}, lintStrings)
+}
+
+func TestLintStatementDiffs(t *testing.T) {
+	schema, err := NewSchemaFromSQL("create table t (id int, i int, s varchar(64), primary key (id))")
+	require.NoError(t, err)
+
+	tt := []struct {
+		statement string
+		findings  []string
+	}{
+		{
+			statement: "alter table t change column s s2 varchar(16)",
+			findings:  []string{"lossy_column_change: column `s` of table `t` changes from varchar(64) to varchar(16), which may truncate values"},
+		},
+		{
+			statement: "alter table t change column id id2 int",
+		},
+		{
+			statement: "alter table t drop column i, add column j int",
+			findings:  []string{"destructive: column `i` of table `t` is dropped"},
+		},
+		{
+			statement: "alter table t drop primary key",
+			findings:  []string{"no_shared_unique_key: after the change, table `t` has no unique key of NOT NULL, non floating point columns that exist before the change; vreplication based Online DDL rejects the change"},
+		},
+	}
+	for _, ts := range tt {
+		t.Run(ts.statement, func(t *testing.T) {
+			stmt, err := sqlparser.ParseStrictDDL(ts.statement)
+			require.NoError(t, err)
+			diffs, err := schema.StatementDiffs(stmt)
+			require.NoError(t, err)
+			require.Len(t, diffs, 1)
+
+			findings, err := LintDiff(diffs[0], nil)
+			require.NoError(t, err)
+			var findingStrings []string
+			for _, finding := range findings {
+				findingStrings = append(findingStrings, finding.String())
+			}
+			assert.Equal(t, ts.findings, findingStrings)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemadiff
+
+import (
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// columnRenameMap returns the columns renamed by the given ALTER TABLE, mapping the lowercase old name
+// of each column to its lowercase new name.
+func columnRenameMap(alterTable *sqlparser.AlterTable) map[string]string {
+	m := map[string]string{}
+	for _, opt := range alterTable.AlterOptions {
+		switch opt := opt.(type) {
+		case *sqlparser.RenameColumn:
+			m[opt.OldName.Name.Lowered()] = opt.NewName.Name.Lowered()
+		case *sqlparser.ChangeColumn:
+			if oldName, newName := opt.OldColumn.Name.Lowered(), opt.NewColDefinition.Name.Lowered(); oldName != newName {
+				m[oldName] = newName
+			}
+		}
+	}
+	return m
+}
+
+// iterationKey is a unique key that vreplication can use to iterate the rows of a table
+type iterationKey struct {
+	name    string
+	columns []string
+}
+
+// iterationKeys returns the unique keys of the table that vreplication can use to iterate its rows: keys
+// whose columns are all NOT NULL, and none of which is of a floating point type. This follows the logic
+// of onlineddl/vrepl's UniqueKeyValidForIteration(). The PRIMARY KEY, if any, comes first.
+func iterationKeys(c *CreateTableEntity) []*iterationKey {
+	columns := map[string]*sqlparser.ColumnDefinition{}
+	for _, col := range c.CreateTable.TableSpec.Columns {
+		columns[col.Name.Lowered()] = col
+	}
+	primaryColumns := map[string]bool{}
+	for _, key := range c.CreateTable.TableSpec.Indexes {
+		if key.Info.Primary {
+			for _, col := range key.Columns {
+				primaryColumns[col.Column.Lowered()] = true
+			}
+		}
+	}
+	validForIteration := func(colName string) bool {
+		col, ok := columns[colName]
+		if !ok {
+			return false
+		}
+		switch col.Type.Type {
+		case "float", "double":
+			// imprecise, we cannot use them while iterating unique keys
+			return false
+		}
+		if primaryColumns[colName] {
+			return true
+		}
+		if col.Type.Options == nil {
+			return false
+		}
+		if col.Type.Options.KeyOpt.IsPrimary() {
+			return true
+		}
+		// NULLable columns in a unique key means the set of values is not really unique
+		return col.Type.Options.Null != nil && !*col.Type.Options.Null
+	}
+
+	var keys []*iterationKey
+	// column level keys: `id int PRIMARY KEY`, `u int UNIQUE KEY`
+	for _, col := range c.CreateTable.TableSpec.Columns {
+		if col.Type.Options == nil || !col.Type.Options.KeyOpt.IsUnique() {
+			continue
+		}
+		if !validForIteration(col.Name.Lowered()) {
+			continue
+		}
+		key := &iterationKey{name: col.Name.String(), columns: []string{col.Name.Lowered()}}
+		if col.Type.Options.KeyOpt.IsPrimary() {
+			key.name = "PRIMARY"
+			keys = append([]*iterationKey{key}, keys...)
+		} else {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range c.CreateTable.TableSpec.Indexes {
+		if !key.Info.Primary && !key.Info.Unique {
+			continue
+		}
+		iterKey := &iterationKey{name: key.Info.Name.String()}
+		for _, col := range key.Columns {
+			colName := col.Column.Lowered()
+			if col.Expression != nil || !validForIteration(colName) {
+				iterKey = nil
+				break
+			}
+			iterKey.columns = append(iterKey.columns, colName)
+		}
+		if iterKey == nil {
+			continue
+		}
+		if key.Info.Primary {
+			iterKey.name = "PRIMARY"
+			keys = append([]*iterationKey{iterKey}, keys...)
+		} else {
+			keys = append(keys, iterKey)
+		}
+	}
+	return keys
+}
+
+// copiedColumns returns the lowercase names of the columns whose values vreplication copies from one table
+// to another, i.e. the columns that are not generated.
+func copiedColumns(c *CreateTableEntity) map[string]bool {
+	m := map[string]bool{}
+	for _, col := range c.CreateTable.TableSpec.Columns {
+		if col.Type.Options != nil && col.Type.Options.As != nil {
+			continue
+		}
+		m[col.Name.Lowered()] = true
+	}
+	return m
+}
+
+// iterationKeyCoveredByColumns returns the first of the given keys whose columns, once renamed by
+// the given map, are all found in the given columns. It returns nil if there is no such key.
+func iterationKeyCoveredByColumns(keys []*iterationKey, columns map[string]bool, renameMap map[string]string) *iterationKey {
+	for _, key := range keys {
+		covered := true
+		for _, colName := range key.columns {
+			if renamed, ok := renameMap[colName]; ok {
+				colName = renamed
+			}
+			if !columns[colName] {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return key
+		}
+	}
+	return nil
+}
+
+// sharedIterationKeys returns the unique keys that vreplication based Online DDL uses to migrate the "from"
+// table into the "to" table. A migration is rejected when either of the keys is nil. Like vreplication, we
+// look for a key on each table whose columns are also found on the other table.
+func sharedIterationKeys(from, to *CreateTableEntity, alterTable *sqlparser.AlterTable) (fromKey, toKey *iterationKey) {
+	renameMap := columnRenameMap(alterTable)
+	reverseRenameMap := map[string]string{}
+	for oldName, newName := range renameMap {
+		reverseRenameMap[newName] = oldName
+	}
+	fromKey = iterationKeyCoveredByColumns(iterationKeys(from), copiedColumns(to), renameMap)
+	toKey = iterationKeyCoveredByColumns(iterationKeys(to), copiedColumns(from), reverseRenameMap)
+	return fromKey, toKey
+}
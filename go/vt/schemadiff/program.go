@@ -0,0 +1,202 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemadiff
+
+import (
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// programEntity is implemented by the entities of stored programs: triggers, stored procedures,
+// functions and events.
+type programEntity interface {
+	Entity
+	// programType is the type of the stored program, e.g. "trigger"
+	programType() string
+}
+
+// CreateProgramEntityDiff stands for the creation of a stored program: a trigger, a stored procedure,
+// a function or an event.
+type CreateProgramEntityDiff struct {
+	to     programEntity
+	create sqlparser.Statement
+}
+
+// IsEmpty implements EntityDiff
+func (d *CreateProgramEntityDiff) IsEmpty() bool {
+	return d.Statement() == nil
+}
+
+// Entities implements EntityDiff
+func (d *CreateProgramEntityDiff) Entities() (from Entity, to Entity) {
+	return nil, d.to
+}
+
+// Statement implements EntityDiff
+func (d *CreateProgramEntityDiff) Statement() sqlparser.Statement {
+	if d == nil {
+		return nil
+	}
+	return d.create
+}
+
+// StatementString implements EntityDiff
+func (d *CreateProgramEntityDiff) StatementString() (s string) {
+	if stmt := d.Statement(); stmt != nil {
+		s = sqlparser.String(stmt)
+	}
+	return s
+}
+
+// CanonicalStatementString implements EntityDiff
+func (d *CreateProgramEntityDiff) CanonicalStatementString() (s string) {
+	if stmt := d.Statement(); stmt != nil {
+		s = sqlparser.CanonicalString(stmt)
+	}
+	return s
+}
+
+// SubsequentDiff implements EntityDiff
+func (d *CreateProgramEntityDiff) SubsequentDiff() EntityDiff {
+	return nil
+}
+
+// SetSubsequentDiff implements EntityDiff
+func (d *CreateProgramEntityDiff) SetSubsequentDiff(EntityDiff) {
+}
+
+// DropProgramEntityDiff stands for the removal of a stored program.
+type DropProgramEntityDiff struct {
+	from programEntity
+	drop sqlparser.Statement
+}
+
+// IsEmpty implements EntityDiff
+func (d *DropProgramEntityDiff) IsEmpty() bool {
+	return d.Statement() == nil
+}
+
+// Entities implements EntityDiff
+func (d *DropProgramEntityDiff) Entities() (from Entity, to Entity) {
+	return d.from, nil
+}
+
+// Statement implements EntityDiff
+func (d *DropProgramEntityDiff) Statement() sqlparser.Statement {
+	if d == nil {
+		return nil
+	}
+	return d.drop
+}
+
+// StatementString implements EntityDiff
+func (d *DropProgramEntityDiff) StatementString() (s string) {
+	if stmt := d.Statement(); stmt != nil {
+		s = sqlparser.String(stmt)
+	}
+	return s
+}
+
+// CanonicalStatementString implements EntityDiff
+func (d *DropProgramEntityDiff) CanonicalStatementString() (s string) {
+	if stmt := d.Statement(); stmt != nil {
+		s = sqlparser.CanonicalString(stmt)
+	}
+	return s
+}
+
+// SubsequentDiff implements EntityDiff
+func (d *DropProgramEntityDiff) SubsequentDiff() EntityDiff {
+	return nil
+}
+
+// SetSubsequentDiff implements EntityDiff
+func (d *DropProgramEntityDiff) SetSubsequentDiff(EntityDiff) {
+}
+
+// AlterProgramEntityDiff stands for a change in the definition of a stored program.
+// MySQL cannot alter the body of a stored program, so the diff's statement drops the stored program,
+// and its subsequent diff creates the stored program anew.
+type AlterProgramEntityDiff struct {
+	from           programEntity
+	to             programEntity
+	drop           sqlparser.Statement
+	subsequentDiff *CreateProgramEntityDiff
+}
+
+// newAlterProgramEntityDiff returns the diff that replaces the given stored program with the other.
+func newAlterProgramEntityDiff(from programEntity, to programEntity) *AlterProgramEntityDiff {
+	return &AlterProgramEntityDiff{
+		from:           from,
+		to:             to,
+		drop:           from.Drop().Statement(),
+		subsequentDiff: to.Create().(*CreateProgramEntityDiff),
+	}
+}
+
+// IsEmpty implements EntityDiff
+func (d *AlterProgramEntityDiff) IsEmpty() bool {
+	return d.Statement() == nil
+}
+
+// Entities implements EntityDiff
+func (d *AlterProgramEntityDiff) Entities() (from Entity, to Entity) {
+	return d.from, d.to
+}
+
+// Statement implements EntityDiff
+func (d *AlterProgramEntityDiff) Statement() sqlparser.Statement {
+	if d == nil {
+		return nil
+	}
+	return d.drop
+}
+
+// StatementString implements EntityDiff
+func (d *AlterProgramEntityDiff) StatementString() (s string) {
+	if stmt := d.Statement(); stmt != nil {
+		s = sqlparser.String(stmt)
+	}
+	return s
+}
+
+// CanonicalStatementString implements EntityDiff
+func (d *AlterProgramEntityDiff) CanonicalStatementString() (s string) {
+	if stmt := d.Statement(); stmt != nil {
+		s = sqlparser.CanonicalString(stmt)
+	}
+	return s
+}
+
+// SubsequentDiff implements EntityDiff
+func (d *AlterProgramEntityDiff) SubsequentDiff() EntityDiff {
+	if d == nil || d.subsequentDiff == nil {
+		return nil
+	}
+	return d.subsequentDiff
+}
+
+// SetSubsequentDiff implements EntityDiff
+func (d *AlterProgramEntityDiff) SetSubsequentDiff(subDiff EntityDiff) {
+	if d == nil {
+		return
+	}
+	if createDiff, ok := subDiff.(*CreateProgramEntityDiff); ok {
+		d.subsequentDiff = createDiff
+	} else {
+		d.subsequentDiff = nil
+	}
+}
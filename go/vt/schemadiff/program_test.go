@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemadiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func newTestProgramEntity(t *testing.T, query string) Entity {
+	stmt, err := sqlparser.ParseStrictDDL(query)
+	require.NoError(t, err)
+	var entity Entity
+	switch stmt := stmt.(type) {
+	case *sqlparser.CreateTrigger:
+		entity, err = NewCreateTriggerEntity(stmt)
+	case *sqlparser.CreateProcedure:
+		entity, err = NewCreateProcedureEntity(stmt)
+	case *sqlparser.CreateFunction:
+		entity, err = NewCreateFunctionEntity(stmt)
+	case *sqlparser.CreateEvent:
+		entity, err = NewCreateEventEntity(stmt)
+	default:
+		require.FailNow(t, "unexpected statement", query)
+	}
+	require.NoError(t, err)
+	return entity
+}
+
+func TestCreateProgramDiff(t *testing.T) {
+	tt := []struct {
+		name    string
+		from    string
+		to      string
+		diff    string
+		create  string
+		isError bool
+	}{
+		{
+			name: "identical trigger",
+			from: "create trigger tr1 before insert on t for each row set new.i = 1",
+			to:   "CREATE TRIGGER `tr1` BEFORE INSERT ON `t` FOR EACH ROW set new.i = 1",
+		},
+		{
+			name: "trigger order is ignored",
+			from: "create trigger tr1 before insert on t for each row set new.i = 1",
+			to:   "create trigger tr1 before insert on t for each row follows tr0 set new.i = 1",
+		},
+		{
+			name:   "trigger body",
+			from:   "create trigger tr1 before insert on t for each row set new.i = 1",
+			to:     "create trigger tr1 before insert on t for each row set new.i = 2",
+			diff:   "drop trigger tr1",
+			create: "create trigger tr1 before insert on t for each row set new.i = 2",
+		},
+		{
+			name:   "trigger timing",
+			from:   "create trigger tr1 before insert on t for each row set @a = 1",
+			to:     "create trigger tr1 after insert on t for each row set @a = 1",
+			diff:   "drop trigger tr1",
+			create: "create trigger tr1 after insert on t for each row set @a = 1",
+		},
+		{
+			name: "identical procedure, default characteristics",
+			from: "create procedure p1(in x int) language sql contains sql sql security definer begin select x; end",
+			to:   "create procedure p1(in x int) begin select x; end",
+		},
+		{
+			name:   "procedure parameters",
+			from:   "create procedure p1(in x int) begin select x; end",
+			to:     "create procedure p1(in x bigint) begin select x; end",
+			diff:   "drop procedure p1",
+			create: "create procedure p1(in x bigint) begin select x; end",
+		},
+		{
+			name:   "function characteristics",
+			from:   "create function f1(x int) returns int return x + 1",
+			to:     "create function f1(x int) returns int deterministic return x + 1",
+			diff:   "drop function f1",
+			create: "create function f1(x int) returns int deterministic return x + 1",
+		},
+		{
+			name: "identical event, default completion and status",
+			from: "create event e1 on schedule every 1 hour on completion not preserve enable do delete from t",
+			to:   "create event if not exists e1 on schedule every 1 hour do delete from t",
+		},
+		{
+			name:   "event schedule",
+			from:   "create event e1 on schedule every 1 hour do delete from t",
+			to:     "create event e1 on schedule every 1 day starts '2022-01-01 00:00:00' do delete from t",
+			diff:   "drop event e1",
+			create: "create event e1 on schedule every 1 day starts '2022-01-01 00:00:00' do delete from t",
+		},
+		{
+			name:    "different types",
+			from:    "create procedure p1() select 1",
+			to:      "create function p1() returns int return 1",
+			isError: true,
+		},
+	}
+	hints := &DiffHints{}
+	for _, ts := range tt {
+		t.Run(ts.name, func(t *testing.T) {
+			from := newTestProgramEntity(t, ts.from)
+			to := newTestProgramEntity(t, ts.to)
+			diff, err := from.Diff(to, hints)
+			switch {
+			case ts.isError:
+				assert.ErrorIs(t, err, ErrEntityTypeMismatch)
+			case ts.diff == "":
+				assert.NoError(t, err)
+				assert.True(t, diff.IsEmpty())
+			default:
+				assert.NoError(t, err)
+				require.NotNil(t, diff)
+				require.False(t, diff.IsEmpty())
+				assert.Equal(t, ts.diff, diff.StatementString())
+
+				// the stored program is dropped, then created anew
+				subsequent := diff.SubsequentDiff()
+				require.NotNil(t, subsequent)
+				assert.Equal(t, ts.create, subsequent.StatementString())
+				assert.Nil(t, subsequent.SubsequentDiff())
+				for _, d := range AllSubsequent(diff) {
+					_, err := sqlparser.ParseStrictDDL(d.CanonicalStatementString())
+					assert.NoError(t, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemadiff
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// defaultRoutineCharacteristics are the characteristics a routine has when none are specified
+var defaultRoutineCharacteristics = map[string]bool{
+	"language sql":         true,
+	"not deterministic":    true,
+	"contains sql":         true,
+	"sql security definer": true,
+}
+
+// normalizeRoutineCharacteristics removes the characteristics that are implied by default
+func normalizeRoutineCharacteristics(characteristics []*sqlparser.RoutineCharacteristic) []*sqlparser.RoutineCharacteristic {
+	var normalized []*sqlparser.RoutineCharacteristic
+	for _, characteristic := range characteristics {
+		if defaultRoutineCharacteristics[strings.ToLower(characteristic.Name)] {
+			continue
+		}
+		normalized = append(normalized, characteristic)
+	}
+	return normalized
+}
+
+// CreateProcedureEntity stands for a PROCEDURE construct. It contains the procedure's CREATE statement.
+type CreateProcedureEntity struct {
+	sqlparser.CreateProcedure
+}
+
+func NewCreateProcedureEntity(c *sqlparser.CreateProcedure) (*CreateProcedureEntity, error) {
+	entity := &CreateProcedureEntity{CreateProcedure: *c}
+	entity.normalize()
+	return entity, nil
+}
+
+func (c *CreateProcedureEntity) normalize() {
+	// IF NOT EXISTS has no meaning in a schema
+	c.CreateProcedure.IfNotExists = false
+	c.CreateProcedure.Characteristics = normalizeRoutineCharacteristics(c.CreateProcedure.Characteristics)
+}
+
+// Name implements Entity interface
+func (c *CreateProcedureEntity) Name() string {
+	return c.CreateProcedure.Name.Name.String()
+}
+
+// programType implements programEntity
+func (c *CreateProcedureEntity) programType() string {
+	return "procedure"
+}
+
+// Diff implements Entity interface function
+func (c *CreateProcedureEntity) Diff(other Entity, hints *DiffHints) (EntityDiff, error) {
+	otherCreateProcedure, ok := other.(*CreateProcedureEntity)
+	if !ok {
+		return nil, ErrEntityTypeMismatch
+	}
+	return c.ProcedureDiff(otherCreateProcedure, hints)
+}
+
+// ProcedureDiff compares this procedure statement with another procedure statement, and sees what it takes to
+// change this procedure to look like the other procedure.
+// It returns an AlterProgramEntityDiff, which drops and recreates the procedure, if changes are found, or nil if not.
+// The other procedure may be of different name; its name is ignored.
+func (c *CreateProcedureEntity) ProcedureDiff(other *CreateProcedureEntity, hints *DiffHints) (*AlterProgramEntityDiff, error) {
+	otherStmt := other.CreateProcedure
+	otherStmt.Name = c.CreateProcedure.Name
+
+	if sqlparser.CanonicalString(&c.CreateProcedure) == sqlparser.CanonicalString(&otherStmt) {
+		return nil, nil
+	}
+	return newAlterProgramEntityDiff(c, other), nil
+}
+
+// Create implements Entity interface
+func (c *CreateProcedureEntity) Create() EntityDiff {
+	return &CreateProgramEntityDiff{to: c, create: &c.CreateProcedure}
+}
+
+// Drop implements Entity interface
+func (c *CreateProcedureEntity) Drop() EntityDiff {
+	dropProcedure := &sqlparser.DropProcedure{
+		Name: c.CreateProcedure.Name,
+	}
+	return &DropProgramEntityDiff{from: c, drop: dropProcedure}
+}
+
+// CreateFunctionEntity stands for a FUNCTION construct. It contains the function's CREATE statement.
+type CreateFunctionEntity struct {
+	sqlparser.CreateFunction
+}
+
+func NewCreateFunctionEntity(c *sqlparser.CreateFunction) (*CreateFunctionEntity, error) {
+	entity := &CreateFunctionEntity{CreateFunction: *c}
+	entity.normalize()
+	return entity, nil
+}
+
+func (c *CreateFunctionEntity) normalize() {
+	// IF NOT EXISTS has no meaning in a schema
+	c.CreateFunction.IfNotExists = false
+	c.CreateFunction.Characteristics = normalizeRoutineCharacteristics(c.CreateFunction.Characteristics)
+}
+
+// Name implements Entity interface
+func (c *CreateFunctionEntity) Name() string {
+	return c.CreateFunction.Name.Name.String()
+}
+
+// programType implements programEntity
+func (c *CreateFunctionEntity) programType() string {
+	return "function"
+}
+
+// Diff implements Entity interface function
+func (c *CreateFunctionEntity) Diff(other Entity, hints *DiffHints) (EntityDiff, error) {
+	otherCreateFunction, ok := other.(*CreateFunctionEntity)
+	if !ok {
+		return nil, ErrEntityTypeMismatch
+	}
+	return c.FunctionDiff(otherCreateFunction, hints)
+}
+
+// FunctionDiff compares this function statement with another function statement, and sees what it takes to
+// change this function to look like the other function.
+// It returns an AlterProgramEntityDiff, which drops and recreates the function, if changes are found, or nil if not.
+// The other function may be of different name; its name is ignored.
+func (c *CreateFunctionEntity) FunctionDiff(other *CreateFunctionEntity, hints *DiffHints) (*AlterProgramEntityDiff, error) {
+	otherStmt := other.CreateFunction
+	otherStmt.Name = c.CreateFunction.Name
+
+	if sqlparser.CanonicalString(&c.CreateFunction) == sqlparser.CanonicalString(&otherStmt) {
+		return nil, nil
+	}
+	return newAlterProgramEntityDiff(c, other), nil
+}
+
+// Create implements Entity interface
+func (c *CreateFunctionEntity) Create() EntityDiff {
+	return &CreateProgramEntityDiff{to: c, create: &c.CreateFunction}
+}
+
+// Drop implements Entity interface
+func (c *CreateFunctionEntity) Drop() EntityDiff {
+	dropFunction := &sqlparser.DropFunction{
+		Name: c.CreateFunction.Name,
+	}
+	return &DropProgramEntityDiff{from: c, drop: dropFunction}
+}
@@ -27,11 +27,15 @@ import (
 	"vitess.io/vitess/go/vt/sqlparser"
 )
 
-// Schema represents a database schema, which may contain entities such as tables and views.
+// Schema represents a database schema, which may contain entities such as tables, views and stored programs.
 // Schema is not in itself an Entity, since it is more of a collection of entities.
 type Schema struct {
-	tables []*CreateTableEntity
-	views  []*CreateViewEntity
+	tables     []*CreateTableEntity
+	views      []*CreateViewEntity
+	functions  []*CreateFunctionEntity
+	procedures []*CreateProcedureEntity
+	triggers   []*CreateTriggerEntity
+	events     []*CreateEventEntity
 
 	named  map[string]Entity
 	sorted []Entity
@@ -40,10 +44,14 @@ type Schema struct {
 // newEmptySchema is used internally to initialize a Schema object
 func newEmptySchema() *Schema {
 	schema := &Schema{
-		tables: []*CreateTableEntity{},
-		views:  []*CreateViewEntity{},
-		named:  map[string]Entity{},
-		sorted: []Entity{},
+		tables:     []*CreateTableEntity{},
+		views:      []*CreateViewEntity{},
+		functions:  []*CreateFunctionEntity{},
+		procedures: []*CreateProcedureEntity{},
+		triggers:   []*CreateTriggerEntity{},
+		events:     []*CreateEventEntity{},
+		named:      map[string]Entity{},
+		sorted:     []Entity{},
 	}
 	return schema
 }
@@ -57,6 +65,14 @@ func NewSchemaFromEntities(entities []Entity) (*Schema, error) {
 			schema.tables = append(schema.tables, c)
 		case *CreateViewEntity:
 			schema.views = append(schema.views, c)
+		case *CreateFunctionEntity:
+			schema.functions = append(schema.functions, c)
+		case *CreateProcedureEntity:
+			schema.procedures = append(schema.procedures, c)
+		case *CreateTriggerEntity:
+			schema.triggers = append(schema.triggers, c)
+		case *CreateEventEntity:
+			schema.events = append(schema.events, c)
 		default:
 			return nil, &UnsupportedEntityError{Entity: c.Name(), Statement: c.Create().CanonicalStatementString()}
 		}
@@ -84,6 +100,30 @@ func NewSchemaFromStatements(statements []sqlparser.Statement) (*Schema, error)
 				return nil, err
 			}
 			entities = append(entities, v)
+		case *sqlparser.CreateFunction:
+			f, err := NewCreateFunctionEntity(stmt)
+			if err != nil {
+				return nil, err
+			}
+			entities = append(entities, f)
+		case *sqlparser.CreateProcedure:
+			p, err := NewCreateProcedureEntity(stmt)
+			if err != nil {
+				return nil, err
+			}
+			entities = append(entities, p)
+		case *sqlparser.CreateTrigger:
+			t, err := NewCreateTriggerEntity(stmt)
+			if err != nil {
+				return nil, err
+			}
+			entities = append(entities, t)
+		case *sqlparser.CreateEvent:
+			e, err := NewCreateEventEntity(stmt)
+			if err != nil {
+				return nil, err
+			}
+			entities = append(entities, e)
 		default:
 			return nil, &UnsupportedStatementError{Statement: sqlparser.CanonicalString(s)}
 		}
@@ -105,7 +145,7 @@ func NewSchemaFromQueries(queries []string) (*Schema, error) {
 }
 
 // NewSchemaFromSQL creates a valid and normalized schema based on a SQL blob that contains
-// CREATE statements for various objects (tables, views)
+// CREATE statements for various objects (tables, views, stored programs)
 func NewSchemaFromSQL(sql string) (*Schema, error) {
 	statements := []sqlparser.Statement{}
 	tokenizer := sqlparser.NewStringTokenizer(sql)
@@ -160,6 +200,15 @@ func (s *Schema) normalize() error {
 		}
 		s.named[name] = v
 	}
+	// Stored programs of each type have a namespace of their own
+	programNames := map[string]bool{}
+	for _, p := range s.programs() {
+		key := p.programType() + "." + p.Name()
+		if programNames[key] {
+			return &ApplyDuplicateEntityError{Entity: p.Name()}
+		}
+		programNames[key] = true
+	}
 
 	// Generally speaking, we want tables and views to be sorted alphabetically
 	sort.SliceStable(s.tables, func(i, j int) bool {
@@ -168,6 +217,28 @@ func (s *Schema) normalize() error {
 	sort.SliceStable(s.views, func(i, j int) bool {
 		return s.views[i].Name() < s.views[j].Name()
 	})
+	// and so do stored programs
+	sort.SliceStable(s.functions, func(i, j int) bool {
+		return s.functions[i].Name() < s.functions[j].Name()
+	})
+	sort.SliceStable(s.procedures, func(i, j int) bool {
+		return s.procedures[i].Name() < s.procedures[j].Name()
+	})
+	sort.SliceStable(s.triggers, func(i, j int) bool {
+		return s.triggers[i].Name() < s.triggers[j].Name()
+	})
+	sort.SliceStable(s.events, func(i, j int) bool {
+		return s.events[i].Name() < s.events[j].Name()
+	})
+
+	// Functions and procedures come first, since views may call functions. Their bodies
+	// are only resolved when they are called, so they do not depend on any other entity.
+	for _, f := range s.functions {
+		s.sorted = append(s.sorted, f)
+	}
+	for _, p := range s.procedures {
+		s.sorted = append(s.sorted, p)
+	}
 
 	// More importantly, we want tables and views to be sorted in applicable order.
 	// For example, if a view v reads from table t, then t must be defined before v.
@@ -226,12 +297,263 @@ func (s *Schema) normalize() error {
 			break
 		}
 	}
-	if len(s.sorted) != len(s.tables)+len(s.views) {
+	if len(s.sorted) != len(s.functions)+len(s.procedures)+len(s.tables)+len(s.views) {
 		// We have leftover views. This can happen if the schema definition is invalid:
 		// - a view depends on a nonexistent table
 		// - two views have a circular dependency
 		return ErrViewDependencyUnresolved
 	}
+
+	// Triggers follow the tables they are defined on, and a trigger that FOLLOWS or PRECEDES
+	// another trigger follows that trigger. Events come last.
+	for _, t := range s.triggers {
+		if err := s.validateTrigger(t); err != nil {
+			return err
+		}
+	}
+	handledTriggers := map[string]bool{}
+	for {
+		handledAnyTriggers := false
+		for _, t := range s.triggers {
+			if handledTriggers[t.Name()] {
+				continue
+			}
+			if t.CreateTrigger.Order == "" || handledTriggers[t.CreateTrigger.OtherTrigger.String()] {
+				s.sorted = append(s.sorted, t)
+				handledTriggers[t.Name()] = true
+				handledAnyTriggers = true
+			}
+		}
+		if !handledAnyTriggers {
+			break
+		}
+	}
+	if len(handledTriggers) != len(s.triggers) {
+		return ErrTriggerOrderUnresolved
+	}
+	for _, e := range s.events {
+		s.sorted = append(s.sorted, e)
+	}
+	return nil
+}
+
+// programs returns the stored programs of this schema: functions, procedures, triggers and events.
+func (s *Schema) programs() []programEntity {
+	var programs []programEntity
+	for _, f := range s.functions {
+		programs = append(programs, f)
+	}
+	for _, p := range s.procedures {
+		programs = append(programs, p)
+	}
+	for _, t := range s.triggers {
+		programs = append(programs, t)
+	}
+	for _, e := range s.events {
+		programs = append(programs, e)
+	}
+	return programs
+}
+
+// referenceErrors validates references across entities, which are not checked by the entities themselves:
+// the tables, columns and indexes referenced by foreign keys, and the columns read by views. These are not
+// validated when the schema is created, since a live schema may well have dangling references, such as foreign
+// keys to tables that have since been dropped.
+func (s *Schema) referenceErrors() (errs []error) {
+	for _, t := range s.tables {
+		if err := s.validateForeignKeys(t); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, v := range s.views {
+		if err := s.validateViewColumns(v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// validateTrigger validates that the given trigger is defined on a table, and that the trigger it FOLLOWS or
+// PRECEDES, if any, is defined for the same table, timing and event.
+func (s *Schema) validateTrigger(t *CreateTriggerEntity) error {
+	if s.Table(t.TableName()) == nil {
+		return &InvalidTableInTriggerError{Trigger: t.Name(), Table: t.TableName()}
+	}
+	if t.CreateTrigger.Order == "" {
+		return nil
+	}
+	otherName := t.CreateTrigger.OtherTrigger.String()
+	other := s.Trigger(otherName)
+	if other == nil ||
+		other.TableName() != t.TableName() ||
+		!strings.EqualFold(other.CreateTrigger.Timing, t.CreateTrigger.Timing) ||
+		!strings.EqualFold(other.CreateTrigger.Event, t.CreateTrigger.Event) {
+		return &InvalidTriggerOrderError{Trigger: t.Name(), Order: t.CreateTrigger.Order, OtherTrigger: otherName}
+	}
+	return nil
+}
+
+// viewCallingFunction returns a view that calls the given function, or nil if there is none.
+func (s *Schema) viewCallingFunction(name string) (*CreateViewEntity, error) {
+	for _, v := range s.views {
+		found := false
+		err := sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
+			if funcExpr, ok := node.(*sqlparser.FuncExpr); ok && funcExpr.Qualifier.IsEmpty() && funcExpr.Name.EqualString(name) {
+				found = true
+				return false, nil
+			}
+			return true, nil
+		}, v.CreateView.Select)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+// validateForeignKeys validates that the tables referenced by the foreign keys of the given table exist,
+// and have the referenced columns and an index that starts with them.
+func (s *Schema) validateForeignKeys(t *CreateTableEntity) error {
+	for _, cs := range t.CreateTable.TableSpec.Constraints {
+		fk, ok := cs.Details.(*sqlparser.ForeignKeyDefinition)
+		if !ok {
+			continue
+		}
+		referencedTable := fk.ReferenceDefinition.ReferencedTable
+		if !referencedTable.Qualifier.IsEmpty() {
+			// referencing a table in another schema
+			continue
+		}
+		referenced := s.Table(referencedTable.Name.String())
+		if referenced == nil {
+			return &InvalidReferencedTableInForeignKeyConstraintError{Table: t.Name(), Constraint: cs.Name.String(), ReferencedTable: referencedTable.Name.String()}
+		}
+		columns := map[string]*sqlparser.ColumnDefinition{}
+		for _, col := range referenced.CreateTable.TableSpec.Columns {
+			columns[col.Name.Lowered()] = col
+		}
+		for _, col := range fk.ReferenceDefinition.ReferencedColumns {
+			if _, ok := columns[col.Lowered()]; !ok {
+				return &InvalidReferencedColumnInForeignKeyConstraintError{Table: t.Name(), Constraint: cs.Name.String(), ReferencedTable: referenced.Name(), Column: col.String()}
+			}
+		}
+		if !hasIndexPrefix(referenced, fk.ReferenceDefinition.ReferencedColumns, columns) {
+			return &MissingForeignKeyReferencedIndexError{Table: t.Name(), Constraint: cs.Name.String(), ReferencedTable: referenced.Name()}
+		}
+	}
+	return nil
+}
+
+// hasIndexPrefix returns true if the table has an index whose first columns are the given columns, in order.
+func hasIndexPrefix(t *CreateTableEntity, prefix sqlparser.Columns, columns map[string]*sqlparser.ColumnDefinition) bool {
+	if len(prefix) == 1 {
+		// PRIMARY KEY, UNIQUE KEY or KEY defined on the column itself
+		var noKey sqlparser.ColumnKeyOption
+		if col := columns[prefix[0].Lowered()]; col.Type.Options != nil && col.Type.Options.KeyOpt != noKey {
+			return true
+		}
+	}
+	for _, key := range t.CreateTable.TableSpec.Indexes {
+		if len(key.Columns) < len(prefix) {
+			continue
+		}
+		matches := true
+		for i, col := range prefix {
+			if !key.Columns[i].Column.Equal(col) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// validateViewColumns validates that the columns read by the given view exist in the tables it reads from.
+// Only references that can be resolved with certainty are validated: a qualified column is validated when its
+// qualifier is a table, and an unqualified column is validated when the view only reads from tables.
+func (s *Schema) validateViewColumns(v *CreateViewEntity) error {
+	tableAliases := map[string]*CreateTableEntity{}
+	otherAliases := map[string]bool{}
+	selectAliases := map[string]bool{}
+	var tables []*CreateTableEntity
+	var columns []*sqlparser.ColName
+	onlyTables := true
+	err := sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
+		switch node := node.(type) {
+		case *sqlparser.AliasedTableExpr:
+			tableName, ok := node.Expr.(sqlparser.TableName)
+			if !ok {
+				// e.g. a derived table
+				onlyTables = false
+				otherAliases[node.As.String()] = true
+				return true, nil
+			}
+			t := s.Table(tableName.Name.String())
+			if t == nil || !tableName.Qualifier.IsEmpty() {
+				onlyTables = false
+				otherAliases[tableName.Name.String()] = true
+				otherAliases[node.As.String()] = true
+				return true, nil
+			}
+			tables = append(tables, t)
+			if node.As.IsEmpty() {
+				tableAliases[t.Name()] = t
+			} else {
+				tableAliases[node.As.String()] = t
+			}
+		case *sqlparser.AliasedExpr:
+			if !node.As.IsEmpty() {
+				selectAliases[node.As.Lowered()] = true
+			}
+		case *sqlparser.ColName:
+			columns = append(columns, node)
+		}
+		return true, nil
+	}, v.CreateView.Select)
+	if err != nil {
+		return err
+	}
+
+	hasColumn := func(t *CreateTableEntity, name sqlparser.IdentifierCI) bool {
+		for _, col := range t.CreateTable.TableSpec.Columns {
+			if col.Name.Equal(name) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, col := range columns {
+		if !col.Qualifier.IsEmpty() {
+			qualifier := col.Qualifier.Name.String()
+			t, ok := tableAliases[qualifier]
+			if !ok || otherAliases[qualifier] || !col.Qualifier.Qualifier.IsEmpty() {
+				continue
+			}
+			if !hasColumn(t, col.Name) {
+				return &InvalidColumnReferencedByViewError{View: v.Name(), Column: sqlparser.String(col)}
+			}
+			continue
+		}
+		if !onlyTables || len(tables) == 0 || selectAliases[col.Name.Lowered()] {
+			continue
+		}
+		found := false
+		for _, t := range tables {
+			if hasColumn(t, col.Name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &InvalidColumnReferencedByViewError{View: v.Name(), Column: col.Name.String()}
+		}
+	}
 	return nil
 }
 
@@ -289,12 +611,84 @@ func (s *Schema) ViewNames() []string {
 	return names
 }
 
+// Functions returns this schema's functions in good order (may be applied without error)
+func (s *Schema) Functions() []*CreateFunctionEntity {
+	var functions []*CreateFunctionEntity
+	for _, entity := range s.sorted {
+		if function, ok := entity.(*CreateFunctionEntity); ok {
+			functions = append(functions, function)
+		}
+	}
+	return functions
+}
+
+// Procedures returns this schema's procedures in good order (may be applied without error)
+func (s *Schema) Procedures() []*CreateProcedureEntity {
+	var procedures []*CreateProcedureEntity
+	for _, entity := range s.sorted {
+		if procedure, ok := entity.(*CreateProcedureEntity); ok {
+			procedures = append(procedures, procedure)
+		}
+	}
+	return procedures
+}
+
+// Triggers returns this schema's triggers in good order (may be applied without error)
+func (s *Schema) Triggers() []*CreateTriggerEntity {
+	var triggers []*CreateTriggerEntity
+	for _, entity := range s.sorted {
+		if trigger, ok := entity.(*CreateTriggerEntity); ok {
+			triggers = append(triggers, trigger)
+		}
+	}
+	return triggers
+}
+
+// Events returns this schema's events in good order (may be applied without error)
+func (s *Schema) Events() []*CreateEventEntity {
+	var events []*CreateEventEntity
+	for _, entity := range s.sorted {
+		if event, ok := entity.(*CreateEventEntity); ok {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// routineEntities returns this schema's functions and procedures in good order
+func (s *Schema) routineEntities() []programEntity {
+	var routines []programEntity
+	for _, entity := range s.sorted {
+		switch entity.(type) {
+		case *CreateFunctionEntity, *CreateProcedureEntity:
+			routines = append(routines, entity.(programEntity))
+		}
+	}
+	return routines
+}
+
+// triggerAndEventEntities returns this schema's triggers and events in good order
+func (s *Schema) triggerAndEventEntities() []programEntity {
+	var programs []programEntity
+	for _, entity := range s.sorted {
+		switch entity.(type) {
+		case *CreateTriggerEntity, *CreateEventEntity:
+			programs = append(programs, entity.(programEntity))
+		}
+	}
+	return programs
+}
+
 // Diff compares this schema with another schema, and sees what it takes to make this schema look
 // like the other. It returns a list of diffs.
 func (s *Schema) Diff(other *Schema, hints *DiffHints) (diffs []EntityDiff, err error) {
 	// dropped entities
 	var dropDiffs []EntityDiff
 	for _, e := range s.Entities() {
+		if _, ok := e.(programEntity); ok {
+			// stored programs are diffed below
+			continue
+		}
 		if _, ok := other.named[e.Name()]; !ok {
 			// other schema does not have the entity
 			dropDiffs = append(dropDiffs, e.Drop())
@@ -305,6 +699,9 @@ func (s *Schema) Diff(other *Schema, hints *DiffHints) (diffs []EntityDiff, err
 	var alterDiffs []EntityDiff
 	var createDiffs []EntityDiff
 	for _, e := range other.Entities() {
+		if _, ok := e.(programEntity); ok {
+			continue
+		}
 		if fromEntity, ok := s.named[e.Name()]; ok {
 			// entities exist by same name in both schemas. Let's diff them.
 			diff, err := fromEntity.Diff(e, hints)
@@ -335,14 +732,160 @@ func (s *Schema) Diff(other *Schema, hints *DiffHints) (diffs []EntityDiff, err
 		}
 	}
 	dropDiffs, createDiffs, renameDiffs := s.heuristicallyDetectTableRenames(dropDiffs, createDiffs, hints)
+
+	// Functions and procedures are created before, and dropped after, the views that may call them.
+	// Triggers and events are created after, and dropped before, the tables they act on.
+	dropRoutineDiffs, alterRoutineDiffs, createRoutineDiffs, err := diffPrograms(s.routineEntities(), other.routineEntities(), hints)
+	if err != nil {
+		return nil, err
+	}
+	dropTriggerDiffs, alterTriggerDiffs, createTriggerDiffs, err := diffPrograms(s.triggerAndEventEntities(), other.triggerAndEventEntities(), hints)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs = append(diffs, dropTriggerDiffs...)
 	diffs = append(diffs, dropDiffs...)
+	diffs = append(diffs, dropRoutineDiffs...)
+	diffs = append(diffs, alterRoutineDiffs...)
 	diffs = append(diffs, alterDiffs...)
+	diffs = append(diffs, alterTriggerDiffs...)
+	diffs = append(diffs, createRoutineDiffs...)
 	diffs = append(diffs, createDiffs...)
+	diffs = append(diffs, createTriggerDiffs...)
 	diffs = append(diffs, renameDiffs...)
 
 	return diffs, err
 }
 
+// diffPrograms compares two lists of stored programs, which are matched by type and name, and returns
+// the diffs that drop, alter and create stored programs.
+func diffPrograms(from []programEntity, to []programEntity, hints *DiffHints) (dropDiffs, alterDiffs, createDiffs []EntityDiff, err error) {
+	key := func(p programEntity) string {
+		return p.programType() + "." + p.Name()
+	}
+	fromPrograms := map[string]programEntity{}
+	for _, p := range from {
+		fromPrograms[key(p)] = p
+	}
+	toPrograms := map[string]programEntity{}
+	for _, p := range to {
+		toPrograms[key(p)] = p
+	}
+	// movedTrigger returns true when the given programs are triggers defined on different tables.
+	// A trigger is dropped along with its table, and so a trigger that moves to another table is
+	// dropped before, and created after, the tables are changed.
+	movedTrigger := func(fromProgram, toProgram programEntity) bool {
+		fromTrigger, ok := fromProgram.(*CreateTriggerEntity)
+		if !ok {
+			return false
+		}
+		toTrigger, ok := toProgram.(*CreateTriggerEntity)
+		return ok && fromTrigger.TableName() != toTrigger.TableName()
+	}
+
+	for _, p := range from {
+		toProgram, ok := toPrograms[key(p)]
+		if !ok || movedTrigger(p, toProgram) {
+			dropDiffs = append(dropDiffs, p.Drop())
+		}
+	}
+	for _, p := range to {
+		fromProgram, ok := fromPrograms[key(p)]
+		if !ok || movedTrigger(fromProgram, p) {
+			createDiffs = append(createDiffs, p.Create())
+			continue
+		}
+		diff, err := fromProgram.Diff(p, hints)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if diff != nil && !diff.IsEmpty() {
+			alterDiffs = append(alterDiffs, diff)
+		}
+	}
+	return dropDiffs, alterDiffs, createDiffs, nil
+}
+
+// OrderedDiff compares this schema with another schema, like Diff does, and returns the diffs in an order
+// that is safe to apply: each diff is validated by applying it onto the schema that results from applying
+// all of its preceding diffs. Dependencies between entities are thus respected. For example, a view is
+// dropped before the column it reads from is dropped, and a table is created before a view that reads from it.
+// An ALTER VIEW, or a change of a stored program, that cannot be ordered is split into a DROP and a CREATE.
+// An ImpossibleApplyDiffOrderError is returned when no valid order exists.
+func (s *Schema) OrderedDiff(other *Schema, hints *DiffHints) ([]EntityDiff, error) {
+	diffs, err := s.Diff(other, hints)
+	if err != nil {
+		return nil, err
+	}
+	// A diff must not break the references across entities. References that are already broken in either
+	// schema are tolerated, since the diffs can neither be blamed for them nor fix them.
+	tolerated := map[string]bool{}
+	for _, schema := range []*Schema{s, other} {
+		for _, err := range schema.referenceErrors() {
+			tolerated[err.Error()] = true
+		}
+	}
+	current := s
+	var ordered []EntityDiff
+	for len(diffs) > 0 {
+		applied := false
+		var errs []error
+		for i, diff := range diffs {
+			next, err := current.Apply([]EntityDiff{diff})
+			if err == nil {
+				for _, referenceErr := range next.referenceErrors() {
+					if !tolerated[referenceErr.Error()] {
+						err = referenceErr
+						break
+					}
+				}
+			}
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			current = next
+			ordered = append(ordered, diff)
+			diffs = append(diffs[0:i:i], diffs[i+1:]...)
+			applied = true
+			break
+		}
+		if applied {
+			continue
+		}
+		// No diff can be applied as is. We can still break an ALTER VIEW, or a change of a stored program,
+		// into a DROP and a CREATE, so that the entity does not stand in the way of other diffs.
+		split := false
+		for i, diff := range diffs {
+			var dropDiff, createDiff EntityDiff
+			switch diff := diff.(type) {
+			case *AlterViewEntityDiff:
+				dropDiff, createDiff = diff.from.Drop(), diff.to.Create()
+			case *AlterProgramEntityDiff:
+				dropDiff, createDiff = diff.from.Drop(), diff.to.Create()
+			default:
+				continue
+			}
+			split = true
+			remaining := append([]EntityDiff{dropDiff}, diffs[0:i]...)
+			remaining = append(remaining, diffs[i+1:]...)
+			diffs = append(remaining, createDiff)
+			break
+		}
+		if !split {
+			return nil, &ImpossibleApplyDiffOrderError{UnorderedDiffs: diffs, ConflictingErrors: errs}
+		}
+	}
+	// Sanity: the result of applying the ordered diffs is identical to the other schema
+	if remainingDiffs, err := current.Diff(other, hints); err != nil {
+		return nil, err
+	} else if len(remainingDiffs) > 0 {
+		return nil, ErrUnexpectedApplyResult
+	}
+	return ordered, nil
+}
+
 func (s *Schema) heuristicallyDetectTableRenames(
 	dropDiffs []EntityDiff,
 	createDiffs []EntityDiff,
@@ -435,6 +978,46 @@ func (s *Schema) View(name string) *CreateViewEntity {
 	return nil
 }
 
+// Function returns a function by name, or nil if nonexistent
+func (s *Schema) Function(name string) *CreateFunctionEntity {
+	for _, function := range s.functions {
+		if function.Name() == name {
+			return function
+		}
+	}
+	return nil
+}
+
+// Procedure returns a procedure by name, or nil if nonexistent
+func (s *Schema) Procedure(name string) *CreateProcedureEntity {
+	for _, procedure := range s.procedures {
+		if procedure.Name() == name {
+			return procedure
+		}
+	}
+	return nil
+}
+
+// Trigger returns a trigger by name, or nil if nonexistent
+func (s *Schema) Trigger(name string) *CreateTriggerEntity {
+	for _, trigger := range s.triggers {
+		if trigger.Name() == name {
+			return trigger
+		}
+	}
+	return nil
+}
+
+// Event returns an event by name, or nil if nonexistent
+func (s *Schema) Event(name string) *CreateEventEntity {
+	for _, event := range s.events {
+		if event.Name() == name {
+			return event
+		}
+	}
+	return nil
+}
+
 // ToStatements returns an ordered list of statements which can be applied to create the schema
 func (s *Schema) ToStatements() []sqlparser.Statement {
 	stmts := []sqlparser.Statement{}
@@ -464,7 +1047,7 @@ func (s *Schema) ToSQL() string {
 }
 
 // apply attempts to apply given list of diffs to this object.
-// These diffs are CREATE/DROP/ALTER TABLE/VIEW.
+// These diffs are CREATE/DROP/ALTER TABLE/VIEW, and CREATE/DROP of stored programs.
 func (s *Schema) apply(diffs []EntityDiff) error {
 	for _, diff := range diffs {
 		switch diff := diff.(type) {
@@ -498,6 +1081,14 @@ func (s *Schema) apply(diffs []EntityDiff) error {
 			if !found {
 				return &ApplyTableNotFoundError{Table: diff.from.Table.Name.String()}
 			}
+			// Dropping a table drops its triggers
+			var triggers []*CreateTriggerEntity
+			for _, t := range s.triggers {
+				if t.TableName() != diff.from.Table.Name.String() {
+					triggers = append(triggers, t)
+				}
+			}
+			s.triggers = triggers
 		case *DropViewEntityDiff:
 			// We expect the view to exist
 			found := false
@@ -571,6 +1162,42 @@ func (s *Schema) apply(diffs []EntityDiff) error {
 			if !found {
 				return &ApplyTableNotFoundError{Table: diff.from.Table.Name.String()}
 			}
+			// Triggers move along with their table. Entities may be shared with other schemas, and so
+			// the triggers are copied rather than modified.
+			for i, t := range s.triggers {
+				if t.TableName() == diff.from.Table.Name.String() {
+					moved := *t
+					moved.CreateTrigger.Table.Name = diff.to.Table.Name
+					s.triggers[i] = &moved
+				}
+			}
+		case *CreateProgramEntityDiff:
+			// We expect the stored program to not exist
+			if err := s.addProgram(diff.to); err != nil {
+				return err
+			}
+		case *DropProgramEntityDiff:
+			// We expect the stored program to exist, and, if it is a function, to not be called by a view
+			if function, ok := diff.from.(*CreateFunctionEntity); ok {
+				view, err := s.viewCallingFunction(function.Name())
+				if err != nil {
+					return err
+				}
+				if view != nil {
+					return &ApplyFunctionReferencedByViewError{Function: function.Name(), View: view.Name()}
+				}
+			}
+			if err := s.removeProgram(diff.from); err != nil {
+				return err
+			}
+		case *AlterProgramEntityDiff:
+			// The stored program is replaced. We expect it to exist.
+			if err := s.removeProgram(diff.from); err != nil {
+				return err
+			}
+			if err := s.addProgram(diff.to); err != nil {
+				return err
+			}
 		default:
 			return &UnsupportedApplyOperationError{Statement: diff.CanonicalStatementString()}
 		}
@@ -582,22 +1209,262 @@ func (s *Schema) apply(diffs []EntityDiff) error {
 }
 
 // Apply attempts to apply given list of diffs to the schema described by this object.
-// These diffs are CREATE/DROP/ALTER TABLE/VIEW.
+// These diffs are CREATE/DROP/ALTER TABLE/VIEW, and CREATE/DROP of stored programs.
 // The operation does not modify this object. Instead, if successful, a new (modified) Schema is returned.
 func (s *Schema) Apply(diffs []EntityDiff) (*Schema, error) {
-	// we export to queries, then import back.
-	// The reason we don't just clone this object's fields, or even export/import to Statements,
-	// is that we want this schema to be immutable an unaffected by the apply() on the duplicate.
-	// statements/slices/maps will have shared pointers and changes will propagate back to this schema.
-	dup, err := NewSchemaFromQueries(s.ToQueries())
-	if err != nil {
+	dup := s.clone()
+	if err := dup.apply(diffs); err != nil {
 		return nil, err
 	}
+	return dup, nil
+}
+
+// clone returns a copy of this schema, which shares its entities with this schema. This is safe since
+// entities are never modified: apply() replaces an entity with a new one, so that changes to the copy do not
+// propagate back to this schema.
+func (s *Schema) clone() *Schema {
+	dup := &Schema{
+		tables:     append([]*CreateTableEntity{}, s.tables...),
+		views:      append([]*CreateViewEntity{}, s.views...),
+		functions:  append([]*CreateFunctionEntity{}, s.functions...),
+		procedures: append([]*CreateProcedureEntity{}, s.procedures...),
+		triggers:   append([]*CreateTriggerEntity{}, s.triggers...),
+		events:     append([]*CreateEventEntity{}, s.events...),
+		named:      make(map[string]Entity, len(s.named)),
+		sorted:     append([]Entity{}, s.sorted...),
+	}
 	for k, v := range s.named {
 		dup.named[k] = v
 	}
-	if err := dup.apply(diffs); err != nil {
-		return nil, err
+	return dup
+}
+
+// StatementDiffs returns the diffs that the given DDL statement applies onto this schema, so that they can be
+// analyzed or applied. Supported statements are CREATE/ALTER/DROP/RENAME TABLE, CREATE/ALTER/DROP VIEW, and
+// CREATE/DROP of stored programs. A CREATE ... IF NOT EXISTS of an existing entity, or a DROP ... IF EXISTS
+// of a nonexistent entity, applies no diff.
+func (s *Schema) StatementDiffs(statement sqlparser.Statement) (diffs []EntityDiff, err error) {
+	createProgramDiff := func(p programEntity, err error) ([]EntityDiff, error) {
+		if err != nil {
+			return nil, err
+		}
+		return []EntityDiff{p.Create()}, nil
 	}
-	return dup, nil
+	missingProgram := func(programType string, name sqlparser.TableName, ifExists bool) ([]EntityDiff, error) {
+		if ifExists {
+			return nil, nil
+		}
+		return nil, &ApplyProgramNotFoundError{Type: programType, Name: name.Name.String()}
+	}
+
+	switch stmt := statement.(type) {
+	case *sqlparser.CreateTable:
+		name := stmt.Table.Name.String()
+		if s.Entity(name) != nil {
+			if stmt.IfNotExists {
+				return nil, nil
+			}
+			return nil, &ApplyDuplicateEntityError{Entity: name}
+		}
+		t, err := NewCreateTableEntity(stmt)
+		if err != nil {
+			return nil, err
+		}
+		return []EntityDiff{t.Create()}, nil
+	case *sqlparser.AlterTable:
+		t := s.Table(stmt.Table.Name.String())
+		if t == nil {
+			return nil, &ApplyTableNotFoundError{Table: stmt.Table.Name.String()}
+		}
+		diff := &AlterTableEntityDiff{from: t, alterTable: stmt}
+		to, err := t.Apply(diff)
+		if err != nil {
+			return nil, err
+		}
+		toCreateTableEntity, ok := to.(*CreateTableEntity)
+		if !ok {
+			return nil, ErrEntityTypeMismatch
+		}
+		diff.to = toCreateTableEntity
+		return []EntityDiff{diff}, nil
+	case *sqlparser.DropTable:
+		for _, name := range stmt.FromTables {
+			t := s.Table(name.Name.String())
+			if t == nil {
+				if stmt.IfExists {
+					continue
+				}
+				return nil, &ApplyTableNotFoundError{Table: name.Name.String()}
+			}
+			diffs = append(diffs, t.Drop())
+		}
+		return diffs, nil
+	case *sqlparser.RenameTable:
+		// Renames are applied in order, e.g. to swap two tables
+		current := s
+		for _, pair := range stmt.TablePairs {
+			from := current.Table(pair.FromTable.Name.String())
+			if from == nil {
+				return nil, &ApplyTableNotFoundError{Table: pair.FromTable.Name.String()}
+			}
+			if current.Entity(pair.ToTable.Name.String()) != nil {
+				return nil, &ApplyDuplicateEntityError{Entity: pair.ToTable.Name.String()}
+			}
+			to := &CreateTableEntity{CreateTable: *sqlparser.CloneRefOfCreateTable(&from.CreateTable)}
+			to.CreateTable.Table = pair.ToTable
+			diff := &RenameTableEntityDiff{
+				from:        from,
+				to:          to,
+				renameTable: &sqlparser.RenameTable{TablePairs: []*sqlparser.RenameTablePair{pair}},
+			}
+			diffs = append(diffs, diff)
+			if current, err = current.Apply([]EntityDiff{diff}); err != nil {
+				return nil, err
+			}
+		}
+		return diffs, nil
+	case *sqlparser.CreateView:
+		v, err := NewCreateViewEntity(stmt)
+		if err != nil {
+			return nil, err
+		}
+		existing := s.Entity(v.Name())
+		if existing == nil {
+			return []EntityDiff{v.Create()}, nil
+		}
+		existingView, ok := existing.(*CreateViewEntity)
+		if !ok || !stmt.IsReplace {
+			return nil, &ApplyDuplicateEntityError{Entity: v.Name()}
+		}
+		diff, err := existingView.ViewDiff(v, &DiffHints{})
+		if err != nil || diff == nil {
+			return nil, err
+		}
+		return []EntityDiff{diff}, nil
+	case *sqlparser.AlterView:
+		v := s.View(stmt.ViewName.Name.String())
+		if v == nil {
+			return nil, &ApplyViewNotFoundError{View: stmt.ViewName.Name.String()}
+		}
+		to, err := NewCreateViewEntity(&sqlparser.CreateView{
+			ViewName:    stmt.ViewName,
+			Algorithm:   stmt.Algorithm,
+			Definer:     stmt.Definer,
+			Security:    stmt.Security,
+			Columns:     stmt.Columns,
+			Select:      stmt.Select,
+			CheckOption: stmt.CheckOption,
+			Comments:    stmt.Comments,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []EntityDiff{&AlterViewEntityDiff{from: v, to: to, alterView: stmt}}, nil
+	case *sqlparser.DropView:
+		for _, name := range stmt.FromTables {
+			v := s.View(name.Name.String())
+			if v == nil {
+				if stmt.IfExists {
+					continue
+				}
+				return nil, &ApplyViewNotFoundError{View: name.Name.String()}
+			}
+			diffs = append(diffs, v.Drop())
+		}
+		return diffs, nil
+	case *sqlparser.CreateFunction:
+		return createProgramDiff(NewCreateFunctionEntity(stmt))
+	case *sqlparser.CreateProcedure:
+		return createProgramDiff(NewCreateProcedureEntity(stmt))
+	case *sqlparser.CreateTrigger:
+		return createProgramDiff(NewCreateTriggerEntity(stmt))
+	case *sqlparser.CreateEvent:
+		return createProgramDiff(NewCreateEventEntity(stmt))
+	case *sqlparser.DropFunction:
+		if f := s.Function(stmt.Name.Name.String()); f != nil {
+			return []EntityDiff{f.Drop()}, nil
+		}
+		return missingProgram("function", stmt.Name, stmt.IfExists)
+	case *sqlparser.DropProcedure:
+		if p := s.Procedure(stmt.Name.Name.String()); p != nil {
+			return []EntityDiff{p.Drop()}, nil
+		}
+		return missingProgram("procedure", stmt.Name, stmt.IfExists)
+	case *sqlparser.DropTrigger:
+		if t := s.Trigger(stmt.Name.Name.String()); t != nil {
+			return []EntityDiff{t.Drop()}, nil
+		}
+		return missingProgram("trigger", stmt.Name, stmt.IfExists)
+	case *sqlparser.DropEvent:
+		if e := s.Event(stmt.Name.Name.String()); e != nil {
+			return []EntityDiff{e.Drop()}, nil
+		}
+		return missingProgram("event", stmt.Name, stmt.IfExists)
+	}
+	return nil, &UnsupportedStatementError{Statement: sqlparser.CanonicalString(statement)}
+}
+
+// addProgram adds the given stored program to this schema. We expect the stored program to not exist.
+func (s *Schema) addProgram(p programEntity) error {
+	for _, existing := range s.programs() {
+		if existing.programType() == p.programType() && existing.Name() == p.Name() {
+			return &ApplyDuplicateEntityError{Entity: p.Name()}
+		}
+	}
+	switch p := p.(type) {
+	case *CreateFunctionEntity:
+		s.functions = append(s.functions, p)
+	case *CreateProcedureEntity:
+		s.procedures = append(s.procedures, p)
+	case *CreateTriggerEntity:
+		s.triggers = append(s.triggers, p)
+	case *CreateEventEntity:
+		s.events = append(s.events, p)
+	default:
+		return ErrEntityTypeMismatch
+	}
+	return nil
+}
+
+// removeProgram removes the given stored program from this schema. We expect the stored program to exist.
+func (s *Schema) removeProgram(p programEntity) error {
+	found := false
+	switch p := p.(type) {
+	case *CreateFunctionEntity:
+		for i, f := range s.functions {
+			if f.Name() == p.Name() {
+				s.functions = append(s.functions[0:i], s.functions[i+1:]...)
+				found = true
+				break
+			}
+		}
+	case *CreateProcedureEntity:
+		for i, proc := range s.procedures {
+			if proc.Name() == p.Name() {
+				s.procedures = append(s.procedures[0:i], s.procedures[i+1:]...)
+				found = true
+				break
+			}
+		}
+	case *CreateTriggerEntity:
+		for i, t := range s.triggers {
+			if t.Name() == p.Name() {
+				s.triggers = append(s.triggers[0:i], s.triggers[i+1:]...)
+				found = true
+				break
+			}
+		}
+	case *CreateEventEntity:
+		for i, e := range s.events {
+			if e.Name() == p.Name() {
+				s.events = append(s.events[0:i], s.events[i+1:]...)
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return &ApplyProgramNotFoundError{Type: p.programType(), Name: p.Name()}
+	}
+	return nil
 }
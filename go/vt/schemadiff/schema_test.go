@@ -21,6 +21,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/sqlparser"
 )
 
 var createQueries = []string{
@@ -142,3 +145,145 @@ func TestToSQL(t *testing.T) {
 	sql := schema.ToSQL()
 	assert.Equal(t, toSQL, sql)
 }
+
+func TestNewSchemaWithStoredPrograms(t *testing.T) {
+	queries := []string{
+		"create event e1 on schedule every 1 hour on completion not preserve enable do delete from t1",
+		"create trigger tr2 before insert on t1 for each row precedes tr1 set new.i = 2",
+		"create trigger tr1 before insert on t1 for each row set new.i = 1",
+		"create view v1 as select f1(id) from t1",
+		"create table t1 (id int primary key, i int)",
+		"create procedure p1(in x int) language sql begin select x; end",
+		"create function f1(x int) returns int deterministic return x + 1",
+	}
+	schema, err := NewSchemaFromQueries(queries)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"f1", "p1", "t1", "v1", "tr1", "tr2", "e1"}, schema.EntityNames())
+	assert.Equal(t, []string{"t1"}, schema.TableNames())
+	assert.Equal(t, []string{"v1"}, schema.ViewNames())
+	require.Len(t, schema.Functions(), 1)
+	require.Len(t, schema.Procedures(), 1)
+	require.Len(t, schema.Triggers(), 2)
+	require.Len(t, schema.Events(), 1)
+	assert.NotNil(t, schema.Trigger("tr2"))
+	assert.Nil(t, schema.Trigger("t1"))
+
+	expectSQL := "CREATE FUNCTION `f1`(`x` int) RETURNS int DETERMINISTIC return x + 1;\n" +
+		"CREATE PROCEDURE `p1`(IN `x` int) begin select x; end;\n" +
+		"CREATE TABLE `t1` (\n\t`id` int PRIMARY KEY,\n\t`i` int\n);\n" +
+		"CREATE VIEW `v1` AS SELECT f1(`id`) FROM `t1`;\n" +
+		"CREATE TRIGGER `tr1` BEFORE INSERT ON `t1` FOR EACH ROW set new.i = 1;\n" +
+		"CREATE TRIGGER `tr2` BEFORE INSERT ON `t1` FOR EACH ROW PRECEDES `tr1` set new.i = 2;\n" +
+		"CREATE EVENT `e1` ON SCHEDULE EVERY 1 HOUR DO delete from t1;\n"
+	assert.Equal(t, expectSQL, schema.ToSQL())
+
+	// The schema can be read back from its own SQL
+	dup, err := NewSchemaFromSQL(schema.ToSQL())
+	require.NoError(t, err)
+	assert.Equal(t, schema.EntityNames(), dup.EntityNames())
+}
+
+func TestInvalidSchema(t *testing.T) {
+	tt := []struct {
+		schema    string
+		expectErr error
+	}{
+		{
+			schema: "create table t11 (id int primary key, i int, key ix (i), constraint f11 foreign key (i) references t12 (id) on delete restrict); create table t12 (id int primary key)",
+		},
+		{
+			schema: "create table t11 (id int primary key, i int, key ix (i), constraint f11 foreign key (i) references t11 (id) on delete restrict)",
+		},
+		{
+			schema:    "create table t11 (id int primary key, i int, key ix (i), constraint f11 foreign key (i) references t12 (id) on delete restrict)",
+			expectErr: &InvalidReferencedTableInForeignKeyConstraintError{Table: "t11", Constraint: "f11", ReferencedTable: "t12"},
+		},
+		{
+			schema:    "create table t11 (id int primary key, i int, key ix (i), constraint f11 foreign key (i) references t12 (x) on delete restrict); create table t12 (id int primary key)",
+			expectErr: &InvalidReferencedColumnInForeignKeyConstraintError{Table: "t11", Constraint: "f11", ReferencedTable: "t12", Column: "x"},
+		},
+		{
+			schema:    "create table t11 (id int primary key, i int, key ix (i), constraint f11 foreign key (i) references t12 (x) on delete restrict); create table t12 (id int primary key, x int)",
+			expectErr: &MissingForeignKeyReferencedIndexError{Table: "t11", Constraint: "f11", ReferencedTable: "t12"},
+		},
+		{
+			schema: "create table t11 (id int primary key, i int, key ix (i), constraint f11 foreign key (i) references t12 (x) on delete restrict); create table t12 (id int primary key, x int, y int, key xy (x, y))",
+		},
+		{
+			schema: "create table t1 (id int primary key, i int); create view v1 as select id, i as x from t1 order by x",
+		},
+		{
+			schema: "create table t1 (id int primary key, i int); create view v1 as select t.id from t1 as t, (select 1 as z) as d where d.z = t.i",
+		},
+		{
+			schema:    "create table t1 (id int primary key, i int); create view v1 as select id, j from t1",
+			expectErr: &InvalidColumnReferencedByViewError{View: "v1", Column: "j"},
+		},
+		{
+			schema:    "create table t1 (id int primary key, i int); create table t2 (id int primary key, j int); create view v1 as select t1.id from t1 join t2 on t1.id = t2.i",
+			expectErr: &InvalidColumnReferencedByViewError{View: "v1", Column: "t2.i"},
+		},
+		{
+			schema: "create table t1 (id int primary key, i int); create trigger tr1 before insert on t1 for each row set new.i = 1; create trigger tr2 before insert on t1 for each row follows tr1 set new.i = 2",
+		},
+		{
+			schema:    "create table t1 (id int primary key, i int); create trigger tr1 before insert on t2 for each row set new.i = 1",
+			expectErr: &InvalidTableInTriggerError{Trigger: "tr1", Table: "t2"},
+		},
+		{
+			schema:    "create table t1 (id int primary key, i int); create view v1 as select id from t1; create trigger tr1 before insert on v1 for each row set new.i = 1",
+			expectErr: &InvalidTableInTriggerError{Trigger: "tr1", Table: "v1"},
+		},
+		{
+			schema:    "create table t1 (id int primary key, i int); create trigger tr1 before insert on t1 for each row set new.i = 1; create trigger tr1 after insert on t1 for each row set @a = 1",
+			expectErr: &ApplyDuplicateEntityError{Entity: "tr1"},
+		},
+		{
+			schema:    "create table t1 (id int primary key, i int); create trigger tr1 before insert on t1 for each row set new.i = 1; create trigger tr2 before update on t1 for each row follows tr1 set new.i = 2",
+			expectErr: &InvalidTriggerOrderError{Trigger: "tr2", Order: "follows", OtherTrigger: "tr1"},
+		},
+		{
+			schema:    "create table t1 (id int primary key, i int); create trigger tr1 before insert on t1 for each row precedes tr2 set new.i = 1; create trigger tr2 before insert on t1 for each row follows tr1 set new.i = 2",
+			expectErr: ErrTriggerOrderUnresolved,
+		},
+		{
+			// procedures and functions have namespaces of their own
+			schema: "create table p1 (id int primary key); create procedure p1() select 1; create function p1() returns int return 1",
+		},
+	}
+	for _, ts := range tt {
+		t.Run(ts.schema, func(t *testing.T) {
+			schema, err := NewSchemaFromSQL(ts.schema)
+			if err == nil {
+				// references across entities are only validated on demand
+				if errs := schema.referenceErrors(); len(errs) > 0 {
+					err = errs[0]
+				}
+			}
+			if ts.expectErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.EqualError(t, err, ts.expectErr.Error())
+			}
+		})
+	}
+}
+
+func TestApplyDoesNotModifySchema(t *testing.T) {
+	schema, err := NewSchemaFromSQL("create table t1 (id int primary key, i int); create trigger tr1 before insert on t1 for each row set new.i = 1")
+	require.NoError(t, err)
+	before := schema.ToSQL()
+
+	stmt, err := sqlparser.ParseStrictDDL("rename table t1 to t2")
+	require.NoError(t, err)
+	diffs, err := schema.StatementDiffs(stmt)
+	require.NoError(t, err)
+	applied, err := schema.Apply(diffs)
+	require.NoError(t, err)
+
+	assert.Equal(t, "t1", schema.Trigger("tr1").TableName())
+	assert.Equal(t, "t2", applied.Trigger("tr1").TableName())
+	assert.Equal(t, before, schema.ToSQL())
+}
@@ -1589,7 +1589,7 @@ func (c *CreateTableEntity) apply(diff *AlterTableEntityDiff) error {
 			// we expect the named key to be found
 			found := false
 			switch opt.Type {
-			case sqlparser.NormalKeyType, sqlparser.PrimaryKeyType:
+			case sqlparser.NormalKeyType:
 				for i, index := range c.TableSpec.Indexes {
 					if strings.EqualFold(index.Info.Name.String(), opt.Name.String()) {
 						found = true
@@ -1597,6 +1597,18 @@ func (c *CreateTableEntity) apply(diff *AlterTableEntityDiff) error {
 						break
 					}
 				}
+			case sqlparser.PrimaryKeyType:
+				// `DROP PRIMARY KEY` has no key name
+				for i, index := range c.TableSpec.Indexes {
+					if index.Info.Primary {
+						found = true
+						c.TableSpec.Indexes = append(c.TableSpec.Indexes[0:i], c.TableSpec.Indexes[i+1:]...)
+						break
+					}
+				}
+				if !found {
+					return &ApplyKeyNotFoundError{Table: c.Name(), Key: "PRIMARY"}
+				}
 			case sqlparser.ForeignKeyType, sqlparser.CheckKeyType:
 				for i, constraint := range c.TableSpec.Constraints {
 					if strings.EqualFold(constraint.Name.String(), opt.Name.String()) {
@@ -1710,6 +1722,39 @@ func (c *CreateTableEntity) apply(diff *AlterTableEntityDiff) error {
 			if !found {
 				return &ApplyColumnNotFoundError{Table: c.Name(), Column: opt.OldName.Name.String()}
 			}
+		case *sqlparser.ChangeColumn:
+			// we expect the column to exist
+			found := false
+			for i, col := range c.TableSpec.Columns {
+				if strings.EqualFold(col.Name.String(), opt.OldColumn.Name.String()) {
+					found = true
+					newName := opt.NewColDefinition.Name
+					if !strings.EqualFold(col.Name.String(), newName.String()) {
+						if columnExists[newName.Lowered()] {
+							return &ApplyDuplicateColumnError{Table: c.Name(), Column: newName.String()}
+						}
+						// like MySQL, keys follow the renamed column
+						for _, index := range c.TableSpec.Indexes {
+							for _, indexCol := range index.Columns {
+								if indexCol.Column.Equal(col.Name) {
+									indexCol.Column = newName
+								}
+							}
+						}
+						delete(columnExists, col.Name.Lowered())
+						columnExists[newName.Lowered()] = true
+					}
+					// redefine. see if we need to position it anywhere other than end of table
+					c.TableSpec.Columns[i] = opt.NewColDefinition
+					if err := reorderColumn(i, opt.First, opt.After); err != nil {
+						return err
+					}
+					break
+				}
+			}
+			if !found {
+				return &ApplyColumnNotFoundError{Table: c.Name(), Column: opt.OldColumn.Name.String()}
+			}
 		case *sqlparser.AlterColumn:
 			// we expect the column to exist
 			found := false
@@ -1804,8 +1849,8 @@ func (c *CreateTableEntity) Apply(diff EntityDiff) (Entity, error) {
 		FullyParsed: c.FullyParsed,
 	}
 	if c.TableSpec != nil {
-		d := *c.TableSpec
-		dupCreateTable.TableSpec = &d
+		// deep copy: applying an ALTER replaces columns and renames key columns in place
+		dupCreateTable.TableSpec = sqlparser.CloneRefOfTableSpec(c.TableSpec)
 	}
 	if c.OptLike != nil {
 		d := *c.OptLike
@@ -1157,6 +1157,60 @@ func TestValidate(t *testing.T) {
 			alter:     "alter table t drop column i",
 			expectErr: &InvalidColumnInKeyError{Table: "t", Column: "i", Key: "i_idx"},
 		},
+		{
+			name:  "drop primary key",
+			from:  "create table t (id int, i int, primary key (id), key i_idx(i))",
+			alter: "alter table t drop primary key",
+			to:    "create table t (id int, i int, key i_idx(i))",
+		},
+		{
+			name:  "drop primary key, add primary key",
+			from:  "create table t (id int, i int, primary key (id))",
+			alter: "alter table t drop primary key, add primary key (id, i)",
+			to:    "create table t (id int, i int, primary key (id, i))",
+		},
+		{
+			name:      "drop primary key, no primary key",
+			from:      "create table t (id int, i int, key i_idx(i))",
+			alter:     "alter table t drop primary key",
+			expectErr: &ApplyKeyNotFoundError{Table: "t", Key: "PRIMARY"},
+		},
+		{
+			name:  "change column, same name",
+			from:  "create table t (id int primary key, i int)",
+			alter: "alter table t change column i i bigint not null",
+			to:    "create table t (id int primary key, i bigint not null)",
+		},
+		{
+			name:  "change column, rename affects keys",
+			from:  "create table t (id int primary key, i int, key i_idx(i))",
+			alter: "alter table t change column i i2 bigint",
+			to:    "create table t (id int primary key, i2 bigint, key i_idx(i2))",
+		},
+		{
+			name:  "change column, column case",
+			from:  "create table t (id int primary key, i int)",
+			alter: "alter table t change column I i bigint",
+			to:    "create table t (id int primary key, i bigint)",
+		},
+		{
+			name:  "change column, position",
+			from:  "create table t (id int primary key, i int, j int)",
+			alter: "alter table t change column j j2 int after id",
+			to:    "create table t (id int primary key, j2 int, i int)",
+		},
+		{
+			name:      "change column, not found",
+			from:      "create table t (id int primary key, i int)",
+			alter:     "alter table t change column j j2 int",
+			expectErr: &ApplyColumnNotFoundError{Table: "t", Column: "j"},
+		},
+		{
+			name:      "change column, duplicate",
+			from:      "create table t (id int primary key, i int, j int)",
+			alter:     "alter table t change column i j int",
+			expectErr: &ApplyDuplicateColumnError{Table: "t", Column: "j"},
+		},
 		{
 			name:  "add multiple keys, multi columns, ok",
 			from:  "create table t (id int primary key, i1 int, i2 int, i3 int)",
@@ -1447,6 +1501,36 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestApplyDoesNotModifySource(t *testing.T) {
+	from := "create table t (id int, i int, j int, primary key (id), key i_idx(i))"
+	alters := []string{
+		"alter table t change column i i2 bigint",
+		"alter table t modify column j bigint first",
+		"alter table t drop primary key",
+		"alter table t drop column j",
+	}
+	stmt, err := sqlparser.ParseStrictDDL(from)
+	require.NoError(t, err)
+	fromCreateTable, ok := stmt.(*sqlparser.CreateTable)
+	require.True(t, ok)
+	fromEntity, err := NewCreateTableEntity(fromCreateTable)
+	require.NoError(t, err)
+	before := sqlparser.CanonicalString(fromEntity)
+
+	for _, alter := range alters {
+		t.Run(alter, func(t *testing.T) {
+			stmt, err := sqlparser.ParseStrictDDL(alter)
+			require.NoError(t, err)
+			alterTable, ok := stmt.(*sqlparser.AlterTable)
+			require.True(t, ok)
+
+			_, err = fromEntity.Apply(&AlterTableEntityDiff{from: fromEntity, alterTable: alterTable})
+			require.NoError(t, err)
+			assert.Equal(t, before, sqlparser.CanonicalString(fromEntity))
+		})
+	}
+}
+
 func TestNormalize(t *testing.T) {
 	tt := []struct {
 		name string
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemadiff
+
+import (
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// CreateTriggerEntity stands for a TRIGGER construct. It contains the trigger's CREATE statement.
+type CreateTriggerEntity struct {
+	sqlparser.CreateTrigger
+}
+
+func NewCreateTriggerEntity(c *sqlparser.CreateTrigger) (*CreateTriggerEntity, error) {
+	entity := &CreateTriggerEntity{CreateTrigger: *c}
+	entity.normalize()
+	return entity, nil
+}
+
+func (c *CreateTriggerEntity) normalize() {
+	// IF NOT EXISTS has no meaning in a schema
+	c.CreateTrigger.IfNotExists = false
+}
+
+// Name implements Entity interface
+func (c *CreateTriggerEntity) Name() string {
+	return c.CreateTrigger.Name.Name.String()
+}
+
+// TableName returns the name of the table the trigger is defined on
+func (c *CreateTriggerEntity) TableName() string {
+	return c.CreateTrigger.Table.Name.String()
+}
+
+// programType implements programEntity
+func (c *CreateTriggerEntity) programType() string {
+	return "trigger"
+}
+
+// Diff implements Entity interface function
+func (c *CreateTriggerEntity) Diff(other Entity, hints *DiffHints) (EntityDiff, error) {
+	otherCreateTrigger, ok := other.(*CreateTriggerEntity)
+	if !ok {
+		return nil, ErrEntityTypeMismatch
+	}
+	return c.TriggerDiff(otherCreateTrigger, hints)
+}
+
+// TriggerDiff compares this trigger statement with another trigger statement, and sees what it takes to
+// change this trigger to look like the other trigger.
+// It returns an AlterProgramEntityDiff, which drops and recreates the trigger, if changes are found, or nil if not.
+// The other trigger may be of different name; its name is ignored.
+// The FOLLOWS/PRECEDES clause is ignored as well: it only applies when the trigger is created, and MySQL
+// does not report it in the trigger's definition.
+func (c *CreateTriggerEntity) TriggerDiff(other *CreateTriggerEntity, hints *DiffHints) (*AlterProgramEntityDiff, error) {
+	stmt := c.CreateTrigger
+	stmt.Order = ""
+	stmt.OtherTrigger = sqlparser.NewIdentifierCS("")
+	otherStmt := other.CreateTrigger
+	otherStmt.Name = stmt.Name
+	otherStmt.Order = ""
+	otherStmt.OtherTrigger = sqlparser.NewIdentifierCS("")
+
+	if sqlparser.CanonicalString(&stmt) == sqlparser.CanonicalString(&otherStmt) {
+		return nil, nil
+	}
+	return newAlterProgramEntityDiff(c, other), nil
+}
+
+// Create implements Entity interface
+func (c *CreateTriggerEntity) Create() EntityDiff {
+	return &CreateProgramEntityDiff{to: c, create: &c.CreateTrigger}
+}
+
+// Drop implements Entity interface
+func (c *CreateTriggerEntity) Drop() EntityDiff {
+	dropTrigger := &sqlparser.DropTrigger{
+		Name: c.CreateTrigger.Name,
+	}
+	return &DropProgramEntityDiff{from: c, drop: dropTrigger}
+}
@@ -184,6 +184,9 @@ func (exec *TabletExecutor) parseDDLs(sqls []string) ([]sqlparser.DDLStatement,
 			revertStatements = append(revertStatements, stmt)
 		case *sqlparser.AlterMigration:
 			alterMigrationStatements = append(alterMigrationStatements, stmt)
+		case *sqlparser.CreateTrigger, *sqlparser.CreateProcedure, *sqlparser.CreateFunction, *sqlparser.CreateEvent,
+			*sqlparser.DropTrigger, *sqlparser.DropProcedure, *sqlparser.DropFunction, *sqlparser.DropEvent:
+			// stored programs are applied directly on all shards
 		default:
 			if len(exec.tablets) != 1 {
 				return nil, nil, nil, nil, fmt.Errorf("non-ddl statements can only be executed for single shard keyspaces: %s", sql)
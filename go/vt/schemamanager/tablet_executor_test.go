@@ -126,6 +126,13 @@ func TestTabletExecutorValidate(t *testing.T) {
 		t.Fatalf("schema changes are for DDLs")
 	}
 
+	// stored programs are applied on all shards
+	if err := executor.Validate(ctx, []string{
+		"CREATE TRIGGER test_trigger BEFORE INSERT ON test_table FOR EACH ROW SET NEW.id = 1",
+		"DROP PROCEDURE IF EXISTS test_procedure"}); err != nil {
+		t.Fatalf("executor.Validate should succeed, for stored programs, got: %v", err)
+	}
+
 	// validates valid ddls
 	if err := executor.Validate(ctx, sqls); err != nil {
 		t.Fatalf("executor.Validate should succeed, but got error: %v", err)
@@ -76,7 +76,9 @@ func ASTToStatementType(stmt Statement) StatementType {
 		return StmtSet
 	case *Show:
 		return StmtShow
-	case DDLStatement, DBDDLStatement, *AlterVschema:
+	case DDLStatement, DBDDLStatement, *AlterVschema,
+		*CreateTrigger, *CreateProcedure, *CreateFunction, *CreateEvent,
+		*DropTrigger, *DropProcedure, *DropFunction, *DropEvent:
 		return StmtDDL
 	case *RevertMigration:
 		return StmtRevert
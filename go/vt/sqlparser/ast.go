@@ -491,6 +491,16 @@ type (
 		Ratio  *Literal
 	}
 
+	// AlterDeadLetters represents an ALTER VITESS_DEAD_LETTERS ... RETRY
+	// statement, which requeues the dead-lettered messages with the given ids.
+	// If Into is set, Table is a dead letter table and the messages are moved
+	// back to the message table Into.
+	AlterDeadLetters struct {
+		Table TableName
+		IDs   ValTuple
+		Into  TableName
+	}
+
 	// AlterTable represents a ALTER TABLE statement.
 	AlterTable struct {
 		Table           TableName
@@ -559,6 +569,105 @@ type (
 		Address string
 	}
 
+	// CreateTrigger represents a CREATE TRIGGER statement.
+	// The body of the trigger is kept as is.
+	CreateTrigger struct {
+		Name         TableName
+		Definer      *Definer
+		IfNotExists  bool
+		Timing       string
+		Event        string
+		Table        TableName
+		Order        string
+		OtherTrigger IdentifierCS
+		Body         string
+	}
+
+	// CreateProcedure represents a CREATE PROCEDURE statement.
+	// The body of the procedure is kept as is.
+	CreateProcedure struct {
+		Name            TableName
+		Definer         *Definer
+		IfNotExists     bool
+		Params          []*RoutineParam
+		Characteristics []*RoutineCharacteristic
+		Body            string
+	}
+
+	// CreateFunction represents a CREATE FUNCTION statement for a stored function.
+	// The body of the function is kept as is.
+	CreateFunction struct {
+		Name            TableName
+		Definer         *Definer
+		IfNotExists     bool
+		Params          []*RoutineParam
+		Returns         ColumnType
+		Characteristics []*RoutineCharacteristic
+		Body            string
+	}
+
+	// RoutineParam represents a parameter of a stored procedure or function.
+	// Mode is only set for procedures, to one of "in", "out" or "inout".
+	RoutineParam struct {
+		Mode string
+		Name IdentifierCI
+		Type ColumnType
+	}
+
+	// RoutineCharacteristic represents a characteristic of a stored procedure or function,
+	// e.g. DETERMINISTIC or SQL SECURITY INVOKER. Comment is only set for the COMMENT characteristic.
+	RoutineCharacteristic struct {
+		Name    string
+		Comment *Literal
+	}
+
+	// CreateEvent represents a CREATE EVENT statement.
+	// The body of the event is kept as is.
+	CreateEvent struct {
+		Name         TableName
+		Definer      *Definer
+		IfNotExists  bool
+		Schedule     *EventSchedule
+		OnCompletion string
+		Status       string
+		Comment      *Literal
+		Body         string
+	}
+
+	// EventSchedule represents the schedule of an event:
+	// either AT timestamp, or EVERY interval [STARTS timestamp] [ENDS timestamp].
+	EventSchedule struct {
+		At     Expr
+		Every  Expr
+		Unit   string
+		Starts Expr
+		Ends   Expr
+	}
+
+	// DropTrigger represents a DROP TRIGGER statement.
+	DropTrigger struct {
+		Name     TableName
+		IfExists bool
+	}
+
+	// DropProcedure represents a DROP PROCEDURE statement.
+	DropProcedure struct {
+		Name     TableName
+		IfExists bool
+	}
+
+	// DropFunction represents a DROP FUNCTION statement.
+	DropFunction struct {
+		Name     TableName
+		IfExists bool
+	}
+
+	// DropEvent represents a DROP EVENT statement.
+	DropEvent struct {
+		Name     TableName
+		IfExists bool
+	}
+
 	// DDLAction is an enum for DDL.Action
 	DDLAction int8
 
@@ -718,11 +827,20 @@ func (*AlterDatabase) iStatement()     {}
 func (*CreateTable) iStatement()       {}
 func (*CreateView) iStatement()        {}
 func (*AlterView) iStatement()         {}
+func (*CreateTrigger) iStatement()     {}
+func (*CreateProcedure) iStatement()   {}
+func (*CreateFunction) iStatement()    {}
+func (*CreateEvent) iStatement()       {}
+func (*DropTrigger) iStatement()       {}
+func (*DropProcedure) iStatement()     {}
+func (*DropFunction) iStatement()      {}
+func (*DropEvent) iStatement()         {}
 func (*LockTables) iStatement()        {}
 func (*UnlockTables) iStatement()      {}
 func (*AlterTable) iStatement()        {}
 func (*AlterVschema) iStatement()      {}
 func (*AlterMigration) iStatement()    {}
+func (*AlterDeadLetters) iStatement()  {}
 func (*RevertMigration) iStatement()   {}
 func (*ShowMigrationLogs) iStatement() {}
 func (*ShowThrottledApps) iStatement() {}
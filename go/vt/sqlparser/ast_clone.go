@@ -45,6 +45,8 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfAlterColumn(in)
 	case *AlterDatabase:
 		return CloneRefOfAlterDatabase(in)
+	case *AlterDeadLetters:
+		return CloneRefOfAlterDeadLetters(in)
 	case *AlterIndex:
 		return CloneRefOfAlterIndex(in)
 	case *AlterMigration:
@@ -123,8 +125,16 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfCountStar(in)
 	case *CreateDatabase:
 		return CloneRefOfCreateDatabase(in)
+	case *CreateEvent:
+		return CloneRefOfCreateEvent(in)
+	case *CreateFunction:
+		return CloneRefOfCreateFunction(in)
+	case *CreateProcedure:
+		return CloneRefOfCreateProcedure(in)
 	case *CreateTable:
 		return CloneRefOfCreateTable(in)
+	case *CreateTrigger:
+		return CloneRefOfCreateTrigger(in)
 	case *CreateView:
 		return CloneRefOfCreateView(in)
 	case *CurTimeFuncExpr:
@@ -143,12 +153,22 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfDropColumn(in)
 	case *DropDatabase:
 		return CloneRefOfDropDatabase(in)
+	case *DropEvent:
+		return CloneRefOfDropEvent(in)
+	case *DropFunction:
+		return CloneRefOfDropFunction(in)
 	case *DropKey:
 		return CloneRefOfDropKey(in)
+	case *DropProcedure:
+		return CloneRefOfDropProcedure(in)
 	case *DropTable:
 		return CloneRefOfDropTable(in)
+	case *DropTrigger:
+		return CloneRefOfDropTrigger(in)
 	case *DropView:
 		return CloneRefOfDropView(in)
+	case *EventSchedule:
+		return CloneRefOfEventSchedule(in)
 	case *ExecuteStmt:
 		return CloneRefOfExecuteStmt(in)
 	case *ExistsExpr:
@@ -383,6 +403,10 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfRollback(in)
 	case RootNode:
 		return CloneRootNode(in)
+	case *RoutineCharacteristic:
+		return CloneRefOfRoutineCharacteristic(in)
+	case *RoutineParam:
+		return CloneRefOfRoutineParam(in)
 	case *SRollback:
 		return CloneRefOfSRollback(in)
 	case *Savepoint:
@@ -617,6 +641,18 @@ func CloneRefOfAlterDatabase(n *AlterDatabase) *AlterDatabase {
 	return &out
 }
 
+// CloneRefOfAlterDeadLetters creates a deep clone of the input.
+func CloneRefOfAlterDeadLetters(n *AlterDeadLetters) *AlterDeadLetters {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Table = CloneTableName(n.Table)
+	out.IDs = CloneValTuple(n.IDs)
+	out.Into = CloneTableName(n.Into)
+	return &out
+}
+
 // CloneRefOfAlterIndex creates a deep clone of the input.
 func CloneRefOfAlterIndex(n *AlterIndex) *AlterIndex {
 	if n == nil {
@@ -1018,6 +1054,46 @@ func CloneRefOfCreateDatabase(n *CreateDatabase) *CreateDatabase {
 	return &out
 }
 
+// CloneRefOfCreateEvent creates a deep clone of the input.
+func CloneRefOfCreateEvent(n *CreateEvent) *CreateEvent {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Name = CloneTableName(n.Name)
+	out.Definer = CloneRefOfDefiner(n.Definer)
+	out.Schedule = CloneRefOfEventSchedule(n.Schedule)
+	out.Comment = CloneRefOfLiteral(n.Comment)
+	return &out
+}
+
+// CloneRefOfCreateFunction creates a deep clone of the input.
+func CloneRefOfCreateFunction(n *CreateFunction) *CreateFunction {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Name = CloneTableName(n.Name)
+	out.Definer = CloneRefOfDefiner(n.Definer)
+	out.Params = CloneSliceOfRefOfRoutineParam(n.Params)
+	out.Returns = CloneColumnType(n.Returns)
+	out.Characteristics = CloneSliceOfRefOfRoutineCharacteristic(n.Characteristics)
+	return &out
+}
+
+// CloneRefOfCreateProcedure creates a deep clone of the input.
+func CloneRefOfCreateProcedure(n *CreateProcedure) *CreateProcedure {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Name = CloneTableName(n.Name)
+	out.Definer = CloneRefOfDefiner(n.Definer)
+	out.Params = CloneSliceOfRefOfRoutineParam(n.Params)
+	out.Characteristics = CloneSliceOfRefOfRoutineCharacteristic(n.Characteristics)
+	return &out
+}
+
 // CloneRefOfCreateTable creates a deep clone of the input.
 func CloneRefOfCreateTable(n *CreateTable) *CreateTable {
 	if n == nil {
@@ -1031,6 +1107,19 @@ func CloneRefOfCreateTable(n *CreateTable) *CreateTable {
 	return &out
 }
 
+// CloneRefOfCreateTrigger creates a deep clone of the input.
+func CloneRefOfCreateTrigger(n *CreateTrigger) *CreateTrigger {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Name = CloneTableName(n.Name)
+	out.Definer = CloneRefOfDefiner(n.Definer)
+	out.Table = CloneTableName(n.Table)
+	out.OtherTrigger = CloneIdentifierCS(n.OtherTrigger)
+	return &out
+}
+
 // CloneRefOfCreateView creates a deep clone of the input.
 func CloneRefOfCreateView(n *CreateView) *CreateView {
 	if n == nil {
@@ -1133,6 +1222,26 @@ func CloneRefOfDropDatabase(n *DropDatabase) *DropDatabase {
 	return &out
 }
 
+// CloneRefOfDropEvent creates a deep clone of the input.
+func CloneRefOfDropEvent(n *DropEvent) *DropEvent {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Name = CloneTableName(n.Name)
+	return &out
+}
+
+// CloneRefOfDropFunction creates a deep clone of the input.
+func CloneRefOfDropFunction(n *DropFunction) *DropFunction {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Name = CloneTableName(n.Name)
+	return &out
+}
+
 // CloneRefOfDropKey creates a deep clone of the input.
 func CloneRefOfDropKey(n *DropKey) *DropKey {
 	if n == nil {
@@ -1143,6 +1252,16 @@ func CloneRefOfDropKey(n *DropKey) *DropKey {
 	return &out
 }
 
+// CloneRefOfDropProcedure creates a deep clone of the input.
+func CloneRefOfDropProcedure(n *DropProcedure) *DropProcedure {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Name = CloneTableName(n.Name)
+	return &out
+}
+
 // CloneRefOfDropTable creates a deep clone of the input.
 func CloneRefOfDropTable(n *DropTable) *DropTable {
 	if n == nil {
@@ -1154,6 +1273,16 @@ func CloneRefOfDropTable(n *DropTable) *DropTable {
 	return &out
 }
 
+// CloneRefOfDropTrigger creates a deep clone of the input.
+func CloneRefOfDropTrigger(n *DropTrigger) *DropTrigger {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Name = CloneTableName(n.Name)
+	return &out
+}
+
 // CloneRefOfDropView creates a deep clone of the input.
 func CloneRefOfDropView(n *DropView) *DropView {
 	if n == nil {
@@ -1165,6 +1294,19 @@ func CloneRefOfDropView(n *DropView) *DropView {
 	return &out
 }
 
+// CloneRefOfEventSchedule creates a deep clone of the input.
+func CloneRefOfEventSchedule(n *EventSchedule) *EventSchedule {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.At = CloneExpr(n.At)
+	out.Every = CloneExpr(n.Every)
+	out.Starts = CloneExpr(n.Starts)
+	out.Ends = CloneExpr(n.Ends)
+	return &out
+}
+
 // CloneRefOfExecuteStmt creates a deep clone of the input.
 func CloneRefOfExecuteStmt(n *ExecuteStmt) *ExecuteStmt {
 	if n == nil {
@@ -2389,6 +2531,27 @@ func CloneRootNode(n RootNode) RootNode {
 	return *CloneRefOfRootNode(&n)
 }
 
+// CloneRefOfRoutineCharacteristic creates a deep clone of the input.
+func CloneRefOfRoutineCharacteristic(n *RoutineCharacteristic) *RoutineCharacteristic {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Comment = CloneRefOfLiteral(n.Comment)
+	return &out
+}
+
+// CloneRefOfRoutineParam creates a deep clone of the input.
+func CloneRefOfRoutineParam(n *RoutineParam) *RoutineParam {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Name = CloneIdentifierCI(n.Name)
+	out.Type = CloneColumnType(n.Type)
+	return &out
+}
+
 // CloneRefOfSRollback creates a deep clone of the input.
 func CloneRefOfSRollback(n *SRollback) *SRollback {
 	if n == nil {
@@ -3729,6 +3892,8 @@ func CloneStatement(in Statement) Statement {
 	switch in := in.(type) {
 	case *AlterDatabase:
 		return CloneRefOfAlterDatabase(in)
+	case *AlterDeadLetters:
+		return CloneRefOfAlterDeadLetters(in)
 	case *AlterMigration:
 		return CloneRefOfAlterMigration(in)
 	case *AlterTable:
@@ -3747,8 +3912,16 @@ func CloneStatement(in Statement) Statement {
 		return CloneRefOfCommit(in)
 	case *CreateDatabase:
 		return CloneRefOfCreateDatabase(in)
+	case *CreateEvent:
+		return CloneRefOfCreateEvent(in)
+	case *CreateFunction:
+		return CloneRefOfCreateFunction(in)
+	case *CreateProcedure:
+		return CloneRefOfCreateProcedure(in)
 	case *CreateTable:
 		return CloneRefOfCreateTable(in)
+	case *CreateTrigger:
+		return CloneRefOfCreateTrigger(in)
 	case *CreateView:
 		return CloneRefOfCreateView(in)
 	case *DeallocateStmt:
@@ -3757,8 +3930,16 @@ func CloneStatement(in Statement) Statement {
 		return CloneRefOfDelete(in)
 	case *DropDatabase:
 		return CloneRefOfDropDatabase(in)
+	case *DropEvent:
+		return CloneRefOfDropEvent(in)
+	case *DropFunction:
+		return CloneRefOfDropFunction(in)
+	case *DropProcedure:
+		return CloneRefOfDropProcedure(in)
 	case *DropTable:
 		return CloneRefOfDropTable(in)
+	case *DropTrigger:
+		return CloneRefOfDropTrigger(in)
 	case *DropView:
 		return CloneRefOfDropView(in)
 	case *ExecuteStmt:
@@ -3953,6 +4134,30 @@ func CloneSliceOfString(n []string) []string {
 	return res
 }
 
+// CloneSliceOfRefOfRoutineParam creates a deep clone of the input.
+func CloneSliceOfRefOfRoutineParam(n []*RoutineParam) []*RoutineParam {
+	if n == nil {
+		return nil
+	}
+	res := make([]*RoutineParam, 0, len(n))
+	for _, x := range n {
+		res = append(res, CloneRefOfRoutineParam(x))
+	}
+	return res
+}
+
+// CloneSliceOfRefOfRoutineCharacteristic creates a deep clone of the input.
+func CloneSliceOfRefOfRoutineCharacteristic(n []*RoutineCharacteristic) []*RoutineCharacteristic {
+	if n == nil {
+		return nil
+	}
+	res := make([]*RoutineCharacteristic, 0, len(n))
+	for _, x := range n {
+		res = append(res, CloneRefOfRoutineCharacteristic(x))
+	}
+	return res
+}
+
 // CloneSliceOfRefOfVariable creates a deep clone of the input.
 func CloneSliceOfRefOfVariable(n []*Variable) []*Variable {
 	if n == nil {
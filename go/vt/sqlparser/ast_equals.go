@@ -92,6 +92,12 @@ func EqualsSQLNode(inA, inB SQLNode) bool {
 			return false
 		}
 		return EqualsRefOfAlterDatabase(a, b)
+	case *AlterDeadLetters:
+		b, ok := inB.(*AlterDeadLetters)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfAlterDeadLetters(a, b)
 	case *AlterIndex:
 		b, ok := inB.(*AlterIndex)
 		if !ok {
@@ -326,12 +332,36 @@ func EqualsSQLNode(inA, inB SQLNode) bool {
 			return false
 		}
 		return EqualsRefOfCreateDatabase(a, b)
+	case *CreateEvent:
+		b, ok := inB.(*CreateEvent)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfCreateEvent(a, b)
+	case *CreateFunction:
+		b, ok := inB.(*CreateFunction)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfCreateFunction(a, b)
+	case *CreateProcedure:
+		b, ok := inB.(*CreateProcedure)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfCreateProcedure(a, b)
 	case *CreateTable:
 		b, ok := inB.(*CreateTable)
 		if !ok {
 			return false
 		}
 		return EqualsRefOfCreateTable(a, b)
+	case *CreateTrigger:
+		b, ok := inB.(*CreateTrigger)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfCreateTrigger(a, b)
 	case *CreateView:
 		b, ok := inB.(*CreateView)
 		if !ok {
@@ -386,24 +416,54 @@ func EqualsSQLNode(inA, inB SQLNode) bool {
 			return false
 		}
 		return EqualsRefOfDropDatabase(a, b)
+	case *DropEvent:
+		b, ok := inB.(*DropEvent)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfDropEvent(a, b)
+	case *DropFunction:
+		b, ok := inB.(*DropFunction)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfDropFunction(a, b)
 	case *DropKey:
 		b, ok := inB.(*DropKey)
 		if !ok {
 			return false
 		}
 		return EqualsRefOfDropKey(a, b)
+	case *DropProcedure:
+		b, ok := inB.(*DropProcedure)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfDropProcedure(a, b)
 	case *DropTable:
 		b, ok := inB.(*DropTable)
 		if !ok {
 			return false
 		}
 		return EqualsRefOfDropTable(a, b)
+	case *DropTrigger:
+		b, ok := inB.(*DropTrigger)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfDropTrigger(a, b)
 	case *DropView:
 		b, ok := inB.(*DropView)
 		if !ok {
 			return false
 		}
 		return EqualsRefOfDropView(a, b)
+	case *EventSchedule:
+		b, ok := inB.(*EventSchedule)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfEventSchedule(a, b)
 	case *ExecuteStmt:
 		b, ok := inB.(*ExecuteStmt)
 		if !ok {
@@ -1106,6 +1166,18 @@ func EqualsSQLNode(inA, inB SQLNode) bool {
 			return false
 		}
 		return EqualsRootNode(a, b)
+	case *RoutineCharacteristic:
+		b, ok := inB.(*RoutineCharacteristic)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfRoutineCharacteristic(a, b)
+	case *RoutineParam:
+		b, ok := inB.(*RoutineParam)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfRoutineParam(a, b)
 	case *SRollback:
 		b, ok := inB.(*SRollback)
 		if !ok {
@@ -1616,6 +1688,19 @@ func EqualsRefOfAlterDatabase(a, b *AlterDatabase) bool {
 		EqualsSliceOfDatabaseOption(a.AlterOptions, b.AlterOptions)
 }
 
+// EqualsRefOfAlterDeadLetters does deep equals between the two objects.
+func EqualsRefOfAlterDeadLetters(a, b *AlterDeadLetters) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return EqualsTableName(a.Table, b.Table) &&
+		EqualsValTuple(a.IDs, b.IDs) &&
+		EqualsTableName(a.Into, b.Into)
+}
+
 // EqualsRefOfAlterIndex does deep equals between the two objects.
 func EqualsRefOfAlterIndex(a, b *AlterIndex) bool {
 	if a == b {
@@ -2090,6 +2175,57 @@ func EqualsRefOfCreateDatabase(a, b *CreateDatabase) bool {
 		EqualsSliceOfDatabaseOption(a.CreateOptions, b.CreateOptions)
 }
 
+// EqualsRefOfCreateEvent does deep equals between the two objects.
+func EqualsRefOfCreateEvent(a, b *CreateEvent) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.IfNotExists == b.IfNotExists &&
+		a.OnCompletion == b.OnCompletion &&
+		a.Status == b.Status &&
+		a.Body == b.Body &&
+		EqualsTableName(a.Name, b.Name) &&
+		EqualsRefOfDefiner(a.Definer, b.Definer) &&
+		EqualsRefOfEventSchedule(a.Schedule, b.Schedule) &&
+		EqualsRefOfLiteral(a.Comment, b.Comment)
+}
+
+// EqualsRefOfCreateFunction does deep equals between the two objects.
+func EqualsRefOfCreateFunction(a, b *CreateFunction) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.IfNotExists == b.IfNotExists &&
+		a.Body == b.Body &&
+		EqualsTableName(a.Name, b.Name) &&
+		EqualsRefOfDefiner(a.Definer, b.Definer) &&
+		EqualsSliceOfRefOfRoutineParam(a.Params, b.Params) &&
+		EqualsColumnType(a.Returns, b.Returns) &&
+		EqualsSliceOfRefOfRoutineCharacteristic(a.Characteristics, b.Characteristics)
+}
+
+// EqualsRefOfCreateProcedure does deep equals between the two objects.
+func EqualsRefOfCreateProcedure(a, b *CreateProcedure) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.IfNotExists == b.IfNotExists &&
+		a.Body == b.Body &&
+		EqualsTableName(a.Name, b.Name) &&
+		EqualsRefOfDefiner(a.Definer, b.Definer) &&
+		EqualsSliceOfRefOfRoutineParam(a.Params, b.Params) &&
+		EqualsSliceOfRefOfRoutineCharacteristic(a.Characteristics, b.Characteristics)
+}
+
 // EqualsRefOfCreateTable does deep equals between the two objects.
 func EqualsRefOfCreateTable(a, b *CreateTable) bool {
 	if a == b {
@@ -2107,6 +2243,25 @@ func EqualsRefOfCreateTable(a, b *CreateTable) bool {
 		EqualsRefOfParsedComments(a.Comments, b.Comments)
 }
 
+// EqualsRefOfCreateTrigger does deep equals between the two objects.
+func EqualsRefOfCreateTrigger(a, b *CreateTrigger) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.IfNotExists == b.IfNotExists &&
+		a.Timing == b.Timing &&
+		a.Event == b.Event &&
+		a.Order == b.Order &&
+		a.Body == b.Body &&
+		EqualsTableName(a.Name, b.Name) &&
+		EqualsRefOfDefiner(a.Definer, b.Definer) &&
+		EqualsTableName(a.Table, b.Table) &&
+		EqualsIdentifierCS(a.OtherTrigger, b.OtherTrigger)
+}
+
 // EqualsRefOfCreateView does deep equals between the two objects.
 func EqualsRefOfCreateView(a, b *CreateView) bool {
 	if a == b {
@@ -2229,6 +2384,30 @@ func EqualsRefOfDropDatabase(a, b *DropDatabase) bool {
 		EqualsIdentifierCS(a.DBName, b.DBName)
 }
 
+// EqualsRefOfDropEvent does deep equals between the two objects.
+func EqualsRefOfDropEvent(a, b *DropEvent) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.IfExists == b.IfExists &&
+		EqualsTableName(a.Name, b.Name)
+}
+
+// EqualsRefOfDropFunction does deep equals between the two objects.
+func EqualsRefOfDropFunction(a, b *DropFunction) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.IfExists == b.IfExists &&
+		EqualsTableName(a.Name, b.Name)
+}
+
 // EqualsRefOfDropKey does deep equals between the two objects.
 func EqualsRefOfDropKey(a, b *DropKey) bool {
 	if a == b {
@@ -2241,6 +2420,18 @@ func EqualsRefOfDropKey(a, b *DropKey) bool {
 		EqualsIdentifierCI(a.Name, b.Name)
 }
 
+// EqualsRefOfDropProcedure does deep equals between the two objects.
+func EqualsRefOfDropProcedure(a, b *DropProcedure) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.IfExists == b.IfExists &&
+		EqualsTableName(a.Name, b.Name)
+}
+
 // EqualsRefOfDropTable does deep equals between the two objects.
 func EqualsRefOfDropTable(a, b *DropTable) bool {
 	if a == b {
@@ -2255,6 +2446,18 @@ func EqualsRefOfDropTable(a, b *DropTable) bool {
 		EqualsRefOfParsedComments(a.Comments, b.Comments)
 }
 
+// EqualsRefOfDropTrigger does deep equals between the two objects.
+func EqualsRefOfDropTrigger(a, b *DropTrigger) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.IfExists == b.IfExists &&
+		EqualsTableName(a.Name, b.Name)
+}
+
 // EqualsRefOfDropView does deep equals between the two objects.
 func EqualsRefOfDropView(a, b *DropView) bool {
 	if a == b {
@@ -2268,6 +2471,21 @@ func EqualsRefOfDropView(a, b *DropView) bool {
 		EqualsRefOfParsedComments(a.Comments, b.Comments)
 }
 
+// EqualsRefOfEventSchedule does deep equals between the two objects.
+func EqualsRefOfEventSchedule(a, b *EventSchedule) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.Unit == b.Unit &&
+		EqualsExpr(a.At, b.At) &&
+		EqualsExpr(a.Every, b.Every) &&
+		EqualsExpr(a.Starts, b.Starts) &&
+		EqualsExpr(a.Ends, b.Ends)
+}
+
 // EqualsRefOfExecuteStmt does deep equals between the two objects.
 func EqualsRefOfExecuteStmt(a, b *ExecuteStmt) bool {
 	if a == b {
@@ -3674,6 +3892,31 @@ func EqualsRootNode(a, b RootNode) bool {
 	return EqualsSQLNode(a.SQLNode, b.SQLNode)
 }
 
+// EqualsRefOfRoutineCharacteristic does deep equals between the two objects.
+func EqualsRefOfRoutineCharacteristic(a, b *RoutineCharacteristic) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.Name == b.Name &&
+		EqualsRefOfLiteral(a.Comment, b.Comment)
+}
+
+// EqualsRefOfRoutineParam does deep equals between the two objects.
+func EqualsRefOfRoutineParam(a, b *RoutineParam) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.Mode == b.Mode &&
+		EqualsIdentifierCI(a.Name, b.Name) &&
+		EqualsColumnType(a.Type, b.Type)
+}
+
 // EqualsRefOfSRollback does deep equals between the two objects.
 func EqualsRefOfSRollback(a, b *SRollback) bool {
 	if a == b {
@@ -6040,6 +6283,12 @@ func EqualsStatement(inA, inB Statement) bool {
 			return false
 		}
 		return EqualsRefOfAlterDatabase(a, b)
+	case *AlterDeadLetters:
+		b, ok := inB.(*AlterDeadLetters)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfAlterDeadLetters(a, b)
 	case *AlterMigration:
 		b, ok := inB.(*AlterMigration)
 		if !ok {
@@ -6094,12 +6343,36 @@ func EqualsStatement(inA, inB Statement) bool {
 			return false
 		}
 		return EqualsRefOfCreateDatabase(a, b)
+	case *CreateEvent:
+		b, ok := inB.(*CreateEvent)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfCreateEvent(a, b)
+	case *CreateFunction:
+		b, ok := inB.(*CreateFunction)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfCreateFunction(a, b)
+	case *CreateProcedure:
+		b, ok := inB.(*CreateProcedure)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfCreateProcedure(a, b)
 	case *CreateTable:
 		b, ok := inB.(*CreateTable)
 		if !ok {
 			return false
 		}
 		return EqualsRefOfCreateTable(a, b)
+	case *CreateTrigger:
+		b, ok := inB.(*CreateTrigger)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfCreateTrigger(a, b)
 	case *CreateView:
 		b, ok := inB.(*CreateView)
 		if !ok {
@@ -6124,12 +6397,36 @@ func EqualsStatement(inA, inB Statement) bool {
 			return false
 		}
 		return EqualsRefOfDropDatabase(a, b)
+	case *DropEvent:
+		b, ok := inB.(*DropEvent)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfDropEvent(a, b)
+	case *DropFunction:
+		b, ok := inB.(*DropFunction)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfDropFunction(a, b)
+	case *DropProcedure:
+		b, ok := inB.(*DropProcedure)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfDropProcedure(a, b)
 	case *DropTable:
 		b, ok := inB.(*DropTable)
 		if !ok {
 			return false
 		}
 		return EqualsRefOfDropTable(a, b)
+	case *DropTrigger:
+		b, ok := inB.(*DropTrigger)
+		if !ok {
+			return false
+		}
+		return EqualsRefOfDropTrigger(a, b)
 	case *DropView:
 		b, ok := inB.(*DropView)
 		if !ok {
@@ -6487,6 +6784,32 @@ func EqualsSliceOfString(a, b []string) bool {
 	return true
 }
 
+// EqualsSliceOfRefOfRoutineParam does deep equals between the two objects.
+func EqualsSliceOfRefOfRoutineParam(a, b []*RoutineParam) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		if !EqualsRefOfRoutineParam(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualsSliceOfRefOfRoutineCharacteristic does deep equals between the two objects.
+func EqualsSliceOfRefOfRoutineCharacteristic(a, b []*RoutineCharacteristic) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		if !EqualsRefOfRoutineCharacteristic(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 // EqualsSliceOfRefOfVariable does deep equals between the two objects.
 func EqualsSliceOfRefOfVariable(a, b []*Variable) bool {
 	if len(a) != len(b) {
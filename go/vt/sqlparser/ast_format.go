@@ -302,6 +302,14 @@ func (node *AlterMigration) Format(buf *TrackedBuffer) {
 	}
 }
 
+// Format formats the node.
+func (node *AlterDeadLetters) Format(buf *TrackedBuffer) {
+	buf.astPrintf(node, "alter vitess_dead_letters %v retry %v", node.Table, node.IDs)
+	if !node.Into.IsEmpty() {
+		buf.astPrintf(node, " into %v", node.Into)
+	}
+}
+
 // Format formats the node.
 func (node *RevertMigration) Format(buf *TrackedBuffer) {
 	buf.astPrintf(node, "revert %vvitess_migration '%#s'", node.Comments, node.UUID)
@@ -2109,6 +2117,162 @@ func (definer *Definer) Format(buf *TrackedBuffer) {
 	}
 }
 
+// Format formats the node.
+func (node *CreateTrigger) Format(buf *TrackedBuffer) {
+	buf.literal("create ")
+	if node.Definer != nil {
+		buf.astPrintf(node, "definer = %v ", node.Definer)
+	}
+	buf.literal("trigger ")
+	if node.IfNotExists {
+		buf.literal("if not exists ")
+	}
+	buf.astPrintf(node, "%v %s %s on %v for each row ", node.Name, node.Timing, node.Event, node.Table)
+	if node.Order != "" {
+		buf.astPrintf(node, "%s %v ", node.Order, node.OtherTrigger)
+	}
+	buf.astPrintf(node, "%#s", node.Body)
+}
+
+// Format formats the node.
+func (node *CreateProcedure) Format(buf *TrackedBuffer) {
+	buf.literal("create ")
+	if node.Definer != nil {
+		buf.astPrintf(node, "definer = %v ", node.Definer)
+	}
+	buf.literal("procedure ")
+	if node.IfNotExists {
+		buf.literal("if not exists ")
+	}
+	buf.astPrintf(node, "%v(", node.Name)
+	for i, param := range node.Params {
+		if i != 0 {
+			buf.literal(", ")
+		}
+		buf.astPrintf(node, "%v", param)
+	}
+	buf.WriteByte(')')
+	for _, characteristic := range node.Characteristics {
+		buf.astPrintf(node, " %v", characteristic)
+	}
+	buf.astPrintf(node, " %#s", node.Body)
+}
+
+// Format formats the node.
+func (node *CreateFunction) Format(buf *TrackedBuffer) {
+	buf.literal("create ")
+	if node.Definer != nil {
+		buf.astPrintf(node, "definer = %v ", node.Definer)
+	}
+	buf.literal("function ")
+	if node.IfNotExists {
+		buf.literal("if not exists ")
+	}
+	buf.astPrintf(node, "%v(", node.Name)
+	for i, param := range node.Params {
+		if i != 0 {
+			buf.literal(", ")
+		}
+		buf.astPrintf(node, "%v", param)
+	}
+	buf.astPrintf(node, ") returns %v", &node.Returns)
+	for _, characteristic := range node.Characteristics {
+		buf.astPrintf(node, " %v", characteristic)
+	}
+	buf.astPrintf(node, " %#s", node.Body)
+}
+
+// Format formats the node.
+func (node *RoutineParam) Format(buf *TrackedBuffer) {
+	if node.Mode != "" {
+		buf.astPrintf(node, "%s ", node.Mode)
+	}
+	buf.astPrintf(node, "%v %v", node.Name, &node.Type)
+}
+
+// Format formats the node.
+func (node *RoutineCharacteristic) Format(buf *TrackedBuffer) {
+	if node.Comment != nil {
+		buf.astPrintf(node, "comment %v", node.Comment)
+		return
+	}
+	buf.astPrintf(node, "%s", node.Name)
+}
+
+// Format formats the node.
+func (node *CreateEvent) Format(buf *TrackedBuffer) {
+	buf.literal("create ")
+	if node.Definer != nil {
+		buf.astPrintf(node, "definer = %v ", node.Definer)
+	}
+	buf.literal("event ")
+	if node.IfNotExists {
+		buf.literal("if not exists ")
+	}
+	buf.astPrintf(node, "%v on schedule %v", node.Name, node.Schedule)
+	if node.OnCompletion != "" {
+		buf.astPrintf(node, " on completion %s", node.OnCompletion)
+	}
+	if node.Status != "" {
+		buf.astPrintf(node, " %s", node.Status)
+	}
+	if node.Comment != nil {
+		buf.astPrintf(node, " comment %v", node.Comment)
+	}
+	buf.astPrintf(node, " do %#s", node.Body)
+}
+
+// Format formats the node.
+func (node *EventSchedule) Format(buf *TrackedBuffer) {
+	if node.At != nil {
+		buf.astPrintf(node, "at %v", node.At)
+		return
+	}
+	buf.astPrintf(node, "every %v %s", node.Every, node.Unit)
+	if node.Starts != nil {
+		buf.astPrintf(node, " starts %v", node.Starts)
+	}
+	if node.Ends != nil {
+		buf.astPrintf(node, " ends %v", node.Ends)
+	}
+}
+
+// Format formats the node.
+func (node *DropTrigger) Format(buf *TrackedBuffer) {
+	exists := ""
+	if node.IfExists {
+		exists = " if exists"
+	}
+	buf.astPrintf(node, "drop trigger%s %v", exists, node.Name)
+}
+
+// Format formats the node.
+func (node *DropProcedure) Format(buf *TrackedBuffer) {
+	exists := ""
+	if node.IfExists {
+		exists = " if exists"
+	}
+	buf.astPrintf(node, "drop procedure%s %v", exists, node.Name)
+}
+
+// Format formats the node.
+func (node *DropFunction) Format(buf *TrackedBuffer) {
+	exists := ""
+	if node.IfExists {
+		exists = " if exists"
+	}
+	buf.astPrintf(node, "drop function%s %v", exists, node.Name)
+}
+
+// Format formats the node.
+func (node *DropEvent) Format(buf *TrackedBuffer) {
+	exists := ""
+	if node.IfExists {
+		exists = " if exists"
+	}
+	buf.astPrintf(node, "drop event%s %v", exists, node.Name)
+}
+
 // Format formats the node.
 func (node *DropTable) Format(buf *TrackedBuffer) {
 	temp := ""
@@ -19,7 +19,6 @@ package sqlparser
 
 import (
 	"fmt"
-
 	"vitess.io/vitess/go/sqltypes"
 )
 
@@ -434,6 +433,18 @@ func (node *AlterMigration) formatFast(buf *TrackedBuffer) {
 	}
 }
 
+// formatFast formats the node.
+func (node *AlterDeadLetters) formatFast(buf *TrackedBuffer) {
+	buf.WriteString("alter vitess_dead_letters ")
+	node.Table.formatFast(buf)
+	buf.WriteString(" retry ")
+	node.IDs.formatFast(buf)
+	if !node.Into.IsEmpty() {
+		buf.WriteString(" into ")
+		node.Into.formatFast(buf)
+	}
+}
+
 // formatFast formats the node.
 func (node *RevertMigration) formatFast(buf *TrackedBuffer) {
 	buf.WriteString("revert ")
@@ -2773,6 +2784,215 @@ func (definer *Definer) formatFast(buf *TrackedBuffer) {
 	}
 }
 
+// formatFast formats the node.
+func (node *CreateTrigger) formatFast(buf *TrackedBuffer) {
+	buf.WriteString("create ")
+	if node.Definer != nil {
+		buf.WriteString("definer = ")
+		node.Definer.formatFast(buf)
+		buf.WriteByte(' ')
+	}
+	buf.WriteString("trigger ")
+	if node.IfNotExists {
+		buf.WriteString("if not exists ")
+	}
+	node.Name.formatFast(buf)
+	buf.WriteByte(' ')
+	buf.WriteString(node.Timing)
+	buf.WriteByte(' ')
+	buf.WriteString(node.Event)
+	buf.WriteString(" on ")
+	node.Table.formatFast(buf)
+	buf.WriteString(" for each row ")
+	if node.Order != "" {
+		buf.WriteString(node.Order)
+		buf.WriteByte(' ')
+		node.OtherTrigger.formatFast(buf)
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(node.Body)
+}
+
+// formatFast formats the node.
+func (node *CreateProcedure) formatFast(buf *TrackedBuffer) {
+	buf.WriteString("create ")
+	if node.Definer != nil {
+		buf.WriteString("definer = ")
+		node.Definer.formatFast(buf)
+		buf.WriteByte(' ')
+	}
+	buf.WriteString("procedure ")
+	if node.IfNotExists {
+		buf.WriteString("if not exists ")
+	}
+	node.Name.formatFast(buf)
+	buf.WriteByte('(')
+	for i, param := range node.Params {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		param.formatFast(buf)
+	}
+	buf.WriteByte(')')
+	for _, characteristic := range node.Characteristics {
+		buf.WriteByte(' ')
+		characteristic.formatFast(buf)
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(node.Body)
+}
+
+// formatFast formats the node.
+func (node *CreateFunction) formatFast(buf *TrackedBuffer) {
+	buf.WriteString("create ")
+	if node.Definer != nil {
+		buf.WriteString("definer = ")
+		node.Definer.formatFast(buf)
+		buf.WriteByte(' ')
+	}
+	buf.WriteString("function ")
+	if node.IfNotExists {
+		buf.WriteString("if not exists ")
+	}
+	node.Name.formatFast(buf)
+	buf.WriteByte('(')
+	for i, param := range node.Params {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		param.formatFast(buf)
+	}
+	buf.WriteString(") returns ")
+	(&node.Returns).formatFast(buf)
+	for _, characteristic := range node.Characteristics {
+		buf.WriteByte(' ')
+		characteristic.formatFast(buf)
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(node.Body)
+}
+
+// formatFast formats the node.
+func (node *RoutineParam) formatFast(buf *TrackedBuffer) {
+	if node.Mode != "" {
+		buf.WriteString(node.Mode)
+		buf.WriteByte(' ')
+	}
+	node.Name.formatFast(buf)
+	buf.WriteByte(' ')
+	(&node.Type).formatFast(buf)
+}
+
+// formatFast formats the node.
+func (node *RoutineCharacteristic) formatFast(buf *TrackedBuffer) {
+	if node.Comment != nil {
+		buf.WriteString("comment ")
+		node.Comment.formatFast(buf)
+		return
+	}
+	buf.WriteString(node.Name)
+}
+
+// formatFast formats the node.
+func (node *CreateEvent) formatFast(buf *TrackedBuffer) {
+	buf.WriteString("create ")
+	if node.Definer != nil {
+		buf.WriteString("definer = ")
+		node.Definer.formatFast(buf)
+		buf.WriteByte(' ')
+	}
+	buf.WriteString("event ")
+	if node.IfNotExists {
+		buf.WriteString("if not exists ")
+	}
+	node.Name.formatFast(buf)
+	buf.WriteString(" on schedule ")
+	node.Schedule.formatFast(buf)
+	if node.OnCompletion != "" {
+		buf.WriteString(" on completion ")
+		buf.WriteString(node.OnCompletion)
+	}
+	if node.Status != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(node.Status)
+	}
+	if node.Comment != nil {
+		buf.WriteString(" comment ")
+		node.Comment.formatFast(buf)
+	}
+	buf.WriteString(" do ")
+	buf.WriteString(node.Body)
+}
+
+// formatFast formats the node.
+func (node *EventSchedule) formatFast(buf *TrackedBuffer) {
+	if node.At != nil {
+		buf.WriteString("at ")
+		node.At.formatFast(buf)
+		return
+	}
+	buf.WriteString("every ")
+	node.Every.formatFast(buf)
+	buf.WriteByte(' ')
+	buf.WriteString(node.Unit)
+	if node.Starts != nil {
+		buf.WriteString(" starts ")
+		node.Starts.formatFast(buf)
+	}
+	if node.Ends != nil {
+		buf.WriteString(" ends ")
+		node.Ends.formatFast(buf)
+	}
+}
+
+// formatFast formats the node.
+func (node *DropTrigger) formatFast(buf *TrackedBuffer) {
+	exists := ""
+	if node.IfExists {
+		exists = " if exists"
+	}
+	buf.WriteString("drop trigger")
+	buf.WriteString(exists)
+	buf.WriteByte(' ')
+	node.Name.formatFast(buf)
+}
+
+// formatFast formats the node.
+func (node *DropProcedure) formatFast(buf *TrackedBuffer) {
+	exists := ""
+	if node.IfExists {
+		exists = " if exists"
+	}
+	buf.WriteString("drop procedure")
+	buf.WriteString(exists)
+	buf.WriteByte(' ')
+	node.Name.formatFast(buf)
+}
+
+// formatFast formats the node.
+func (node *DropFunction) formatFast(buf *TrackedBuffer) {
+	exists := ""
+	if node.IfExists {
+		exists = " if exists"
+	}
+	buf.WriteString("drop function")
+	buf.WriteString(exists)
+	buf.WriteByte(' ')
+	node.Name.formatFast(buf)
+}
+
+// formatFast formats the node.
+func (node *DropEvent) formatFast(buf *TrackedBuffer) {
+	exists := ""
+	if node.IfExists {
+		exists = " if exists"
+	}
+	buf.WriteString("drop event")
+	buf.WriteString(exists)
+	buf.WriteByte(' ')
+	node.Name.formatFast(buf)
+}
+
 // formatFast formats the node.
 func (node *DropTable) formatFast(buf *TrackedBuffer) {
 	temp := ""
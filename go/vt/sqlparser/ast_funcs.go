@@ -110,6 +110,16 @@ const (
 	colKey
 )
 
+// IsPrimary returns true when the option defines the column as the PRIMARY KEY
+func (o ColumnKeyOption) IsPrimary() bool {
+	return o == colKeyPrimary
+}
+
+// IsUnique returns true when the option defines the column as the PRIMARY KEY or as a UNIQUE KEY
+func (o ColumnKeyOption) IsUnique() bool {
+	return o == colKeyPrimary || o == colKeyUnique || o == colKeyUniqueKey
+}
+
 // ReferenceAction indicates the action takes by a referential constraint e.g.
 // the `CASCADE` in a `FOREIGN KEY .. ON DELETE CASCADE` table definition.
 type ReferenceAction int
@@ -1767,6 +1777,8 @@ func (ty ShowCommandType) ToString() string {
 		return VariableSessionStr
 	case VGtidExecGlobal:
 		return VGtidExecGlobalStr
+	case VitessDeadLetters:
+		return VitessDeadLettersStr
 	case VitessMigrations:
 		return VitessMigrationsStr
 	case VitessReplicationStatus:
@@ -788,6 +788,25 @@ func TestSplitStatementToPieces(t *testing.T) {
 	}
 }
 
+func TestSplitStatementToPiecesStoredPrograms(t *testing.T) {
+	input := "create table event (begin int); " +
+		"create procedure p1() begin select 1; select 2; end; " +
+		"create definer = `root`@`localhost` trigger tr1 before insert on t1 for each row begin if new.x > 1 then set new.x = 1; end if; end; " +
+		"create trigger tr2 after insert on t1 for each row set @a = 1; " +
+		"begin; select 3; commit"
+	pieces, err := SplitStatementToPieces(input)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"create table event (begin int)",
+		" create procedure p1() begin select 1; select 2; end",
+		" create definer = `root`@`localhost` trigger tr1 before insert on t1 for each row begin if new.x > 1 then set new.x = 1; end if; end",
+		" create trigger tr2 after insert on t1 for each row set @a = 1",
+		" begin",
+		" select 3",
+		" commit",
+	}, pieces)
+}
+
 func TestTypeConversion(t *testing.T) {
 	ct1 := &ColumnType{Type: "BIGINT"}
 	ct2 := &ColumnType{Type: "bigint"}
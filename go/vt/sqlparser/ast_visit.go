@@ -44,6 +44,8 @@ func VisitSQLNode(in SQLNode, f Visit) error {
 		return VisitRefOfAlterColumn(in, f)
 	case *AlterDatabase:
 		return VisitRefOfAlterDatabase(in, f)
+	case *AlterDeadLetters:
+		return VisitRefOfAlterDeadLetters(in, f)
 	case *AlterIndex:
 		return VisitRefOfAlterIndex(in, f)
 	case *AlterMigration:
@@ -122,8 +124,16 @@ func VisitSQLNode(in SQLNode, f Visit) error {
 		return VisitRefOfCountStar(in, f)
 	case *CreateDatabase:
 		return VisitRefOfCreateDatabase(in, f)
+	case *CreateEvent:
+		return VisitRefOfCreateEvent(in, f)
+	case *CreateFunction:
+		return VisitRefOfCreateFunction(in, f)
+	case *CreateProcedure:
+		return VisitRefOfCreateProcedure(in, f)
 	case *CreateTable:
 		return VisitRefOfCreateTable(in, f)
+	case *CreateTrigger:
+		return VisitRefOfCreateTrigger(in, f)
 	case *CreateView:
 		return VisitRefOfCreateView(in, f)
 	case *CurTimeFuncExpr:
@@ -142,12 +152,22 @@ func VisitSQLNode(in SQLNode, f Visit) error {
 		return VisitRefOfDropColumn(in, f)
 	case *DropDatabase:
 		return VisitRefOfDropDatabase(in, f)
+	case *DropEvent:
+		return VisitRefOfDropEvent(in, f)
+	case *DropFunction:
+		return VisitRefOfDropFunction(in, f)
 	case *DropKey:
 		return VisitRefOfDropKey(in, f)
+	case *DropProcedure:
+		return VisitRefOfDropProcedure(in, f)
 	case *DropTable:
 		return VisitRefOfDropTable(in, f)
+	case *DropTrigger:
+		return VisitRefOfDropTrigger(in, f)
 	case *DropView:
 		return VisitRefOfDropView(in, f)
+	case *EventSchedule:
+		return VisitRefOfEventSchedule(in, f)
 	case *ExecuteStmt:
 		return VisitRefOfExecuteStmt(in, f)
 	case *ExistsExpr:
@@ -382,6 +402,10 @@ func VisitSQLNode(in SQLNode, f Visit) error {
 		return VisitRefOfRollback(in, f)
 	case RootNode:
 		return VisitRootNode(in, f)
+	case *RoutineCharacteristic:
+		return VisitRefOfRoutineCharacteristic(in, f)
+	case *RoutineParam:
+		return VisitRefOfRoutineParam(in, f)
 	case *SRollback:
 		return VisitRefOfSRollback(in, f)
 	case *Savepoint:
@@ -645,6 +669,24 @@ func VisitRefOfAlterDatabase(in *AlterDatabase, f Visit) error {
 	}
 	return nil
 }
+func VisitRefOfAlterDeadLetters(in *AlterDeadLetters, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitTableName(in.Table, f); err != nil {
+		return err
+	}
+	if err := VisitValTuple(in.IDs, f); err != nil {
+		return err
+	}
+	if err := VisitTableName(in.Into, f); err != nil {
+		return err
+	}
+	return nil
+}
 func VisitRefOfAlterIndex(in *AlterIndex, f Visit) error {
 	if in == nil {
 		return nil
@@ -1181,6 +1223,77 @@ func VisitRefOfCreateDatabase(in *CreateDatabase, f Visit) error {
 	}
 	return nil
 }
+func VisitRefOfCreateEvent(in *CreateEvent, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitTableName(in.Name, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfDefiner(in.Definer, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfEventSchedule(in.Schedule, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.Comment, f); err != nil {
+		return err
+	}
+	return nil
+}
+func VisitRefOfCreateFunction(in *CreateFunction, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitTableName(in.Name, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfDefiner(in.Definer, f); err != nil {
+		return err
+	}
+	for _, el := range in.Params {
+		if err := VisitRefOfRoutineParam(el, f); err != nil {
+			return err
+		}
+	}
+	for _, el := range in.Characteristics {
+		if err := VisitRefOfRoutineCharacteristic(el, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func VisitRefOfCreateProcedure(in *CreateProcedure, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitTableName(in.Name, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfDefiner(in.Definer, f); err != nil {
+		return err
+	}
+	for _, el := range in.Params {
+		if err := VisitRefOfRoutineParam(el, f); err != nil {
+			return err
+		}
+	}
+	for _, el := range in.Characteristics {
+		if err := VisitRefOfRoutineCharacteristic(el, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 func VisitRefOfCreateTable(in *CreateTable, f Visit) error {
 	if in == nil {
 		return nil
@@ -1202,6 +1315,27 @@ func VisitRefOfCreateTable(in *CreateTable, f Visit) error {
 	}
 	return nil
 }
+func VisitRefOfCreateTrigger(in *CreateTrigger, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitTableName(in.Name, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfDefiner(in.Definer, f); err != nil {
+		return err
+	}
+	if err := VisitTableName(in.Table, f); err != nil {
+		return err
+	}
+	if err := VisitIdentifierCS(in.OtherTrigger, f); err != nil {
+		return err
+	}
+	return nil
+}
 func VisitRefOfCreateView(in *CreateView, f Visit) error {
 	if in == nil {
 		return nil
@@ -1346,6 +1480,30 @@ func VisitRefOfDropDatabase(in *DropDatabase, f Visit) error {
 	}
 	return nil
 }
+func VisitRefOfDropEvent(in *DropEvent, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitTableName(in.Name, f); err != nil {
+		return err
+	}
+	return nil
+}
+func VisitRefOfDropFunction(in *DropFunction, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitTableName(in.Name, f); err != nil {
+		return err
+	}
+	return nil
+}
 func VisitRefOfDropKey(in *DropKey, f Visit) error {
 	if in == nil {
 		return nil
@@ -1358,6 +1516,18 @@ func VisitRefOfDropKey(in *DropKey, f Visit) error {
 	}
 	return nil
 }
+func VisitRefOfDropProcedure(in *DropProcedure, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitTableName(in.Name, f); err != nil {
+		return err
+	}
+	return nil
+}
 func VisitRefOfDropTable(in *DropTable, f Visit) error {
 	if in == nil {
 		return nil
@@ -1373,6 +1543,18 @@ func VisitRefOfDropTable(in *DropTable, f Visit) error {
 	}
 	return nil
 }
+func VisitRefOfDropTrigger(in *DropTrigger, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitTableName(in.Name, f); err != nil {
+		return err
+	}
+	return nil
+}
 func VisitRefOfDropView(in *DropView, f Visit) error {
 	if in == nil {
 		return nil
@@ -1388,6 +1570,27 @@ func VisitRefOfDropView(in *DropView, f Visit) error {
 	}
 	return nil
 }
+func VisitRefOfEventSchedule(in *EventSchedule, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitExpr(in.At, f); err != nil {
+		return err
+	}
+	if err := VisitExpr(in.Every, f); err != nil {
+		return err
+	}
+	if err := VisitExpr(in.Starts, f); err != nil {
+		return err
+	}
+	if err := VisitExpr(in.Ends, f); err != nil {
+		return err
+	}
+	return nil
+}
 func VisitRefOfExecuteStmt(in *ExecuteStmt, f Visit) error {
 	if in == nil {
 		return nil
@@ -3043,6 +3246,30 @@ func VisitRootNode(in RootNode, f Visit) error {
 	}
 	return nil
 }
+func VisitRefOfRoutineCharacteristic(in *RoutineCharacteristic, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.Comment, f); err != nil {
+		return err
+	}
+	return nil
+}
+func VisitRefOfRoutineParam(in *RoutineParam, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitIdentifierCI(in.Name, f); err != nil {
+		return err
+	}
+	return nil
+}
 func VisitRefOfSRollback(in *SRollback, f Visit) error {
 	if in == nil {
 		return nil
@@ -4590,6 +4817,8 @@ func VisitStatement(in Statement, f Visit) error {
 	switch in := in.(type) {
 	case *AlterDatabase:
 		return VisitRefOfAlterDatabase(in, f)
+	case *AlterDeadLetters:
+		return VisitRefOfAlterDeadLetters(in, f)
 	case *AlterMigration:
 		return VisitRefOfAlterMigration(in, f)
 	case *AlterTable:
@@ -4608,8 +4837,16 @@ func VisitStatement(in Statement, f Visit) error {
 		return VisitRefOfCommit(in, f)
 	case *CreateDatabase:
 		return VisitRefOfCreateDatabase(in, f)
+	case *CreateEvent:
+		return VisitRefOfCreateEvent(in, f)
+	case *CreateFunction:
+		return VisitRefOfCreateFunction(in, f)
+	case *CreateProcedure:
+		return VisitRefOfCreateProcedure(in, f)
 	case *CreateTable:
 		return VisitRefOfCreateTable(in, f)
+	case *CreateTrigger:
+		return VisitRefOfCreateTrigger(in, f)
 	case *CreateView:
 		return VisitRefOfCreateView(in, f)
 	case *DeallocateStmt:
@@ -4618,8 +4855,16 @@ func VisitStatement(in Statement, f Visit) error {
 		return VisitRefOfDelete(in, f)
 	case *DropDatabase:
 		return VisitRefOfDropDatabase(in, f)
+	case *DropEvent:
+		return VisitRefOfDropEvent(in, f)
+	case *DropFunction:
+		return VisitRefOfDropFunction(in, f)
+	case *DropProcedure:
+		return VisitRefOfDropProcedure(in, f)
 	case *DropTable:
 		return VisitRefOfDropTable(in, f)
+	case *DropTrigger:
+		return VisitRefOfDropTrigger(in, f)
 	case *DropView:
 		return VisitRefOfDropView(in, f)
 	case *ExecuteStmt:
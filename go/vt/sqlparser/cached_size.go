@@ -188,6 +188,29 @@ func (cached *AlterDatabase) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *AlterDeadLetters) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(96)
+	}
+	// field Table vitess.io/vitess/go/vt/sqlparser.TableName
+	size += cached.Table.CachedSize(false)
+	// field IDs vitess.io/vitess/go/vt/sqlparser.ValTuple
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.IDs)) * int64(16))
+		for _, elem := range cached.IDs {
+			if cc, ok := elem.(cachedObject); ok {
+				size += cc.CachedSize(true)
+			}
+		}
+	}
+	// field Into vitess.io/vitess/go/vt/sqlparser.TableName
+	size += cached.Into.CachedSize(false)
+	return size
+}
 func (cached *AlterIndex) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -870,6 +893,92 @@ func (cached *CreateDatabase) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *CreateEvent) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(112)
+	}
+	// field Name vitess.io/vitess/go/vt/sqlparser.TableName
+	size += cached.Name.CachedSize(false)
+	// field Definer *vitess.io/vitess/go/vt/sqlparser.Definer
+	size += cached.Definer.CachedSize(true)
+	// field Schedule *vitess.io/vitess/go/vt/sqlparser.EventSchedule
+	size += cached.Schedule.CachedSize(true)
+	// field OnCompletion string
+	size += hack.RuntimeAllocSize(int64(len(cached.OnCompletion)))
+	// field Status string
+	size += hack.RuntimeAllocSize(int64(len(cached.Status)))
+	// field Comment *vitess.io/vitess/go/vt/sqlparser.Literal
+	size += cached.Comment.CachedSize(true)
+	// field Body string
+	size += hack.RuntimeAllocSize(int64(len(cached.Body)))
+	return size
+}
+func (cached *CreateFunction) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(208)
+	}
+	// field Name vitess.io/vitess/go/vt/sqlparser.TableName
+	size += cached.Name.CachedSize(false)
+	// field Definer *vitess.io/vitess/go/vt/sqlparser.Definer
+	size += cached.Definer.CachedSize(true)
+	// field Params []*vitess.io/vitess/go/vt/sqlparser.RoutineParam
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Params)) * int64(8))
+		for _, elem := range cached.Params {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field Returns vitess.io/vitess/go/vt/sqlparser.ColumnType
+	size += cached.Returns.CachedSize(false)
+	// field Characteristics []*vitess.io/vitess/go/vt/sqlparser.RoutineCharacteristic
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Characteristics)) * int64(8))
+		for _, elem := range cached.Characteristics {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field Body string
+	size += hack.RuntimeAllocSize(int64(len(cached.Body)))
+	return size
+}
+func (cached *CreateProcedure) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(112)
+	}
+	// field Name vitess.io/vitess/go/vt/sqlparser.TableName
+	size += cached.Name.CachedSize(false)
+	// field Definer *vitess.io/vitess/go/vt/sqlparser.Definer
+	size += cached.Definer.CachedSize(true)
+	// field Params []*vitess.io/vitess/go/vt/sqlparser.RoutineParam
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Params)) * int64(8))
+		for _, elem := range cached.Params {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field Characteristics []*vitess.io/vitess/go/vt/sqlparser.RoutineCharacteristic
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Characteristics)) * int64(8))
+		for _, elem := range cached.Characteristics {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field Body string
+	size += hack.RuntimeAllocSize(int64(len(cached.Body)))
+	return size
+}
 func (cached *CreateTable) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -888,6 +997,32 @@ func (cached *CreateTable) CachedSize(alloc bool) int64 {
 	size += cached.Comments.CachedSize(true)
 	return size
 }
+func (cached *CreateTrigger) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(160)
+	}
+	// field Name vitess.io/vitess/go/vt/sqlparser.TableName
+	size += cached.Name.CachedSize(false)
+	// field Definer *vitess.io/vitess/go/vt/sqlparser.Definer
+	size += cached.Definer.CachedSize(true)
+	// field Timing string
+	size += hack.RuntimeAllocSize(int64(len(cached.Timing)))
+	// field Event string
+	size += hack.RuntimeAllocSize(int64(len(cached.Event)))
+	// field Table vitess.io/vitess/go/vt/sqlparser.TableName
+	size += cached.Table.CachedSize(false)
+	// field Order string
+	size += hack.RuntimeAllocSize(int64(len(cached.Order)))
+	// field OtherTrigger vitess.io/vitess/go/vt/sqlparser.IdentifierCS
+	size += cached.OtherTrigger.CachedSize(false)
+	// field Body string
+	size += hack.RuntimeAllocSize(int64(len(cached.Body)))
+	return size
+}
 func (cached *CreateView) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -1077,6 +1212,30 @@ func (cached *DropDatabase) CachedSize(alloc bool) int64 {
 	size += cached.DBName.CachedSize(false)
 	return size
 }
+func (cached *DropEvent) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field Name vitess.io/vitess/go/vt/sqlparser.TableName
+	size += cached.Name.CachedSize(false)
+	return size
+}
+func (cached *DropFunction) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field Name vitess.io/vitess/go/vt/sqlparser.TableName
+	size += cached.Name.CachedSize(false)
+	return size
+}
 func (cached *DropKey) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -1089,6 +1248,18 @@ func (cached *DropKey) CachedSize(alloc bool) int64 {
 	size += cached.Name.CachedSize(false)
 	return size
 }
+func (cached *DropProcedure) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field Name vitess.io/vitess/go/vt/sqlparser.TableName
+	size += cached.Name.CachedSize(false)
+	return size
+}
 func (cached *DropTable) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -1108,6 +1279,18 @@ func (cached *DropTable) CachedSize(alloc bool) int64 {
 	size += cached.Comments.CachedSize(true)
 	return size
 }
+func (cached *DropTrigger) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field Name vitess.io/vitess/go/vt/sqlparser.TableName
+	size += cached.Name.CachedSize(false)
+	return size
+}
 func (cached *DropView) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -1127,6 +1310,34 @@ func (cached *DropView) CachedSize(alloc bool) int64 {
 	size += cached.Comments.CachedSize(true)
 	return size
 }
+func (cached *EventSchedule) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(80)
+	}
+	// field At vitess.io/vitess/go/vt/sqlparser.Expr
+	if cc, ok := cached.At.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Every vitess.io/vitess/go/vt/sqlparser.Expr
+	if cc, ok := cached.Every.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Unit string
+	size += hack.RuntimeAllocSize(int64(len(cached.Unit)))
+	// field Starts vitess.io/vitess/go/vt/sqlparser.Expr
+	if cc, ok := cached.Starts.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Ends vitess.io/vitess/go/vt/sqlparser.Expr
+	if cc, ok := cached.Ends.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	return size
+}
 func (cached *ExecuteStmt) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -3108,6 +3319,36 @@ func (cached *RevertMigration) CachedSize(alloc bool) int64 {
 	size += cached.Comments.CachedSize(true)
 	return size
 }
+func (cached *RoutineCharacteristic) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(24)
+	}
+	// field Name string
+	size += hack.RuntimeAllocSize(int64(len(cached.Name)))
+	// field Comment *vitess.io/vitess/go/vt/sqlparser.Literal
+	size += cached.Comment.CachedSize(true)
+	return size
+}
+func (cached *RoutineParam) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(144)
+	}
+	// field Mode string
+	size += hack.RuntimeAllocSize(int64(len(cached.Mode)))
+	// field Name vitess.io/vitess/go/vt/sqlparser.IdentifierCI
+	size += cached.Name.CachedSize(false)
+	// field Type vitess.io/vitess/go/vt/sqlparser.ColumnType
+	size += cached.Type.CachedSize(false)
+	return size
+}
 func (cached *SRollback) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -286,6 +286,7 @@ const (
 	VariableSessionStr         = " variables"
 	VGtidExecGlobalStr         = " global vgtid_executed"
 	KeyspaceStr                = " keyspaces"
+	VitessDeadLettersStr       = " vitess_dead_letters"
 	VitessMigrationsStr        = " vitess_migrations"
 	VitessReplicationStatusStr = " vitess_replication_status"
 	VitessShardsStr            = " vitess_shards"
@@ -792,6 +793,7 @@ const (
 	VariableGlobal
 	VariableSession
 	VGtidExecGlobal
+	VitessDeadLetters
 	VitessMigrations
 	VitessReplicationStatus
 	VitessShards
@@ -130,11 +130,12 @@ var keywords = []keyword{
 	{"asc", ASC},
 	{"ascii", ASCII},
 	{"asensitive", UNUSED},
+	{"at", AT},
 	{"auto_increment", AUTO_INCREMENT},
 	{"autoextend_size", AUTOEXTEND_SIZE},
 	{"avg", AVG},
 	{"avg_row_length", AVG_ROW_LENGTH},
-	{"before", UNUSED},
+	{"before", BEFORE},
 	{"begin", BEGIN},
 	{"between", BETWEEN},
 	{"bigint", BIGINT},
@@ -175,11 +176,13 @@ var keywords = []keyword{
 	{"commit", COMMIT},
 	{"compact", COMPACT},
 	{"complete", COMPLETE},
+	{"completion", COMPLETION},
 	{"compressed", COMPRESSED},
 	{"compression", COMPRESSION},
 	{"condition", UNUSED},
 	{"connection", CONNECTION},
 	{"constraint", CONSTRAINT},
+	{"contains", CONTAINS},
 	{"continue", UNUSED},
 	{"convert", CONVERT},
 	{"copy", COPY},
@@ -221,7 +224,7 @@ var keywords = []keyword{
 	{"dense_rank", DENSE_RANK},
 	{"desc", DESC},
 	{"describe", DESCRIBE},
-	{"deterministic", UNUSED},
+	{"deterministic", DETERMINISTIC},
 	{"directory", DIRECTORY},
 	{"disable", DISABLE},
 	{"discard", DISCARD},
@@ -235,7 +238,7 @@ var keywords = []keyword{
 	{"dumpfile", DUMPFILE},
 	{"duplicate", DUPLICATE},
 	{"dynamic", DYNAMIC},
-	{"each", UNUSED},
+	{"each", EACH},
 	{"else", ELSE},
 	{"elseif", UNUSED},
 	{"empty", EMPTY},
@@ -243,6 +246,7 @@ var keywords = []keyword{
 	{"enclosed", ENCLOSED},
 	{"encryption", ENCRYPTION},
 	{"end", END},
+	{"ends", ENDS},
 	{"enforced", ENFORCED},
 	{"engine", ENGINE},
 	{"engine_attribute", ENGINE_ATTRIBUTE},
@@ -252,6 +256,7 @@ var keywords = []keyword{
 	{"escape", ESCAPE},
 	{"escaped", ESCAPED},
 	{"event", EVENT},
+	{"every", EVERY},
 	{"exchange", EXCHANGE},
 	{"exclusive", EXCLUSIVE},
 	{"execute", EXECUTE},
@@ -275,6 +280,7 @@ var keywords = []keyword{
 	{"float8", UNUSED},
 	{"flush", FLUSH},
 	{"following", FOLLOWING},
+	{"follows", FOLLOWS},
 	{"for", FOR},
 	{"force", FORCE},
 	{"foreign", FOREIGN},
@@ -317,7 +323,7 @@ var keywords = []keyword{
 	{"index", INDEX},
 	{"indexes", INDEXES},
 	{"infile", UNUSED},
-	{"inout", UNUSED},
+	{"inout", INOUT},
 	{"inner", INNER},
 	{"inplace", INPLACE},
 	{"insensitive", UNUSED},
@@ -406,7 +412,7 @@ var keywords = []keyword{
 	{"long", UNUSED},
 	{"longblob", LONGBLOB},
 	{"longtext", LONGTEXT},
-	{"loop", UNUSED},
+	{"loop", LOOP},
 	{"low_priority", LOW_PRIORITY},
 	{"ltrim", LTRIM},
 	{"min", MIN},
@@ -431,7 +437,7 @@ var keywords = []keyword{
 	{"mod", MOD},
 	{"mode", MODE},
 	{"modify", MODIFY},
-	{"modifies", UNUSED},
+	{"modifies", MODIFIES},
 	{"multilinestring", MULTILINESTRING},
 	{"multipoint", MULTIPOINT},
 	{"multipolygon", MULTIPOLYGON},
@@ -465,7 +471,7 @@ var keywords = []keyword{
 	{"or", OR},
 	{"order", ORDER},
 	{"ordinality", ORDINALITY},
-	{"out", UNUSED},
+	{"out", OUT},
 	{"outer", OUTER},
 	{"outfile", OUTFILE},
 	{"over", OVER},
@@ -483,9 +489,11 @@ var keywords = []keyword{
 	{"point", POINT},
 	{"polygon", POLYGON},
 	{"position", POSITION},
+	{"precedes", PRECEDES},
 	{"preceding", PRECEDING},
 	{"precision", UNUSED},
 	{"prepare", PREPARE},
+	{"preserve", PRESERVE},
 	{"primary", PRIMARY},
 	{"privileges", PRIVILEGES},
 	{"processlist", PROCESSLIST},
@@ -498,7 +506,7 @@ var keywords = []keyword{
 	{"rank", RANK},
 	{"ratio", RATIO},
 	{"read", READ},
-	{"reads", UNUSED},
+	{"reads", READS},
 	{"read_write", UNUSED},
 	{"real", REAL},
 	{"rebuild", REBUILD},
@@ -518,7 +526,7 @@ var keywords = []keyword{
 	{"rename", RENAME},
 	{"reorganize", REORGANIZE},
 	{"repair", REPAIR},
-	{"repeat", UNUSED},
+	{"repeat", REPEAT},
 	{"repeatable", REPEATABLE},
 	{"replace", REPLACE},
 	{"require", UNUSED},
@@ -528,6 +536,7 @@ var keywords = []keyword{
 	{"return", UNUSED},
 	{"returning", RETURNING},
 	{"retry", RETRY},
+	{"returns", RETURNS},
 	{"revert", REVERT},
 	{"revoke", UNUSED},
 	{"right", RIGHT},
@@ -540,6 +549,7 @@ var keywords = []keyword{
 	{"rtrim", RTRIM},
 	{"s3", S3},
 	{"savepoint", SAVEPOINT},
+	{"schedule", SCHEDULE},
 	{"schema", SCHEMA},
 	{"schemas", SCHEMAS},
 	{"second", SECOND},
@@ -575,6 +585,7 @@ var keywords = []keyword{
 	{"ssl", UNUSED},
 	{"start", START},
 	{"starting", STARTING},
+	{"starts", STARTS},
 	{"stats_auto_recalc", STATS_AUTO_RECALC},
 	{"stats_persistent", STATS_PERSISTENT},
 	{"stats_sample_pages", STATS_SAMPLE_PAGES},
@@ -653,6 +664,7 @@ var keywords = []keyword{
 	{"vindexes", VINDEXES},
 	{"view", VIEW},
 	{"vitess", VITESS},
+	{"vitess_dead_letters", VITESS_DEAD_LETTERS},
 	{"vitess_keyspaces", VITESS_KEYSPACES},
 	{"vitess_metadata", VITESS_METADATA},
 	{"vitess_migration", VITESS_MIGRATION},
@@ -670,7 +682,7 @@ var keywords = []keyword{
 	{"weight_string", WEIGHT_STRING},
 	{"when", WHEN},
 	{"where", WHERE},
-	{"while", UNUSED},
+	{"while", WHILE},
 	{"window", WINDOW},
 	{"with", WITH},
 	{"without", WITHOUT},
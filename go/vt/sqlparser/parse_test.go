@@ -1716,6 +1716,53 @@ var (
 	}, {
 		input:  "drop view if exists a cascade",
 		output: "drop view if exists a",
+	}, {
+		input: "create trigger tr1 before insert on t1 for each row set new.x = 1",
+	}, {
+		input:  "create definer = `root`@`localhost` trigger if not exists tr1 after update on t1 for each row follows tr0 update t2 set x = x + 1",
+		output: "create definer = root@localhost trigger if not exists tr1 after update on t1 for each row follows tr0 update t2 set x = x + 1",
+	}, {
+		input: "create trigger tr1 before delete on t1 for each row BEGIN IF OLD.x > 1 THEN SET @a = 1; END IF; CASE WHEN 1 THEN SET @b = 2; END CASE; END",
+	}, {
+		input:  "create procedure p1(in a int, out b varchar(10), inout c int) comment 'hi' deterministic reads sql data begin select a into b; end",
+		output: "create procedure p1(in a int, out b varchar(10), inout c int) comment 'hi' deterministic reads sql data begin select a into b; end",
+	}, {
+		input:  "create procedure p1() begin select 1; select 2; end",
+		output: "create procedure p1() begin select 1; select 2; end",
+	}, {
+		input: "create function f1(a int) returns int deterministic return a + 1",
+	}, {
+		input:  "create function f1() returns varchar(10) charset utf8mb4 no sql return 'x'",
+		output: "create function f1() returns varchar(10) character set utf8mb4 no sql return 'x'",
+	}, {
+		input: "create function f1(a decimal(10,2)) returns decimal(10,2) unsigned zerofill sql security invoker begin return a; end",
+	}, {
+		input: "create function f1() returns enum('a', 'b') character set latin1 binary deterministic return 'a'",
+	}, {
+		input:  "create definer = current_user() procedure p1() language sql not deterministic modifies sql data select 1",
+		output: "create definer = current_user procedure p1() language sql not deterministic modifies sql data select 1",
+	}, {
+		input: "create trigger tr1 before insert on t1 for each row precedes tr0 begin set new.x = 1; end",
+	}, {
+		input: "create event e1 on schedule every 1 hour do delete from t1",
+	}, {
+		input: "create definer = 'sa'@localhost event e1 on schedule every 1 hour do call p1()",
+	}, {
+		input:  "create event if not exists e1 on schedule at current_timestamp + interval 1 hour on completion preserve disable comment 'c' do delete from t1",
+		output: "create event if not exists e1 on schedule at current_timestamp() + interval 1 hour on completion preserve disable comment 'c' do delete from t1",
+	}, {
+		input: "create event e1 on schedule every 1 day starts '2022-01-01' ends '2023-01-01' do begin delete from t1; delete from t2; end",
+	}, {
+		input: "drop trigger if exists tr1",
+	}, {
+		input: "drop procedure p1",
+	}, {
+		input: "drop function if exists ks.f1",
+	}, {
+		input: "drop event e1",
+	}, {
+		input:  "select before, each, loop from t",
+		output: "select `before`, `each`, `loop` from t",
 	}, {
 		input:  "drop index b on a lock = none algorithm default",
 		output: "alter table a drop key b, lock none, algorithm = default",
@@ -1987,6 +2034,14 @@ var (
 		input: "show vitess_migrations like '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90'",
 	}, {
 		input: "show vitess_migration '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90' logs",
+	}, {
+		input: "show vitess_dead_letters from msg",
+	}, {
+		input: "show vitess_dead_letters from ks.msg_dead_letters",
+	}, {
+		input: "alter vitess_dead_letters msg retry (1)",
+	}, {
+		input: "alter vitess_dead_letters ks.msg_dead_letters retry (1, 2, 'a') into ks.msg",
 	}, {
 		input: "revert vitess_migration '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90'",
 	}, {
@@ -3284,6 +3339,21 @@ func TestInvalid(t *testing.T) {
 	}{{
 		input: "select a, b from (select * from tbl) sort by a",
 		err:   "syntax error",
+	}, {
+		input: "create or replace trigger tr1 before insert on t1 for each row set new.x = 1",
+		err:   "syntax error",
+	}, {
+		input: "create trigger tr1 before insert on t1 for each row",
+		err:   "syntax error at position 52",
+	}, {
+		input: "create procedure p1() comment 'x'",
+		err:   "syntax error at position 34",
+	}, {
+		input: "create event e1 on schedule every 1 hour do",
+		err:   "syntax error at position 44",
+	}, {
+		input: "create function f1(in a int) returns int return a",
+		err:   "syntax error at position 22 near 'in'",
 	}, {
 		input: "/*!*/",
 		err:   "Query was empty",
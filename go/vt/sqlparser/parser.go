@@ -276,17 +276,28 @@ func SplitStatementToPieces(blob string) (pieces []string, err error) {
 	var stmt string
 	stmtBegin := 0
 	emptyStatement := true
+	// The body of a stored program may contain semicolons within BEGIN ... END blocks.
+	var firstToken int
+	objectTypeKnown := false
+	storedProgram := false
+	var blocks blockDepth
 loop:
 	for {
 		tkn, _ = tokenizer.Scan()
 		switch tkn {
 		case ';':
+			if storedProgram && blocks.depth > 0 {
+				continue
+			}
 			stmt = blob[stmtBegin : tokenizer.Pos-1]
 			if !emptyStatement {
 				pieces = append(pieces, stmt)
 				emptyStatement = true
 			}
 			stmtBegin = tokenizer.Pos
+			objectTypeKnown = false
+			storedProgram = false
+			blocks = blockDepth{}
 		case 0, eofChar:
 			blobTail := tokenizer.Pos - 1
 			if stmtBegin < blobTail {
@@ -297,6 +308,20 @@ loop:
 			}
 			break loop
 		default:
+			if emptyStatement {
+				firstToken = tkn
+			} else if firstToken == CREATE && !objectTypeKnown {
+				switch tkn {
+				case TRIGGER, PROCEDURE, FUNCTION, EVENT:
+					objectTypeKnown = true
+					storedProgram = true
+				case TABLE, VIEW, INDEX, DATABASE, SCHEMA:
+					objectTypeKnown = true
+				}
+			}
+			if storedProgram {
+				blocks.update(tkn)
+			}
 			emptyStatement = false
 		}
 	}
@@ -144,6 +144,11 @@ func bindVariable(yylex yyLexer, bvar string) {
   windowDefinitions WindowDefinitions
   namedWindow *NamedWindow
   namedWindows NamedWindows
+  routineParam *RoutineParam
+  routineParams []*RoutineParam
+  routineCharacteristic *RoutineCharacteristic
+  routineCharacteristics []*RoutineCharacteristic
+  eventSchedule *EventSchedule
 
   whens         []*When
   columnDefinitions []*ColumnDefinition
@@ -300,6 +305,10 @@ func bindVariable(yylex yyLexer, bvar string) {
 %token <str> STATUS VARIABLES WARNINGS CASCADED DEFINER OPTION SQL UNDEFINED
 %token <str> SEQUENCE MERGE TEMPORARY TEMPTABLE INVOKER SECURITY FIRST AFTER LAST
 
+// Stored program tokens
+%token <str> BEFORE EACH FOLLOWS PRECEDES RETURNS DETERMINISTIC CONTAINS READS MODIFIES INOUT OUT ROUTINE_BODY
+%token <str> SCHEDULE AT EVERY STARTS ENDS COMPLETION PRESERVE LOOP REPEAT WHILE
+
 // Migration tokens
 %token <str> VITESS_MIGRATION CANCEL RETRY COMPLETE CLEANUP THROTTLE UNTHROTTLE EXPIRE RATIO
 
@@ -322,7 +331,7 @@ func bindVariable(yylex yyLexer, bvar string) {
 // SHOW tokens
 %token <str> CODE COLLATION COLUMNS DATABASES ENGINES EVENT EXTENDED FIELDS FULL FUNCTION GTID_EXECUTED
 %token <str> KEYSPACES OPEN PLUGINS PRIVILEGES PROCESSLIST SCHEMAS TABLES TRIGGERS USER
-%token <str> VGTID_EXECUTED VITESS_KEYSPACES VITESS_METADATA VITESS_MIGRATIONS VITESS_REPLICATION_STATUS VITESS_SHARDS VITESS_TABLETS VITESS_TARGET VSCHEMA VITESS_THROTTLED_APPS
+%token <str> VGTID_EXECUTED VITESS_DEAD_LETTERS VITESS_KEYSPACES VITESS_METADATA VITESS_MIGRATIONS VITESS_REPLICATION_STATUS VITESS_SHARDS VITESS_TABLETS VITESS_TARGET VSCHEMA VITESS_THROTTLED_APPS
 
 // SET tokens
 %token <str> NAMES GLOBAL SESSION ISOLATION LEVEL READ WRITE ONLY REPEATABLE COMMITTED UNCOMMITTED SERIALIZABLE
@@ -449,7 +458,15 @@ func bindVariable(yylex yyLexer, bvar string) {
 %type <strs> select_options flush_option_list
 %type <str> select_option algorithm_view security_view security_view_opt
 %type <str> generated_always_opt user_username address_opt
-%type <definer> definer_opt user
+%type <definer> definer_opt user create_program_prefix
+%type <str> trigger_time trigger_event trigger_order routine_body event_on_completion_opt event_status_opt
+%type <routineParam> procedure_param function_param
+%type <routineParams> procedure_param_list_opt procedure_param_list function_param_list_opt function_param_list
+%type <routineCharacteristic> routine_characteristic
+%type <routineCharacteristics> routine_characteristic_list_opt routine_characteristic_list
+%type <eventSchedule> event_schedule
+%type <expr> event_starts_opt event_ends_opt
+%type <literal> event_comment_opt
 %type <expr> expression frame_expression signed_literal signed_literal_or_null null_as_literal now_or_signed_literal signed_literal bit_expr regular_expressions xml_expressions
 %type <expr> interval_value simple_expr literal NUM_literal text_literal text_literal_or_arg bool_pri literal_or_null now predicate tuple_expression null_int_variable_arg performance_schema_function_expressions gtid_function_expressions
 %type <tableExprs> from_opt table_references from_clause
@@ -460,7 +477,7 @@ func bindVariable(yylex yyLexer, bvar string) {
 %type <joinCondition> join_condition join_condition_opt on_expression_opt
 %type <tableNames> table_name_list delete_table_list view_name_list
 %type <joinType> inner_join outer_join straight_join natural_join
-%type <tableName> table_name into_table_name delete_table_name
+%type <tableName> table_name into_table_name delete_table_name dead_letters_into_opt
 %type <aliasedTableName> aliased_table_name
 %type <indexHint> index_hint
 %type <indexHintForType> index_hint_for_opt
@@ -1089,6 +1106,26 @@ create_statement:
   {
     $$ = &CreateView{ViewName: $8.ToViewName(), Comments: Comments($2).Parsed(), IsReplace:$3, Algorithm:$4, Definer: $5 ,Security:$6, Columns:$9, Select: $11, CheckOption: $12 }
   }
+| create_program_prefix TRIGGER not_exists_opt table_name trigger_time trigger_event ON table_name FOR EACH ROW routine_body
+  {
+    $$ = &CreateTrigger{Name: $4, Definer: $1, IfNotExists: $3, Timing: $5, Event: $6, Table: $8, Body: $12}
+  }
+| create_program_prefix TRIGGER not_exists_opt table_name trigger_time trigger_event ON table_name FOR EACH ROW trigger_order table_id routine_body
+  {
+    $$ = &CreateTrigger{Name: $4, Definer: $1, IfNotExists: $3, Timing: $5, Event: $6, Table: $8, Order: $12, OtherTrigger: $13, Body: $14}
+  }
+| create_program_prefix PROCEDURE not_exists_opt table_name openb procedure_param_list_opt closeb routine_characteristic_list_opt routine_body
+  {
+    $$ = &CreateProcedure{Name: $4, Definer: $1, IfNotExists: $3, Params: $6, Characteristics: $8, Body: $9}
+  }
+| create_program_prefix FUNCTION not_exists_opt table_name openb function_param_list_opt closeb RETURNS column_type routine_characteristic_list_opt routine_body
+  {
+    $$ = &CreateFunction{Name: $4, Definer: $1, IfNotExists: $3, Params: $6, Returns: $9, Characteristics: $10, Body: $11}
+  }
+| create_program_prefix EVENT not_exists_opt table_name ON SCHEDULE event_schedule event_on_completion_opt event_status_opt event_comment_opt DO routine_body
+  {
+    $$ = &CreateEvent{Name: $4, Definer: $1, IfNotExists: $3, Schedule: $7, OnCompletion: $8, Status: $9, Comment: $10, Body: $12}
+  }
 | create_database_prefix create_options_opt
   {
     $1.FullyParsed = true
@@ -1096,6 +1133,243 @@ create_statement:
     $$ = $1
   }
 
+create_program_prefix:
+  CREATE comment_opt replace_opt algorithm_view definer_opt security_view_opt
+  {
+    // Stored programs share this prefix with views, but only accept a definer.
+    if $3 || $4 != "" || $6 != "" {
+      yylex.Error("syntax error")
+      return 1
+    }
+    $$ = $5
+  }
+
+trigger_time:
+  BEFORE
+  {
+    $$ = "before"
+  }
+| AFTER
+  {
+    $$ = "after"
+  }
+
+trigger_event:
+  INSERT
+  {
+    $$ = "insert"
+  }
+| UPDATE
+  {
+    $$ = "update"
+  }
+| DELETE
+  {
+    $$ = "delete"
+  }
+
+trigger_order:
+  FOLLOWS
+  {
+    $$ = "follows"
+  }
+| PRECEDES
+  {
+    $$ = "precedes"
+  }
+
+procedure_param_list_opt:
+  {
+    $$ = nil
+  }
+| procedure_param_list
+  {
+    $$ = $1
+  }
+
+procedure_param_list:
+  procedure_param
+  {
+    $$ = []*RoutineParam{$1}
+  }
+| procedure_param_list ',' procedure_param
+  {
+    $$ = append($1, $3)
+  }
+
+procedure_param:
+  sql_id column_type
+  {
+    $$ = &RoutineParam{Name: $1, Type: $2}
+  }
+| IN sql_id column_type
+  {
+    $$ = &RoutineParam{Mode: "in", Name: $2, Type: $3}
+  }
+| OUT sql_id column_type
+  {
+    $$ = &RoutineParam{Mode: "out", Name: $2, Type: $3}
+  }
+| INOUT sql_id column_type
+  {
+    $$ = &RoutineParam{Mode: "inout", Name: $2, Type: $3}
+  }
+
+function_param_list_opt:
+  {
+    $$ = nil
+  }
+| function_param_list
+  {
+    $$ = $1
+  }
+
+function_param_list:
+  function_param
+  {
+    $$ = []*RoutineParam{$1}
+  }
+| function_param_list ',' function_param
+  {
+    $$ = append($1, $3)
+  }
+
+function_param:
+  sql_id column_type
+  {
+    $$ = &RoutineParam{Name: $1, Type: $2}
+  }
+
+routine_characteristic_list_opt:
+  {
+    $$ = nil
+  }
+| routine_characteristic_list
+  {
+    $$ = $1
+  }
+
+routine_characteristic_list:
+  routine_characteristic
+  {
+    $$ = []*RoutineCharacteristic{$1}
+  }
+| routine_characteristic_list routine_characteristic
+  {
+    $$ = append($1, $2)
+  }
+
+routine_characteristic:
+  COMMENT_KEYWORD STRING
+  {
+    $$ = &RoutineCharacteristic{Name: "comment", Comment: NewStrLiteral($2)}
+  }
+| LANGUAGE SQL
+  {
+    $$ = &RoutineCharacteristic{Name: "language sql"}
+  }
+| DETERMINISTIC
+  {
+    $$ = &RoutineCharacteristic{Name: "deterministic"}
+  }
+| NOT DETERMINISTIC
+  {
+    $$ = &RoutineCharacteristic{Name: "not deterministic"}
+  }
+| CONTAINS SQL
+  {
+    $$ = &RoutineCharacteristic{Name: "contains sql"}
+  }
+| NO SQL
+  {
+    $$ = &RoutineCharacteristic{Name: "no sql"}
+  }
+| READS SQL DATA
+  {
+    $$ = &RoutineCharacteristic{Name: "reads sql data"}
+  }
+| MODIFIES SQL DATA
+  {
+    $$ = &RoutineCharacteristic{Name: "modifies sql data"}
+  }
+| SQL SECURITY DEFINER
+  {
+    $$ = &RoutineCharacteristic{Name: "sql security definer"}
+  }
+| SQL SECURITY INVOKER
+  {
+    $$ = &RoutineCharacteristic{Name: "sql security invoker"}
+  }
+
+event_schedule:
+  AT expression
+  {
+    $$ = &EventSchedule{At: $2}
+  }
+| EVERY simple_expr sql_id event_starts_opt event_ends_opt
+  {
+    $$ = &EventSchedule{Every: $2, Unit: $3.String(), Starts: $4, Ends: $5}
+  }
+
+event_starts_opt:
+  {
+    $$ = nil
+  }
+| STARTS expression
+  {
+    $$ = $2
+  }
+
+event_ends_opt:
+  {
+    $$ = nil
+  }
+| ENDS expression
+  {
+    $$ = $2
+  }
+
+event_on_completion_opt:
+  {
+    $$ = ""
+  }
+| ON COMPLETION PRESERVE
+  {
+    $$ = "preserve"
+  }
+| ON COMPLETION NOT PRESERVE
+  {
+    $$ = "not preserve"
+  }
+
+event_status_opt:
+  {
+    $$ = ""
+  }
+| ENABLE
+  {
+    $$ = "enable"
+  }
+| DISABLE
+  {
+    $$ = "disable"
+  }
+
+event_comment_opt:
+  {
+    $$ = nil
+  }
+| COMMENT_KEYWORD STRING
+  {
+    $$ = NewStrLiteral($2)
+  }
+
+routine_body:
+  ROUTINE_BODY
+  {
+    $$ = $1
+  }
+
 replace_opt:
   {
     $$ = false
@@ -2814,6 +3088,15 @@ ratio_opt:
     $$ = NewDecimalLiteral($2)
   }
 
+dead_letters_into_opt:
+  {
+    $$ = TableName{}
+  }
+| INTO table_name
+  {
+    $$ = $2
+  }
+
 alter_commands_list:
   {
     $$ = nil
@@ -3153,6 +3436,14 @@ alter_statement:
         },
     }
   }
+| ALTER comment_opt VITESS_DEAD_LETTERS table_name RETRY row_tuple dead_letters_into_opt
+  {
+    $$ = &AlterDeadLetters{
+      Table: $4,
+      IDs: $6,
+      Into: $7,
+    }
+  }
 | ALTER comment_opt VITESS_MIGRATION STRING RETRY
   {
     $$ = &AlterMigration{
@@ -3804,6 +4095,22 @@ drop_statement:
   {
     $$ = &DropDatabase{Comments: Comments($2).Parsed(), DBName: $5, IfExists: $4}
   }
+| DROP comment_opt TRIGGER exists_opt table_name
+  {
+    $$ = &DropTrigger{Name: $5, IfExists: $4}
+  }
+| DROP comment_opt PROCEDURE exists_opt table_name
+  {
+    $$ = &DropProcedure{Name: $5, IfExists: $4}
+  }
+| DROP comment_opt FUNCTION exists_opt table_name
+  {
+    $$ = &DropFunction{Name: $5, IfExists: $4}
+  }
+| DROP comment_opt EVENT exists_opt table_name
+  {
+    $$ = &DropEvent{Name: $5, IfExists: $4}
+  }
 
 truncate_statement:
   TRUNCATE TABLE table_name
@@ -3950,6 +4257,10 @@ show_statement:
   {
     $$ = &Show{&ShowBasic{Command: VitessMigrations, Filter: $4, DbName: $3}}
   }
+| SHOW VITESS_DEAD_LETTERS FROM table_name
+  {
+    $$ = &Show{&ShowBasic{Command: VitessDeadLetters, Tbl: $4}}
+  }
 | SHOW VITESS_MIGRATION STRING LOGS
   {
     $$ = &ShowMigrationLogs{UUID: string($3)}
@@ -7380,10 +7691,12 @@ non_reserved_keyword:
 | ALWAYS
 | ARRAY
 | ASCII
+| AT
 | AUTO_INCREMENT
 | AUTOEXTEND_SIZE
 | AVG %prec FUNCTION_CALL_NON_KEYWORD
 | AVG_ROW_LENGTH
+| BEFORE
 | BEGIN
 | BIGINT
 | BIT
@@ -7414,10 +7727,12 @@ non_reserved_keyword:
 | COMMITTED
 | COMPACT
 | COMPLETE
+| COMPLETION
 | COMPONENT
 | COMPRESSED
 | COMPRESSION
 | CONNECTION
+| CONTAINS
 | COPY
 | COUNT %prec FUNCTION_CALL_NON_KEYWORD
 | CSV
@@ -7431,6 +7746,7 @@ non_reserved_keyword:
 | DEFINER
 | DEFINITION
 | DESCRIPTION
+| DETERMINISTIC
 | DIRECTORY
 | DISABLE
 | DISCARD
@@ -7440,10 +7756,12 @@ non_reserved_keyword:
 | DUMPFILE
 | DUPLICATE
 | DYNAMIC
+| EACH
 | ENABLE
 | ENCLOSED
 | ENCRYPTION
 | END
+| ENDS
 | ENFORCED
 | ENGINE
 | ENGINE_ATTRIBUTE
@@ -7452,6 +7770,7 @@ non_reserved_keyword:
 | ERROR
 | ESCAPED
 | EVENT
+| EVERY
 | EXCHANGE
 | EXCLUDE
 | EXCLUSIVE
@@ -7467,6 +7786,7 @@ non_reserved_keyword:
 | FIXED
 | FLUSH
 | FOLLOWING
+| FOLLOWS
 | FORMAT
 | FORMAT_BYTES %prec FUNCTION_CALL_NON_KEYWORD
 | FORMAT_PICO_TIME %prec FUNCTION_CALL_NON_KEYWORD
@@ -7548,6 +7868,7 @@ non_reserved_keyword:
 | LOGS
 | LONGBLOB
 | LONGTEXT
+| LOOP
 | LTRIM %prec FUNCTION_CALL_NON_KEYWORD
 | MANIFEST
 | MASTER_COMPRESSION_ALGORITHMS
@@ -7565,6 +7886,7 @@ non_reserved_keyword:
 | MIN %prec FUNCTION_CALL_NON_KEYWORD
 | MIN_ROWS
 | MODE
+| MODIFIES
 | MODIFY
 | MULTILINESTRING
 | MULTIPOINT
@@ -7601,8 +7923,10 @@ non_reserved_keyword:
 | PATH
 | PERSIST
 | PERSIST_ONLY
+| PRECEDES
 | PRECEDING
 | PREPARE
+| PRESERVE
 | PRIVILEGE_CHECKS_USER
 | PRIVILEGES
 | PROCESS
@@ -7617,6 +7941,7 @@ non_reserved_keyword:
 | QUERY
 | RANDOM
 | RATIO
+| READS
 | REAL
 | REBUILD
 | REDUNDANT
@@ -7632,6 +7957,7 @@ non_reserved_keyword:
 | REMOVE
 | REORGANIZE
 | REPAIR
+| REPEAT
 | REPEATABLE
 | RESTRICT
 | REQUIRE_ROW_FORMAT
@@ -7641,12 +7967,14 @@ non_reserved_keyword:
 | RETAIN
 | RETRY
 | RETURNING
+| RETURNS
 | REUSE
 | ROLE
 | ROLLBACK
 | ROW_FORMAT
 | RTRIM %prec FUNCTION_CALL_NON_KEYWORD
 | S3
+| SCHEDULE
 | SECONDARY
 | SECONDARY_ENGINE
 | SECONDARY_ENGINE_ATTRIBUTE
@@ -7667,6 +7995,7 @@ non_reserved_keyword:
 | SRID
 | START
 | STARTING
+| STARTS
 | STATS_AUTO_RECALC
 | STATS_PERSISTENT
 | STATS_SAMPLE_PAGES
@@ -7726,6 +8055,7 @@ non_reserved_keyword:
 | VINDEXES
 | VISIBLE
 | VITESS
+| VITESS_DEAD_LETTERS
 | VITESS_KEYSPACES
 | VITESS_METADATA
 | VITESS_MIGRATION
@@ -7739,6 +8069,7 @@ non_reserved_keyword:
 | WAIT_FOR_EXECUTED_GTID_SET %prec FUNCTION_CALL_NON_KEYWORD
 | WAIT_UNTIL_SQL_THREAD_AFTER_GTIDS %prec FUNCTION_CALL_NON_KEYWORD
 | WARNINGS
+| WHILE
 | WITHOUT
 | WORK
 | YEAR
@@ -44,6 +44,7 @@ type Tokenizer struct {
 	nesting        int
 	multi          bool
 	specialComment *Tokenizer
+	routine        routineHeader
 
 	Pos int
 	buf string
@@ -67,11 +68,13 @@ func (tkn *Tokenizer) Lex(lval *yySymType) int {
 		return tkn.skipStatement()
 	}
 
+	pos := tkn.tokenStart()
 	typ, val := tkn.Scan()
 	for typ == COMMENT {
 		if tkn.AllowComments {
 			break
 		}
+		pos = tkn.tokenStart()
 		typ, val = tkn.Scan()
 	}
 	if typ == 0 || typ == ';' || typ == LEX_ERROR {
@@ -81,6 +84,10 @@ func (tkn *Tokenizer) Lex(lval *yySymType) int {
 		// Parse function to see how this is handled.
 		tkn.partialDDL = nil
 	}
+	if tkn.routine.next(typ) {
+		// The body of a stored program is kept as is, up to the end of the statement.
+		typ, val = ROUTINE_BODY, tkn.scanRoutineBody(pos)
+	}
 	lval.str = val
 	tkn.lastToken = val
 	return typ
@@ -305,6 +312,245 @@ func (tkn *Tokenizer) skipStatement() int {
 	}
 }
 
+// tokenStart skips any whitespace before the next token and returns its position.
+// Tokens inside a special comment report the position of the comment.
+func (tkn *Tokenizer) tokenStart() int {
+	if tkn.specialComment == nil {
+		tkn.skipBlank()
+	}
+	return tkn.Pos
+}
+
+// scanRoutineBody scans the body of a stored program, which starts at the given
+// position, and returns it. The body extends to the end of the input, or to the
+// first semicolon that is not within a BEGIN ... END or a CASE ... END block.
+// The tokenizer is left at the end of the body.
+func (tkn *Tokenizer) scanRoutineBody(start int) string {
+	// In multi mode the tokenizer reports ';' as EOF, which would end the
+	// body at the first statement of a BEGIN ... END block.
+	multi := tkn.multi
+	tkn.multi = false
+	defer func() { tkn.multi = multi }()
+
+	tkn.Pos = start
+	tkn.skipBlank()
+	start = tkn.Pos
+	end := start
+	var blocks blockDepth
+	for {
+		tkn.skipBlank()
+		pos := tkn.Pos
+		typ, _ := tkn.Scan()
+		switch typ {
+		case 0, LEX_ERROR:
+			tkn.Pos = pos
+			return tkn.buf[start:end]
+		case ';':
+			if blocks.depth <= 0 {
+				tkn.Pos = pos
+				return tkn.buf[start:end]
+			}
+		}
+		blocks.update(typ)
+		end = tkn.Pos
+	}
+}
+
+// routineHeader follows the tokens of a CREATE statement for a stored program, to
+// find where its body starts. The body comes after:
+//   - the characteristics of a procedure, which follow its parameters,
+//   - the return type and the characteristics of a function,
+//   - FOR EACH ROW in a trigger, and its FOLLOWS or PRECEDES clause if any,
+//   - DO in an event.
+type routineHeader struct {
+	state routineState
+	// kind is PROCEDURE, FUNCTION, TRIGGER or EVENT
+	kind int
+	// depth is the nesting of parentheses in the parameters and the return type
+	depth int
+	// skip is the number of tokens left in the current characteristic or
+	// charset clause
+	skip int
+	prev int
+}
+
+type routineState int
+
+const (
+	routineStatementStart routineState = iota
+	routineNone
+	routineCreate
+	routineDefiner
+	routineParams
+	routineReturns
+	routineReturnType
+	routineReturnTypeLength
+	routineReturnTypeOptions
+	routineCharacteristics
+	routineTriggerRow
+	routineTriggerOrder
+	routineEventDo
+	routineBodyNext
+)
+
+// next follows the given token, and returns whether it is the first token of the
+// body of a stored program.
+func (r *routineHeader) next(typ int) bool {
+	switch typ {
+	case COMMENT:
+		return false
+	case 0, ';', LEX_ERROR:
+		*r = routineHeader{}
+		return false
+	}
+	defer func() { r.prev = typ }()
+
+	if r.skip > 0 {
+		r.skip--
+		return false
+	}
+
+	switch r.state {
+	case routineStatementStart:
+		r.state = routineNone
+		if typ == CREATE {
+			r.state = routineCreate
+		}
+	case routineCreate, routineDefiner:
+		switch typ {
+		case PROCEDURE, FUNCTION:
+			r.kind, r.state = typ, routineParams
+		case TRIGGER:
+			r.kind, r.state = typ, routineTriggerRow
+		case EVENT:
+			r.kind, r.state = typ, routineEventDo
+		case DEFINER:
+			r.state = routineDefiner
+		case '=', CURRENT_USER, '(', ')', STRING, ID, AT_ID:
+			// the definer
+			if r.state != routineDefiner {
+				r.state = routineNone
+			}
+		default:
+			r.state = routineNone
+		}
+	case routineParams:
+		switch typ {
+		case '(':
+			r.depth++
+		case ')':
+			r.depth--
+			if r.depth == 0 {
+				r.state = routineCharacteristics
+				if r.kind == FUNCTION {
+					r.state = routineReturns
+				}
+			}
+		}
+	case routineReturns:
+		r.state = routineNone
+		if typ == RETURNS {
+			r.state = routineReturnType
+		}
+	case routineReturnType:
+		r.state = routineReturnTypeLength
+	case routineReturnTypeLength:
+		// the name of the type may be followed by its length, or its values
+		switch {
+		case typ == '(':
+			r.depth++
+			return false
+		case typ == ')':
+			r.depth--
+			if r.depth == 0 {
+				r.state = routineReturnTypeOptions
+			}
+			return false
+		case r.depth > 0:
+			return false
+		}
+		r.state = routineReturnTypeOptions
+		return r.next(typ)
+	case routineReturnTypeOptions:
+		switch typ {
+		case UNSIGNED, SIGNED, ZEROFILL, BYTE, BINARY, ASCII, UNICODE:
+		case CHARSET:
+			r.skip = 1
+		case CHARACTER:
+			r.skip = 2
+		default:
+			r.state = routineCharacteristics
+			return r.characteristic(typ)
+		}
+	case routineCharacteristics:
+		return r.characteristic(typ)
+	case routineTriggerRow:
+		if typ == ROW && r.prev == EACH {
+			r.state = routineTriggerOrder
+		}
+	case routineTriggerOrder:
+		if typ == FOLLOWS || typ == PRECEDES {
+			// the body follows the name of the other trigger
+			r.state, r.skip = routineBodyNext, 1
+			return false
+		}
+		return true
+	case routineEventDo:
+		if typ == DO {
+			r.state = routineBodyNext
+		}
+	case routineBodyNext:
+		return true
+	}
+	return false
+}
+
+// characteristic follows a token after the return type of a function, or the
+// parameters of a procedure, and returns whether it is the first token of the
+// body, rather than the start of a characteristic.
+func (r *routineHeader) characteristic(typ int) bool {
+	switch typ {
+	case DETERMINISTIC:
+	case COMMENT_KEYWORD, LANGUAGE, NOT, CONTAINS, NO:
+		r.skip = 1
+	case READS, MODIFIES, SQL:
+		r.skip = 2
+	default:
+		return true
+	}
+	return false
+}
+
+// blockDepth tracks the nesting of BEGIN ... END and CASE ... END blocks in the
+// body of a stored program.
+type blockDepth struct {
+	depth int
+	prev  int
+}
+
+// update updates the depth with the next token of the body.
+func (b *blockDepth) update(typ int) {
+	switch typ {
+	case BEGIN:
+		b.depth++
+	case CASE:
+		// END CASE closes a CASE statement
+		if b.prev != END {
+			b.depth++
+		}
+	case END:
+		b.depth--
+	case IF, LOOP, REPEAT, WHILE:
+		// END IF, END LOOP, etc. do not close a BEGIN ... END block
+		if b.prev == END {
+			b.depth++
+		}
+	}
+	if typ != COMMENT {
+		b.prev = typ
+	}
+}
+
 // skipBlank skips the cursor while it finds whitespace
 func (tkn *Tokenizer) skipBlank() {
 	ch := tkn.cur()
@@ -17,9 +17,13 @@ limitations under the License.
 package k8stopo
 
 import (
+	"context"
 	"path"
+	"sync"
+	"time"
 
-	"context"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/topo"
@@ -40,9 +44,9 @@ func (s *Server) NewLeaderParticipation(name, id string) (topo.LeaderParticipati
 
 // kubernetesLeaderParticipation implements topo.LeaderParticipation.
 //
-// We use a directory (in global election path, with the name) with
-// ephemeral files in it, that contains the id.  The oldest revision
-// wins the election.
+// The leader holds a coordination.k8s.io Lease named after the election, with
+// its id recorded on the Lease. The Lease is renewed for as long as we are the
+// leader; if the process dies, another participant takes over once it expires.
 type kubernetesLeaderParticipation struct {
 	// s is our parent kubernetes topo Server
 	s *Server
@@ -61,7 +65,7 @@ type kubernetesLeaderParticipation struct {
 }
 
 func (mp *kubernetesLeaderParticipation) getElectionPath() string {
-	return path.Join(mp.s.root, electionsPath, mp.name)
+	return path.Join(electionsPath, mp.name)
 }
 
 // WaitForLeadership is part of the topo.LeaderParticipation interface.
@@ -74,32 +78,61 @@ func (mp *kubernetesLeaderParticipation) WaitForLeadership() (context.Context, e
 	}
 
 	electionPath := mp.getElectionPath()
-	var ld topo.LockDescriptor
+
+	var (
+		mu sync.Mutex
+		ld *kubernetesLockDescriptor
+	)
 
 	// We use a cancelable context here. If stop is closed,
 	// we just cancel that context.
 	lockCtx, lockCancel := context.WithCancel(context.Background())
 	go func() {
 		<-mp.stop
-		if ld != nil {
-			if err := ld.Unlock(context.Background()); err != nil {
+
+		mu.Lock()
+		lockCancel()
+		held := ld
+		mu.Unlock()
+
+		if held != nil {
+			if err := held.Unlock(context.Background()); err != nil {
 				log.Errorf("failed to unlock electionPath %v: %v", electionPath, err)
 			}
 		}
-		lockCancel()
 		close(mp.done)
 	}()
 
 	// Try to get the primaryship, by getting a lock.
-	var err error
-	ld, err = mp.s.lock(lockCtx, electionPath, mp.id, true)
+	held, err := mp.s.lock(lockCtx, electionPath, mp.id, true)
 	if err != nil {
 		// It can be that we were interrupted.
 		return nil, err
 	}
 
+	mu.Lock()
+	if lockCtx.Err() != nil {
+		// Stop was called as we were acquiring the lease.
+		mu.Unlock()
+		if err := held.Unlock(context.Background()); err != nil {
+			log.Errorf("failed to unlock electionPath %v: %v", electionPath, err)
+		}
+		return nil, topo.NewError(topo.Interrupted, "Leadership")
+	}
+	ld = held
+	mu.Unlock()
+
+	// If the lease is taken from us, we are no longer the leader.
+	go func() {
+		select {
+		case <-held.lost:
+			lockCancel()
+		case <-lockCtx.Done():
+		}
+	}()
+
 	// We got the lock. Return the lockContext. If Stop() is called,
-	// it will cancel the lockCtx, and cancel the returned context.
+	// or the lease is lost, the returned context is canceled.
 	return lockCtx, nil
 }
 
@@ -111,13 +144,21 @@ func (mp *kubernetesLeaderParticipation) Stop() {
 
 // GetCurrentLeaderID is part of the topo.LeaderParticipation interface
 func (mp *kubernetesLeaderParticipation) GetCurrentLeaderID(ctx context.Context) (string, error) {
-	id, _, err := mp.s.Get(ctx, mp.getElectionPath())
+	name, key := mp.s.leaseName(mp.getElectionPath())
+
+	lease, err := mp.s.leaseClient.Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		// NoNode means nobody is the primary
-		if topo.IsErrType(err, topo.NoNode) {
+		// NotFound means nobody is the primary
+		if errors.IsNotFound(err) {
 			return "", nil
 		}
-		return "", err
+		return "", convertError(err, key)
+	}
+
+	// Neither does an expired lease.
+	if leaseExpired(lease, time.Now()) {
+		return "", nil
 	}
-	return string(id), nil
+
+	return lease.Annotations[leaseContentsAnnotation], nil
 }
@@ -17,22 +17,58 @@ limitations under the License.
 package k8stopo
 
 import (
-	"time"
-
 	"context"
+	"flag"
+	"fmt"
+	"time"
 
+	"github.com/google/uuid"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/topo"
-	vtv1beta1 "vitess.io/vitess/go/vt/topo/k8stopo/apis/topo/v1beta1"
 )
 
+var (
+	// leaseTTL is the duration, in seconds, of the coordination.k8s.io Leases
+	// backing locks and leader election.
+	leaseTTL = flag.Int("topo_k8s_lease_ttl", 30, "Lease TTL in seconds for locks and leader election. Leases are renewed every third of the TTL, and an expired lease may be taken over by another client.")
+)
+
+const (
+	// leaseKeyAnnotation records the topo path a Lease was taken on, since
+	// Lease names are hashed.
+	leaseKeyAnnotation = "vitess.io/topo-key"
+
+	// leaseContentsAnnotation records the contents given to Lock, or the id
+	// of the leader for elections.
+	leaseContentsAnnotation = "vitess.io/topo-contents"
+
+	// leaseRetryInterval is how often a blocked Lock checks whether the
+	// Lease was released or expired.
+	leaseRetryInterval = 10 * time.Millisecond
+)
+
+// errLeaseLost is returned when a Lease we held was deleted or taken over.
+var errLeaseLost = fmt.Errorf("lease lost")
+
 // kubernetesLockDescriptor implements topo.LockDescriptor.
 type kubernetesLockDescriptor struct {
 	s         *Server
-	leaseID   string
+	leaseName string
 	leasePath string
+	holder    string
+	ttl       time.Duration
+
+	// cancel stops the renewal loop, which closes done when it exits.
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// lost is closed by the renewal loop if the Lease is deleted or taken
+	// over by another holder.
+	lost chan struct{}
 }
 
 // Lock is part of the topo.Conn interface.
@@ -40,9 +76,16 @@ func (s *Server) Lock(ctx context.Context, dirPath, contents string) (topo.LockD
 	return s.lock(ctx, dirPath, contents, false)
 }
 
+// leaseName returns the name of the Lease guarding nodePath, and the full key
+// it is derived from.
+func (s *Server) leaseName(nodePath string) (string, string) {
+	node := s.newNodeReference(nodePath)
+	return fmt.Sprintf("%s-lock", node.id), node.key
+}
+
 // lock is used by both Lock() and primary election.
 // it blocks until the lock is taken, interrupted, or times out
-func (s *Server) lock(ctx context.Context, nodePath, contents string, createMissing bool) (topo.LockDescriptor, error) {
+func (s *Server) lock(ctx context.Context, nodePath, contents string, createMissing bool) (*kubernetesLockDescriptor, error) {
 	// Satisfy the topo.Conn interface
 	if !createMissing {
 		// Per the topo.Conn interface:
@@ -53,53 +96,189 @@ func (s *Server) lock(ctx context.Context, nodePath, contents string, createMiss
 		}
 	}
 
-	resource, err := s.buildFileResource(nodePath, []byte(contents))
-	if err != nil {
-		return nil, convertError(err, nodePath)
+	name, key := s.leaseName(nodePath)
+	ld := &kubernetesLockDescriptor{
+		s:         s,
+		leaseName: name,
+		leasePath: key,
+		holder:    uuid.New().String(),
+		ttl:       time.Duration(*leaseTTL) * time.Second,
+		done:      make(chan struct{}),
+		lost:      make(chan struct{}),
 	}
 
-	// mark locks as ephemeral
-	resource.Data.Ephemeral = true
-
-	var final *vtv1beta1.VitessTopoNode
-
 	for {
-		// Try and and create the resource. The kube api will handle the actual atomic lock creation
-		final, err = s.resourceClient.Create(ctx, resource, metav1.CreateOptions{})
-		if errors.IsAlreadyExists(err) {
-			select {
-			case <-time.After(10 * time.Millisecond):
-				continue // retry
-			case <-ctx.Done():
-				return nil, convertError(ctx.Err(), nodePath)
-			}
-		} else if err != nil {
+		acquired, err := ld.tryAcquire(ctx, contents)
+		if ctx.Err() != nil {
+			return nil, convertError(ctx.Err(), nodePath)
+		}
+		if err != nil {
 			return nil, convertError(err, nodePath)
 		}
+		if acquired {
+			break
+		}
 
-		break
+		select {
+		case <-time.After(leaseRetryInterval):
+		case <-ctx.Done():
+			return nil, convertError(ctx.Err(), nodePath)
+		}
 	}
 
-	// Update the internal cache
-	err = s.memberIndexer.Update(final)
+	var renewCtx context.Context
+	renewCtx, ld.cancel = context.WithCancel(context.Background())
+	go ld.renew(renewCtx)
+
+	return ld, nil
+}
+
+// leaseExpired returns true if the lease was not renewed within its duration.
+func leaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+
+	return now.After(lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second))
+}
+
+// tryAcquire creates the Lease, or takes it over if its holder let it expire.
+// It returns false if the Lease is currently held by someone else.
+func (ld *kubernetesLockDescriptor) tryAcquire(ctx context.Context, contents string) (bool, error) {
+	now := metav1.NewMicroTime(time.Now())
+	ttl := int32(ld.ttl / time.Second)
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ld.leaseName,
+			Namespace: ld.s.namespace,
+			Annotations: map[string]string{
+				leaseKeyAnnotation:      ld.leasePath,
+				leaseContentsAnnotation: contents,
+			},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &ld.holder,
+			LeaseDurationSeconds: &ttl,
+			AcquireTime:          &now,
+			RenewTime:            &now,
+		},
+	}
+
+	// The kube api will handle the actual atomic lock creation
+	_, err := ld.s.leaseClient.Create(ctx, lease, metav1.CreateOptions{})
+	switch {
+	case err == nil:
+		return true, nil
+	case !errors.IsAlreadyExists(err):
+		return false, err
+	}
+
+	current, err := ld.s.leaseClient.Get(ctx, ld.leaseName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		// Released since we tried to create it, try again.
+		return false, nil
+	case err != nil:
+		return false, err
+	case !leaseExpired(current, time.Now()):
+		return false, nil
+	}
+
+	log.Warningf("Taking over expired lease %v on %v from %v", ld.leaseName, ld.leasePath, holderOf(current))
+
+	// The update carries the resource version we just read, so when several
+	// clients race for an expired lease only one of them wins.
+	transitions := int32(1)
+	if current.Spec.LeaseTransitions != nil {
+		transitions += *current.Spec.LeaseTransitions
+	}
+	current.Annotations = lease.Annotations
+	current.Spec = lease.Spec
+	current.Spec.LeaseTransitions = &transitions
+
+	_, err = ld.s.leaseClient.Update(ctx, current, metav1.UpdateOptions{})
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.IsConflict(err), errors.IsNotFound(err):
+		return false, nil
+	}
+
+	return false, err
+}
+
+func holderOf(lease *coordinationv1.Lease) string {
+	if lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+
+	return *lease.Spec.HolderIdentity
+}
+
+// get returns the Lease if we still hold it, and errLeaseLost otherwise.
+func (ld *kubernetesLockDescriptor) get(ctx context.Context) (*coordinationv1.Lease, error) {
+	lease, err := ld.s.leaseClient.Get(ctx, ld.leaseName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, errLeaseLost
+	}
 	if err != nil {
-		return nil, convertError(err, nodePath)
+		return nil, err
+	}
+	if holderOf(lease) != ld.holder {
+		return nil, errLeaseLost
 	}
 
-	return &kubernetesLockDescriptor{
-		s:         s,
-		leaseID:   resource.Name,
-		leasePath: resource.Data.Key,
-	}, nil
+	return lease, nil
+}
+
+// renew keeps the Lease alive until ctx is canceled, by bumping its renew time
+// every third of its duration. If the Lease is lost, it closes ld.lost and
+// stops. Transient errors are retried on the next tick; if they last longer
+// than the TTL the Lease expires and can be taken over, which the next renewal
+// then detects.
+func (ld *kubernetesLockDescriptor) renew(ctx context.Context) {
+	defer close(ld.done)
+
+	ticker := time.NewTicker(ld.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		lease, err := ld.get(ctx)
+		if err == nil {
+			now := metav1.NewMicroTime(time.Now())
+			lease.Spec.RenewTime = &now
+			_, err = ld.s.leaseClient.Update(ctx, lease, metav1.UpdateOptions{})
+		}
+
+		switch {
+		case err == nil, ctx.Err() != nil:
+		case err == errLeaseLost:
+			log.Errorf("Lost lease %v on %v", ld.leaseName, ld.leasePath)
+			close(ld.lost)
+			return
+		default:
+			log.Warningf("Failed to renew lease %v on %v: %v", ld.leaseName, ld.leasePath, err)
+		}
+	}
 }
 
 // Check is part of the topo.LockDescriptor interface.
 func (ld *kubernetesLockDescriptor) Check(ctx context.Context) error {
-	// Get the object and ensure the leaseid
-	_, err := ld.s.resourceClient.Get(ctx, ld.leaseID, metav1.GetOptions{}) // TODO namespacing
+	lease, err := ld.get(ctx)
+	if err == errLeaseLost {
+		return topo.NewError(topo.NoNode, ld.leasePath)
+	}
 	if err != nil {
 		return convertError(err, ld.leasePath)
-
+	}
+	if leaseExpired(lease, time.Now()) {
+		return topo.NewError(topo.Timeout, ld.leasePath)
 	}
 
 	return nil
@@ -107,9 +286,24 @@ func (ld *kubernetesLockDescriptor) Check(ctx context.Context) error {
 
 // Unlock is part of the topo.LockDescriptor interface.
 func (ld *kubernetesLockDescriptor) Unlock(ctx context.Context) error {
-	err := ld.s.resourceClient.Delete(ctx, ld.leaseID, metav1.DeleteOptions{}) // TODO namespacing
+	ld.cancel()
+	<-ld.done
+
+	lease, err := ld.get(ctx)
+	if err == errLeaseLost {
+		return topo.NewError(topo.NoNode, ld.leasePath)
+	}
+	if err != nil {
+		return convertError(err, ld.leasePath)
+	}
+
+	// Only delete the version we just checked we hold.
+	err = ld.s.leaseClient.Delete(ctx, ld.leaseName, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &lease.ResourceVersion},
+	})
 	if err != nil {
 		return convertError(err, ld.leasePath)
 	}
+
 	return nil
 }
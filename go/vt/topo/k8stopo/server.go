@@ -28,14 +28,18 @@ We follow these conventions within this package:
 package k8stopo
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"k8s.io/apimachinery/pkg/fields"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
+	coordinationv1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
@@ -82,6 +86,9 @@ type Server struct {
 	// resource is a scoped-down kubernetes.Interface used for convenience
 	resourceClient vttyped.VitessTopoNodeInterface
 
+	// leaseClient is used for locks and leader election
+	leaseClient coordinationv1.LeaseInterface
+
 	// stopChan is used to tell the client-go informers to quit
 	stopChan chan struct{}
 
@@ -121,8 +128,14 @@ func indexByParent(obj any) ([]string, error) {
 // syncTree starts and syncs the member objects that form the directory "tree"
 func (s *Server) syncTree() error {
 	// Create the informer / indexer
-	restClient := s.vtKubeClient.TopoV1beta1().RESTClient()
-	listwatch := cache.NewListWatchFromClient(restClient, "vitesstoponodes", s.namespace, fields.Everything())
+	listwatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return s.resourceClient.List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return s.resourceClient.Watch(context.Background(), options)
+		},
+	}
 
 	// set up index funcs
 	indexers := cache.Indexers{}
@@ -211,18 +224,24 @@ func NewServer(_, root string) (*Server, error) {
 		return nil, fmt.Errorf("error creating vitess Kubernetes client: %s", err)
 	}
 
+	return newServer(kubeClientset, vtKubeClientset, namespace, root)
+}
+
+// newServer returns a new k8stopo.Server using the given clients.
+func newServer(kubeClient kubernetes.Interface, vtKubeClient vtkube.Interface, namespace, root string) (*Server, error) {
 	// Create the server
 	s := &Server{
 		namespace:      namespace,
-		kubeClient:     kubeClientset,
-		vtKubeClient:   vtKubeClientset,
-		resourceClient: vtKubeClientset.TopoV1beta1().VitessTopoNodes(namespace),
+		kubeClient:     kubeClient,
+		vtKubeClient:   vtKubeClient,
+		resourceClient: vtKubeClient.TopoV1beta1().VitessTopoNodes(namespace),
+		leaseClient:    kubeClient.CoordinationV1().Leases(namespace),
 		root:           root,
 		stopChan:       make(chan struct{}),
 	}
 
 	// Sync cache
-	if err = s.syncTree(); err != nil {
+	if err := s.syncTree(); err != nil {
 		return nil, err
 	}
 
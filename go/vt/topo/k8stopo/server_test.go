@@ -0,0 +1,257 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stopo
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	vtkubefake "vitess.io/vitess/go/vt/topo/k8stopo/client/clientset/versioned/fake"
+	"vitess.io/vitess/go/vt/topo/test"
+)
+
+// fakeFactory is a topo.Factory whose servers share a pair of fake clientsets,
+// the way real servers share an API server.
+type fakeFactory struct {
+	kubeClient   *kubefake.Clientset
+	vtKubeClient *vtkubefake.Clientset
+}
+
+func newFakeFactory() *fakeFactory {
+	f := &fakeFactory{
+		kubeClient:   kubefake.NewSimpleClientset(),
+		vtKubeClient: vtkubefake.NewSimpleClientset(),
+	}
+
+	// The object trackers behind fake clientsets neither assign resource
+	// versions nor reject stale updates, both of which we rely on.
+	var resourceVersion int64
+	for _, fake := range []struct {
+		*k8stesting.Fake
+		tracker k8stesting.ObjectTracker
+	}{
+		{&f.kubeClient.Fake, f.kubeClient.Tracker()},
+		{&f.vtKubeClient.Fake, f.vtKubeClient.Tracker()},
+	} {
+		tracker := fake.tracker
+		fake.PrependReactor("create", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			obj, err := meta.Accessor(action.(k8stesting.CreateAction).GetObject())
+			if err != nil {
+				return true, nil, err
+			}
+
+			resourceVersion++
+			obj.SetResourceVersion(strconv.FormatInt(resourceVersion, 10))
+
+			return false, nil, nil
+		})
+		fake.PrependReactor("update", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			update := action.(k8stesting.UpdateAction)
+			obj, err := meta.Accessor(update.GetObject())
+			if err != nil {
+				return true, nil, err
+			}
+
+			current, err := tracker.Get(update.GetResource(), update.GetNamespace(), obj.GetName())
+			if err != nil {
+				return true, nil, err
+			}
+			currentMeta, err := meta.Accessor(current)
+			if err != nil {
+				return true, nil, err
+			}
+			if obj.GetResourceVersion() != currentMeta.GetResourceVersion() {
+				return true, nil, errors.NewConflict(update.GetResource().GroupResource(), obj.GetName(), fmt.Errorf("stale resource version %v", obj.GetResourceVersion()))
+			}
+
+			resourceVersion++
+			obj.SetResourceVersion(strconv.FormatInt(resourceVersion, 10))
+
+			return false, nil, nil
+		})
+	}
+
+	return f
+}
+
+// HasGlobalReadOnlyCell is part of the topo.Factory interface.
+func (f *fakeFactory) HasGlobalReadOnlyCell(serverAddr, root string) bool {
+	return false
+}
+
+// Create is part of the topo.Factory interface.
+func (f *fakeFactory) Create(cell, serverAddr, root string) (topo.Conn, error) {
+	return newServer(f.kubeClient, f.vtKubeClient, "default", root)
+}
+
+func TestKubernetesTopoFake(t *testing.T) {
+	factory := newFakeFactory()
+
+	// Run the test suite.
+	testIndex := 0
+	test.TopoServerTestSuite(t, func() *topo.Server {
+		// Each test will use its own sub-directories.
+		testRoot := fmt.Sprintf("/test-%v", testIndex)
+		testIndex++
+
+		globalRoot := path.Join(testRoot, topo.GlobalCell)
+		cellRoot := path.Join(testRoot, test.LocalCellName)
+
+		ts, err := topo.NewWithFactory(factory, "", globalRoot)
+		if err != nil {
+			t.Fatalf("NewWithFactory() failed: %v", err)
+		}
+		if err := ts.CreateCellInfo(context.Background(), test.LocalCellName, &topodatapb.CellInfo{
+			Root: cellRoot,
+		}); err != nil {
+			t.Fatalf("CreateCellInfo() failed: %v", err)
+		}
+
+		return ts
+	})
+}
+
+func TestLeaseExpiry(t *testing.T) {
+	defer func(ttl int) { *leaseTTL = ttl }(*leaseTTL)
+	*leaseTTL = 1
+
+	ctx := context.Background()
+	factory := newFakeFactory()
+
+	conn, err := factory.Create(topo.GlobalCell, "", "/test")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	s := conn.(*Server)
+
+	// A held lease is renewed past its TTL.
+	ld, err := s.lock(ctx, "keyspaces/ks", "first", true)
+	require.NoError(t, err)
+
+	time.Sleep(1500 * time.Millisecond)
+	require.NoError(t, ld.Check(ctx))
+
+	fastCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = s.lock(fastCtx, "keyspaces/ks", "second", true)
+	require.True(t, topo.IsErrType(err, topo.Timeout), "lock should time out while the lease is held, got %v", err)
+
+	// Once the holder stops renewing, the lease expires and is taken over.
+	ld.cancel()
+	<-ld.done
+
+	ld2, err := s.lock(ctx, "keyspaces/ks", "second", true)
+	require.NoError(t, err)
+	defer ld2.Unlock(ctx)
+
+	require.True(t, topo.IsErrType(ld.Check(ctx), topo.NoNode), "the previous holder should have lost the lease")
+	require.Error(t, ld.Unlock(ctx))
+
+	lease, err := s.leaseClient.Get(ctx, ld2.leaseName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "second", lease.Annotations[leaseContentsAnnotation])
+	assert.Equal(t, int32(1), *lease.Spec.LeaseTransitions)
+}
+
+func TestWatchResume(t *testing.T) {
+	ctx := context.Background()
+	factory := newFakeFactory()
+
+	// Record the resource version each file watch starts from, and keep the
+	// watchers around so we can drop them.
+	var (
+		mu       sync.Mutex
+		versions []string
+		watchers []watch.Interface
+	)
+	tracker := factory.vtKubeClient.Tracker()
+	factory.vtKubeClient.PrependWatchReactor("vitesstoponodes", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		restrictions := action.(k8stesting.WatchAction).GetWatchRestrictions()
+		if restrictions.Fields == nil || restrictions.Fields.Empty() {
+			// The informer backing ListDir.
+			return false, nil, nil
+		}
+
+		w, err := tracker.Watch(action.GetResource(), action.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		versions = append(versions, restrictions.ResourceVersion)
+		watchers = append(watchers, w)
+
+		return true, w, nil
+	})
+
+	conn, err := factory.Create(topo.GlobalCell, "", "/test")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	v1, err := conn.Create(ctx, "file", []byte("1"))
+	require.NoError(t, err)
+
+	current, changes, cancel := conn.Watch(ctx, "file")
+	require.NoError(t, current.Err)
+	defer cancel()
+
+	v2, err := conn.Update(ctx, "file", []byte("2"), v1)
+	require.NoError(t, err)
+
+	wd := <-changes
+	require.NoError(t, wd.Err)
+	assert.Equal(t, "2", string(wd.Contents))
+
+	// Drop the watch, as a lost connection to the API server would.
+	mu.Lock()
+	watchers[0].Stop()
+	mu.Unlock()
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(versions) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err = conn.Update(ctx, "file", []byte("3"), v2)
+	require.NoError(t, err)
+
+	wd = <-changes
+	require.NoError(t, wd.Err)
+	assert.Equal(t, "3", string(wd.Contents))
+
+	// The watch was resumed from the last version we saw, not re-listed.
+	assert.Equal(t, []string{v1.String(), v2.String()}, versions)
+}
@@ -18,15 +18,22 @@ package k8stopo
 
 import (
 	"context"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
-	"k8s.io/client-go/tools/cache"
+	"k8s.io/apimachinery/pkg/watch"
 
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/topo"
 	vtv1beta1 "vitess.io/vitess/go/vt/topo/k8stopo/apis/topo/v1beta1"
 )
 
+// watchRetryInterval is how long to wait before re-establishing a watch that
+// failed to open.
+var watchRetryInterval = time.Second
+
 // Watch is part of the topo.Conn interface.
 func (s *Server) Watch(ctx context.Context, filePath string) (*topo.WatchData, <-chan *topo.WatchData, topo.CancelFunc) {
 	log.Info("Starting Kubernetes topo Watch on ", filePath)
@@ -34,96 +41,188 @@ func (s *Server) Watch(ctx context.Context, filePath string) (*topo.WatchData, <
 	current := &topo.WatchData{}
 
 	// get current
-	contents, ver, err := s.Get(ctx, filePath)
+	node := s.newNodeReference(filePath)
+	result, err := s.resourceClient.Get(ctx, node.id, metav1.GetOptions{})
 	if err != nil {
 		// Per the topo.Conn interface:
 		// "If the initial read fails, or the file doesn't
 		// exist, current.Err is set, and 'changes'/'cancel' are nil."
-		current.Err = err
+		current.Err = convertError(err, filePath)
+		return current, nil, nil
+	}
+
+	contents, err := unpackValue([]byte(result.Data.Value))
+	if err != nil {
+		current.Err = convertError(err, filePath)
 		return current, nil, nil
 	}
 	current.Contents = contents
-	current.Version = ver
+	current.Version = KubernetesVersion(result.GetResourceVersion())
 
 	// Create a context, will be used to cancel the watch.
 	watchCtx, watchCancel := context.WithCancel(context.Background())
 
-	// Create the changes channel
-	changes := make(chan *topo.WatchData, 10)
-
-	// Create a signal channel for non-interrupt shutdowns
-	gracefulShutdown := make(chan struct{})
-
-	resource, err := s.buildFileResource(filePath, []byte{})
+	// Open the first watch before returning, so that changes made right after
+	// we return are seen.
+	w, err := s.openWatch(watchCtx, node.id, result.GetResourceVersion())
 	if err != nil {
-		// Per the topo.Conn interface:
-		// current.Err is set, and 'changes'/'cancel' are nil
 		watchCancel()
-		current.Err = err
+		current.Err = convertError(err, filePath)
 		return current, nil, nil
 	}
 
-	// Create the informer / indexer to watch the single resource
-	restClient := s.vtKubeClient.TopoV1beta1().RESTClient()
-	listwatch := cache.NewListWatchFromClient(restClient, "vitesstoponodes", s.namespace, fields.OneTermEqualSelector("metadata.name", resource.Name))
+	// Create the changes channel
+	changes := make(chan *topo.WatchData, 10)
+
+	go s.watchNode(watchCtx, filePath, node.id, result.GetResourceVersion(), w, changes)
 
-	// set up index funcs
-	indexers := cache.Indexers{}
-	indexers["by_parent"] = indexByParent
+	return current, changes, topo.CancelFunc(watchCancel)
+}
 
-	_, memberInformer := cache.NewIndexerInformer(listwatch, &vtv1beta1.VitessTopoNode{}, 0,
-		cache.ResourceEventHandlerFuncs{
-			AddFunc: func(obj any) {
-				vtn := obj.(*vtv1beta1.VitessTopoNode)
-				out, err := unpackValue([]byte(vtn.Data.Value))
-				if err != nil {
-					changes <- &topo.WatchData{Err: err}
-					close(gracefulShutdown)
-				} else {
-					changes <- &topo.WatchData{
-						Contents: out,
-						Version:  KubernetesVersion(vtn.GetResourceVersion()),
-					}
+// openWatch watches the VitessTopoNode with the given name for changes made
+// after resourceVersion.
+func (s *Server) openWatch(ctx context.Context, name, resourceVersion string) (watch.Interface, error) {
+	return s.resourceClient.Watch(ctx, metav1.ListOptions{
+		FieldSelector:       fields.OneTermEqualSelector("metadata.name", name).String(),
+		ResourceVersion:     resourceVersion,
+		AllowWatchBookmarks: true,
+	})
+}
+
+// watchNode sends the changes to a single VitessTopoNode seen by w, and its
+// successors, until the node is deleted or ctx is canceled, and then closes
+// changes.
+//
+// When the underlying watch ends, for example because the connection to the
+// API server dropped, it is re-opened from the last resource version we saw,
+// so no change is missed and nothing is re-listed. Only if that version is too
+// old to resume from do we re-read the node.
+func (s *Server) watchNode(ctx context.Context, filePath, name, resourceVersion string, w watch.Interface, changes chan<- *topo.WatchData) {
+	defer close(changes)
+
+	for {
+		var done bool
+		resourceVersion, done = s.consumeWatch(ctx, w, filePath, name, resourceVersion, changes)
+		w.Stop()
+
+		if done {
+			return
+		}
+
+		for {
+			var err error
+			if w, err = s.openWatch(ctx, name, resourceVersion); err == nil {
+				break
+			}
+
+			if ctx.Err() == nil {
+				log.Warningf("Kubernetes topo Watch on %v failed, retrying in %v: %v", filePath, watchRetryInterval, err)
+			}
+
+			select {
+			case <-time.After(watchRetryInterval):
+			case <-ctx.Done():
+				changes <- &topo.WatchData{Err: topo.NewError(topo.Interrupted, filePath)}
+				return
+			}
+		}
+	}
+}
+
+// consumeWatch forwards the events of a single watch to changes, and returns
+// the last resource version seen. It returns true once the caller should stop
+// watching, after sending the final event.
+func (s *Server) consumeWatch(ctx context.Context, w watch.Interface, filePath, name, resourceVersion string, changes chan<- *topo.WatchData) (string, bool) {
+	for {
+		select {
+		case <-ctx.Done():
+			changes <- &topo.WatchData{Err: topo.NewError(topo.Interrupted, filePath)}
+			return resourceVersion, true
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				// The watch was closed under us, resume it.
+				return resourceVersion, false
+			}
+
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				vtn, ok := event.Object.(*vtv1beta1.VitessTopoNode)
+				if !ok || vtn.Name != name {
+					continue
 				}
-			},
-			UpdateFunc: func(oldObj, newObj any) {
-				vtn := newObj.(*vtv1beta1.VitessTopoNode)
+
+				resourceVersion = vtn.GetResourceVersion()
+
 				out, err := unpackValue([]byte(vtn.Data.Value))
 				if err != nil {
 					changes <- &topo.WatchData{Err: err}
-					close(gracefulShutdown)
-				} else {
-					changes <- &topo.WatchData{
-						Contents: out,
-						Version:  KubernetesVersion(vtn.GetResourceVersion()),
-					}
+					return resourceVersion, true
 				}
-			},
-			DeleteFunc: func(obj any) {
-				vtn := obj.(*vtv1beta1.VitessTopoNode)
-				changes <- &topo.WatchData{Err: topo.NewError(topo.NoNode, vtn.Name)}
-				close(gracefulShutdown)
-			},
-		}, indexers)
 
-	// create control chan for informer and start it
-	informerChan := make(chan struct{})
-	go memberInformer.Run(informerChan)
+				changes <- &topo.WatchData{
+					Contents: out,
+					Version:  KubernetesVersion(resourceVersion),
+				}
+			case watch.Deleted:
+				vtn, ok := event.Object.(*vtv1beta1.VitessTopoNode)
+				if !ok || vtn.Name != name {
+					continue
+				}
 
-	// Handle interrupts
-	go closeOnDone(watchCtx, filePath, informerChan, gracefulShutdown, changes)
+				changes <- &topo.WatchData{Err: topo.NewError(topo.NoNode, filePath)}
+				return resourceVersion, true
+			case watch.Bookmark:
+				if vtn, ok := event.Object.(*vtv1beta1.VitessTopoNode); ok {
+					resourceVersion = vtn.GetResourceVersion()
+				}
+			case watch.Error:
+				err := errors.FromObject(event.Object)
+				if !errors.IsResourceExpired(err) && !errors.IsGone(err) {
+					log.Warningf("Kubernetes topo Watch on %v returned an error, resuming: %v", filePath, err)
+					return resourceVersion, false
+				}
 
-	return current, changes, topo.CancelFunc(watchCancel)
+				// Our resource version was compacted away, re-read the node
+				// and resume from its current version.
+				return s.resyncWatch(ctx, filePath, name, resourceVersion, changes)
+			}
+		}
+	}
 }
 
-func closeOnDone(ctx context.Context, filePath string, informerChan chan struct{}, gracefulShutdown chan struct{}, changes chan *topo.WatchData) {
-	select {
-	case <-ctx.Done():
-		if err := ctx.Err(); err != nil && err == context.Canceled {
+// resyncWatch re-reads a watched node after its resource version expired,
+// sending its contents if they changed since resourceVersion.
+func (s *Server) resyncWatch(ctx context.Context, filePath, name, resourceVersion string, changes chan<- *topo.WatchData) (string, bool) {
+	result, err := s.resourceClient.Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		changes <- &topo.WatchData{Err: topo.NewError(topo.NoNode, filePath)}
+		return resourceVersion, true
+	case err != nil:
+		if ctx.Err() != nil {
 			changes <- &topo.WatchData{Err: topo.NewError(topo.Interrupted, filePath)}
+			return resourceVersion, true
 		}
-	case <-gracefulShutdown:
+
+		// Resuming from the expired version will fail again and bring us
+		// back here.
+		log.Warningf("Kubernetes topo Watch on %v failed to re-read the node: %v", filePath, err)
+		time.Sleep(watchRetryInterval)
+		return resourceVersion, false
+	case result.GetResourceVersion() == resourceVersion:
+		return resourceVersion, false
+	}
+
+	out, err := unpackValue([]byte(result.Data.Value))
+	if err != nil {
+		changes <- &topo.WatchData{Err: err}
+		return resourceVersion, true
 	}
-	close(informerChan)
-	close(changes)
+
+	changes <- &topo.WatchData{
+		Contents: out,
+		Version:  KubernetesVersion(result.GetResourceVersion()),
+	}
+
+	return result.GetResourceVersion(), false
 }
@@ -79,16 +79,18 @@ const (
 	SrvKeyspaceFile      = "SrvKeyspace"
 	RoutingRulesFile     = "RoutingRules"
 	ExternalClustersFile = "ExternalClusters"
+	SnowflakeNodeFile    = "SnowflakeNode"
 )
 
 // Path for all object types.
 const (
-	CellsPath        = "cells"
-	CellsAliasesPath = "cells_aliases"
-	KeyspacesPath    = "keyspaces"
-	ShardsPath       = "shards"
-	TabletsPath      = "tablets"
-	MetadataPath     = "metadata"
+	CellsPath          = "cells"
+	CellsAliasesPath   = "cells_aliases"
+	KeyspacesPath      = "keyspaces"
+	ShardsPath         = "shards"
+	TabletsPath        = "tablets"
+	MetadataPath       = "metadata"
+	SnowflakeNodesPath = "snowflake_nodes"
 
 	ExternalClusterMySQL  = "mysql"
 	ExternalClusterVitess = "vitess"
@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"path"
+	"strconv"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// SnowflakeNodeInfo is a meta struct that contains metadata to give the
+// data more context and convenience. This is the main way we interact
+// with a snowflake node record stored in the topo.
+type SnowflakeNodeInfo struct {
+	NodeID  int64
+	version Version
+	*topodatapb.SnowflakeNode
+}
+
+// GetSnowflakeNodeDir returns the directory of the given snowflake node
+// ID, which is locked by the vtgate that holds it.
+func GetSnowflakeNodeDir(nodeID int64) string {
+	return path.Join(SnowflakeNodesPath, strconv.FormatInt(nodeID, 10))
+}
+
+// LockSnowflakeNode creates the record of the given snowflake node ID if
+// needed, and locks it. It blocks until the lock is acquired, or ctx
+// expires. The lock is held for as long as ctx is not canceled, so it
+// should not be a request context.
+func (ts *Server) LockSnowflakeNode(ctx context.Context, nodeID int64, holder string) (LockDescriptor, *SnowflakeNodeInfo, error) {
+	nodePath := path.Join(GetSnowflakeNodeDir(nodeID), SnowflakeNodeFile)
+	data, err := proto.Marshal(&topodatapb.SnowflakeNode{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := ts.globalCell.Create(ctx, nodePath, data); err != nil && !IsErrType(err, NodeExists) {
+		return nil, nil, err
+	}
+
+	ld, err := ts.globalCell.Lock(ctx, GetSnowflakeNodeDir(nodeID), holder)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Read the record once locked, to see the latest reservation.
+	node, err := ts.GetSnowflakeNode(ctx, nodeID)
+	if err != nil {
+		if uerr := ld.Unlock(context.Background()); uerr != nil {
+			return nil, nil, vterrors.Wrapf(err, "unlock failed: %v", uerr)
+		}
+		return nil, nil, err
+	}
+	return ld, node, nil
+}
+
+// GetSnowflakeNode returns the record of the given snowflake node ID.
+func (ts *Server) GetSnowflakeNode(ctx context.Context, nodeID int64) (*SnowflakeNodeInfo, error) {
+	data, version, err := ts.globalCell.Get(ctx, path.Join(GetSnowflakeNodeDir(nodeID), SnowflakeNodeFile))
+	if err != nil {
+		return nil, err
+	}
+	node := &topodatapb.SnowflakeNode{}
+	if err = proto.Unmarshal(data, node); err != nil {
+		return nil, vterrors.Wrap(err, "bad snowflake node data")
+	}
+
+	return &SnowflakeNodeInfo{
+		NodeID:        nodeID,
+		version:       version,
+		SnowflakeNode: node,
+	}, nil
+}
+
+// UpdateSnowflakeNode updates the record of a snowflake node ID. It fails
+// with BadVersion if the record changed since it was read.
+func (ts *Server) UpdateSnowflakeNode(ctx context.Context, node *SnowflakeNodeInfo) error {
+	data, err := proto.Marshal(node.SnowflakeNode)
+	if err != nil {
+		return err
+	}
+	version, err := ts.globalCell.Update(ctx, path.Join(GetSnowflakeNodeDir(node.NodeID), SnowflakeNodeFile), data, node.version)
+	if err != nil {
+		return err
+	}
+	node.version = version
+	return nil
+}
@@ -94,7 +94,8 @@ func RebuildKeyspaceLocked(ctx context.Context, log logutil.Logger, ts *topo.Ser
 			return err
 		}
 		srvKeyspaceMap[cell] = &topodatapb.SrvKeyspace{
-			ServedFrom: ki.ComputeCellServedFrom(cell),
+			ServedFrom:      ki.ComputeCellServedFrom(cell),
+			ThrottlerConfig: ki.ThrottlerConfig,
 		}
 	}
 
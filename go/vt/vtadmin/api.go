@@ -115,10 +115,12 @@ func NewAPI(clusters []*cluster.Cluster, opts Options) *API {
 	if authz == nil {
 		authz, _ = rbac.NewAuthorizer(&rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "*",
@@ -349,6 +351,12 @@ func (api *API) Handler() http.Handler {
 	router.HandleFunc("/keyspace/{cluster_id}/{name}/validate/schema", httpAPI.Adapt(vtadminhttp.ValidateSchemaKeyspace)).Name("API.ValidateSchemaKeyspace").Methods("PUT", "OPTIONS")
 	router.HandleFunc("/keyspace/{cluster_id}/{name}/validate/version", httpAPI.Adapt(vtadminhttp.ValidateVersionKeyspace)).Name("API.ValidateVersionKeyspace").Methods("PUT", "OPTIONS")
 	router.HandleFunc("/keyspaces", httpAPI.Adapt(vtadminhttp.GetKeyspaces)).Name("API.GetKeyspaces")
+	router.HandleFunc("/migration/{cluster_id}/{keyspace}/{uuid}/cancel", httpAPI.Adapt(vtadminhttp.CancelSchemaMigration)).Name("API.CancelSchemaMigration").Methods("PUT", "OPTIONS")
+	router.HandleFunc("/migration/{cluster_id}/{keyspace}/{uuid}/complete", httpAPI.Adapt(vtadminhttp.CompleteSchemaMigration)).Name("API.CompleteSchemaMigration").Methods("PUT", "OPTIONS")
+	router.HandleFunc("/migration/{cluster_id}/{keyspace}/{uuid}/retry", httpAPI.Adapt(vtadminhttp.RetrySchemaMigration)).Name("API.RetrySchemaMigration").Methods("PUT", "OPTIONS")
+	router.HandleFunc("/migrations/{cluster_id}/{keyspace}", httpAPI.Adapt(vtadminhttp.ApplySchema)).Name("API.ApplySchema").Methods("POST")
+	router.HandleFunc("/migrations/{cluster_id}/{keyspace}", httpAPI.Adapt(vtadminhttp.GetSchemaMigrations)).Name("API.GetSchemaMigrations")
+	router.HandleFunc("/migrations/{cluster_id}/{keyspace}/cancel", httpAPI.Adapt(vtadminhttp.CancelAllSchemaMigrations)).Name("API.CancelAllSchemaMigrations").Methods("PUT", "OPTIONS")
 	router.HandleFunc("/schema/{table}", httpAPI.Adapt(vtadminhttp.FindSchema)).Name("API.FindSchema")
 	router.HandleFunc("/schema/{cluster_id}/{keyspace}/{table}", httpAPI.Adapt(vtadminhttp.GetSchema)).Name("API.GetSchema")
 	router.HandleFunc("/schemas", httpAPI.Adapt(vtadminhttp.GetSchemas)).Name("API.GetSchemas")
@@ -373,6 +381,8 @@ func (api *API) Handler() http.Handler {
 	router.HandleFunc("/tablet/{tablet}/start_replication", httpAPI.Adapt(vtadminhttp.StartReplication)).Name("API.StartReplication").Methods("PUT", "OPTIONS")
 	router.HandleFunc("/tablet/{tablet}/stop_replication", httpAPI.Adapt(vtadminhttp.StopReplication)).Name("API.StopReplication").Methods("PUT", "OPTIONS")
 	router.HandleFunc("/tablet/{tablet}/externally_promoted", httpAPI.Adapt(vtadminhttp.TabletExternallyPromoted)).Name("API.TabletExternallyPromoted").Methods("POST")
+	router.HandleFunc("/vdiff/{cluster_id}/{keyspace}/{workflow}", httpAPI.Adapt(vtadminhttp.VDiffCreate)).Name("API.VDiffCreate").Methods("POST")
+	router.HandleFunc("/vdiff/{cluster_id}/{keyspace}/{workflow}/{arg}", httpAPI.Adapt(vtadminhttp.VDiffShow)).Name("API.VDiffShow")
 	router.HandleFunc("/vschema/{cluster_id}/{keyspace}", httpAPI.Adapt(vtadminhttp.GetVSchema)).Name("API.GetVSchema")
 	router.HandleFunc("/vschemas", httpAPI.Adapt(vtadminhttp.GetVSchemas)).Name("API.GetVSchemas")
 	router.HandleFunc("/vtctlds", httpAPI.Adapt(vtadminhttp.GetVtctlds)).Name("API.GetVtctlds")
@@ -409,6 +419,78 @@ func (api *API) EjectDynamicCluster(key string, value any) {
 	api.clusters = append(api.clusters[:clusterIndex], api.clusters[clusterIndex+1:]...)
 }
 
+// ApplySchema is part of the vtadminpb.VTAdminServer interface.
+func (api *API) ApplySchema(ctx context.Context, req *vtadminpb.ApplySchemaRequest) (*vtctldatapb.ApplySchemaResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.ApplySchema")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	scope := rbac.Scope{Keyspace: req.Options.GetKeyspace()}
+	if !api.authz.IsAuthorizedInScope(ctx, req.ClusterId, rbac.SchemaMigrationResource, rbac.CreateAction, scope) {
+		api.authz.AuditDenied(ctx, req.ClusterId, rbac.SchemaMigrationResource, rbac.CreateAction, scope)
+		return nil, fmt.Errorf("%w: cannot apply schema in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.ApplySchema(ctx, req.Options)
+	api.authz.Audit(ctx, c.ID, rbac.SchemaMigrationResource, rbac.CreateAction, scope, err)
+
+	return resp, err
+}
+
+// CancelSchemaMigration is part of the vtadminpb.VTAdminServer interface.
+func (api *API) CancelSchemaMigration(ctx context.Context, req *vtadminpb.CancelSchemaMigrationRequest) (*vtctldatapb.CancelSchemaMigrationResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.CancelSchemaMigration")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	scope := rbac.Scope{Keyspace: req.Options.GetKeyspace()}
+	if !api.authz.IsAuthorizedInScope(ctx, req.ClusterId, rbac.SchemaMigrationResource, rbac.CancelSchemaMigrationAction, scope) {
+		api.authz.AuditDenied(ctx, req.ClusterId, rbac.SchemaMigrationResource, rbac.CancelSchemaMigrationAction, scope)
+		return nil, fmt.Errorf("%w: cannot cancel schema migration in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.CancelSchemaMigration(ctx, req.Options)
+	api.authz.Audit(ctx, c.ID, rbac.SchemaMigrationResource, rbac.CancelSchemaMigrationAction, scope, err)
+
+	return resp, err
+}
+
+// CompleteSchemaMigration is part of the vtadminpb.VTAdminServer interface.
+func (api *API) CompleteSchemaMigration(ctx context.Context, req *vtadminpb.CompleteSchemaMigrationRequest) (*vtctldatapb.CompleteSchemaMigrationResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.CompleteSchemaMigration")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	scope := rbac.Scope{Keyspace: req.Options.GetKeyspace()}
+	if !api.authz.IsAuthorizedInScope(ctx, req.ClusterId, rbac.SchemaMigrationResource, rbac.CompleteSchemaMigrationAction, scope) {
+		api.authz.AuditDenied(ctx, req.ClusterId, rbac.SchemaMigrationResource, rbac.CompleteSchemaMigrationAction, scope)
+		return nil, fmt.Errorf("%w: cannot complete schema migration in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.CompleteSchemaMigration(ctx, req.Options)
+	api.authz.Audit(ctx, c.ID, rbac.SchemaMigrationResource, rbac.CompleteSchemaMigrationAction, scope, err)
+
+	return resp, err
+}
+
 // CreateKeyspace is part of the vtadminpb.VTAdminServer interface.
 func (api *API) CreateKeyspace(ctx context.Context, req *vtadminpb.CreateKeyspaceRequest) (*vtadminpb.CreateKeyspaceResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "API.CreateKeyspace")
@@ -416,7 +498,9 @@ func (api *API) CreateKeyspace(ctx context.Context, req *vtadminpb.CreateKeyspac
 
 	span.Annotate("cluster_id", req.ClusterId)
 
-	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.KeyspaceResource, rbac.CreateAction) {
+	scope := rbac.Scope{Keyspace: req.Options.GetName()}
+	if !api.authz.IsAuthorizedInScope(ctx, req.ClusterId, rbac.KeyspaceResource, rbac.CreateAction, scope) {
+		api.authz.AuditDenied(ctx, req.ClusterId, rbac.KeyspaceResource, rbac.CreateAction, scope)
 		return nil, fmt.Errorf("%w: cannot create keyspace in %s", errors.ErrUnauthorized, req.ClusterId)
 	}
 
@@ -426,6 +510,7 @@ func (api *API) CreateKeyspace(ctx context.Context, req *vtadminpb.CreateKeyspac
 	}
 
 	ks, err := c.CreateKeyspace(ctx, req.Options)
+	api.authz.Audit(ctx, c.ID, rbac.KeyspaceResource, rbac.CreateAction, scope, err)
 	if err != nil {
 		return nil, err
 	}
@@ -442,7 +527,9 @@ func (api *API) CreateShard(ctx context.Context, req *vtadminpb.CreateShardReque
 
 	span.Annotate("cluster_id", req.ClusterId)
 
-	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.ShardResource, rbac.CreateAction) {
+	scope := rbac.Scope{Keyspace: req.Options.GetKeyspace(), Shard: req.Options.GetShardName()}
+	if !api.authz.IsAuthorizedInScope(ctx, req.ClusterId, rbac.ShardResource, rbac.CreateAction, scope) {
+		api.authz.AuditDenied(ctx, req.ClusterId, rbac.ShardResource, rbac.CreateAction, scope)
 		return nil, fmt.Errorf("%w: cannot create shard in %s", errors.ErrUnauthorized, req.ClusterId)
 	}
 
@@ -451,7 +538,10 @@ func (api *API) CreateShard(ctx context.Context, req *vtadminpb.CreateShardReque
 		return nil, err
 	}
 
-	return c.CreateShard(ctx, req.Options)
+	resp, err := c.CreateShard(ctx, req.Options)
+	api.authz.Audit(ctx, c.ID, rbac.ShardResource, rbac.CreateAction, scope, err)
+
+	return resp, err
 }
 
 // DeleteKeyspace is part of the vtadminpb.VTAdminServer interface.
@@ -461,7 +551,9 @@ func (api *API) DeleteKeyspace(ctx context.Context, req *vtadminpb.DeleteKeyspac
 
 	span.Annotate("cluster_id", req.ClusterId)
 
-	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.KeyspaceResource, rbac.DeleteAction) {
+	scope := rbac.Scope{Keyspace: req.Options.GetKeyspace()}
+	if !api.authz.IsAuthorizedInScope(ctx, req.ClusterId, rbac.KeyspaceResource, rbac.DeleteAction, scope) {
+		api.authz.AuditDenied(ctx, req.ClusterId, rbac.KeyspaceResource, rbac.DeleteAction, scope)
 		return nil, fmt.Errorf("%w: cannot delete keyspace in %s", errors.ErrUnauthorized, req.ClusterId)
 	}
 
@@ -470,7 +562,10 @@ func (api *API) DeleteKeyspace(ctx context.Context, req *vtadminpb.DeleteKeyspac
 		return nil, err
 	}
 
-	return c.DeleteKeyspace(ctx, req.Options)
+	resp, err := c.DeleteKeyspace(ctx, req.Options)
+	api.authz.Audit(ctx, c.ID, rbac.KeyspaceResource, rbac.DeleteAction, scope, err)
+
+	return resp, err
 }
 
 // DeleteShards is part of the vtadminpb.VTAdminServer interface.
@@ -480,8 +575,22 @@ func (api *API) DeleteShards(ctx context.Context, req *vtadminpb.DeleteShardsReq
 
 	span.Annotate("cluster_id", req.ClusterId)
 
-	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.ShardResource, rbac.DeleteAction) {
-		return nil, fmt.Errorf("%w: cannot delete shards in %s", errors.ErrUnauthorized, req.ClusterId)
+	// The actor must be authorized to delete every one of the shards; we don't
+	// partially delete the requested shards.
+	var scopes []rbac.Scope
+	for _, shard := range req.Options.GetShards() {
+		scopes = append(scopes, rbac.Scope{Keyspace: shard.Keyspace, Shard: shard.Name})
+	}
+
+	if len(scopes) == 0 {
+		scopes = append(scopes, rbac.Scope{})
+	}
+
+	for _, scope := range scopes {
+		if !api.authz.IsAuthorizedInScope(ctx, req.ClusterId, rbac.ShardResource, rbac.DeleteAction, scope) {
+			api.authz.AuditDenied(ctx, req.ClusterId, rbac.ShardResource, rbac.DeleteAction, scope)
+			return nil, fmt.Errorf("%w: cannot delete shards in %s", errors.ErrUnauthorized, req.ClusterId)
+		}
 	}
 
 	c, err := api.getClusterForRequest(req.ClusterId)
@@ -489,7 +598,12 @@ func (api *API) DeleteShards(ctx context.Context, req *vtadminpb.DeleteShardsReq
 		return nil, err
 	}
 
-	return c.DeleteShards(ctx, req.Options)
+	resp, err := c.DeleteShards(ctx, req.Options)
+	for _, scope := range scopes {
+		api.authz.Audit(ctx, c.ID, rbac.ShardResource, rbac.DeleteAction, scope, err)
+	}
+
+	return resp, err
 }
 
 // DeleteTablet is part of the vtadminpb.VTAdminServer interface.
@@ -502,10 +616,12 @@ func (api *API) DeleteTablet(ctx context.Context, req *vtadminpb.DeleteTabletReq
 		return nil, err
 	}
 
-	if _, err := c.DeleteTablets(ctx, &vtctldatapb.DeleteTabletsRequest{
+	_, err = c.DeleteTablets(ctx, &vtctldatapb.DeleteTabletsRequest{
 		AllowPrimary:  req.AllowPrimary,
 		TabletAliases: []*topodatapb.TabletAlias{tablet.Tablet.Alias},
-	}); err != nil {
+	})
+	api.authz.Audit(ctx, c.ID, rbac.TabletResource, rbac.DeleteAction, tabletScope(tablet), err)
+	if err != nil {
 		return nil, fmt.Errorf("failed to delete tablet: %w", err)
 	}
 
@@ -525,11 +641,16 @@ func (api *API) EmergencyFailoverShard(ctx context.Context, req *vtadminpb.Emerg
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.ShardResource, rbac.EmergencyFailoverShardAction) {
+	scope := rbac.Scope{Keyspace: req.Options.GetKeyspace(), Shard: req.Options.GetShard()}
+	if !api.authz.IsAuthorizedInScope(ctx, c.ID, rbac.ShardResource, rbac.EmergencyFailoverShardAction, scope) {
+		api.authz.AuditDenied(ctx, c.ID, rbac.ShardResource, rbac.EmergencyFailoverShardAction, scope)
 		return nil, nil
 	}
 
-	return c.EmergencyFailoverShard(ctx, req.Options)
+	resp, err := c.EmergencyFailoverShard(ctx, req.Options)
+	api.authz.Audit(ctx, c.ID, rbac.ShardResource, rbac.EmergencyFailoverShardAction, scope, err)
+
+	return resp, err
 }
 
 // FindSchema is part of the vtadminpb.VTAdminServer interface.
@@ -973,6 +1094,32 @@ func (api *API) GetSchemas(ctx context.Context, req *vtadminpb.GetSchemasRequest
 	}, nil
 }
 
+// GetSchemaMigrations is part of the vtadminpb.VTAdminServer interface.
+func (api *API) GetSchemaMigrations(ctx context.Context, req *vtadminpb.GetSchemaMigrationsRequest) (*vtadminpb.GetSchemaMigrationsResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.GetSchemaMigrations")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !api.authz.IsAuthorizedInScope(ctx, c.ID, rbac.SchemaMigrationResource, rbac.GetAction, rbac.Scope{Keyspace: req.Options.GetKeyspace()}) {
+		return &vtadminpb.GetSchemaMigrationsResponse{}, nil
+	}
+
+	migrations, err := c.GetSchemaMigrations(ctx, req.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtadminpb.GetSchemaMigrationsResponse{
+		SchemaMigrations: migrations,
+	}, nil
+}
+
 // GetShardReplicationPositions is part of the vtadminpb.VTAdminServer interface.
 func (api *API) GetShardReplicationPositions(ctx context.Context, req *vtadminpb.GetShardReplicationPositionsRequest) (*vtadminpb.GetShardReplicationPositionsResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "API.GetShardReplicationPositions")
@@ -1417,11 +1564,16 @@ func (api *API) PlannedFailoverShard(ctx context.Context, req *vtadminpb.Planned
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.ShardResource, rbac.PlannedFailoverShardAction) {
+	scope := rbac.Scope{Keyspace: req.Options.GetKeyspace(), Shard: req.Options.GetShard()}
+	if !api.authz.IsAuthorizedInScope(ctx, c.ID, rbac.ShardResource, rbac.PlannedFailoverShardAction, scope) {
+		api.authz.AuditDenied(ctx, c.ID, rbac.ShardResource, rbac.PlannedFailoverShardAction, scope)
 		return nil, nil
 	}
 
-	return c.PlannedFailoverShard(ctx, req.Options)
+	resp, err := c.PlannedFailoverShard(ctx, req.Options)
+	api.authz.Audit(ctx, c.ID, rbac.ShardResource, rbac.PlannedFailoverShardAction, scope, err)
+
+	return resp, err
 }
 
 // RefreshState is part of the vtadminpb.VTAdminServer interface.
@@ -1434,7 +1586,9 @@ func (api *API) RefreshState(ctx context.Context, req *vtadminpb.RefreshStateReq
 		return nil, err
 	}
 
-	if err := c.RefreshState(ctx, tablet); err != nil {
+	err = c.RefreshState(ctx, tablet)
+	api.authz.Audit(ctx, c.ID, rbac.TabletResource, rbac.PutAction, tabletScope(tablet), err)
+	if err != nil {
 		return nil, err
 	}
 
@@ -1454,7 +1608,10 @@ func (api *API) RefreshTabletReplicationSource(ctx context.Context, req *vtadmin
 		return nil, err
 	}
 
-	return c.RefreshTabletReplicationSource(ctx, tablet)
+	resp, err := c.RefreshTabletReplicationSource(ctx, tablet)
+	api.authz.Audit(ctx, c.ID, rbac.TabletResource, rbac.RefreshTabletReplicationSourceAction, tabletScope(tablet), err)
+
+	return resp, err
 }
 
 // ReloadSchemas is part of the vtadminpb.VTAdminServer interface.
@@ -1482,6 +1639,7 @@ func (api *API) ReloadSchemas(ctx context.Context, req *vtadminpb.ReloadSchemasR
 			defer wg.Done()
 
 			cr, err := c.ReloadSchemas(ctx, req)
+			api.authz.Audit(ctx, c.ID, rbac.SchemaResource, rbac.ReloadAction, rbac.Scope{}, err)
 			if err != nil {
 				rec.RecordError(fmt.Errorf("ReloadSchemas(cluster = %s) failed: %w", c.ID, err))
 				return
@@ -1503,6 +1661,30 @@ func (api *API) ReloadSchemas(ctx context.Context, req *vtadminpb.ReloadSchemasR
 	return &resp, nil
 }
 
+// RetrySchemaMigration is part of the vtadminpb.VTAdminServer interface.
+func (api *API) RetrySchemaMigration(ctx context.Context, req *vtadminpb.RetrySchemaMigrationRequest) (*vtctldatapb.RetrySchemaMigrationResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.RetrySchemaMigration")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	scope := rbac.Scope{Keyspace: req.Options.GetKeyspace()}
+	if !api.authz.IsAuthorizedInScope(ctx, req.ClusterId, rbac.SchemaMigrationResource, rbac.RetrySchemaMigrationAction, scope) {
+		api.authz.AuditDenied(ctx, req.ClusterId, rbac.SchemaMigrationResource, rbac.RetrySchemaMigrationAction, scope)
+		return nil, fmt.Errorf("%w: cannot retry schema migration in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.RetrySchemaMigration(ctx, req.Options)
+	api.authz.Audit(ctx, c.ID, rbac.SchemaMigrationResource, rbac.RetrySchemaMigrationAction, scope, err)
+
+	return resp, err
+}
+
 // RunHealthCheck is part of the vtadminpb.VTAdminServer interface.
 func (api *API) RunHealthCheck(ctx context.Context, req *vtadminpb.RunHealthCheckRequest) (*vtadminpb.RunHealthCheckResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "API.RunHealthCheck")
@@ -1543,6 +1725,7 @@ func (api *API) SetReadOnly(ctx context.Context, req *vtadminpb.SetReadOnlyReque
 		TabletAlias: tablet.Tablet.Alias,
 		Writable:    false,
 	})
+	api.authz.Audit(ctx, c.ID, rbac.TabletResource, rbac.ManageTabletWritabilityAction, tabletScope(tablet), err)
 	if err != nil {
 		return nil, fmt.Errorf("Error setting tablet to read-only: %w", err)
 	}
@@ -1564,6 +1747,7 @@ func (api *API) SetReadWrite(ctx context.Context, req *vtadminpb.SetReadWriteReq
 		TabletAlias: tablet.Tablet.Alias,
 		Writable:    true,
 	})
+	api.authz.Audit(ctx, c.ID, rbac.TabletResource, rbac.ManageTabletWritabilityAction, tabletScope(tablet), err)
 	if err != nil {
 		return nil, fmt.Errorf("Error setting tablet to read-write: %w", err)
 	}
@@ -1582,7 +1766,9 @@ func (api *API) StartReplication(ctx context.Context, req *vtadminpb.StartReplic
 	}
 
 	start := true
-	if err := c.ToggleTabletReplication(ctx, tablet, start); err != nil {
+	err = c.ToggleTabletReplication(ctx, tablet, start)
+	api.authz.Audit(ctx, c.ID, rbac.TabletResource, rbac.ManageTabletReplicationAction, tabletScope(tablet), err)
+	if err != nil {
 		return nil, err
 	}
 
@@ -1603,7 +1789,9 @@ func (api *API) StopReplication(ctx context.Context, req *vtadminpb.StopReplicat
 	}
 
 	start := true
-	if err := c.ToggleTabletReplication(ctx, tablet, !start); err != nil {
+	err = c.ToggleTabletReplication(ctx, tablet, !start)
+	api.authz.Audit(ctx, c.ID, rbac.TabletResource, rbac.ManageTabletReplicationAction, tabletScope(tablet), err)
+	if err != nil {
 		return nil, err
 	}
 
@@ -1623,7 +1811,10 @@ func (api *API) TabletExternallyPromoted(ctx context.Context, req *vtadminpb.Tab
 		return nil, err
 	}
 
-	return c.TabletExternallyPromoted(ctx, tablet)
+	resp, err := c.TabletExternallyPromoted(ctx, tablet)
+	api.authz.Audit(ctx, c.ID, rbac.ShardResource, rbac.TabletExternallyPromotedAction, tabletScope(tablet), err)
+
+	return resp, err
 }
 
 // ValidateKeyspace is part of the vtadminpb.VTAdminServer interface.
@@ -1636,7 +1827,7 @@ func (api *API) ValidateKeyspace(ctx context.Context, req *vtadminpb.ValidateKey
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.KeyspaceResource, rbac.PutAction) {
+	if !api.authz.IsAuthorizedInScope(ctx, c.ID, rbac.KeyspaceResource, rbac.PutAction, rbac.Scope{Keyspace: req.Keyspace}) {
 		return nil, nil
 	}
 
@@ -1662,7 +1853,7 @@ func (api *API) ValidateSchemaKeyspace(ctx context.Context, req *vtadminpb.Valid
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.KeyspaceResource, rbac.PutAction) {
+	if !api.authz.IsAuthorizedInScope(ctx, c.ID, rbac.KeyspaceResource, rbac.PutAction, rbac.Scope{Keyspace: req.Keyspace}) {
 		return nil, nil
 	}
 
@@ -1687,7 +1878,7 @@ func (api *API) ValidateVersionKeyspace(ctx context.Context, req *vtadminpb.Vali
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.KeyspaceResource, rbac.PutAction) {
+	if !api.authz.IsAuthorizedInScope(ctx, c.ID, rbac.KeyspaceResource, rbac.PutAction, rbac.Scope{Keyspace: req.Keyspace}) {
 		return nil, nil
 	}
 
@@ -1702,6 +1893,49 @@ func (api *API) ValidateVersionKeyspace(ctx context.Context, req *vtadminpb.Vali
 	return res, nil
 }
 
+// VDiffCreate is part of the vtadminpb.VTAdminServer interface.
+func (api *API) VDiffCreate(ctx context.Context, req *vtadminpb.VDiffCreateRequest) (*vtctldatapb.VDiffCreateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.VDiffCreate")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	scope := rbac.Scope{Keyspace: req.Options.GetTargetKeyspace()}
+	if !api.authz.IsAuthorizedInScope(ctx, req.ClusterId, rbac.VDiffResource, rbac.CreateAction, scope) {
+		api.authz.AuditDenied(ctx, req.ClusterId, rbac.VDiffResource, rbac.CreateAction, scope)
+		return nil, fmt.Errorf("%w: cannot create vdiff in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.VDiffCreate(ctx, req.Options)
+	api.authz.Audit(ctx, c.ID, rbac.VDiffResource, rbac.CreateAction, scope, err)
+
+	return resp, err
+}
+
+// VDiffShow is part of the vtadminpb.VTAdminServer interface.
+func (api *API) VDiffShow(ctx context.Context, req *vtadminpb.VDiffShowRequest) (*vtctldatapb.VDiffShowResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.VDiffShow")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	if !api.authz.IsAuthorizedInScope(ctx, req.ClusterId, rbac.VDiffResource, rbac.GetAction, rbac.Scope{Keyspace: req.Options.GetTargetKeyspace()}) {
+		return nil, fmt.Errorf("%w: cannot get vdiff in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.VDiffShow(ctx, req.Options)
+}
+
 // VTExplain is part of the vtadminpb.VTAdminServer interface.
 func (api *API) VTExplain(ctx context.Context, req *vtadminpb.VTExplainRequest) (*vtadminpb.VTExplainResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "API.VTExplain")
@@ -1927,7 +2161,10 @@ func (api *API) getTabletForResourceAndAction(
 	)
 
 	for _, c := range clusters {
-		if !api.authz.IsAuthorized(ctx, c.ID, resource, action) {
+		// The tablet's keyspace and shard are not known yet, so we skip only
+		// the clusters where the actor cannot take the action at all, and
+		// check the tablet's scope once we've found it.
+		if !api.authz.IsAuthorizedInAnyScope(ctx, c.ID, resource, action) {
 			continue
 		}
 
@@ -1963,6 +2200,12 @@ func (api *API) getTabletForResourceAndAction(
 		t := tablets[0]
 		for _, c := range clusters {
 			if c.ID == t.Cluster.Id {
+				scope := tabletScope(t)
+				if !api.authz.IsAuthorizedInScope(ctx, c.ID, resource, action, scope) {
+					api.authz.AuditDenied(ctx, c.ID, resource, action, scope)
+					return nil, nil, fmt.Errorf("%w: cannot %s %s %s in %s/%s", errors.ErrUnauthorized, action, resource, topoproto.TabletAliasString(alias), scope.Keyspace, scope.Shard)
+				}
+
 				return t, c, nil
 			}
 		}
@@ -1972,3 +2215,11 @@ func (api *API) getTabletForResourceAndAction(
 
 	return nil, nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "%s: %s, searched clusters = %v", errors.ErrAmbiguousTablet, alias, ids)
 }
+
+// tabletScope returns the rbac scope (keyspace and shard) of the given tablet.
+func tabletScope(tablet *vtadminpb.Tablet) rbac.Scope {
+	return rbac.Scope{
+		Keyspace: tablet.Tablet.Keyspace,
+		Shard:    tablet.Tablet.Shard,
+	}
+}
@@ -43,16 +43,239 @@ import (
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
+func TestApplySchema(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
+			}{
+				{
+					Resource: "SchemaMigration",
+					Actions:  []string{"create"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.ApplySchema(ctx, &vtadminpb.ApplySchemaRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.ApplySchemaRequest{
+				Keyspace:    "test",
+				Sql:         []string{"alter table t1 add column c int"},
+				DdlStrategy: "vitess",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to ApplySchema", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to ApplySchema", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.ApplySchema(ctx, &vtadminpb.ApplySchemaRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.ApplySchemaRequest{
+				Keyspace:    "test",
+				Sql:         []string{"alter table t1 add column c int"},
+				DdlStrategy: "vitess",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to ApplySchema", actor)
+	})
+}
+
+func TestCancelSchemaMigration(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
+			}{
+				{
+					Resource: "SchemaMigration",
+					Actions:  []string{"cancel_schema_migration"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.CancelSchemaMigration(ctx, &vtadminpb.CancelSchemaMigrationRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.CancelSchemaMigrationRequest{
+				Keyspace: "test",
+				Uuid:     "6d0ad6c8_2a49_11ed_9c61_0a43f95f28a3",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to CancelSchemaMigration", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to CancelSchemaMigration", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.CancelSchemaMigration(ctx, &vtadminpb.CancelSchemaMigrationRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.CancelSchemaMigrationRequest{
+				Keyspace: "test",
+				Uuid:     "6d0ad6c8_2a49_11ed_9c61_0a43f95f28a3",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to CancelSchemaMigration", actor)
+	})
+}
+
+func TestCompleteSchemaMigration(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
+			}{
+				{
+					Resource: "SchemaMigration",
+					Actions:  []string{"complete_schema_migration"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.CompleteSchemaMigration(ctx, &vtadminpb.CompleteSchemaMigrationRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.CompleteSchemaMigrationRequest{
+				Keyspace: "test",
+				Uuid:     "6d0ad6c8_2a49_11ed_9c61_0a43f95f28a3",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to CompleteSchemaMigration", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to CompleteSchemaMigration", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.CompleteSchemaMigration(ctx, &vtadminpb.CompleteSchemaMigrationRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.CompleteSchemaMigrationRequest{
+				Keyspace: "test",
+				Uuid:     "6d0ad6c8_2a49_11ed_9c61_0a43f95f28a3",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to CompleteSchemaMigration", actor)
+	})
+}
+
 func TestCreateKeyspace(t *testing.T) {
 	t.Parallel()
 
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Keyspace",
@@ -118,10 +341,12 @@ func TestCreateShard(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Shard",
@@ -189,10 +414,12 @@ func TestDeleteKeyspace(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Keyspace",
@@ -258,10 +485,12 @@ func TestDeleteShards(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Shard",
@@ -337,10 +566,12 @@ func TestDeleteTablet(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Tablet",
@@ -408,10 +639,12 @@ func TestEmergencyFailoverShard(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Shard",
@@ -479,10 +712,12 @@ func TestFindSchema(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Schema",
@@ -578,10 +813,12 @@ func TestGetBackups(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Backup",
@@ -657,10 +894,12 @@ func TestGetCellInfos(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "CellInfo",
@@ -742,10 +981,12 @@ func TestGetCellsAliases(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "CellsAlias",
@@ -821,10 +1062,12 @@ func TestGetClusters(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Cluster",
@@ -892,10 +1135,12 @@ func TestGetGates(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "VTGate",
@@ -987,10 +1232,12 @@ func TestGetKeyspace(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Keyspace",
@@ -1052,10 +1299,12 @@ func TestGetKeyspaces(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Keyspace",
@@ -1145,10 +1394,12 @@ func TestGetSchema(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Schema",
@@ -1219,10 +1470,12 @@ func TestGetSchemas(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Schema",
@@ -1320,16 +1573,89 @@ func TestGetSchemas(t *testing.T) {
 	})
 }
 
+func TestGetSchemaMigrations(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
+			}{
+				{
+					Resource: "SchemaMigration",
+					Actions:  []string{"get"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.GetSchemaMigrations(ctx, &vtadminpb.GetSchemaMigrationsRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.GetSchemaMigrationsRequest{
+				Keyspace: "test",
+			},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, resp.SchemaMigrations, "actor %+v should not be permitted to GetSchemaMigrations", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.GetSchemaMigrations(ctx, &vtadminpb.GetSchemaMigrationsRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.GetSchemaMigrationsRequest{
+				Keyspace: "test",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, resp.SchemaMigrations, "actor %+v should be permitted to GetSchemaMigrations", actor)
+	})
+}
+
 func TestGetShardReplicationPositions(t *testing.T) {
 	t.Parallel()
 
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "ShardReplicationPosition",
@@ -1419,10 +1745,12 @@ func TestGetSrvVSchema(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "SrvVSchema",
@@ -1484,10 +1812,12 @@ func TestGetSrvVSchemas(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "SrvVSchema",
@@ -1577,10 +1907,12 @@ func TestGetTablet(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Tablet",
@@ -1646,10 +1978,12 @@ func TestGetTablets(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Tablet",
@@ -1739,10 +2073,12 @@ func TestGetVSchema(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "VSchema",
@@ -1804,10 +2140,12 @@ func TestGetVSchemas(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "VSchema",
@@ -1897,10 +2235,12 @@ func TestGetVtctlds(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Vtctld",
@@ -1992,10 +2332,12 @@ func TestGetWorkflow(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Workflow",
@@ -2059,10 +2401,12 @@ func TestGetWorkflows(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Workflow",
@@ -2138,10 +2482,12 @@ func TestPingTablet(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Tablet",
@@ -2207,10 +2553,12 @@ func TestPlannedFailoverShard(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Shard",
@@ -2218,6 +2566,20 @@ func TestPlannedFailoverShard(t *testing.T) {
 					Subjects: []string{"user:allowed"},
 					Clusters: []string{"*"},
 				},
+				{
+					Resource:  "Shard",
+					Actions:   []string{"planned_failover_shard"},
+					Subjects:  []string{"group:test-owners"},
+					Clusters:  []string{"*"},
+					Keyspaces: []string{"te*"},
+				},
+				{
+					Resource:  "Shard",
+					Actions:   []string{"planned_failover_shard"},
+					Subjects:  []string{"group:other-owners"},
+					Clusters:  []string{"*"},
+					Keyspaces: []string{"other*"},
+				},
 			},
 		},
 	}
@@ -2251,6 +2613,46 @@ func TestPlannedFailoverShard(t *testing.T) {
 		assert.Nil(t, resp, "actor %+v should not be permitted to PlannedFailoverShard", actor)
 	})
 
+	t.Run("actor scoped to other keyspaces", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other", Groups: []string{"other-owners"}}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.PlannedFailoverShard(ctx, &vtadminpb.PlannedFailoverShardRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.PlannedReparentShardRequest{
+				Keyspace: "test",
+				Shard:    "-",
+			},
+		})
+		require.NoError(t, err)
+		assert.Nil(t, resp, "actor %+v should not be permitted to PlannedFailoverShard", actor)
+	})
+
+	t.Run("actor scoped to keyspace", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other", Groups: []string{"test-owners"}}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.PlannedFailoverShard(ctx, &vtadminpb.PlannedFailoverShardRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.PlannedReparentShardRequest{
+				Keyspace: "test",
+				Shard:    "-",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to PlannedFailoverShard", actor)
+	})
+
 	t.Run("authorized actor", func(t *testing.T) {
 		t.Parallel()
 
@@ -2278,10 +2680,12 @@ func TestRefreshState(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Tablet",
@@ -2289,6 +2693,20 @@ func TestRefreshState(t *testing.T) {
 					Subjects: []string{"user:allowed"},
 					Clusters: []string{"*"},
 				},
+				{
+					Resource:  "Tablet",
+					Actions:   []string{"put"},
+					Subjects:  []string{"group:test-owners"},
+					Clusters:  []string{"*"},
+					Keyspaces: []string{"test"},
+				},
+				{
+					Resource:  "Tablet",
+					Actions:   []string{"put"},
+					Subjects:  []string{"group:other-owners"},
+					Clusters:  []string{"*"},
+					Keyspaces: []string{"otherks"},
+				},
 			},
 		},
 	}
@@ -2321,6 +2739,44 @@ func TestRefreshState(t *testing.T) {
 		assert.Nil(t, resp, "actor %+v should not be permitted to RefreshState", actor)
 	})
 
+	t.Run("actor scoped to other keyspaces", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other", Groups: []string{"other-owners"}}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.RefreshState(ctx, &vtadminpb.RefreshStateRequest{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to RefreshState", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to RefreshState", actor)
+	})
+
+	t.Run("actor scoped to keyspace", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other", Groups: []string{"test-owners"}}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.RefreshState(ctx, &vtadminpb.RefreshStateRequest{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to RefreshState", actor)
+	})
+
 	t.Run("authorized actor", func(t *testing.T) {
 		t.Parallel()
 
@@ -2347,10 +2803,12 @@ func TestRefreshTabletReplicationSource(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Tablet",
@@ -2416,10 +2874,12 @@ func TestReloadSchemas(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Schema",
@@ -2502,16 +2962,91 @@ func TestReloadSchemas(t *testing.T) {
 	})
 }
 
+func TestRetrySchemaMigration(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
+			}{
+				{
+					Resource: "SchemaMigration",
+					Actions:  []string{"retry_schema_migration"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.RetrySchemaMigration(ctx, &vtadminpb.RetrySchemaMigrationRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.RetrySchemaMigrationRequest{
+				Keyspace: "test",
+				Uuid:     "6d0ad6c8_2a49_11ed_9c61_0a43f95f28a3",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to RetrySchemaMigration", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to RetrySchemaMigration", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.RetrySchemaMigration(ctx, &vtadminpb.RetrySchemaMigrationRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.RetrySchemaMigrationRequest{
+				Keyspace: "test",
+				Uuid:     "6d0ad6c8_2a49_11ed_9c61_0a43f95f28a3",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to RetrySchemaMigration", actor)
+	})
+}
+
 func TestRunHealthCheck(t *testing.T) {
 	t.Parallel()
 
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Tablet",
@@ -2577,10 +3112,12 @@ func TestSetReadOnly(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Tablet",
@@ -2646,10 +3183,12 @@ func TestSetReadWrite(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Tablet",
@@ -2715,10 +3254,12 @@ func TestStartReplication(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Tablet",
@@ -2784,10 +3325,12 @@ func TestStopReplication(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Tablet",
@@ -2853,10 +3396,12 @@ func TestTabletExternallyPromoted(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Shard",
@@ -2916,16 +3461,166 @@ func TestTabletExternallyPromoted(t *testing.T) {
 	})
 }
 
+func TestVDiffCreate(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
+			}{
+				{
+					Resource: "VDiff",
+					Actions:  []string{"create"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.VDiffCreate(ctx, &vtadminpb.VDiffCreateRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.VDiffCreateRequest{
+				TargetKeyspace: "test",
+				Workflow:       "wf",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to VDiffCreate", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to VDiffCreate", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.VDiffCreate(ctx, &vtadminpb.VDiffCreateRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.VDiffCreateRequest{
+				TargetKeyspace: "test",
+				Workflow:       "wf",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to VDiffCreate", actor)
+	})
+}
+
+func TestVDiffShow(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
+			}{
+				{
+					Resource: "VDiff",
+					Actions:  []string{"get"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.VDiffShow(ctx, &vtadminpb.VDiffShowRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.VDiffShowRequest{
+				TargetKeyspace: "test",
+				Workflow:       "wf",
+				Arg:            "last",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to VDiffShow", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to VDiffShow", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.VDiffShow(ctx, &vtadminpb.VDiffShowRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.VDiffShowRequest{
+				TargetKeyspace: "test",
+				Workflow:       "wf",
+				Arg:            "last",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to VDiffShow", actor)
+	})
+}
+
 func TestVTExplain(t *testing.T) {
 	t.Parallel()
 
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "VTExplain",
@@ -2987,10 +3682,12 @@ func TestValidateKeyspace(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Keyspace",
@@ -3052,10 +3749,12 @@ func TestValidateSchemaKeyspace(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Keyspace",
@@ -3117,10 +3816,12 @@ func TestValidateVersionKeyspace(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{
 					Resource: "Keyspace",
@@ -3184,6 +3885,32 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 				Name: "test",
 			},
 			VtctldClient: &fakevtctldclient.VtctldClient{
+				ApplySchemaResults: map[string]struct {
+					Response *vtctldatapb.ApplySchemaResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.ApplySchemaResponse{
+							UuidList: []string{"6d0ad6c8_2a49_11ed_9c61_0a43f95f28a3"},
+						},
+					},
+				},
+				CancelSchemaMigrationResults: map[string]struct {
+					Response *vtctldatapb.CancelSchemaMigrationResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.CancelSchemaMigrationResponse{},
+					},
+				},
+				CompleteSchemaMigrationResults: map[string]struct {
+					Response *vtctldatapb.CompleteSchemaMigrationResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.CompleteSchemaMigrationResponse{},
+					},
+				},
 				DeleteShardsResults: map[string]error{
 					"test/-": nil,
 				},
@@ -3286,6 +4013,18 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						},
 					},
 				},
+				GetSchemaMigrationsResults: map[string]struct {
+					Response *vtctldatapb.GetSchemaMigrationsResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.GetSchemaMigrationsResponse{
+							Migrations: []*vtctldatapb.SchemaMigration{
+								{Uuid: "6d0ad6c8_2a49_11ed_9c61_0a43f95f28a3", Keyspace: "test"},
+							},
+						},
+					},
+				},
 				GetSrvVSchemaResults: map[string]struct {
 					Response *vtctldatapb.GetSrvVSchemaResponse
 					Error    error
@@ -3371,6 +4110,14 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						Response: &vtctldatapb.ReparentTabletResponse{},
 					},
 				},
+				RetrySchemaMigrationResults: map[string]struct {
+					Response *vtctldatapb.RetrySchemaMigrationResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.RetrySchemaMigrationResponse{},
+					},
+				},
 				RunHealthCheckResults: map[string]error{
 					"zone1-0000000100": nil,
 				},
@@ -3399,6 +4146,24 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						Response: &vtctldatapb.TabletExternallyReparentedResponse{},
 					},
 				},
+				VDiffCreateResults: map[string]struct {
+					Response *vtctldatapb.VDiffCreateResponse
+					Error    error
+				}{
+					"test/wf": {
+						Response: &vtctldatapb.VDiffCreateResponse{
+							Uuid: "7e1be7d9-2a49-11ed-9c61-0a43f95f28a3",
+						},
+					},
+				},
+				VDiffShowResults: map[string]struct {
+					Response *vtctldatapb.VDiffShowResponse
+					Error    error
+				}{
+					"test/wf": {
+						Response: &vtctldatapb.VDiffShowResponse{},
+					},
+				},
 				ValidateKeyspaceResults: map[string]struct {
 					Response *vtctldatapb.ValidateKeyspaceResponse
 					Error    error
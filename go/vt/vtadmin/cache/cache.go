@@ -148,6 +148,18 @@ func (c *Cache[Key, Value]) Add(key Key, val Value, d time.Duration) error {
 	return c.add(key.Key(), val, d)
 }
 
+// Set adds a (key, value) to the cache, replacing any existing Value for the
+// key, following the semantics of (github.com/patrickmn/go-cache).Cache.Set.
+func (c *Cache[Key, Value]) Set(key Key, val Value, d time.Duration) {
+	k := key.Key()
+
+	c.m.Lock()
+	c.lastFill[k] = time.Now().UTC()
+	c.m.Unlock()
+
+	c.cache.Set(k, val, d)
+}
+
 func (c *Cache[Key, Value]) add(key string, val Value, d time.Duration) error {
 	c.m.Lock()
 	// Record the time we last cached this key, to check against
@@ -171,6 +183,49 @@ func (c *Cache[Key, Value]) Get(key Key) (Value, bool) {
 	return v.(Value), ok
 }
 
+// Delete removes the Value stored for the key, if any, so the next call to Get
+// misses. It also forgets when the key was last filled, so a backfill for the
+// key is not skipped as a duplicate.
+func (c *Cache[Key, Value]) Delete(key Key) {
+	k := key.Key()
+
+	c.m.Lock()
+	delete(c.lastFill, k)
+	c.m.Unlock()
+
+	c.cache.Delete(k)
+}
+
+// Replace replaces the Value stored for the key, keeping its expiration. It
+// does nothing, and returns false, if the key is not cached.
+func (c *Cache[Key, Value]) Replace(key Key, val Value) bool {
+	k := key.Key()
+
+	_, exp, ok := c.cache.GetWithExpiration(k)
+	if !ok {
+		return false
+	}
+
+	d := NoExpiration
+	if !exp.IsZero() {
+		d = time.Until(exp)
+		if d <= 0 {
+			return false
+		}
+	}
+
+	return c.cache.Replace(k, val, d) == nil
+}
+
+// Flush removes all Values from the cache.
+func (c *Cache[Key, Value]) Flush() {
+	c.m.Lock()
+	c.lastFill = map[string]time.Time{}
+	c.m.Unlock()
+
+	c.cache.Flush()
+}
+
 // EnqueueBackfill submits a request to the backfill queue.
 func (c *Cache[Key, Value]) EnqueueBackfill(k Key) bool {
 	req := &backfillRequest[Key]{
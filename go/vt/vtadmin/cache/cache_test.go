@@ -191,3 +191,66 @@ func TestEnqueueBackfillTimeout(t *testing.T) {
 		assert.Equal(t, q.shouldFail, !ok, "enqueue should %s wait timeout", q.msg)
 	}
 }
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New(func(ctx context.Context, key testkey) (int, error) {
+		return 0, nil
+	}, cache.Config{
+		BackfillRequestTTL:               time.Hour,
+		BackfillRequestDuplicateInterval: time.Hour,
+	})
+	defer c.Close()
+
+	err := c.Add(testkey("a"), 1, cache.DefaultExpiration)
+	assert.NoError(t, err)
+	err = c.Add(testkey("b"), 2, cache.DefaultExpiration)
+	assert.NoError(t, err)
+
+	c.Delete(testkey("a"))
+
+	_, ok := c.Get(testkey("a"))
+	assert.False(t, ok, "deleted key should not be cached")
+
+	val, ok := c.Get(testkey("b"))
+	assert.True(t, ok, "other keys should still be cached")
+	assert.Equal(t, 2, val)
+
+	err = c.Add(testkey("a"), 3, cache.DefaultExpiration)
+	assert.NoError(t, err, "deleted key should be re-addable")
+
+	c.Flush()
+
+	_, ok = c.Get(testkey("a"))
+	assert.False(t, ok, "flushed key should not be cached")
+	_, ok = c.Get(testkey("b"))
+	assert.False(t, ok, "flushed key should not be cached")
+}
+
+func TestReplace(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New(func(ctx context.Context, key testkey) (int, error) {
+		return 0, nil
+	}, cache.Config{
+		BackfillRequestTTL:               time.Hour,
+		BackfillRequestDuplicateInterval: time.Hour,
+	})
+	defer c.Close()
+
+	assert.False(t, c.Replace(testkey("a"), 1), "uncached key should not be replaced")
+	_, ok := c.Get(testkey("a"))
+	assert.False(t, ok, "uncached key should not be added by Replace")
+
+	c.Set(testkey("a"), 1, time.Hour)
+	assert.True(t, c.Replace(testkey("a"), 2))
+
+	val, ok := c.Get(testkey("a"))
+	assert.True(t, ok)
+	assert.Equal(t, 2, val)
+
+	c.Set(testkey("b"), 1, time.Millisecond)
+	time.Sleep(time.Millisecond * 5)
+	assert.False(t, c.Replace(testkey("b"), 2), "expired key should not be replaced")
+}
@@ -93,6 +93,19 @@ type Cluster struct {
 	// 	*: at the time it was cached; if keyspaces were created/destroyed in
 	//  the interim, we won't pick that up until something refreshes the cache.
 	schemaCache *cache.Cache[schemacache.Key, []*vtadminpb.Schema]
+	// keyspaceCache and tabletCache cache the GetKeyspaces and GetTablets
+	// payloads of the cluster, if cfg.KeyspaceCacheConfig and
+	// cfg.TabletCacheConfig are set, respectively. Otherwise, they are nil.
+	keyspaceCache *cache.Cache[clusterCacheKey, []*vtadminpb.Keyspace]
+	tabletCache   *cache.Cache[clusterCacheKey, []*vtadminpb.Tablet]
+
+	// watchCancel and watchDone control the cache invalidation watch, if
+	// cfg.CacheInvalidationWatch is set. See startCacheInvalidationWatch.
+	watchCancel context.CancelFunc
+	watchDone   chan struct{}
+	// onCacheInvalidation, if set, is called by the cache invalidation watch
+	// after invalidating the caches for a keyspace. It is used in tests.
+	onCacheInvalidation func(keyspace string)
 
 	cfg Config
 }
@@ -199,6 +212,22 @@ func New(ctx context.Context, cfg Config) (*Cluster, error) {
 		return []*vtadminpb.Schema{schema}, nil
 	}, *cluster.cfg.SchemaCacheConfig)
 
+	if cluster.cfg.KeyspaceCacheConfig != nil {
+		cluster.keyspaceCache = cache.New(func(ctx context.Context, key clusterCacheKey) ([]*vtadminpb.Keyspace, error) {
+			return cluster.getKeyspaces(ctx)
+		}, *cluster.cfg.KeyspaceCacheConfig)
+	}
+
+	if cluster.cfg.TabletCacheConfig != nil {
+		cluster.tabletCache = cache.New(func(ctx context.Context, key clusterCacheKey) ([]*vtadminpb.Tablet, error) {
+			return cluster.getTablets(ctx)
+		}, *cluster.cfg.TabletCacheConfig)
+	}
+
+	if cfg.cacheInvalidationWatch() {
+		cluster.startCacheInvalidationWatch()
+	}
+
 	return cluster, nil
 }
 
@@ -221,8 +250,16 @@ func (c *Cluster) Close() error {
 
 	// First, close any caches, which may have connections to DB or Vtctld
 	// (N.B. (andrew) when we have multiple caches, we can close them
-	// concurrently, like we do with the proxies).
+	// concurrently, like we do with the proxies). The cache invalidation
+	// watch, if any, is stopped before that, since it uses both.
+	c.stopCacheInvalidationWatch()
 	rec.RecordError(c.schemaCache.Close())
+	if c.keyspaceCache != nil {
+		rec.RecordError(c.keyspaceCache.Close())
+	}
+	if c.tabletCache != nil {
+		rec.RecordError(c.tabletCache.Close())
+	}
 
 	for _, closer := range []io.Closer{c.DB, c.Vtctld} {
 		wg.Add(1)
@@ -353,6 +390,94 @@ func (c *Cluster) parseTablet(rows *sql.Rows) (*vtadminpb.Tablet, error) {
 	return tablet, nil
 }
 
+// ApplySchema applies a schema change to a keyspace in the given cluster,
+// proxying an ApplySchemaRequest to a vtctld in that cluster. Passing a
+// non-direct DdlStrategy submits the change as an Online DDL migration.
+func (c *Cluster) ApplySchema(ctx context.Context, req *vtctldatapb.ApplySchemaRequest) (*vtctldatapb.ApplySchemaResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.ApplySchema")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	if req == nil {
+		return nil, fmt.Errorf("%w: request cannot be nil", errors.ErrInvalidRequest)
+	}
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("ddl_strategy", req.DdlStrategy)
+	span.Annotate("migration_context", req.MigrationContext)
+	span.Annotate("skip_preflight", req.SkipPreflight)
+
+	if req.Keyspace == "" {
+		return nil, fmt.Errorf("%w: keyspace name is required", errors.ErrInvalidRequest)
+	}
+
+	if len(req.Sql) == 0 {
+		return nil, fmt.Errorf("%w: at least one sql statement is required", errors.ErrInvalidRequest)
+	}
+
+	resp, err := c.Vtctld.ApplySchema(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Schema changes are not reflected in the topo, so the cache invalidation
+	// watch will not see them.
+	schemacache.Invalidate(c.schemaCache, c.ID, req.Keyspace)
+
+	return resp, nil
+}
+
+// CancelSchemaMigration cancels a schema migration (or, if no uuid is given,
+// all pending migrations) in a keyspace, proxying a
+// CancelSchemaMigrationRequest to a vtctld in that cluster.
+func (c *Cluster) CancelSchemaMigration(ctx context.Context, req *vtctldatapb.CancelSchemaMigrationRequest) (*vtctldatapb.CancelSchemaMigrationResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.CancelSchemaMigration")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	if req == nil {
+		return nil, fmt.Errorf("%w: request cannot be nil", errors.ErrInvalidRequest)
+	}
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("uuid", req.Uuid)
+
+	if req.Keyspace == "" {
+		return nil, fmt.Errorf("%w: keyspace name is required", errors.ErrInvalidRequest)
+	}
+
+	return c.Vtctld.CancelSchemaMigration(ctx, req)
+}
+
+// CompleteSchemaMigration completes a schema migration that was submitted
+// with postponed completion, proxying a CompleteSchemaMigrationRequest to a
+// vtctld in that cluster.
+func (c *Cluster) CompleteSchemaMigration(ctx context.Context, req *vtctldatapb.CompleteSchemaMigrationRequest) (*vtctldatapb.CompleteSchemaMigrationResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.CompleteSchemaMigration")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	if req == nil {
+		return nil, fmt.Errorf("%w: request cannot be nil", errors.ErrInvalidRequest)
+	}
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("uuid", req.Uuid)
+
+	if req.Keyspace == "" {
+		return nil, fmt.Errorf("%w: keyspace name is required", errors.ErrInvalidRequest)
+	}
+
+	if req.Uuid == "" {
+		return nil, fmt.Errorf("%w: uuid is required", errors.ErrInvalidRequest)
+	}
+
+	return c.Vtctld.CompleteSchemaMigration(ctx, req)
+}
+
 // CreateKeyspace creates a keyspace in the given cluster, proxying a
 // CreateKeyspaceRequest to a vtctld in that cluster.
 func (c *Cluster) CreateKeyspace(ctx context.Context, req *vtctldatapb.CreateKeyspaceRequest) (*vtadminpb.Keyspace, error) {
@@ -381,6 +506,9 @@ func (c *Cluster) CreateKeyspace(ctx context.Context, req *vtctldatapb.CreateKey
 		return nil, err
 	}
 
+	// A keyspace without shards is not seen by the cache invalidation watch.
+	c.invalidateTopoCaches()
+
 	return &vtadminpb.Keyspace{
 		Cluster:  c.ToProto(),
 		Keyspace: resp.Keyspace,
@@ -444,7 +572,14 @@ func (c *Cluster) DeleteKeyspace(ctx context.Context, req *vtctldatapb.DeleteKey
 	}
 	defer c.topoRWPool.Release()
 
-	return c.Vtctld.DeleteKeyspace(ctx, req)
+	resp, err := c.Vtctld.DeleteKeyspace(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.invalidateTopoCaches()
+
+	return resp, nil
 }
 
 // DeleteShards deletes one or more shards in the given cluster, proxying a
@@ -476,7 +611,14 @@ func (c *Cluster) DeleteShards(ctx context.Context, req *vtctldatapb.DeleteShard
 	}
 	defer c.topoRWPool.Release()
 
-	return c.Vtctld.DeleteShards(ctx, req)
+	resp, err := c.Vtctld.DeleteShards(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.invalidateTopoCaches()
+
+	return resp, nil
 }
 
 // DeleteTablets deletes one or more tablets in the given cluster.
@@ -492,7 +634,15 @@ func (c *Cluster) DeleteTablets(ctx context.Context, req *vtctldatapb.DeleteTabl
 	}
 	defer c.topoRWPool.Release()
 
-	return c.Vtctld.DeleteTablets(ctx, req)
+	resp, err := c.Vtctld.DeleteTablets(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tablet records are not seen by the cache invalidation watch.
+	c.invalidateTopoCaches()
+
+	return resp, nil
 }
 
 // EmergencyFailoverShard fails over a shard to a new primary. It assumes the
@@ -1144,6 +1294,30 @@ func (c *Cluster) GetKeyspaces(ctx context.Context) ([]*vtadminpb.Keyspace, erro
 
 	AnnotateSpan(c, span)
 
+	if c.keyspaceCache == nil {
+		return c.getKeyspaces(ctx)
+	}
+
+	key := clusterCacheKey(c.ID)
+	if !cache.ShouldRefreshFromIncomingContext(ctx) {
+		keyspaces, ok := c.keyspaceCache.Get(key)
+		span.Annotate("cache_hit", ok)
+		if ok {
+			return keyspaces, nil
+		}
+	}
+
+	keyspaces, err := c.getKeyspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.keyspaceCache.Set(key, keyspaces, cache.DefaultExpiration)
+
+	return keyspaces, nil
+}
+
+func (c *Cluster) getKeyspaces(ctx context.Context) ([]*vtadminpb.Keyspace, error) {
 	if err := c.topoReadPool.Acquire(ctx); err != nil {
 		return nil, fmt.Errorf("GetKeyspaces() failed to acquire topoReadPool: %w", err)
 	}
@@ -1200,7 +1374,27 @@ func (c *Cluster) GetTablets(ctx context.Context) ([]*vtadminpb.Tablet, error) {
 
 	AnnotateSpan(c, span)
 
-	return c.getTablets(ctx)
+	if c.tabletCache == nil {
+		return c.getTablets(ctx)
+	}
+
+	key := clusterCacheKey(c.ID)
+	if !cache.ShouldRefreshFromIncomingContext(ctx) {
+		tablets, ok := c.tabletCache.Get(key)
+		span.Annotate("cache_hit", ok)
+		if ok {
+			return tablets, nil
+		}
+	}
+
+	tablets, err := c.getTablets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.tabletCache.Set(key, tablets, cache.DefaultExpiration)
+
+	return tablets, nil
 }
 
 func (c *Cluster) getTablets(ctx context.Context) ([]*vtadminpb.Tablet, error) {
@@ -1662,6 +1856,50 @@ func (c *Cluster) getTabletsToQueryForSchemas(ctx context.Context, keyspace stri
 	return []*vtadminpb.Tablet{randomServingTablet}, nil
 }
 
+// GetSchemaMigrations returns the schema migrations in a keyspace matching the
+// given request, proxying a GetSchemaMigrationsRequest to a vtctld in that
+// cluster.
+func (c *Cluster) GetSchemaMigrations(ctx context.Context, req *vtctldatapb.GetSchemaMigrationsRequest) ([]*vtadminpb.SchemaMigration, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.GetSchemaMigrations")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	if req == nil {
+		return nil, fmt.Errorf("%w: request cannot be nil", errors.ErrInvalidRequest)
+	}
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("uuid", req.Uuid)
+	span.Annotate("migration_context", req.MigrationContext)
+	span.Annotate("status", req.Status)
+
+	if req.Keyspace == "" {
+		return nil, fmt.Errorf("%w: keyspace name is required", errors.ErrInvalidRequest)
+	}
+
+	if err := c.schemaReadPool.Acquire(ctx); err != nil {
+		return nil, fmt.Errorf("GetSchemaMigrations(%+v) failed to acquire schemaReadPool: %w", req, err)
+	}
+
+	resp, err := c.Vtctld.GetSchemaMigrations(ctx, req)
+	c.schemaReadPool.Release()
+
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]*vtadminpb.SchemaMigration, len(resp.Migrations))
+	for i, m := range resp.Migrations {
+		migrations[i] = &vtadminpb.SchemaMigration{
+			Cluster:         c.ToProto(),
+			SchemaMigration: m,
+		}
+	}
+
+	return migrations, nil
+}
+
 // GetShardReplicationPositions returns a ClusterShardReplicationPosition object
 // for each keyspace/shard in the cluster.
 func (c *Cluster) GetShardReplicationPositions(ctx context.Context, req *vtadminpb.GetShardReplicationPositionsRequest) ([]*vtadminpb.ClusterShardReplicationPosition, error) {
@@ -2254,6 +2492,32 @@ func (c *Cluster) reloadTabletSchemas(ctx context.Context, req *vtadminpb.Reload
 	return results, nil
 }
 
+// RetrySchemaMigration retries a failed or cancelled schema migration,
+// proxying a RetrySchemaMigrationRequest to a vtctld in that cluster.
+func (c *Cluster) RetrySchemaMigration(ctx context.Context, req *vtctldatapb.RetrySchemaMigrationRequest) (*vtctldatapb.RetrySchemaMigrationResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.RetrySchemaMigration")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	if req == nil {
+		return nil, fmt.Errorf("%w: request cannot be nil", errors.ErrInvalidRequest)
+	}
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("uuid", req.Uuid)
+
+	if req.Keyspace == "" {
+		return nil, fmt.Errorf("%w: keyspace name is required", errors.ErrInvalidRequest)
+	}
+
+	if req.Uuid == "" {
+		return nil, fmt.Errorf("%w: uuid is required", errors.ErrInvalidRequest)
+	}
+
+	return c.Vtctld.RetrySchemaMigration(ctx, req)
+}
+
 // SetWritable toggles the writability of a tablet, setting it to either
 // read-write or read-only.
 func (c *Cluster) SetWritable(ctx context.Context, req *vtctldatapb.SetWritableRequest) error {
@@ -2322,6 +2586,65 @@ func (c *Cluster) ToggleTabletReplication(ctx context.Context, tablet *vtadminpb
 	return err
 }
 
+// VDiffCreate starts a VDiff for a workflow, proxying a VDiffCreateRequest to
+// a vtctld in that cluster.
+func (c *Cluster) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRequest) (*vtctldatapb.VDiffCreateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.VDiffCreate")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	if req == nil {
+		return nil, fmt.Errorf("%w: request cannot be nil", errors.ErrInvalidRequest)
+	}
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("uuid", req.Uuid)
+
+	if req.TargetKeyspace == "" {
+		return nil, fmt.Errorf("%w: target keyspace name is required", errors.ErrInvalidRequest)
+	}
+
+	if req.Workflow == "" {
+		return nil, fmt.Errorf("%w: workflow name is required", errors.ErrInvalidRequest)
+	}
+
+	return c.Vtctld.VDiffCreate(ctx, req)
+}
+
+// VDiffShow returns the state of one or more VDiffs for a workflow, proxying a
+// VDiffShowRequest to a vtctld in that cluster.
+func (c *Cluster) VDiffShow(ctx context.Context, req *vtctldatapb.VDiffShowRequest) (*vtctldatapb.VDiffShowResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.VDiffShow")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	if req == nil {
+		return nil, fmt.Errorf("%w: request cannot be nil", errors.ErrInvalidRequest)
+	}
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("arg", req.Arg)
+
+	if req.TargetKeyspace == "" {
+		return nil, fmt.Errorf("%w: target keyspace name is required", errors.ErrInvalidRequest)
+	}
+
+	if req.Workflow == "" {
+		return nil, fmt.Errorf("%w: workflow name is required", errors.ErrInvalidRequest)
+	}
+
+	if err := c.workflowReadPool.Acquire(ctx); err != nil {
+		return nil, fmt.Errorf("VDiffShow(%+v) failed to acquire workflowReadPool: %w", req, err)
+	}
+	defer c.workflowReadPool.Release()
+
+	return c.Vtctld.VDiffShow(ctx, req)
+}
+
 // Debug returns a map of debug information for a cluster.
 func (c *Cluster) Debug() map[string]any {
 	m := map[string]any{
@@ -2341,6 +2664,14 @@ func (c *Cluster) Debug() map[string]any {
 		},
 	}
 
+	caches := m["caches"].(map[string]any)
+	if c.keyspaceCache != nil {
+		caches["keyspaces"] = c.keyspaceCache.Debug()
+	}
+	if c.tabletCache != nil {
+		caches["tablets"] = c.tabletCache.Debug()
+	}
+
 	if vtsql, ok := c.DB.(debug.Debuggable); ok {
 		m["vtsql"] = vtsql.Debug()
 	}
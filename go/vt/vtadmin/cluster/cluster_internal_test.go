@@ -31,18 +31,25 @@ import (
 	"vitess.io/vitess/go/pools"
 	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vitessdriver"
+	"vitess.io/vitess/go/vt/vtadmin/cache"
+	"vitess.io/vitess/go/vt/vtadmin/cluster/internal/caches/schemacache"
 	"vitess.io/vitess/go/vt/vtadmin/cluster/resolver"
 	"vitess.io/vitess/go/vt/vtadmin/vtctldclient/fakevtctldclient"
 	"vitess.io/vitess/go/vt/vtadmin/vtsql"
 	"vitess.io/vitess/go/vt/vtadmin/vtsql/fakevtsql"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+	grpcvtctldtestutil "vitess.io/vitess/go/vt/vtctl/grpcvtctldserver/testutil"
+	"vitess.io/vitess/go/vt/vtctl/localvtctldclient"
 	"vitess.io/vitess/go/vt/vtctl/vtctldclient"
 
 	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
 )
 
 type vtctldProxy struct {
@@ -1938,3 +1945,177 @@ func TestTabletExternallyPromoted(t *testing.T) {
 		})
 	}
 }
+
+func TestCacheInvalidationWatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ts := memorytopo.NewServer("zone1")
+
+	for _, keyspace := range []string{"ks1", "ks2"} {
+		require.NoError(t, ts.CreateKeyspace(ctx, keyspace, &topodatapb.Keyspace{}))
+		require.NoError(t, ts.CreateShard(ctx, keyspace, "-"))
+	}
+
+	vtctld := grpcvtctldtestutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return grpcvtctldserver.NewVtctldServer(ts)
+	})
+
+	invalidations := make(chan string, 10)
+	c := &Cluster{
+		ID: "test",
+		Vtctld: &vtctldProxy{
+			VtctldClient: localvtctldclient.New(vtctld),
+		},
+		schemaCache: cache.New(func(ctx context.Context, key schemacache.Key) ([]*vtadminpb.Schema, error) {
+			return nil, assert.AnError
+		}, cache.Config{}),
+		keyspaceCache: cache.New(func(ctx context.Context, key clusterCacheKey) ([]*vtadminpb.Keyspace, error) {
+			return nil, assert.AnError
+		}, cache.Config{}),
+		tabletCache: cache.New(func(ctx context.Context, key clusterCacheKey) ([]*vtadminpb.Tablet, error) {
+			return nil, assert.AnError
+		}, cache.Config{}),
+		cfg: Config{
+			CacheInvalidationWatchRetryInterval: time.Millisecond * 10,
+		},
+		onCacheInvalidation: func(keyspace string) {
+			invalidations <- keyspace
+		},
+	}
+	defer c.schemaCache.Close()
+	defer c.keyspaceCache.Close()
+	defer c.tabletCache.Close()
+
+	keys := map[string]schemacache.Key{
+		"ks1": {ClusterID: "test", Keyspace: "ks1"},
+		"ks2": {ClusterID: "test", Keyspace: "ks2"},
+		"all": {ClusterID: "test"},
+	}
+	cached := func(name string) bool {
+		_, ok := c.schemaCache.Get(keys[name])
+		return ok
+	}
+	topoCached := func() (keyspaces bool, tablets bool) {
+		_, keyspaces = c.keyspaceCache.Get(clusterCacheKey("test"))
+		_, tablets = c.tabletCache.Get(clusterCacheKey("test"))
+		return keyspaces, tablets
+	}
+	tablet := func(keyspace string, uid uint32, tabletType topodatapb.TabletType) *vtadminpb.Tablet {
+		return &vtadminpb.Tablet{
+			Tablet: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  uid,
+				},
+				Keyspace: keyspace,
+				Shard:    "-",
+				Type:     tabletType,
+			},
+		}
+	}
+	fillTablets := func(tablets ...*vtadminpb.Tablet) {
+		c.tabletCache.Set(clusterCacheKey("test"), tablets, cache.DefaultExpiration)
+	}
+	fill := func() {
+		for _, key := range keys {
+			c.schemaCache.Set(key, []*vtadminpb.Schema{}, cache.DefaultExpiration)
+		}
+		keyspaces := []*vtadminpb.Keyspace{}
+		for _, keyspace := range []string{"ks1", "ks2"} {
+			keyspaces = append(keyspaces, &vtadminpb.Keyspace{
+				Cluster:  &vtadminpb.Cluster{Id: "test"},
+				Keyspace: &vtctldatapb.Keyspace{Name: keyspace, Keyspace: &topodatapb.Keyspace{}},
+				Shards: map[string]*vtctldatapb.Shard{
+					"-": {Keyspace: keyspace, Name: "-", Shard: &topodatapb.Shard{}},
+				},
+			})
+		}
+		c.keyspaceCache.Set(clusterCacheKey("test"), keyspaces, cache.DefaultExpiration)
+		fillTablets(tablet("ks1", 100, topodatapb.TabletType_REPLICA), tablet("ks2", 200, topodatapb.TabletType_REPLICA))
+	}
+	waitForInvalidation := func(keyspace string) {
+		t.Helper()
+		select {
+		case ks := <-invalidations:
+			require.Equal(t, keyspace, ks)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for the caches to be invalidated", "keyspace %s", keyspace)
+		}
+	}
+
+	fill()
+
+	c.startCacheInvalidationWatch()
+	defer c.stopCacheInvalidationWatch()
+
+	// The current shard records are received once the vtctld has established
+	// its shard watches, after the caches are flushed.
+	received := []string{}
+	for i := 0; i < 2; i++ {
+		select {
+		case ks := <-invalidations:
+			received = append(received, ks)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for the current shard records")
+		}
+	}
+	assert.ElementsMatch(t, []string{"ks1", "ks2"}, received)
+	assert.False(t, cached("ks1") || cached("ks2") || cached("all"), "cache should be flushed when the watch starts")
+	keyspacesCached, tabletsCached := topoCached()
+	assert.False(t, keyspacesCached || tabletsCached, "keyspaces and tablets should be flushed when the watch starts")
+
+	fill()
+
+	_, err := ts.UpdateShardFields(ctx, "ks1", "-", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  100,
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	waitForInvalidation("ks1")
+	assert.False(t, cached("ks1"), "cached schemas for ks1 should be invalidated")
+	assert.False(t, cached("all"), "cached schemas for the cluster should be invalidated")
+	assert.True(t, cached("ks2"), "cached schemas for ks2 should not be invalidated")
+
+	keyspaces, ok := c.keyspaceCache.Get(clusterCacheKey("test"))
+	require.True(t, ok, "cached keyspaces should be updated, not invalidated")
+	for _, ks := range keyspaces {
+		primary := ks.Shards["-"].GetShard().GetPrimaryAlias()
+		switch ks.Keyspace.Name {
+		case "ks1":
+			assert.Equal(t, "zone1-0000000100", topoproto.TabletAliasString(primary), "cached ks1/- should have the new primary")
+		case "ks2":
+			assert.Nil(t, primary, "cached ks2/- should not change")
+		}
+	}
+	_, tabletsCached = c.tabletCache.Get(clusterCacheKey("test"))
+	assert.False(t, tabletsCached, "cached tablets should be invalidated when they disagree with the new primary")
+
+	// The cached tablets already agree with the new primary of ks2.
+	fillTablets(tablet("ks1", 100, topodatapb.TabletType_PRIMARY), tablet("ks2", 200, topodatapb.TabletType_PRIMARY))
+
+	_, err = ts.UpdateShardFields(ctx, "ks2", "-", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  200,
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	waitForInvalidation("ks2")
+	keyspacesCached, tabletsCached = topoCached()
+	assert.True(t, keyspacesCached, "cached keyspaces should be updated, not invalidated")
+	assert.True(t, tabletsCached, "cached tablets should not be invalidated when they agree with the new primary")
+
+	require.NoError(t, ts.DeleteShard(ctx, "ks1", "-"))
+
+	waitForInvalidation("ks1")
+	keyspacesCached, tabletsCached = topoCached()
+	assert.False(t, keyspacesCached, "cached keyspaces should be invalidated when a keyspace has no shards left")
+	assert.False(t, tabletsCached, "cached tablets should be invalidated when their shard is deleted")
+}
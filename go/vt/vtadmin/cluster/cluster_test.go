@@ -34,6 +34,7 @@ import (
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtadmin/cache"
 	"vitess.io/vitess/go/vt/vtadmin/cluster"
 	vtadminerrors "vitess.io/vitess/go/vt/vtadmin/errors"
 	"vitess.io/vitess/go/vt/vtadmin/testutil"
@@ -361,6 +362,59 @@ func TestDeleteKeyspace(t *testing.T) {
 	}
 }
 
+func TestGetKeyspacesCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	vtctld := &fakevtctldclient.VtctldClient{
+		GetKeyspacesResults: &struct {
+			Keyspaces []*vtctldatapb.Keyspace
+			Error     error
+		}{
+			Keyspaces: []*vtctldatapb.Keyspace{{Name: "ks1"}},
+		},
+		FindAllShardsInKeyspaceResults: map[string]struct {
+			Response *vtctldatapb.FindAllShardsInKeyspaceResponse
+			Error    error
+		}{
+			"ks1": {
+				Response: &vtctldatapb.FindAllShardsInKeyspaceResponse{},
+			},
+		},
+	}
+	c := testutil.BuildCluster(t, testutil.TestClusterConfig{
+		Cluster: &vtadminpb.Cluster{
+			Id:   "c1",
+			Name: "cluster1",
+		},
+		VtctldClient: vtctld,
+		Config: &cluster.Config{
+			KeyspaceCacheConfig: &cache.Config{
+				DefaultExpiration: time.Hour,
+			},
+		},
+	})
+	defer c.Close()
+
+	keyspaces, err := c.GetKeyspaces(ctx)
+	require.NoError(t, err)
+	require.Len(t, keyspaces, 1)
+
+	// Keyspaces are served from the cache, until they are invalidated.
+	vtctld.GetKeyspacesResults.Error = assert.AnError
+
+	keyspaces, err = c.GetKeyspaces(ctx)
+	require.NoError(t, err, "keyspaces should be cached")
+	assert.Len(t, keyspaces, 1)
+
+	_, err = c.DeleteKeyspace(ctx, &vtctldatapb.DeleteKeyspaceRequest{Keyspace: "ks1"})
+	require.NoError(t, err)
+
+	vtctld.GetKeyspacesResults.Error = assert.AnError
+	_, err = c.GetKeyspaces(ctx)
+	assert.Error(t, err, "deleting a keyspace should invalidate the cached keyspaces")
+}
+
 func TestDeleteShards(t *testing.T) {
 	t.Parallel()
 
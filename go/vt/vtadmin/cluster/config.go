@@ -48,6 +48,10 @@ var (
 	// DefaultReadPoolWaitTimeout is the pool wait timeout used when creating
 	// read-only RPC pools if a config has no wait timeout set.
 	DefaultReadPoolWaitTimeout = time.Millisecond * 100
+	// DefaultCacheInvalidationWatchRetryInterval is how long to wait before
+	// re-establishing a failed cache invalidation watch if a config has no
+	// retry interval set.
+	DefaultCacheInvalidationWatchRetryInterval = time.Second * 5
 )
 
 // Config represents the options to configure a vtadmin cluster.
@@ -76,6 +80,26 @@ type Config struct {
 	FailoverPoolConfig *RPCPoolConfig
 
 	SchemaCacheConfig *cache.Config
+	// KeyspaceCacheConfig and TabletCacheConfig, when set, enable caching the
+	// keyspaces and tablets of the cluster, respectively. They are meant to be
+	// used along with CacheInvalidationWatch, with DefaultExpiration as the
+	// fallback refresh interval.
+	KeyspaceCacheConfig *cache.Config
+	TabletCacheConfig   *cache.Config
+
+	// CacheInvalidationWatch, when true, makes the cluster watch its vtctld for
+	// changes to shard records (for example, a new primary after a reparent),
+	// invalidating the cached schemas of the affected keyspaces, as well as the
+	// cached keyspaces and tablets, as soon as they change. Cache expiration
+	// still applies, as a fallback for changes the watch does not cover.
+	//
+	// It is a pointer so that Merge can tell an override which disables the
+	// watch apart from one which does not set it.
+	CacheInvalidationWatch *bool
+	// CacheInvalidationWatchRetryInterval is how long to wait before
+	// re-establishing the watch after it fails. If non-positive,
+	// DefaultCacheInvalidationWatchRetryInterval is used.
+	CacheInvalidationWatchRetryInterval time.Duration
 
 	vtctldConfigOpts []vtctldclient.ConfigOption
 	vtsqlConfigOpts  []vtsql.ConfigOption
@@ -86,6 +110,12 @@ func (cfg Config) Cluster(ctx context.Context) (*Cluster, error) {
 	return New(ctx, cfg)
 }
 
+// cacheInvalidationWatch returns whether the cache invalidation watch is
+// enabled. It is disabled unless explicitly set.
+func (cfg Config) cacheInvalidationWatch() bool {
+	return cfg.CacheInvalidationWatch != nil && *cfg.CacheInvalidationWatch
+}
+
 // String is part of the flag.Value interface.
 func (cfg *Config) String() string { return fmt.Sprintf("%T:%+v", cfg, *cfg) }
 
@@ -212,7 +242,12 @@ func (cfg *Config) MarshalJSON() ([]byte, error) {
 		EmergencyFailoverPoolConfig *RPCPoolConfig `json:"emergency_failover_pool_config"`
 		FailoverPoolConfig          *RPCPoolConfig `json:"failover_pool_config"`
 
-		SchemaCacheConfig *cache.Config `json:"schema_cache_config"`
+		SchemaCacheConfig   *cache.Config `json:"schema_cache_config"`
+		KeyspaceCacheConfig *cache.Config `json:"keyspace_cache_config,omitempty"`
+		TabletCacheConfig   *cache.Config `json:"tablet_cache_config,omitempty"`
+
+		CacheInvalidationWatch              bool          `json:"cache_invalidation_watch"`
+		CacheInvalidationWatchRetryInterval time.Duration `json:"cache_invalidation_watch_retry_interval"`
 	}{
 		ID:                          cfg.ID,
 		Name:                        cfg.Name,
@@ -228,6 +263,12 @@ func (cfg *Config) MarshalJSON() ([]byte, error) {
 		EmergencyFailoverPoolConfig: defaultRWPoolConfig.merge(cfg.EmergencyFailoverPoolConfig),
 		FailoverPoolConfig:          defaultRWPoolConfig.merge(cfg.FailoverPoolConfig),
 		SchemaCacheConfig:           mergeCacheConfigs(defaultCacheConfig, cfg.SchemaCacheConfig),
+
+		KeyspaceCacheConfig:         cfg.KeyspaceCacheConfig,
+		TabletCacheConfig:           cfg.TabletCacheConfig,
+
+		CacheInvalidationWatch:              cfg.cacheInvalidationWatch(),
+		CacheInvalidationWatchRetryInterval: cfg.CacheInvalidationWatchRetryInterval,
 	}
 
 	return json.Marshal(&tmp)
@@ -252,6 +293,11 @@ func (cfg Config) Merge(override Config) Config {
 		EmergencyFailoverPoolConfig: cfg.EmergencyFailoverPoolConfig.merge(override.EmergencyFailoverPoolConfig),
 		FailoverPoolConfig:          cfg.FailoverPoolConfig.merge(override.FailoverPoolConfig),
 		SchemaCacheConfig:           mergeCacheConfigs(cfg.SchemaCacheConfig, override.SchemaCacheConfig),
+		KeyspaceCacheConfig:         mergeCacheConfigs(cfg.KeyspaceCacheConfig, override.KeyspaceCacheConfig),
+		TabletCacheConfig:           mergeCacheConfigs(cfg.TabletCacheConfig, override.TabletCacheConfig),
+
+		CacheInvalidationWatch:              cfg.CacheInvalidationWatch,
+		CacheInvalidationWatchRetryInterval: cfg.CacheInvalidationWatchRetryInterval,
 	}
 
 	if override.ID != "" {
@@ -270,6 +316,14 @@ func (cfg Config) Merge(override Config) Config {
 		merged.TabletFQDNTmplStr = override.TabletFQDNTmplStr
 	}
 
+	if override.CacheInvalidationWatch != nil {
+		merged.CacheInvalidationWatch = override.CacheInvalidationWatch
+	}
+
+	if override.CacheInvalidationWatchRetryInterval > 0 {
+		merged.CacheInvalidationWatchRetryInterval = override.CacheInvalidationWatchRetryInterval
+	}
+
 	// first, the default flags
 	merged.DiscoveryFlagsByImpl.Merge(cfg.DiscoveryFlagsByImpl)
 	// then, apply any overrides
@@ -18,6 +18,7 @@ package cluster
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -25,6 +26,8 @@ import (
 func TestMergeConfig(t *testing.T) {
 	t.Parallel()
 
+	enabled, disabled := true, false
+
 	tests := []struct {
 		name     string
 		base     Config
@@ -128,6 +131,42 @@ func TestMergeConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "merging cache invalidation watch",
+			base: Config{
+				ID:                                  "c1",
+				CacheInvalidationWatch:              &enabled,
+				CacheInvalidationWatchRetryInterval: time.Second,
+			},
+			override: Config{
+				CacheInvalidationWatchRetryInterval: time.Minute,
+			},
+			expected: Config{
+				ID:                                  "c1",
+				DiscoveryFlagsByImpl:                FlagsByImpl{},
+				VtSQLFlags:                          map[string]string{},
+				VtctldFlags:                         map[string]string{},
+				CacheInvalidationWatch:              &enabled,
+				CacheInvalidationWatchRetryInterval: time.Minute,
+			},
+		},
+		{
+			name: "overriding cache invalidation watch",
+			base: Config{
+				ID:                     "c1",
+				CacheInvalidationWatch: &enabled,
+			},
+			override: Config{
+				CacheInvalidationWatch: &disabled,
+			},
+			expected: Config{
+				ID:                     "c1",
+				DiscoveryFlagsByImpl:   FlagsByImpl{},
+				VtSQLFlags:             map[string]string{},
+				VtctldFlags:            map[string]string{},
+				CacheInvalidationWatch: &disabled,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -19,7 +19,9 @@ package cluster
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/vtadmin/cache"
@@ -147,6 +149,20 @@ func parseOne(cfg *Config, name string, val string) error {
 		cfg.DiscoveryImpl = val
 	case "tablet-fqdn-tmpl":
 		cfg.TabletFQDNTmplStr = val
+	case "cache-invalidation-watch":
+		watch, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %w", name, err)
+		}
+
+		cfg.CacheInvalidationWatch = &watch
+	case "cache-invalidation-watch-retry-interval":
+		interval, err := time.ParseDuration(val)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %w", name, err)
+		}
+
+		cfg.CacheInvalidationWatchRetryInterval = interval
 	default:
 		switch {
 		case strings.HasPrefix(name, "vtsql-"):
@@ -253,6 +269,36 @@ func parseOne(cfg *Config, name string, val string) error {
 			if err := parseCacheConfigFlag(cfg.SchemaCacheConfig, strings.TrimPrefix(name, "schema-cache-"), val); err != nil {
 				return fmt.Errorf("error parsing %s: %w", name, err)
 			}
+		case strings.HasPrefix(name, "keyspace-cache-"):
+			if cfg.KeyspaceCacheConfig == nil {
+				cfg.KeyspaceCacheConfig = &cache.Config{
+					DefaultExpiration:                -1,
+					CleanupInterval:                  -1,
+					BackfillRequestTTL:               -1,
+					BackfillRequestDuplicateInterval: -1,
+					BackfillQueueSize:                -1,
+					BackfillEnqueueWaitTime:          -1,
+				}
+			}
+
+			if err := parseCacheConfigFlag(cfg.KeyspaceCacheConfig, strings.TrimPrefix(name, "keyspace-cache-"), val); err != nil {
+				return fmt.Errorf("error parsing %s: %w", name, err)
+			}
+		case strings.HasPrefix(name, "tablet-cache-"):
+			if cfg.TabletCacheConfig == nil {
+				cfg.TabletCacheConfig = &cache.Config{
+					DefaultExpiration:                -1,
+					CleanupInterval:                  -1,
+					BackfillRequestTTL:               -1,
+					BackfillRequestDuplicateInterval: -1,
+					BackfillQueueSize:                -1,
+					BackfillEnqueueWaitTime:          -1,
+				}
+			}
+
+			if err := parseCacheConfigFlag(cfg.TabletCacheConfig, strings.TrimPrefix(name, "tablet-cache-"), val); err != nil {
+				return fmt.Errorf("error parsing %s: %w", name, err)
+			}
 		default:
 			match := discoveryFlagRegexp.FindStringSubmatch(name)
 			if match == nil {
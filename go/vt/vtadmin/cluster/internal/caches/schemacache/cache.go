@@ -71,6 +71,16 @@ func AddOrBackfill(c *schemaCache, schemas []*vtadminpb.Schema, key Key, d time.
 	}
 }
 
+// Invalidate removes the cached schemas for a keyspace in a cluster, both for
+// that keyspace alone and for the cluster-wide GetSchemas payloads which
+// include it.
+func Invalidate(c *schemaCache, clusterID string, keyspace string) {
+	for _, includeNonServingShards := range []bool{false, true} {
+		c.Delete(Key{ClusterID: clusterID, Keyspace: keyspace, IncludeNonServingShards: includeNonServingShards})
+		c.Delete(Key{ClusterID: clusterID, Keyspace: "", IncludeNonServingShards: includeNonServingShards})
+	}
+}
+
 // LoadOptions is the set of options used by Load(All|One) to filter down fully-
 // cached schema payloads.
 type LoadOptions struct {
@@ -0,0 +1,227 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtadmin/cluster/internal/caches/schemacache"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// clusterCacheKey is the cache key for payloads which span an entire cluster,
+// such as all of its keyspaces, or all of its tablets.
+type clusterCacheKey string
+
+// Key is part of the cache.Keyer interface.
+func (k clusterCacheKey) Key() string { return string(k) }
+
+// invalidateTopoCaches removes the cached keyspaces and tablets of the
+// cluster, if they are cached.
+func (c *Cluster) invalidateTopoCaches() {
+	key := clusterCacheKey(c.ID)
+	if c.keyspaceCache != nil {
+		c.keyspaceCache.Delete(key)
+	}
+	if c.tabletCache != nil {
+		c.tabletCache.Delete(key)
+	}
+}
+
+// updateTopoCaches updates the cached keyspaces and tablets of the cluster for
+// a changed (or, if shard.Shard is nil, deleted) shard record.
+//
+// The shard is patched into the cached keyspaces, which are only invalidated
+// if its keyspace is not cached yet or has no shards left. The cached tablets
+// are only invalidated if they disagree with the shard's primary, or if the
+// shard was deleted while some of its tablets are still cached.
+func (c *Cluster) updateTopoCaches(shard *vtctldatapb.Shard) {
+	key := clusterCacheKey(c.ID)
+
+	if c.keyspaceCache != nil {
+		if keyspaces, ok := c.keyspaceCache.Get(key); ok {
+			if updated, ok := updateCachedKeyspaces(keyspaces, shard); ok {
+				c.keyspaceCache.Replace(key, updated)
+			} else {
+				c.keyspaceCache.Delete(key)
+			}
+		}
+	}
+
+	if c.tabletCache != nil {
+		if tablets, ok := c.tabletCache.Get(key); ok && cachedTabletsStale(tablets, shard) {
+			c.tabletCache.Delete(key)
+		}
+	}
+}
+
+// updateCachedKeyspaces returns a copy of the cached keyspaces with the shard
+// record set (or removed, if it was deleted). It returns false if the keyspace
+// of the shard is not cached, or has no shards left.
+func updateCachedKeyspaces(keyspaces []*vtadminpb.Keyspace, shard *vtctldatapb.Shard) ([]*vtadminpb.Keyspace, bool) {
+	updated := make([]*vtadminpb.Keyspace, len(keyspaces))
+	copy(updated, keyspaces)
+
+	for i, ks := range updated {
+		if ks.GetKeyspace().GetName() != shard.Keyspace {
+			continue
+		}
+
+		ks = proto.Clone(ks).(*vtadminpb.Keyspace)
+		if shard.Shard == nil {
+			delete(ks.Shards, shard.Name)
+			if len(ks.Shards) == 0 {
+				return nil, false
+			}
+		} else {
+			if ks.Shards == nil {
+				ks.Shards = map[string]*vtctldatapb.Shard{}
+			}
+			ks.Shards[shard.Name] = shard
+		}
+
+		updated[i] = ks
+		return updated, true
+	}
+
+	return nil, false
+}
+
+// cachedTabletsStale returns true if the cached tablets of the shard disagree
+// with its record: a tablet other than the shard's primary is cached as
+// PRIMARY, the primary is cached with another type, or the shard was deleted.
+func cachedTabletsStale(tablets []*vtadminpb.Tablet, shard *vtctldatapb.Shard) bool {
+	primary := shard.GetShard().GetPrimaryAlias()
+
+	for _, tablet := range tablets {
+		t := tablet.GetTablet()
+		if t.GetKeyspace() != shard.Keyspace || t.GetShard() != shard.Name {
+			continue
+		}
+
+		if shard.Shard == nil {
+			return true
+		}
+
+		isPrimary := primary != nil && topoproto.TabletAliasEqual(t.GetAlias(), primary)
+		if isPrimary != (t.GetType() == topodatapb.TabletType_PRIMARY) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// startCacheInvalidationWatch starts a background goroutine which watches the
+// cluster's shard records through a vtctld, invalidating cached schemas,
+// keyspaces and tablets when the shards of a keyspace change. If the watch
+// fails, it is re-established after the configured retry interval. The
+// goroutine runs until Close is called.
+func (c *Cluster) startCacheInvalidationWatch() {
+	retryInterval := c.cfg.CacheInvalidationWatchRetryInterval
+	if retryInterval <= 0 {
+		retryInterval = DefaultCacheInvalidationWatchRetryInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.watchCancel = cancel
+	c.watchDone = make(chan struct{})
+
+	go func() {
+		defer close(c.watchDone)
+
+		for {
+			err := c.watchShards(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+
+			log.Warningf("cache invalidation watch for cluster %s failed; retrying in %s: %s", c.ID, retryInterval, err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+		}
+	}()
+}
+
+// stopCacheInvalidationWatch stops the watch started by
+// startCacheInvalidationWatch, if any, and waits for it to exit.
+func (c *Cluster) stopCacheInvalidationWatch() {
+	if c.watchCancel == nil {
+		return
+	}
+
+	c.watchCancel()
+	<-c.watchDone
+}
+
+// watchShards runs a single WatchShards stream until it fails or ctx is
+// cancelled.
+//
+// Changes may have been missed while no stream was open, so the entire schema
+// cache, as well as the cached keyspaces and tablets, are flushed once the
+// stream is opened. After that, for each shard record received (first the
+// current ones, then the changed ones), the schemas for the keyspace of the
+// shard are invalidated, and the cached keyspaces and tablets are updated for
+// that shard only (see updateTopoCaches).
+func (c *Cluster) watchShards(ctx context.Context) error {
+	stream, err := c.Vtctld.WatchShards(ctx, &vtctldatapb.WatchShardsRequest{})
+	if err != nil {
+		return err
+	}
+
+	c.schemaCache.Flush()
+	c.invalidateTopoCaches()
+
+	for {
+		resp, err := stream.Recv()
+		switch {
+		case errors.Is(err, io.EOF):
+			return errors.New("WatchShards stream closed by vtctld")
+		case err != nil:
+			return err
+		}
+
+		shard := resp.GetShard()
+		if shard == nil {
+			continue
+		}
+
+		keyspace := shard.Keyspace
+		log.Infof("shard %s/%s changed in cluster %s; updating cached schemas, keyspaces and tablets", keyspace, shard.Name, c.ID)
+
+		schemacache.Invalidate(c.schemaCache, c.ID, keyspace)
+		c.updateTopoCaches(shard)
+
+		if c.onCacheInvalidation != nil {
+			c.onCacheInvalidation(keyspace)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/vtadmin/errors"
+
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// ApplySchema implements the http wrapper for
+// POST /migrations/{cluster_id}/{keyspace}.
+//
+// The request body is a vtctldata.ApplySchemaRequest. A ddl_strategy other
+// than "direct" submits the statements as Online DDL migrations.
+func ApplySchema(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	var req vtctldatapb.ApplySchemaRequest
+	if err := decoder.Decode(&req); err != nil {
+		return NewJSONResponse(nil, &errors.BadRequest{
+			Err: err,
+		})
+	}
+
+	req.Keyspace = vars["keyspace"]
+
+	resp, err := api.server.ApplySchema(ctx, &vtadminpb.ApplySchemaRequest{
+		ClusterId: vars["cluster_id"],
+		Options:   &req,
+	})
+	return NewJSONResponse(resp, err)
+}
+
+// CancelSchemaMigration implements the http wrapper for
+// PUT /migration/{cluster_id}/{keyspace}/{uuid}/cancel.
+func CancelSchemaMigration(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	resp, err := api.server.CancelSchemaMigration(ctx, &vtadminpb.CancelSchemaMigrationRequest{
+		ClusterId: vars["cluster_id"],
+		Options: &vtctldatapb.CancelSchemaMigrationRequest{
+			Keyspace: vars["keyspace"],
+			Uuid:     vars["uuid"],
+		},
+	})
+	return NewJSONResponse(resp, err)
+}
+
+// CancelAllSchemaMigrations implements the http wrapper for
+// PUT /migrations/{cluster_id}/{keyspace}/cancel.
+func CancelAllSchemaMigrations(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	resp, err := api.server.CancelSchemaMigration(ctx, &vtadminpb.CancelSchemaMigrationRequest{
+		ClusterId: vars["cluster_id"],
+		Options: &vtctldatapb.CancelSchemaMigrationRequest{
+			Keyspace: vars["keyspace"],
+		},
+	})
+	return NewJSONResponse(resp, err)
+}
+
+// CompleteSchemaMigration implements the http wrapper for
+// PUT /migration/{cluster_id}/{keyspace}/{uuid}/complete.
+func CompleteSchemaMigration(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	resp, err := api.server.CompleteSchemaMigration(ctx, &vtadminpb.CompleteSchemaMigrationRequest{
+		ClusterId: vars["cluster_id"],
+		Options: &vtctldatapb.CompleteSchemaMigrationRequest{
+			Keyspace: vars["keyspace"],
+			Uuid:     vars["uuid"],
+		},
+	})
+	return NewJSONResponse(resp, err)
+}
+
+// GetSchemaMigrations implements the http wrapper for
+// GET /migrations/{cluster_id}/{keyspace}[?uuid=|migration_context=|status=][&recent=].
+//
+// recent is a duration (e.g. "24h") limiting the results to migrations
+// requested within that window.
+func GetSchemaMigrations(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+	query := r.URL.Query()
+
+	req := &vtctldatapb.GetSchemaMigrationsRequest{
+		Keyspace:         vars["keyspace"],
+		Uuid:             query.Get("uuid"),
+		MigrationContext: query.Get("migration_context"),
+		Status:           query.Get("status"),
+	}
+
+	if param := query.Get("recent"); param != "" {
+		recent, err := time.ParseDuration(param)
+		if err != nil {
+			return NewJSONResponse(nil, &errors.BadRequest{
+				Err: err,
+			})
+		}
+
+		req.Recent = protoutil.DurationToProto(recent)
+	}
+
+	resp, err := api.server.GetSchemaMigrations(ctx, &vtadminpb.GetSchemaMigrationsRequest{
+		ClusterId: vars["cluster_id"],
+		Options:   req,
+	})
+	return NewJSONResponse(resp, err)
+}
+
+// RetrySchemaMigration implements the http wrapper for
+// PUT /migration/{cluster_id}/{keyspace}/{uuid}/retry.
+func RetrySchemaMigration(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	resp, err := api.server.RetrySchemaMigration(ctx, &vtadminpb.RetrySchemaMigrationRequest{
+		ClusterId: vars["cluster_id"],
+		Options: &vtctldatapb.RetrySchemaMigrationRequest{
+			Keyspace: vars["keyspace"],
+			Uuid:     vars["uuid"],
+		},
+	})
+	return NewJSONResponse(resp, err)
+}
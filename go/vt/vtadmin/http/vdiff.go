@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"vitess.io/vitess/go/vt/vtadmin/errors"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// VDiffCreate implements the http wrapper for
+// POST /vdiff/{cluster_id}/{keyspace}/{workflow}.
+//
+// The request body is optional, and may specify a uuid for the new VDiff and
+// a set of tabletmanagerdata.VDiffOptions.
+func VDiffCreate(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	var body struct {
+		UUID    string                            `json:"uuid"`
+		Options *tabletmanagerdatapb.VDiffOptions `json:"options"`
+	}
+
+	if err := decoder.Decode(&body); err != nil && err != io.EOF {
+		return NewJSONResponse(nil, &errors.BadRequest{
+			Err: err,
+		})
+	}
+
+	resp, err := api.server.VDiffCreate(ctx, &vtadminpb.VDiffCreateRequest{
+		ClusterId: vars["cluster_id"],
+		Options: &vtctldatapb.VDiffCreateRequest{
+			Workflow:       vars["workflow"],
+			TargetKeyspace: vars["keyspace"],
+			Uuid:           body.UUID,
+			Options:        body.Options,
+		},
+	})
+	return NewJSONResponse(resp, err)
+}
+
+// VDiffShow implements the http wrapper for
+// GET /vdiff/{cluster_id}/{keyspace}/{workflow}/{arg}, where arg is one of
+// "all", "last", or a VDiff uuid.
+func VDiffShow(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	resp, err := api.server.VDiffShow(ctx, &vtadminpb.VDiffShowRequest{
+		ClusterId: vars["cluster_id"],
+		Options: &vtctldatapb.VDiffShowRequest{
+			Workflow:       vars["workflow"],
+			TargetKeyspace: vars["keyspace"],
+			Arg:            vars["arg"],
+		},
+	})
+	return NewJSONResponse(resp, err)
+}
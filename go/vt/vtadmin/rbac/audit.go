@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/log"
+)
+
+// AuditOutcome is the outcome of an audited action.
+type AuditOutcome string
+
+// AuditOutcome definitions.
+const (
+	// AuditDenied is the outcome of an action the actor was not authorized
+	// to take.
+	AuditDenied AuditOutcome = "denied"
+	// AuditFailure is the outcome of an authorized action that failed.
+	AuditFailure AuditOutcome = "failure"
+	// AuditSuccess is the outcome of an authorized action that succeeded.
+	AuditSuccess AuditOutcome = "success"
+)
+
+// AuditEvent records a single mutating action taken (or attempted) through
+// the vtadmin API.
+type AuditEvent struct {
+	Time      time.Time    `json:"time"`
+	Actor     *Actor       `json:"actor"`
+	ClusterID string       `json:"cluster_id"`
+	Resource  Resource     `json:"resource"`
+	Action    Action       `json:"action"`
+	Keyspace  string       `json:"keyspace,omitempty"`
+	Shard     string       `json:"shard,omitempty"`
+	Outcome   AuditOutcome `json:"outcome"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// Auditor records audit events.
+type Auditor interface {
+	Audit(event *AuditEvent)
+}
+
+// logAuditor writes audit events to the vitess log.
+type logAuditor struct{}
+
+func (logAuditor) Audit(event *AuditEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Errorf("[rbac]: failed to marshal audit event %+v: %s", event, err)
+		return
+	}
+
+	log.Infof("[rbac.audit]: %s", data)
+}
+
+// fileAuditor writes audit events to a file, as newline-delimited JSON.
+type fileAuditor struct {
+	m sync.Mutex
+	f *os.File
+}
+
+func newFileAuditor(path string) (*fileAuditor, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileAuditor{f: f}, nil
+}
+
+func (fa *fileAuditor) Audit(event *AuditEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Errorf("[rbac]: failed to marshal audit event %+v: %s", event, err)
+		return
+	}
+
+	fa.m.Lock()
+	defer fa.m.Unlock()
+
+	if _, err := fa.f.Write(append(data, '\n')); err != nil {
+		log.Errorf("[rbac]: failed to write audit event %s: %s", data, err)
+	}
+}
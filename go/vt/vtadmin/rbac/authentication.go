@@ -88,10 +88,12 @@ func AuthenticationUnaryInterceptor(authn Authenticator) grpc.UnaryServerInterce
 }
 
 // Actor represents the subject in the "subject action resource" of an
-// authorization check. It has a name and many roles.
+// authorization check. It has a name, many roles, and many groups. Groups are
+// typically claims from an identity provider, as extracted by an Authenticator.
 type Actor struct {
-	Name  string   `json:"name"`
-	Roles []string `json:"roles"`
+	Name   string   `json:"name"`
+	Roles  []string `json:"roles"`
+	Groups []string `json:"groups,omitempty"`
 }
 
 type actorkey struct{}
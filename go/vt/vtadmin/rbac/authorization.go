@@ -18,13 +18,19 @@ package rbac
 
 import (
 	"context"
+	"sync"
+	"time"
 )
 
 // Authorizer contains a set of rules that determine which actors may take which
-// actions on which resources in which clusters.
+// actions on which resources in which clusters. Its rules may be replaced at
+// runtime via Reload.
 type Authorizer struct {
+	m sync.RWMutex
 	// keyed by resource name
 	policies map[string][]*Rule
+
+	auditor Auditor
 }
 
 // NewAuthorizer returns a new Authorizer based on the given Config, which
@@ -35,10 +41,12 @@ type Authorizer struct {
 //
 // 	authz, err := rbac.NewAuthorizer(&rbac.Config{
 // 		Rules: []*struct {
-// 			Resource string
-// 			Actions  []string
-// 			Subjects []string
-// 			Clusters []string
+// 			Resource  string
+// 			Actions   []string
+// 			Subjects  []string
+// 			Clusters  []string
+// 			Keyspaces []string
+// 			Shards    []string
 // 		}{
 // 			{
 // 				Resource: "*",
@@ -55,17 +63,52 @@ func NewAuthorizer(cfg *Config) (*Authorizer, error) {
 
 	return &Authorizer{
 		policies: cfg.cfg,
+		auditor:  cfg.auditor,
 	}, nil
 }
 
 // IsAuthorized returns whether an Actor (from the context) is permitted to take
 // the given action on the given resource in the given cluster.
+//
+// Only rules that are not restricted to particular keyspaces or shards are
+// considered; use IsAuthorizedInScope for actions on a particular keyspace or
+// shard.
 func (authz *Authorizer) IsAuthorized(ctx context.Context, clusterID string, resource Resource, action Action) bool {
 	actor, _ := FromContext(ctx) // nil is ok here, since rule.Allows handles it
+	return authz.anyRule(resource, func(rule *Rule) bool {
+		return rule.Allows(clusterID, action, actor)
+	})
+}
+
+// IsAuthorizedInScope returns whether an Actor (from the context) is permitted
+// to take the given action on the given resource in the given cluster, where
+// the resource belongs to the keyspace and shard of the given scope.
+func (authz *Authorizer) IsAuthorizedInScope(ctx context.Context, clusterID string, resource Resource, action Action, scope Scope) bool {
+	actor, _ := FromContext(ctx) // nil is ok here, since rule.AllowsInScope handles it
+	return authz.anyRule(resource, func(rule *Rule) bool {
+		return rule.AllowsInScope(clusterID, action, actor, scope)
+	})
+}
+
+// IsAuthorizedInAnyScope returns whether an Actor (from the context) is
+// permitted to take the given action on the given resource in the given cluster
+// in at least one keyspace or shard. Callers must still check
+// IsAuthorizedInScope once the resource's keyspace and shard are known.
+func (authz *Authorizer) IsAuthorizedInAnyScope(ctx context.Context, clusterID string, resource Resource, action Action) bool {
+	actor, _ := FromContext(ctx) // nil is ok here, since rule.AllowsInAnyScope handles it
+	return authz.anyRule(resource, func(rule *Rule) bool {
+		return rule.AllowsInAnyScope(clusterID, action, actor)
+	})
+}
+
+func (authz *Authorizer) anyRule(resource Resource, allows func(rule *Rule) bool) bool {
+	authz.m.RLock()
+	defer authz.m.RUnlock()
+
 	if p, ok := authz.policies["*"]; ok {
 		// We have policies for the wildcard resource to check first
 		for _, rule := range p {
-			if rule.Allows(clusterID, action, actor) {
+			if allows(rule) {
 				return true
 			}
 		}
@@ -73,7 +116,7 @@ func (authz *Authorizer) IsAuthorized(ctx context.Context, clusterID string, res
 
 	if p, ok := authz.policies[string(resource)]; ok {
 		for _, rule := range p {
-			if rule.Allows(clusterID, action, actor) {
+			if allows(rule) {
 				return true
 			}
 		}
@@ -81,3 +124,61 @@ func (authz *Authorizer) IsAuthorized(ctx context.Context, clusterID string, res
 
 	return false
 }
+
+// Reload replaces the authorizer's rules with the rules from the given config.
+// If the rules are invalid, the current rules are kept and an error is
+// returned. The config's authenticator and audit log are ignored.
+func (authz *Authorizer) Reload(cfg *Config) error {
+	if err := cfg.reifyRules(); err != nil {
+		return err
+	}
+
+	authz.m.Lock()
+	defer authz.m.Unlock()
+
+	authz.policies = cfg.cfg
+	return nil
+}
+
+// Audit records the outcome of a mutating action the Actor (from the context)
+// was authorized to take. A nil error is recorded as a success, and a non-nil
+// error as a failure.
+func (authz *Authorizer) Audit(ctx context.Context, clusterID string, resource Resource, action Action, scope Scope, err error) {
+	outcome := AuditSuccess
+	if err != nil {
+		outcome = AuditFailure
+	}
+
+	authz.audit(ctx, clusterID, resource, action, scope, outcome, err)
+}
+
+// AuditDenied records a mutating action the Actor (from the context) was not
+// authorized to take.
+func (authz *Authorizer) AuditDenied(ctx context.Context, clusterID string, resource Resource, action Action, scope Scope) {
+	authz.audit(ctx, clusterID, resource, action, scope, AuditDenied, nil)
+}
+
+func (authz *Authorizer) audit(ctx context.Context, clusterID string, resource Resource, action Action, scope Scope, outcome AuditOutcome, err error) {
+	actor, _ := FromContext(ctx)
+	event := &AuditEvent{
+		Time:      time.Now().UTC(),
+		Actor:     actor,
+		ClusterID: clusterID,
+		Resource:  resource,
+		Action:    action,
+		Keyspace:  scope.Keyspace,
+		Shard:     scope.Shard,
+		Outcome:   outcome,
+	}
+
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	var auditor Auditor = logAuditor{}
+	if authz.auditor != nil {
+		auditor = authz.auditor
+	}
+
+	auditor.Audit(event)
+}
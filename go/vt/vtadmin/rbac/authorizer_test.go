@@ -18,6 +18,11 @@ package rbac
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -29,10 +34,12 @@ func TestIsAuthorized(t *testing.T) {
 
 	authz, err := NewAuthorizer(&Config{
 		Rules: []*struct {
-			Resource string
-			Actions  []string
-			Subjects []string
-			Clusters []string
+			Resource  string
+			Actions   []string
+			Subjects  []string
+			Clusters  []string
+			Keyspaces []string
+			Shards    []string
 		}{
 			{
 				Resource: "*",
@@ -116,3 +123,216 @@ func TestIsAuthorized(t *testing.T) {
 		})
 	}
 }
+
+func TestIsAuthorizedInScope(t *testing.T) {
+	t.Parallel()
+
+	authz, err := NewAuthorizer(&Config{
+		Rules: []*struct {
+			Resource  string
+			Actions   []string
+			Subjects  []string
+			Clusters  []string
+			Keyspaces []string
+			Shards    []string
+		}{
+			{
+				Resource: string(ShardResource),
+				Actions:  []string{string(GetAction)},
+				Subjects: []string{"*"},
+				Clusters: []string{"*"},
+			},
+			{
+				Resource:  string(ShardResource),
+				Actions:   []string{string(PlannedFailoverShardAction)},
+				Subjects:  []string{"group:commerce-team"},
+				Clusters:  []string{"*"},
+				Keyspaces: []string{"commerce_*"},
+			},
+			{
+				Resource:  string(ShardResource),
+				Actions:   []string{string(EmergencyFailoverShardAction)},
+				Subjects:  []string{"role:oncall"},
+				Clusters:  []string{"c1"},
+				Keyspaces: []string{"customer"},
+				Shards:    []string{"-80", "80-"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	commerceTeam := &Actor{Name: "someuser", Groups: []string{"commerce-team"}}
+	oncall := &Actor{Name: "someuser", Roles: []string{"oncall"}}
+
+	tests := []struct {
+		name         string
+		actor        *Actor
+		clusterID    string
+		action       Action
+		scope        Scope
+		isAuthorized bool
+	}{
+		{
+			name:         "unscoped rule allows any scope",
+			actor:        nil,
+			clusterID:    "c1",
+			action:       GetAction,
+			scope:        Scope{Keyspace: "customer", Shard: "-80"},
+			isAuthorized: true,
+		},
+		{
+			name:         "group in matching keyspace",
+			actor:        commerceTeam,
+			clusterID:    "c1",
+			action:       PlannedFailoverShardAction,
+			scope:        Scope{Keyspace: "commerce_eu", Shard: "-"},
+			isAuthorized: true,
+		},
+		{
+			name:         "group in other keyspace",
+			actor:        commerceTeam,
+			clusterID:    "c1",
+			action:       PlannedFailoverShardAction,
+			scope:        Scope{Keyspace: "customer", Shard: "-"},
+			isAuthorized: false,
+		},
+		{
+			name:         "scoped rule does not allow unscoped action",
+			actor:        commerceTeam,
+			clusterID:    "c1",
+			action:       PlannedFailoverShardAction,
+			scope:        Scope{},
+			isAuthorized: false,
+		},
+		{
+			name:         "matching keyspace and shard",
+			actor:        oncall,
+			clusterID:    "c1",
+			action:       EmergencyFailoverShardAction,
+			scope:        Scope{Keyspace: "customer", Shard: "80-"},
+			isAuthorized: true,
+		},
+		{
+			name:         "matching keyspace in other shard",
+			actor:        oncall,
+			clusterID:    "c1",
+			action:       EmergencyFailoverShardAction,
+			scope:        Scope{Keyspace: "customer", Shard: "-"},
+			isAuthorized: false,
+		},
+		{
+			name:         "matching scope in other cluster",
+			actor:        oncall,
+			clusterID:    "c2",
+			action:       EmergencyFailoverShardAction,
+			scope:        Scope{Keyspace: "customer", Shard: "80-"},
+			isAuthorized: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := NewContext(context.Background(), tt.actor)
+			got := authz.IsAuthorizedInScope(ctx, tt.clusterID, ShardResource, tt.action, tt.scope)
+			assert.Equal(t, tt.isAuthorized, got)
+
+			if tt.isAuthorized {
+				assert.True(t, authz.IsAuthorizedInAnyScope(ctx, tt.clusterID, ShardResource, tt.action), "actor authorized in scope %+v should be authorized in some scope", tt.scope)
+			}
+		})
+	}
+
+	ctx := NewContext(context.Background(), commerceTeam)
+	assert.False(t, authz.IsAuthorized(ctx, "c1", ShardResource, PlannedFailoverShardAction), "keyspace-scoped rules should not allow cluster-wide actions")
+}
+
+func TestReload(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rbac.yaml")
+	auditPath := filepath.Join(dir, "audit.log")
+
+	err := os.WriteFile(path, []byte(fmt.Sprintf(`auditlog: %s
+rules:
+  - resource: Shard
+    actions: ["planned_failover_shard"]
+    subjects: ["user:alice"]
+    clusters: ["*"]
+    keyspaces: ["commerce"]
+`, auditPath)), 0644)
+	require.NoError(t, err)
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	authz := cfg.GetAuthorizer()
+	alice := NewContext(context.Background(), &Actor{Name: "alice"})
+	bob := NewContext(context.Background(), &Actor{Name: "bob"})
+	scope := Scope{Keyspace: "commerce", Shard: "-"}
+
+	assert.True(t, authz.IsAuthorizedInScope(alice, "c1", ShardResource, PlannedFailoverShardAction, scope))
+	assert.False(t, authz.IsAuthorizedInScope(bob, "c1", ShardResource, PlannedFailoverShardAction, scope))
+
+	err = os.WriteFile(path, []byte(`rules:
+  - resource: Shard
+    actions: ["planned_failover_shard"]
+    subjects: ["user:bob"]
+    clusters: ["*"]
+    keyspaces: ["commerce"]
+`), 0644)
+	require.NoError(t, err)
+
+	newCfg, err := readConfig(path)
+	require.NoError(t, err)
+	require.NoError(t, authz.Reload(newCfg))
+
+	assert.False(t, authz.IsAuthorizedInScope(alice, "c1", ShardResource, PlannedFailoverShardAction, scope))
+	assert.True(t, authz.IsAuthorizedInScope(bob, "c1", ShardResource, PlannedFailoverShardAction, scope))
+
+	// Invalid rules are rejected, and the current rules are kept.
+	invalidCfg := &Config{
+		Rules: []*struct {
+			Resource  string
+			Actions   []string
+			Subjects  []string
+			Clusters  []string
+			Keyspaces []string
+			Shards    []string
+		}{
+			{
+				Resource:  string(ShardResource),
+				Actions:   []string{"*"},
+				Subjects:  []string{"*"},
+				Clusters:  []string{"*"},
+				Keyspaces: []string{"[commerce"},
+			},
+		},
+	}
+	assert.Error(t, authz.Reload(invalidCfg))
+	assert.True(t, authz.IsAuthorizedInScope(bob, "c1", ShardResource, PlannedFailoverShardAction, scope))
+
+	// Audit events go to the file from the original config.
+	authz.AuditDenied(alice, "c1", ShardResource, PlannedFailoverShardAction, scope)
+	authz.Audit(bob, "c1", ShardResource, PlannedFailoverShardAction, scope, nil)
+
+	data, err := os.ReadFile(auditPath)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var event AuditEvent
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &event))
+	assert.Equal(t, "alice", event.Actor.Name)
+	assert.Equal(t, AuditDenied, event.Outcome)
+	assert.Equal(t, "commerce", event.Keyspace)
+
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &event))
+	assert.Equal(t, "bob", event.Actor.Name)
+	assert.Equal(t, AuditSuccess, event.Outcome)
+}
@@ -17,8 +17,12 @@ limitations under the License.
 package rbac
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -32,11 +36,16 @@ import (
 // cfg.Reify. A config must be reified before first use.
 type Config struct {
 	Authenticator string
-	Rules         []*struct {
-		Resource string
-		Actions  []string
-		Subjects []string
-		Clusters []string
+	// AuditLog is the path of a file to append audit events to, one JSON
+	// object per line. If empty, audit events are written to the vtadmin log.
+	AuditLog string
+	Rules    []*struct {
+		Resource  string
+		Actions   []string
+		Subjects  []string
+		Clusters  []string
+		Keyspaces []string
+		Shards    []string
 	}
 
 	reified bool
@@ -44,6 +53,7 @@ type Config struct {
 	cfg           map[string][]*Rule
 	authenticator Authenticator
 	authorizer    *Authorizer
+	auditor       Auditor
 }
 
 // LoadConfig reads the file at path into a Config struct, and then reifies
@@ -54,6 +64,19 @@ type Config struct {
 // Any file format supported by viper is supported. Currently this is yaml, json
 // or toml.
 func LoadConfig(path string) (*Config, error) {
+	cfg, err := readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Reify(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func readConfig(path string) (*Config, error) {
 	v := viper.New()
 	v.SetConfigFile(path)
 	if err := v.ReadInConfig(); err != nil {
@@ -65,11 +88,60 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, err
 	}
 
-	if err := cfg.Reify(); err != nil {
-		return nil, err
+	return &cfg, nil
+}
+
+// WatchConfig polls the config file at path every interval, and reloads the
+// authorizer's rules whenever the file changes. Invalid configs are logged and
+// ignored, leaving the authorizer's current rules in place. Only the rules are
+// reloaded; changing the authenticator or audit log requires a restart.
+//
+// WatchConfig blocks until the context is cancelled, so callers should run it
+// in a separate goroutine.
+func WatchConfig(ctx context.Context, path string, interval time.Duration, authz *Authorizer) {
+	var (
+		modTime time.Time
+		size    int64
+	)
+
+	if fi, err := os.Stat(path); err == nil {
+		modTime, size = fi.ModTime(), fi.Size()
 	}
 
-	return &cfg, nil
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		fi, err := os.Stat(path)
+		if err != nil {
+			log.Warningf("[rbac]: failed to stat config %s: %s", path, err)
+			continue
+		}
+
+		if fi.ModTime().Equal(modTime) && fi.Size() == size {
+			continue
+		}
+
+		modTime, size = fi.ModTime(), fi.Size()
+
+		cfg, err := readConfig(path)
+		if err == nil {
+			err = authz.Reload(cfg)
+		}
+
+		if err != nil {
+			log.Errorf("[rbac]: failed to reload config %s, keeping current rules: %s", path, err)
+			continue
+		}
+
+		log.Infof("[rbac]: reloaded config %s", path)
+	}
 }
 
 // Reify makes a config that was loaded from a file usable, by validating the
@@ -82,7 +154,51 @@ func (c *Config) Reify() error {
 		return nil
 	}
 
-	// reify the rules
+	if err := c.reifyRules(); err != nil {
+		return err
+	}
+
+	// reify the auditor
+	if c.AuditLog != "" {
+		auditor, err := newFileAuditor(c.AuditLog)
+		if err != nil {
+			return fmt.Errorf("cannot open audit log: %w", err)
+		}
+
+		c.auditor = auditor
+	}
+
+	c.authorizer = &Authorizer{
+		policies: c.cfg,
+		auditor:  c.auditor,
+	}
+
+	// reify the authenticator
+	switch {
+	case strings.HasSuffix(c.Authenticator, ".so"):
+		authn, err := loadAuthenticatorPlugin(c.Authenticator)
+		if err != nil {
+			return err
+		}
+
+		c.authenticator = authn
+	case c.Authenticator != "":
+		factory, ok := authenticators[c.Authenticator]
+		if !ok {
+			return fmt.Errorf("%w %s", ErrUnregisteredAuthenticationImpl, c.Authenticator)
+		}
+
+		c.authenticator = factory()
+	default:
+		log.Info("[rbac]: no authenticator implementation specified")
+		c.authenticator = nil // Technically a no-op, but being super explicit about it.
+	}
+
+	c.reified = true
+	return nil
+}
+
+func (c *Config) reifyRules() error {
 	byResource := map[string][]*Rule{}
 	rec := concurrency.AllErrorRecorder{}
 
@@ -107,10 +223,18 @@ func (c *Config) Reify() error {
 			rec.RecordError(fmt.Errorf("rule %d: clusters list cannot include wildcard and other clusters, have %v", i, clusters.List()))
 		}
 
+		for _, pattern := range append(append([]string{}, rule.Keyspaces...), rule.Shards...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				rec.RecordError(fmt.Errorf("rule %d: invalid keyspace or shard pattern %q: %w", i, pattern, err))
+			}
+		}
+
 		resourceRules = append(resourceRules, &Rule{
-			actions:  actions,
-			subjects: subjects,
-			clusters: clusters,
+			actions:   actions,
+			subjects:  subjects,
+			clusters:  clusters,
+			keyspaces: rule.Keyspaces,
+			shards:    rule.Shards,
 		})
 		byResource[rule.Resource] = resourceRules
 	}
@@ -122,32 +246,6 @@ func (c *Config) Reify() error {
 	log.Infof("[rbac]: loaded authorizer with %d rules", len(c.Rules))
 
 	c.cfg = byResource
-	c.authorizer = &Authorizer{
-		policies: c.cfg,
-	}
-
-	// reify the authenticator
-	switch {
-	case strings.HasSuffix(c.Authenticator, ".so"):
-		authn, err := loadAuthenticatorPlugin(c.Authenticator)
-		if err != nil {
-			return err
-		}
-
-		c.authenticator = authn
-	case c.Authenticator != "":
-		factory, ok := authenticators[c.Authenticator]
-		if !ok {
-			return fmt.Errorf("%w %s", ErrUnregisteredAuthenticationImpl, c.Authenticator)
-		}
-
-		c.authenticator = factory()
-	default:
-		log.Info("[rbac]: no authenticator implementation specified")
-		c.authenticator = nil // Technically a no-op, but being super explicit about it.
-	}
-
-	c.reified = true
 	return nil
 }
 
@@ -184,10 +282,12 @@ func DefaultConfig() *Config {
 
 	return &Config{
 		Rules: []*struct {
-			Resource string
-			Actions  []string
-			Subjects []string
-			Clusters []string
+			Resource  string
+			Actions   []string
+			Subjects  []string
+			Clusters  []string
+			Keyspaces []string
+			Shards    []string
 		}{
 			{
 				Resource: "*",
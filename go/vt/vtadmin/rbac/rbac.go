@@ -54,6 +54,20 @@ check before calling into the cluster.
 5. Being unauthorized for an <action, resource> for a cluster does not fail the
 overall request. Instead, the action is simply not taken in that cluster, and is
 still taken in other clusters for which the actor is authorized.
+
+6. Rules may be scoped to keyspaces and shards. A rule with keyspace or shard
+patterns only grants an action on resources in matching keyspaces and shards,
+such as reparenting a shard or managing a tablet. Scoped rules never grant
+cluster-wide actions, such as listing all keyspaces or tablets in a cluster.
+
+Subjects in a rule are either the wildcard ("*"), or one of "user:<name>",
+"role:<role>" or "group:<group>", matched against the Actor's name, roles and
+groups, respectively.
+
+Rules may be reloaded at runtime (see WatchConfig), and every mutating action
+taken through the API is recorded in an audit log, including the actor, the
+cluster, keyspace and shard, and whether the action was denied, succeeded or
+failed.
 */
 package rbac
 
@@ -83,6 +97,12 @@ const (
 	ManageTabletReplicationAction        Action = "manage_tablet_replication" // Start/Stop Replication
 	ManageTabletWritabilityAction        Action = "manage_tablet_writability" // SetRead{Only,Write}
 	RefreshTabletReplicationSourceAction Action = "refresh_tablet_replication_source"
+
+	/* schema migration-specific actions */
+
+	CancelSchemaMigrationAction   Action = "cancel_schema_migration"
+	CompleteSchemaMigrationAction Action = "complete_schema_migration"
+	RetrySchemaMigrationAction    Action = "retry_schema_migration"
 )
 
 // Resource is an enum representing all resources managed by vtadmin.
@@ -111,7 +131,9 @@ const (
 
 	BackupResource                   Resource = "Backup"
 	SchemaResource                   Resource = "Schema"
+	SchemaMigrationResource          Resource = "SchemaMigration"
 	ShardReplicationPositionResource Resource = "ShardReplicationPosition"
+	VDiffResource                    Resource = "VDiff"
 	WorkflowResource                 Resource = "Workflow"
 
 	VTExplainResource Resource = "VTExplain"
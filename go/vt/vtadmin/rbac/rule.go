@@ -18,6 +18,7 @@ package rbac
 
 import (
 	"fmt"
+	"path"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 )
@@ -27,6 +28,20 @@ type Rule struct {
 	clusters sets.String
 	actions  sets.String
 	subjects sets.String
+
+	// keyspaces and shards are optional lists of glob patterns (in the syntax
+	// of path.Match) restricting the rule to resources in matching keyspaces
+	// and shards. An empty list does not restrict the rule.
+	keyspaces []string
+	shards    []string
+}
+
+// Scope identifies the keyspace and shard an action is taken in, for rules
+// that are restricted to particular keyspaces or shards. The zero value is the
+// unscoped (cluster-wide) scope.
+type Scope struct {
+	Keyspace string
+	Shard    string
 }
 
 // Allows returns true if the actor is allowed to take the specified action in
@@ -34,7 +49,36 @@ type Rule struct {
 //
 // A nil actor signifies the unauthenticated state, and is only allowed access
 // if the rule contains the wildcard ("*") subject.
+//
+// Rules restricted to particular keyspaces or shards never allow cluster-wide
+// actions; use AllowsInScope to check those rules.
 func (r *Rule) Allows(clusterID string, action Action, actor *Actor) bool {
+	if len(r.keyspaces) > 0 || len(r.shards) > 0 {
+		return false
+	}
+
+	return r.allows(clusterID, action, actor)
+}
+
+// AllowsInScope returns true if the actor is allowed to take the specified
+// action in the specified cluster, on a resource in the given keyspace and
+// shard.
+func (r *Rule) AllowsInScope(clusterID string, action Action, actor *Actor, scope Scope) bool {
+	if !matchesAny(r.keyspaces, scope.Keyspace) || !matchesAny(r.shards, scope.Shard) {
+		return false
+	}
+
+	return r.allows(clusterID, action, actor)
+}
+
+// AllowsInAnyScope returns true if the actor is allowed to take the specified
+// action in the specified cluster in at least one keyspace or shard. It is used
+// to skip clusters before the scope of a resource is known.
+func (r *Rule) AllowsInAnyScope(clusterID string, action Action, actor *Actor) bool {
+	return r.allows(clusterID, action, actor)
+}
+
+func (r *Rule) allows(clusterID string, action Action, actor *Actor) bool {
 	if r.clusters.HasAny("*", clusterID) {
 		if r.actions.HasAny("*", string(action)) {
 			if r.subjects.Has("*") {
@@ -54,6 +98,29 @@ func (r *Rule) Allows(clusterID string, action Action, actor *Actor) bool {
 					return true
 				}
 			}
+
+			for _, group := range actor.Groups {
+				if r.subjects.Has(fmt.Sprintf("group:%s", group)) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// matchesAny returns true if name matches any of the patterns, or if there are
+// no patterns at all. Patterns are validated when the config is reified, so
+// match errors are treated as non-matches.
+func matchesAny(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
 		}
 	}
 
@@ -70,6 +70,18 @@ func _inlineActor(buf *strings.Builder, actor rbac.Actor) {
 		buf.WriteString("}")
 	}
 
+	if actor.Groups != nil {
+		buf.WriteString(", Groups: []string{")
+		for i, group := range actor.Groups {
+			buf.WriteString(strings.Join([]string{`"`, group, `"`}, ""))
+			if i != len(actor.Groups)-1 {
+				buf.WriteString(", ")
+			}
+		}
+
+		buf.WriteString("}")
+	}
+
 	buf.WriteString("}")
 }
 
@@ -60,10 +60,12 @@ type TestCase struct {
 }
 
 type AuthzRules struct {
-	Resource string   `json:"resource"`
-	Actions  []string `json:"actions"`
-	Subjects []string `json:"subjects"`
-	Clusters []string `json:"clusters"`
+	Resource  string   `json:"resource"`
+	Actions   []string `json:"actions"`
+	Subjects  []string `json:"subjects"`
+	Clusters  []string `json:"clusters"`
+	Keyspaces []string `json:"keyspaces"`
+	Shards    []string `json:"shards"`
 }
 
 type FakeVtctldClientResult struct {
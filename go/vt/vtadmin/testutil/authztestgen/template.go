@@ -68,10 +68,12 @@ func Test{{ .Method }}(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct{
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+				Shards    []string
 			}{
 				{{- range .Rules }}
 				{
@@ -79,6 +81,12 @@ func Test{{ .Method }}(t *testing.T) {
 					Actions:  []string{ {{ range .Actions }}"{{ . }}",{{ end }} },
 					Subjects: []string{ {{ range .Subjects }}"{{ . }}",{{ end }} },
 					Clusters: []string{ {{ range .Clusters }}"{{ . }}",{{ end }} },
+					{{- if .Keyspaces }}
+					Keyspaces: []string{ {{ range .Keyspaces }}"{{ . }}",{{ end }} },
+					{{- end }}
+					{{- if .Shards }}
+					Shards: []string{ {{ range .Shards }}"{{ . }}",{{ end }} },
+					{{- end }}
 				},
 				{{- end }}
 			},
@@ -38,6 +38,19 @@ import (
 type VtctldClient struct {
 	vtctldclient.VtctldClient
 
+	ApplySchemaResults map[string]struct {
+		Response *vtctldatapb.ApplySchemaResponse
+		Error    error
+	}
+	CancelSchemaMigrationResults map[string]struct {
+		Response *vtctldatapb.CancelSchemaMigrationResponse
+		Error    error
+	}
+	CompleteSchemaMigrationResults map[string]struct {
+		Response *vtctldatapb.CompleteSchemaMigrationResponse
+		Error    error
+	}
+
 	CreateKeyspaceShouldErr bool
 	CreateShardShouldErr    bool
 	DeleteKeyspaceShouldErr bool
@@ -81,6 +94,10 @@ type VtctldClient struct {
 		Response *vtctldatapb.GetSchemaResponse
 		Error    error
 	}
+	GetSchemaMigrationsResults map[string]struct {
+		Response *vtctldatapb.GetSchemaMigrationsResponse
+		Error    error
+	}
 	GetSrvVSchemaResults map[string]struct {
 		Response *vtctldatapb.GetSrvVSchemaResponse
 		Error    error
@@ -115,6 +132,10 @@ type VtctldClient struct {
 		Response *vtctldatapb.ReparentTabletResponse
 		Error    error
 	}
+	RetrySchemaMigrationResults map[string]struct {
+		Response *vtctldatapb.RetrySchemaMigrationResponse
+		Error    error
+	}
 	RunHealthCheckResults            map[string]error
 	SetWritableResults               map[string]error
 	ShardReplicationPositionsResults map[string]struct {
@@ -127,6 +148,16 @@ type VtctldClient struct {
 		Response *vtctldatapb.TabletExternallyReparentedResponse
 		Error    error
 	}
+	// Keyed by <keyspace/workflow>.
+	VDiffCreateResults map[string]struct {
+		Response *vtctldatapb.VDiffCreateResponse
+		Error    error
+	}
+	// Keyed by <keyspace/workflow>.
+	VDiffShowResults map[string]struct {
+		Response *vtctldatapb.VDiffShowResponse
+		Error    error
+	}
 	ValidateKeyspaceResults map[string]struct {
 		Response *vtctldatapb.ValidateKeyspaceResponse
 		Error    error
@@ -145,6 +176,48 @@ type VtctldClient struct {
 // incorrectly.
 var _ vtctldclient.VtctldClient = (*VtctldClient)(nil)
 
+// ApplySchema is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) ApplySchema(ctx context.Context, req *vtctldatapb.ApplySchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplySchemaResponse, error) {
+	if fake.ApplySchemaResults == nil {
+		return nil, fmt.Errorf("%w: ApplySchemaResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	key := req.Keyspace
+	if result, ok := fake.ApplySchemaResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, key)
+}
+
+// CancelSchemaMigration is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) CancelSchemaMigration(ctx context.Context, req *vtctldatapb.CancelSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.CancelSchemaMigrationResponse, error) {
+	if fake.CancelSchemaMigrationResults == nil {
+		return nil, fmt.Errorf("%w: CancelSchemaMigrationResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	key := req.Keyspace
+	if result, ok := fake.CancelSchemaMigrationResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, key)
+}
+
+// CompleteSchemaMigration is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) CompleteSchemaMigration(ctx context.Context, req *vtctldatapb.CompleteSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.CompleteSchemaMigrationResponse, error) {
+	if fake.CompleteSchemaMigrationResults == nil {
+		return nil, fmt.Errorf("%w: CompleteSchemaMigrationResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	key := req.Keyspace
+	if result, ok := fake.CompleteSchemaMigrationResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, key)
+}
+
 // Close is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) Close() error { return nil }
 
@@ -361,6 +434,20 @@ func (fake *VtctldClient) GetSchema(ctx context.Context, req *vtctldatapb.GetSch
 	return nil, fmt.Errorf("%w: no result set for tablet alias %s", assert.AnError, key)
 }
 
+// GetSchemaMigrations is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) GetSchemaMigrations(ctx context.Context, req *vtctldatapb.GetSchemaMigrationsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetSchemaMigrationsResponse, error) {
+	if fake.GetSchemaMigrationsResults == nil {
+		return nil, fmt.Errorf("%w: GetSchemaMigrationsResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	key := req.Keyspace
+	if result, ok := fake.GetSchemaMigrationsResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, key)
+}
+
 // GetSrvVSchema is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) GetSrvVSchema(ctx context.Context, req *vtctldatapb.GetSrvVSchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.GetSrvVSchemaResponse, error) {
 	if fake.GetSrvVSchemaResults == nil {
@@ -530,6 +617,20 @@ func (fake *VtctldClient) ReparentTablet(ctx context.Context, req *vtctldatapb.R
 	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
 }
 
+// RetrySchemaMigration is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) RetrySchemaMigration(ctx context.Context, req *vtctldatapb.RetrySchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.RetrySchemaMigrationResponse, error) {
+	if fake.RetrySchemaMigrationResults == nil {
+		return nil, fmt.Errorf("%w: RetrySchemaMigrationResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	key := req.Keyspace
+	if result, ok := fake.RetrySchemaMigrationResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, key)
+}
+
 // RunHealthCheck is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) RunHealthCheck(ctx context.Context, req *vtctldatapb.RunHealthCheckRequest, opts ...grpc.CallOption) (*vtctldatapb.RunHealthCheckResponse, error) {
 	if fake.RunHealthCheckResults == nil {
@@ -630,6 +731,34 @@ func (fake *VtctldClient) TabletExternallyReparented(ctx context.Context, req *v
 	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
 }
 
+// VDiffCreate is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffCreateResponse, error) {
+	if fake.VDiffCreateResults == nil {
+		return nil, fmt.Errorf("%w: VDiffCreateResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	key := fmt.Sprintf("%s/%s", req.TargetKeyspace, req.Workflow)
+	if result, ok := fake.VDiffCreateResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for workflow %s", assert.AnError, key)
+}
+
+// VDiffShow is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) VDiffShow(ctx context.Context, req *vtctldatapb.VDiffShowRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffShowResponse, error) {
+	if fake.VDiffShowResults == nil {
+		return nil, fmt.Errorf("%w: VDiffShowResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	key := fmt.Sprintf("%s/%s", req.TargetKeyspace, req.Workflow)
+	if result, ok := fake.VDiffShowResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for workflow %s", assert.AnError, key)
+}
+
 // ValidateKeyspace is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) ValidateKeyspace(ctx context.Context, req *vtctldatapb.ValidateKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateKeyspaceResponse, error) {
 	if fake.ValidateKeyspaceResults == nil {
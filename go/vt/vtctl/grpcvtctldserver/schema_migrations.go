@@ -0,0 +1,216 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtctldserver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	vttimepb "vitess.io/vitess/go/vt/proto/vttime"
+)
+
+// Migration status hints understood by the Online DDL executor's VExec
+// handler. An UPDATE setting migration_status to one of these values performs
+// the corresponding action rather than literally updating the column.
+const (
+	cancelMigrationHint    = "cancel"
+	cancelAllMigrationHint = "cancel-all"
+	completeMigrationHint  = "complete"
+	retryMigrationHint     = "retry"
+)
+
+// schemaMigrationsVExec runs a _vt.schema_migrations query on the primary of
+// every shard in the keyspace, returning the results keyed by shard name.
+//
+// The query is built per primary by buildQuery, which is passed the primary's
+// database name, since the executor only accepts queries qualified by
+// mysql_schema.
+func (s *VtctldServer) schemaMigrationsVExec(ctx context.Context, keyspace string, uuid string, buildQuery func(dbName string) (string, error)) (map[string]*querypb.QueryResult, error) {
+	shards, err := s.ts.FindAllShardsInKeyspace(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		m       sync.Mutex
+		wg      sync.WaitGroup
+		rec     concurrency.AllErrorRecorder
+		results = make(map[string]*querypb.QueryResult, len(shards))
+	)
+
+	for _, shard := range shards {
+		if !shard.HasPrimary() {
+			rec.RecordError(vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "shard %s/%s has no primary", keyspace, shard.ShardName()))
+			continue
+		}
+
+		wg.Add(1)
+		go func(shardName string) {
+			defer wg.Done()
+
+			primary, err := s.ts.GetTablet(ctx, shards[shardName].PrimaryAlias)
+			if err != nil {
+				rec.RecordError(fmt.Errorf("GetTablet(%v) failed: %w", topoproto.TabletAliasString(shards[shardName].PrimaryAlias), err))
+				return
+			}
+
+			query, err := buildQuery(topoproto.TabletDbName(primary.Tablet))
+			if err != nil {
+				rec.RecordError(err)
+				return
+			}
+
+			qr, err := s.tmc.VExec(ctx, primary.Tablet, query, uuid, keyspace)
+			if err != nil {
+				rec.RecordError(fmt.Errorf("VExec(%v) failed: %w", topoproto.TabletAliasString(primary.Alias), err))
+				return
+			}
+
+			m.Lock()
+			defer m.Unlock()
+			results[shardName] = qr
+		}(shard.ShardName())
+	}
+
+	wg.Wait()
+
+	if rec.HasErrors() {
+		return nil, rec.Error()
+	}
+
+	return results, nil
+}
+
+// updateSchemaMigrationStatus sets the migration_status hint for one (or, if
+// uuid is empty, all) migrations in the keyspace, returning the number of rows
+// affected on each shard.
+func (s *VtctldServer) updateSchemaMigrationStatus(ctx context.Context, keyspace string, uuid string, hint string) (map[string]uint64, error) {
+	if keyspace == "" {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "keyspace is required")
+	}
+
+	if uuid != "" && !schema.IsOnlineDDLUUID(uuid) {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "%s is not a valid migration UUID", uuid)
+	}
+
+	results, err := s.schemaMigrationsVExec(ctx, keyspace, uuid, func(dbName string) (string, error) {
+		if uuid == "" {
+			return sqlparser.ParseAndBind("update _vt.schema_migrations set migration_status=%a where mysql_schema=%a",
+				sqltypes.StringBindVariable(hint),
+				sqltypes.StringBindVariable(dbName),
+			)
+		}
+
+		return sqlparser.ParseAndBind("update _vt.schema_migrations set migration_status=%a where migration_uuid=%a and mysql_schema=%a",
+			sqltypes.StringBindVariable(hint),
+			sqltypes.StringBindVariable(uuid),
+			sqltypes.StringBindVariable(dbName),
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected := make(map[string]uint64, len(results))
+	for shard, qr := range results {
+		rowsAffected[shard] = qr.RowsAffected
+	}
+
+	return rowsAffected, nil
+}
+
+// schemaMigrationsFromResult converts the rows of a
+// `select * from _vt.schema_migrations` query into SchemaMigration messages.
+func schemaMigrationsFromResult(qr *querypb.QueryResult) ([]*vtctldatapb.SchemaMigration, error) {
+	result := sqltypes.Proto3ToResult(qr)
+	migrations := make([]*vtctldatapb.SchemaMigration, 0, len(result.Rows))
+
+	for _, row := range result.Named().Rows {
+		m := &vtctldatapb.SchemaMigration{
+			Uuid:               row.AsString("migration_uuid", ""),
+			Keyspace:           row.AsString("keyspace", ""),
+			Shard:              row.AsString("shard", ""),
+			Schema:             row.AsString("mysql_schema", ""),
+			Table:              row.AsString("mysql_table", ""),
+			MigrationStatement: row.AsString("migration_statement", ""),
+			Strategy:           row.AsString("strategy", ""),
+			Options:            row.AsString("options", ""),
+			AddedAt:            schemaMigrationTimestamp(row, "added_timestamp"),
+			RequestedAt:        schemaMigrationTimestamp(row, "requested_timestamp"),
+			ReadyAt:            schemaMigrationTimestamp(row, "ready_timestamp"),
+			StartedAt:          schemaMigrationTimestamp(row, "started_timestamp"),
+			LivenessTimestamp:  schemaMigrationTimestamp(row, "liveness_timestamp"),
+			CompletedAt:        schemaMigrationTimestamp(row, "completed_timestamp"),
+			Status:             row.AsString("migration_status", ""),
+			Message:            row.AsString("message", ""),
+			MigrationContext:   row.AsString("migration_context", ""),
+			DdlAction:          row.AsString("ddl_action", ""),
+			Progress:           float32(row.AsFloat64("progress", 0)),
+			EtaSeconds:         row.AsInt64("eta_seconds", -1),
+			RowsCopied:         row.AsUint64("rows_copied", 0),
+			TableRows:          row.AsInt64("table_rows", 0),
+			Retries:            row.AsUint64("retries", 0),
+			TabletFailure:      row.AsBool("tablet_failure", false),
+			PostponeCompletion: row.AsBool("postpone_completion", false),
+			ReadyToComplete:    row.AsBool("ready_to_complete", false),
+		}
+
+		if tablet := row.AsString("tablet", ""); tablet != "" {
+			alias, err := topoproto.ParseTabletAlias(tablet)
+			if err != nil {
+				return nil, fmt.Errorf("migration %s has invalid tablet alias %s: %w", m.Uuid, tablet, err)
+			}
+
+			m.Tablet = alias
+		}
+
+		migrations = append(migrations, m)
+	}
+
+	return migrations, nil
+}
+
+// schemaMigrationTimestamp parses a timestamp column of _vt.schema_migrations.
+// NULL or unparseable values result in a nil timestamp.
+func schemaMigrationTimestamp(row sqltypes.RowNamedValues, column string) *vttimepb.Time {
+	val := row.AsString(column, "")
+	if val == "" {
+		return nil
+	}
+
+	t, err := time.Parse(sqltypes.TimestampFormat, val)
+	if err != nil {
+		log.Warningf("cannot parse %s value %q in _vt.schema_migrations: %s", column, val, err)
+		return nil
+	}
+
+	return protoutil.TimeToProto(t)
+}
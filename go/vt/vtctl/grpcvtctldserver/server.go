@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"path/filepath"
 	"sort"
@@ -29,14 +30,17 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/proto"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"vitess.io/vitess/go/event"
+	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/netutil"
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/sync2"
 	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/callerid"
@@ -44,6 +48,7 @@ import (
 	hk "vitess.io/vitess/go/vt/hook"
 	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/schemadiff"
 
 	"vitess.io/vitess/go/vt/schemamanager"
 
@@ -62,6 +67,8 @@ import (
 	"vitess.io/vitess/go/vt/vtctl/schematools"
 	"vitess.io/vitess/go/vt/vtctl/workflow"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vschemadiff"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vdiff"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
 	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
@@ -174,11 +181,20 @@ func (s *VtctldServer) ApplySchema(ctx context.Context, req *vtctldatapb.ApplySc
 	span.Annotate("keyspace", req.Keyspace)
 	span.Annotate("skip_preflight", req.SkipPreflight)
 	span.Annotate("ddl_strategy", req.DdlStrategy)
+	span.Annotate("dry_run", req.DryRun)
 
 	if len(req.Sql) == 0 {
 		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "Sql must be a non-empty array")
 	}
 
+	if req.DryRun {
+		lint, err := s.lintSchemaChanges(ctx, req.Keyspace, req.Sql)
+		if err != nil {
+			return nil, err
+		}
+		return &vtctldatapb.ApplySchemaResponse{Lint: lint}, nil
+	}
+
 	// Attach the callerID as the EffectiveCallerID.
 	if req.CallerId != nil {
 		span.Annotate("caller_id", req.CallerId.Principal)
@@ -253,6 +269,8 @@ func (s *VtctldServer) ApplyVSchema(ctx context.Context, req *vtctldatapb.ApplyV
 	span.Annotate("cells", strings.Join(req.Cells, ","))
 	span.Annotate("skip_rebuild", req.SkipRebuild)
 	span.Annotate("dry_run", req.DryRun)
+	span.Annotate("validate_schema", req.ValidateSchema)
+	span.Annotate("sql_mode", req.Sql != "")
 
 	if _, err := s.ts.GetKeyspace(ctx, req.Keyspace); err != nil {
 		if topo.IsErrType(err, topo.NoNode) {
@@ -265,43 +283,36 @@ func (s *VtctldServer) ApplyVSchema(ctx context.Context, req *vtctldatapb.ApplyV
 		return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "must pass exactly one of req.VSchema and req.Sql")
 	}
 
-	var (
-		vs  *vschemapb.Keyspace
-		err error
-	)
-
-	if req.Sql != "" {
-		span.Annotate("sql_mode", true)
-
-		stmt, err := sqlparser.Parse(req.Sql)
-		if err != nil {
-			return nil, vterrors.Wrapf(err, "Parse(%s)", req.Sql)
-		}
-		ddl, ok := stmt.(*sqlparser.AlterVschema)
-		if !ok {
-			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "error parsing VSchema DDL statement `%s`", req.Sql)
-		}
-
-		vs, err = s.ts.GetVSchema(ctx, req.Keyspace)
-		if err != nil && !topo.IsErrType(err, topo.NoNode) {
-			return nil, vterrors.Wrapf(err, "GetVSchema(%s)", req.Keyspace)
-		} // otherwise, we keep the empty vschema object from above
+	diff, err := s.diffVSchema(ctx, req.Keyspace, req.VSchema, req.Sql, req.ValidateSchema)
+	if err != nil {
+		return nil, err
+	}
 
-		vs, err = topotools.ApplyVSchemaDDL(req.Keyspace, vs, ddl)
-		if err != nil {
-			return nil, vterrors.Wrapf(err, "ApplyVSchemaDDL(%s,%v,%v)", req.Keyspace, vs, ddl)
-		}
-	} else { // "jsonMode"
-		span.Annotate("sql_mode", false)
-		vs = req.VSchema
+	resp := &vtctldatapb.ApplyVSchemaResponse{
+		VSchema:            diff.VSchema,
+		Changes:            diff.Changes,
+		ValidationErrors:   diff.ValidationErrors,
+		ValidationWarnings: diff.ValidationWarnings,
 	}
 
 	if req.DryRun { // we return what was passed in and parsed, rather than current
-		return &vtctldatapb.ApplyVSchemaResponse{VSchema: vs}, nil
+		return resp, nil
+	}
+
+	if len(diff.ValidationErrors) > 0 {
+		messages := make([]string, 0, len(diff.ValidationErrors))
+		for _, validationError := range diff.ValidationErrors {
+			messages = append(messages, (&vschemadiff.ValidationError{
+				Entity:  vschemadiff.EntityType(validationError.Entity),
+				Name:    validationError.Name,
+				Message: validationError.Message,
+			}).Error())
+		}
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "the VSchema of keyspace %s is not valid: %s", req.Keyspace, strings.Join(messages, "; "))
 	}
 
-	if err = s.ts.SaveVSchema(ctx, req.Keyspace, vs); err != nil {
-		return nil, vterrors.Wrapf(err, "SaveVSchema(%s, %v)", req.Keyspace, req.VSchema)
+	if err = s.ts.SaveVSchema(ctx, req.Keyspace, diff.VSchema); err != nil {
+		return nil, vterrors.Wrapf(err, "SaveVSchema(%s, %v)", req.Keyspace, diff.VSchema)
 	}
 
 	if !req.SkipRebuild {
@@ -309,11 +320,11 @@ func (s *VtctldServer) ApplyVSchema(ctx context.Context, req *vtctldatapb.ApplyV
 			return nil, vterrors.Wrapf(err, "RebuildSrvVSchema")
 		}
 	}
-	updatedVS, err := s.ts.GetVSchema(ctx, req.Keyspace)
+	resp.VSchema, err = s.ts.GetVSchema(ctx, req.Keyspace)
 	if err != nil {
 		return nil, vterrors.Wrapf(err, "GetVSchema(%s)", req.Keyspace)
 	}
-	return &vtctldatapb.ApplyVSchemaResponse{VSchema: updatedVS}, nil
+	return resp, nil
 }
 
 // Backup is part of the vtctlservicepb.VtctldServer interface.
@@ -433,6 +444,29 @@ func (s *VtctldServer) backupTablet(ctx context.Context, tablet *topodatapb.Tabl
 	}
 }
 
+// CancelSchemaMigration is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) CancelSchemaMigration(ctx context.Context, req *vtctldatapb.CancelSchemaMigrationRequest) (*vtctldatapb.CancelSchemaMigrationResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.CancelSchemaMigration")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("uuid", req.Uuid)
+
+	hint := cancelMigrationHint
+	if req.Uuid == "" {
+		hint = cancelAllMigrationHint
+	}
+
+	rowsAffected, err := s.updateSchemaMigrationStatus(ctx, req.Keyspace, req.Uuid, hint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.CancelSchemaMigrationResponse{
+		RowsAffectedByShard: rowsAffected,
+	}, nil
+}
+
 // ChangeTabletType is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ChangeTabletType(ctx context.Context, req *vtctldatapb.ChangeTabletTypeRequest) (*vtctldatapb.ChangeTabletTypeResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ChangeTabletType")
@@ -524,6 +558,28 @@ func (s *VtctldServer) ChangeTabletType(ctx context.Context, req *vtctldatapb.Ch
 	}, nil
 }
 
+// CompleteSchemaMigration is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) CompleteSchemaMigration(ctx context.Context, req *vtctldatapb.CompleteSchemaMigrationRequest) (*vtctldatapb.CompleteSchemaMigrationResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.CompleteSchemaMigration")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("uuid", req.Uuid)
+
+	if req.Uuid == "" {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "uuid is required")
+	}
+
+	rowsAffected, err := s.updateSchemaMigrationStatus(ctx, req.Keyspace, req.Uuid, completeMigrationHint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.CompleteSchemaMigrationResponse{
+		RowsAffectedByShard: rowsAffected,
+	}, nil
+}
+
 // CreateKeyspace is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) CreateKeyspace(ctx context.Context, req *vtctldatapb.CreateKeyspaceRequest) (*vtctldatapb.CreateKeyspaceResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.CreateKeyspace")
@@ -850,6 +906,437 @@ func (s *VtctldServer) DeleteTablets(ctx context.Context, req *vtctldatapb.Delet
 	return &vtctldatapb.DeleteTabletsResponse{}, nil
 }
 
+// DeploySchema is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) DeploySchema(ctx context.Context, req *vtctldatapb.DeploySchemaRequest) (resp *vtctldatapb.DeploySchemaResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.DeploySchema")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("dry_run", req.DryRun)
+	span.Annotate("ddl_strategy", req.DdlStrategy)
+
+	if len(req.Sql) == 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "Sql must be a non-empty array")
+	}
+
+	desired, err := schemadiff.NewSchemaFromQueries(req.Sql)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "invalid desired schema")
+	}
+
+	current, err := s.getConsistentKeyspaceSchema(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	hints := &schemadiff.DiffHints{}
+	if req.HeuristicTableRenames {
+		hints.TableRenameStrategy = schemadiff.TableRenameHeuristicStatement
+	}
+	if req.HeuristicColumnRenames {
+		hints.ColumnRenameStrategy = schemadiff.ColumnRenameHeuristicStatement
+	}
+
+	diffs, err := current.OrderedDiff(desired, hints)
+	if err != nil {
+		var orderErr *schemadiff.ImpossibleApplyDiffOrderError
+		if errors.As(err, &orderErr) {
+			return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "cannot deploy the schema of keyspace %s: %v", req.Keyspace, err)
+		}
+		return nil, vterrors.Wrapf(err, "cannot diff the schema of keyspace %s", req.Keyspace)
+	}
+
+	resp = &vtctldatapb.DeploySchemaResponse{
+		Diff: deploySchemaStatements(diffs),
+	}
+	if req.DryRun || len(resp.Diff) == 0 {
+		return resp, nil
+	}
+
+	if drops := deploySchemaDrops(diffs, desired); len(drops) > 0 && !req.AllowDrops {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "the schema changes of keyspace %s drop %s, which requires AllowDrops", req.Keyspace, strings.Join(drops, ", "))
+	}
+
+	applyResp, err := s.ApplySchema(ctx, &vtctldatapb.ApplySchemaRequest{
+		Keyspace:                req.Keyspace,
+		AllowLongUnavailability: req.AllowLongUnavailability,
+		Sql:                     resp.Diff,
+		DdlStrategy:             req.DdlStrategy,
+		MigrationContext:        req.MigrationContext,
+		WaitReplicasTimeout:     req.WaitReplicasTimeout,
+		SkipPreflight:           req.SkipPreflight,
+		CallerId:                req.CallerId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.UuidList = applyResp.UuidList
+	return resp, nil
+}
+
+// getKeyspaceSchema returns the tables, views and stored programs of a
+// keyspace, as reported by the primary of its first shard, along with the alias
+// of that primary. It returns a nil schema when no shard has a primary.
+func (s *VtctldServer) getKeyspaceSchema(ctx context.Context, keyspace string) (*schemadiff.Schema, *topodatapb.TabletAlias, error) {
+	shards, err := s.ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(shards)
+
+	for _, shard := range shards {
+		si, err := s.ts.GetShard(ctx, keyspace, shard)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !si.HasPrimary() {
+			continue
+		}
+
+		current, err := s.getShardSchema(ctx, si)
+		if err != nil {
+			return nil, nil, err
+		}
+		return current, si.PrimaryAlias, nil
+	}
+
+	return nil, nil, nil
+}
+
+// getConsistentKeyspaceSchema returns the tables, views and stored programs of
+// a keyspace, as reported by the primaries of all its shards. It fails if a
+// shard has no primary, or if the schemas of the shards differ.
+func (s *VtctldServer) getConsistentKeyspaceSchema(ctx context.Context, keyspace string) (*schemadiff.Schema, error) {
+	shards, err := s.ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if len(shards) == 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "keyspace %s has no shards", keyspace)
+	}
+	sort.Strings(shards)
+
+	var (
+		reference      *schemadiff.Schema
+		referenceShard string
+	)
+	for _, shard := range shards {
+		si, err := s.ts.GetShard(ctx, keyspace, shard)
+		if err != nil {
+			return nil, err
+		}
+		if !si.HasPrimary() {
+			return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "shard %s/%s has no primary", keyspace, shard)
+		}
+
+		current, err := s.getShardSchema(ctx, si)
+		if err != nil {
+			return nil, err
+		}
+		if reference == nil {
+			reference, referenceShard = current, shard
+			continue
+		}
+
+		diffs, err := reference.Diff(current, &schemadiff.DiffHints{})
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot compare the schemas of shards %s/%s and %s/%s", keyspace, referenceShard, keyspace, shard)
+		}
+		if len(diffs) > 0 {
+			return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "the schema of shard %s/%s differs from the schema of shard %s/%s: %s", keyspace, shard, keyspace, referenceShard, strings.Join(deploySchemaStatements(diffs), "; "))
+		}
+	}
+
+	return reference, nil
+}
+
+// getShardSchema returns the tables, views and stored programs of a shard, as
+// reported by its primary.
+func (s *VtctldServer) getShardSchema(ctx context.Context, si *topo.ShardInfo) (*schemadiff.Schema, error) {
+	sd, err := schematools.GetSchema(ctx, s.ts, s.tmc, si.PrimaryAlias, &tabletmanagerdatapb.GetSchemaRequest{
+		IncludeViews:          true,
+		TableSchemaOnly:       true,
+		IncludeStoredPrograms: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	queries := make([]string, 0, len(sd.TableDefinitions)+len(sd.StoredProgramDefinitions))
+	for _, td := range sd.TableDefinitions {
+		// online DDL and table GC artifacts are not part of the schema
+		if schema.IsInternalOperationTableName(td.Name) {
+			continue
+		}
+		queries = append(queries, td.Schema)
+	}
+	for _, spd := range sd.StoredProgramDefinitions {
+		queries = append(queries, spd.Schema)
+	}
+	current, err := schemadiff.NewSchemaFromQueries(queries)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot parse the schema of %v", topoproto.TabletAliasString(si.PrimaryAlias))
+	}
+	return current, nil
+}
+
+// lintSchemaChanges runs the schema change linter on each of the given SQL
+// commands, in order, starting from the current schema of the keyspace. Each
+// command is linted against the schema that results from the commands before
+// it. Commands that do not change the schema, such as Online DDL control
+// commands, have no findings.
+func (s *VtctldServer) lintSchemaChanges(ctx context.Context, keyspace string, sqls []string) ([]*vtctldatapb.SchemaChangeLint, error) {
+	current, primaryAlias, err := s.getKeyspaceSchema(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "no shard in keyspace %s has a primary", keyspace)
+	}
+
+	primary, err := s.ts.GetTablet(ctx, primaryAlias)
+	if err != nil {
+		return nil, err
+	}
+	// Without a known server version, we cannot tell which changes run with
+	// ALGORITHM=INSTANT.
+	var capableOf mysql.CapableOf
+	if primary.DbServerVersion != "" {
+		_, capableOf, _ = mysql.GetFlavor(primary.DbServerVersion, nil)
+	}
+
+	lints := make([]*vtctldatapb.SchemaChangeLint, 0, len(sqls))
+	for _, sql := range sqls {
+		sql = strings.TrimSpace(sql)
+		if sql == "" {
+			continue
+		}
+
+		lint := &vtctldatapb.SchemaChangeLint{Sql: sql}
+		lints = append(lints, lint)
+
+		stmt, err := sqlparser.Parse(sql)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot parse %s", sql)
+		}
+		diffs, err := current.StatementDiffs(stmt)
+		if err != nil {
+			var unsupportedErr *schemadiff.UnsupportedStatementError
+			if errors.As(err, &unsupportedErr) {
+				continue
+			}
+			return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "cannot apply %s to the schema of keyspace %s: %v", sql, keyspace, err)
+		}
+
+		diffLints, err := schemadiff.LintDiffs(diffs, capableOf)
+		if err != nil {
+			return nil, err
+		}
+		for _, diffLint := range diffLints {
+			for _, finding := range diffLint.Findings {
+				lint.Findings = append(lint.Findings, &vtctldatapb.SchemaChangeLintFinding{
+					Code:    string(finding.Code),
+					Entity:  finding.Entity,
+					Message: finding.Message,
+				})
+			}
+		}
+
+		current, err = current.Apply(diffs)
+		if err != nil {
+			return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "cannot apply %s to the schema of keyspace %s: %v", sql, keyspace, err)
+		}
+	}
+
+	return lints, nil
+}
+
+// deploySchemaStatements returns the statements of the diffs, including their
+// subsequent diffs, in the order in which the diffs are given.
+func deploySchemaStatements(diffs []schemadiff.EntityDiff) []string {
+	var statements []string
+	for _, diff := range diffs {
+		for _, d := range schemadiff.AllSubsequent(diff) {
+			statements = append(statements, d.StatementString())
+		}
+	}
+	return statements
+}
+
+// deploySchemaDrops returns the tables, views, stored programs and columns
+// that the diffs drop, and that are not in the desired schema. Entities that
+// are dropped to be created again, or renamed, are not included.
+func deploySchemaDrops(diffs []schemadiff.EntityDiff, desired *schemadiff.Schema) []string {
+	var drops []string
+	for _, diff := range diffs {
+		switch diff := diff.(type) {
+		case *schemadiff.DropTableEntityDiff:
+			from, _ := diff.Entities()
+			if desired.Table(from.Name()) == nil {
+				drops = append(drops, "table "+from.Name())
+			}
+		case *schemadiff.DropViewEntityDiff:
+			from, _ := diff.Entities()
+			if desired.View(from.Name()) == nil {
+				drops = append(drops, "view "+from.Name())
+			}
+		case *schemadiff.DropProgramEntityDiff:
+			// A changed stored program may be dropped and then created again.
+			from, _ := diff.Entities()
+			switch from.(type) {
+			case *schemadiff.CreateFunctionEntity:
+				if desired.Function(from.Name()) == nil {
+					drops = append(drops, "function "+from.Name())
+				}
+			case *schemadiff.CreateProcedureEntity:
+				if desired.Procedure(from.Name()) == nil {
+					drops = append(drops, "procedure "+from.Name())
+				}
+			case *schemadiff.CreateTriggerEntity:
+				if desired.Trigger(from.Name()) == nil {
+					drops = append(drops, "trigger "+from.Name())
+				}
+			case *schemadiff.CreateEventEntity:
+				if desired.Event(from.Name()) == nil {
+					drops = append(drops, "event "+from.Name())
+				}
+			}
+		case *schemadiff.AlterTableEntityDiff:
+			for _, d := range schemadiff.AllSubsequent(diff) {
+				alterTable := d.(*schemadiff.AlterTableEntityDiff).AlterTable()
+				for _, option := range alterTable.AlterOptions {
+					if dropColumn, ok := option.(*sqlparser.DropColumn); ok {
+						drops = append(drops, fmt.Sprintf("column %s.%s", alterTable.Table.Name.String(), dropColumn.Name.Name.String()))
+					}
+				}
+			}
+		}
+	}
+	return drops
+}
+
+// DiffVSchema is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) DiffVSchema(ctx context.Context, req *vtctldatapb.DiffVSchemaRequest) (*vtctldatapb.DiffVSchemaResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.DiffVSchema")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("validate_schema", req.ValidateSchema)
+	span.Annotate("sql_mode", req.Sql != "")
+
+	if (req.Sql != "" && req.VSchema != nil) || (req.Sql == "" && req.VSchema == nil) {
+		return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "must pass exactly one of req.VSchema and req.Sql")
+	}
+
+	if _, err := s.ts.GetKeyspace(ctx, req.Keyspace); err != nil {
+		if topo.IsErrType(err, topo.NoNode) {
+			return nil, vterrors.Wrapf(err, "keyspace(%s) doesn't exist, check if the keyspace is initialized", req.Keyspace)
+		}
+		return nil, vterrors.Wrapf(err, "GetKeyspace(%s)", req.Keyspace)
+	}
+
+	return s.diffVSchema(ctx, req.Keyspace, req.VSchema, req.Sql, req.ValidateSchema)
+}
+
+// diffVSchema returns the desired VSchema of a keyspace, which is either vs
+// or the result of the VSchema DDL statement sql on the current VSchema, the
+// changes from the current VSchema, and the result of its validation. The
+// VSchema is only validated against the schema of the tables when
+// validateSchema is set, and the checks against the schema of a keyspace that
+// cannot be read, for example because it has no primary, are skipped and
+// reported as warnings.
+func (s *VtctldServer) diffVSchema(ctx context.Context, keyspace string, vs *vschemapb.Keyspace, sql string, validateSchema bool) (*vtctldatapb.DiffVSchemaResponse, error) {
+	current, err := s.ts.GetVSchema(ctx, keyspace)
+	if err != nil && !topo.IsErrType(err, topo.NoNode) {
+		return nil, vterrors.Wrapf(err, "GetVSchema(%s)", keyspace)
+	}
+
+	desired := vs
+	if sql != "" {
+		ddl, err := parseVSchemaDDL(sql)
+		if err != nil {
+			return nil, err
+		}
+		// ApplyVSchemaDDL modifies the VSchema it is given, and we still need
+		// the current one for the diff.
+		var vs *vschemapb.Keyspace
+		if current != nil {
+			vs = proto.Clone(current).(*vschemapb.Keyspace)
+		}
+		desired, err = topotools.ApplyVSchemaDDL(keyspace, vs, ddl)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "ApplyVSchemaDDL(%s,%v,%v)", keyspace, vs, ddl)
+		}
+	}
+
+	var getSchema vschemadiff.SchemaFunc
+	if validateSchema {
+		getSchema = func(keyspace string) (*schemadiff.Schema, error) {
+			if _, err := s.ts.GetKeyspace(ctx, keyspace); err != nil {
+				if topo.IsErrType(err, topo.NoNode) {
+					return nil, nil
+				}
+				return nil, fmt.Errorf("%w: GetKeyspace(%s): %s", vschemadiff.ErrSchemaUnavailable, keyspace, err)
+			}
+			schema, _, err := s.getKeyspaceSchema(ctx, keyspace)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", vschemadiff.ErrSchemaUnavailable, err)
+			}
+			if schema == nil {
+				return nil, fmt.Errorf("%w: no shard in keyspace %s has a primary", vschemadiff.ErrSchemaUnavailable, keyspace)
+			}
+			return schema, nil
+		}
+	}
+
+	validationErrors, warnings, err := vschemadiff.Validate(keyspace, desired, getSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &vtctldatapb.DiffVSchemaResponse{
+		VSchema: desired,
+	}
+	for _, change := range vschemadiff.Diff(current, desired) {
+		resp.Changes = append(resp.Changes, &vtctldatapb.VSchemaChange{
+			Type:    string(change.Type),
+			Entity:  string(change.Entity),
+			Name:    change.Name,
+			Details: change.Details,
+		})
+	}
+	resp.ValidationErrors = vschemaValidationErrorsToProto(validationErrors)
+	resp.ValidationWarnings = vschemaValidationErrorsToProto(warnings)
+	return resp, nil
+}
+
+func vschemaValidationErrorsToProto(validationErrors []*vschemadiff.ValidationError) []*vtctldatapb.VSchemaValidationError {
+	var result []*vtctldatapb.VSchemaValidationError
+	for _, validationError := range validationErrors {
+		result = append(result, &vtctldatapb.VSchemaValidationError{
+			Entity:  string(validationError.Entity),
+			Name:    validationError.Name,
+			Message: validationError.Message,
+		})
+	}
+	return result
+}
+
+// parseVSchemaDDL parses a VSchema DDL statement, such as
+// `alter vschema add table t1`.
+func parseVSchemaDDL(sql string) (*sqlparser.AlterVschema, error) {
+	stmt, err := sqlparser.Parse(sql)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "Parse(%s)", sql)
+	}
+	ddl, ok := stmt.(*sqlparser.AlterVschema)
+	if !ok {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "error parsing VSchema DDL statement `%s`", sql)
+	}
+	return ddl, nil
+}
+
 // EmergencyReparentShard is part of the vtctldservicepb.VtctldServer interface.
 func (s *VtctldServer) EmergencyReparentShard(ctx context.Context, req *vtctldatapb.EmergencyReparentShardRequest) (*vtctldatapb.EmergencyReparentShardResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.EmergencyReparentShard")
@@ -871,6 +1358,7 @@ func (s *VtctldServer) EmergencyReparentShard(ctx context.Context, req *vtctldat
 
 	span.Annotate("wait_replicas_timeout_sec", waitReplicasTimeout.Seconds())
 	span.Annotate("prevent_cross_cell_promotion", req.PreventCrossCellPromotion)
+	span.Annotate("dry_run", req.DryRun)
 
 	m := sync.RWMutex{}
 	logstream := []*logutilpb.Event{}
@@ -881,28 +1369,39 @@ func (s *VtctldServer) EmergencyReparentShard(ctx context.Context, req *vtctldat
 		logstream = append(logstream, e)
 	})
 
-	ev, err := reparentutil.NewEmergencyReparenter(s.ts, s.tmc, logger).ReparentShard(ctx,
-		req.Keyspace,
-		req.Shard,
-		reparentutil.EmergencyReparentOptions{
-			NewPrimaryAlias:           req.NewPrimary,
-			IgnoreReplicas:            sets.NewString(ignoreReplicaAliases...),
-			WaitReplicasTimeout:       waitReplicasTimeout,
-			PreventCrossCellPromotion: req.PreventCrossCellPromotion,
-		},
-	)
+	erp := reparentutil.NewEmergencyReparenter(s.ts, s.tmc, logger)
+	opts := reparentutil.EmergencyReparentOptions{
+		NewPrimaryAlias:           req.NewPrimary,
+		IgnoreReplicas:            sets.NewString(ignoreReplicaAliases...),
+		WaitReplicasTimeout:       waitReplicasTimeout,
+		PreventCrossCellPromotion: req.PreventCrossCellPromotion,
+	}
 
 	resp := &vtctldatapb.EmergencyReparentShardResponse{
 		Keyspace: req.Keyspace,
 		Shard:    req.Shard,
 	}
 
-	if ev != nil {
-		resp.Keyspace = ev.ShardInfo.Keyspace()
-		resp.Shard = ev.ShardInfo.ShardName()
-
-		if ev.NewPrimary != nil && !topoproto.TabletAliasIsZero(ev.NewPrimary.Alias) {
-			resp.PromotedPrimary = ev.NewPrimary.Alias
+	if req.DryRun {
+		var sim *reparentutil.ReparentSimulation
+		sim, err = erp.SimulateReparentShard(ctx, req.Keyspace, req.Shard, opts)
+		if sim != nil {
+			if sim.NewPrimary != nil {
+				resp.PromotedPrimary = sim.NewPrimary.Alias
+			}
+			resp.ExcludedTablets = excludedTabletsToProto(sim.ExcludedTablets)
+			resp.PlannedSteps = sim.Steps
+		}
+	} else {
+		var ev *events.Reparent
+		ev, err = erp.ReparentShard(ctx, req.Keyspace, req.Shard, opts)
+		if ev != nil {
+			resp.Keyspace = ev.ShardInfo.Keyspace()
+			resp.Shard = ev.ShardInfo.ShardName()
+
+			if ev.NewPrimary != nil && !topoproto.TabletAliasIsZero(ev.NewPrimary.Alias) {
+				resp.PromotedPrimary = ev.NewPrimary.Alias
+			}
 		}
 	}
 
@@ -915,6 +1414,24 @@ func (s *VtctldServer) EmergencyReparentShard(ctx context.Context, req *vtctldat
 	return resp, err
 }
 
+// excludedTabletsToProto converts the tablets a reparent dry run would not
+// consider for promotion.
+func excludedTabletsToProto(excluded []*reparentutil.ExcludedTablet) []*vtctldatapb.ExcludedTablet {
+	if len(excluded) == 0 {
+		return nil
+	}
+
+	tablets := make([]*vtctldatapb.ExcludedTablet, len(excluded))
+	for i, tablet := range excluded {
+		tablets[i] = &vtctldatapb.ExcludedTablet{
+			TabletAlias: tablet.Alias,
+			Reason:      tablet.Reason,
+		}
+	}
+
+	return tablets
+}
+
 // ExecuteFetchAsApp is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ExecuteFetchAsApp(ctx context.Context, req *vtctldatapb.ExecuteFetchAsAppRequest) (*vtctldatapb.ExecuteFetchAsAppResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ExecuteFetchAsApp")
@@ -1262,6 +1779,82 @@ func (s *VtctldServer) GetSchema(ctx context.Context, req *vtctldatapb.GetSchema
 	}, nil
 }
 
+// GetSchemaMigrations is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetSchemaMigrations(ctx context.Context, req *vtctldatapb.GetSchemaMigrationsRequest) (*vtctldatapb.GetSchemaMigrationsResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetSchemaMigrations")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("uuid", req.Uuid)
+	span.Annotate("migration_context", req.MigrationContext)
+	span.Annotate("status", req.Status)
+
+	if req.Keyspace == "" {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "keyspace is required")
+	}
+
+	var (
+		condition string
+		condArg   string
+	)
+
+	switch {
+	case req.Uuid != "" && (req.MigrationContext != "" || req.Status != ""),
+		req.MigrationContext != "" && req.Status != "":
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "only one of uuid, migration_context and status may be set")
+	case req.Uuid != "":
+		if !schema.IsOnlineDDLUUID(req.Uuid) {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "%s is not a valid migration UUID", req.Uuid)
+		}
+
+		condition, condArg = " and migration_uuid=%a", req.Uuid
+	case req.MigrationContext != "":
+		condition, condArg = " and migration_context=%a", req.MigrationContext
+	case req.Status != "":
+		condition, condArg = " and migration_status=%a", req.Status
+	}
+
+	recent, ok, err := protoutil.DurationFromProto(req.Recent)
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "invalid recent duration: %s", err)
+	}
+
+	if ok && recent > 0 {
+		condition += fmt.Sprintf(" and requested_timestamp > now() - interval %d second", int64(recent.Seconds()))
+	}
+
+	results, err := s.schemaMigrationsVExec(ctx, req.Keyspace, req.Uuid, func(dbName string) (string, error) {
+		bindVars := []*querypb.BindVariable{sqltypes.StringBindVariable(dbName)}
+		if condArg != "" {
+			bindVars = append(bindVars, sqltypes.StringBindVariable(condArg))
+		}
+
+		return sqlparser.ParseAndBind("select * from _vt.schema_migrations where mysql_schema=%a"+condition+" order by id", bindVars...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	shards := make([]string, 0, len(results))
+	for shard := range results {
+		shards = append(shards, shard)
+	}
+
+	sort.Strings(shards)
+
+	resp := &vtctldatapb.GetSchemaMigrationsResponse{}
+	for _, shard := range shards {
+		migrations, err := schemaMigrationsFromResult(results[shard])
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Migrations = append(resp.Migrations, migrations...)
+	}
+
+	return resp, nil
+}
+
 // GetShard is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetShard(ctx context.Context, req *vtctldatapb.GetShardRequest) (*vtctldatapb.GetShardResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetShard")
@@ -1927,6 +2520,108 @@ func (s *VtctldServer) InitShardPrimaryLocked(
 	return nil
 }
 
+// LookupVindexCreate is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) LookupVindexCreate(ctx context.Context, req *vtctldatapb.LookupVindexCreateRequest) (*vtctldatapb.LookupVindexCreateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.LookupVindexCreate")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+
+	return s.ws.LookupVindexCreate(ctx, req)
+}
+
+// LookupVindexExternalize is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) LookupVindexExternalize(ctx context.Context, req *vtctldatapb.LookupVindexExternalizeRequest) (*vtctldatapb.LookupVindexExternalizeResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.LookupVindexExternalize")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("vindex", req.Name)
+
+	return s.ws.LookupVindexExternalize(ctx, req)
+}
+
+// LookupVindexShow is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) LookupVindexShow(ctx context.Context, req *vtctldatapb.LookupVindexShowRequest) (*vtctldatapb.LookupVindexShowResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.LookupVindexShow")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("vindex", req.Name)
+
+	return s.ws.LookupVindexShow(ctx, req)
+}
+
+// LookupVindexVerify is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) LookupVindexVerify(ctx context.Context, req *vtctldatapb.LookupVindexVerifyRequest) (*vtctldatapb.LookupVindexVerifyResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.LookupVindexVerify")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("vindex", req.Name)
+	span.Annotate("show", req.Show)
+
+	show, err := s.ws.LookupVindexShow(ctx, &vtctldatapb.LookupVindexShowRequest{
+		Keyspace: req.Keyspace,
+		Name:     req.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if show.Workflow == nil {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "lookup vindex %s.%s has no backfill workflow to verify", req.Keyspace, req.Name)
+	}
+
+	resp := &vtctldatapb.LookupVindexVerifyResponse{
+		TargetKeyspace: show.TargetKeyspace,
+		Workflow:       show.Workflow.Name,
+	}
+
+	if req.Show != "" {
+		vdiffShow, err := s.VDiffShow(ctx, &vtctldatapb.VDiffShowRequest{
+			TargetKeyspace: resp.TargetKeyspace,
+			Workflow:       resp.Workflow,
+			Arg:            req.Show,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resp.TabletResponses = vdiffShow.TabletResponses
+		return resp, nil
+	}
+
+	options := req.Options
+	if options == nil {
+		// Compare every row of the lookup table.
+		options = &tabletmanagerdatapb.VDiffOptions{
+			PickerOptions: &tabletmanagerdatapb.VDiffPickerOptions{
+				TabletTypes: "in_order:RDONLY,REPLICA,PRIMARY",
+			},
+			CoreOptions: &tabletmanagerdatapb.VDiffCoreOptions{
+				MaxRows:        math.MaxInt64,
+				SamplePct:      100,
+				TimeoutSeconds: 30,
+			},
+			ReportOptions: &tabletmanagerdatapb.VDiffReportOptions{
+				Format: "json",
+			},
+		}
+	}
+
+	vdiffCreate, err := s.VDiffCreate(ctx, &vtctldatapb.VDiffCreateRequest{
+		TargetKeyspace: resp.TargetKeyspace,
+		Workflow:       resp.Workflow,
+		Options:        options,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Uuid = vdiffCreate.Uuid
+	return resp, nil
+}
+
 // PingTablet is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) PingTablet(ctx context.Context, req *vtctldatapb.PingTabletRequest) (*vtctldatapb.PingTabletResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.PingTablet")
@@ -1971,6 +2666,8 @@ func (s *VtctldServer) PlannedReparentShard(ctx context.Context, req *vtctldatap
 		span.Annotate("new_primary_alias", topoproto.TabletAliasString(req.NewPrimary))
 	}
 
+	span.Annotate("dry_run", req.DryRun)
+
 	m := sync.RWMutex{}
 	logstream := []*logutilpb.Event{}
 	logger := logutil.NewCallbackLogger(func(e *logutilpb.Event) {
@@ -1980,27 +2677,38 @@ func (s *VtctldServer) PlannedReparentShard(ctx context.Context, req *vtctldatap
 		logstream = append(logstream, e)
 	})
 
-	ev, err := reparentutil.NewPlannedReparenter(s.ts, s.tmc, logger).ReparentShard(ctx,
-		req.Keyspace,
-		req.Shard,
-		reparentutil.PlannedReparentOptions{
-			AvoidPrimaryAlias:   req.AvoidPrimary,
-			NewPrimaryAlias:     req.NewPrimary,
-			WaitReplicasTimeout: waitReplicasTimeout,
-		},
-	)
+	pr := reparentutil.NewPlannedReparenter(s.ts, s.tmc, logger)
+	opts := reparentutil.PlannedReparentOptions{
+		AvoidPrimaryAlias:   req.AvoidPrimary,
+		NewPrimaryAlias:     req.NewPrimary,
+		WaitReplicasTimeout: waitReplicasTimeout,
+	}
 
 	resp := &vtctldatapb.PlannedReparentShardResponse{
 		Keyspace: req.Keyspace,
 		Shard:    req.Shard,
 	}
 
-	if ev != nil {
-		resp.Keyspace = ev.ShardInfo.Keyspace()
-		resp.Shard = ev.ShardInfo.ShardName()
-
-		if !topoproto.TabletAliasIsZero(ev.NewPrimary.Alias) {
-			resp.PromotedPrimary = ev.NewPrimary.Alias
+	if req.DryRun {
+		var sim *reparentutil.ReparentSimulation
+		sim, err = pr.SimulateReparentShard(ctx, req.Keyspace, req.Shard, opts)
+		if sim != nil {
+			if sim.NewPrimary != nil {
+				resp.PromotedPrimary = sim.NewPrimary.Alias
+			}
+			resp.ExcludedTablets = excludedTabletsToProto(sim.ExcludedTablets)
+			resp.PlannedSteps = sim.Steps
+		}
+	} else {
+		var ev *events.Reparent
+		ev, err = pr.ReparentShard(ctx, req.Keyspace, req.Shard, opts)
+		if ev != nil {
+			resp.Keyspace = ev.ShardInfo.Keyspace()
+			resp.Shard = ev.ShardInfo.ShardName()
+
+			if !topoproto.TabletAliasIsZero(ev.NewPrimary.Alias) {
+				resp.PromotedPrimary = ev.NewPrimary.Alias
+			}
 		}
 	}
 
@@ -2401,6 +3109,28 @@ func (s *VtctldServer) RestoreFromBackup(req *vtctldatapb.RestoreFromBackupReque
 	}
 }
 
+// RetrySchemaMigration is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) RetrySchemaMigration(ctx context.Context, req *vtctldatapb.RetrySchemaMigrationRequest) (*vtctldatapb.RetrySchemaMigrationResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.RetrySchemaMigration")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("uuid", req.Uuid)
+
+	if req.Uuid == "" {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "uuid is required")
+	}
+
+	rowsAffected, err := s.updateSchemaMigrationStatus(ctx, req.Keyspace, req.Uuid, retryMigrationHint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.RetrySchemaMigrationResponse{
+		RowsAffectedByShard: rowsAffected,
+	}, nil
+}
+
 // RunHealthCheck is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) RunHealthCheck(ctx context.Context, req *vtctldatapb.RunHealthCheckRequest) (*vtctldatapb.RunHealthCheckResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.RunHealthCheck")
@@ -2444,7 +3174,7 @@ func (s *VtctldServer) SetKeyspaceDurabilityPolicy(ctx context.Context, req *vtc
 
 	policyValid := reparentutil.CheckDurabilityPolicyExists(req.DurabilityPolicy)
 	if !policyValid {
-		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "durability policy <%v> is not a valid policy. Please register it as a policy first", req.DurabilityPolicy)
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "durability policy <%v> is not a valid policy. Please register it as a policy first, or declare it in --durability_policies_file", req.DurabilityPolicy)
 	}
 
 	ki.DurabilityPolicy = req.DurabilityPolicy
@@ -3169,6 +3899,220 @@ func (s *VtctldServer) UpdateCellsAlias(ctx context.Context, req *vtctldatapb.Up
 	}, nil
 }
 
+// UpdateThrottlerConfig is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) UpdateThrottlerConfig(ctx context.Context, req *vtctldatapb.UpdateThrottlerConfigRequest) (*vtctldatapb.UpdateThrottlerConfigResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.UpdateThrottlerConfig")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("enable", req.Enable)
+	span.Annotate("disable", req.Disable)
+	span.Annotate("threshold", req.Threshold)
+	span.Annotate("throttled_app", req.ThrottledApp.GetName())
+
+	if req.Enable && req.Disable {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "--enable and --disable are mutually exclusive")
+	}
+	if req.CheckAsCheckSelf && req.CheckAsCheckShard {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "--check-as-check-self and --check-as-check-shard are mutually exclusive")
+	}
+	if req.Threshold < 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "threshold must be positive, got %v", req.Threshold)
+	}
+	if req.CustomQuerySet && req.CustomQuery != "" && req.Threshold == 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "a custom query requires a positive threshold")
+	}
+	if app := req.ThrottledApp; app.GetName() != "" {
+		if app.Ratio < 0 || app.Ratio > 1 {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "throttled app ratio must be in the range [0..1], got %v", app.Ratio)
+		}
+		if app.ExpiresAt == nil {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "throttled app %v must have an expiration time", app.Name)
+		}
+	}
+
+	ctx, unlock, lockErr := s.ts.LockKeyspace(ctx, req.Keyspace, "UpdateThrottlerConfig")
+	if lockErr != nil {
+		return nil, lockErr
+	}
+
+	var err error
+	defer unlock(&err)
+
+	ki, err := s.ts.GetKeyspace(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	throttlerConfig := ki.ThrottlerConfig
+	if throttlerConfig == nil {
+		throttlerConfig = &topodatapb.ThrottlerConfig{}
+	}
+	if req.CustomQuerySet {
+		// custom query provided
+		throttlerConfig.CustomQuery = req.CustomQuery
+		// a custom query comes with its own threshold; restoring the default query resets it
+		throttlerConfig.Threshold = req.Threshold
+	} else if req.Threshold > 0 {
+		throttlerConfig.Threshold = req.Threshold
+	}
+	if req.Enable {
+		throttlerConfig.Enabled = proto.Bool(true)
+	}
+	if req.Disable {
+		throttlerConfig.Enabled = proto.Bool(false)
+	}
+	if req.CheckAsCheckSelf {
+		throttlerConfig.CheckAsCheckSelf = true
+	}
+	if req.CheckAsCheckShard {
+		throttlerConfig.CheckAsCheckSelf = false
+	}
+
+	now := time.Now()
+	if app := req.ThrottledApp; app.GetName() != "" {
+		if throttlerConfig.ThrottledApps == nil {
+			throttlerConfig.ThrottledApps = make(map[string]*topodatapb.ThrottledAppRule)
+		}
+		throttlerConfig.ThrottledApps[app.Name] = app
+	}
+	// Purge expired rules, which includes any rule the request just expired in order to unthrottle an app.
+	for appName, rule := range throttlerConfig.ThrottledApps {
+		if !protoutil.TimeFromProto(rule.ExpiresAt).After(now) {
+			delete(throttlerConfig.ThrottledApps, appName)
+		}
+	}
+
+	ki.ThrottlerConfig = throttlerConfig
+	if err = s.ts.UpdateKeyspace(ctx, ki); err != nil {
+		return nil, err
+	}
+
+	if err = s.ts.UpdateSrvKeyspaceThrottlerConfig(ctx, req.Keyspace, nil, throttlerConfig); err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.UpdateThrottlerConfigResponse{
+		ThrottlerConfig: throttlerConfig,
+	}, nil
+}
+
+// VDiffCreate is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRequest) (*vtctldatapb.VDiffCreateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.VDiffCreate")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("uuid", req.Uuid)
+
+	vdiffUUID := req.Uuid
+	if vdiffUUID == "" {
+		id, err := uuid.NewUUID()
+		if err != nil {
+			return nil, err
+		}
+
+		vdiffUUID = id.String()
+	} else if _, err := uuid.Parse(vdiffUUID); err != nil {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "invalid vdiff uuid %s: %s", vdiffUUID, err)
+	}
+
+	if _, err := s.vdiff(ctx, req.TargetKeyspace, req.Workflow, &tabletmanagerdatapb.VDiffRequest{
+		Command:   string(vdiff.CreateAction),
+		VdiffUuid: vdiffUUID,
+		Options:   req.Options,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.VDiffCreateResponse{
+		Uuid: vdiffUUID,
+	}, nil
+}
+
+// VDiffShow is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) VDiffShow(ctx context.Context, req *vtctldatapb.VDiffShowRequest) (*vtctldatapb.VDiffShowResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.VDiffShow")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("arg", req.Arg)
+
+	vreq := &tabletmanagerdatapb.VDiffRequest{
+		Command:    string(vdiff.ShowAction),
+		SubCommand: req.Arg,
+	}
+
+	switch req.Arg {
+	case vdiff.AllActionArg, vdiff.LastActionArg:
+	default:
+		if _, err := uuid.Parse(req.Arg); err != nil {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "arg must be %s, %s or a vdiff uuid; have %s", vdiff.AllActionArg, vdiff.LastActionArg, req.Arg)
+		}
+
+		vreq.VdiffUuid = req.Arg
+	}
+
+	responses, err := s.vdiff(ctx, req.TargetKeyspace, req.Workflow, vreq)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.VDiffShowResponse{
+		TabletResponses: responses,
+	}, nil
+}
+
+// vdiff sends the VDiff request to the primary of each of the workflow's
+// target shards, returning the responses keyed by shard name.
+func (s *VtctldServer) vdiff(ctx context.Context, keyspace string, workflowName string, req *tabletmanagerdatapb.VDiffRequest) (map[string]*tabletmanagerdatapb.VDiffResponse, error) {
+	if keyspace == "" || workflowName == "" {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "target keyspace and workflow are required")
+	}
+
+	req.Keyspace = keyspace
+	req.Workflow = workflowName
+
+	targets, err := workflow.BuildTargets(ctx, s.ts, s.tmc, keyspace, workflowName)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		m         sync.Mutex
+		wg        sync.WaitGroup
+		rec       concurrency.AllErrorRecorder
+		responses = make(map[string]*tabletmanagerdatapb.VDiffResponse, len(targets.Targets))
+	)
+
+	for _, target := range targets.Targets {
+		wg.Add(1)
+		go func(target *workflow.MigrationTarget) {
+			defer wg.Done()
+
+			resp, err := s.tmc.VDiff(ctx, target.GetPrimary().Tablet, req)
+			if err != nil {
+				rec.RecordError(fmt.Errorf("VDiff(%v) failed: %w", topoproto.TabletAliasString(target.GetPrimary().Alias), err))
+				return
+			}
+
+			m.Lock()
+			defer m.Unlock()
+			responses[target.GetShard().ShardName()] = resp
+		}(target)
+	}
+
+	wg.Wait()
+
+	if rec.HasErrors() {
+		return nil, rec.Error()
+	}
+
+	return responses, nil
+}
+
 // Validate is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) Validate(ctx context.Context, req *vtctldatapb.ValidateRequest) (*vtctldatapb.ValidateResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.Validate")
@@ -3408,7 +4352,7 @@ func (s *VtctldServer) ValidateSchemaKeyspace(ctx context.Context, req *vtctldat
 		wg              sync.WaitGroup
 	)
 
-	r := &tabletmanagerdatapb.GetSchemaRequest{ExcludeTables: req.ExcludeTables, IncludeViews: req.IncludeViews}
+	r := &tabletmanagerdatapb.GetSchemaRequest{ExcludeTables: req.ExcludeTables, IncludeViews: req.IncludeViews, IncludeStoredPrograms: true}
 	for _, shard := range shards[0:] {
 		wg.Add(1)
 		go func(shard string) {
@@ -3841,6 +4785,165 @@ func (s *VtctldServer) ValidateVSchema(ctx context.Context, req *vtctldatapb.Val
 	return &resp, nil
 }
 
+// watchShardsRelistInterval is how often WatchShards lists the keyspaces and
+// their shards again, to watch the ones created since the stream started. It
+// is a variable so tests can shorten it.
+var watchShardsRelistInterval = 30 * time.Second
+
+// WatchShards is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) WatchShards(req *vtctldatapb.WatchShardsRequest, stream vtctlservicepb.Vtctld_WatchShardsServer) error {
+	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.WatchShards")
+	defer span.Finish()
+
+	span.Annotate("keyspaces", strings.Join(req.Keyspaces, ","))
+
+	type shardChange struct {
+		keyspace string
+		shard    string
+		data     *topo.WatchShardData
+	}
+
+	var (
+		wg      sync.WaitGroup
+		changes = make(chan shardChange)
+		// watched holds the keyspace/shard names of the watched shards. It is
+		// only accessed by the goroutine of the stream.
+		watched = map[string]bool{}
+	)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	// watchNewShards watches the shards which are not watched yet. Their
+	// current values are sent once every watch is established, so that callers
+	// know no later change can be missed. Keyspaces and shards deleted since
+	// the first listing are skipped.
+	watchNewShards := func(first bool) error {
+		keyspaces := req.Keyspaces
+		if len(keyspaces) == 0 {
+			var err error
+			keyspaces, err = s.ts.GetKeyspaces(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		var currentShards []*vtctldatapb.Shard
+
+		for _, keyspace := range keyspaces {
+			shards, err := s.ts.GetShardNames(ctx, keyspace)
+			if err != nil {
+				if !first && topo.IsErrType(err, topo.NoNode) {
+					continue
+				}
+				return err
+			}
+
+			for _, shard := range shards {
+				key := topoproto.KeyspaceShardString(keyspace, shard)
+				if watched[key] {
+					continue
+				}
+
+				current, watch, watchCancel := s.ts.WatchShard(ctx, keyspace, shard)
+				if current.Err != nil {
+					if !first && topo.IsErrType(current.Err, topo.NoNode) {
+						continue
+					}
+					return fmt.Errorf("WatchShard(%s/%s) failed: %w", keyspace, shard, current.Err)
+				}
+				watched[key] = true
+
+				currentShards = append(currentShards, &vtctldatapb.Shard{
+					Keyspace: keyspace,
+					Name:     shard,
+					Shard:    current.Value,
+				})
+
+				wg.Add(1)
+				go func(keyspace string, shard string) {
+					defer wg.Done()
+					defer func() {
+						watchCancel()
+						for range watch { // drain the channel so the watcher can exit
+						}
+					}()
+
+					for {
+						select {
+						case <-ctx.Done():
+							return
+						case data, ok := <-watch:
+							if !ok {
+								return
+							}
+
+							select {
+							case <-ctx.Done():
+								return
+							case changes <- shardChange{keyspace: keyspace, shard: shard, data: data}:
+							}
+
+							if data.Err != nil {
+								return
+							}
+						}
+					}
+				}(keyspace, shard)
+			}
+		}
+
+		for _, shard := range currentShards {
+			if err := stream.Send(&vtctldatapb.WatchShardsResponse{Shard: shard}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := watchNewShards(true); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(watchShardsRelistInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := watchNewShards(false); err != nil {
+				return err
+			}
+		case change := <-changes:
+			shard := &vtctldatapb.Shard{
+				Keyspace: change.keyspace,
+				Name:     change.shard,
+			}
+
+			switch {
+			case change.data.Err == nil:
+				shard.Shard = change.data.Value
+			case topo.IsErrType(change.data.Err, topo.NoNode):
+				// The shard was deleted, which is sent as a shard without a
+				// record. It is watched again if it is recreated.
+				delete(watched, topoproto.KeyspaceShardString(change.keyspace, change.shard))
+			default:
+				return fmt.Errorf("watch on shard %s/%s failed: %w", change.keyspace, change.shard, change.data.Err)
+			}
+
+			if err := stream.Send(&vtctldatapb.WatchShardsResponse{Shard: shard}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
 // StartServer registers a VtctldServer for RPCs on the given gRPC server.
 func StartServer(s *grpc.Server, ts *topo.Server) {
 	vtctlservicepb.RegisterVtctldServer(s, NewVtctldServer(ts))
@@ -36,6 +36,8 @@ import (
 	hk "vitess.io/vitess/go/vt/hook"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/schemadiff"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
@@ -328,6 +330,180 @@ func TestApplyRoutingRules(t *testing.T) {
 	}
 }
 
+func TestApplySchemaDryRun(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("zone1")
+	tmc := testutil.TabletManagerClient{
+		GetSchemaResults: map[string]struct {
+			Schema *tabletmanagerdatapb.SchemaDefinition
+			Error  error
+		}{
+			"zone1-0000000100": {
+				Schema: &tabletmanagerdatapb.SchemaDefinition{
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   "t1",
+							Schema: "CREATE TABLE `t1` (`id` int NOT NULL, `name` varchar(64), `i` bigint, PRIMARY KEY (`id`))",
+							Type:   tmutils.TableBaseTable,
+						},
+						{
+							Name:   "t2",
+							Schema: "CREATE TABLE `t2` (`id` int NOT NULL, PRIMARY KEY (`id`))",
+							Type:   tmutils.TableBaseTable,
+						},
+					},
+				},
+			},
+			"zone1-0000000200": {
+				Schema: &tabletmanagerdatapb.SchemaDefinition{
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   "t1",
+							Schema: "CREATE TABLE `t1` (`id` int NOT NULL, `name` varchar(64), PRIMARY KEY (`id`))",
+							Type:   tmutils.TableBaseTable,
+						},
+					},
+				},
+			},
+		},
+	}
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{AlsoSetShardPrimary: true}, &topodatapb.Tablet{
+		Alias:           &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		Keyspace:        "testkeyspace",
+		Shard:           "-",
+		Type:            topodatapb.TabletType_PRIMARY,
+		DbServerVersion: "8.0.30",
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+		Keyspace: "unknownversion",
+		Shard:    "-",
+		Type:     topodatapb.TabletType_PRIMARY,
+	})
+
+	tests := []struct {
+		name      string
+		req       *vtctldatapb.ApplySchemaRequest
+		expected  *vtctldatapb.ApplySchemaResponse
+		shouldErr bool
+	}{
+		{
+			name: "findings",
+			req: &vtctldatapb.ApplySchemaRequest{
+				Keyspace: "testkeyspace",
+				Sql: []string{
+					"alter table t1 modify column name varchar(16)",
+					"alter table t1 add column j int",
+					"drop table t2",
+					"create table t3 (id int not null, primary key (id))",
+					"alter table t3 drop primary key",
+					"revert vitess_migration '9e8a9249_3976_11ed_9442_0a43f95f28a3'",
+				},
+				DryRun: true,
+			},
+			expected: &vtctldatapb.ApplySchemaResponse{
+				Lint: []*vtctldatapb.SchemaChangeLint{
+					{
+						Sql: "alter table t1 modify column name varchar(16)",
+						Findings: []*vtctldatapb.SchemaChangeLintFinding{
+							{
+								Code:    "lossy_column_change",
+								Entity:  "t1",
+								Message: "column `name` of table `t1` changes from varchar(64) to varchar(16), which may truncate values",
+							},
+						},
+					},
+					{
+						Sql: "alter table t1 add column j int",
+						Findings: []*vtctldatapb.SchemaChangeLintFinding{
+							{
+								Code:    "instant_ddl",
+								Entity:  "t1",
+								Message: "the change to table `t1` can run with ALGORITHM=INSTANT",
+							},
+						},
+					},
+					{
+						Sql: "drop table t2",
+						Findings: []*vtctldatapb.SchemaChangeLintFinding{
+							{
+								Code:    "destructive",
+								Entity:  "t2",
+								Message: "table `t2` is dropped",
+							},
+						},
+					},
+					{
+						Sql: "create table t3 (id int not null, primary key (id))",
+					},
+					{
+						Sql: "alter table t3 drop primary key",
+						Findings: []*vtctldatapb.SchemaChangeLintFinding{
+							{
+								Code:    "no_shared_unique_key",
+								Entity:  "t3",
+								Message: "after the change, table `t3` has no unique key of NOT NULL, non floating point columns that exist before the change; vreplication based Online DDL rejects the change",
+							},
+						},
+					},
+					{
+						Sql: "revert vitess_migration '9e8a9249_3976_11ed_9442_0a43f95f28a3'",
+					},
+				},
+			},
+		},
+		{
+			name: "unknown server version",
+			req: &vtctldatapb.ApplySchemaRequest{
+				Keyspace: "unknownversion",
+				Sql:      []string{"alter table t1 add column j int"},
+				DryRun:   true,
+			},
+			expected: &vtctldatapb.ApplySchemaResponse{
+				Lint: []*vtctldatapb.SchemaChangeLint{
+					{
+						Sql: "alter table t1 add column j int",
+					},
+				},
+			},
+		},
+		{
+			name: "missing table",
+			req: &vtctldatapb.ApplySchemaRequest{
+				Keyspace: "testkeyspace",
+				Sql:      []string{"alter table t4 add column j int"},
+				DryRun:   true,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "invalid sql",
+			req: &vtctldatapb.ApplySchemaRequest{
+				Keyspace: "testkeyspace",
+				Sql:      []string{"alter tablez t1"},
+				DryRun:   true,
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := vtctld.ApplySchema(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
 func TestApplyVSchema(t *testing.T) {
 	t.Parallel()
 
@@ -349,6 +525,10 @@ func TestApplyVSchema(t *testing.T) {
 				VSchema: &vschemapb.Keyspace{
 					Sharded: false,
 				},
+				Changes: []*vtctldatapb.VSchemaChange{
+					{Type: "modify", Entity: "keyspace", Details: []string{"sharded: true -> false"}},
+					{Type: "remove", Entity: "vindex", Name: "v1"},
+				},
 			},
 			shouldErr: false,
 		}, {
@@ -364,6 +544,10 @@ func TestApplyVSchema(t *testing.T) {
 				VSchema: &vschemapb.Keyspace{
 					Sharded: false,
 				},
+				Changes: []*vtctldatapb.VSchemaChange{
+					{Type: "modify", Entity: "keyspace", Details: []string{"sharded: true -> false"}},
+					{Type: "remove", Entity: "vindex", Name: "v1"},
+				},
 			},
 			shouldErr: false,
 		}, {
@@ -395,6 +579,100 @@ func TestApplyVSchema(t *testing.T) {
 				VSchema: &vschemapb.Keyspace{
 					Sharded: false,
 				},
+				Changes: []*vtctldatapb.VSchemaChange{
+					{Type: "modify", Entity: "keyspace", Details: []string{"sharded: true -> false"}},
+					{Type: "remove", Entity: "vindex", Name: "v1"},
+				},
+			},
+			shouldErr: false,
+		}, {
+			name: "invalid vschema",
+			req: &vtctldatapb.ApplyVSchemaRequest{
+				Keyspace: "testkeyspace",
+				VSchema: &vschemapb.Keyspace{
+					Sharded: true,
+					Tables: map[string]*vschemapb.Table{
+						"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "v2"}}},
+					},
+				},
+			},
+			shouldErr: true,
+		}, {
+			name: "invalid vschema dry run",
+			req: &vtctldatapb.ApplyVSchemaRequest{
+				Keyspace: "testkeyspace",
+				VSchema: &vschemapb.Keyspace{
+					Sharded: true,
+					Tables: map[string]*vschemapb.Table{
+						"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "v2"}}},
+					},
+				},
+				DryRun: true,
+			},
+			exp: &vtctldatapb.ApplyVSchemaResponse{
+				VSchema: &vschemapb.Keyspace{
+					Sharded: true,
+					Tables: map[string]*vschemapb.Table{
+						"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "v2"}}},
+					},
+				},
+				Changes: []*vtctldatapb.VSchemaChange{
+					{Type: "remove", Entity: "vindex", Name: "v1"},
+					{Type: "add", Entity: "table", Name: "t1"},
+				},
+				ValidationErrors: []*vtctldatapb.VSchemaValidationError{
+					{Entity: "keyspace", Message: "vindex v2 not found for table t1"},
+				},
+			},
+			shouldErr: false,
+		}, {
+			name: "tables not created yet",
+			req: &vtctldatapb.ApplyVSchemaRequest{
+				Keyspace: "testkeyspace",
+				Sql:      "alter vschema on t1 add vindex v1(id)",
+			},
+			exp: &vtctldatapb.ApplyVSchemaResponse{
+				VSchema: &vschemapb.Keyspace{
+					Sharded: true,
+					Vindexes: map[string]*vschemapb.Vindex{
+						"v1": {
+							Type: "hash",
+						},
+					},
+					Tables: map[string]*vschemapb.Table{
+						"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Columns: []string{"id"}, Name: "v1"}}},
+					},
+				},
+				Changes: []*vtctldatapb.VSchemaChange{
+					{Type: "add", Entity: "table", Name: "t1"},
+				},
+			},
+			shouldErr: false,
+		}, {
+			name: "no primary to validate the tables",
+			req: &vtctldatapb.ApplyVSchemaRequest{
+				Keyspace:       "testkeyspace",
+				Sql:            "alter vschema on t1 add vindex v1(id)",
+				ValidateSchema: true,
+			},
+			exp: &vtctldatapb.ApplyVSchemaResponse{
+				VSchema: &vschemapb.Keyspace{
+					Sharded: true,
+					Vindexes: map[string]*vschemapb.Vindex{
+						"v1": {
+							Type: "hash",
+						},
+					},
+					Tables: map[string]*vschemapb.Table{
+						"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Columns: []string{"id"}, Name: "v1"}}},
+					},
+				},
+				Changes: []*vtctldatapb.VSchemaChange{
+					{Type: "add", Entity: "table", Name: "t1"},
+				},
+				ValidationWarnings: []*vtctldatapb.VSchemaValidationError{
+					{Entity: "keyspace", Message: "the tables are not validated against the schema of keyspace `testkeyspace`: schema is not available: no shard in keyspace testkeyspace has a primary"},
+				},
 			},
 			shouldErr: false,
 		},
@@ -470,9 +748,7 @@ func TestApplyVSchema(t *testing.T) {
 			} else {
 				changedSrvVSchema := &vschemapb.SrvVSchema{
 					Keyspaces: map[string]*vschemapb.Keyspace{
-						"testkeyspace": {
-							Sharded: false,
-						},
+						"testkeyspace": tt.exp.VSchema,
 					},
 					RoutingRules: &vschemapb.RoutingRules{
 						Rules: []*vschemapb.RoutingRule{},
@@ -990,6 +1266,135 @@ func TestBackupShard(t *testing.T) {
 	}
 }
 
+func TestCancelSchemaMigration(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ts := memorytopo.NewServer("zone1")
+
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary: true,
+	}, &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  100,
+		},
+		Keyspace: "testkeyspace",
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}, &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  200,
+		},
+		Keyspace: "testkeyspace",
+		Shard:    "80-",
+		Type:     topodatapb.TabletType_PRIMARY,
+	})
+
+	uuid := "6d0ad6c8_2a49_11ed_9c61_0a43f95f28a3"
+	tmc := &testutil.TabletManagerClient{
+		VExecResults: map[string]map[string]struct {
+			Result *querypb.QueryResult
+			Error  error
+		}{
+			"zone1-0000000100": {
+				"update _vt.schema_migrations set migration_status = 'cancel' where migration_uuid = '6d0ad6c8_2a49_11ed_9c61_0a43f95f28a3' and mysql_schema = 'vt_testkeyspace'": {
+					Result: &querypb.QueryResult{RowsAffected: 1},
+				},
+				"update _vt.schema_migrations set migration_status = 'cancel-all' where mysql_schema = 'vt_testkeyspace'": {
+					Result: &querypb.QueryResult{RowsAffected: 3},
+				},
+			},
+			"zone1-0000000200": {
+				"update _vt.schema_migrations set migration_status = 'cancel' where migration_uuid = '6d0ad6c8_2a49_11ed_9c61_0a43f95f28a3' and mysql_schema = 'vt_testkeyspace'": {
+					Result: &querypb.QueryResult{RowsAffected: 0},
+				},
+				"update _vt.schema_migrations set migration_status = 'cancel-all' where mysql_schema = 'vt_testkeyspace'": {
+					Result: &querypb.QueryResult{RowsAffected: 2},
+				},
+			},
+		},
+	}
+
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	tests := []struct {
+		name      string
+		req       *vtctldatapb.CancelSchemaMigrationRequest
+		expected  *vtctldatapb.CancelSchemaMigrationResponse
+		shouldErr bool
+	}{
+		{
+			name: "single migration",
+			req: &vtctldatapb.CancelSchemaMigrationRequest{
+				Keyspace: "testkeyspace",
+				Uuid:     uuid,
+			},
+			expected: &vtctldatapb.CancelSchemaMigrationResponse{
+				RowsAffectedByShard: map[string]uint64{
+					"-80": 1,
+					"80-": 0,
+				},
+			},
+		},
+		{
+			name: "all migrations",
+			req: &vtctldatapb.CancelSchemaMigrationRequest{
+				Keyspace: "testkeyspace",
+			},
+			expected: &vtctldatapb.CancelSchemaMigrationResponse{
+				RowsAffectedByShard: map[string]uint64{
+					"-80": 3,
+					"80-": 2,
+				},
+			},
+		},
+		{
+			name: "invalid uuid",
+			req: &vtctldatapb.CancelSchemaMigrationRequest{
+				Keyspace: "testkeyspace",
+				Uuid:     "not-a-uuid",
+			},
+			shouldErr: true,
+		},
+		{
+			name: "missing keyspace",
+			req: &vtctldatapb.CancelSchemaMigrationRequest{
+				Uuid: uuid,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "keyspace not found",
+			req: &vtctldatapb.CancelSchemaMigrationRequest{
+				Keyspace: "otherkeyspace",
+				Uuid:     uuid,
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := vtctld.CancelSchemaMigration(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
 func TestChangeTabletType(t *testing.T) {
 	t.Parallel()
 
@@ -3328,19 +3733,605 @@ func TestDeleteTablets(t *testing.T) {
 	}
 }
 
-func TestEmergencyReparentShard(t *testing.T) {
-	t.Parallel()
-
-	tests := []struct {
-		name    string
-		ts      *topo.Server
-		tmc     tmclient.TabletManagerClient
-		tablets []*topodatapb.Tablet
-
-		req                 *vtctldatapb.EmergencyReparentShardRequest
-		expected            *vtctldatapb.EmergencyReparentShardResponse
-		expectEventsToOccur bool
-		shouldErr           bool
+func TestDeploySchema(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("zone1")
+	tmc := testutil.TabletManagerClient{
+		GetSchemaResults: map[string]struct {
+			Schema *tabletmanagerdatapb.SchemaDefinition
+			Error  error
+		}{
+			"zone1-0000000100": {
+				Schema: &tabletmanagerdatapb.SchemaDefinition{
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   "t0",
+							Schema: "CREATE TABLE `t0` (`id` int NOT NULL, PRIMARY KEY (`id`))",
+							Type:   tmutils.TableBaseTable,
+						},
+						{
+							Name:   "t1",
+							Schema: "CREATE TABLE `t1` (`id` int NOT NULL, PRIMARY KEY (`id`))",
+							Type:   tmutils.TableBaseTable,
+						},
+						{
+							Name:   "_vt_HOLD_6ace8bcef73211ea87e9f875a4d24e90_20200915120410",
+							Schema: "CREATE TABLE `_vt_HOLD_6ace8bcef73211ea87e9f875a4d24e90_20200915120410` (`id` int NOT NULL, PRIMARY KEY (`id`))",
+							Type:   tmutils.TableBaseTable,
+						},
+						{
+							Name:   "v0",
+							Schema: "CREATE ALGORITHM=UNDEFINED SQL SECURITY DEFINER VIEW `v0` AS select `t0`.`id` AS `id` from `t0`",
+							Type:   tmutils.TableView,
+						},
+					},
+					StoredProgramDefinitions: []*tabletmanagerdatapb.StoredProgramDefinition{
+						{
+							Name:   "tr0",
+							Type:   "TRIGGER",
+							Schema: "CREATE TRIGGER `tr0` BEFORE INSERT ON `t1` FOR EACH ROW set new.id = 1",
+						},
+					},
+				},
+			},
+			"zone1-0000000300": {
+				Schema: &tabletmanagerdatapb.SchemaDefinition{
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   "t1",
+							Schema: "CREATE TABLE `t1` (`id` int NOT NULL, PRIMARY KEY (`id`))",
+							Type:   tmutils.TableBaseTable,
+						},
+					},
+				},
+			},
+			"zone1-0000000301": {
+				Schema: &tabletmanagerdatapb.SchemaDefinition{
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   "t1",
+							Schema: "CREATE TABLE `t1` (`id` int NOT NULL, `val` int, PRIMARY KEY (`id`))",
+							Type:   tmutils.TableBaseTable,
+						},
+					},
+				},
+			},
+			"zone1-0000000400": {
+				Schema: &tabletmanagerdatapb.SchemaDefinition{
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   "t1",
+							Schema: "CREATE TABLE `t1` (`id` int NOT NULL, PRIMARY KEY (`id`))",
+							Type:   tmutils.TableBaseTable,
+						},
+					},
+				},
+			},
+			"zone1-0000000401": {
+				Schema: &tabletmanagerdatapb.SchemaDefinition{
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   "t1",
+							Schema: "CREATE TABLE `t1` (`id` int NOT NULL, PRIMARY KEY (`id`))",
+							Type:   tmutils.TableBaseTable,
+						},
+					},
+				},
+			},
+			"zone1-0000000402": {
+				Schema: &tabletmanagerdatapb.SchemaDefinition{
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   "t1",
+							Schema: "CREATE TABLE `t1` (`id` int NOT NULL, PRIMARY KEY (`id`))",
+							Type:   tmutils.TableBaseTable,
+						},
+					},
+				},
+			},
+		},
+	}
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{AlsoSetShardPrimary: true}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		Keyspace: "testkeyspace",
+		Shard:    "-",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+		Keyspace: "noprimary",
+		Shard:    "-",
+		Type:     topodatapb.TabletType_REPLICA,
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 300},
+		Keyspace: "drifted",
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 301},
+		Keyspace: "drifted",
+		Shard:    "80-",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 400},
+		Keyspace: "sharded",
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 401},
+		Keyspace: "sharded",
+		Shard:    "80-",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 402},
+		Keyspace: "halfprimary",
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 403},
+		Keyspace: "halfprimary",
+		Shard:    "80-",
+		Type:     topodatapb.TabletType_REPLICA,
+	})
+
+	tests := []struct {
+		name      string
+		req       *vtctldatapb.DeploySchemaRequest
+		expected  *vtctldatapb.DeploySchemaResponse
+		shouldErr bool
+	}{
+		{
+			name: "tables before views",
+			req: &vtctldatapb.DeploySchemaRequest{
+				Keyspace: "testkeyspace",
+				Sql: []string{
+					"create view v1 as select id, name from t2",
+					"create table t1 (id int not null, val int, primary key (id))",
+					"create table t2 (id int not null, name varchar(10), primary key (id))",
+				},
+				DryRun: true,
+			},
+			expected: &vtctldatapb.DeploySchemaResponse{
+				Diff: []string{
+					"drop trigger tr0",
+					"drop view v0",
+					"drop table t0",
+					"alter table t1 add column val int",
+					"create table t2 (\n\tid int not null,\n\t`name` varchar(10),\n\tprimary key (id)\n)",
+					"create view v1 as select id, `name` from t2",
+				},
+			},
+		},
+		{
+			name: "table rename",
+			req: &vtctldatapb.DeploySchemaRequest{
+				Keyspace: "testkeyspace",
+				Sql: []string{
+					"create table t1 (id int not null, primary key (id))",
+					"create table t3 (id int not null, primary key (id))",
+					"create view v0 as select t3.id from t3",
+				},
+				HeuristicTableRenames: true,
+				DryRun:                true,
+			},
+			expected: &vtctldatapb.DeploySchemaResponse{
+				Diff: []string{
+					"drop trigger tr0",
+					"drop view v0",
+					"rename table t0 to t3",
+					"create view v0 as select t3.id from t3",
+				},
+			},
+		},
+		{
+			name: "no changes",
+			req: &vtctldatapb.DeploySchemaRequest{
+				Keyspace: "testkeyspace",
+				Sql: []string{
+					"create table t0 (id int not null, primary key (id))",
+					"create table t1 (id int not null, primary key (id))",
+					"create view v0 as select t0.id as id from t0",
+					"create trigger tr0 before insert on t1 for each row set new.id = 1",
+				},
+			},
+			expected: &vtctldatapb.DeploySchemaResponse{},
+		},
+		{
+			name: "stored programs",
+			req: &vtctldatapb.DeploySchemaRequest{
+				Keyspace: "testkeyspace",
+				Sql: []string{
+					"create table t0 (id int not null, primary key (id))",
+					"create table t1 (id int not null, primary key (id))",
+					"create view v0 as select t0.id as id from t0",
+					"create trigger tr0 before insert on t1 for each row set new.id = 2",
+					"create procedure p0() begin select id from t0; select id from t1; end",
+				},
+				DryRun: true,
+			},
+			expected: &vtctldatapb.DeploySchemaResponse{
+				Diff: []string{
+					"drop trigger tr0",
+					"create trigger tr0 before insert on t1 for each row set new.id = 2",
+					"create procedure p0() begin select id from t0; select id from t1; end",
+				},
+			},
+		},
+		{
+			name: "sharded",
+			req: &vtctldatapb.DeploySchemaRequest{
+				Keyspace: "sharded",
+				Sql:      []string{"create table t1 (id int not null, val int, primary key (id))"},
+				DryRun:   true,
+			},
+			expected: &vtctldatapb.DeploySchemaResponse{
+				Diff: []string{
+					"alter table t1 add column val int",
+				},
+			},
+		},
+		{
+			name: "drops without AllowDrops",
+			req: &vtctldatapb.DeploySchemaRequest{
+				Keyspace: "testkeyspace",
+				Sql: []string{
+					"create table t1 (id int not null, primary key (id))",
+					"create view v0 as select t1.id as id from t1",
+					"create trigger tr0 before insert on t1 for each row set new.id = 1",
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "drifted shards",
+			req: &vtctldatapb.DeploySchemaRequest{
+				Keyspace: "drifted",
+				Sql:      []string{"create table t1 (id int not null, val int, primary key (id))"},
+				DryRun:   true,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "shard without primary",
+			req: &vtctldatapb.DeploySchemaRequest{
+				Keyspace: "halfprimary",
+				Sql:      []string{"create table t1 (id int not null, primary key (id))"},
+				DryRun:   true,
+			},
+			shouldErr: true,
+		},
+		{
+			name:      "empty sql",
+			req:       &vtctldatapb.DeploySchemaRequest{Keyspace: "testkeyspace"},
+			shouldErr: true,
+		},
+		{
+			name: "unsupported statement",
+			req: &vtctldatapb.DeploySchemaRequest{
+				Keyspace: "testkeyspace",
+				Sql:      []string{"insert into t1 values (1)"},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "no primary",
+			req: &vtctldatapb.DeploySchemaRequest{
+				Keyspace: "noprimary",
+				Sql:      []string{"create table t1 (id int not null, primary key (id))"},
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := vtctld.DeploySchema(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
+func TestDeploySchemaDrops(t *testing.T) {
+	current, err := schemadiff.NewSchemaFromQueries([]string{
+		"create table t0 (id int not null, primary key (id))",
+		"create table t1 (id int not null, val int, primary key (id))",
+		"create view v0 as select t0.id as id from t0",
+		"create view v1 as select t1.id as id from t1",
+		"create trigger tr0 before insert on t1 for each row set new.id = 1",
+		"create trigger tr1 before insert on t1 for each row set new.id = 1",
+	})
+	require.NoError(t, err)
+	desired, err := schemadiff.NewSchemaFromQueries([]string{
+		"create table t2 (id int not null, primary key (id))",
+		"create table t1 (id int not null, primary key (id))",
+		"create view v0 as select t2.id as id from t2",
+		"create trigger tr0 before insert on t1 for each row set new.id = 2",
+	})
+	require.NoError(t, err)
+
+	diffs, err := current.OrderedDiff(desired, &schemadiff.DiffHints{TableRenameStrategy: schemadiff.TableRenameHeuristicStatement})
+	require.NoError(t, err)
+
+	// t0 is renamed to t2, v0 and tr0 are created again
+	assert.ElementsMatch(t, []string{"view v1", "trigger tr1", "column t1.val"}, deploySchemaDrops(diffs, desired))
+
+	// OrderedDiff may split the change of a stored program into a drop and
+	// a create, which is not a drop either.
+	diffs = []schemadiff.EntityDiff{current.Trigger("tr0").Drop(), current.Trigger("tr1").Drop(), desired.Trigger("tr0").Create()}
+	assert.ElementsMatch(t, []string{"trigger tr1"}, deploySchemaDrops(diffs, desired))
+}
+
+func TestDiffVSchema(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("zone1")
+	tmc := testutil.TabletManagerClient{
+		GetSchemaResults: map[string]struct {
+			Schema *tabletmanagerdatapb.SchemaDefinition
+			Error  error
+		}{
+			"zone1-0000000100": {
+				Schema: &tabletmanagerdatapb.SchemaDefinition{
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   "t1",
+							Schema: "CREATE TABLE `t1` (`id` int NOT NULL, `name` varchar(64), PRIMARY KEY (`id`))",
+							Type:   tmutils.TableBaseTable,
+						},
+					},
+				},
+			},
+			"zone1-0000000200": {
+				Schema: &tabletmanagerdatapb.SchemaDefinition{
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   "name_lookup",
+							Schema: "CREATE TABLE `name_lookup` (",
+							Type:   tmutils.TableBaseTable,
+						},
+					},
+				},
+			},
+		},
+	}
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{AlsoSetShardPrimary: true}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		Keyspace: "testkeyspace",
+		Shard:    "-",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}, &topodatapb.Tablet{
+		// the schema of brokenkeyspace cannot be parsed
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+		Keyspace: "brokenkeyspace",
+		Shard:    "-",
+		Type:     topodatapb.TabletType_PRIMARY,
+	})
+	current := &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"hash": {Type: "hash"},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}}},
+		},
+	}
+	require.NoError(t, ts.SaveVSchema(ctx, "testkeyspace", current))
+	// lookupkeyspace has no primary to read the schema of its lookup tables from
+	testutil.AddKeyspace(ctx, t, ts, &vtctldatapb.Keyspace{
+		Name:     "lookupkeyspace",
+		Keyspace: &topodatapb.Keyspace{},
+	})
+
+	tests := []struct {
+		name      string
+		req       *vtctldatapb.DiffVSchemaRequest
+		expected  *vtctldatapb.DiffVSchemaResponse
+		shouldErr bool
+	}{
+		{
+			name: "vschema",
+			req: &vtctldatapb.DiffVSchemaRequest{
+				Keyspace:       "testkeyspace",
+				ValidateSchema: true,
+				VSchema: &vschemapb.Keyspace{
+					Sharded: true,
+					Vindexes: map[string]*vschemapb.Vindex{
+						"xxhash": {Type: "xxhash"},
+					},
+					Tables: map[string]*vschemapb.Table{
+						"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "uid", Name: "xxhash"}}},
+					},
+				},
+			},
+			expected: &vtctldatapb.DiffVSchemaResponse{
+				Changes: []*vtctldatapb.VSchemaChange{
+					{Type: "remove", Entity: "vindex", Name: "hash"},
+					{Type: "add", Entity: "vindex", Name: "xxhash"},
+					{Type: "modify", Entity: "table", Name: "t1", Details: []string{`column_vindexes: "hash(id)" -> "xxhash(uid)"`}},
+				},
+				ValidationErrors: []*vtctldatapb.VSchemaValidationError{
+					{Entity: "table", Name: "t1", Message: "column `uid` of vindex `xxhash` is not in the table"},
+				},
+				VSchema: &vschemapb.Keyspace{
+					Sharded: true,
+					Vindexes: map[string]*vschemapb.Vindex{
+						"xxhash": {Type: "xxhash"},
+					},
+					Tables: map[string]*vschemapb.Table{
+						"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "uid", Name: "xxhash"}}},
+					},
+				},
+			},
+		},
+		{
+			name: "sql",
+			req: &vtctldatapb.DiffVSchemaRequest{
+				Keyspace:       "testkeyspace",
+				Sql:            "alter vschema on t2 add vindex hash(id)",
+				ValidateSchema: true,
+			},
+			expected: &vtctldatapb.DiffVSchemaResponse{
+				Changes: []*vtctldatapb.VSchemaChange{
+					{Type: "add", Entity: "table", Name: "t2"},
+				},
+				ValidationErrors: []*vtctldatapb.VSchemaValidationError{
+					{Entity: "table", Name: "t2", Message: "table is not in the schema of keyspace `testkeyspace`"},
+				},
+				VSchema: &vschemapb.Keyspace{
+					Sharded: true,
+					Vindexes: map[string]*vschemapb.Vindex{
+						"hash": {Type: "hash"},
+					},
+					Tables: map[string]*vschemapb.Table{
+						"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}}},
+						"t2": {ColumnVindexes: []*vschemapb.ColumnVindex{{Columns: []string{"id"}, Name: "hash"}}},
+					},
+				},
+			},
+		},
+		{
+			name: "no schema validation",
+			req: &vtctldatapb.DiffVSchemaRequest{
+				Keyspace: "testkeyspace",
+				Sql:      "alter vschema on t2 add vindex hash(id)",
+			},
+			expected: &vtctldatapb.DiffVSchemaResponse{
+				Changes: []*vtctldatapb.VSchemaChange{
+					{Type: "add", Entity: "table", Name: "t2"},
+				},
+				VSchema: &vschemapb.Keyspace{
+					Sharded: true,
+					Vindexes: map[string]*vschemapb.Vindex{
+						"hash": {Type: "hash"},
+					},
+					Tables: map[string]*vschemapb.Table{
+						"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}}},
+						"t2": {ColumnVindexes: []*vschemapb.ColumnVindex{{Columns: []string{"id"}, Name: "hash"}}},
+					},
+				},
+			},
+		},
+		{
+			name: "lookup keyspace without a primary",
+			req: &vtctldatapb.DiffVSchemaRequest{
+				Keyspace:       "testkeyspace",
+				Sql:            "alter vschema create vindex name_lookup using lookup_unique with table=`lookupkeyspace.name_lookup`, from=name, to=keyspace_id",
+				ValidateSchema: true,
+			},
+			expected: &vtctldatapb.DiffVSchemaResponse{
+				Changes: []*vtctldatapb.VSchemaChange{
+					{Type: "add", Entity: "vindex", Name: "name_lookup"},
+				},
+				ValidationWarnings: []*vtctldatapb.VSchemaValidationError{
+					{Entity: "vindex", Name: "name_lookup", Message: "lookup table lookupkeyspace.name_lookup is not validated: schema is not available: no shard in keyspace lookupkeyspace has a primary"},
+				},
+				VSchema: &vschemapb.Keyspace{
+					Sharded: true,
+					Vindexes: map[string]*vschemapb.Vindex{
+						"hash": {Type: "hash"},
+						"name_lookup": {
+							Type:   "lookup_unique",
+							Params: map[string]string{"table": "lookupkeyspace.name_lookup", "from": "name", "to": "keyspace_id"},
+						},
+					},
+					Tables: map[string]*vschemapb.Table{
+						"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}}},
+					},
+				},
+			},
+		},
+		{
+			name: "unreadable lookup keyspace schema",
+			req: &vtctldatapb.DiffVSchemaRequest{
+				Keyspace:       "testkeyspace",
+				Sql:            "alter vschema create vindex name_lookup using lookup_unique with table=`brokenkeyspace.name_lookup`, from=name, to=keyspace_id",
+				ValidateSchema: true,
+			},
+			expected: &vtctldatapb.DiffVSchemaResponse{
+				Changes: []*vtctldatapb.VSchemaChange{
+					{Type: "add", Entity: "vindex", Name: "name_lookup"},
+				},
+				ValidationWarnings: []*vtctldatapb.VSchemaValidationError{
+					{Entity: "vindex", Name: "name_lookup", Message: "lookup table brokenkeyspace.name_lookup is not validated: schema is not available: cannot parse the schema of zone1-0000000200: syntax error at position 29"},
+				},
+				VSchema: &vschemapb.Keyspace{
+					Sharded: true,
+					Vindexes: map[string]*vschemapb.Vindex{
+						"hash": {Type: "hash"},
+						"name_lookup": {
+							Type:   "lookup_unique",
+							Params: map[string]string{"table": "brokenkeyspace.name_lookup", "from": "name", "to": "keyspace_id"},
+						},
+					},
+					Tables: map[string]*vschemapb.Table{
+						"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}}},
+					},
+				},
+			},
+		},
+		{
+			name: "both vschema and sql",
+			req: &vtctldatapb.DiffVSchemaRequest{
+				Keyspace: "testkeyspace",
+				VSchema:  &vschemapb.Keyspace{},
+				Sql:      "alter vschema on t2 add vindex hash(id)",
+			},
+			shouldErr: true,
+		},
+		{
+			name: "nonexistent keyspace",
+			req: &vtctldatapb.DiffVSchemaRequest{
+				Keyspace: "nonexistent",
+				VSchema:  &vschemapb.Keyspace{},
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := vtctld.DiffVSchema(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+
+	// the current VSchema is not modified by a diff
+	vs, err := ts.GetVSchema(ctx, "testkeyspace")
+	require.NoError(t, err)
+	utils.MustMatch(t, current, vs)
+}
+
+func TestEmergencyReparentShard(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		ts      *topo.Server
+		tmc     tmclient.TabletManagerClient
+		tablets []*topodatapb.Tablet
+
+		req                 *vtctldatapb.EmergencyReparentShardRequest
+		expected            *vtctldatapb.EmergencyReparentShardResponse
+		expectEventsToOccur bool
+		shouldErr           bool
 	}{
 		{
 			name: "successful reparent",
@@ -4617,78 +5608,223 @@ func TestGetSchema(t *testing.T) {
 			shouldErr: false,
 		},
 		{
-			name: "table sizes only",
-			req: &vtctldatapb.GetSchemaRequest{
-				TabletAlias:    validAlias,
-				TableSizesOnly: true,
+			name: "table sizes only",
+			req: &vtctldatapb.GetSchemaRequest{
+				TabletAlias:    validAlias,
+				TableSizesOnly: true,
+			},
+			expected: &vtctldatapb.GetSchemaResponse{
+				Schema: &tabletmanagerdatapb.SchemaDefinition{
+					DatabaseSchema: "CREATE DATABASE vt_testkeyspace",
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:       "t1",
+							Type:       "BASE",
+							DataLength: 100,
+							RowCount:   50,
+						},
+					},
+				},
+			},
+			shouldErr: false,
+		},
+		{
+			name: "table names take precedence over table sizes",
+			req: &vtctldatapb.GetSchemaRequest{
+				TabletAlias:    validAlias,
+				TableNamesOnly: true,
+				TableSizesOnly: true,
+			},
+			expected: &vtctldatapb.GetSchemaResponse{
+				Schema: &tabletmanagerdatapb.SchemaDefinition{
+					DatabaseSchema: "CREATE DATABASE vt_testkeyspace",
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name: "t1",
+						},
+					},
+				},
+			},
+			shouldErr: false,
+		},
+		// error cases
+		{
+			name: "no tablet",
+			req: &vtctldatapb.GetSchemaRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "notfound",
+					Uid:  100,
+				},
+			},
+			expected:  nil,
+			shouldErr: true,
+		},
+		{
+			name: "no schema",
+			req: &vtctldatapb.GetSchemaRequest{
+				TabletAlias: otherAlias,
+			},
+			expected:  nil,
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupSchema()
+
+			resp, err := vtctld.GetSchema(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
+func TestGetSchemaMigrations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ts := memorytopo.NewServer("zone1")
+
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary: true,
+	}, &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  100,
+		},
+		Keyspace: "testkeyspace",
+		Shard:    "-",
+		Type:     topodatapb.TabletType_PRIMARY,
+	})
+
+	fields := sqltypes.MakeTestFields(
+		"migration_uuid|keyspace|shard|mysql_schema|mysql_table|strategy|requested_timestamp|completed_timestamp|migration_status|progress|eta_seconds|rows_copied|tablet|postpone_completion",
+		"varchar|varchar|varchar|varchar|varchar|varchar|timestamp|timestamp|varchar|float64|int64|uint64|varchar|int64",
+	)
+	qr := sqltypes.ResultToProto3(sqltypes.MakeTestResult(fields,
+		"6d0ad6c8_2a49_11ed_9c61_0a43f95f28a3|testkeyspace|-|vt_testkeyspace|t1|vitess|2022-09-01 12:00:00|null|running|42.5|30|100|zone1-0000000100|0",
+		"7e1be7d9_2a49_11ed_9c61_0a43f95f28a3|testkeyspace|-|vt_testkeyspace|t2|online|2022-09-01 11:00:00|2022-09-01 11:05:00|complete|100|0|2000|zone1-0000000100|1",
+	))
+
+	tmc := &testutil.TabletManagerClient{
+		VExecResults: map[string]map[string]struct {
+			Result *querypb.QueryResult
+			Error  error
+		}{
+			"zone1-0000000100": {
+				"select * from _vt.schema_migrations where mysql_schema = 'vt_testkeyspace' order by id asc": {
+					Result: qr,
+				},
+				"select * from _vt.schema_migrations where mysql_schema = 'vt_testkeyspace' and migration_status = 'running' order by id asc": {
+					Result: &querypb.QueryResult{
+						Fields: qr.Fields,
+						Rows:   qr.Rows[:1],
+					},
+				},
+			},
+		},
+	}
+
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	running := &vtctldatapb.SchemaMigration{
+		Uuid:        "6d0ad6c8_2a49_11ed_9c61_0a43f95f28a3",
+		Keyspace:    "testkeyspace",
+		Shard:       "-",
+		Schema:      "vt_testkeyspace",
+		Table:       "t1",
+		Strategy:    "vitess",
+		RequestedAt: protoutil.TimeToProto(time.Date(2022, time.September, 1, 12, 0, 0, 0, time.UTC)),
+		Status:      "running",
+		Progress:    42.5,
+		EtaSeconds:  30,
+		RowsCopied:  100,
+		Tablet: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  100,
+		},
+	}
+	complete := &vtctldatapb.SchemaMigration{
+		Uuid:        "7e1be7d9_2a49_11ed_9c61_0a43f95f28a3",
+		Keyspace:    "testkeyspace",
+		Shard:       "-",
+		Schema:      "vt_testkeyspace",
+		Table:       "t2",
+		Strategy:    "online",
+		RequestedAt: protoutil.TimeToProto(time.Date(2022, time.September, 1, 11, 0, 0, 0, time.UTC)),
+		CompletedAt: protoutil.TimeToProto(time.Date(2022, time.September, 1, 11, 5, 0, 0, time.UTC)),
+		Status:      "complete",
+		Progress:    100,
+		RowsCopied:  2000,
+		Tablet: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  100,
+		},
+		PostponeCompletion: true,
+	}
+
+	tests := []struct {
+		name      string
+		req       *vtctldatapb.GetSchemaMigrationsRequest
+		expected  *vtctldatapb.GetSchemaMigrationsResponse
+		shouldErr bool
+	}{
+		{
+			name: "all migrations",
+			req: &vtctldatapb.GetSchemaMigrationsRequest{
+				Keyspace: "testkeyspace",
 			},
-			expected: &vtctldatapb.GetSchemaResponse{
-				Schema: &tabletmanagerdatapb.SchemaDefinition{
-					DatabaseSchema: "CREATE DATABASE vt_testkeyspace",
-					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
-						{
-							Name:       "t1",
-							Type:       "BASE",
-							DataLength: 100,
-							RowCount:   50,
-						},
-					},
-				},
+			expected: &vtctldatapb.GetSchemaMigrationsResponse{
+				Migrations: []*vtctldatapb.SchemaMigration{running, complete},
 			},
-			shouldErr: false,
 		},
 		{
-			name: "table names take precedence over table sizes",
-			req: &vtctldatapb.GetSchemaRequest{
-				TabletAlias:    validAlias,
-				TableNamesOnly: true,
-				TableSizesOnly: true,
+			name: "by status",
+			req: &vtctldatapb.GetSchemaMigrationsRequest{
+				Keyspace: "testkeyspace",
+				Status:   "running",
 			},
-			expected: &vtctldatapb.GetSchemaResponse{
-				Schema: &tabletmanagerdatapb.SchemaDefinition{
-					DatabaseSchema: "CREATE DATABASE vt_testkeyspace",
-					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
-						{
-							Name: "t1",
-						},
-					},
-				},
+			expected: &vtctldatapb.GetSchemaMigrationsResponse{
+				Migrations: []*vtctldatapb.SchemaMigration{running},
 			},
-			shouldErr: false,
 		},
-		// error cases
 		{
-			name: "no tablet",
-			req: &vtctldatapb.GetSchemaRequest{
-				TabletAlias: &topodatapb.TabletAlias{
-					Cell: "notfound",
-					Uid:  100,
-				},
+			name: "conflicting filters",
+			req: &vtctldatapb.GetSchemaMigrationsRequest{
+				Keyspace: "testkeyspace",
+				Uuid:     "6d0ad6c8_2a49_11ed_9c61_0a43f95f28a3",
+				Status:   "running",
 			},
-			expected:  nil,
 			shouldErr: true,
 		},
 		{
-			name: "no schema",
-			req: &vtctldatapb.GetSchemaRequest{
-				TabletAlias: otherAlias,
-			},
-			expected:  nil,
+			name:      "missing keyspace",
+			req:       &vtctldatapb.GetSchemaMigrationsRequest{},
 			shouldErr: true,
 		},
 	}
 
 	for _, tt := range tests {
+		tt := tt
+
 		t.Run(tt.name, func(t *testing.T) {
-			setupSchema()
+			t.Parallel()
 
-			resp, err := vtctld.GetSchema(ctx, tt.req)
+			resp, err := vtctld.GetSchemaMigrations(ctx, tt.req)
 			if tt.shouldErr {
 				assert.Error(t, err)
 				return
 			}
 
-			assert.NoError(t, err)
+			require.NoError(t, err)
 			utils.MustMatch(t, tt.expected, resp)
 		})
 	}
@@ -8351,7 +9487,7 @@ func TestSetKeyspaceDurabilityPolicy(t *testing.T) {
 				Keyspace:         "ks1",
 				DurabilityPolicy: "non-existent",
 			},
-			expectedErr: "durability policy <non-existent> is not a valid policy. Please register it as a policy first",
+			expectedErr: "durability policy <non-existent> is not a valid policy. Please register it as a policy first, or declare it in --durability_policies_file",
 		},
 	}
 
@@ -10610,6 +11746,224 @@ func TestUpdateCellsAlias(t *testing.T) {
 	}
 }
 
+func TestUpdateThrottlerConfig(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	future := protoutil.TimeToProto(time.Now().Add(time.Hour))
+	past := protoutil.TimeToProto(time.Now().Add(-time.Hour))
+
+	tests := []struct {
+		name        string
+		keyspace    *topodatapb.Keyspace
+		req         *vtctldatapb.UpdateThrottlerConfigRequest
+		expected    *topodatapb.ThrottlerConfig
+		expectedErr string
+	}{
+		{
+			name:     "enable with threshold",
+			keyspace: &topodatapb.Keyspace{},
+			req: &vtctldatapb.UpdateThrottlerConfigRequest{
+				Keyspace:  "ks1",
+				Enable:    true,
+				Threshold: 2.5,
+			},
+			expected: &topodatapb.ThrottlerConfig{
+				Enabled:   proto.Bool(true),
+				Threshold: 2.5,
+			},
+		},
+		{
+			name: "custom query with threshold",
+			keyspace: &topodatapb.Keyspace{
+				ThrottlerConfig: &topodatapb.ThrottlerConfig{
+					Enabled:   proto.Bool(true),
+					Threshold: 2.5,
+				},
+			},
+			req: &vtctldatapb.UpdateThrottlerConfigRequest{
+				Keyspace:       "ks1",
+				CustomQuery:    "show global status like 'threads_running'",
+				CustomQuerySet: true,
+				Threshold:      50,
+			},
+			expected: &topodatapb.ThrottlerConfig{
+				Enabled:     proto.Bool(true),
+				CustomQuery: "show global status like 'threads_running'",
+				Threshold:   50,
+			},
+		},
+		{
+			name: "default query resets threshold",
+			keyspace: &topodatapb.Keyspace{
+				ThrottlerConfig: &topodatapb.ThrottlerConfig{
+					Enabled:     proto.Bool(true),
+					CustomQuery: "show global status like 'threads_running'",
+					Threshold:   50,
+				},
+			},
+			req: &vtctldatapb.UpdateThrottlerConfigRequest{
+				Keyspace:       "ks1",
+				CustomQuerySet: true,
+			},
+			expected: &topodatapb.ThrottlerConfig{
+				Enabled: proto.Bool(true),
+			},
+		},
+		{
+			name:     "custom query without threshold",
+			keyspace: &topodatapb.Keyspace{},
+			req: &vtctldatapb.UpdateThrottlerConfigRequest{
+				Keyspace:       "ks1",
+				CustomQuery:    "show global status like 'threads_running'",
+				CustomQuerySet: true,
+			},
+			expectedErr: "a custom query requires a positive threshold",
+		},
+		{
+			name:     "negative threshold",
+			keyspace: &topodatapb.Keyspace{},
+			req: &vtctldatapb.UpdateThrottlerConfigRequest{
+				Keyspace:  "ks1",
+				Threshold: -1,
+			},
+			expectedErr: "threshold must be positive, got -1",
+		},
+		{
+			name: "throttle app",
+			keyspace: &topodatapb.Keyspace{
+				ThrottlerConfig: &topodatapb.ThrottlerConfig{
+					Enabled: proto.Bool(true),
+					ThrottledApps: map[string]*topodatapb.ThrottledAppRule{
+						"stale": {Name: "stale", Ratio: 1, ExpiresAt: past},
+					},
+				},
+			},
+			req: &vtctldatapb.UpdateThrottlerConfigRequest{
+				Keyspace: "ks1",
+				ThrottledApp: &topodatapb.ThrottledAppRule{
+					Name:      "online-ddl",
+					Ratio:     0.5,
+					ExpiresAt: future,
+				},
+			},
+			expected: &topodatapb.ThrottlerConfig{
+				Enabled: proto.Bool(true),
+				ThrottledApps: map[string]*topodatapb.ThrottledAppRule{
+					"online-ddl": {Name: "online-ddl", Ratio: 0.5, ExpiresAt: future},
+				},
+			},
+		},
+		{
+			name:     "throttle app without enabling",
+			keyspace: &topodatapb.Keyspace{},
+			req: &vtctldatapb.UpdateThrottlerConfigRequest{
+				Keyspace: "ks1",
+				ThrottledApp: &topodatapb.ThrottledAppRule{
+					Name:      "online-ddl",
+					Ratio:     1,
+					ExpiresAt: future,
+				},
+			},
+			expected: &topodatapb.ThrottlerConfig{
+				ThrottledApps: map[string]*topodatapb.ThrottledAppRule{
+					"online-ddl": {Name: "online-ddl", Ratio: 1, ExpiresAt: future},
+				},
+			},
+		},
+		{
+			name: "unthrottle app",
+			keyspace: &topodatapb.Keyspace{
+				ThrottlerConfig: &topodatapb.ThrottlerConfig{
+					ThrottledApps: map[string]*topodatapb.ThrottledAppRule{
+						"online-ddl": {Name: "online-ddl", Ratio: 1, ExpiresAt: future},
+						"workflow":   {Name: "workflow", Exempt: true, ExpiresAt: future},
+					},
+				},
+			},
+			req: &vtctldatapb.UpdateThrottlerConfigRequest{
+				Keyspace: "ks1",
+				ThrottledApp: &topodatapb.ThrottledAppRule{
+					Name:      "online-ddl",
+					ExpiresAt: past,
+				},
+			},
+			expected: &topodatapb.ThrottlerConfig{
+				ThrottledApps: map[string]*topodatapb.ThrottledAppRule{
+					"workflow": {Name: "workflow", Exempt: true, ExpiresAt: future},
+				},
+			},
+		},
+		{
+			name:     "enable and disable",
+			keyspace: &topodatapb.Keyspace{},
+			req: &vtctldatapb.UpdateThrottlerConfigRequest{
+				Keyspace: "ks1",
+				Enable:   true,
+				Disable:  true,
+			},
+			expectedErr: "--enable and --disable are mutually exclusive",
+		},
+		{
+			name:     "invalid ratio",
+			keyspace: &topodatapb.Keyspace{},
+			req: &vtctldatapb.UpdateThrottlerConfigRequest{
+				Keyspace: "ks1",
+				ThrottledApp: &topodatapb.ThrottledAppRule{
+					Name:      "online-ddl",
+					Ratio:     1.5,
+					ExpiresAt: future,
+				},
+			},
+			expectedErr: "throttled app ratio must be in the range [0..1], got 1.5",
+		},
+		{
+			name: "keyspace not found",
+			req: &vtctldatapb.UpdateThrottlerConfigRequest{
+				Keyspace: "ks1",
+				Enable:   true,
+			},
+			expectedErr: "node doesn't exist: keyspaces/ks1",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := memorytopo.NewServer("zone1")
+			if tt.keyspace != nil {
+				testutil.AddKeyspace(ctx, t, ts, &vtctldatapb.Keyspace{
+					Name:     "ks1",
+					Keyspace: tt.keyspace,
+				})
+				require.NoError(t, ts.UpdateSrvKeyspace(ctx, "zone1", "ks1", &topodatapb.SrvKeyspace{}))
+			}
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(ts)
+			})
+			resp, err := vtctld.UpdateThrottlerConfig(ctx, tt.req)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp.ThrottlerConfig)
+
+			ki, err := ts.GetKeyspace(ctx, "ks1")
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, ki.ThrottlerConfig)
+
+			srvks, err := ts.GetSrvKeyspace(ctx, "zone1", "ks1")
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, srvks.ThrottlerConfig)
+		})
+	}
+}
+
 func TestValidate(t *testing.T) {
 	t.Parallel()
 
@@ -10912,6 +12266,35 @@ func TestValidateSchemaKeyspace(t *testing.T) {
 			},
 			shouldErr: false,
 		},
+		{
+			name: "different stored programs",
+			req: &vtctldatapb.ValidateSchemaKeyspaceRequest{
+				Keyspace: "ks1",
+			},
+			expected: &vtctldatapb.ValidateSchemaKeyspaceResponse{
+				Results: []string{"zone1-0000000100 has an extra procedure named p1"},
+				ResultsByShard: map[string]*vtctldatapb.ValidateShardResponse{
+					"-": {Results: []string{"zone1-0000000100 has an extra procedure named p1"}},
+				},
+			},
+			setup: func() {
+				withProcedure := proto.Clone(schema1).(*tabletmanagerdatapb.SchemaDefinition)
+				withProcedure.StoredProgramDefinitions = []*tabletmanagerdatapb.StoredProgramDefinition{{
+					Name:   "p1",
+					Type:   "PROCEDURE",
+					Schema: "CREATE PROCEDURE `p1`() select 1",
+				}}
+				setupSchema(&topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				}, withProcedure)
+				setupSchema(&topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  101,
+				}, schema1)
+			},
+			shouldErr: false,
+		},
 		{
 			name: "skip-no-primary: no primary",
 			req: &vtctldatapb.ValidateSchemaKeyspaceRequest{
@@ -11615,3 +12998,132 @@ func TestValidateShard(t *testing.T) {
 		})
 	}
 }
+
+func TestWatchShards(t *testing.T) {
+	watchShardsRelistInterval = 10 * time.Millisecond
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer("zone1")
+	testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{
+		Keyspace: "testkeyspace",
+		Name:     "-80",
+	}, &vtctldatapb.Shard{
+		Keyspace: "testkeyspace",
+		Name:     "80-",
+	}, &vtctldatapb.Shard{
+		Keyspace: "otherkeyspace",
+		Name:     "-",
+	})
+
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+	client := localvtctldclient.New(vtctld)
+
+	t.Run("shard changes", func(t *testing.T) {
+		stream, err := client.WatchShards(ctx, &vtctldatapb.WatchShardsRequest{
+			Keyspaces: []string{"testkeyspace"},
+		})
+		require.NoError(t, err)
+
+		// The current shard records are sent once the watches are established.
+		var current []string
+		for i := 0; i < 2; i++ {
+			resp, err := stream.Recv()
+			require.NoError(t, err)
+			assert.Equal(t, "testkeyspace", resp.Shard.Keyspace)
+			assert.NotNil(t, resp.Shard.Shard)
+			current = append(current, resp.Shard.Name)
+		}
+		assert.ElementsMatch(t, []string{"-80", "80-"}, current)
+
+		_, err = ts.UpdateShardFields(ctx, "otherkeyspace", "-", func(si *topo.ShardInfo) error {
+			si.IsPrimaryServing = false
+			return nil
+		})
+		require.NoError(t, err)
+
+		primary := &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  100,
+		}
+		_, err = ts.UpdateShardFields(ctx, "testkeyspace", "80-", func(si *topo.ShardInfo) error {
+			si.PrimaryAlias = primary
+			return nil
+		})
+		require.NoError(t, err)
+
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, "testkeyspace", resp.Shard.Keyspace)
+		assert.Equal(t, "80-", resp.Shard.Name)
+		utils.MustMatch(t, primary, resp.Shard.Shard.PrimaryAlias)
+
+		err = ts.DeleteShard(ctx, "testkeyspace", "-80")
+		require.NoError(t, err)
+
+		// A deleted shard is sent without a record.
+		resp, err = stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, "testkeyspace", resp.Shard.Keyspace)
+		assert.Equal(t, "-80", resp.Shard.Name)
+		assert.Nil(t, resp.Shard.Shard)
+
+		// Shards created after the stream started are watched once the
+		// shards are listed again.
+		err = ts.CreateShard(ctx, "testkeyspace", "-40")
+		require.NoError(t, err)
+
+		resp, err = stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, "testkeyspace", resp.Shard.Keyspace)
+		assert.Equal(t, "-40", resp.Shard.Name)
+		assert.NotNil(t, resp.Shard.Shard)
+
+		_, err = ts.UpdateShardFields(ctx, "testkeyspace", "-40", func(si *topo.ShardInfo) error {
+			si.PrimaryAlias = primary
+			return nil
+		})
+		require.NoError(t, err)
+
+		resp, err = stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, "-40", resp.Shard.Name)
+		utils.MustMatch(t, primary, resp.Shard.Shard.PrimaryAlias)
+	})
+
+	t.Run("keyspaces created later", func(t *testing.T) {
+		stream, err := client.WatchShards(ctx, &vtctldatapb.WatchShardsRequest{})
+		require.NoError(t, err)
+
+		seen := map[string]bool{}
+		created := false
+		for !seen["newkeyspace/-"] {
+			resp, err := stream.Recv()
+			require.NoError(t, err)
+			seen[resp.Shard.Keyspace+"/"+resp.Shard.Name] = true
+
+			if resp.Shard.Keyspace == "otherkeyspace" && !created {
+				created = true
+				// Once the stream is established, create a new keyspace.
+				testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{
+					Keyspace: "newkeyspace",
+					Name:     "-",
+				})
+			}
+		}
+	})
+
+	t.Run("keyspace not found", func(t *testing.T) {
+		stream, err := client.WatchShards(ctx, &vtctldatapb.WatchShardsRequest{
+			Keyspaces: []string{"notfound"},
+		})
+		require.NoError(t, err)
+
+		_, err = stream.Recv()
+		assert.Error(t, err)
+	})
+}
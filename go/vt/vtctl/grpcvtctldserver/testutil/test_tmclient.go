@@ -282,6 +282,16 @@ type TabletManagerClient struct {
 	UndoDemotePrimaryDelays map[string]time.Duration
 	// keyed by tablet alias
 	UndoDemotePrimaryResults map[string]error
+	// keyed by tablet alias.
+	VDiffResults map[string]struct {
+		Response *tabletmanagerdatapb.VDiffResponse
+		Error    error
+	}
+	// tablet alias => query string => result
+	VExecResults map[string]map[string]struct {
+		Result *querypb.QueryResult
+		Error  error
+	}
 	// tablet alias => duration
 	VReplicationExecDelays map[string]time.Duration
 	// tablet alias => query string => result
@@ -1223,6 +1233,52 @@ func (fake *TabletManagerClient) UndoDemotePrimary(ctx context.Context, tablet *
 	return assert.AnError
 }
 
+// VDiff is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) VDiff(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.VDiffRequest) (*tabletmanagerdatapb.VDiffResponse, error) {
+	if fake.VDiffResults == nil {
+		return nil, assert.AnError
+	}
+
+	if tablet.Alias == nil {
+		return nil, assert.AnError
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if result, ok := fake.VDiffResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, assert.AnError
+}
+
+// VExec is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) VExec(ctx context.Context, tablet *topodatapb.Tablet, query string, workflow string, keyspace string) (*querypb.QueryResult, error) {
+	if fake.VExecResults == nil {
+		return nil, assert.AnError
+	}
+
+	if tablet.Alias == nil {
+		return nil, assert.AnError
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if resultsForTablet, ok := fake.VExecResults[key]; ok {
+		// Round trip the expected query both to ensure it's valid and to
+		// standardize on capitalization and formatting.
+		stmt, err := sqlparser.Parse(query)
+		if err != nil {
+			return nil, err
+		}
+
+		parsedQuery := sqlparser.String(stmt)
+		if result, ok := resultsForTablet[parsedQuery]; ok {
+			return result.Result, result.Error
+		}
+	}
+
+	return nil, assert.AnError
+}
+
 // VReplicationExec is part of the tmclient.TabletManagerCLient interface.
 func (fake *TabletManagerClient) VReplicationExec(ctx context.Context, tablet *topodatapb.Tablet, query string) (*querypb.QueryResult, error) {
 	if fake.VReplicationExecResults == nil {
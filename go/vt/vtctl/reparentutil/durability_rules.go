@@ -0,0 +1,291 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reparentutil
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"vitess.io/vitess/go/vt/log"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/promotionrule"
+)
+
+var (
+	durabilityPoliciesFile = flag.String("durability_policies_file", "", "JSON or YAML file of declarative durability policies. Keyspaces use them by name, like the built-in policies")
+)
+
+// Relations of a replica to the primary in SemiSyncRule
+const (
+	SameAsPrimary      = "same"
+	DifferentToPrimary = "different"
+)
+
+// DurabilityPolicies is the content of a durability policies file.
+// For example, this file declares a policy where a primary needs a semi-sync ack
+// from a REPLICA in another region, while RDONLY tablets never ack:
+//
+//	cell_aliases:
+//	  us_east: [zone1, zone2]
+//	  us_west: [zone3]
+//	policies:
+//	  cross_region:
+//	    promotion_rules:
+//	      - tablet_types: [PRIMARY, REPLICA]
+//	        promotion_rule: neutral
+//	    semi_sync_ackers: 1
+//	    semi_sync_rules:
+//	      - tablet_types: [PRIMARY, REPLICA]
+//	        primary_cell_alias: different
+type DurabilityPolicies struct {
+	// CellAliases maps each cell alias to its cells. Durability policies are evaluated
+	// on tablet records alone, so the aliases are declared here rather than read from
+	// the topo. Like in the topo, a cell that is in no alias is its own alias.
+	CellAliases map[string][]string `json:"cell_aliases,omitempty"`
+	// Policies maps the policy names to their rules.
+	Policies map[string]*DurabilityRules `json:"policies"`
+}
+
+// DurabilityRules declares a durability policy.
+type DurabilityRules struct {
+	// PromotionRules are evaluated in order. The first one matching a tablet gives
+	// its promotion rule. Tablets matching none must not be promoted.
+	PromotionRules []*TabletPromotionRule `json:"promotion_rules"`
+	// SemiSyncAckers is the number of semi-sync acks a primary waits for. 0 disables semi-sync.
+	SemiSyncAckers int `json:"semi_sync_ackers,omitempty"`
+	// SemiSyncRules select the replicas which send semi-sync acks. A replica acks if it matches any of them.
+	SemiSyncRules []*SemiSyncRule `json:"semi_sync_rules,omitempty"`
+}
+
+// TabletSelector matches tablets. Empty fields match all tablets.
+type TabletSelector struct {
+	TabletTypes []string `json:"tablet_types,omitempty"`
+	Cells       []string `json:"cells,omitempty"`
+	CellAliases []string `json:"cell_aliases,omitempty"`
+}
+
+// TabletPromotionRule gives a promotion rule to the tablets it selects.
+type TabletPromotionRule struct {
+	TabletSelector
+	PromotionRule promotionrule.CandidatePromotionRule `json:"promotion_rule"`
+}
+
+// SemiSyncRule selects replicas which send semi-sync acks. Besides the replica itself,
+// it can match the cell and cell alias of the replica against those of the primary.
+type SemiSyncRule struct {
+	TabletSelector
+	// PrimaryCell is SameAsPrimary, DifferentToPrimary, or empty to match any cell.
+	PrimaryCell string `json:"primary_cell,omitempty"`
+	// PrimaryCellAlias is SameAsPrimary, DifferentToPrimary, or empty to match any cell alias.
+	PrimaryCellAlias string `json:"primary_cell_alias,omitempty"`
+}
+
+// ParseDurabilityPolicies parses and validates durability policies, given in JSON or YAML.
+func ParseDurabilityPolicies(data []byte) (*DurabilityPolicies, error) {
+	policies := &DurabilityPolicies{}
+	if err := yaml.UnmarshalStrict(data, policies); err != nil {
+		return nil, err
+	}
+	if _, err := policies.cellToAlias(); err != nil {
+		return nil, err
+	}
+	for name, rules := range policies.Policies {
+		if err := rules.validate(); err != nil {
+			return nil, fmt.Errorf("durability policy %v: %v", name, err)
+		}
+	}
+	return policies, nil
+}
+
+// LoadDurabilityPolicies reads the durability policies of the given file and registers them.
+// Like RegisterDurability, it is meant to be called once, before the policies are used.
+func LoadDurabilityPolicies(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	policies, err := ParseDurabilityPolicies(data)
+	if err != nil {
+		return fmt.Errorf("%v: %v", path, err)
+	}
+	for name := range policies.Policies {
+		if durabilityPolicies[name] != nil {
+			return fmt.Errorf("%v: durability policy %v already registered", path, name)
+		}
+	}
+	cellToAlias, _ := policies.cellToAlias()
+	for name, rules := range policies.Policies {
+		durability := newDurabilityRules(rules, cellToAlias)
+		durabilityPolicies[name] = func() Durabler {
+			return durability
+		}
+	}
+	return nil
+}
+
+// LoadDurabilityPoliciesFile registers the policies of --durability_policies_file.
+// It must be called once the flags are parsed, and exits if the file is not valid,
+// rather than leaving the keyspaces using its policies without a durability policy.
+func LoadDurabilityPoliciesFile() {
+	if *durabilityPoliciesFile == "" {
+		return
+	}
+	if err := LoadDurabilityPolicies(*durabilityPoliciesFile); err != nil {
+		log.Exitf("failed to load durability policies: %v", err)
+	}
+}
+
+// cellToAlias inverts CellAliases.
+func (policies *DurabilityPolicies) cellToAlias() (map[string]string, error) {
+	cellToAlias := make(map[string]string)
+	for alias, cells := range policies.CellAliases {
+		for _, cell := range cells {
+			if other, ok := cellToAlias[cell]; ok {
+				return nil, fmt.Errorf("cell %v is in both cell aliases %v and %v", cell, other, alias)
+			}
+			cellToAlias[cell] = alias
+		}
+	}
+	return cellToAlias, nil
+}
+
+func (rules *DurabilityRules) validate() error {
+	for _, rule := range rules.PromotionRules {
+		if err := rule.TabletSelector.validate(); err != nil {
+			return err
+		}
+		if _, err := promotionrule.Parse(string(rule.PromotionRule)); err != nil {
+			return err
+		}
+	}
+	if rules.SemiSyncAckers < 0 {
+		return fmt.Errorf("semi_sync_ackers must not be negative")
+	}
+	if rules.SemiSyncAckers > 0 && len(rules.SemiSyncRules) == 0 {
+		return fmt.Errorf("semi_sync_ackers is %v, but no semi_sync_rules select the replicas which ack", rules.SemiSyncAckers)
+	}
+	for _, rule := range rules.SemiSyncRules {
+		if err := rule.TabletSelector.validate(); err != nil {
+			return err
+		}
+		for _, relation := range []string{rule.PrimaryCell, rule.PrimaryCellAlias} {
+			switch relation {
+			case "", SameAsPrimary, DifferentToPrimary:
+			default:
+				return fmt.Errorf("invalid relation to the primary %q, expected %q or %q", relation, SameAsPrimary, DifferentToPrimary)
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks the tablet types, and rewrites them in the case of their enum names.
+func (selector *TabletSelector) validate() error {
+	for i, tabletType := range selector.TabletTypes {
+		parsed, err := topoproto.ParseTabletType(tabletType)
+		if err != nil {
+			return err
+		}
+		selector.TabletTypes[i] = parsed.String()
+	}
+	return nil
+}
+
+//=======================================================================
+
+// durabilityRules is the Durabler of a declarative durability policy
+type durabilityRules struct {
+	rules       *DurabilityRules
+	cellToAlias map[string]string
+}
+
+func newDurabilityRules(rules *DurabilityRules, cellToAlias map[string]string) *durabilityRules {
+	return &durabilityRules{
+		rules:       rules,
+		cellToAlias: cellToAlias,
+	}
+}
+
+// promotionRule implements the Durabler interface
+func (d *durabilityRules) promotionRule(tablet *topodatapb.Tablet) promotionrule.CandidatePromotionRule {
+	for _, rule := range d.rules.PromotionRules {
+		if d.matches(&rule.TabletSelector, tablet) {
+			return rule.PromotionRule
+		}
+	}
+	return promotionrule.MustNot
+}
+
+// semiSyncAckers implements the Durabler interface
+func (d *durabilityRules) semiSyncAckers(tablet *topodatapb.Tablet) int {
+	return d.rules.SemiSyncAckers
+}
+
+// isReplicaSemiSync implements the Durabler interface
+func (d *durabilityRules) isReplicaSemiSync(primary, replica *topodatapb.Tablet) bool {
+	for _, rule := range d.rules.SemiSyncRules {
+		if d.matches(&rule.TabletSelector, replica) &&
+			matchesRelation(rule.PrimaryCell, primary.Alias.Cell, replica.Alias.Cell) &&
+			matchesRelation(rule.PrimaryCellAlias, d.cellAlias(primary.Alias.Cell), d.cellAlias(replica.Alias.Cell)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *durabilityRules) matches(selector *TabletSelector, tablet *topodatapb.Tablet) bool {
+	if len(selector.TabletTypes) > 0 && !containsString(selector.TabletTypes, tablet.Type.String()) {
+		return false
+	}
+	if len(selector.Cells) > 0 && !containsString(selector.Cells, tablet.Alias.Cell) {
+		return false
+	}
+	if len(selector.CellAliases) > 0 && !containsString(selector.CellAliases, d.cellAlias(tablet.Alias.Cell)) {
+		return false
+	}
+	return true
+}
+
+func (d *durabilityRules) cellAlias(cell string) string {
+	if alias, ok := d.cellToAlias[cell]; ok {
+		return alias
+	}
+	return cell
+}
+
+func matchesRelation(relation string, primaryValue, replicaValue string) bool {
+	switch relation {
+	case SameAsPrimary:
+		return primaryValue == replicaValue
+	case DifferentToPrimary:
+		return primaryValue != replicaValue
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reparentutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/promotionrule"
+)
+
+const crossRegionPolicies = `
+cell_aliases:
+  us_east: [zone1, zone2]
+  us_west: [zone3]
+policies:
+  test_rules_cross_region:
+    promotion_rules:
+      - tablet_types: [replica]
+        cells: [zone2]
+        promotion_rule: prefer
+      - tablet_types: [PRIMARY, REPLICA]
+        promotion_rule: neutral
+    semi_sync_ackers: 1
+    semi_sync_rules:
+      - tablet_types: [PRIMARY, REPLICA]
+        primary_cell_alias: different
+`
+
+func newTestTablet(cell string, uid uint32, tabletType topodatapb.TabletType) *topodatapb.Tablet {
+	return &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: cell,
+			Uid:  uid,
+		},
+		Type: tabletType,
+	}
+}
+
+func TestDurabilityRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "durability.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(crossRegionPolicies), 0600))
+	require.NoError(t, LoadDurabilityPolicies(path))
+	assert.True(t, CheckDurabilityPolicyExists("test_rules_cross_region"))
+	err := LoadDurabilityPolicies(path)
+	assert.EqualError(t, err, path+": durability policy test_rules_cross_region already registered")
+
+	durability, err := GetDurabilityPolicy("test_rules_cross_region")
+	require.NoError(t, err)
+
+	primary := newTestTablet("zone1", 100, topodatapb.TabletType_PRIMARY)
+	sameCellReplica := newTestTablet("zone1", 101, topodatapb.TabletType_REPLICA)
+	sameRegionReplica := newTestTablet("zone2", 102, topodatapb.TabletType_REPLICA)
+	otherRegionReplica := newTestTablet("zone3", 103, topodatapb.TabletType_REPLICA)
+	otherRegionRdonly := newTestTablet("zone3", 104, topodatapb.TabletType_RDONLY)
+	unaliasedCellReplica := newTestTablet("zone4", 105, topodatapb.TabletType_REPLICA)
+
+	assert.Equal(t, promotionrule.Neutral, PromotionRule(durability, primary))
+	assert.Equal(t, promotionrule.Neutral, PromotionRule(durability, sameCellReplica))
+	assert.Equal(t, promotionrule.Prefer, PromotionRule(durability, sameRegionReplica))
+	assert.Equal(t, promotionrule.MustNot, PromotionRule(durability, otherRegionRdonly))
+	assert.Equal(t, 1, SemiSyncAckers(durability, primary))
+
+	assert.False(t, IsReplicaSemiSync(durability, primary, sameCellReplica))
+	assert.False(t, IsReplicaSemiSync(durability, primary, sameRegionReplica))
+	assert.True(t, IsReplicaSemiSync(durability, primary, otherRegionReplica))
+	assert.False(t, IsReplicaSemiSync(durability, primary, otherRegionRdonly))
+	assert.True(t, IsReplicaSemiSync(durability, primary, unaliasedCellReplica))
+	assert.True(t, IsReplicaSemiSync(durability, otherRegionReplica, sameRegionReplica))
+}
+
+func TestParseDurabilityPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "json",
+			data: `{"policies": {"none_but_rdonly": {"promotion_rules": [{"tablet_types": ["RDONLY"], "promotion_rule": "prefer_not"}]}}}`,
+		}, {
+			name:    "unknown field",
+			data:    `{"policies": {"p": {"semi_sync_acks": 1}}}`,
+			wantErr: `error unmarshaling JSON: while decoding JSON: json: unknown field "semi_sync_acks"`,
+		}, {
+			name:    "unknown tablet type",
+			data:    `{"policies": {"p": {"promotion_rules": [{"tablet_types": ["MASTERS"], "promotion_rule": "neutral"}]}}}`,
+			wantErr: "durability policy p: unknown TabletType MASTERS",
+		}, {
+			name:    "invalid promotion rule",
+			data:    `{"policies": {"p": {"promotion_rules": [{"promotion_rule": "must"}]}}}`,
+			wantErr: "durability policy p: CandidatePromotionRule: must not supported yet",
+		}, {
+			name:    "ackers without rules",
+			data:    `{"policies": {"p": {"semi_sync_ackers": 2}}}`,
+			wantErr: "durability policy p: semi_sync_ackers is 2, but no semi_sync_rules select the replicas which ack",
+		}, {
+			name:    "invalid relation",
+			data:    `{"policies": {"p": {"semi_sync_ackers": 1, "semi_sync_rules": [{"primary_cell": "other"}]}}}`,
+			wantErr: `durability policy p: invalid relation to the primary "other", expected "same" or "different"`,
+		}, {
+			name:    "cell in two aliases",
+			data:    `{"cell_aliases": {"a": ["zone1"], "b": ["zone1"]}}`,
+			wantErr: "cell zone1 is in both cell aliases",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDurabilityPolicies([]byte(tt.data))
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -64,6 +64,8 @@ type EmergencyReparentOptions struct {
 	// these details back out.
 	lockAction string
 	durability Durabler
+	// simulation records why tablets are not considered, in dry runs.
+	simulation *ReparentSimulation
 }
 
 // counters for Emergency Reparent Shard
@@ -722,6 +724,7 @@ func (erp *EmergencyReparenter) filterValidCandidates(validTablets []*topodatapb
 		// Remove tablets which have MustNot promote rule since they must never be promoted
 		if PromotionRule(opts.durability, tablet) == promotionrule.MustNot {
 			erp.logger.Infof("Removing %s from list of valid candidates for promotion because it has the Must Not promote rule", tabletAliasStr)
+			opts.simulation.exclude(tablet, "has the MustNot promotion rule")
 			if opts.NewPrimaryAlias != nil && topoproto.TabletAliasEqual(opts.NewPrimaryAlias, tablet.Alias) {
 				return nil, vterrors.Errorf(vtrpc.Code_ABORTED, "proposed primary %s has a must not promotion rule", topoproto.TabletAliasString(opts.NewPrimaryAlias))
 			}
@@ -730,6 +733,7 @@ func (erp *EmergencyReparenter) filterValidCandidates(validTablets []*topodatapb
 		// If ERS is configured to prevent cross cell promotions, remove any tablet not from the same cell as the previous primary
 		if opts.PreventCrossCellPromotion && prevPrimary != nil && tablet.Alias.Cell != prevPrimary.Alias.Cell {
 			erp.logger.Infof("Removing %s from list of valid candidates for promotion because it isn't in the same cell as the previous primary", tabletAliasStr)
+			opts.simulation.exclude(tablet, "not in the cell %v of the previous primary", prevPrimary.Alias.Cell)
 			if opts.NewPrimaryAlias != nil && topoproto.TabletAliasEqual(opts.NewPrimaryAlias, tablet.Alias) {
 				return nil, vterrors.Errorf(vtrpc.Code_ABORTED, "proposed primary %s is is a different cell as the previous primary", topoproto.TabletAliasString(opts.NewPrimaryAlias))
 			}
@@ -738,6 +742,7 @@ func (erp *EmergencyReparenter) filterValidCandidates(validTablets []*topodatapb
 		// Remove any tablet which cannot make forward progress using the list of tablets we have reached
 		if !canEstablishForTablet(opts.durability, tablet, tabletsReachable) {
 			erp.logger.Infof("Removing %s from list of valid candidates for promotion because it will not be able to make forward progress on promotion with the tablets currently reachable", tabletAliasStr)
+			opts.simulation.exclude(tablet, "not enough reachable tablets would send it semi-sync acks")
 			if opts.NewPrimaryAlias != nil && topoproto.TabletAliasEqual(opts.NewPrimaryAlias, tablet.Alias) {
 				return nil, vterrors.Errorf(vtrpc.Code_ABORTED, "proposed primary %s will not be able to make forward progress on being promoted", topoproto.TabletAliasString(opts.NewPrimaryAlias))
 			}
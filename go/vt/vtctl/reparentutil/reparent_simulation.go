@@ -0,0 +1,459 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reparentutil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// ReparentSimulation is the outcome of a reparent dry run. A dry run reads the
+// shard, its tablets and their replication status. It does not lock the shard,
+// and it does not modify anything.
+type ReparentSimulation struct {
+	// NewPrimary is the tablet the reparent would promote. It is nil when the
+	// reparent would have nothing to do.
+	NewPrimary *topodatapb.Tablet
+	// ExcludedTablets are the tablets the reparent would not consider for
+	// promotion, sorted by alias.
+	ExcludedTablets []*ExcludedTablet
+	// Steps are the steps the reparent would take, in order.
+	Steps []string
+
+	mu sync.Mutex
+}
+
+// ExcludedTablet is a tablet a reparent would not consider for promotion.
+type ExcludedTablet struct {
+	Alias  *topodatapb.TabletAlias
+	Reason string
+}
+
+// exclude records why the tablet is not considered for promotion. It is safe
+// to call from multiple goroutines, and on a nil simulation, which is a no-op.
+func (sim *ReparentSimulation) exclude(tablet *topodatapb.Tablet, format string, args ...any) {
+	if sim == nil {
+		return
+	}
+
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+
+	sim.ExcludedTablets = append(sim.ExcludedTablets, &ExcludedTablet{
+		Alias:  tablet.Alias,
+		Reason: fmt.Sprintf(format, args...),
+	})
+}
+
+func (sim *ReparentSimulation) addStep(format string, args ...any) {
+	sim.Steps = append(sim.Steps, fmt.Sprintf(format, args...))
+}
+
+// addReparentReplicasSteps adds the steps to point all the other tablets, but
+// the ignored ones, at the new primary.
+func (sim *ReparentSimulation) addReparentReplicasSteps(tabletMap map[string]*topo.TabletInfo, durability Durabler, ignoredTablets sets.String) {
+	for _, alias := range sortedTabletAliases(tabletMap) {
+		tablet := tabletMap[alias].Tablet
+		if topoproto.TabletAliasEqual(tablet.Alias, sim.NewPrimary.Alias) || ignoredTablets.Has(alias) {
+			continue
+		}
+
+		step := fmt.Sprintf("SetReplicationSource on %v to replicate from %v", alias, topoproto.TabletAliasString(sim.NewPrimary.Alias))
+		if IsReplicaSemiSync(durability, sim.NewPrimary, tablet) {
+			step += ", sending semi-sync acks"
+		}
+		sim.addStep(step)
+	}
+}
+
+func (sim *ReparentSimulation) sortExcludedTablets() {
+	sort.SliceStable(sim.ExcludedTablets, func(i, j int) bool {
+		return topoproto.TabletAliasString(sim.ExcludedTablets[i].Alias) < topoproto.TabletAliasString(sim.ExcludedTablets[j].Alias)
+	})
+}
+
+func sortedTabletAliases(tabletMap map[string]*topo.TabletInfo) []string {
+	aliases := make([]string, 0, len(tabletMap))
+	for alias := range tabletMap {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+func semiSyncDescription(durability Durabler, tablet *topodatapb.Tablet) string {
+	if ackers := SemiSyncAckers(durability, tablet); ackers > 0 {
+		return fmt.Sprintf("with semi-sync, waiting for %d ack(s)", ackers)
+	}
+	return "without semi-sync"
+}
+
+// SimulateReparentShard is the dry run of ReparentShard. It chooses the new
+// primary the same way, from the replication positions of the tablets, and
+// returns the steps ReparentShard would take, without locking the shard or
+// modifying anything.
+func (pr *PlannedReparenter) SimulateReparentShard(ctx context.Context, keyspace string, shard string, opts PlannedReparentOptions) (*ReparentSimulation, error) {
+	shardInfo, err := pr.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+
+	keyspaceDurability, err := pr.ts.GetKeyspaceDurability(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.durability, err = GetDurabilityPolicy(keyspaceDurability)
+	if err != nil {
+		return nil, err
+	}
+
+	tabletMap, err := pr.ts.GetTabletMapForShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.NewPrimaryAlias == nil && opts.AvoidPrimaryAlias == nil {
+		opts.AvoidPrimaryAlias = shardInfo.PrimaryAlias
+	}
+
+	sim := &ReparentSimulation{}
+
+	// Same checks as preflightChecks.
+	if opts.NewPrimaryAlias != nil && topoproto.TabletAliasEqual(opts.NewPrimaryAlias, opts.AvoidPrimaryAlias) {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "primary-elect tablet %v is the same as the tablet to avoid", topoproto.TabletAliasString(opts.NewPrimaryAlias))
+	}
+
+	if opts.NewPrimaryAlias == nil {
+		if shardInfo.PrimaryAlias != nil && !topoproto.TabletAliasEqual(opts.AvoidPrimaryAlias, shardInfo.PrimaryAlias) {
+			sim.addStep("nothing to do: the current primary %v is not the tablet to avoid", topoproto.TabletAliasString(shardInfo.PrimaryAlias))
+			return sim, nil
+		}
+
+		opts.NewPrimaryAlias, err = chooseNewPrimary(ctx, pr.tmc, shardInfo, tabletMap, opts.AvoidPrimaryAlias, opts.WaitReplicasTimeout, opts.durability, pr.logger, sim)
+		sim.sortExcludedTablets()
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.NewPrimaryAlias == nil {
+			return nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "cannot find a tablet to reparent to in the same cell as the current primary")
+		}
+	}
+
+	primaryElectAliasStr := topoproto.TabletAliasString(opts.NewPrimaryAlias)
+
+	newPrimaryTabletInfo, ok := tabletMap[primaryElectAliasStr]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "primary-elect tablet %v is not in the shard", primaryElectAliasStr)
+	}
+
+	sim.NewPrimary = newPrimaryTabletInfo.Tablet
+
+	// The four kinds of promotions of reparentShardLocked.
+	currentPrimary := FindCurrentPrimary(tabletMap, pr.logger)
+	switch {
+	case currentPrimary == nil && shardInfo.PrimaryAlias == nil:
+		sim.addStep("InitPrimary on %v, %v, as no primary has ever been elected in the shard", primaryElectAliasStr, semiSyncDescription(opts.durability, sim.NewPrimary))
+	case currentPrimary == nil && shardInfo.PrimaryAlias != nil:
+		sim.addStep("DemotePrimary on all %d tablets, as there is no clear current primary", len(tabletMap))
+		sim.addStep("check that no tablet has transactions which %v does not have", primaryElectAliasStr)
+		sim.addStep("PromoteReplica on %v, %v", primaryElectAliasStr, semiSyncDescription(opts.durability, sim.NewPrimary))
+	case topoproto.TabletAliasEqual(currentPrimary.Alias, opts.NewPrimaryAlias):
+		sim.addStep("SetReadWrite on %v, which is already the current primary, to recover from a previous partial reparent", primaryElectAliasStr)
+	default:
+		snapshotCtx, snapshotCancel := context.WithTimeout(ctx, *topo.RemoteOperationTimeout)
+		defer snapshotCancel()
+
+		if _, err := pr.tmc.PrimaryPosition(snapshotCtx, currentPrimary.Tablet); err != nil {
+			return nil, vterrors.Wrapf(err, "cannot get replication position on current primary %v; current primary must be healthy to perform PlannedReparent", currentPrimary.AliasString())
+		}
+
+		sim.addStep("SetReplicationSource on %v to replicate from the current primary %v, and wait up to %v for it to catch up", primaryElectAliasStr, currentPrimary.AliasString(), opts.WaitReplicasTimeout)
+		sim.addStep("DemotePrimary on the current primary %v", currentPrimary.AliasString())
+		sim.addStep("wait up to %v for %v to reach the position of the demoted primary, or UndoDemotePrimary on %v", opts.WaitReplicasTimeout, primaryElectAliasStr, currentPrimary.AliasString())
+		sim.addStep("PromoteReplica on %v, %v", primaryElectAliasStr, semiSyncDescription(opts.durability, sim.NewPrimary))
+	}
+
+	sim.addReparentReplicasSteps(tabletMap, opts.durability, nil)
+	sim.addStep("PopulateReparentJournal on %v, and wait up to %v for all the replicas to replicate it", primaryElectAliasStr, opts.WaitReplicasTimeout)
+
+	if currentPrimary == nil && shardInfo.PrimaryAlias == nil {
+		sim.addStep("RefreshState on %v", primaryElectAliasStr)
+	}
+
+	return sim, nil
+}
+
+// SimulateReparentShard is the dry run of ReparentShard. It chooses the new
+// primary the same way, except that it reads the replication status of the
+// tablets without stopping replication, and returns the steps ReparentShard
+// would take, without locking the shard or modifying anything.
+//
+// As replication keeps running, the positions may differ from the positions
+// ReparentShard would see.
+func (erp *EmergencyReparenter) SimulateReparentShard(ctx context.Context, keyspace string, shard string, opts EmergencyReparentOptions) (*ReparentSimulation, error) {
+	shardInfo, err := erp.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+
+	keyspaceDurability, err := erp.ts.GetKeyspaceDurability(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.durability, err = GetDurabilityPolicy(keyspaceDurability)
+	if err != nil {
+		return nil, err
+	}
+
+	var prevPrimary *topodatapb.Tablet
+	if shardInfo.PrimaryAlias != nil {
+		prevPrimaryInfo, err := erp.ts.GetTablet(ctx, shardInfo.PrimaryAlias)
+		if err != nil {
+			return nil, err
+		}
+		prevPrimary = prevPrimaryInfo.Tablet
+	}
+
+	tabletMap, err := erp.ts.GetTabletMapForShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to get tablet map for %v/%v: %v", keyspace, shard, err)
+	}
+
+	sim := &ReparentSimulation{}
+	opts.simulation = sim
+	defer sim.sortExcludedTablets()
+
+	snapshot, err := erp.readReplicationSnapshot(ctx, tabletMap, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Same candidates as reparentShardLocked, recording why the others are not
+	// considered.
+	validCandidates, err := FindValidEmergencyReparentCandidates(snapshot.statusMap, snapshot.primaryStatusMap)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tablet := range snapshot.reachableTablets {
+		if _, ok := validCandidates[topoproto.TabletAliasString(tablet.Alias)]; !ok {
+			sim.exclude(tablet, "has errant GTIDs")
+		}
+	}
+
+	restrictedCandidates, err := restrictValidCandidates(validCandidates, tabletMap)
+	if err != nil {
+		return nil, err
+	}
+
+	for alias := range validCandidates {
+		if _, ok := restrictedCandidates[alias]; !ok {
+			sim.exclude(tabletMap[alias].Tablet, "tablet type %v can not be promoted", tabletMap[alias].Type)
+		}
+	}
+
+	if len(restrictedCandidates) == 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "no valid candidates for emergency reparent")
+	}
+
+	var waitingCandidates []string
+	for alias := range restrictedCandidates {
+		if _, ok := snapshot.statusMap[alias]; ok {
+			waitingCandidates = append(waitingCandidates, alias)
+		}
+	}
+	sort.Strings(waitingCandidates)
+	sim.addStep("wait up to %v for %v to apply their relay logs", opts.WaitReplicasTimeout, strings.Join(waitingCandidates, ", "))
+
+	intermediateSource, validCandidateTablets, err := erp.findMostAdvanced(restrictedCandidates, tabletMap, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	validCandidateTablets, err = erp.filterValidCandidates(validCandidateTablets, snapshot.reachableTablets, prevPrimary, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	isIdeal, err := erp.isIntermediateSourceIdeal(intermediateSource, validCandidateTablets, tabletMap, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	intermediateSourceAliasStr := topoproto.TabletAliasString(intermediateSource.Alias)
+	newPrimary := intermediateSource
+
+	if !isIdeal {
+		sim.addStep("SetReplicationSource on the other tablets to replicate from %v, the most advanced tablet, which is not the best candidate", intermediateSourceAliasStr)
+
+		// Assuming all the valid candidates start replicating from the
+		// intermediate source.
+		betterCandidate, err := erp.identifyPrimaryCandidate(intermediateSource, validCandidateTablets, tabletMap, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if !topoproto.TabletAliasEqual(betterCandidate.Alias, intermediateSource.Alias) {
+			sim.addStep("wait up to %v for %v to catch up with %v", opts.WaitReplicasTimeout, topoproto.TabletAliasString(betterCandidate.Alias), intermediateSourceAliasStr)
+			newPrimary = betterCandidate
+		}
+	}
+
+	sim.NewPrimary = newPrimary
+	newPrimaryAliasStr := topoproto.TabletAliasString(newPrimary.Alias)
+
+	if shardInfo.PrimaryAlias == nil {
+		sim.addStep("InitPrimary on %v, %v, as the shard has no primary", newPrimaryAliasStr, semiSyncDescription(opts.durability, newPrimary))
+	} else {
+		sim.addStep("PromoteReplica on %v, %v", newPrimaryAliasStr, semiSyncDescription(opts.durability, newPrimary))
+	}
+
+	sim.addReparentReplicasSteps(tabletMap, opts.durability, opts.IgnoreReplicas)
+	sim.addStep("PopulateReparentJournal on %v", newPrimaryAliasStr)
+
+	return sim, nil
+}
+
+// readReplicationSnapshot is the read-only counterpart of
+// stopReplicationAndBuildStatusMaps for dry runs. It reads the replication
+// status of the replicas, and the position of the tablets which are not
+// replicating, and adds the steps stopReplicationAndBuildStatusMaps would take.
+func (erp *EmergencyReparenter) readReplicationSnapshot(ctx context.Context, tabletMap map[string]*topo.TabletInfo, opts EmergencyReparentOptions) (*replicationSnapshot, error) {
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		allTablets []*topodatapb.Tablet
+		errs       []error
+		res        = &replicationSnapshot{
+			statusMap:        map[string]*replicationdatapb.StopReplicationStatus{},
+			primaryStatusMap: map[string]*replicationdatapb.PrimaryStatus{},
+			reachableTablets: []*topodatapb.Tablet{},
+		}
+	)
+
+	groupCtx, groupCancel := context.WithTimeout(ctx, opts.WaitReplicasTimeout)
+	defer groupCancel()
+
+	readStatus := func(alias string, tablet *topodatapb.Tablet) {
+		defer wg.Done()
+
+		erp.logger.Infof("getting replication status from %v", alias)
+
+		status, err := erp.tmc.ReplicationStatus(groupCtx, tablet)
+		if err != nil {
+			sqlErr, isSQLErr := mysql.NewSQLErrorFromError(err).(*mysql.SQLError)
+			if !isSQLErr || sqlErr == nil || sqlErr.Number() != mysql.ERNotReplica {
+				opts.simulation.exclude(tablet, "failed to get replication status: %v", err)
+
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, err)
+				return
+			}
+
+			position, err := erp.tmc.PrimaryPosition(groupCtx, tablet)
+			if err != nil {
+				opts.simulation.exclude(tablet, "is not replicating, and failed to get its position: %v", err)
+
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			res.primaryStatusMap[alias] = &replicationdatapb.PrimaryStatus{Position: position}
+			res.reachableTablets = append(res.reachableTablets, tablet)
+			return
+		}
+
+		if mysql.ProtoToReplicationStatus(status).SQLState != mysql.ReplicationStateRunning {
+			opts.simulation.exclude(tablet, "sql thread stopped")
+
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, vterrors.New(vtrpc.Code_FAILED_PRECONDITION, "sql thread stopped on tablet - "+alias))
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		// Replication is not stopped, so the status is the same before and
+		// after.
+		res.statusMap[alias] = &replicationdatapb.StopReplicationStatus{
+			Before: status,
+			After:  status,
+		}
+		res.reachableTablets = append(res.reachableTablets, tablet)
+	}
+
+	for alias, tabletInfo := range tabletMap {
+		allTablets = append(allTablets, tabletInfo.Tablet)
+		if opts.IgnoreReplicas.Has(alias) {
+			opts.simulation.exclude(tabletInfo.Tablet, "ignored by the request")
+			continue
+		}
+
+		wg.Add(1)
+		go readStatus(alias, tabletInfo.Tablet)
+	}
+
+	wg.Wait()
+
+	// Like stopReplicationAndBuildStatusMaps, a single unreachable tablet is
+	// tolerated.
+	if len(errs) > 1 && !haveRevoked(opts.durability, res.reachableTablets, allTablets) {
+		return nil, vterrors.Errorf(vtrpc.Code_UNAVAILABLE, "could not reach sufficient tablets to guarantee safety: %v", errs[0])
+	}
+
+	var stopped, demoted []string
+	for alias := range res.statusMap {
+		stopped = append(stopped, alias)
+	}
+	for alias := range res.primaryStatusMap {
+		demoted = append(demoted, alias)
+	}
+	sort.Strings(stopped)
+	sort.Strings(demoted)
+
+	if len(stopped) > 0 {
+		opts.simulation.addStep("StopReplicationAndGetStatus on %v, stopping the IO thread", strings.Join(stopped, ", "))
+	}
+	if len(demoted) > 0 {
+		opts.simulation.addStep("DemotePrimary on %v, which are not replicating", strings.Join(demoted, ", "))
+	}
+
+	return res, nil
+}
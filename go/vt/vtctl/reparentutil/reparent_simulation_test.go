@@ -0,0 +1,353 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reparentutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver/testutil"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/reparenttestutil"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func newSimulationTestTablet(cell string, uid uint32, tabletType topodatapb.TabletType) *topodatapb.Tablet {
+	return &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: cell,
+			Uid:  uid,
+		},
+		Type:     tabletType,
+		Keyspace: "testkeyspace",
+		Shard:    "-",
+	}
+}
+
+func excludedTabletsByAlias(sim *ReparentSimulation) map[string]string {
+	excluded := make(map[string]string, len(sim.ExcludedTablets))
+	for _, tablet := range sim.ExcludedTablets {
+		excluded[topoproto.TabletAliasString(tablet.Alias)] = tablet.Reason
+	}
+	return excluded
+}
+
+func TestPlannedReparenter_SimulateReparentShard(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		tmc  *testutil.TabletManagerClient
+		opts PlannedReparentOptions
+
+		expectedNewPrimary *topodatapb.TabletAlias
+		expectedExcluded   map[string]string
+		expectedSteps      []string
+		errShouldContain   string
+	}{
+		{
+			name: "graceful promotion of the most advanced replica",
+			tmc: &testutil.TabletManagerClient{
+				PrimaryPositionResults: map[string]struct {
+					Position string
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Position: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-10",
+					},
+				},
+				ReplicationStatusResults: map[string]struct {
+					Position *replicationdatapb.Status
+					Error    error
+				}{
+					"zone1-0000000101": {
+						Position: &replicationdatapb.Status{
+							Position: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-10",
+						},
+					},
+					"zone1-0000000102": {
+						Position: &replicationdatapb.Status{
+							Position: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5",
+						},
+					},
+				},
+			},
+			expectedNewPrimary: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  101,
+			},
+			expectedExcluded: map[string]string{
+				"zone1-0000000100": "tablet to avoid",
+				"zone1-0000000103": "tablet type RDONLY is not REPLICA",
+				"zone2-0000000200": "not in the cell zone1 of the current primary",
+			},
+			expectedSteps: []string{
+				"SetReplicationSource on zone1-0000000101 to replicate from the current primary zone1-0000000100, and wait up to 10s for it to catch up",
+				"DemotePrimary on the current primary zone1-0000000100",
+				"wait up to 10s for zone1-0000000101 to reach the position of the demoted primary, or UndoDemotePrimary on zone1-0000000100",
+				"PromoteReplica on zone1-0000000101, with semi-sync, waiting for 1 ack(s)",
+				"SetReplicationSource on zone1-0000000100 to replicate from zone1-0000000101, sending semi-sync acks",
+				"SetReplicationSource on zone1-0000000102 to replicate from zone1-0000000101, sending semi-sync acks",
+				"SetReplicationSource on zone1-0000000103 to replicate from zone1-0000000101",
+				"SetReplicationSource on zone2-0000000200 to replicate from zone1-0000000101, sending semi-sync acks",
+				"PopulateReparentJournal on zone1-0000000101, and wait up to 10s for all the replicas to replicate it",
+			},
+		},
+		{
+			name: "tablet to avoid is not the primary",
+			tmc:  &testutil.TabletManagerClient{},
+			opts: PlannedReparentOptions{
+				AvoidPrimaryAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  102,
+				},
+			},
+			expectedSteps: []string{
+				"nothing to do: the current primary zone1-0000000100 is not the tablet to avoid",
+			},
+		},
+		{
+			name: "current primary is unreachable",
+			tmc:  &testutil.TabletManagerClient{},
+			opts: PlannedReparentOptions{
+				NewPrimaryAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  102,
+				},
+			},
+			errShouldContain: "current primary must be healthy to perform PlannedReparent",
+		},
+	}
+
+	ctx := context.Background()
+	logger := logutil.NewMemoryLogger()
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := memorytopo.NewServer("zone1", "zone2")
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary: true,
+			},
+				newSimulationTestTablet("zone1", 100, topodatapb.TabletType_PRIMARY),
+				newSimulationTestTablet("zone1", 101, topodatapb.TabletType_REPLICA),
+				newSimulationTestTablet("zone1", 102, topodatapb.TabletType_REPLICA),
+				newSimulationTestTablet("zone1", 103, topodatapb.TabletType_RDONLY),
+				newSimulationTestTablet("zone2", 200, topodatapb.TabletType_REPLICA),
+			)
+			reparenttestutil.SetKeyspaceDurability(ctx, t, ts, "testkeyspace", "semi_sync")
+
+			opts := tt.opts
+			opts.WaitReplicasTimeout = 10 * time.Second
+
+			sim, err := NewPlannedReparenter(ts, tt.tmc, logger).SimulateReparentShard(ctx, "testkeyspace", "-", opts)
+			if tt.errShouldContain != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errShouldContain)
+				return
+			}
+
+			require.NoError(t, err)
+			if tt.expectedNewPrimary == nil {
+				assert.Nil(t, sim.NewPrimary)
+			} else {
+				require.NotNil(t, sim.NewPrimary)
+				assert.Equal(t, topoproto.TabletAliasString(tt.expectedNewPrimary), topoproto.TabletAliasString(sim.NewPrimary.Alias))
+			}
+			if len(tt.expectedExcluded) > 0 {
+				assert.Equal(t, tt.expectedExcluded, excludedTabletsByAlias(sim))
+			} else {
+				assert.Empty(t, sim.ExcludedTablets)
+			}
+			assert.Equal(t, tt.expectedSteps, sim.Steps)
+
+			// The shard is not locked nor reparented.
+			si, err := ts.GetShard(ctx, "testkeyspace", "-")
+			require.NoError(t, err)
+			assert.Equal(t, "zone1-0000000100", topoproto.TabletAliasString(si.PrimaryAlias))
+		})
+	}
+}
+
+func TestEmergencyReparenter_SimulateReparentShard(t *testing.T) {
+	t.Parallel()
+
+	running := int32(mysql.ReplicationStateRunning)
+	replicaStatus := func(relayLogPosition string) struct {
+		Position *replicationdatapb.Status
+		Error    error
+	} {
+		return struct {
+			Position *replicationdatapb.Status
+			Error    error
+		}{
+			Position: &replicationdatapb.Status{
+				Position:         "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-10",
+				RelayLogPosition: relayLogPosition,
+				SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+				IoState:          running,
+				SqlState:         running,
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts EmergencyReparentOptions
+
+		expectedNewPrimary *topodatapb.TabletAlias
+		expectedExcluded   map[string]string
+		expectedSteps      []string
+		errShouldContain   string
+	}{
+		{
+			name: "most advanced tablet is in another cell",
+			opts: EmergencyReparentOptions{
+				IgnoreReplicas:            sets.NewString("zone1-0000000104"),
+				PreventCrossCellPromotion: true,
+			},
+			expectedNewPrimary: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  101,
+			},
+			expectedExcluded: map[string]string{
+				"zone1-0000000100": "failed to get replication status: assert.AnError general error for testing",
+				"zone1-0000000102": "has errant GTIDs",
+				"zone1-0000000103": "tablet type DRAINED can not be promoted",
+				"zone1-0000000104": "ignored by the request",
+				"zone2-0000000200": "not in the cell zone1 of the previous primary",
+			},
+			expectedSteps: []string{
+				"StopReplicationAndGetStatus on zone1-0000000101, zone1-0000000102, zone1-0000000103, zone2-0000000200, stopping the IO thread",
+				"wait up to 10s for zone1-0000000101, zone2-0000000200 to apply their relay logs",
+				"SetReplicationSource on the other tablets to replicate from zone2-0000000200, the most advanced tablet, which is not the best candidate",
+				"wait up to 10s for zone1-0000000101 to catch up with zone2-0000000200",
+				"PromoteReplica on zone1-0000000101, with semi-sync, waiting for 1 ack(s)",
+				"SetReplicationSource on zone1-0000000100 to replicate from zone1-0000000101, sending semi-sync acks",
+				"SetReplicationSource on zone1-0000000102 to replicate from zone1-0000000101, sending semi-sync acks",
+				"SetReplicationSource on zone1-0000000103 to replicate from zone1-0000000101",
+				"SetReplicationSource on zone2-0000000200 to replicate from zone1-0000000101, sending semi-sync acks",
+				"PopulateReparentJournal on zone1-0000000101",
+			},
+		},
+		{
+			name: "most advanced tablet is promoted",
+			opts: EmergencyReparentOptions{
+				IgnoreReplicas: sets.NewString("zone1-0000000104"),
+			},
+			expectedNewPrimary: &topodatapb.TabletAlias{
+				Cell: "zone2",
+				Uid:  200,
+			},
+			expectedExcluded: map[string]string{
+				"zone1-0000000100": "failed to get replication status: assert.AnError general error for testing",
+				"zone1-0000000102": "has errant GTIDs",
+				"zone1-0000000103": "tablet type DRAINED can not be promoted",
+				"zone1-0000000104": "ignored by the request",
+			},
+			expectedSteps: []string{
+				"StopReplicationAndGetStatus on zone1-0000000101, zone1-0000000102, zone1-0000000103, zone2-0000000200, stopping the IO thread",
+				"wait up to 10s for zone1-0000000101, zone2-0000000200 to apply their relay logs",
+				"PromoteReplica on zone2-0000000200, with semi-sync, waiting for 1 ack(s)",
+				"SetReplicationSource on zone1-0000000100 to replicate from zone2-0000000200, sending semi-sync acks",
+				"SetReplicationSource on zone1-0000000101 to replicate from zone2-0000000200, sending semi-sync acks",
+				"SetReplicationSource on zone1-0000000102 to replicate from zone2-0000000200, sending semi-sync acks",
+				"SetReplicationSource on zone1-0000000103 to replicate from zone2-0000000200",
+				"PopulateReparentJournal on zone2-0000000200",
+			},
+		},
+		{
+			name: "requested primary in another cell",
+			opts: EmergencyReparentOptions{
+				NewPrimaryAlias: &topodatapb.TabletAlias{
+					Cell: "zone2",
+					Uid:  200,
+				},
+				IgnoreReplicas:            sets.NewString("zone1-0000000104"),
+				PreventCrossCellPromotion: true,
+			},
+			errShouldContain: "proposed primary zone2-0000000200 is is a different cell as the previous primary",
+		},
+	}
+
+	ctx := context.Background()
+	logger := logutil.NewMemoryLogger()
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := memorytopo.NewServer("zone1", "zone2")
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary: true,
+			},
+				newSimulationTestTablet("zone1", 100, topodatapb.TabletType_PRIMARY),
+				newSimulationTestTablet("zone1", 101, topodatapb.TabletType_REPLICA),
+				newSimulationTestTablet("zone1", 102, topodatapb.TabletType_REPLICA),
+				newSimulationTestTablet("zone1", 103, topodatapb.TabletType_DRAINED),
+				newSimulationTestTablet("zone1", 104, topodatapb.TabletType_REPLICA),
+				newSimulationTestTablet("zone2", 200, topodatapb.TabletType_REPLICA),
+			)
+			reparenttestutil.SetKeyspaceDurability(ctx, t, ts, "testkeyspace", "semi_sync")
+
+			// zone1-0000000100 is the dead primary, zone1-0000000102 has an
+			// errant GTID, and zone2-0000000200 is the most advanced.
+			tmc := &testutil.TabletManagerClient{
+				ReplicationStatusResults: map[string]struct {
+					Position *replicationdatapb.Status
+					Error    error
+				}{
+					"zone1-0000000101": replicaStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-20"),
+					"zone1-0000000102": replicaStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-20,AAAAAAAA-71CA-11E1-9E33-C80AA9429562:1"),
+					"zone1-0000000103": replicaStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-20"),
+					"zone2-0000000200": replicaStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21"),
+				},
+			}
+
+			opts := tt.opts
+			opts.WaitReplicasTimeout = 10 * time.Second
+
+			sim, err := NewEmergencyReparenter(ts, tmc, logger).SimulateReparentShard(ctx, "testkeyspace", "-", opts)
+			if tt.errShouldContain != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errShouldContain)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, sim.NewPrimary)
+			assert.Equal(t, topoproto.TabletAliasString(tt.expectedNewPrimary), topoproto.TabletAliasString(sim.NewPrimary.Alias))
+			assert.Equal(t, tt.expectedExcluded, excludedTabletsByAlias(sim))
+			assert.Equal(t, tt.expectedSteps, sim.Steps)
+		})
+	}
+}
@@ -59,7 +59,22 @@ func ChooseNewPrimary(
 	// (TODO:@ajm188) it's a little gross we need to pass this, maybe embed in the context?
 	logger logutil.Logger,
 ) (*topodatapb.TabletAlias, error) {
+	return chooseNewPrimary(ctx, tmc, shardInfo, tabletMap, avoidPrimaryAlias, waitReplicasTimeout, durability, logger, nil)
+}
 
+// chooseNewPrimary implements ChooseNewPrimary. When sim is not nil, it records
+// in sim why the tablets are not considered.
+func chooseNewPrimary(
+	ctx context.Context,
+	tmc tmclient.TabletManagerClient,
+	shardInfo *topo.ShardInfo,
+	tabletMap map[string]*topo.TabletInfo,
+	avoidPrimaryAlias *topodatapb.TabletAlias,
+	waitReplicasTimeout time.Duration,
+	durability Durabler,
+	logger logutil.Logger,
+	sim *ReparentSimulation,
+) (*topodatapb.TabletAlias, error) {
 	var primaryCell string
 	if shardInfo.PrimaryAlias != nil {
 		primaryCell = shardInfo.PrimaryAlias.Cell
@@ -77,10 +92,13 @@ func ChooseNewPrimary(
 	for _, tablet := range tabletMap {
 		switch {
 		case primaryCell != "" && tablet.Alias.Cell != primaryCell:
+			sim.exclude(tablet.Tablet, "not in the cell %v of the current primary", primaryCell)
 			continue
 		case avoidPrimaryAlias != nil && topoproto.TabletAliasEqual(tablet.Alias, avoidPrimaryAlias):
+			sim.exclude(tablet.Tablet, "tablet to avoid")
 			continue
 		case tablet.Tablet.Type != topodatapb.TabletType_REPLICA:
+			sim.exclude(tablet.Tablet, "tablet type %v is not REPLICA", tablet.Tablet.Type)
 			continue
 		}
 
@@ -95,6 +113,8 @@ func ChooseNewPrimary(
 			if err == nil {
 				validTablets = append(validTablets, tablet)
 				tabletPositions = append(tabletPositions, pos)
+			} else {
+				sim.exclude(tablet, "failed to get replication status: %v", err)
 			}
 		}(tablet.Tablet)
 	}
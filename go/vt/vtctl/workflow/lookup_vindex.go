@@ -0,0 +1,591 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/schematools"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// lookupVindexWorkflowSuffix is appended to the name of the lookup table to
+// name the workflow that backfills it.
+const lookupVindexWorkflowSuffix = "_vdx"
+
+// LookupVindexCreate adds a write_only lookup vindex to the VSchema, creates
+// its lookup table, and starts a VReplication workflow in the keyspace of the
+// lookup table that backfills it from the existing rows.
+func (s *Server) LookupVindexCreate(ctx context.Context, req *vtctldatapb.LookupVindexCreateRequest) (*vtctldatapb.LookupVindexCreateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.LookupVindexCreate")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("cells", strings.Join(req.Cells, ","))
+	span.Annotate("continue_after_copy_with_owner", req.ContinueAfterCopyWithOwner)
+
+	if req.Vindex == nil {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "vindex specification is required")
+	}
+
+	ms, err := CreateLookupVindex(ctx, s.ts, s.tmc, req.Keyspace, req.Vindex, strings.Join(req.Cells, ","), strings.Join(topoproto.MakeStringTypeList(req.TabletTypes), ","), req.ContinueAfterCopyWithOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.LookupVindexCreateResponse{
+		Workflow:       ms.Workflow,
+		TargetKeyspace: ms.TargetKeyspace,
+	}, nil
+}
+
+// CreateLookupVindex adds a write_only lookup vindex to the VSchema of the
+// keyspace, creates its lookup table, and starts the workflow that backfills
+// it. The VSchema of the keyspace of the lookup table is restored if the
+// workflow cannot be created. It returns the settings of the workflow.
+func CreateLookupVindex(ctx context.Context, ts *topo.Server, tmc tmclient.TabletManagerClient, keyspace string, specs *vschemapb.Keyspace, cell, tabletTypes string, continueAfterCopyWithOwner bool) (*vtctldatapb.MaterializeSettings, error) {
+	// PrepareCreateLookup modifies the vindex params, so work on a copy.
+	specs = proto.Clone(specs).(*vschemapb.Keyspace)
+	ms, sourceVSchema, targetVSchema, err := PrepareCreateLookup(ctx, ts, tmc, keyspace, specs, continueAfterCopyWithOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	// The lookup table has to be in the target VSchema before the workflow
+	// is created, so keep the original to restore it if that fails.
+	origTargetVSchema, err := ts.GetVSchema(ctx, ms.TargetKeyspace)
+	if err != nil {
+		return nil, err
+	}
+	if err := ts.SaveVSchema(ctx, ms.TargetKeyspace, targetVSchema); err != nil {
+		return nil, err
+	}
+	ms.Cell = cell
+	ms.TabletTypes = tabletTypes
+	if err := materialize(ctx, ts, tmc, ms); err != nil {
+		if rerr := ts.SaveVSchema(ctx, ms.TargetKeyspace, origTargetVSchema); rerr != nil {
+			log.Errorf("failed to restore the vschema of keyspace %s after failing to create lookup vindex workflow %s: %v", ms.TargetKeyspace, ms.Workflow, rerr)
+		}
+		return nil, err
+	}
+	if err := ts.SaveVSchema(ctx, keyspace, sourceVSchema); err != nil {
+		return nil, err
+	}
+	if err := ts.RebuildSrvVSchema(ctx, nil); err != nil {
+		return nil, err
+	}
+
+	return ms, nil
+}
+
+// LookupVindexShow returns a lookup vindex from the VSchema of the given
+// keyspace, along with the workflow backfilling it, if there is one.
+func (s *Server) LookupVindexShow(ctx context.Context, req *vtctldatapb.LookupVindexShowRequest) (*vtctldatapb.LookupVindexShowResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.LookupVindexShow")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("vindex", req.Name)
+
+	_, vindex, targetKeyspace, workflowName, err := s.getLookupVindex(ctx, req.Keyspace, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	wf, err := s.getWorkflow(ctx, targetKeyspace, workflowName)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &vtctldatapb.LookupVindexShowResponse{
+		Vindex:         vindex,
+		TargetKeyspace: targetKeyspace,
+		Workflow:       wf,
+		Externalized:   vindex.Params["write_only"] != "true",
+	}
+	resp.ReadyToExternalize = !resp.Externalized && wf != nil && checkLookupVindexStreams(vindex, wf) == nil
+
+	return resp, nil
+}
+
+// LookupVindexExternalize makes a lookup vindex created by LookupVindexCreate
+// usable for queries, once its backfill workflow has finished copying (for
+// owned vindexes) or is running and caught up (for unowned vindexes). The
+// workflow of an owned vindex is deleted, since vtgate maintains the lookup
+// table from then on.
+func (s *Server) LookupVindexExternalize(ctx context.Context, req *vtctldatapb.LookupVindexExternalizeRequest) (*vtctldatapb.LookupVindexExternalizeResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.LookupVindexExternalize")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("vindex", req.Name)
+
+	sourceVSchema, vindex, targetKeyspace, workflowName, err := s.getLookupVindex(ctx, req.Keyspace, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	wf, err := s.getWorkflow(ctx, targetKeyspace, workflowName)
+	if err != nil {
+		return nil, err
+	}
+	if wf == nil {
+		return nil, vterrors.Errorf(vtrpc.Code_NOT_FOUND, "workflow %s not found in keyspace %s", workflowName, targetKeyspace)
+	}
+	if err := checkLookupVindexStreams(vindex, wf); err != nil {
+		return nil, vterrors.Wrapf(err, "cannot externalize vindex %s.%s", req.Keyspace, req.Name)
+	}
+
+	resp := &vtctldatapb.LookupVindexExternalizeResponse{}
+	if vindex.Owner != "" {
+		// If there is an owner, we have to delete the streams.
+		targetShards, err := s.ts.GetServingShards(ctx, targetKeyspace)
+		if err != nil {
+			return nil, err
+		}
+		err = forAllShards(targetShards, func(targetShard *topo.ShardInfo) error {
+			targetPrimary, err := s.ts.GetTablet(ctx, targetShard.PrimaryAlias)
+			if err != nil {
+				return err
+			}
+			query := fmt.Sprintf("delete from _vt.vreplication where db_name=%s and workflow=%s", encodeString(targetPrimary.DbName()), encodeString(workflowName))
+			_, err = s.tmc.VReplicationExec(ctx, targetPrimary.Tablet, query)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		resp.WorkflowDeleted = true
+	}
+
+	// Remove the write_only param and save the source vschema.
+	delete(vindex.Params, "write_only")
+	if err := s.ts.SaveVSchema(ctx, req.Keyspace, sourceVSchema); err != nil {
+		return nil, err
+	}
+	if err := s.ts.RebuildSrvVSchema(ctx, nil); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// getLookupVindex returns the VSchema of the keyspace along with the named
+// lookup vindex in it, the keyspace of its lookup table, and the name of the
+// workflow that backfills that table.
+func (s *Server) getLookupVindex(ctx context.Context, keyspace, name string) (*vschemapb.Keyspace, *vschemapb.Vindex, string, string, error) {
+	if keyspace == "" || name == "" {
+		return nil, nil, "", "", vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "keyspace and vindex name are required")
+	}
+
+	vschema, err := s.ts.GetVSchema(ctx, keyspace)
+	if err != nil {
+		return nil, nil, "", "", err
+	}
+	vindex := vschema.Vindexes[name]
+	if vindex == nil {
+		return nil, nil, "", "", vterrors.Errorf(vtrpc.Code_NOT_FOUND, "vindex %s not found in vschema of keyspace %s", name, keyspace)
+	}
+
+	targetKeyspace, targetTableName, err := sqlparser.ParseTable(vindex.Params["table"])
+	if err != nil || targetKeyspace == "" {
+		return nil, nil, "", "", vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "vindex table name must be in the form <keyspace>.<table>. Got: %v", vindex.Params["table"])
+	}
+
+	return vschema, vindex, targetKeyspace, targetTableName + lookupVindexWorkflowSuffix, nil
+}
+
+// getWorkflow returns the named workflow in the keyspace, or nil if there is
+// no such workflow.
+func (s *Server) getWorkflow(ctx context.Context, keyspace, name string) (*vtctldatapb.Workflow, error) {
+	resp, err := s.GetWorkflows(ctx, &vtctldatapb.GetWorkflowsRequest{Keyspace: keyspace})
+	if err != nil {
+		return nil, err
+	}
+	for _, wf := range resp.Workflows {
+		if wf.Name == name {
+			return wf, nil
+		}
+	}
+	return nil, nil
+}
+
+// lookupVindexMaxLag is the maximum replication lag of the running streams of
+// a lookup vindex backfill for the vindex to be externalized.
+const lookupVindexMaxLag = 10 * time.Second
+
+// checkLookupVindexStreams returns an error unless every stream of the
+// workflow backfilling the vindex is done with its copy phase: owned vindexes
+// whose backfill stops after copying need all streams stopped after copy,
+// all others need all streams running and caught up, that is done copying and
+// lagging by at most lookupVindexMaxLag.
+func checkLookupVindexStreams(vindex *vschemapb.Vindex, wf *vtctldatapb.Workflow) error {
+	numStreams := 0
+	for _, shardStreams := range wf.ShardStreams {
+		for _, stream := range shardStreams.Streams {
+			numStreams++
+			if vindex.Owner == "" || !stream.BinlogSource.StopAfterCopy {
+				// If there's no owner or we've requested that the workflow NOT be stopped
+				// after the copy phase completes, then all streams need to be running.
+				if stream.State != binlogplayer.BlpRunning {
+					return fmt.Errorf("stream %d for %v.%v is not in Running state: %v", stream.Id, wf.Target.Keyspace, stream.Shard, stream.State)
+				}
+				if len(stream.CopyStates) > 0 {
+					return fmt.Errorf("stream %d for %v.%v is still copying %d table(s)", stream.Id, wf.Target.Keyspace, stream.Shard, len(stream.CopyStates))
+				}
+				if lag := time.Since(logutil.ProtoToTime(stream.TimeUpdated)); lag > lookupVindexMaxLag {
+					return fmt.Errorf("stream %d for %v.%v is lagging by %v, more than %v", stream.Id, wf.Target.Keyspace, stream.Shard, lag.Round(time.Second), lookupVindexMaxLag)
+				}
+			} else {
+				// If there is an owner, all streams need to be stopped after copy.
+				if stream.State != binlogplayer.BlpStopped || !strings.Contains(stream.Message, "Stopped after copy") {
+					return fmt.Errorf("stream %d for %v.%v is not in Stopped after copy state: %v, %v", stream.Id, wf.Target.Keyspace, stream.Shard, stream.State, stream.Message)
+				}
+			}
+		}
+	}
+	if numStreams == 0 {
+		return fmt.Errorf("workflow %s has no streams", wf.Name)
+	}
+	return nil
+}
+
+// PrepareCreateLookup validates the lookup vindex specs against the VSchemas
+// and schemas of the keyspaces involved. It returns the settings of the
+// workflow that backfills the lookup table, along with the source and target
+// VSchemas updated with the vindex and its lookup table.
+func PrepareCreateLookup(ctx context.Context, ts *topo.Server, tmc tmclient.TabletManagerClient, keyspace string, specs *vschemapb.Keyspace, continueAfterCopyWithOwner bool) (ms *vtctldatapb.MaterializeSettings, sourceVSchema, targetVSchema *vschemapb.Keyspace, err error) {
+	// Important variables are pulled out here.
+	var (
+		// lookup vindex info
+		vindexName      string
+		vindex          *vschemapb.Vindex
+		targetKeyspace  string
+		targetTableName string
+		vindexFromCols  []string
+		vindexToCol     string
+
+		// source table info
+		sourceTableName string
+		// sourceTable is the supplied table info
+		sourceTable *vschemapb.Table
+		// sourceVSchemaTable is the table info present in the vschema
+		sourceVSchemaTable *vschemapb.Table
+		// sourceVindexColumns are computed from the input sourceTable
+		sourceVindexColumns []string
+
+		// target table info
+		createDDL        string
+		materializeQuery string
+	)
+
+	// Validate input vindex
+	if len(specs.Vindexes) != 1 {
+		return nil, nil, nil, fmt.Errorf("only one vindex must be specified in the specs: %v", specs.Vindexes)
+	}
+	for name, vi := range specs.Vindexes {
+		vindexName = name
+		vindex = vi
+	}
+	if !strings.Contains(vindex.Type, "lookup") {
+		return nil, nil, nil, fmt.Errorf("vindex %s is not a lookup type", vindex.Type)
+	}
+
+	targetKeyspace, targetTableName, err = sqlparser.ParseTable(vindex.Params["table"])
+	if err != nil || targetKeyspace == "" {
+		return nil, nil, nil, fmt.Errorf("vindex table name must be in the form <keyspace>.<table>. Got: %v", vindex.Params["table"])
+	}
+
+	vindexFromCols = strings.Split(vindex.Params["from"], ",")
+	if strings.Contains(vindex.Type, "unique") {
+		if len(vindexFromCols) != 1 {
+			return nil, nil, nil, fmt.Errorf("unique vindex 'from' should have only one column: %v", vindex)
+		}
+	} else {
+		if len(vindexFromCols) < 2 {
+			return nil, nil, nil, fmt.Errorf("non-unique vindex 'from' should have more than one column: %v", vindex)
+		}
+	}
+	vindexToCol = vindex.Params["to"]
+	// Make the vindex write_only. If one exists already in the vschema,
+	// it will need to match this vindex exactly, including the write_only setting.
+	vindex.Params["write_only"] = "true"
+	// See if we can create the vindex without errors.
+	if _, err := vindexes.CreateVindex(vindex.Type, vindexName, vindex.Params); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Validate input table
+	if len(specs.Tables) != 1 {
+		return nil, nil, nil, fmt.Errorf("exactly one table must be specified in the specs: %v", specs.Tables)
+	}
+	// Loop executes once.
+	for k, ti := range specs.Tables {
+		if len(ti.ColumnVindexes) != 1 {
+			return nil, nil, nil, fmt.Errorf("exactly one ColumnVindex must be specified for the table: %v", specs.Tables)
+		}
+		sourceTableName = k
+		sourceTable = ti
+	}
+
+	// Validate input table and vindex consistency
+	if sourceTable.ColumnVindexes[0].Name != vindexName {
+		return nil, nil, nil, fmt.Errorf("ColumnVindex name must match vindex name: %s vs %s", sourceTable.ColumnVindexes[0].Name, vindexName)
+	}
+	if vindex.Owner != "" && vindex.Owner != sourceTableName {
+		return nil, nil, nil, fmt.Errorf("vindex owner must match table name: %v vs %v", vindex.Owner, sourceTableName)
+	}
+	if len(sourceTable.ColumnVindexes[0].Columns) != 0 {
+		sourceVindexColumns = sourceTable.ColumnVindexes[0].Columns
+	} else {
+		if sourceTable.ColumnVindexes[0].Column == "" {
+			return nil, nil, nil, fmt.Errorf("at least one column must be specified in ColumnVindexes: %v", sourceTable.ColumnVindexes)
+		}
+		sourceVindexColumns = []string{sourceTable.ColumnVindexes[0].Column}
+	}
+	if len(sourceVindexColumns) != len(vindexFromCols) {
+		return nil, nil, nil, fmt.Errorf("length of table columns differes from length of vindex columns: %v vs %v", sourceVindexColumns, vindexFromCols)
+	}
+
+	// Validate against source vschema
+	sourceVSchema, err = ts.GetVSchema(ctx, keyspace)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if sourceVSchema.Vindexes == nil {
+		sourceVSchema.Vindexes = make(map[string]*vschemapb.Vindex)
+	}
+	if sourceVSchema.Tables == nil {
+		sourceVSchema.Tables = make(map[string]*vschemapb.Table)
+	}
+	// If source and target keyspaces are same, Make vschemas point to the same object.
+	if keyspace == targetKeyspace {
+		targetVSchema = sourceVSchema
+	} else {
+		targetVSchema, err = ts.GetVSchema(ctx, targetKeyspace)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if targetVSchema.Vindexes == nil {
+		targetVSchema.Vindexes = make(map[string]*vschemapb.Vindex)
+	}
+	if targetVSchema.Tables == nil {
+		targetVSchema.Tables = make(map[string]*vschemapb.Table)
+	}
+	if existing, ok := sourceVSchema.Vindexes[vindexName]; ok {
+		if !proto.Equal(existing, vindex) {
+			return nil, nil, nil, fmt.Errorf("a conflicting vindex named %s already exists in the source vschema", vindexName)
+		}
+	}
+	sourceVSchemaTable = sourceVSchema.Tables[sourceTableName]
+	if sourceVSchemaTable == nil {
+		if !schema.IsInternalOperationTableName(sourceTableName) {
+			return nil, nil, nil, fmt.Errorf("source table %s not found in vschema", sourceTableName)
+		}
+		sourceVSchemaTable = &vschemapb.Table{}
+		sourceVSchema.Tables[sourceTableName] = sourceVSchemaTable
+	}
+	for _, colVindex := range sourceVSchemaTable.ColumnVindexes {
+		// For a conflict, the vindex name and column should match.
+		if colVindex.Name != vindexName {
+			continue
+		}
+		colName := colVindex.Column
+		if len(colVindex.Columns) != 0 {
+			colName = colVindex.Columns[0]
+		}
+		if colName == sourceVindexColumns[0] {
+			return nil, nil, nil, fmt.Errorf("ColumnVindex for table %v already exists: %v, please remove it and try again", sourceTableName, colName)
+		}
+	}
+
+	// Validate against source schema
+	sourceShards, err := ts.GetServingShards(ctx, keyspace)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	onesource := sourceShards[0]
+	if onesource.PrimaryAlias == nil {
+		return nil, nil, nil, fmt.Errorf("source shard has no primary: %v", onesource.ShardName())
+	}
+	req := &tabletmanagerdatapb.GetSchemaRequest{Tables: []string{sourceTableName}}
+	tableSchema, err := schematools.GetSchema(ctx, ts, tmc, onesource.PrimaryAlias, req)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(tableSchema.TableDefinitions) != 1 {
+		return nil, nil, nil, fmt.Errorf("unexpected number of tables returned from schema: %v", tableSchema.TableDefinitions)
+	}
+
+	// Generate "create table" statement
+	lines := strings.Split(tableSchema.TableDefinitions[0].Schema, "\n")
+	if len(lines) < 3 {
+		// Unreachable
+		return nil, nil, nil, fmt.Errorf("schema looks incorrect: %s, expecting at least four lines", tableSchema.TableDefinitions[0].Schema)
+	}
+	var modified []string
+	modified = append(modified, strings.Replace(lines[0], sourceTableName, targetTableName, 1))
+	for i := range sourceVindexColumns {
+		line, err := generateColDef(lines, sourceVindexColumns[i], vindexFromCols[i])
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		modified = append(modified, line)
+	}
+
+	if vindex.Params["data_type"] == "" || strings.EqualFold(vindex.Type, "consistent_lookup_unique") || strings.EqualFold(vindex.Type, "consistent_lookup") {
+		modified = append(modified, fmt.Sprintf("  %s varbinary(128),", sqlescape.EscapeID(vindexToCol)))
+	} else {
+		modified = append(modified, fmt.Sprintf("  %s %s,", sqlescape.EscapeID(vindexToCol), sqlescape.EscapeID(vindex.Params["data_type"])))
+	}
+	buf := sqlparser.NewTrackedBuffer(nil)
+	fmt.Fprintf(buf, "  PRIMARY KEY (")
+	prefix := ""
+	for _, col := range vindexFromCols {
+		fmt.Fprintf(buf, "%s%s", prefix, sqlescape.EscapeID(col))
+		prefix = ", "
+	}
+	fmt.Fprintf(buf, ")")
+	modified = append(modified, buf.String())
+	modified = append(modified, ")")
+	createDDL = strings.Join(modified, "\n")
+
+	// Generate vreplication query
+	buf = sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("select ")
+	for i := range vindexFromCols {
+		buf.Myprintf("%v as %v, ", sqlparser.NewIdentifierCI(sourceVindexColumns[i]), sqlparser.NewIdentifierCI(vindexFromCols[i]))
+	}
+	if strings.EqualFold(vindexToCol, "keyspace_id") || strings.EqualFold(vindex.Type, "consistent_lookup_unique") || strings.EqualFold(vindex.Type, "consistent_lookup") {
+		buf.Myprintf("keyspace_id() as %v ", sqlparser.NewIdentifierCI(vindexToCol))
+	} else {
+		buf.Myprintf("%v as %v ", sqlparser.NewIdentifierCI(vindexToCol), sqlparser.NewIdentifierCI(vindexToCol))
+	}
+	buf.Myprintf("from %v", sqlparser.NewIdentifierCS(sourceTableName))
+	if vindex.Owner != "" {
+		// Only backfill
+		buf.Myprintf(" group by ")
+		for i := range vindexFromCols {
+			buf.Myprintf("%v, ", sqlparser.NewIdentifierCI(vindexFromCols[i]))
+		}
+		buf.Myprintf("%v", sqlparser.NewIdentifierCI(vindexToCol))
+	}
+	materializeQuery = buf.String()
+
+	// Update targetVSchema
+	var targetTable *vschemapb.Table
+	if targetVSchema.Sharded {
+		// Choose a primary vindex type for target table based on source specs
+		var targetVindexType string
+		var targetVindex *vschemapb.Vindex
+		for _, field := range tableSchema.TableDefinitions[0].Fields {
+			if sourceVindexColumns[0] == field.Name {
+				targetVindexType, err = vindexes.ChooseVindexForType(field.Type)
+				if err != nil {
+					return nil, nil, nil, err
+				}
+				targetVindex = &vschemapb.Vindex{
+					Type: targetVindexType,
+				}
+				break
+			}
+		}
+		if targetVindex == nil {
+			// Unreachable. We validated column names when generating the DDL.
+			return nil, nil, nil, fmt.Errorf("column %s not found in schema %v", sourceVindexColumns[0], tableSchema.TableDefinitions[0])
+		}
+		if existing, ok := targetVSchema.Vindexes[targetVindexType]; ok {
+			if !proto.Equal(existing, targetVindex) {
+				return nil, nil, nil, fmt.Errorf("a conflicting vindex named %v already exists in the target vschema", targetVindexType)
+			}
+		} else {
+			targetVSchema.Vindexes[targetVindexType] = targetVindex
+		}
+
+		targetTable = &vschemapb.Table{
+			ColumnVindexes: []*vschemapb.ColumnVindex{{
+				Column: vindexFromCols[0],
+				Name:   targetVindexType,
+			}},
+		}
+	} else {
+		targetTable = &vschemapb.Table{}
+	}
+	if existing, ok := targetVSchema.Tables[targetTableName]; ok {
+		if !proto.Equal(existing, targetTable) {
+			return nil, nil, nil, fmt.Errorf("a conflicting table named %v already exists in the target vschema", targetTableName)
+		}
+	} else {
+		targetVSchema.Tables[targetTableName] = targetTable
+	}
+
+	ms = &vtctldatapb.MaterializeSettings{
+		Workflow:              targetTableName + lookupVindexWorkflowSuffix,
+		MaterializationIntent: vtctldatapb.MaterializationIntent_CREATELOOKUPINDEX,
+		SourceKeyspace:        keyspace,
+		TargetKeyspace:        targetKeyspace,
+		StopAfterCopy:         vindex.Owner != "" && !continueAfterCopyWithOwner,
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{{
+			TargetTable:      targetTableName,
+			SourceExpression: materializeQuery,
+			CreateDdl:        createDDL,
+		}},
+	}
+
+	// Update sourceVSchema
+	sourceVSchema.Vindexes[vindexName] = vindex
+	sourceVSchemaTable.ColumnVindexes = append(sourceVSchemaTable.ColumnVindexes, sourceTable.ColumnVindexes[0])
+
+	return ms, sourceVSchema, targetVSchema, nil
+}
+
+func generateColDef(lines []string, sourceVindexCol, vindexFromCol string) (string, error) {
+	source := sqlescape.EscapeID(sourceVindexCol)
+	target := sqlescape.EscapeID(vindexFromCol)
+
+	for _, line := range lines[1:] {
+		if strings.Contains(line, source) {
+			line = strings.Replace(line, source, target, 1)
+			line = strings.Replace(line, " AUTO_INCREMENT", "", 1)
+			line = strings.Replace(line, " DEFAULT NULL", "", 1)
+			return line, nil
+		}
+	}
+	return "", fmt.Errorf("column %s not found in schema %v", sourceVindexCol, lines)
+}
@@ -0,0 +1,368 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver/testutil"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// fakeLookupTMC answers GetSchema from a per-tablet schema map, and
+// VReplicationExec with the result of the first registered query fragment
+// the query contains, or an empty result. It records the schema changes and
+// vreplication queries it receives.
+type fakeLookupTMC struct {
+	tmclient.TabletManagerClient
+
+	schemas     map[string]*tabletmanagerdatapb.SchemaDefinition
+	vrepResults map[string]*querypb.QueryResult
+
+	mu          sync.Mutex
+	schemaDDLs  []string
+	vrepQueries []string
+}
+
+func (fake *fakeLookupTMC) GetSchema(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetSchemaRequest) (*tabletmanagerdatapb.SchemaDefinition, error) {
+	sd, ok := fake.schemas[topoproto.TabletAliasString(tablet.Alias)]
+	if !ok {
+		return &tabletmanagerdatapb.SchemaDefinition{}, nil
+	}
+	return sd, nil
+}
+
+func (fake *fakeLookupTMC) ApplySchema(ctx context.Context, tablet *topodatapb.Tablet, change *tmutils.SchemaChange) (*tabletmanagerdatapb.SchemaChangeResult, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.schemaDDLs = append(fake.schemaDDLs, change.SQL)
+	return &tabletmanagerdatapb.SchemaChangeResult{}, nil
+}
+
+func (fake *fakeLookupTMC) VReplicationExec(ctx context.Context, tablet *topodatapb.Tablet, query string) (*querypb.QueryResult, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.vrepQueries = append(fake.vrepQueries, query)
+	for fragment, qr := range fake.vrepResults {
+		if strings.Contains(query, fragment) {
+			return qr, nil
+		}
+	}
+	return &querypb.QueryResult{}, nil
+}
+
+func (fake *fakeLookupTMC) queriesContaining(fragment string) []string {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	var queries []string
+	for _, query := range fake.vrepQueries {
+		if strings.Contains(query, fragment) {
+			queries = append(queries, query)
+		}
+	}
+	return queries
+}
+
+var (
+	lookupSourcePrimary = &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		Keyspace: "sourceks",
+		Shard:    "0",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}
+	lookupTargetPrimary = &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+		Keyspace: "targetks",
+		Shard:    "0",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}
+)
+
+func newLookupVindexTestServer(ctx context.Context, t *testing.T, tmc *fakeLookupTMC, sourceVSchema *vschemapb.Keyspace) *Server {
+	t.Helper()
+
+	ts := memorytopo.NewServer("zone1")
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{AlsoSetShardPrimary: true}, lookupSourcePrimary, lookupTargetPrimary)
+	require.NoError(t, ts.SaveVSchema(ctx, "sourceks", sourceVSchema))
+	require.NoError(t, ts.SaveVSchema(ctx, "targetks", &vschemapb.Keyspace{}))
+
+	return NewServer(ts, tmc)
+}
+
+func TestLookupVindexCreate(t *testing.T) {
+	ctx := context.Background()
+	tmc := &fakeLookupTMC{
+		schemas: map[string]*tabletmanagerdatapb.SchemaDefinition{
+			"zone1-0000000100": {
+				TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+					Name:   "t1",
+					Schema: "CREATE TABLE `t1` (\n  `id` bigint NOT NULL AUTO_INCREMENT,\n  `c1` int DEFAULT NULL,\n  PRIMARY KEY (`id`)\n)",
+					Fields: sqltypes.MakeTestFields("id|c1", "int64|int32"),
+				}},
+			},
+		},
+	}
+	vschema := &vschemapb.Keyspace{
+		Sharded:  true,
+		Vindexes: map[string]*vschemapb.Vindex{"hash": {Type: "hash"}},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}}},
+		},
+	}
+	ws := newLookupVindexTestServer(ctx, t, tmc, vschema)
+
+	specs := &vschemapb.Keyspace{
+		Vindexes: map[string]*vschemapb.Vindex{
+			"v": {
+				Type: "lookup_unique",
+				Params: map[string]string{
+					"table": "targetks.lkp",
+					"from":  "c1",
+					"to":    "keyspace_id",
+				},
+				Owner: "t1",
+			},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "c1", Name: "v"}},
+			},
+		},
+	}
+	resp, err := ws.LookupVindexCreate(ctx, &vtctldatapb.LookupVindexCreateRequest{
+		Keyspace:    "sourceks",
+		Vindex:      specs,
+		TabletTypes: []topodatapb.TabletType{topodatapb.TabletType_REPLICA},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &vtctldatapb.LookupVindexCreateResponse{Workflow: "lkp_vdx", TargetKeyspace: "targetks"}, resp)
+
+	// The request itself is left untouched.
+	assert.NotContains(t, specs.Vindexes["v"].Params, "write_only")
+
+	assert.Equal(t, []string{"CREATE TABLE `lkp` (\n  `c1` int,\n  `keyspace_id` varbinary(128),\n  PRIMARY KEY (`c1`)\n)"}, tmc.schemaDDLs)
+
+	inserts := tmc.queriesContaining("insert into _vt.vreplication")
+	require.Len(t, inserts, 1)
+	assert.Contains(t, inserts[0], "'lkp_vdx'")
+	assert.Contains(t, inserts[0], "select c1 as c1, keyspace_id() as keyspace_id from t1 group by c1, keyspace_id")
+	assert.Contains(t, inserts[0], "stop_after_copy:true")
+	assert.Contains(t, inserts[0], "'replica'")
+	assert.Len(t, tmc.queriesContaining("update _vt.vreplication set state='Running' where db_name='vt_targetks' and workflow='lkp_vdx'"), 1)
+
+	sourceVSchema, err := ws.ts.GetVSchema(ctx, "sourceks")
+	require.NoError(t, err)
+	assert.Equal(t, "true", sourceVSchema.Vindexes["v"].Params["write_only"])
+	assert.Equal(t, []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}, {Column: "c1", Name: "v"}}, sourceVSchema.Tables["t1"].ColumnVindexes)
+
+	targetVSchema, err := ws.ts.GetVSchema(ctx, "targetks")
+	require.NoError(t, err)
+	assert.Contains(t, targetVSchema.Tables, "lkp")
+
+	srvVSchema, err := ws.ts.GetSrvVSchema(ctx, "zone1")
+	require.NoError(t, err)
+	assert.Contains(t, srvVSchema.Keyspaces["sourceks"].Vindexes, "v")
+
+	// Creating it again, even from scratch, finds the existing workflow.
+	require.NoError(t, ws.ts.SaveVSchema(ctx, "sourceks", vschema))
+	tmc.vrepResults = map[string]*querypb.QueryResult{
+		"workflow='lkp_vdx'": sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("1", "int64"), "1")),
+	}
+	_, err = ws.LookupVindexCreate(ctx, &vtctldatapb.LookupVindexCreateRequest{
+		Keyspace: "sourceks",
+		Vindex:   specs,
+	})
+	assert.ErrorContains(t, err, "workflow lkp_vdx already exists in keyspace targetks")
+
+	// The lookup table is removed from the target VSchema again when the
+	// workflow cannot be created.
+	require.NoError(t, ws.ts.SaveVSchema(ctx, "targetks", &vschemapb.Keyspace{}))
+	_, err = ws.LookupVindexCreate(ctx, &vtctldatapb.LookupVindexCreateRequest{
+		Keyspace: "sourceks",
+		Vindex:   specs,
+	})
+	assert.ErrorContains(t, err, "workflow lkp_vdx already exists in keyspace targetks")
+	targetVSchema, err = ws.ts.GetVSchema(ctx, "targetks")
+	require.NoError(t, err)
+	assert.NotContains(t, targetVSchema.Tables, "lkp")
+	sourceVSchema, err = ws.ts.GetVSchema(ctx, "sourceks")
+	require.NoError(t, err)
+	assert.NotContains(t, sourceVSchema.Vindexes, "v")
+}
+
+func TestLookupVindexExternalize(t *testing.T) {
+	vindex := func(owner string) *vschemapb.Vindex {
+		return &vschemapb.Vindex{
+			Type: "lookup_unique",
+			Params: map[string]string{
+				"table":      "targetks.lkp",
+				"from":       "c1",
+				"to":         "keyspace_id",
+				"write_only": "true",
+			},
+			Owner: owner,
+		}
+	}
+	streamResultUpdated := func(stopAfterCopy bool, state, message string, timeUpdated time.Time) *querypb.QueryResult {
+		bls := &binlogdatapb.BinlogSource{
+			Keyspace:      "sourceks",
+			Shard:         "0",
+			StopAfterCopy: stopAfterCopy,
+		}
+		source, err := prototext.Marshal(bls)
+		require.NoError(t, err)
+		return sqltypes.ResultToProto3(sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"id|workflow|source|pos|stop_pos|max_replication_lag|state|db_name|time_updated|transaction_timestamp|message|tags",
+				"int64|varchar|blob|varchar|varchar|int64|varchar|varchar|int64|int64|varchar|varchar",
+			),
+			fmt.Sprintf("1|lkp_vdx|%s|||0|%s|vt_targetks|%d|0|%s|", source, state, timeUpdated.Unix(), message),
+		))
+	}
+	streamResult := func(stopAfterCopy bool, state, message string) *querypb.QueryResult {
+		return streamResultUpdated(stopAfterCopy, state, message, time.Now())
+	}
+	copyStates := sqltypes.ResultToProto3(sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("table_name|lastpk", "varchar|varbinary"),
+		"t1|fields:{name:\"id\" type:INT64} rows:{lengths:1 values:\"5\"}",
+	))
+
+	tests := []struct {
+		name            string
+		owner           string
+		workflow        *querypb.QueryResult
+		copyStates      *querypb.QueryResult
+		ready           bool
+		err             string
+		workflowDeleted bool
+	}{{
+		name:            "owned and stopped after copy",
+		owner:           "t1",
+		workflow:        streamResult(true, "Stopped", "Stopped after copy."),
+		ready:           true,
+		workflowDeleted: true,
+	}, {
+		name:     "owned and still copying",
+		owner:    "t1",
+		workflow: streamResult(true, "Running", ""),
+		err:      "is not in Stopped after copy state",
+	}, {
+		name:     "owned and continuing after copy",
+		owner:    "t1",
+		workflow: streamResult(false, "Running", ""),
+		ready:    true,
+		// Owned vindexes are maintained by vtgate once externalized.
+		workflowDeleted: true,
+	}, {
+		name:     "unowned and running",
+		workflow: streamResult(false, "Running", ""),
+		ready:    true,
+	}, {
+		name:       "unowned and copying",
+		workflow:   streamResult(false, "Running", ""),
+		copyStates: copyStates,
+		err:        "is not in Running state: Copying",
+	}, {
+		name:     "unowned and lagging",
+		workflow: streamResultUpdated(false, "Running", "", time.Now().Add(-time.Minute)),
+		err:      "is lagging by",
+	}, {
+		name:     "unowned and stopped",
+		workflow: streamResult(false, "Stopped", ""),
+		err:      "is not in Running state",
+	}, {
+		name: "no workflow",
+		err:  "workflow lkp_vdx not found in keyspace targetks",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tmc := &fakeLookupTMC{vrepResults: map[string]*querypb.QueryResult{}}
+			if tt.workflow != nil {
+				tmc.vrepResults["select id, workflow, source"] = tt.workflow
+			}
+			if tt.copyStates != nil {
+				tmc.vrepResults["select table_name, lastpk from _vt.copy_state"] = tt.copyStates
+			}
+			ws := newLookupVindexTestServer(ctx, t, tmc, &vschemapb.Keyspace{
+				Sharded:  true,
+				Vindexes: map[string]*vschemapb.Vindex{"hash": {Type: "hash"}, "v": vindex(tt.owner)},
+				Tables: map[string]*vschemapb.Table{
+					"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}, {Column: "c1", Name: "v"}}},
+				},
+			})
+
+			show, err := ws.LookupVindexShow(ctx, &vtctldatapb.LookupVindexShowRequest{Keyspace: "sourceks", Name: "v"})
+			require.NoError(t, err)
+			assert.Equal(t, "targetks", show.TargetKeyspace)
+			assert.Equal(t, tt.workflow != nil, show.Workflow != nil)
+			assert.Equal(t, tt.ready, show.ReadyToExternalize)
+			assert.False(t, show.Externalized)
+
+			resp, err := ws.LookupVindexExternalize(ctx, &vtctldatapb.LookupVindexExternalizeRequest{Keyspace: "sourceks", Name: "v"})
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.workflowDeleted, resp.WorkflowDeleted)
+
+			deletes := tmc.queriesContaining("delete from _vt.vreplication where db_name='vt_targetks' and workflow='lkp_vdx'")
+			if tt.workflowDeleted {
+				assert.Len(t, deletes, 1)
+			} else {
+				assert.Empty(t, deletes)
+			}
+
+			sourceVSchema, err := ws.ts.GetVSchema(ctx, "sourceks")
+			require.NoError(t, err)
+			assert.NotContains(t, sourceVSchema.Vindexes["v"].Params, "write_only")
+
+			show, err = ws.LookupVindexShow(ctx, &vtctldatapb.LookupVindexShowRequest{Keyspace: "sourceks", Name: "v"})
+			require.NoError(t, err)
+			assert.True(t, show.Externalized)
+			assert.False(t, show.ReadyToExternalize)
+		})
+	}
+
+	t.Run("unknown vindex", func(t *testing.T) {
+		ctx := context.Background()
+		ws := newLookupVindexTestServer(ctx, t, &fakeLookupTMC{}, &vschemapb.Keyspace{})
+		_, err := ws.LookupVindexShow(ctx, &vtctldatapb.LookupVindexShowRequest{Keyspace: "sourceks", Name: "v"})
+		assert.ErrorContains(t, err, "vindex v not found in vschema of keyspace sourceks")
+	})
+}
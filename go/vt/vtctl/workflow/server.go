@@ -62,9 +62,10 @@ var (
 // workflows (MoveTables, Reshard, etc) and schema migration workflows.
 //
 // NB: This is in alpha, and you probably don't want to depend on it (yet!).
-// Currently, it provides only a read-only API to vreplication workflows. Write
-// actions on vreplication workflows, and schema migration workflows entirely,
-// are not yet supported, but planned.
+// Currently, it provides a read-only API to vreplication workflows, plus the
+// creation and externalization of lookup vindexes. Other write actions on
+// vreplication workflows, and schema migration workflows entirely, are not yet
+// supported, but planned.
 type Server struct {
 	ts  *topo.Server
 	tmc tmclient.TabletManagerClient
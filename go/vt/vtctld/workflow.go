@@ -29,6 +29,7 @@ import (
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vtctl"
 	"vitess.io/vitess/go/vt/workflow"
+	"vitess.io/vitess/go/vt/workflow/rollingrestart"
 	"vitess.io/vitess/go/vt/workflow/topovalidator"
 )
 
@@ -52,6 +53,9 @@ func initWorkflowManager(ts *topo.Server) {
 		topovalidator.RegisterShardValidator()
 		topovalidator.Register()
 
+		// Register the rolling restart workflow.
+		rollingrestart.Register()
+
 		// Unregister the disabled workflows.
 		for _, name := range workflowManagerDisable {
 			workflow.Unregister(name)
@@ -83,6 +83,13 @@ type (
 		// Target is used to override the "database" target in the
 		// vtgate session to simulate `USE <target>`
 		Target string
+
+		// Cost controls whether the cost of each statement is computed
+		Cost bool
+
+		// TableStats maps table names, optionally qualified with their
+		// keyspace, to the stats used to estimate the rows a statement reads
+		TableStats map[string]*TableStats
 	}
 
 	// TabletQuery defines a query that was sent to a given tablet and how it was
@@ -119,6 +126,10 @@ type (
 
 		// list of queries / bind vars sent to each tablet
 		TabletActions map[string]*TabletActions
+
+		// summary of how expensive the statement is, only set when
+		// Options.Cost is
+		Cost *Cost `json:",omitempty"`
 	}
 
 	outputQuery struct {
@@ -134,6 +145,8 @@ type (
 		vtgateSession  *vtgatepb.Session
 		spMap          map[string]string
 		spCount        int
+		computeCost    bool
+		tableStats     map[string]*TableStats
 
 		// time simulator
 		batchTime       *sync2.Batcher
@@ -190,7 +203,7 @@ func Init(vSchemaStr, sqlSchema, ksShardMapStr string, opts *Options) (*VTExplai
 	vte := &VTExplain{vtgateSession: &vtgatepb.Session{
 		TargetString: "",
 		Autocommit:   true,
-	}}
+	}, computeCost: opts.Cost, tableStats: opts.TableStats}
 	vte.setGlobalTabletEnv(tabletEnv)
 	err = vte.initVtgateExecutor(vSchemaStr, ksShardMapStr, opts)
 	if err != nil {
@@ -314,11 +327,15 @@ func (vte *VTExplain) explain(sql string) (*Explain, error) {
 		return nil, err
 	}
 
-	return &Explain{
+	explain := &Explain{
 		SQL:           sql,
 		Plans:         plans,
 		TabletActions: tabletActions,
-	}, nil
+	}
+	if vte.computeCost {
+		explain.Cost = vte.cost(plans, tabletActions)
+	}
+	return explain, nil
 }
 
 // ExplainsAsText returns a text representation of the explains in logical time
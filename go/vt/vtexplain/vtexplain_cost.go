@@ -0,0 +1,198 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtexplain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/jsonutil"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+)
+
+type (
+	// TableStats holds the statistics of a table that are used to estimate
+	// how many rows a statement reads.
+	TableStats struct {
+		// Rows is the total number of rows in the table, across all shards.
+		Rows int64 `json:"rows"`
+	}
+
+	// Cost summarizes how expensive a statement is to execute, so that
+	// changes to queries can be compared with each other.
+	Cost struct {
+		// ShardsTouched is the number of shards that were sent at least
+		// one query, in any keyspace.
+		ShardsTouched int
+
+		// RoundTrips is the number of queries vtgate sent to the tablets.
+		RoundTrips int
+
+		// Scatter is true if any part of the plan is sent to all the
+		// shards of a keyspace.
+		Scatter bool
+
+		// JoinStrategy lists the join strategies vtgate uses, if any.
+		JoinStrategy string `json:",omitempty"`
+
+		// EstimatedRows is the estimated number of rows read by the
+		// tablets. It is only set when table stats are supplied.
+		EstimatedRows *int64 `json:",omitempty"`
+	}
+
+	costOutput struct {
+		SQL  string
+		Cost *Cost
+	}
+)
+
+// ParseTableStats parses a JSON map of table name to TableStats. Table names
+// may be qualified with their keyspace, as in "ks.table".
+func ParseTableStats(data string) (map[string]*TableStats, error) {
+	stats := make(map[string]*TableStats)
+	if err := json.Unmarshal([]byte(data), &stats); err != nil {
+		return nil, fmt.Errorf("unable to parse table stats: %v", err)
+	}
+	for name, s := range stats {
+		if s == nil || s.Rows < 0 {
+			return nil, fmt.Errorf("invalid table stats for %s", name)
+		}
+	}
+	return stats, nil
+}
+
+// ExplainsAsCostJSON returns a json representation of the cost of each of the
+// explains
+func ExplainsAsCostJSON(explains []*Explain) string {
+	costs := make([]costOutput, 0, len(explains))
+	for _, explain := range explains {
+		costs = append(costs, costOutput{SQL: explain.SQL, Cost: explain.Cost})
+	}
+	costJSON, _ := jsonutil.MarshalIndentNoEscape(costs, "", "    ")
+	return string(costJSON)
+}
+
+func (vte *VTExplain) cost(plans []*engine.Plan, tabletActions map[string]*TabletActions) *Cost {
+	cost := &Cost{ShardsTouched: len(tabletActions)}
+	for _, actions := range tabletActions {
+		cost.RoundTrips += len(actions.TabletQueries)
+	}
+
+	var descriptions []engine.PrimitiveDescription
+	for _, plan := range plans {
+		if plan.Instructions != nil {
+			descriptions = append(descriptions, engine.PrimitiveToPlanDescription(plan.Instructions))
+		}
+	}
+
+	joins := make(map[string]bool)
+	var rows int64
+	var walk func(desc engine.PrimitiveDescription)
+	walk = func(desc engine.PrimitiveDescription) {
+		if desc.Variant == engine.Scatter.String() {
+			cost.Scatter = true
+		}
+		switch desc.OperatorType {
+		case "Join":
+			if strings.HasPrefix(desc.Variant, "Hash") {
+				joins["Hash"] = true
+			} else {
+				joins["NestedLoop"] = true
+			}
+		case "SemiJoin":
+			joins["SemiJoin"] = true
+		case "Route", "Update", "Delete":
+			rows += vte.estimateRows(desc, tabletActions)
+		}
+		for _, input := range desc.Inputs {
+			walk(input)
+		}
+	}
+	for _, desc := range descriptions {
+		walk(desc)
+	}
+
+	strategies := make([]string, 0, len(joins))
+	for strategy := range joins {
+		strategies = append(strategies, strategy)
+	}
+	sort.Strings(strategies)
+	cost.JoinStrategy = strings.Join(strategies, ",")
+
+	if vte.tableStats != nil {
+		cost.EstimatedRows = &rows
+	}
+	return cost
+}
+
+// estimateRows estimates the number of rows a route reads, assuming the rows
+// of its tables are spread evenly over the shards of the keyspace. Tables
+// without stats are not counted.
+func (vte *VTExplain) estimateRows(desc engine.PrimitiveDescription, tabletActions map[string]*TabletActions) int64 {
+	if vte.tableStats == nil || desc.Keyspace == nil || desc.Variant == engine.None.String() {
+		return 0
+	}
+	tables, _ := desc.Other["Table"].(string)
+	if tables == "" {
+		return 0
+	}
+
+	ks := desc.Keyspace.Name
+	var rows int64
+	for _, table := range strings.Split(tables, ",") {
+		table = strings.Trim(strings.TrimSpace(table), "`")
+		stats, ok := vte.tableStats[ks+"."+table]
+		if !ok {
+			stats, ok = vte.tableStats[table]
+		}
+		if !ok {
+			continue
+		}
+		if desc.Variant == engine.EqualUnique.String() {
+			rows += min64(stats.Rows, 1)
+			continue
+		}
+		rows += stats.Rows * int64(vte.shardsTouched(ks, tabletActions)) / int64(vte.shardCount(ks))
+	}
+	return rows
+}
+
+func (vte *VTExplain) shardsTouched(ks string, tabletActions map[string]*TabletActions) int {
+	touched := 0
+	for tablet := range tabletActions {
+		if strings.HasPrefix(tablet, ks+"/") {
+			touched++
+		}
+	}
+	return touched
+}
+
+func (vte *VTExplain) shardCount(ks string) int {
+	if count := len(vte.explainTopo.KeyspaceShards[ks]); count > 0 {
+		return count
+	}
+	return 1
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
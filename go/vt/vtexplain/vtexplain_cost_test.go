@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtexplain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCost(t *testing.T) {
+	opts := defaultTestOpts()
+	opts.Cost = true
+	opts.TableStats = map[string]*TableStats{
+		"user":            {Rows: 1000},
+		"ks_unsharded.t1": {Rows: 50},
+	}
+	vte := initTest(ModeMulti, opts, &testopts{}, t)
+	defer vte.Stop()
+
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{
+			sql:  "select * from user where id = 1",
+			want: `{"ShardsTouched":1,"RoundTrips":1,"Scatter":false,"EstimatedRows":1}`,
+		},
+		{
+			sql:  "select * from user",
+			want: `{"ShardsTouched":4,"RoundTrips":4,"Scatter":true,"EstimatedRows":1000}`,
+		},
+		{
+			sql:  "select * from t1",
+			want: `{"ShardsTouched":1,"RoundTrips":1,"Scatter":false,"EstimatedRows":50}`,
+		},
+		{
+			// music has no stats
+			sql:  "select * from music",
+			want: `{"ShardsTouched":4,"RoundTrips":4,"Scatter":true,"EstimatedRows":0}`,
+		},
+		{
+			// the lookup of name_user_map is a round trip of its own
+			sql:  "select u.id, t.id from user u join t1 t on u.id = t.id where u.name = 'a'",
+			want: `{"ShardsTouched":3,"RoundTrips":3,"Scatter":false,"JoinStrategy":"NestedLoop","EstimatedRows":51}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.sql, func(t *testing.T) {
+			explains, err := vte.Run(test.sql)
+			require.NoError(t, err)
+			require.Len(t, explains, 1)
+			got, err := json.Marshal(explains[0].Cost)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.want, string(got))
+		})
+	}
+}
+
+func TestCostWithoutTableStats(t *testing.T) {
+	opts := defaultTestOpts()
+	opts.Cost = true
+	vte := initTest(ModeMulti, opts, &testopts{}, t)
+	defer vte.Stop()
+
+	explains, err := vte.Run("select * from user where id = 1; select * from user")
+	require.NoError(t, err)
+
+	want := `[
+    {
+        "SQL": "select * from user where id = 1",
+        "Cost": {
+            "ShardsTouched": 1,
+            "RoundTrips": 1,
+            "Scatter": false
+        }
+    },
+    {
+        "SQL": "select * from user",
+        "Cost": {
+            "ShardsTouched": 4,
+            "RoundTrips": 4,
+            "Scatter": true
+        }
+    }
+]
+`
+	assert.Equal(t, want, ExplainsAsCostJSON(explains))
+}
+
+func TestCostNotComputed(t *testing.T) {
+	vte := initTest(ModeMulti, defaultTestOpts(), &testopts{}, t)
+	defer vte.Stop()
+
+	explains, err := vte.Run("select * from user where id = 1")
+	require.NoError(t, err)
+	require.Len(t, explains, 1)
+	assert.Nil(t, explains[0].Cost)
+	assert.NotContains(t, ExplainsAsJSON(explains), `"Cost"`)
+}
+
+func TestParseTableStats(t *testing.T) {
+	stats, err := ParseTableStats(`{"user": {"rows": 10}, "ks.t1": {"rows": 0}}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*TableStats{
+		"user":  {Rows: 10},
+		"ks.t1": {Rows: 0},
+	}, stats)
+
+	_, err = ParseTableStats(`{"user": {"rows": -1}}`)
+	assert.EqualError(t, err, "invalid table stats for user")
+
+	_, err = ParseTableStats(`{"user": null}`)
+	assert.EqualError(t, err, "invalid table stats for user")
+
+	_, err = ParseTableStats(`[]`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtexplain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/json2"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtctl/schematools"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// The files a Snapshot is written to, in the formats of the --schema-file,
+// --vschema-file and --ks-shard-map-file flags of vtexplain.
+const (
+	snapshotSchemaFile     = "schema.sql"
+	snapshotVSchemaFile    = "vschema.json"
+	snapshotKsShardMapFile = "ks_shard_map.json"
+)
+
+// Snapshot holds the table schemas, VSchema and serving shards of a live
+// cluster, in the formats Init takes, so that queries can be explained
+// against the cluster offline.
+type Snapshot struct {
+	Schema     string
+	VSchema    string
+	KsShardMap string
+}
+
+// FetchSnapshot builds a Snapshot from the SrvVSchema and SrvKeyspaces of the
+// given cell, and from the table schemas of a primary tablet of each keyspace.
+//
+// vtexplain has a single schema for all keyspaces, so when several keyspaces
+// have a table with the same name, the definition from the keyspace that
+// sorts first is kept.
+func FetchSnapshot(ctx context.Context, ts *topo.Server, tmc tmclient.TabletManagerClient, cell string) (*Snapshot, error) {
+	srvVSchema, err := ts.GetSrvVSchema(ctx, cell)
+	if err != nil {
+		return nil, fmt.Errorf("GetSrvVSchema(%s): %v", cell, err)
+	}
+
+	keyspaces := make([]string, 0, len(srvVSchema.Keyspaces))
+	vschemas := make(map[string]json.RawMessage, len(srvVSchema.Keyspaces))
+	for ks, kvs := range srvVSchema.Keyspaces {
+		keyspaces = append(keyspaces, ks)
+		data, err := json2.MarshalPB(kvs)
+		if err != nil {
+			return nil, err
+		}
+		vschemas[ks] = data
+	}
+	sort.Strings(keyspaces)
+
+	vschemaJSON, err := json.MarshalIndent(vschemas, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	ksShardMap := make(map[string]map[string]*topo.ShardInfo, len(keyspaces))
+	tables := make(map[string]string)
+	var schema strings.Builder
+	for _, ks := range keyspaces {
+		srvKeyspace, err := ts.GetSrvKeyspace(ctx, cell, ks)
+		if err != nil {
+			return nil, fmt.Errorf("GetSrvKeyspace(%s, %s): %v", cell, ks, err)
+		}
+
+		shards := make(map[string]*topo.ShardInfo)
+		for _, partition := range srvKeyspace.Partitions {
+			if partition.ServedType != topodatapb.TabletType_PRIMARY {
+				continue
+			}
+			for _, ref := range partition.ShardReferences {
+				shards[ref.Name] = topo.NewShardInfo(ks, ref.Name, &topodatapb.Shard{KeyRange: ref.KeyRange}, nil)
+			}
+		}
+		if len(shards) == 0 {
+			return nil, fmt.Errorf("keyspace %s has no primary shards serving in cell %s", ks, cell)
+		}
+		ksShardMap[ks] = shards
+
+		sd, err := fetchKeyspaceSchema(ctx, ts, tmc, ks, shards)
+		if err != nil {
+			return nil, err
+		}
+		for _, td := range sd.TableDefinitions {
+			if td.Type != tmutils.TableBaseTable {
+				continue
+			}
+			if other, ok := tables[td.Name]; ok {
+				log.Warningf("table %s is defined in keyspaces %s and %s, keeping the definition from %s", td.Name, other, ks, other)
+				continue
+			}
+			tables[td.Name] = ks
+			fmt.Fprintf(&schema, "%s;\n", td.Schema)
+		}
+	}
+
+	ksShardMapJSON, err := json.MarshalIndent(ksShardMap, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Snapshot{
+		Schema:     schema.String(),
+		VSchema:    string(vschemaJSON),
+		KsShardMap: string(ksShardMapJSON),
+	}, nil
+}
+
+// fetchKeyspaceSchema returns the table schemas from the primary of the first
+// of the given shards that has one.
+func fetchKeyspaceSchema(ctx context.Context, ts *topo.Server, tmc tmclient.TabletManagerClient, ks string, shards map[string]*topo.ShardInfo) (*tabletmanagerdatapb.SchemaDefinition, error) {
+	names := make([]string, 0, len(shards))
+	for name := range shards {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		si, err := ts.GetShard(ctx, ks, name)
+		if err != nil {
+			return nil, fmt.Errorf("GetShard(%s, %s): %v", ks, name, err)
+		}
+		if si.PrimaryAlias == nil {
+			continue
+		}
+		return schematools.GetSchema(ctx, ts, tmc, si.PrimaryAlias, &tabletmanagerdatapb.GetSchemaRequest{
+			Tables:          []string{"/.*/"},
+			TableSchemaOnly: true,
+		})
+	}
+	return nil, fmt.Errorf("keyspace %s has no shard with a primary to fetch the schema from", ks)
+}
+
+// Write writes the snapshot to the given directory, creating it if needed.
+func (s *Snapshot) Write(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	files := map[string]string{
+		snapshotSchemaFile:     s.Schema,
+		snapshotVSchemaFile:    s.VSchema,
+		snapshotKsShardMapFile: s.KsShardMap,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadSnapshot reads a snapshot written by Snapshot.Write from the given
+// directory.
+func ReadSnapshot(dir string) (*Snapshot, error) {
+	read := func(name string) (string, error) {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return "", fmt.Errorf("cannot read snapshot: %v", err)
+		}
+		return string(data), nil
+	}
+
+	var (
+		s   Snapshot
+		err error
+	)
+	if s.Schema, err = read(snapshotSchemaFile); err != nil {
+		return nil, err
+	}
+	if s.VSchema, err = read(snapshotVSchemaFile); err != nil {
+		return nil, err
+	}
+	if s.KsShardMap, err = read(snapshotKsShardMapFile); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtexplain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+type snapshotTMC struct {
+	tmclient.TabletManagerClient
+	schemas map[string]*tabletmanagerdatapb.SchemaDefinition
+}
+
+func (tmc *snapshotTMC) GetSchema(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetSchemaRequest) (*tabletmanagerdatapb.SchemaDefinition, error) {
+	return tmc.schemas[tablet.Keyspace], nil
+}
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("zone1")
+
+	keyspaceShards := map[string][]string{
+		"ks_sharded":   {"-80", "80-"},
+		"ks_unsharded": {"0"},
+	}
+	uid := uint32(100)
+	for ks, shards := range keyspaceShards {
+		require.NoError(t, ts.CreateKeyspace(ctx, ks, &topodatapb.Keyspace{}))
+		partition := &topodatapb.SrvKeyspace_KeyspacePartition{ServedType: topodatapb.TabletType_PRIMARY}
+		for _, shard := range shards {
+			require.NoError(t, ts.CreateShard(ctx, ks, shard))
+			// Only the last shard of each keyspace has a primary, which the
+			// schema is then fetched from.
+			if shard == shards[len(shards)-1] {
+				alias := &topodatapb.TabletAlias{Cell: "zone1", Uid: uid}
+				uid++
+				require.NoError(t, ts.CreateTablet(ctx, &topodatapb.Tablet{Alias: alias, Keyspace: ks, Shard: shard, Type: topodatapb.TabletType_PRIMARY}))
+				_, err := ts.UpdateShardFields(ctx, ks, shard, func(si *topo.ShardInfo) error {
+					si.PrimaryAlias = alias
+					return nil
+				})
+				require.NoError(t, err)
+			}
+			_, kr, err := topo.ValidateShardName(shard)
+			require.NoError(t, err)
+			partition.ShardReferences = append(partition.ShardReferences, &topodatapb.ShardReference{Name: shard, KeyRange: kr})
+		}
+		require.NoError(t, ts.UpdateSrvKeyspace(ctx, "zone1", ks, &topodatapb.SrvKeyspace{
+			Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{partition},
+		}))
+	}
+	require.NoError(t, ts.UpdateSrvVSchema(ctx, "zone1", &vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"ks_sharded": {
+				Sharded:  true,
+				Vindexes: map[string]*vschemapb.Vindex{"hash": {Type: "hash"}},
+				Tables: map[string]*vschemapb.Table{
+					"t2": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}}},
+				},
+			},
+			"ks_unsharded": {
+				Tables: map[string]*vschemapb.Table{"t1": {}},
+			},
+		},
+	}))
+
+	tmc := &snapshotTMC{
+		schemas: map[string]*tabletmanagerdatapb.SchemaDefinition{
+			"ks_sharded": {
+				TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+					Name:   "t2",
+					Schema: "CREATE TABLE `t2` (\n  `id` bigint NOT NULL,\n  `name` varchar(64) DEFAULT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB",
+					Type:   tmutils.TableBaseTable,
+				}, {
+					Name:   "v2",
+					Schema: "CREATE VIEW `v2` AS select `id` from `t2`",
+					Type:   tmutils.TableView,
+				}},
+			},
+			"ks_unsharded": {
+				TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+					Name:   "t1",
+					Schema: "CREATE TABLE `t1` (\n  `id` bigint NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB",
+					Type:   tmutils.TableBaseTable,
+				}, {
+					// Also defined in ks_sharded, which sorts first.
+					Name:   "t2",
+					Schema: "CREATE TABLE `t2` (\n  `other` int\n) ENGINE=InnoDB",
+					Type:   tmutils.TableBaseTable,
+				}},
+			},
+		},
+	}
+
+	snapshot, err := FetchSnapshot(ctx, ts, tmc, "zone1")
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE `t2` (\n  `id` bigint NOT NULL,\n  `name` varchar(64) DEFAULT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB;\n"+
+		"CREATE TABLE `t1` (\n  `id` bigint NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB;\n", snapshot.Schema)
+
+	dir := t.TempDir()
+	require.NoError(t, snapshot.Write(dir))
+	read, err := ReadSnapshot(dir)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, read)
+
+	vte, err := Init(read.VSchema, read.Schema, read.KsShardMap, defaultTestOpts())
+	require.NoError(t, err)
+	defer vte.Stop()
+
+	assert.Len(t, vte.explainTopo.KeyspaceShards["ks_sharded"], 2)
+	assert.Len(t, vte.explainTopo.KeyspaceShards["ks_unsharded"], 1)
+
+	explains, err := vte.Run("select name from t2 where id = 1; select id from t1")
+	require.NoError(t, err)
+	require.Len(t, explains, 2)
+
+	shard, err := key.EvenShardsKeyRange(0, 2)
+	require.NoError(t, err)
+	assert.Contains(t, explains[0].TabletActions, "ks_sharded/"+key.KeyRangeString(shard))
+	assert.Len(t, explains[0].TabletActions, 1)
+	assert.Contains(t, explains[1].TabletActions, "ks_unsharded/0")
+
+	_, err = FetchSnapshot(ctx, ts, tmc, "zone2")
+	assert.Error(t, err)
+}
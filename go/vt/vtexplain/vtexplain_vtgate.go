@@ -164,7 +164,7 @@ func getShardRanges(ks string, vschema *vschemapb.Keyspace, ksShardMap map[strin
 		for shard, info := range shardMap {
 			ref := &topodatapb.ShardReference{
 				Name:     shard,
-				KeyRange: info.KeyRange,
+				KeyRange: info.GetKeyRange(),
 			}
 
 			shards = append(shards, ref)
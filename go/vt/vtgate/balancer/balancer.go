@@ -0,0 +1,225 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package balancer implements the policies the tablet gateway uses to
+// choose between the healthy tablets of a target.
+package balancer
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// Random picks tablets at random. It is the default policy.
+	Random = "random"
+	// LeastOutstanding prefers the tablets with the fewest queries in flight.
+	LeastOutstanding = "least_outstanding"
+	// EWMALatency prefers the tablets with the lowest exponentially weighted
+	// moving average of query latency, weighted by the queries in flight.
+	EWMALatency = "ewma_latency"
+
+	// ewmaWeight is the weight of the newest latency sample in the average.
+	ewmaWeight = 0.3
+	// errorLatency is the latency recorded for a query that failed because of
+	// its tablet (see isTabletError), if it failed faster than that, so that
+	// failing tablets are not preferred.
+	errorLatency = time.Second
+)
+
+// Policy orders the healthy tablets of a target by preference, and is told
+// about every query sent to a tablet so it can track their load.
+//
+// Implementations must be safe for concurrent use.
+type Policy interface {
+	// Sort orders tablets in place, most preferred first.
+	Sort(tablets []*discovery.TabletHealth)
+
+	// Begin records that a query is being sent to the tablet. The returned
+	// function must be called with the result of the query once it's done.
+	Begin(alias *topodatapb.TabletAlias) (done func(err error))
+
+	// Prune forgets the tablets for which keep returns false, such as the
+	// tablets which left the healthcheck.
+	Prune(keep func(alias *topodatapb.TabletAlias) bool)
+}
+
+var (
+	mu       sync.Mutex
+	policies = make(map[string]func() Policy)
+)
+
+func init() {
+	Register(Random, func() Policy { return randomPolicy{} })
+	Register(LeastOutstanding, func() Policy {
+		return newLoadPolicy(func(load *tabletLoad) float64 {
+			return float64(load.outstanding)
+		})
+	})
+	Register(EWMALatency, func() Policy {
+		return newLoadPolicy(func(load *tabletLoad) float64 {
+			return load.latency * float64(load.outstanding+1)
+		})
+	})
+}
+
+// Register makes a policy available under the given name. It panics if a
+// policy is already registered under that name.
+func Register(name string, factory func() Policy) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := policies[name]; ok {
+		panic(fmt.Sprintf("balancer policy %s is already registered", name))
+	}
+	policies[name] = factory
+}
+
+// New returns a new instance of the named policy.
+func New(name string) (Policy, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	factory, ok := policies[name]
+	if !ok {
+		names := make([]string, 0, len(policies))
+		for name := range policies {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown balancer policy %q, valid values are: %s", name, strings.Join(names, ", "))
+	}
+	return factory(), nil
+}
+
+type randomPolicy struct{}
+
+func (randomPolicy) Sort(tablets []*discovery.TabletHealth) {
+	rand.Shuffle(len(tablets), func(i, j int) {
+		tablets[i], tablets[j] = tablets[j], tablets[i]
+	})
+}
+
+func (randomPolicy) Begin(*topodatapb.TabletAlias) func(error) {
+	return func(error) {}
+}
+
+func (randomPolicy) Prune(func(*topodatapb.TabletAlias) bool) {}
+
+// isTabletError returns true if the query failed because of its tablet, or
+// the connection to it, rather than because of the query itself.
+func isTabletError(err error) bool {
+	switch vterrors.Code(err) {
+	case vtrpcpb.Code_UNAVAILABLE, vtrpcpb.Code_DEADLINE_EXCEEDED, vtrpcpb.Code_CLUSTER_EVENT, vtrpcpb.Code_RESOURCE_EXHAUSTED:
+		return true
+	}
+	return false
+}
+
+// tabletLoad is what a loadPolicy knows about a tablet.
+type tabletLoad struct {
+	alias *topodatapb.TabletAlias
+
+	// outstanding is the number of queries in flight.
+	outstanding int
+	// latency is the moving average of the query latency, in nanoseconds.
+	// It is zero until the first query finishes, so that new tablets are
+	// tried first.
+	latency float64
+}
+
+// loadPolicy orders tablets by a score computed from their load, lowest
+// first. Tablets with the same score are ordered at random.
+type loadPolicy struct {
+	score func(load *tabletLoad) float64
+
+	mu      sync.Mutex
+	tablets map[string]*tabletLoad
+}
+
+func newLoadPolicy(score func(load *tabletLoad) float64) *loadPolicy {
+	return &loadPolicy{
+		score:   score,
+		tablets: make(map[string]*tabletLoad),
+	}
+}
+
+func (lp *loadPolicy) Sort(tablets []*discovery.TabletHealth) {
+	randomPolicy{}.Sort(tablets)
+
+	scores := make(map[*discovery.TabletHealth]float64, len(tablets))
+	lp.mu.Lock()
+	for _, th := range tablets {
+		if load, ok := lp.tablets[topoproto.TabletAliasString(th.Tablet.Alias)]; ok {
+			scores[th] = lp.score(load)
+		}
+	}
+	lp.mu.Unlock()
+
+	sort.SliceStable(tablets, func(i, j int) bool {
+		return scores[tablets[i]] < scores[tablets[j]]
+	})
+}
+
+func (lp *loadPolicy) Begin(alias *topodatapb.TabletAlias) func(error) {
+	key := topoproto.TabletAliasString(alias)
+	start := time.Now()
+
+	lp.mu.Lock()
+	load, ok := lp.tablets[key]
+	if !ok {
+		load = &tabletLoad{alias: alias}
+		lp.tablets[key] = load
+	}
+	load.outstanding++
+	lp.mu.Unlock()
+
+	return func(err error) {
+		latency := time.Since(start)
+		if err != nil && isTabletError(err) && latency < errorLatency {
+			latency = errorLatency
+		}
+
+		lp.mu.Lock()
+		defer lp.mu.Unlock()
+		load.outstanding--
+		if load.latency == 0 {
+			load.latency = float64(latency)
+		} else {
+			load.latency = ewmaWeight*float64(latency) + (1-ewmaWeight)*load.latency
+		}
+	}
+}
+
+func (lp *loadPolicy) Prune(keep func(alias *topodatapb.TabletAlias) bool) {
+	lp.mu.Lock()
+	defer lp.mu.Unlock()
+	for key, load := range lp.tablets {
+		if !keep(load.alias) {
+			// Queries still in flight update the forgotten load.
+			delete(lp.tablets, key)
+		}
+	}
+}
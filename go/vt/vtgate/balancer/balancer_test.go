@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package balancer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func newTablets(n int) []*discovery.TabletHealth {
+	tablets := make([]*discovery.TabletHealth, 0, n)
+	for i := 1; i <= n; i++ {
+		tablets = append(tablets, &discovery.TabletHealth{Tablet: topo.NewTablet(uint32(i), "cell1", "host")})
+	}
+	return tablets
+}
+
+func TestNew(t *testing.T) {
+	for _, name := range []string{Random, LeastOutstanding, EWMALatency} {
+		policy, err := New(name)
+		require.NoError(t, err)
+		assert.NotNil(t, policy)
+	}
+
+	_, err := New("round_robin")
+	assert.EqualError(t, err, `unknown balancer policy "round_robin", valid values are: ewma_latency, least_outstanding, random`)
+
+	assert.Panics(t, func() { Register(Random, func() Policy { return randomPolicy{} }) })
+}
+
+func TestRandom(t *testing.T) {
+	policy, err := New(Random)
+	require.NoError(t, err)
+
+	tablets := newTablets(3)
+	policy.Sort(tablets)
+	assert.ElementsMatch(t, newTablets(3), tablets)
+	policy.Begin(tablets[0].Tablet.Alias)(nil)
+}
+
+func TestLeastOutstanding(t *testing.T) {
+	policy, err := New(LeastOutstanding)
+	require.NoError(t, err)
+
+	tablets := newTablets(3)
+	tablet1, tablet2 := tablets[0].Tablet.Alias, tablets[1].Tablet.Alias
+	done1 := policy.Begin(tablet1)
+	policy.Begin(tablet1)
+	done2 := policy.Begin(tablet2)
+
+	for i := 0; i < 10; i++ {
+		policy.Sort(tablets)
+		assert.EqualValues(t, []uint32{3, 2, 1}, uids(tablets))
+	}
+
+	// tablet 1 now has one query in flight, like tablet 2
+	done1(nil)
+	done2(nil)
+	policy.Sort(tablets)
+	assert.EqualValues(t, 1, tablets[2].Tablet.Alias.Uid)
+}
+
+func TestEWMALatency(t *testing.T) {
+	policy, err := New(EWMALatency)
+	require.NoError(t, err)
+	lp := policy.(*loadPolicy)
+
+	tablets := newTablets(3)
+	tablet1 := tablets[0].Tablet.Alias
+	policy.Begin(tablet1)(nil)
+	policy.Begin(tablets[1].Tablet.Alias)(nil)
+	// tablet 3 has no latency samples, so it's tried first
+	policy.Sort(tablets)
+	assert.EqualValues(t, 3, tablets[0].Tablet.Alias.Uid)
+
+	lp.tablets["cell1-0000000001"].latency = 100
+	lp.tablets["cell1-0000000002"].latency = 300
+	policy.Begin(tablet1)(vterrors.New(vtrpcpb.Code_UNAVAILABLE, "connection refused"))
+	assert.Greater(t, lp.tablets["cell1-0000000001"].latency, float64(errorLatency)/10)
+
+	// errors of the query itself are not the tablet's fault
+	lp.tablets["cell1-0000000001"].latency = 100
+	policy.Begin(tablet1)(vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "syntax error"))
+	assert.Less(t, lp.tablets["cell1-0000000001"].latency, float64(errorLatency)/10)
+	policy.Begin(tablet1)(errors.New("duplicate entry"))
+	assert.Less(t, lp.tablets["cell1-0000000001"].latency, float64(errorLatency)/10)
+
+	lp.tablets["cell1-0000000001"].latency = 100
+	lp.tablets["cell1-0000000002"].latency = 250
+	lp.tablets["cell1-0000000003"] = &tabletLoad{latency: 500}
+	policy.Sort(tablets)
+	assert.EqualValues(t, []uint32{1, 2, 3}, uids(tablets))
+
+	// two queries in flight on tablet 1 triple its score
+	policy.Begin(tablet1)
+	policy.Begin(tablet1)
+	policy.Sort(tablets)
+	assert.EqualValues(t, []uint32{2, 1, 3}, uids(tablets))
+}
+
+func TestPrune(t *testing.T) {
+	policy, err := New(LeastOutstanding)
+	require.NoError(t, err)
+	lp := policy.(*loadPolicy)
+
+	tablets := newTablets(3)
+	for _, th := range tablets {
+		policy.Begin(th.Tablet.Alias)(nil)
+	}
+
+	// tablet 2 left the healthcheck
+	policy.Prune(func(alias *topodatapb.TabletAlias) bool {
+		return alias.Uid != 2
+	})
+	assert.Len(t, lp.tablets, 2)
+	assert.NotContains(t, lp.tablets, "cell1-0000000002")
+
+	policy, err = New(Random)
+	require.NoError(t, err)
+	policy.Prune(func(*topodatapb.TabletAlias) bool { return false })
+}
+
+func uids(tablets []*discovery.TabletHealth) []uint32 {
+	var uids []uint32
+	for _, th := range tablets {
+		uids = append(uids, th.Tablet.Alias.Uid)
+	}
+	return uids
+}
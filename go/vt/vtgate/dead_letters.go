@@ -0,0 +1,281 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/messager/deadletter"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// Messages that were sent vt_max_attempts times without being acked are
+// dead-lettered by the tablets: they are left with neither a time_acked nor a
+// time_next, either in their message table or in its vt_dead_letter_table.
+// The functions below list and requeue them, with the queries of the
+// deadletter package. Since a message and its dead letter always live on the
+// same shard, they run on each shard in turn.
+
+// defaultDeadLettersLimit is the number of dead letters listed by the dead
+// letters API when no limit is given.
+const defaultDeadLettersLimit = 100
+
+// DeadLetter is a dead-lettered message.
+type DeadLetter struct {
+	Shard  string            `json:"shard"`
+	Values map[string]string `json:"values"`
+}
+
+// DeadLettersPage is a page of dead letters. If there may be more dead
+// letters, Next is set to the shard and id of the last one, to be passed to
+// DeadLetters to list the next page.
+type DeadLettersPage struct {
+	DeadLetters []*DeadLetter `json:"dead_letters"`
+	Next        *DeadLetter   `json:"next,omitempty"`
+}
+
+func (e *Executor) deadLetterShards(ctx context.Context, keyspace string) ([]*srvtopo.ResolvedShard, error) {
+	rss, _, err := e.resolver.resolver.GetAllShards(ctx, keyspace, topodatapb.TabletType_PRIMARY)
+	return rss, err
+}
+
+func shardSession(keyspace string, rs *srvtopo.ResolvedShard) *SafeSession {
+	return NewSafeSession(&vtgatepb.Session{TargetString: keyspace + "/" + rs.Target.Shard})
+}
+
+// DeadLetters returns at most limit dead letters in the given table, which is
+// either a message table or a dead letter table, ordered by shard and id. If
+// shard is set, the dead letters of the shards before it are skipped, and if
+// after is also set, so are the dead letters of shard up to that id.
+func (e *Executor) DeadLetters(ctx context.Context, keyspace, table, shard, after string, limit int) (*DeadLettersPage, error) {
+	if limit <= 0 || limit > deadletter.MaxListLimit {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "limit must be between 1 and %d, got %d", deadletter.MaxListLimit, limit)
+	}
+
+	if after != "" && shard == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "after requires a shard")
+	}
+
+	rss, err := e.deadLetterShards(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	if shard != "" {
+		i := 0
+		for i < len(rss) && rss[i].Target.Shard != shard {
+			i++
+		}
+		if i == len(rss) {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "shard %s not found in keyspace %s", shard, keyspace)
+		}
+		rss = rss[i:]
+	}
+
+	name := sqlparser.NewIdentifierCS(table)
+	page := &DeadLettersPage{}
+	for i, rs := range rss {
+		query := deadletter.BuildListQuery(name, false)
+		bindVars := map[string]*querypb.BindVariable{
+			"limit": sqltypes.Int64BindVariable(int64(limit - len(page.DeadLetters))),
+		}
+		if i == 0 && after != "" {
+			query = deadletter.BuildListQuery(name, true)
+			bindVars["after"] = sqltypes.StringBindVariable(after)
+		}
+
+		qr, err := e.Execute(ctx, "DeadLetters", shardSession(keyspace, rs), query.Query, bindVars)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "shard %v", rs.Target.Shard)
+		}
+
+		for _, row := range qr.Rows {
+			letter := &DeadLetter{
+				Shard:  rs.Target.Shard,
+				Values: make(map[string]string, len(row)),
+			}
+			for i, field := range qr.Fields {
+				letter.Values[field.Name] = row[i].ToString()
+			}
+			page.DeadLetters = append(page.DeadLetters, letter)
+		}
+
+		if len(page.DeadLetters) == limit {
+			last := page.DeadLetters[limit-1]
+			page.Next = &DeadLetter{
+				Shard:  last.Shard,
+				Values: map[string]string{"id": last.Values["id"]},
+			}
+			break
+		}
+	}
+
+	return page, nil
+}
+
+// RequeueDeadLetters schedules the dead letters with the given ids in table
+// for immediate delivery, and resets their attempt count. If into is set,
+// table is a dead letter table and the messages are moved back to the message
+// table into, which must have the same columns. It returns the number of
+// messages requeued.
+func (e *Executor) RequeueDeadLetters(ctx context.Context, keyspace, table, into string, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no message ids given")
+	}
+
+	rss, err := e.deadLetterShards(ctx, keyspace)
+	if err != nil {
+		return 0, err
+	}
+
+	var queries []string
+	for _, query := range deadletter.BuildRequeueQueries(sqlparser.NewIdentifierCS(table), sqlparser.NewIdentifierCS(into)) {
+		queries = append(queries, query.Query)
+	}
+
+	idbvs := &querypb.BindVariable{
+		Type:   querypb.Type_TUPLE,
+		Values: make([]*querypb.Value, 0, len(ids)),
+	}
+	for _, id := range ids {
+		idbvs.Values = append(idbvs.Values, &querypb.Value{
+			Type:  querypb.Type_VARBINARY,
+			Value: []byte(id),
+		})
+	}
+	bindVars := map[string]*querypb.BindVariable{
+		"ids":      idbvs,
+		"time_now": sqltypes.Int64BindVariable(time.Now().UnixNano()),
+	}
+
+	var count int64
+	for _, rs := range rss {
+		n, err := e.requeueDeadLettersOnShard(ctx, shardSession(keyspace, rs), queries, bindVars)
+		if err != nil {
+			return count, vterrors.Wrapf(err, "shard %v", rs.Target.Shard)
+		}
+		count += n
+	}
+
+	return count, nil
+}
+
+// requeueDeadLettersOnShard runs the requeue queries in a transaction, and
+// returns the number of rows affected by the last one.
+func (e *Executor) requeueDeadLettersOnShard(ctx context.Context, session *SafeSession, queries []string, bindVars map[string]*querypb.BindVariable) (int64, error) {
+	if _, err := e.Execute(ctx, "RequeueDeadLetters", session, "begin", nil); err != nil {
+		return 0, err
+	}
+
+	var qr *sqltypes.Result
+	for _, query := range queries {
+		var err error
+		if qr, err = e.Execute(ctx, "RequeueDeadLetters", session, query, bindVars); err != nil {
+			if _, rerr := e.Execute(ctx, "RequeueDeadLetters", session, "rollback", nil); rerr != nil {
+				return 0, vterrors.Wrapf(err, "rollback failed: %v", rerr)
+			}
+			return 0, err
+		}
+	}
+
+	if _, err := e.Execute(ctx, "RequeueDeadLetters", session, "commit", nil); err != nil {
+		return 0, err
+	}
+
+	return int64(qr.RowsAffected), nil
+}
+
+// initDeadLettersAPI registers the dead letters API:
+//
+//	GET  /api/dead-letters/<keyspace>/<table>[?limit=<n>][&shard=<shard>[&after=<id>]]
+//	POST /api/dead-letters/<keyspace>/<table>?id=<id>[&id=<id>...][&into=<table>]
+func initDeadLettersAPI(e *Executor) {
+	handleAPI("dead-letters/", func(w http.ResponseWriter, r *http.Request) error {
+		parts := strings.Split(getItemPath(r.URL.Path), "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid dead-letters path: %q  expected path: /<keyspace>/<table>", r.URL.Path)
+		}
+		keyspace, table := parts[0], parts[1]
+
+		var resp any
+		switch r.Method {
+		case http.MethodGet:
+			if err := acl.CheckAccessHTTP(r, acl.DEBUGGING); err != nil {
+				acl.SendError(w, err)
+				return nil
+			}
+
+			if err := r.ParseForm(); err != nil {
+				return err
+			}
+
+			limit := defaultDeadLettersLimit
+			if l := r.Form.Get("limit"); l != "" {
+				var err error
+				if limit, err = strconv.Atoi(l); err != nil {
+					return fmt.Errorf("invalid limit %q: %v", l, err)
+				}
+			}
+
+			page, err := e.DeadLetters(r.Context(), keyspace, table, r.Form.Get("shard"), r.Form.Get("after"), limit)
+			if err != nil {
+				return err
+			}
+			resp = page
+		case http.MethodPost:
+			if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+				acl.SendError(w, err)
+				return nil
+			}
+			if err := r.ParseForm(); err != nil {
+				return err
+			}
+
+			count, err := e.RequeueDeadLetters(r.Context(), keyspace, table, r.Form.Get("into"), r.Form["id"])
+			if err != nil {
+				return err
+			}
+			resp = map[string]int64{"requeued": count}
+		default:
+			http.Error(w, fmt.Sprintf("unsupported method %v", r.Method), http.StatusMethodNotAllowed)
+			return nil
+		}
+
+		data, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			return fmt.Errorf("cannot marshal data: %v", err)
+		}
+		w.Header().Set("Content-Type", jsonContentType)
+		w.Write(data)
+		return nil
+	})
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+func TestDeadLetters(t *testing.T) {
+	executor, _, _, sbclookup := createExecutorEnv()
+
+	sbclookup.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("id|message", "int64|varchar"),
+		"1|a",
+		"2|b",
+	)})
+
+	page, err := executor.DeadLetters(context.Background(), KsTestUnsharded, "msg", "", "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, &DeadLettersPage{
+		DeadLetters: []*DeadLetter{
+			{Shard: "0", Values: map[string]string{"id": "1", "message": "a"}},
+			{Shard: "0", Values: map[string]string{"id": "2", "message": "b"}},
+		},
+		Next: &DeadLetter{Shard: "0", Values: map[string]string{"id": "2"}},
+	}, page)
+	require.Len(t, sbclookup.Queries, 1)
+	assert.Equal(t, "select * from msg where time_acked is null and time_next is null order by id asc limit :limit", sbclookup.Queries[0].Sql)
+	assert.Equal(t, sqltypes.Int64BindVariable(2), sbclookup.Queries[0].BindVariables["limit"])
+
+	// The next page starts after the last dead letter.
+	sbclookup.Queries = nil
+	sbclookup.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("id|message", "int64|varchar"),
+		"3|c",
+	)})
+	page, err = executor.DeadLetters(context.Background(), KsTestUnsharded, "msg", page.Next.Shard, page.Next.Values["id"], 2)
+	require.NoError(t, err)
+	assert.Equal(t, &DeadLettersPage{
+		DeadLetters: []*DeadLetter{
+			{Shard: "0", Values: map[string]string{"id": "3", "message": "c"}},
+		},
+	}, page)
+	require.Len(t, sbclookup.Queries, 1)
+	assert.Equal(t, "select * from msg where time_acked is null and time_next is null and id > :after order by id asc limit :limit", sbclookup.Queries[0].Sql)
+	assert.Equal(t, sqltypes.StringBindVariable("2"), sbclookup.Queries[0].BindVariables["after"])
+
+	_, err = executor.DeadLetters(context.Background(), KsTestUnsharded, "msg", "", "", 0)
+	require.EqualError(t, err, "limit must be between 1 and 1000, got 0")
+	_, err = executor.DeadLetters(context.Background(), KsTestUnsharded, "msg", "", "2", 10)
+	require.EqualError(t, err, "after requires a shard")
+	_, err = executor.DeadLetters(context.Background(), KsTestUnsharded, "msg", "-80", "", 10)
+	require.EqualError(t, err, "shard -80 not found in keyspace TestUnsharded")
+}
+
+func TestRequeueDeadLetters(t *testing.T) {
+	executor, _, _, sbclookup := createExecutorEnv()
+
+	_, err := executor.RequeueDeadLetters(context.Background(), KsTestUnsharded, "msg", "", nil)
+	require.EqualError(t, err, "no message ids given")
+
+	sbclookup.SetResults([]*sqltypes.Result{{RowsAffected: 2}})
+	count, err := executor.RequeueDeadLetters(context.Background(), KsTestUnsharded, "msg", "", []string{"1", "2"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	require.Len(t, sbclookup.Queries, 1)
+	assert.Equal(t, "update msg set time_next = :time_now, epoch = 0 where id in ::ids and time_acked is null and time_next is null", sbclookup.Queries[0].Sql)
+	assert.EqualValues(t, 1, sbclookup.CommitCount.Get())
+
+	sbclookup.Queries = nil
+	sbclookup.SetResults([]*sqltypes.Result{{RowsAffected: 1}, {RowsAffected: 1}, {RowsAffected: 1}})
+	count, err = executor.RequeueDeadLetters(context.Background(), KsTestUnsharded, "msg_dead", "msg", []string{"1"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	var got []string
+	for _, q := range sbclookup.Queries {
+		got = append(got, q.Sql)
+	}
+	assert.Equal(t, []string{
+		"insert into msg select * from msg_dead where id in ::ids and time_acked is null and time_next is null",
+		"update msg set time_next = :time_now, epoch = 0 where id in ::ids and time_acked is null and time_next is null",
+		"delete from msg_dead where id in ::ids and time_acked is null and time_next is null",
+	}, got)
+	assert.EqualValues(t, 2, sbclookup.CommitCount.Get())
+}
+
+func TestDeadLettersStatements(t *testing.T) {
+	executor, _, _, sbclookup := createExecutorEnv()
+	session := NewSafeSession(&vtgatepb.Session{TargetString: KsTestUnsharded, Autocommit: true})
+
+	sbclookup.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("id|message", "int64|varchar"),
+		"1|a",
+	)})
+	qr, err := executor.Execute(context.Background(), "TestDeadLettersStatements", session, "show vitess_dead_letters from msg", nil)
+	require.NoError(t, err)
+	assert.Len(t, qr.Rows, 1)
+	require.Len(t, sbclookup.Queries, 1)
+	assert.Equal(t, "select * from msg where time_acked is null and time_next is null order by id asc limit 1000", sbclookup.Queries[0].Sql)
+
+	sbclookup.Queries = nil
+	sbclookup.SetResults([]*sqltypes.Result{{RowsAffected: 1}, {RowsAffected: 1}, {RowsAffected: 1}})
+	qr, err = executor.Execute(context.Background(), "TestDeadLettersStatements", session, "alter vitess_dead_letters msg_dead retry (1) into msg", nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, qr.RowsAffected)
+	var got []string
+	for _, q := range sbclookup.Queries {
+		got = append(got, q.Sql)
+	}
+	assert.Equal(t, []string{
+		"insert into msg select * from msg_dead where id in ::ids and time_acked is null and time_next is null",
+		"update msg set time_next = :time_now, epoch = 0 where id in ::ids and time_acked is null and time_next is null",
+		"delete from msg_dead where id in ::ids and time_acked is null and time_next is null",
+	}, got)
+	assert.EqualValues(t, 1, sbclookup.CommitCount.Get())
+	assert.False(t, session.InTransaction(), "requeueing must not leave the session in a transaction")
+}
@@ -254,7 +254,7 @@ func (cached *Generate) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(48)
+		size += int64(80)
 	}
 	// field Keyspace *vitess.io/vitess/go/vt/vtgate/vindexes.Keyspace
 	size += cached.Keyspace.CachedSize(true)
@@ -264,6 +264,8 @@ func (cached *Generate) CachedSize(alloc bool) int64 {
 	if cc, ok := cached.Values.(cachedObject); ok {
 		size += cc.CachedSize(true)
 	}
+	// field Sequence string
+	size += hack.RuntimeAllocSize(int64(len(cached.Sequence)))
 	return size
 }
 func (cached *GroupByParams) CachedSize(alloc bool) int64 {
@@ -719,6 +721,29 @@ func (cached *ReplaceVariables) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *RequeueDeadLetters) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(80)
+	}
+	// field Keyspace *vitess.io/vitess/go/vt/vtgate/vindexes.Keyspace
+	size += cached.Keyspace.CachedSize(true)
+	// field Table string
+	size += hack.RuntimeAllocSize(int64(len(cached.Table)))
+	// field Into string
+	size += hack.RuntimeAllocSize(int64(len(cached.Into)))
+	// field IDs []string
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.IDs)) * int64(16))
+		for _, elem := range cached.IDs {
+			size += hack.RuntimeAllocSize(int64(len(elem)))
+		}
+	}
+	return size
+}
 func (cached *RevertMigration) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -74,6 +74,14 @@ func (t *noopVCursor) ReleaseLock(context.Context) error {
 	panic("implement me")
 }
 
+func (t *noopVCursor) GenerateSnowflakeIDs(ctx context.Context, count int64) ([]int64, error) {
+	panic("unimplemented")
+}
+
+func (t *noopVCursor) RequeueDeadLetters(ctx context.Context, keyspace, table, into string, ids []string) (int64, error) {
+	panic("unimplemented")
+}
+
 func (t *noopVCursor) SetExec(ctx context.Context, name string, value string) error {
 	panic("implement me")
 }
@@ -176,6 +184,10 @@ func (t *noopVCursor) InTransactionAndIsDML() bool {
 	panic("implement me")
 }
 
+func (t *noopVCursor) InTransaction() bool {
+	panic("implement me")
+}
+
 func (t *noopVCursor) FindRoutedTable(sqlparser.TableName) (*vindexes.Table, error) {
 	panic("implement me")
 }
@@ -369,6 +381,10 @@ func (f *loggingVCursor) InTransactionAndIsDML() bool {
 	return false
 }
 
+func (f *loggingVCursor) InTransaction() bool {
+	return false
+}
+
 func (f *loggingVCursor) LookupRowLockShardSession() vtgatepb.CommitOrder {
 	panic("implement me")
 }
@@ -451,6 +467,21 @@ func (f *loggingVCursor) ExecuteStandalone(ctx context.Context, query string, bi
 	return f.nextResult()
 }
 
+func (f *loggingVCursor) GenerateSnowflakeIDs(ctx context.Context, count int64) ([]int64, error) {
+	f.log = append(f.log, fmt.Sprintf("GenerateSnowflakeIDs %d", count))
+	ids := make([]int64, count)
+	for i := range ids {
+		// Snowflake IDs are not consecutive.
+		ids[i] = 1000 + 10*int64(i)
+	}
+	return ids, nil
+}
+
+func (f *loggingVCursor) RequeueDeadLetters(ctx context.Context, keyspace, table, into string, ids []string) (int64, error) {
+	f.log = append(f.log, fmt.Sprintf("RequeueDeadLetters %s %s %s %v", keyspace, table, into, ids))
+	return int64(len(ids)), nil
+}
+
 func (f *loggingVCursor) StreamExecuteMulti(ctx context.Context, query string, rss []*srvtopo.ResolvedShard, bindVars []map[string]*querypb.BindVariable, rollbackOnError bool, autocommit bool, callback func(reply *sqltypes.Result) error) []error {
 	f.mu.Lock()
 	f.log = append(f.log, fmt.Sprintf("StreamExecuteMulti %s %s", query, printResolvedShardsBindVars(rss, bindVars)))
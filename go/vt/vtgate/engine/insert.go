@@ -172,6 +172,16 @@ type Generate struct {
 	Values evalengine.Expr
 	// Insert using Select, offset for auto increment column
 	Offset int
+	// Sequence is the qualified name of the sequence table.
+	Sequence string
+	// Cache is the number of values to fetch from the sequence
+	// at a time and hand out from the vtgate sequence cache.
+	// If zero, values are fetched as they are needed.
+	Cache int64
+	// Snowflake is set if values are snowflake IDs generated
+	// by vtgate. Keyspace, Query, Sequence and Cache are then
+	// unused.
+	Snowflake bool
 }
 
 // InsertOpcode is a number representing the opcode
@@ -548,33 +558,20 @@ func (ins *Insert) processGenerateFromValues(
 	}
 
 	// If generation is needed, generate the requested number of values (as one call).
+	var ids []int64
 	if count != 0 {
-		rss, _, err := vcursor.ResolveDestinations(ctx, ins.Generate.Keyspace.Name, nil, []key.Destination{key.DestinationAnyShard{}})
-		if err != nil {
-			return 0, err
-		}
-		if len(rss) != 1 {
-			return 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "auto sequence generation can happen through single shard only, it is getting routed to %d shards", len(rss))
-		}
-		bindVars := map[string]*querypb.BindVariable{"n": sqltypes.Int64BindVariable(count)}
-		qr, err := vcursor.ExecuteStandalone(ctx, ins.Generate.Query, bindVars, rss[0])
-		if err != nil {
-			return 0, err
-		}
-		// If no rows are returned, it's an internal error, and the code
-		// must panic, which will be caught and reported.
-		insertID, err = evalengine.ToInt64(qr.Rows[0][0])
+		ids, err = ins.generate(ctx, vcursor, count)
 		if err != nil {
 			return 0, err
 		}
+		insertID = ids[0]
 	}
 
 	// Fill the holes where no value was supplied.
-	cur := insertID
 	for i, v := range values {
 		if shouldGenerate(v) {
-			bindVars[SeqVarName+strconv.Itoa(i)] = sqltypes.Int64BindVariable(cur)
-			cur++
+			bindVars[SeqVarName+strconv.Itoa(i)] = sqltypes.Int64BindVariable(ids[0])
+			ids = ids[1:]
 		} else {
 			bindVars[SeqVarName+strconv.Itoa(i)] = sqltypes.ValueBindVariable(v)
 		}
@@ -611,6 +608,57 @@ func (ins *Insert) processGenerateFromRows(
 	}
 
 	// If generation is needed, generate the requested number of values (as one call).
+	ids, err := ins.generate(ctx, vcursor, count)
+	if err != nil {
+		return 0, err
+	}
+	insertID = ids[0]
+
+	for idx, val := range rows {
+		if genColPresent {
+			if val[offset].IsNull() {
+				val[offset] = sqltypes.NewInt64(ids[0])
+				ids = ids[1:]
+			}
+		} else {
+			rows[idx] = append(val, sqltypes.NewInt64(ids[0]))
+			ids = ids[1:]
+		}
+	}
+
+	return insertID, nil
+}
+
+// generate returns count new values, in increasing order. Sequence values
+// are taken from the sequence cache if enabled.
+func (ins *Insert) generate(ctx context.Context, vcursor VCursor, count int64) ([]int64, error) {
+	if ins.Generate.Snowflake {
+		return vcursor.GenerateSnowflakeIDs(ctx, count)
+	}
+
+	var first int64
+	var err error
+	if ins.Generate.Cache == 0 {
+		first, err = ins.fetchSequence(ctx, vcursor, count)
+	} else {
+		first, err = sequenceCaches.get(ins.Generate.Sequence).reserve(count, ins.Generate.Cache, func(n int64) (int64, error) {
+			return ins.fetchSequence(ctx, vcursor, n)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, count)
+	for i := range ids {
+		ids[i] = first + int64(i)
+	}
+	return ids, nil
+}
+
+// fetchSequence fetches count consecutive values from the sequence table,
+// and returns the first one.
+func (ins *Insert) fetchSequence(ctx context.Context, vcursor VCursor, count int64) (int64, error) {
 	rss, _, err := vcursor.ResolveDestinations(ctx, ins.Generate.Keyspace.Name, nil, []key.Destination{key.DestinationAnyShard{}})
 	if err != nil {
 		return 0, err
@@ -625,25 +673,7 @@ func (ins *Insert) processGenerateFromRows(
 	}
 	// If no rows are returned, it's an internal error, and the code
 	// must panic, which will be caught and reported.
-	insertID, err = evalengine.ToInt64(qr.Rows[0][0])
-	if err != nil {
-		return 0, err
-	}
-
-	used := insertID
-	for idx, val := range rows {
-		if genColPresent {
-			if val[offset].IsNull() {
-				val[offset] = sqltypes.NewInt64(used)
-				used++
-			}
-		} else {
-			rows[idx] = append(val, sqltypes.NewInt64(used))
-			used++
-		}
-	}
-
-	return insertID, nil
+	return evalengine.ToInt64(qr.Rows[0][0])
 }
 
 // getInsertShardedRoute performs all the vindex related work
@@ -976,7 +1006,11 @@ func (ins *Insert) description() PrimitiveDescription {
 	}
 
 	if ins.Generate != nil && ins.Generate.Values == nil {
-		other["AutoIncrement"] = fmt.Sprintf("%s:%d", ins.Generate.Keyspace.Name, ins.Generate.Offset)
+		if ins.Generate.Snowflake {
+			other["AutoIncrement"] = fmt.Sprintf("%s:%d", vindexes.GeneratorSnowflake, ins.Generate.Offset)
+		} else {
+			other["AutoIncrement"] = fmt.Sprintf("%s:%d", ins.Generate.Keyspace.Name, ins.Generate.Offset)
+		}
 	}
 
 	if len(ins.VindexValueOffset) > 0 {
@@ -88,6 +88,8 @@ type (
 
 		InTransactionAndIsDML() bool
 
+		InTransaction() bool
+
 		LookupRowLockShardSession() vtgatepb.CommitOrder
 
 		FindRoutedTable(tablename sqlparser.TableName) (*vindexes.Table, error)
@@ -115,6 +117,14 @@ type (
 
 		// StreamExecutePrimitiveStandalone executes the primitive in its own new autocommit session.
 		StreamExecutePrimitiveStandalone(ctx context.Context, primitive Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(result *sqltypes.Result) error) error
+
+		// GenerateSnowflakeIDs returns count new snowflake IDs, in increasing order.
+		GenerateSnowflakeIDs(ctx context.Context, count int64) ([]int64, error)
+
+		// RequeueDeadLetters requeues the dead-lettered messages with the given
+		// ids in table, moving them back to the message table into if it is set.
+		// It returns the number of messages requeued.
+		RequeueDeadLetters(ctx context.Context, keyspace, table, into string, ids []string) (int64, error)
 	}
 
 	//SessionActions gives primitives ability to interact with the session state
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+var _ Primitive = (*RequeueDeadLetters)(nil)
+
+// RequeueDeadLetters represents the instructions to requeue dead-lettered
+// messages, on every shard of the keyspace.
+type RequeueDeadLetters struct {
+	Keyspace *vindexes.Keyspace
+	// Table is the message table or dead letter table holding the messages.
+	Table string
+	// Into is the message table to move the messages back to, if Table is a
+	// dead letter table.
+	Into string
+	IDs  []string
+
+	noTxNeeded
+
+	noInputs
+}
+
+func (r *RequeueDeadLetters) description() PrimitiveDescription {
+	other := map[string]any{
+		"Table": r.Table,
+		"IDs":   r.IDs,
+	}
+	if r.Into != "" {
+		other["Into"] = r.Into
+	}
+	return PrimitiveDescription{
+		OperatorType: "RequeueDeadLetters",
+		Keyspace:     r.Keyspace,
+		Other:        other,
+	}
+}
+
+// RouteType implements the Primitive interface
+func (r *RequeueDeadLetters) RouteType() string {
+	return "RequeueDeadLetters"
+}
+
+// GetKeyspaceName implements the Primitive interface
+func (r *RequeueDeadLetters) GetKeyspaceName() string {
+	return r.Keyspace.Name
+}
+
+// GetTableName implements the Primitive interface
+func (r *RequeueDeadLetters) GetTableName() string {
+	return r.Table
+}
+
+// TryExecute implements the Primitive interface
+func (r *RequeueDeadLetters) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	count, err := vcursor.RequeueDeadLetters(ctx, r.Keyspace.Name, r.Table, r.Into, r.IDs)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{RowsAffected: uint64(count)}, nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (r *RequeueDeadLetters) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	result, err := r.TryExecute(ctx, vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(result)
+}
+
+// GetFields implements the Primitive interface
+func (r *RequeueDeadLetters) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "[BUG] GetFields is not reachable")
+}
@@ -71,6 +71,9 @@ const (
 	// Is used when the query explicitly sets a target destination:
 	// in the clause e.g: UPDATE `keyspace[-]`.x1 SET foo=1
 	ByDestination
+	// Between is for routing a query with a BETWEEN predicate on the vindex column.
+	// Requires: A RangeMapper Vindex, and two Values: the bounds of the range.
+	Between
 )
 
 var opName = map[Opcode]string{
@@ -86,6 +89,7 @@ var opName = map[Opcode]string{
 	None:          "None",
 	ByDestination: "ByDestination",
 	SubShard:      "SubShard",
+	Between:       "Between",
 }
 
 // MarshalJSON serializes the Opcode as a JSON string.
@@ -164,6 +168,8 @@ func (rp *RoutingParameters) findRoute(ctx context.Context, vcursor VCursor, bin
 		default:
 			return rp.multiEqual(ctx, vcursor, bindVars)
 		}
+	case Between:
+		return rp.between(ctx, vcursor, bindVars)
 	default:
 		// Unreachable.
 		return nil, nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unsupported opcode: %v", rp.Opcode)
@@ -349,6 +355,23 @@ func (rp *RoutingParameters) equal(ctx context.Context, vcursor VCursor, bindVar
 	return rss, multiBindVars, nil
 }
 
+func (rp *RoutingParameters) between(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) ([]*srvtopo.ResolvedShard, []map[string]*querypb.BindVariable, error) {
+	env := evalengine.EnvWithBindVars(bindVars, vcursor.ConnCollation())
+	from, err := env.Evaluate(rp.Values[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := env.Evaluate(rp.Values[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	destination, err := rp.Vindex.(vindexes.RangeMapper).MapRange(ctx, vcursor, from.Value(), to.Value())
+	if err != nil {
+		return nil, nil, err
+	}
+	return rp.byDestination(ctx, vcursor, bindVars, destination)
+}
+
 func (rp *RoutingParameters) equalMultiCol(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) ([]*srvtopo.ResolvedShard, []map[string]*querypb.BindVariable, error) {
 	env := evalengine.EnvWithBindVars(bindVars, vcursor.ConnCollation())
 	var rowValue []sqltypes.Value
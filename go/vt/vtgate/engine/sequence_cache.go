@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"sync"
+
+	"vitess.io/vitess/go/stats"
+)
+
+var (
+	// sequenceCaches holds the values vtgate fetched ahead from sequences.
+	sequenceCaches = &sequenceCacheMap{caches: make(map[string]*sequenceCache)}
+
+	sequenceCacheHits   = stats.NewCountersWithSingleLabel("SequenceCacheHits", "Number of sequence allocations served from the vtgate cache", "Sequence")
+	sequenceCacheMisses = stats.NewCountersWithSingleLabel("SequenceCacheMisses", "Number of sequence allocations that fetched values from the sequence table", "Sequence")
+)
+
+type sequenceCacheMap struct {
+	mu     sync.Mutex
+	caches map[string]*sequenceCache
+}
+
+// get returns the cache of the given sequence, creating it if needed.
+func (m *sequenceCacheMap) get(sequence string) *sequenceCache {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sc, ok := m.caches[sequence]
+	if !ok {
+		sc = &sequenceCache{sequence: sequence}
+		m.caches[sequence] = sc
+	}
+	return sc
+}
+
+// sequenceCache hands out a range of values fetched from a sequence. The
+// sequence table never returns a value twice, so values that are fetched
+// but not handed out, for example because vtgate restarts, are skipped
+// rather than reused.
+type sequenceCache struct {
+	sequence string
+
+	mu sync.Mutex
+	// next is the next value to hand out, and limit is the value after
+	// the last cached one.
+	next, limit int64
+}
+
+// reserve returns the first of count consecutive values. They are taken
+// from the cache if it has enough of them. Otherwise, fetch is called to
+// get at least size new values from the sequence. Cached values that do
+// not precede the new ones are dropped.
+func (sc *sequenceCache) reserve(count, size int64, fetch func(n int64) (int64, error)) (int64, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.limit-sc.next >= count {
+		sequenceCacheHits.Add(sc.sequence, 1)
+	} else {
+		sequenceCacheMisses.Add(sc.sequence, 1)
+		n := size
+		if n < count {
+			n = count
+		}
+		first, err := fetch(n)
+		if err != nil {
+			return 0, err
+		}
+		if first != sc.limit {
+			sc.next = first
+		}
+		sc.limit = first + n
+	}
+
+	first := sc.next
+	sc.next += count
+	return first, nil
+}
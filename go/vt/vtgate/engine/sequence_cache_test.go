@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestSequenceCacheReserve(t *testing.T) {
+	sc := &sequenceCache{sequence: "ks.reserve_seq"}
+
+	var fetches []int64
+	next := int64(1)
+	fetch := func(n int64) (int64, error) {
+		fetches = append(fetches, n)
+		first := next
+		next += n
+		return first, nil
+	}
+
+	first, err := sc.reserve(2, 5, fetch)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, first)
+	first, err = sc.reserve(3, 5, fetch)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, first)
+	assert.Equal(t, []int64{5}, fetches)
+
+	// Contiguous values are appended to the ones left in the cache.
+	first, err = sc.reserve(1, 5, fetch)
+	require.NoError(t, err)
+	assert.EqualValues(t, 6, first)
+	first, err = sc.reserve(6, 5, fetch)
+	require.NoError(t, err)
+	assert.EqualValues(t, 7, first)
+	assert.Equal(t, []int64{5, 5, 6}, fetches)
+
+	// Values left in the cache are dropped if the new ones don't follow them,
+	// for example because another vtgate fetched values in between.
+	next += 100
+	first, err = sc.reserve(5, 5, fetch)
+	require.NoError(t, err)
+	assert.EqualValues(t, 117, first)
+
+	// Failed fetches leave the cache unchanged.
+	_, err = sc.reserve(10, 5, func(int64) (int64, error) {
+		return 0, errors.New("fetch failed")
+	})
+	require.EqualError(t, err, "fetch failed")
+	first, err = sc.reserve(4, 5, fetch)
+	require.NoError(t, err)
+	assert.EqualValues(t, 122, first)
+
+	assert.EqualValues(t, 1, sequenceCacheHits.Counts()["ks.reserve_seq"])
+	assert.EqualValues(t, 6, sequenceCacheMisses.Counts()["ks.reserve_seq"])
+}
+
+func TestInsertUnshardedGenerateCached(t *testing.T) {
+	ins := NewQueryInsert(
+		InsertUnsharded,
+		&vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: false,
+		},
+		"dummy_insert",
+	)
+	ins.Generate = &Generate{
+		Keyspace: &vindexes.Keyspace{
+			Name:    "ks2",
+			Sharded: false,
+		},
+		Query: "dummy_generate",
+		Values: evalengine.NewTupleExpr(
+			evalengine.NullExpr,
+			evalengine.NullExpr,
+		),
+		Sequence: "ks2.cached_seq",
+		Cache:    10,
+	}
+
+	vc := newDMLTestVCursor("0")
+	vc.results = []*sqltypes.Result{
+		sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"nextval",
+				"int64",
+			),
+			"4",
+		),
+		{InsertID: 1},
+		{InsertID: 1},
+	}
+
+	result, err := ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	expectResult(t, "Execute", result, &sqltypes.Result{InsertID: 4})
+
+	// The second insert is served from the cache.
+	result, err = ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	expectResult(t, "Execute", result, &sqltypes.Result{InsertID: 6})
+
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks2 [] Destinations:DestinationAnyShard()`,
+		`ExecuteStandalone dummy_generate n: type:INT64 value:"10" ks2 0`,
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: dummy_insert {__seq0: type:INT64 value:"4" __seq1: type:INT64 value:"5"} true true`,
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: dummy_insert {__seq0: type:INT64 value:"6" __seq1: type:INT64 value:"7"} true true`,
+	})
+}
+
+func TestInsertUnshardedGenerateSnowflake(t *testing.T) {
+	ins := NewQueryInsert(
+		InsertUnsharded,
+		&vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: false,
+		},
+		"dummy_insert",
+	)
+	ins.Generate = &Generate{
+		Values: evalengine.NewTupleExpr(
+			evalengine.NullExpr,
+			evalengine.NewLiteralInt(2),
+			evalengine.NullExpr,
+		),
+		Snowflake: true,
+	}
+
+	vc := newDMLTestVCursor("0")
+	vc.results = []*sqltypes.Result{{InsertID: 1}}
+
+	result, err := ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	expectResult(t, "Execute", result, &sqltypes.Result{InsertID: 1000})
+
+	// Snowflake IDs are generated in vtgate, without a query to a sequence table.
+	vc.ExpectLog(t, []string{
+		`GenerateSnowflakeIDs 2`,
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: dummy_insert {__seq0: type:INT64 value:"1000" __seq1: type:INT64 value:"2" __seq2: type:INT64 value:"1010"} true true`,
+	})
+}
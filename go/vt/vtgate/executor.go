@@ -53,6 +53,7 @@ import (
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder"
+	"vitess.io/vitess/go/vt/vtgate/snowflake"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vtgate/vschemaacl"
 
@@ -107,6 +108,9 @@ type Executor struct {
 
 	// allowScatter will fail planning if set to false and a plan contains any scatter queries
 	allowScatter bool
+
+	// snowflake generates snowflake IDs. It is created on first use.
+	snowflake *snowflake.Generator
 }
 
 var executorOnce sync.Once
@@ -1388,3 +1392,30 @@ func getTabletThrottlerStatus(tabletHostPort string) (string, error) {
 func (e *Executor) ReleaseLock(ctx context.Context, session *SafeSession) error {
 	return e.txConn.ReleaseLock(ctx, session)
 }
+
+// GenerateSnowflakeIDs returns count new snowflake IDs. The first call leases
+// a snowflake node ID from the topo.
+func (e *Executor) GenerateSnowflakeIDs(ctx context.Context, count int64) ([]int64, error) {
+	e.mu.Lock()
+	if e.snowflake == nil {
+		ts, err := e.serv.GetTopoServer()
+		if err != nil {
+			e.mu.Unlock()
+			return nil, err
+		}
+		e.snowflake = snowflake.NewGenerator(ts)
+	}
+	generator := e.snowflake
+	e.mu.Unlock()
+
+	return generator.Generate(ctx, count)
+}
+
+// closeSnowflake releases the snowflake node ID, if the executor holds one.
+func (e *Executor) closeSnowflake() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.snowflake != nil {
+		e.snowflake.Close()
+	}
+}
@@ -189,6 +189,8 @@ func createInstructionFor(query string, stmt sqlparser.Statement, reservedVars *
 		return buildGeneralDDLPlan(query, stmt, reservedVars, vschema, enableOnlineDDL, enableDirectDDL)
 	case *sqlparser.AlterMigration:
 		return buildAlterMigrationPlan(query, vschema, enableOnlineDDL)
+	case *sqlparser.AlterDeadLetters:
+		return buildAlterDeadLettersPlan(stmt, vschema)
 	case *sqlparser.RevertMigration:
 		return buildRevertMigrationPlan(query, stmt, vschema, enableOnlineDDL)
 	case *sqlparser.ShowMigrationLogs:
@@ -224,6 +226,9 @@ func createInstructionFor(query string, stmt sqlparser.Statement, reservedVars *
 		return buildFlushPlan(stmt, vschema)
 	case *sqlparser.CallProc:
 		return buildCallProcPlan(stmt, vschema)
+	case *sqlparser.CreateTrigger, *sqlparser.CreateProcedure, *sqlparser.CreateFunction, *sqlparser.CreateEvent,
+		*sqlparser.DropTrigger, *sqlparser.DropProcedure, *sqlparser.DropFunction, *sqlparser.DropEvent:
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "unsupported: stored program DDL through vtgate: %s", sqlparser.String(stmt))
 	case *sqlparser.Stream:
 		return buildStreamPlan(stmt, vschema)
 	case *sqlparser.VStream:
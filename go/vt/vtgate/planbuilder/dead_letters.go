@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/messager/deadletter"
+)
+
+// deadLetterKeyspace returns the keyspace of a message table or dead letter
+// table. Dead letters are always read and requeued on the primaries.
+func deadLetterKeyspace(tbl sqlparser.TableName, vschema plancontext.VSchema, command string) (*vindexes.Keyspace, error) {
+	_, ks, tabletType, err := vschema.TargetDestination(tbl.Qualifier.String())
+	if err != nil {
+		return nil, err
+	}
+	if ks == nil {
+		return nil, vterrors.NewErrorf(vtrpcpb.Code_FAILED_PRECONDITION, vterrors.NoDB, "No database selected: use keyspace<:shard><@type> or keyspace<[range]><@type> (<> are optional)")
+	}
+
+	if tabletType != topodatapb.TabletType_PRIMARY {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "%s works only on primary tablet", command)
+	}
+
+	return ks, nil
+}
+
+func buildShowDeadLettersPlan(show *sqlparser.ShowBasic, vschema plancontext.VSchema) (engine.Primitive, error) {
+	ks, err := deadLetterKeyspace(show.Tbl, vschema, "show vitess_dead_letters")
+	if err != nil {
+		return nil, err
+	}
+
+	// Lists the first dead letters of each shard. The dead letters API of
+	// vtgate pages through all of them.
+	query, err := deadletter.BuildListQuery(show.Tbl.Name, false).GenerateQuery(map[string]*querypb.BindVariable{
+		"limit": sqltypes.Int64BindVariable(deadletter.MaxListLimit),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &engine.Send{
+		Keyspace:          ks,
+		TargetDestination: key.DestinationAllShards{},
+		Query:             query,
+	}, nil
+}
+
+func buildAlterDeadLettersPlan(stmt *sqlparser.AlterDeadLetters, vschema plancontext.VSchema) (engine.Primitive, error) {
+	ks, err := deadLetterKeyspace(stmt.Table, vschema, "ALTER VITESS_DEAD_LETTERS")
+	if err != nil {
+		return nil, err
+	}
+
+	if !stmt.Into.Qualifier.IsEmpty() && stmt.Into.Qualifier.String() != ks.Name {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "dead letters can only be moved to a message table in keyspace %s, got %s", ks.Name, sqlparser.String(stmt.Into))
+	}
+
+	ids := make([]string, 0, len(stmt.IDs))
+	for _, expr := range stmt.IDs {
+		lit, ok := expr.(*sqlparser.Literal)
+		if !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "message ids must be literals, got %s", sqlparser.String(expr))
+		}
+		ids = append(ids, lit.Val)
+	}
+
+	return &engine.RequeueDeadLetters{
+		Keyspace: ks,
+		Table:    stmt.Table.Name.String(),
+		Into:     stmt.Into.Name.String(),
+		IDs:      ids,
+	}, nil
+}
@@ -381,9 +381,15 @@ func modifyForAutoinc(ins *sqlparser.Insert, eins *engine.Insert) error {
 		return nil
 	}
 	colNum := findOrAddColumn(ins, eins.Table.AutoIncrement.Column)
-	eins.Generate = &engine.Generate{
-		Keyspace: eins.Table.AutoIncrement.Sequence.Keyspace,
-		Query:    fmt.Sprintf("select next :n values from %s", sqlparser.String(eins.Table.AutoIncrement.Sequence.Name)),
+	if eins.Table.AutoIncrement.Generator == vindexes.GeneratorSnowflake {
+		eins.Generate = &engine.Generate{Snowflake: true}
+	} else {
+		eins.Generate = &engine.Generate{
+			Keyspace: eins.Table.AutoIncrement.Sequence.Keyspace,
+			Query:    fmt.Sprintf("select next :n values from %s", sqlparser.String(eins.Table.AutoIncrement.Sequence.Name)),
+			Sequence: eins.Table.AutoIncrement.Sequence.Keyspace.Name + "." + eins.Table.AutoIncrement.Sequence.Name.String(),
+			Cache:    eins.Table.AutoIncrement.Cache,
+		}
 	}
 	switch rows := ins.Rows.(type) {
 	case sqlparser.SelectStatement:
@@ -113,6 +113,8 @@ func (r *Route) Cost() int {
 		return 10
 	case engine.MultiEqual:
 		return 10
+	case engine.Between:
+		return 10
 	case engine.Scatter:
 		return 20
 	}
@@ -182,6 +184,9 @@ func (r *Route) searchForNewVindexes(ctx *plancontext.PlanningContext, predicate
 	case *sqlparser.IsExpr:
 		found := r.planIsExpr(ctx, node)
 		newVindexFound = newVindexFound || found
+	case *sqlparser.BetweenExpr:
+		found := r.planBetweenOp(ctx, node)
+		newVindexFound = newVindexFound || found
 	}
 	return newVindexFound, nil
 }
@@ -553,6 +558,41 @@ func (r *Route) planLikeOp(ctx *plancontext.PlanningContext, node *sqlparser.Com
 
 }
 
+func (r *Route) planBetweenOp(ctx *plancontext.PlanningContext, node *sqlparser.BetweenExpr) bool {
+	column, ok := node.Left.(*sqlparser.ColName)
+	if !ok || !node.IsBetween {
+		return false
+	}
+	from := r.makeEvalEngineExpr(ctx, node.From)
+	to := r.makeEvalEngineExpr(ctx, node.To)
+	if from == nil || to == nil {
+		return false
+	}
+
+	newVindexFound := false
+	for _, v := range r.VindexPreds {
+		if !ctx.SemTable.DirectDeps(column).IsSolvedBy(v.TableID) {
+			continue
+		}
+		// only vindexes that can map a range of values are of any use here
+		vindex, ok := v.ColVindex.Vindex.(vindexes.RangeMapper)
+		if !ok || !column.Name.Equal(v.ColVindex.Columns[0]) {
+			continue
+		}
+		v.Options = append(v.Options, &VindexOption{
+			Values:      []evalengine.Expr{from, to},
+			ValueExprs:  []sqlparser.Expr{node.From, node.To},
+			Predicates:  []sqlparser.Expr{node},
+			OpCode:      engine.Between,
+			FoundVindex: vindex,
+			Cost:        costFor(v.ColVindex, engine.Between),
+			Ready:       true,
+		})
+		newVindexFound = true
+	}
+	return newVindexFound
+}
+
 func (r *Route) planCompositeInOpRecursive(
 	ctx *plancontext.PlanningContext,
 	cmp *sqlparser.ComparisonExpr,
@@ -415,6 +415,7 @@ func TestWithDefaultKeyspaceFromFile(t *testing.T) {
 	testFile(t, "alterVschema_cases.txt", testOutputTempDir, vschema)
 	testFile(t, "ddl_cases.txt", testOutputTempDir, vschema)
 	testFile(t, "migration_cases.txt", testOutputTempDir, vschema)
+	testFile(t, "dead_letters_cases.txt", testOutputTempDir, vschema)
 	testFile(t, "flush_cases.txt", testOutputTempDir, vschema)
 	testFile(t, "show_cases.txt", testOutputTempDir, vschema)
 	testFile(t, "call_cases.txt", testOutputTempDir, vschema)
@@ -0,0 +1,225 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package snowflake generates time-ordered, 64-bit unique IDs in vtgate.
+
+An ID is made of, from the most significant bit:
+  - a zero sign bit,
+  - 41 bits of timestamp, in milliseconds since Epoch,
+  - 10 bits of node ID,
+  - 12 bits of sequence number within the millisecond.
+
+Each vtgate that generates IDs leases a node ID from the global topo by
+locking it, so that no two vtgates generate IDs with the same node ID at
+the same time.
+
+IDs of a node ID are also never generated twice over time. The generator
+never moves its timestamp backwards, even if the clock does: it keeps
+generating IDs at the last timestamp it used and, if the sequence runs
+out, moves to the next millisecond ahead of the clock. Before it
+generates IDs at a timestamp, it records in the topo that the node ID is
+reserved until that timestamp, and a little beyond. The next holder of
+the node ID, such as the same vtgate after a restart, only generates IDs
+from the reserved timestamp on. Reservations are compare-and-swap
+updates, so a vtgate that lost its lease without noticing cannot
+reserve timestamps that the next holder uses.
+*/
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// Epoch is the start of snowflake timestamps, 2022-01-01T00:00:00Z,
+	// in milliseconds since the Unix epoch.
+	Epoch = 1640995200000
+
+	nodeBits     = 10
+	sequenceBits = 12
+
+	// MaxNodeID is the largest node ID.
+	MaxNodeID   = 1<<nodeBits - 1
+	maxSequence = 1<<sequenceBits - 1
+)
+
+var (
+	// reserveAhead is how far beyond the current timestamp the generator
+	// reserves timestamps, so that it does not update the topo for
+	// every millisecond.
+	reserveAhead = 10 * time.Second
+
+	// lockTimeout is how long the generator waits for the lock of a node
+	// ID before it tries the next one.
+	lockTimeout = time.Second
+
+	// firstNodeID returns the node ID the generator tries first.
+	firstNodeID = func() int64 { return rand.Int63n(MaxNodeID + 1) }
+)
+
+// Generator generates snowflake IDs. It leases a node ID on first use.
+type Generator struct {
+	ts     *topo.Server
+	holder string
+	now    func() time.Time
+
+	mu sync.Mutex
+	// node is the record of the node ID the generator holds, or nil.
+	node       *topo.SnowflakeNodeInfo
+	lock       topo.LockDescriptor
+	cancelLock context.CancelFunc
+	// last is the timestamp of the last ID, and seq the sequence
+	// number of the next one at that timestamp.
+	last, seq int64
+}
+
+// NewGenerator returns a generator that leases node IDs from ts.
+func NewGenerator(ts *topo.Server) *Generator {
+	hostname, _ := os.Hostname()
+	return &Generator{
+		ts:     ts,
+		holder: fmt.Sprintf("%s:%d", hostname, os.Getpid()),
+		now:    time.Now,
+	}
+}
+
+// Generate returns count new IDs, in increasing order.
+func (g *Generator) Generate(ctx context.Context, count int64) ([]int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.node == nil {
+		if err := g.acquire(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make([]int64, 0, count)
+	for int64(len(ids)) < count {
+		if now := g.now().UnixMilli() - Epoch; now > g.last {
+			g.last, g.seq = now, 0
+		} else if g.seq > maxSequence {
+			// The sequence ran out, or the clock went backwards: borrow
+			// the next millisecond.
+			g.last, g.seq = g.last+1, 0
+		}
+
+		if g.last >= g.node.ReservedUntil {
+			if err := g.reserve(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		ids = append(ids, g.last<<(nodeBits+sequenceBits)|g.node.NodeID<<sequenceBits|g.seq)
+		g.seq++
+	}
+	return ids, nil
+}
+
+// acquire leases a free node ID, trying them from a random one.
+func (g *Generator) acquire(ctx context.Context) error {
+	start := firstNodeID()
+	for i := int64(0); i <= MaxNodeID; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		nodeID := (start + i) % (MaxNodeID + 1)
+
+		// Some topo implementations only keep the lock while its context
+		// is live, so it cannot be a timeout context.
+		lockCtx, cancel := context.WithCancel(context.Background())
+		timer := time.AfterFunc(lockTimeout, cancel)
+		ld, node, err := g.ts.LockSnowflakeNode(lockCtx, nodeID, g.holder)
+		if !timer.Stop() {
+			// Another vtgate holds the node ID.
+			if err == nil {
+				g.unlock(ld)
+			}
+			cancel()
+			continue
+		}
+		if err != nil {
+			cancel()
+			return err
+		}
+
+		log.Infof("Leased snowflake node ID %d, reserved until %d", nodeID, node.ReservedUntil)
+		g.node, g.lock, g.cancelLock = node, ld, cancel
+		// Previous holders may have generated IDs up to the reserved
+		// timestamp, so start from it.
+		g.last, g.seq = node.ReservedUntil, 0
+		return nil
+	}
+	return vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "all %d snowflake node IDs are in use", MaxNodeID+1)
+}
+
+// reserve records in the topo that the node ID is used beyond the current
+// timestamp. If the lease was lost, the node ID is released, and the next
+// call to Generate leases a new one.
+func (g *Generator) reserve(ctx context.Context) error {
+	nodeID := g.node.NodeID
+	if err := g.lock.Check(ctx); err != nil {
+		g.release()
+		return vterrors.Wrapf(err, "lost the lease of snowflake node ID %d", nodeID)
+	}
+
+	g.node.Holder = g.holder
+	g.node.ReservedUntil = g.last + reserveAhead.Milliseconds()
+	if err := g.ts.UpdateSnowflakeNode(ctx, g.node); err != nil {
+		// Re-read the record on the next reservation. If another vtgate
+		// updated it, it has taken the node ID over.
+		g.release()
+		return vterrors.Wrapf(err, "cannot reserve snowflake node ID %d", nodeID)
+	}
+	return nil
+}
+
+// release releases the node ID. The caller must hold mu.
+func (g *Generator) release() {
+	if g.node == nil {
+		return
+	}
+	g.unlock(g.lock)
+	g.cancelLock()
+	g.node, g.lock, g.cancelLock = nil, nil, nil
+}
+
+func (g *Generator) unlock(ld topo.LockDescriptor) {
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	if err := ld.Unlock(ctx); err != nil {
+		log.Warningf("Cannot release snowflake node ID lock: %v", err)
+	}
+}
+
+// Close releases the node ID, if the generator holds one.
+func (g *Generator) Close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.release()
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snowflake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+func init() {
+	lockTimeout = 50 * time.Millisecond
+	firstNodeID = func() int64 { return 0 }
+}
+
+// fakeClock is a clock that only moves when told to.
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func newTestGenerator(ts *topo.Server, clock *fakeClock) *Generator {
+	g := NewGenerator(ts)
+	g.now = clock.now
+	return g
+}
+
+func split(id int64) (timestamp, nodeID, seq int64) {
+	return id >> (nodeBits + sequenceBits), id >> sequenceBits & MaxNodeID, id & maxSequence
+}
+
+func TestGenerate(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("zone1")
+	clock := &fakeClock{t: time.UnixMilli(Epoch + 1000)}
+	g := newTestGenerator(ts, clock)
+	defer g.Close()
+
+	ids, err := g.Generate(ctx, 3)
+	require.NoError(t, err)
+	require.Len(t, ids, 3)
+	for i, id := range ids {
+		timestamp, nodeID, seq := split(id)
+		assert.EqualValues(t, 1000, timestamp)
+		assert.EqualValues(t, 0, nodeID)
+		assert.EqualValues(t, i, seq)
+	}
+
+	node, err := ts.GetSnowflakeNode(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, g.holder, node.Holder)
+	assert.EqualValues(t, 1000+reserveAhead.Milliseconds(), node.ReservedUntil)
+
+	// Running out of sequence numbers moves to the next millisecond.
+	ids, err = g.Generate(ctx, maxSequence)
+	require.NoError(t, err)
+	timestamp, _, seq := split(ids[len(ids)-1])
+	assert.EqualValues(t, 1001, timestamp)
+	assert.EqualValues(t, 1, seq)
+
+	// A second generator leases another node ID.
+	g2 := newTestGenerator(ts, clock)
+	defer g2.Close()
+	ids, err = g2.Generate(ctx, 1)
+	require.NoError(t, err)
+	_, nodeID, _ := split(ids[0])
+	assert.EqualValues(t, 1, nodeID)
+}
+
+func TestGenerateClockBackwards(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("zone1")
+	clock := &fakeClock{t: time.UnixMilli(Epoch + 1000)}
+	g := newTestGenerator(ts, clock)
+	defer g.Close()
+
+	ids, err := g.Generate(ctx, 1)
+	require.NoError(t, err)
+	last := ids[0]
+
+	clock.t = clock.t.Add(-time.Minute)
+	ids, err = g.Generate(ctx, maxSequence+2)
+	require.NoError(t, err)
+	for _, id := range ids {
+		require.Greater(t, id, last)
+		last = id
+	}
+}
+
+func TestGenerateRestart(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("zone1")
+	clock := &fakeClock{t: time.UnixMilli(Epoch + 1000)}
+	g := newTestGenerator(ts, clock)
+
+	ids, err := g.Generate(ctx, 1)
+	require.NoError(t, err)
+	g.Close()
+
+	// After a restart with the clock behind, the same node ID only
+	// generates IDs after the ones it reserved.
+	clock.t = clock.t.Add(-time.Minute)
+	g = newTestGenerator(ts, clock)
+	defer g.Close()
+	ids2, err := g.Generate(ctx, 1)
+	require.NoError(t, err)
+	timestamp, nodeID, _ := split(ids2[0])
+	assert.EqualValues(t, 0, nodeID)
+	assert.EqualValues(t, 1000+reserveAhead.Milliseconds(), timestamp)
+	assert.Greater(t, ids2[0], ids[0])
+}
+
+func TestGenerateTakenOver(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("zone1")
+	clock := &fakeClock{t: time.UnixMilli(Epoch + 1000)}
+	g := newTestGenerator(ts, clock)
+	defer g.Close()
+
+	_, err := g.Generate(ctx, 1)
+	require.NoError(t, err)
+
+	// Another holder takes the node ID over and reserves more time.
+	node, err := ts.GetSnowflakeNode(ctx, 0)
+	require.NoError(t, err)
+	node.Holder = "other"
+	node.ReservedUntil = 1000 * 1000
+	require.NoError(t, ts.UpdateSnowflakeNode(ctx, node))
+
+	// The generator fails to make its next reservation, and gives up
+	// the node ID.
+	clock.t = clock.t.Add(reserveAhead)
+	_, err = g.Generate(ctx, 1)
+	require.Error(t, err)
+
+	// It then leases it again, after the other holder's reservation.
+	ids, err := g.Generate(ctx, 1)
+	require.NoError(t, err)
+	timestamp, nodeID, _ := split(ids[0])
+	assert.EqualValues(t, 0, nodeID)
+	assert.EqualValues(t, 1000*1000, timestamp)
+}
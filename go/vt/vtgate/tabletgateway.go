@@ -20,19 +20,20 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"math/rand"
 	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/balancer"
 	"vitess.io/vitess/go/vt/vtgate/buffer"
 	"vitess.io/vitess/go/vt/vttablet/queryservice"
 
@@ -50,6 +51,15 @@ var (
 	initialTabletTimeout = flag.Duration("gateway_initial_tablet_timeout", 30*time.Second, "At startup, the tabletGateway will wait up to this duration to get at least one tablet per keyspace/shard/tablet type")
 	// retryCount is the number of times a query will be retried on error
 	retryCount = flag.Int("retry-count", 2, "retry count")
+
+	balancerPolicy      = flag.String("gateway_balancer_policy", balancer.Random, "The policy used to choose between the healthy tablets of a target. Allowed values: random, least_outstanding, ewma_latency")
+	balancerMaxLocalLag = flag.Duration("gateway_balancer_max_local_lag", 0, "Tablets in the local cell are preferred over tablets in other cells only if their replication lag is at most this value. 0 means local tablets are always preferred")
+
+	// balancerPruneInterval is how often the balancer forgets the tablets
+	// which left the healthcheck.
+	balancerPruneInterval = time.Minute
+
+	tabletSelections = stats.NewCountersWithMultiLabels("TabletGatewaySelections", "Number of times each tablet was chosen to serve a query", []string{"Keyspace", "ShardName", "DbType", "Tablet"})
 )
 
 // TabletGateway implements the Gateway interface.
@@ -62,6 +72,8 @@ type TabletGateway struct {
 	localCell            string
 	retryCount           int
 	defaultConnCollation uint32
+	balancer             balancer.Policy
+	maxLocalLag          time.Duration
 
 	// mu protects the fields of this group.
 	mu sync.Mutex
@@ -91,18 +103,44 @@ func NewTabletGateway(ctx context.Context, hc discovery.HealthCheck, serv srvtop
 		}
 		hc = createHealthCheck(ctx, *HealthCheckRetryDelay, *HealthCheckTimeout, topoServer, localCell, *CellsToWatch)
 	}
+	policy, err := balancer.New(*balancerPolicy)
+	if err != nil {
+		log.Exitf("Unable to create new TabletGateway: %v", err)
+	}
 	gw := &TabletGateway{
 		hc:                hc,
 		srvTopoServer:     serv,
 		localCell:         localCell,
 		retryCount:        *retryCount,
+		balancer:          policy,
+		maxLocalLag:       *balancerMaxLocalLag,
 		statusAggregators: make(map[string]*TabletStatusAggregator),
 	}
 	gw.setupBuffering(ctx)
+	go gw.pruneBalancer(ctx)
 	gw.QueryService = queryservice.Wrap(nil, gw.withRetry)
 	return gw
 }
 
+// pruneBalancer periodically makes the balancer forget the tablets which left
+// the healthcheck, until ctx is done.
+func (gw *TabletGateway) pruneBalancer(ctx context.Context) {
+	ticker := time.NewTicker(balancerPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			gw.balancer.Prune(func(alias *topodatapb.TabletAlias) bool {
+				_, err := gw.hc.GetTabletHealthByAlias(alias)
+				return err == nil
+			})
+		}
+	}
+}
+
 func (gw *TabletGateway) setupBuffering(ctx context.Context) {
 	cfg := buffer.NewConfigFromFlags()
 	gw.buffer = buffer.New(cfg)
@@ -323,10 +361,13 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 		}
 
 		gw.updateDefaultConnCollation(tabletLastUsed)
+		tabletSelections.Add([]string{target.Keyspace, target.Shard, topoproto.TabletTypeLString(target.TabletType), topoproto.TabletAliasString(tabletLastUsed.Alias)}, 1)
 
 		startTime := time.Now()
+		done := gw.balancer.Begin(tabletLastUsed.Alias)
 		var canRetry bool
 		canRetry, err = inner(ctx, target, th.Conn)
+		done(err)
 		gw.updateStats(target, startTime, err)
 		if canRetry {
 			invalidTablets[topoproto.TabletAliasString(tabletLastUsed.Alias)] = true
@@ -359,6 +400,9 @@ func (gw *TabletGateway) getStatsAggregator(target *querypb.Target) *TabletStatu
 	return aggr
 }
 
+// shuffleTablets orders tablets by preference. Tablets in the given cell whose
+// replication lag is acceptable come first, then the others, and each group is
+// ordered by the balancer policy.
 func (gw *TabletGateway) shuffleTablets(cell string, tablets []*discovery.TabletHealth) {
 	sameCell, diffCell, sameCellMax := 0, 0, -1
 	length := len(tablets)
@@ -384,28 +428,31 @@ func (gw *TabletGateway) shuffleTablets(cell string, tablets []*discovery.Tablet
 		}
 	}
 
-	// shuffle in same cell tablets
-	for i := sameCellMax; i > 0; i-- {
-		swap := rand.Intn(i + 1)
-		tablets[i], tablets[swap] = tablets[swap], tablets[i]
-	}
-
-	// shuffle in diff cell tablets
-	for i, diffCellMin := length-1, sameCellMax+1; i > diffCellMin; i-- {
-		swap := rand.Intn(i-sameCellMax) + diffCellMin
-		tablets[i], tablets[swap] = tablets[swap], tablets[i]
-	}
+	gw.balancer.Sort(tablets[:sameCellMax+1])
+	gw.balancer.Sort(tablets[sameCellMax+1:])
 }
 
 func (gw *TabletGateway) nextTablet(cell string, tablets []*discovery.TabletHealth, offset, length int, sameCell bool) int {
 	for ; offset < length; offset++ {
-		if (tablets[offset].Tablet.Alias.Cell == cell) == sameCell {
+		if gw.isPreferred(cell, tablets[offset]) == sameCell {
 			return offset
 		}
 	}
 	return -1
 }
 
+// isPreferred returns true if the tablet is in the given cell and its
+// replication lag is within gw.maxLocalLag.
+func (gw *TabletGateway) isPreferred(cell string, th *discovery.TabletHealth) bool {
+	if th.Tablet.Alias.Cell != cell {
+		return false
+	}
+	if gw.maxLocalLag == 0 || th.Stats == nil {
+		return true
+	}
+	return time.Duration(th.Stats.ReplicationLagSeconds)*time.Second <= gw.maxLocalLag
+}
+
 // TabletsCacheStatus returns a displayable version of the health check cache.
 func (gw *TabletGateway) TabletsCacheStatus() discovery.TabletsCacheStatusList {
 	return gw.hc.CacheStatus()
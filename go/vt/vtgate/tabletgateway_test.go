@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -31,7 +32,9 @@ import (
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/balancer"
 )
 
 func TestTabletGatewayExecute(t *testing.T) {
@@ -140,6 +143,62 @@ func TestTabletGatewayShuffleTablets(t *testing.T) {
 	}
 }
 
+func TestTabletGatewayShuffleTabletsMaxLocalLag(t *testing.T) {
+	hc := discovery.NewFakeHealthCheck(nil)
+	tg := NewTabletGateway(context.Background(), hc, nil, "local")
+	tg.maxLocalLag = 10 * time.Second
+
+	newTablet := func(uid uint32, cell string, lag uint32) *discovery.TabletHealth {
+		return &discovery.TabletHealth{
+			Tablet:  topo.NewTablet(uid, cell, "host"),
+			Target:  &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA},
+			Serving: true,
+			Stats:   &querypb.RealtimeStats{ReplicationLagSeconds: lag},
+		}
+	}
+	ts1 := newTablet(1, "cell1", 30)
+	ts2 := newTablet(2, "cell1", 5)
+	ts3 := newTablet(3, "cell2", 1)
+
+	// the lagging local tablet is treated like the remote one
+	tablets := []*discovery.TabletHealth{ts1, ts2, ts3}
+	for i := 0; i < 10; i++ {
+		tg.shuffleTablets("cell1", tablets)
+		assert.Equal(t, ts2, tablets[0], "should have the local tablet with low lag in the front, got %+v", tablets)
+		assert.ElementsMatch(t, []*discovery.TabletHealth{ts1, ts3}, tablets[1:])
+	}
+}
+
+func TestTabletGatewayBalancer(t *testing.T) {
+	keyspace := "ks_balancer"
+	target := &querypb.Target{
+		Keyspace:   keyspace,
+		Shard:      "0",
+		TabletType: topodatapb.TabletType_REPLICA,
+	}
+	hc := discovery.NewFakeHealthCheck(nil)
+	tg := NewTabletGateway(context.Background(), hc, nil, "cell")
+	policy, err := balancer.New(balancer.LeastOutstanding)
+	require.NoError(t, err)
+	tg.balancer = policy
+
+	sc1 := hc.AddTestTablet("cell", "1.1.1.1", 1001, keyspace, "0", target.TabletType, true, 10, nil)
+	sc2 := hc.AddTestTablet("cell", "1.1.1.1", 1002, keyspace, "0", target.TabletType, true, 10, nil)
+
+	key := strings.Join([]string{keyspace, "0", "replica", topoproto.TabletAliasString(sc2.Tablet().Alias)}, ".")
+	selections := tabletSelections.Counts()[key]
+
+	// a query in flight on the first tablet sends the next one to the second
+	done := policy.Begin(sc1.Tablet().Alias)
+	_, err = tg.Execute(context.Background(), target, "query", nil, 0, 0, nil)
+	require.NoError(t, err)
+	done(nil)
+	assert.EqualValues(t, 0, sc1.ExecCount.Get())
+	assert.EqualValues(t, 1, sc2.ExecCount.Get())
+
+	assert.EqualValues(t, selections+1, tabletSelections.Counts()[key])
+}
+
 func TestTabletGatewayReplicaTransactionError(t *testing.T) {
 	keyspace := "ks"
 	shard := "0"
@@ -67,6 +67,8 @@ type iExecute interface {
 	ExecuteMessageStream(ctx context.Context, rss []*srvtopo.ResolvedShard, name string, callback func(*sqltypes.Result) error) error
 	ExecuteVStream(ctx context.Context, rss []*srvtopo.ResolvedShard, filter *binlogdatapb.Filter, gtid string, callback func(evs []*binlogdatapb.VEvent) error) error
 	ReleaseLock(ctx context.Context, session *SafeSession) error
+	GenerateSnowflakeIDs(ctx context.Context, count int64) ([]int64, error)
+	RequeueDeadLetters(ctx context.Context, keyspace, table, into string, ids []string) (int64, error)
 
 	showVitessReplicationStatus(ctx context.Context, filter *sqlparser.ShowFilter) (*sqltypes.Result, error)
 	showShards(ctx context.Context, filter *sqlparser.ShowFilter, destTabletType topodatapb.TabletType) (*sqltypes.Result, error)
@@ -550,6 +552,11 @@ func (vc *vcursorImpl) InTransactionAndIsDML() bool {
 	return false
 }
 
+// InTransaction returns true if the session has an open transaction.
+func (vc *vcursorImpl) InTransaction() bool {
+	return vc.safeSession.InTransaction()
+}
+
 func (vc *vcursorImpl) LookupRowLockShardSession() vtgatepb.CommitOrder {
 	switch vc.logStats.StmtType {
 	case "DELETE", "UPDATE":
@@ -967,6 +974,16 @@ func (vc *vcursorImpl) ReleaseLock(ctx context.Context) error {
 	return vc.executor.ReleaseLock(ctx, vc.safeSession)
 }
 
+// GenerateSnowflakeIDs implements the VCursor interface
+func (vc *vcursorImpl) GenerateSnowflakeIDs(ctx context.Context, count int64) ([]int64, error) {
+	return vc.executor.GenerateSnowflakeIDs(ctx, count)
+}
+
+// RequeueDeadLetters implements the VCursor interface
+func (vc *vcursorImpl) RequeueDeadLetters(ctx context.Context, keyspace, table, into string, ids []string) (int64, error) {
+	return vc.executor.RequeueDeadLetters(ctx, keyspace, table, into, ids)
+}
+
 func (vc *vcursorImpl) cloneWithAutocommitSession() *vcursorImpl {
 	safeSession := NewAutocommitSession(vc.safeSession.Session)
 	return &vcursorImpl{
@@ -288,6 +288,7 @@ func (lu *clCommon) handleDup(ctx context.Context, vcursor VCursor, values []sql
 	if err != nil {
 		return err
 	}
+	defer lu.lkp.invalidate([][]sqltypes.Value{values})
 	switch len(qr.Rows) {
 	case 0:
 		if _, err := vcursor.Execute(ctx, "VindexCreate", lu.insertLookupQuery, bindVars, true /* rollbackOnError */, vtgatepb.CommitOrder_PRE); err != nil {
@@ -483,6 +483,10 @@ func (vc *loggingVCursor) InTransactionAndIsDML() bool {
 	return false
 }
 
+func (vc *loggingVCursor) InTransaction() bool {
+	return false
+}
+
 type bv struct {
 	Name string
 	Bv   string
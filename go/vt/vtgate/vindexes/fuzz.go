@@ -90,6 +90,7 @@ var availableVindexes = []string{"binary",
 	"unicode_loose_xxhash",
 	"reverse_bits",
 	"region_json",
+	"range_map",
 	"null"}
 
 // FuzzVindex implements the vindexes fuzzer
@@ -0,0 +1,158 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/cache"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+)
+
+var (
+	lookupCacheHits   = stats.NewCountersWithSingleLabel("LookupVindexCacheHits", "Lookup vindex values served from the vtgate cache", "Table")
+	lookupCacheMisses = stats.NewCountersWithSingleLabel("LookupVindexCacheMisses", "Lookup vindex values that had to be read from the lookup table", "Table")
+)
+
+// lookupCache is an optional LRU cache of the rows of a lookup table,
+// keyed by the value of the first from column. It's enabled by the
+// cache_size param of the lookup vindexes, and its entries expire
+// after the cache_ttl param, 1s by default.
+//
+// The cache is not transactional, so a lookup may be served a value
+// up to cache_ttl after the write that changed it commits:
+//
+//   - Writes that a vtgate makes to the lookup table replace the
+//     entries of the values they write with tombstones, which keep
+//     these values out of the cache for cache_ttl. As the write is
+//     only visible to other sessions once its transaction commits,
+//     this vtgate serves the new rows right away, unless the
+//     transaction stays open for longer than cache_ttl.
+//   - Writes made by other vtgates are only seen once the entries
+//     expire.
+//
+// Values that only compare equal under the collation of the column
+// are cached separately, so a write only invalidates the value it was
+// made with. Values that are not in the lookup table are not cached,
+// so that new rows are seen right away.
+type lookupCache struct {
+	table string
+	ttl   time.Duration
+	now   func() time.Time
+
+	// mu makes checking for a tombstone and caching rows atomic.
+	mu  sync.Mutex
+	lru *cache.LRUCache
+}
+
+// lookupCacheEntry holds the cached rows of a value. Entries without
+// rows are tombstones.
+type lookupCacheEntry struct {
+	rows    [][]sqltypes.Value
+	expires time.Time
+}
+
+const defaultLookupCacheTTL = time.Second
+
+// newLookupCache returns the cache configured by the vindex params,
+// or nil if it's not enabled.
+func newLookupCache(table string, m map[string]string) (*lookupCache, error) {
+	sizeStr, ok := m["cache_size"]
+	if !ok {
+		return nil, nil
+	}
+	size, err := strconv.ParseInt(sizeStr, 10, 64)
+	if err != nil || size < 0 {
+		return nil, fmt.Errorf("cache_size value must be a positive integer: '%s'", sizeStr)
+	}
+	if size == 0 {
+		return nil, nil
+	}
+	ttl := defaultLookupCacheTTL
+	if ttlStr, ok := m["cache_ttl"]; ok {
+		ttl, err = time.ParseDuration(ttlStr)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("cache_ttl value must be a positive duration: '%s'", ttlStr)
+		}
+	}
+	return &lookupCache{
+		table: table,
+		ttl:   ttl,
+		now:   time.Now,
+		lru: cache.NewLRUCache(size, func(any) int64 {
+			return 1
+		}),
+	}, nil
+}
+
+// entry returns the unexpired entry of key, if any.
+func (lc *lookupCache) entry(key string) *lookupCacheEntry {
+	v, ok := lc.lru.Get(key)
+	if !ok {
+		return nil
+	}
+	entry := v.(*lookupCacheEntry)
+	if !lc.now().Before(entry.expires) {
+		lc.lru.Delete(key)
+		return nil
+	}
+	return entry
+}
+
+// get returns the cached rows of id, if any.
+func (lc *lookupCache) get(id sqltypes.Value) ([][]sqltypes.Value, bool) {
+	if entry := lc.entry(id.ToString()); entry != nil && entry.rows != nil {
+		lookupCacheHits.Add(lc.table, 1)
+		return entry.rows, true
+	}
+	lookupCacheMisses.Add(lc.table, 1)
+	return nil, false
+}
+
+// set caches the rows of id that were read from the lookup table,
+// unless id was written to recently.
+func (lc *lookupCache) set(id sqltypes.Value, rows [][]sqltypes.Value) {
+	if len(rows) == 0 {
+		return
+	}
+	key := id.ToString()
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if entry := lc.entry(key); entry != nil && entry.rows == nil {
+		return
+	}
+	lc.lru.Set(key, &lookupCacheEntry{
+		rows:    rows,
+		expires: lc.now().Add(lc.ttl),
+	})
+}
+
+// invalidate replaces the entries of the first column of rowsColValues
+// with tombstones.
+func (lc *lookupCache) invalidate(rowsColValues [][]sqltypes.Value) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	expires := lc.now().Add(lc.ttl)
+	for _, row := range rowsColValues {
+		lc.lru.Set(row[0].ToString(), &lookupCacheEntry{expires: expires})
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+)
+
+func createCachedLookup(t *testing.T, name string, size string) SingleColumn {
+	t.Helper()
+	l, err := CreateVindex(name, name, map[string]string{
+		"table":      "cached_t",
+		"from":       "fromc",
+		"to":         "toc",
+		"cache_size": size,
+		"cache_ttl":  "1m",
+	})
+	require.NoError(t, err)
+	return l.(SingleColumn)
+}
+
+// lookupQueryIDs returns the ids of the lookup queries sent to vc.
+func lookupQueryIDs(t *testing.T, vc *vcursor) [][]string {
+	t.Helper()
+	var ids [][]string
+	for _, query := range vc.queries {
+		var values []string
+		for _, v := range query.BindVariables["fromc"].Values {
+			values = append(values, string(v.Value))
+		}
+		ids = append(ids, values)
+	}
+	vc.queries = nil
+	return ids
+}
+
+func TestLookupCacheMap(t *testing.T) {
+	lookup := createCachedLookup(t, "lookup_unique", "10")
+	now := time.Now()
+	lookup.(*LookupUnique).lkp.cache.now = func() time.Time { return now }
+	vc := &vcursor{numRows: 1}
+	ids := []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(2)}
+	want := []key.Destination{
+		key.DestinationKeyspaceID("1"),
+		key.DestinationNone{},
+	}
+	hits, misses := lookupCacheHits.Counts()["cached_t"], lookupCacheMisses.Counts()["cached_t"]
+
+	got, err := lookup.Map(context.Background(), vc, ids)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, [][]string{{"1", "2"}}, lookupQueryIDs(t, vc))
+
+	// 1 is cached, but 2 is not in the lookup table.
+	got, err = lookup.Map(context.Background(), vc, ids)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, [][]string{{"2"}}, lookupQueryIDs(t, vc))
+	assert.EqualValues(t, 1, lookupCacheHits.Counts()["cached_t"]-hits)
+	assert.EqualValues(t, 3, lookupCacheMisses.Counts()["cached_t"]-misses)
+
+	// Entries expire after the ttl.
+	now = now.Add(time.Minute)
+	_, err = lookup.Map(context.Background(), vc, ids[:1])
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"1"}}, lookupQueryIDs(t, vc))
+
+	// Rows read in a transaction are not cached.
+	now = now.Add(time.Minute)
+	vc.inTx = true
+	_, err = lookup.Map(context.Background(), vc, ids[:1])
+	require.NoError(t, err)
+	vc.inTx = false
+	_, err = lookup.Map(context.Background(), vc, ids[:1])
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"1"}, {"1"}}, lookupQueryIDs(t, vc))
+}
+
+func TestLookupCacheInvalidate(t *testing.T) {
+	lookup := createCachedLookup(t, "lookup", "10")
+	vc := &vcursor{numRows: 1}
+	ids := []sqltypes.Value{sqltypes.NewInt64(1)}
+	mapID := func() {
+		t.Helper()
+		_, err := lookup.Map(context.Background(), vc, ids)
+		require.NoError(t, err)
+	}
+	mapID()
+	mapID()
+	assert.Equal(t, [][]string{{"1"}}, lookupQueryIDs(t, vc))
+
+	lkp := lookup.(Lookup)
+	err := lkp.Create(context.Background(), vc, [][]sqltypes.Value{ids}, [][]byte{[]byte("test")}, false)
+	require.NoError(t, err)
+	vc.queries = nil
+	mapID()
+	assert.Equal(t, [][]string{{"1"}}, lookupQueryIDs(t, vc))
+
+	err = lkp.Delete(context.Background(), vc, [][]sqltypes.Value{ids}, []byte("1"))
+	require.NoError(t, err)
+	vc.queries = nil
+	mapID()
+	assert.Equal(t, [][]string{{"1"}}, lookupQueryIDs(t, vc))
+
+	err = lkp.Update(context.Background(), vc, ids, []byte("1"), []sqltypes.Value{sqltypes.NewInt64(2)})
+	require.NoError(t, err)
+	vc.queries = nil
+	mapID()
+	assert.Equal(t, [][]string{{"1"}}, lookupQueryIDs(t, vc))
+}
+
+func TestLookupCacheParams(t *testing.T) {
+	testcases := []struct {
+		params map[string]string
+		err    string
+	}{{
+		params: map[string]string{"cache_size": "-1"},
+		err:    "cache_size value must be a positive integer: '-1'",
+	}, {
+		params: map[string]string{"cache_size": "10", "cache_ttl": "10"},
+		err:    "cache_ttl value must be a positive duration: '10'",
+	}}
+	for _, tcase := range testcases {
+		tcase.params["table"] = "t"
+		tcase.params["from"] = "fromc"
+		tcase.params["to"] = "toc"
+		_, err := CreateVindex("lookup", "lookup", tcase.params)
+		assert.EqualError(t, err, tcase.err)
+	}
+
+	lookup := createCachedLookup(t, "lookup_hash", "0")
+	assert.Nil(t, lookup.(*LookupHash).lkp.cache)
+	lookup = createCachedLookup(t, "consistent_lookup", "10")
+	assert.NotNil(t, lookup.(*ConsistentLookup).lkp.cache)
+}
+
+func TestLookupCacheTombstones(t *testing.T) {
+	lookup := createCachedLookup(t, "lookup", "10")
+	now := time.Now()
+	lookup.(*LookupNonUnique).lkp.cache.now = func() time.Time { return now }
+	vc := &vcursor{numRows: 1}
+	ids := []sqltypes.Value{sqltypes.NewInt64(1)}
+	mapID := func() {
+		t.Helper()
+		_, err := lookup.Map(context.Background(), vc, ids)
+		require.NoError(t, err)
+	}
+
+	// Until the write is likely committed, lookups in other sessions may
+	// still read the old rows, so they are not cached.
+	err := lookup.(Lookup).Create(context.Background(), vc, [][]sqltypes.Value{ids}, [][]byte{[]byte("test")}, false)
+	require.NoError(t, err)
+	vc.queries = nil
+	mapID()
+	mapID()
+	assert.Equal(t, [][]string{{"1"}, {"1"}}, lookupQueryIDs(t, vc))
+
+	now = now.Add(time.Minute)
+	mapID()
+	mapID()
+	assert.Equal(t, [][]string{{"1"}}, lookupQueryIDs(t, vc))
+}
@@ -44,6 +44,7 @@ type lookupInternal struct {
 	IgnoreNulls          bool     `json:"ignore_nulls,omitempty"`
 	BatchLookup          bool     `json:"batch_lookup,omitempty"`
 	sel, ver, del        string
+	cache                *lookupCache
 }
 
 func (lkp *lookupInternal) Init(lookupQueryParams map[string]string, autocommit, upsert, multiShardAutocommit bool) error {
@@ -64,6 +65,10 @@ func (lkp *lookupInternal) Init(lookupQueryParams map[string]string, autocommit,
 	if err != nil {
 		return err
 	}
+	lkp.cache, err = newLookupCache(lkp.Table, lookupQueryParams)
+	if err != nil {
+		return err
+	}
 
 	lkp.Autocommit = autocommit
 	lkp.Upsert = upsert
@@ -86,6 +91,40 @@ func (lkp *lookupInternal) Lookup(ctx context.Context, vcursor VCursor, ids []sq
 	if vcursor == nil {
 		return nil, fmt.Errorf("cannot perform lookup: no vcursor provided")
 	}
+	// DMLs in a transaction lock the lookup rows, so they can't use the cache.
+	if lkp.cache == nil || vcursor.InTransactionAndIsDML() {
+		return lkp.lookup(ctx, vcursor, ids, co)
+	}
+	results := make([]*sqltypes.Result, len(ids))
+	var missing []sqltypes.Value
+	var missingIdx []int
+	for i, id := range ids {
+		if rows, ok := lkp.cache.get(id); ok {
+			results[i] = &sqltypes.Result{Rows: rows}
+			continue
+		}
+		missing = append(missing, id)
+		missingIdx = append(missingIdx, i)
+	}
+	if len(missing) == 0 {
+		return results, nil
+	}
+	missingResults, err := lkp.lookup(ctx, vcursor, missing, co)
+	if err != nil {
+		return nil, err
+	}
+	// Rows read in a transaction may not be committed yet.
+	cacheable := lkp.Autocommit || !vcursor.InTransaction()
+	for i, result := range missingResults {
+		results[missingIdx[i]] = result
+		if cacheable {
+			lkp.cache.set(missing[i], result.Rows)
+		}
+	}
+	return results, nil
+}
+
+func (lkp *lookupInternal) lookup(ctx context.Context, vcursor VCursor, ids []sqltypes.Value, co vtgatepb.CommitOrder) ([]*sqltypes.Result, error) {
 	results := make([]*sqltypes.Result, 0, len(ids))
 	if lkp.Autocommit {
 		co = vtgatepb.CommitOrder_AUTOCOMMIT
@@ -286,7 +325,9 @@ nextRow:
 		fmt.Fprintf(buf, "%s=values(%s)", lkp.To, lkp.To)
 	}
 
-	if _, err := vcursor.Execute(ctx, "VindexCreate", buf.String(), bindVars, true /* rollbackOnError */, co); err != nil {
+	_, err := vcursor.Execute(ctx, "VindexCreate", buf.String(), bindVars, true /* rollbackOnError */, co)
+	lkp.invalidate(trimmedRowsCols)
+	if err != nil {
 		return fmt.Errorf("lookup.Create: %v", err)
 	}
 	return nil
@@ -328,6 +369,7 @@ func (lkp *lookupInternal) Delete(ctx context.Context, vcursor VCursor, rowsColV
 		}
 		bindVars[lkp.To] = sqltypes.ValueBindVariable(value)
 		_, err := vcursor.Execute(ctx, "VindexDelete", lkp.del, bindVars, true /* rollbackOnError */, co)
+		lkp.invalidate([][]sqltypes.Value{column})
 		if err != nil {
 			return fmt.Errorf("lookup.Delete: %v", err)
 		}
@@ -343,6 +385,14 @@ func (lkp *lookupInternal) Update(ctx context.Context, vcursor VCursor, oldValue
 	return lkp.Create(ctx, vcursor, [][]sqltypes.Value{newValues}, []sqltypes.Value{toValue}, false /* ignoreMode */)
 }
 
+// invalidate keeps the rows that were written to the lookup table out of the
+// cache until their write is likely committed, see lookupCache.
+func (lkp *lookupInternal) invalidate(rowsColValues [][]sqltypes.Value) {
+	if lkp.cache != nil {
+		lkp.cache.invalidate(rowsColValues)
+	}
+}
+
 func (lkp *lookupInternal) initDelStmt() string {
 	var delBuffer bytes.Buffer
 	fmt.Fprintf(&delBuffer, "delete from %s where ", lkp.Table)
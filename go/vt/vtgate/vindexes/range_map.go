@@ -0,0 +1,217 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var (
+	_ SingleColumn = (*RangeMap)(nil)
+	_ RangeMapper  = (*RangeMap)(nil)
+)
+
+func init() {
+	Register("range_map", NewRangeMap)
+}
+
+// idRange is a range of ids, bounds included, and the keyspace id
+// they all map to.
+type idRange struct {
+	from, to uint64
+	ksid     []byte
+}
+
+// RangeMap is a unique vindex that maps ranges of numeric ids to
+// keyspace ids. The ranges are listed in the "ranges" param, as
+// comma-separated from-to:ksid entries, where ksid is in hex:
+//
+//	"ranges": "1-10000:40,10001-20000:80"
+//
+// Since the ranges are part of the VSchema, which is stored in the
+// topo, changing them with ApplyVSchema changes the mapping of all
+// vtgates. Ids that are not in any range map to no keyspace id.
+type RangeMap struct {
+	name   string
+	ranges []idRange
+}
+
+// NewRangeMap creates a RangeMap vindex.
+func NewRangeMap(name string, params map[string]string) (Vindex, error) {
+	spec, ok := params["ranges"]
+	if !ok {
+		return nil, fmt.Errorf("range_map: missing ranges param")
+	}
+	ranges, err := parseIDRanges(spec)
+	if err != nil {
+		return nil, fmt.Errorf("range_map: %v", err)
+	}
+	return &RangeMap{
+		name:   name,
+		ranges: ranges,
+	}, nil
+}
+
+// parseIDRanges parses the ranges param, and returns the ranges
+// sorted by their lower bound.
+func parseIDRanges(spec string) ([]idRange, error) {
+	var ranges []idRange
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		bounds, ksid, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid range %s: must be from-to:keyspace_id", entry)
+		}
+		from, to, ok := strings.Cut(bounds, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid range %s: must be from-to:keyspace_id", entry)
+		}
+		var r idRange
+		var err error
+		if r.from, err = strconv.ParseUint(strings.TrimSpace(from), 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid range %s: %v", entry, err)
+		}
+		if r.to, err = strconv.ParseUint(strings.TrimSpace(to), 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid range %s: %v", entry, err)
+		}
+		if r.from > r.to {
+			return nil, fmt.Errorf("invalid range %s: lower bound is greater than upper bound", entry)
+		}
+		if r.ksid, err = hex.DecodeString(strings.TrimSpace(ksid)); err != nil {
+			return nil, fmt.Errorf("invalid range %s: %v", entry, err)
+		}
+		ranges = append(ranges, r)
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("no ranges")
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].from < ranges[j].from
+	})
+	for i := 1; i < len(ranges); i++ {
+		if ranges[i].from <= ranges[i-1].to {
+			return nil, fmt.Errorf("ranges %d-%d and %d-%d overlap", ranges[i-1].from, ranges[i-1].to, ranges[i].from, ranges[i].to)
+		}
+	}
+	return ranges, nil
+}
+
+// String returns the name of the vindex.
+func (vind *RangeMap) String() string {
+	return vind.name
+}
+
+// Cost returns the cost of this vindex as 1.
+func (*RangeMap) Cost() int {
+	return 1
+}
+
+// IsUnique returns true since the Vindex is unique.
+func (*RangeMap) IsUnique() bool {
+	return true
+}
+
+// NeedsVCursor satisfies the Vindex interface.
+func (*RangeMap) NeedsVCursor() bool {
+	return false
+}
+
+// Map can map ids to key.Destination objects.
+func (vind *RangeMap) Map(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) ([]key.Destination, error) {
+	out := make([]key.Destination, 0, len(ids))
+	for _, id := range ids {
+		ksid := vind.find(id)
+		if ksid == nil {
+			out = append(out, key.DestinationNone{})
+			continue
+		}
+		out = append(out, key.DestinationKeyspaceID(ksid))
+	}
+	return out, nil
+}
+
+// Verify returns true if ids maps to ksids.
+func (vind *RangeMap) Verify(ctx context.Context, vcursor VCursor, ids []sqltypes.Value, ksids [][]byte) ([]bool, error) {
+	out := make([]bool, len(ids))
+	for i, id := range ids {
+		ksid := vind.find(id)
+		out[i] = ksid != nil && bytes.Equal(ksid, ksids[i])
+	}
+	return out, nil
+}
+
+// MapRange returns the keyspace ids of the ranges that overlap with
+// the ids between from and to.
+func (vind *RangeMap) MapRange(ctx context.Context, vcursor VCursor, from, to sqltypes.Value) (key.Destination, error) {
+	if from.IsNull() || to.IsNull() {
+		return key.DestinationNone{}, nil
+	}
+	lo, err := evalengine.ToUint64(from)
+	if err != nil {
+		// Negative or non-integral bounds are not worth the trouble.
+		return key.DestinationAllShards{}, nil
+	}
+	hi, err := evalengine.ToUint64(to)
+	if err != nil {
+		return key.DestinationAllShards{}, nil
+	}
+	if lo > hi {
+		return key.DestinationNone{}, nil
+	}
+	var ksids key.DestinationKeyspaceIDs
+	for i := vind.search(lo); i < len(vind.ranges) && vind.ranges[i].from <= hi; i++ {
+		ksids = append(ksids, vind.ranges[i].ksid)
+	}
+	if len(ksids) == 0 {
+		return key.DestinationNone{}, nil
+	}
+	return ksids, nil
+}
+
+// find returns the keyspace id of id, or nil if it's not in any range.
+func (vind *RangeMap) find(id sqltypes.Value) []byte {
+	num, err := evalengine.ToUint64(id)
+	if err != nil {
+		return nil
+	}
+	i := vind.search(num)
+	if i == len(vind.ranges) || vind.ranges[i].from > num {
+		return nil
+	}
+	return vind.ranges[i].ksid
+}
+
+// search returns the index of the first range whose upper bound is
+// not lower than num.
+func (vind *RangeMap) search(num uint64) int {
+	return sort.Search(len(vind.ranges), func(i int) bool {
+		return vind.ranges[i].to >= num
+	})
+}
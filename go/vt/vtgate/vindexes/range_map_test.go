@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+)
+
+func createRangeMap(t *testing.T) RangeMapper {
+	t.Helper()
+	vindex, err := CreateVindex("range_map", "range_map", map[string]string{
+		"ranges": "20001-30000:c0, 1-10000:40,10001-20000:80",
+	})
+	require.NoError(t, err)
+	return vindex.(RangeMapper)
+}
+
+func TestRangeMapInfo(t *testing.T) {
+	rangeMap := createRangeMap(t)
+	assert.Equal(t, 1, rangeMap.Cost())
+	assert.Equal(t, "range_map", rangeMap.String())
+	assert.True(t, rangeMap.IsUnique())
+	assert.False(t, rangeMap.NeedsVCursor())
+}
+
+func TestRangeMapMap(t *testing.T) {
+	rangeMap := createRangeMap(t)
+	got, err := rangeMap.Map(context.Background(), nil, []sqltypes.Value{
+		sqltypes.NewInt64(1),
+		sqltypes.NewInt64(10000),
+		sqltypes.NewInt64(10001),
+		sqltypes.NewUint64(30000),
+		sqltypes.NewInt64(0),
+		sqltypes.NewInt64(30001),
+		sqltypes.NewInt64(-1),
+		sqltypes.NewVarChar("abcd"),
+		sqltypes.NULL,
+	})
+	require.NoError(t, err)
+	want := []key.Destination{
+		key.DestinationKeyspaceID([]byte{0x40}),
+		key.DestinationKeyspaceID([]byte{0x40}),
+		key.DestinationKeyspaceID([]byte{0x80}),
+		key.DestinationKeyspaceID([]byte{0xc0}),
+		key.DestinationNone{},
+		key.DestinationNone{},
+		key.DestinationNone{},
+		key.DestinationNone{},
+		key.DestinationNone{},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestRangeMapVerify(t *testing.T) {
+	rangeMap := createRangeMap(t)
+	got, err := rangeMap.Verify(context.Background(), nil,
+		[]sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(2), sqltypes.NewInt64(40000)},
+		[][]byte{{0x40}, {0x80}, {0x40}},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false, false}, got)
+}
+
+func TestRangeMapMapRange(t *testing.T) {
+	rangeMap := createRangeMap(t)
+	testcases := []struct {
+		from, to sqltypes.Value
+		want     key.Destination
+	}{{
+		from: sqltypes.NewInt64(5),
+		to:   sqltypes.NewInt64(50),
+		want: key.DestinationKeyspaceIDs{{0x40}},
+	}, {
+		from: sqltypes.NewInt64(0),
+		to:   sqltypes.NewInt64(10001),
+		want: key.DestinationKeyspaceIDs{{0x40}, {0x80}},
+	}, {
+		from: sqltypes.NewInt64(10000),
+		to:   sqltypes.NewInt64(100000),
+		want: key.DestinationKeyspaceIDs{{0x40}, {0x80}, {0xc0}},
+	}, {
+		from: sqltypes.NewInt64(30001),
+		to:   sqltypes.NewInt64(40000),
+		want: key.DestinationNone{},
+	}, {
+		from: sqltypes.NewInt64(50),
+		to:   sqltypes.NewInt64(5),
+		want: key.DestinationNone{},
+	}, {
+		from: sqltypes.NULL,
+		to:   sqltypes.NewInt64(5),
+		want: key.DestinationNone{},
+	}, {
+		from: sqltypes.NewInt64(-5),
+		to:   sqltypes.NewInt64(5),
+		want: key.DestinationAllShards{},
+	}}
+	for _, tcase := range testcases {
+		got, err := rangeMap.MapRange(context.Background(), nil, tcase.from, tcase.to)
+		require.NoError(t, err)
+		assert.Equal(t, tcase.want, got, "%v-%v", tcase.from, tcase.to)
+	}
+}
+
+func TestRangeMapBadRanges(t *testing.T) {
+	testcases := []struct {
+		ranges string
+		err    string
+	}{{
+		ranges: "",
+		err:    "range_map: no ranges",
+	}, {
+		ranges: "1-10",
+		err:    "range_map: invalid range 1-10: must be from-to:keyspace_id",
+	}, {
+		ranges: "10:40",
+		err:    "range_map: invalid range 10:40: must be from-to:keyspace_id",
+	}, {
+		ranges: "10-1:40",
+		err:    "range_map: invalid range 10-1:40: lower bound is greater than upper bound",
+	}, {
+		ranges: "1-10:zz",
+		err:    "range_map: invalid range 1-10:zz: encoding/hex: invalid byte: U+007A 'z'",
+	}, {
+		ranges: "1-10:40,10-20:80",
+		err:    "range_map: ranges 1-10 and 10-20 overlap",
+	}}
+	for _, tcase := range testcases {
+		_, err := CreateVindex("range_map", "range_map", map[string]string{"ranges": tcase.ranges})
+		assert.EqualError(t, err, tcase.err, tcase.ranges)
+	}
+	_, err := CreateVindex("range_map", "range_map", nil)
+	assert.EqualError(t, err, "range_map: missing ranges param")
+}
@@ -39,6 +39,7 @@ type VCursor interface {
 	Execute(ctx context.Context, method string, query string, bindvars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error)
 	ExecuteKeyspaceID(ctx context.Context, keyspace string, ksid []byte, query string, bindVars map[string]*querypb.BindVariable, rollbackOnError, autocommit bool) (*sqltypes.Result, error)
 	InTransactionAndIsDML() bool
+	InTransaction() bool
 	LookupRowLockShardSession() vtgatepb.CommitOrder
 }
 
@@ -117,6 +118,16 @@ type Prefixable interface {
 	PrefixVindex() SingleColumn
 }
 
+// A RangeMapper vindex can map a range of ids to the keyspace
+// ids of all the ids in that range. It's being used to reduce
+// the fan out for 'BETWEEN' expressions.
+type RangeMapper interface {
+	SingleColumn
+	// MapRange returns the destination of all the ids between from
+	// and to, inclusive.
+	MapRange(ctx context.Context, vcursor VCursor, from, to sqltypes.Value) (key.Destination, error)
+}
+
 // A Lookup vindex is one that needs to lookup
 // a previously stored map to compute the keyspace
 // id from an id. This means that the creation of
@@ -180,10 +180,24 @@ func (ks *KeyspaceSchema) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// Auto-increment generators.
+const (
+	// GeneratorSequence generates values from a sequence table.
+	GeneratorSequence = "sequence"
+	// GeneratorSnowflake generates snowflake IDs in vtgate.
+	GeneratorSnowflake = "snowflake"
+)
+
 // AutoIncrement contains the auto-inc information for a table.
 type AutoIncrement struct {
 	Column   sqlparser.IdentifierCI `json:"column"`
 	Sequence *Table                 `json:"sequence"`
+	// Cache is the number of values vtgate fetches from the
+	// sequence at a time and hands out locally.
+	Cache int64 `json:"cache,omitempty"`
+	// Generator is GeneratorSnowflake for snowflake IDs, in
+	// which case Sequence is nil. It is empty for sequences.
+	Generator string `json:"generator,omitempty"`
 }
 
 // BuildVSchema builds a VSchema from a SrvVSchema.
@@ -409,6 +423,26 @@ func resolveAutoIncrement(source *vschemapb.SrvVSchema, vschema *VSchema) {
 			if t == nil || table.AutoIncrement == nil {
 				continue
 			}
+			switch table.AutoIncrement.Generator {
+			case "", GeneratorSequence:
+			case GeneratorSnowflake:
+				if table.AutoIncrement.Sequence != "" {
+					delete(ksvschema.Tables, tname)
+					delete(vschema.uniqueTables, tname)
+					ksvschema.Error = fmt.Errorf("auto_increment of table %s cannot have both a sequence and a %s generator", tname, GeneratorSnowflake)
+					continue
+				}
+				t.AutoIncrement = &AutoIncrement{
+					Column:    sqlparser.NewIdentifierCI(table.AutoIncrement.Column),
+					Generator: GeneratorSnowflake,
+				}
+				continue
+			default:
+				delete(ksvschema.Tables, tname)
+				delete(vschema.uniqueTables, tname)
+				ksvschema.Error = fmt.Errorf("unknown auto_increment generator %s for table %s", table.AutoIncrement.Generator, tname)
+				continue
+			}
 			seqks, seqtab, err := sqlparser.ParseTable(table.AutoIncrement.Sequence)
 			var seq *Table
 			if err == nil {
@@ -424,6 +458,7 @@ func resolveAutoIncrement(source *vschemapb.SrvVSchema, vschema *VSchema) {
 			t.AutoIncrement = &AutoIncrement{
 				Column:   sqlparser.NewIdentifierCI(table.AutoIncrement.Column),
 				Sequence: seq,
+				Cache:    table.AutoIncrement.Cache,
 			}
 		}
 	}
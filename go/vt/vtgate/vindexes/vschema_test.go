@@ -1948,6 +1948,7 @@ func TestSequence(t *testing.T) {
 						AutoIncrement: &vschemapb.AutoIncrement{
 							Column:   "c2",
 							Sequence: "`unsharded`.`seq`",
+							Cache:    100,
 						},
 					},
 				},
@@ -2016,6 +2017,7 @@ func TestSequence(t *testing.T) {
 		AutoIncrement: &AutoIncrement{
 			Column:   sqlparser.NewIdentifierCI("c2"),
 			Sequence: seq,
+			Cache:    100,
 		},
 	}
 	t2.Ordered = []*ColumnVindex{
@@ -2176,6 +2178,87 @@ func TestBadSequenceName(t *testing.T) {
 	}
 }
 
+func TestSnowflakeGenerator(t *testing.T) {
+	table := func(autoInc *vschemapb.AutoIncrement) *vschemapb.Table {
+		return &vschemapb.Table{
+			ColumnVindexes: []*vschemapb.ColumnVindex{{
+				Column: "c1",
+				Name:   "stfu1",
+			}},
+			AutoIncrement: autoInc,
+		}
+	}
+	source := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"unsharded": {
+				Tables: map[string]*vschemapb.Table{
+					"seq": {
+						Type: "sequence",
+					},
+				},
+			},
+			"sharded": {
+				Sharded: true,
+				Vindexes: map[string]*vschemapb.Vindex{
+					"stfu1": {
+						Type: "stfu",
+					},
+				},
+				Tables: map[string]*vschemapb.Table{
+					"t1": table(&vschemapb.AutoIncrement{
+						Column:    "c1",
+						Generator: "snowflake",
+					}),
+					"t2": table(&vschemapb.AutoIncrement{
+						Column:   "c1",
+						Sequence: "seq",
+					}),
+					"t3": table(&vschemapb.AutoIncrement{
+						Column:    "c1",
+						Sequence:  "seq",
+						Generator: "sequence",
+					}),
+				},
+			},
+		},
+	}
+	got := BuildVSchema(&source)
+	ks := got.Keyspaces["sharded"]
+	require.NoError(t, ks.Error)
+	assert.Equal(t, &AutoIncrement{
+		Column:    sqlparser.NewIdentifierCI("c1"),
+		Generator: GeneratorSnowflake,
+	}, ks.Tables["t1"].AutoIncrement)
+	assert.Empty(t, ks.Tables["t2"].AutoIncrement.Generator)
+	assert.Equal(t, "seq", ks.Tables["t2"].AutoIncrement.Sequence.Name.String())
+	assert.Equal(t, "seq", ks.Tables["t3"].AutoIncrement.Sequence.Name.String())
+
+	testcases := []struct {
+		autoInc *vschemapb.AutoIncrement
+		err     string
+	}{{
+		autoInc: &vschemapb.AutoIncrement{
+			Column:    "c1",
+			Sequence:  "seq",
+			Generator: "snowflake",
+		},
+		err: "auto_increment of table t1 cannot have both a sequence and a snowflake generator",
+	}, {
+		autoInc: &vschemapb.AutoIncrement{
+			Column:    "c1",
+			Generator: "uuid",
+		},
+		err: "unknown auto_increment generator uuid for table t1",
+	}}
+	for _, tcase := range testcases {
+		source.Keyspaces["sharded"].Tables = map[string]*vschemapb.Table{"t1": table(tcase.autoInc)}
+		got := BuildVSchema(&source)
+		ks := got.Keyspaces["sharded"]
+		assert.EqualError(t, ks.Error, tcase.err)
+		assert.Nil(t, ks.Tables["t1"], "table t1 must not be present in the keyspace")
+	}
+}
+
 func TestBadShardedSequence(t *testing.T) {
 	bad := vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
@@ -0,0 +1,247 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vschemadiff computes the changes between two VSchemas of a keyspace,
+// and validates a keyspace VSchema against the schema of its tables.
+package vschemadiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+// ChangeType is the kind of change to an entity of a VSchema.
+type ChangeType string
+
+const (
+	AddChangeType    ChangeType = "add"
+	RemoveChangeType ChangeType = "remove"
+	ModifyChangeType ChangeType = "modify"
+)
+
+// EntityType is the kind of entity of a VSchema.
+type EntityType string
+
+const (
+	KeyspaceEntityType EntityType = "keyspace"
+	VindexEntityType   EntityType = "vindex"
+	TableEntityType    EntityType = "table"
+)
+
+// Change is a change to a single entity of a keyspace VSchema.
+type Change struct {
+	Type   ChangeType
+	Entity EntityType
+	// Name is the name of the vindex or table. It is empty for keyspace changes.
+	Name string
+	// Details lists the attributes of a modified entity that change, one
+	// per entry, e.g. `type: "hash" -> "xxhash"`.
+	Details []string
+}
+
+// String returns a one line description of the change.
+func (c *Change) String() string {
+	var b strings.Builder
+	b.WriteString(string(c.Type))
+	b.WriteString(" ")
+	b.WriteString(string(c.Entity))
+	if c.Name != "" {
+		b.WriteString(" ")
+		b.WriteString(c.Name)
+	}
+	if len(c.Details) > 0 {
+		b.WriteString(": ")
+		b.WriteString(strings.Join(c.Details, ", "))
+	}
+	return b.String()
+}
+
+// Diff returns the changes that turn the "from" VSchema into the "to" VSchema.
+// Keyspace changes come first, then vindex changes, then table changes, each
+// sorted by name. A nil VSchema is the same as an empty one.
+func Diff(from, to *vschemapb.Keyspace) []*Change {
+	if from == nil {
+		from = &vschemapb.Keyspace{}
+	}
+	if to == nil {
+		to = &vschemapb.Keyspace{}
+	}
+
+	var changes []*Change
+	var details []string
+	details = appendBoolDetail(details, "sharded", from.Sharded, to.Sharded)
+	details = appendBoolDetail(details, "require_explicit_routing", from.RequireExplicitRouting, to.RequireExplicitRouting)
+	if len(details) > 0 {
+		changes = append(changes, &Change{Type: ModifyChangeType, Entity: KeyspaceEntityType, Details: details})
+	}
+
+	for _, name := range unionKeys(from.Vindexes, to.Vindexes) {
+		fromVindex, inFrom := from.Vindexes[name]
+		toVindex, inTo := to.Vindexes[name]
+		switch {
+		case !inFrom:
+			changes = append(changes, &Change{Type: AddChangeType, Entity: VindexEntityType, Name: name})
+		case !inTo:
+			changes = append(changes, &Change{Type: RemoveChangeType, Entity: VindexEntityType, Name: name})
+		default:
+			if details := diffVindex(fromVindex, toVindex); len(details) > 0 {
+				changes = append(changes, &Change{Type: ModifyChangeType, Entity: VindexEntityType, Name: name, Details: details})
+			}
+		}
+	}
+
+	for _, name := range unionKeys(from.Tables, to.Tables) {
+		fromTable, inFrom := from.Tables[name]
+		toTable, inTo := to.Tables[name]
+		switch {
+		case !inFrom:
+			changes = append(changes, &Change{Type: AddChangeType, Entity: TableEntityType, Name: name})
+		case !inTo:
+			changes = append(changes, &Change{Type: RemoveChangeType, Entity: TableEntityType, Name: name})
+		default:
+			if details := diffTable(fromTable, toTable); len(details) > 0 {
+				changes = append(changes, &Change{Type: ModifyChangeType, Entity: TableEntityType, Name: name, Details: details})
+			}
+		}
+	}
+	return changes
+}
+
+// diffVindex returns the details of the changes between two definitions of a vindex.
+func diffVindex(from, to *vschemapb.Vindex) (details []string) {
+	if from == nil {
+		from = &vschemapb.Vindex{}
+	}
+	if to == nil {
+		to = &vschemapb.Vindex{}
+	}
+	details = appendStringDetail(details, "type", from.Type, to.Type)
+	details = appendStringDetail(details, "owner", from.Owner, to.Owner)
+	for _, param := range unionKeys(from.Params, to.Params) {
+		fromValue, inFrom := from.Params[param]
+		toValue, inTo := to.Params[param]
+		switch {
+		case !inFrom:
+			details = append(details, fmt.Sprintf("params.%s: added %q", param, toValue))
+		case !inTo:
+			details = append(details, fmt.Sprintf("params.%s: removed %q", param, fromValue))
+		default:
+			details = appendStringDetail(details, "params."+param, fromValue, toValue)
+		}
+	}
+	return details
+}
+
+// diffTable returns the details of the changes between two definitions of a table.
+func diffTable(from, to *vschemapb.Table) (details []string) {
+	if from == nil {
+		from = &vschemapb.Table{}
+	}
+	if to == nil {
+		to = &vschemapb.Table{}
+	}
+	details = appendStringDetail(details, "type", from.Type, to.Type)
+	details = appendStringDetail(details, "pinned", from.Pinned, to.Pinned)
+	details = appendStringDetail(details, "column_vindexes", columnVindexesString(from.ColumnVindexes), columnVindexesString(to.ColumnVindexes))
+	details = appendStringDetail(details, "auto_increment", autoIncrementString(from.AutoIncrement), autoIncrementString(to.AutoIncrement))
+
+	fromColumns := map[string]*vschemapb.Column{}
+	for _, col := range from.Columns {
+		fromColumns[strings.ToLower(col.Name)] = col
+	}
+	toColumns := map[string]*vschemapb.Column{}
+	for _, col := range to.Columns {
+		toColumns[strings.ToLower(col.Name)] = col
+	}
+	for _, name := range unionKeys(fromColumns, toColumns) {
+		fromCol, inFrom := fromColumns[name]
+		toCol, inTo := toColumns[name]
+		switch {
+		case !inFrom:
+			details = append(details, fmt.Sprintf("columns.%s: added %s", toCol.Name, toCol.Type))
+		case !inTo:
+			details = append(details, fmt.Sprintf("columns.%s: removed", fromCol.Name))
+		case fromCol.Type != toCol.Type:
+			details = append(details, fmt.Sprintf("columns.%s: %s -> %s", toCol.Name, fromCol.Type, toCol.Type))
+		}
+	}
+	details = appendBoolDetail(details, "column_list_authoritative", from.ColumnListAuthoritative, to.ColumnListAuthoritative)
+	return details
+}
+
+// columnVindexesString returns the column vindexes of a table in order, e.g.
+// "hash(id), name_lookup(name)". The order matters, as the first one is the
+// primary vindex.
+func columnVindexesString(columnVindexes []*vschemapb.ColumnVindex) string {
+	strs := make([]string, 0, len(columnVindexes))
+	for _, cv := range columnVindexes {
+		columns := cv.Columns
+		if cv.Column != "" {
+			columns = []string{cv.Column}
+		}
+		strs = append(strs, fmt.Sprintf("%s(%s)", cv.Name, strings.Join(columns, ", ")))
+	}
+	return strings.Join(strs, ", ")
+}
+
+// autoIncrementString returns the auto increment of a table, e.g. "id from user_seq"
+// or "id from snowflake, cache 100".
+func autoIncrementString(autoIncrement *vschemapb.AutoIncrement) string {
+	if autoIncrement == nil {
+		return ""
+	}
+	source := autoIncrement.Sequence
+	if autoIncrement.Generator != "" {
+		source = autoIncrement.Generator
+	}
+	s := fmt.Sprintf("%s from %s", autoIncrement.Column, source)
+	if autoIncrement.Cache != 0 {
+		s = fmt.Sprintf("%s, cache %d", s, autoIncrement.Cache)
+	}
+	return s
+}
+
+func appendStringDetail(details []string, attribute string, from, to string) []string {
+	if from == to {
+		return details
+	}
+	return append(details, fmt.Sprintf("%s: %q -> %q", attribute, from, to))
+}
+
+func appendBoolDetail(details []string, attribute string, from, to bool) []string {
+	if from == to {
+		return details
+	}
+	return append(details, fmt.Sprintf("%s: %t -> %t", attribute, from, to))
+}
+
+// unionKeys returns the keys of both maps, sorted.
+func unionKeys[V any](m1, m2 map[string]V) []string {
+	keys := make([]string, 0, len(m1)+len(m2))
+	for key := range m1 {
+		keys = append(keys, key)
+	}
+	for key := range m2 {
+		if _, ok := m1[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
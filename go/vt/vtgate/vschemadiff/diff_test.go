@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vschemadiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/json2"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func parseKeyspace(t *testing.T, s string) *vschemapb.Keyspace {
+	t.Helper()
+	if s == "" {
+		return nil
+	}
+	ks := &vschemapb.Keyspace{}
+	require.NoError(t, json2.Unmarshal([]byte(s), ks))
+	return ks
+}
+
+func TestDiff(t *testing.T) {
+	tt := []struct {
+		name    string
+		from    string
+		to      string
+		changes []string
+	}{
+		{
+			name: "identical",
+			from: `{"sharded": true, "vindexes": {"hash": {"type": "hash"}}, "tables": {"t1": {"column_vindexes": [{"column": "id", "name": "hash"}]}}}`,
+			to:   `{"sharded": true, "vindexes": {"hash": {"type": "hash"}}, "tables": {"t1": {"column_vindexes": [{"column": "id", "name": "hash"}]}}}`,
+		},
+		{
+			name: "empty to sharded",
+			to:   `{"sharded": true, "vindexes": {"hash": {"type": "hash"}}, "tables": {"t1": {"column_vindexes": [{"column": "id", "name": "hash"}]}}}`,
+			changes: []string{
+				"modify keyspace: sharded: false -> true",
+				"add vindex hash",
+				"add table t1",
+			},
+		},
+		{
+			name: "removals",
+			from: `{"vindexes": {"hash": {"type": "hash"}}, "tables": {"t1": {}, "t2": {}}}`,
+			to:   `{"tables": {"t2": {}}}`,
+			changes: []string{
+				"remove vindex hash",
+				"remove table t1",
+			},
+		},
+		{
+			name: "vindex changes",
+			from: `{"vindexes": {"v": {"type": "lookup_unique", "params": {"table": "ks.v_lookup", "from": "name", "to": "keyspace_id", "write_only": "true"}, "owner": "t1"}}}`,
+			to:   `{"vindexes": {"v": {"type": "consistent_lookup_unique", "params": {"table": "ks.v_lookup2", "from": "name", "to": "keyspace_id", "ignore_nulls": "true"}, "owner": "t2"}}}`,
+			changes: []string{
+				`modify vindex v: type: "lookup_unique" -> "consistent_lookup_unique", owner: "t1" -> "t2", params.ignore_nulls: added "true", params.table: "ks.v_lookup" -> "ks.v_lookup2", params.write_only: removed "true"`,
+			},
+		},
+		{
+			name: "table changes",
+			from: `{"tables": {"t1": {"column_vindexes": [{"column": "id", "name": "hash"}, {"columns": ["a", "b"], "name": "v"}], "auto_increment": {"column": "id", "sequence": "seq"}, "columns": [{"name": "id", "type": "INT64"}, {"name": "a", "type": "VARCHAR"}]}}}`,
+			to:   `{"tables": {"t1": {"column_vindexes": [{"column": "id", "name": "xxhash"}, {"columns": ["a", "b"], "name": "v"}], "auto_increment": {"column": "id", "generator": "snowflake"}, "columns": [{"name": "id", "type": "INT32"}, {"name": "b", "type": "VARCHAR"}], "column_list_authoritative": true}}}`,
+			changes: []string{
+				`modify table t1: column_vindexes: "hash(id), v(a, b)" -> "xxhash(id), v(a, b)", auto_increment: "id from seq" -> "id from snowflake", columns.a: removed, columns.b: added VARCHAR, columns.id: INT64 -> INT32, column_list_authoritative: false -> true`,
+			},
+		},
+		{
+			name: "column vindex order",
+			from: `{"tables": {"t1": {"column_vindexes": [{"column": "id", "name": "hash"}, {"column": "name", "name": "v"}]}}}`,
+			to:   `{"tables": {"t1": {"column_vindexes": [{"column": "name", "name": "v"}, {"column": "id", "name": "hash"}]}}}`,
+			changes: []string{
+				`modify table t1: column_vindexes: "hash(id), v(name)" -> "v(name), hash(id)"`,
+			},
+		},
+		{
+			name: "legacy column and columns",
+			from: `{"tables": {"t1": {"column_vindexes": [{"column": "id", "name": "hash"}]}}}`,
+			to:   `{"tables": {"t1": {"column_vindexes": [{"columns": ["id"], "name": "hash"}]}}}`,
+		},
+	}
+	for _, ts := range tt {
+		t.Run(ts.name, func(t *testing.T) {
+			changes := Diff(parseKeyspace(t, ts.from), parseKeyspace(t, ts.to))
+			var changeStrings []string
+			for _, change := range changes {
+				changeStrings = append(changeStrings, change.String())
+			}
+			assert.Equal(t, ts.changes, changeStrings)
+		})
+	}
+}
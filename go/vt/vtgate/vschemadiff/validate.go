@@ -0,0 +1,267 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vschemadiff
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+// ValidationError is a mistake in a keyspace VSchema.
+type ValidationError struct {
+	Entity EntityType
+	// Name is the name of the vindex or table. It is empty for keyspace errors.
+	Name    string
+	Message string
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("%s: %s", e.Entity, e.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", e.Entity, sqlescape.EscapeID(e.Name), e.Message)
+}
+
+// SchemaFunc returns the schema of the tables of a keyspace. It returns a nil
+// schema when the keyspace does not exist, and an error wrapping
+// ErrSchemaUnavailable when the keyspace exists but its schema cannot be read.
+type SchemaFunc func(keyspace string) (*schemadiff.Schema, error)
+
+// ErrSchemaUnavailable is returned by a SchemaFunc when the schema of a
+// keyspace cannot be read, for example because the keyspace has no primary
+// tablet. Validate then skips the checks that need this schema, and reports a
+// warning instead of failing.
+var ErrSchemaUnavailable = errors.New("schema is not available")
+
+// Validate returns the mistakes in the VSchema of a keyspace that vtgate only
+// detects when it builds the VSchema, or not at all:
+//   - errors of vindexes.BuildKeyspaceSchema(), such as a table using a vindex
+//     that does not exist
+//   - a vindex owned by a table that does not exist, or that does not use it
+//   - an owner on a vindex that is not a lookup vindex
+//
+// When getSchema is not nil, the VSchema is also validated against the schema
+// of the tables:
+//   - the tables of the VSchema exist in the keyspace
+//   - the columns of column vindexes, of the auto increment and of the column
+//     list of a table exist in the table
+//   - the table of a lookup vindex exists, with its "from" and "to" columns.
+//     An unqualified lookup table is looked up in the keyspace itself.
+//
+// The checks that need a schema which is not available are skipped, and
+// reported as warnings. Errors and warnings are sorted by entity type and name.
+func Validate(keyspace string, ks *vschemapb.Keyspace, getSchema SchemaFunc) (validationErrors []*ValidationError, warnings []*ValidationError, err error) {
+	if ks == nil {
+		ks = &vschemapb.Keyspace{}
+	}
+
+	addError := func(entity EntityType, name string, format string, args ...any) {
+		validationErrors = append(validationErrors, &ValidationError{
+			Entity:  entity,
+			Name:    name,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	addWarning := func(entity EntityType, name string, format string, args ...any) {
+		warnings = append(warnings, &ValidationError{
+			Entity:  entity,
+			Name:    name,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if _, err := vindexes.BuildKeyspaceSchema(ks, keyspace); err != nil {
+		addError(KeyspaceEntityType, "", "%v", err)
+	}
+
+	for name, vindex := range ks.Vindexes {
+		if vindex.Owner == "" {
+			continue
+		}
+		if v, err := vindexes.CreateVindex(vindex.Type, name, vindex.Params); err == nil {
+			if _, ok := v.(vindexes.Lookup); !ok {
+				addError(VindexEntityType, name, "owner %s is set, but vindex type %s is not a lookup vindex", sqlescape.EscapeID(vindex.Owner), vindex.Type)
+				continue
+			}
+		}
+		owner, ok := ks.Tables[vindex.Owner]
+		if !ok {
+			addError(VindexEntityType, name, "owner table %s is not in the VSchema", sqlescape.EscapeID(vindex.Owner))
+			continue
+		}
+		if !usesVindex(owner, name) {
+			addError(VindexEntityType, name, "owner table %s does not use the vindex", sqlescape.EscapeID(vindex.Owner))
+		}
+	}
+
+	if getSchema != nil {
+		// keyspaceSchema is the schema of a keyspace, or the reason why it is
+		// not available.
+		type keyspaceSchema struct {
+			schema      *schemadiff.Schema
+			unavailable error
+		}
+		schemas := map[string]*keyspaceSchema{}
+		schemaOf := func(keyspace string) (*keyspaceSchema, error) {
+			if ksSchema, ok := schemas[keyspace]; ok {
+				return ksSchema, nil
+			}
+			schema, err := getSchema(keyspace)
+			if err != nil && !errors.Is(err, ErrSchemaUnavailable) {
+				return nil, err
+			}
+			schemas[keyspace] = &keyspaceSchema{schema: schema, unavailable: err}
+			return schemas[keyspace], nil
+		}
+
+		if len(ks.Tables) > 0 {
+			ksSchema, err := schemaOf(keyspace)
+			if err != nil {
+				return nil, nil, err
+			}
+			switch {
+			case ksSchema.unavailable != nil:
+				addWarning(KeyspaceEntityType, "", "the tables are not validated against the schema of keyspace %s: %v", sqlescape.EscapeID(keyspace), ksSchema.unavailable)
+			case ksSchema.schema != nil:
+				for name, table := range ks.Tables {
+					validateTable(ksSchema.schema, keyspace, name, table, addError)
+				}
+			}
+		}
+
+		for name, vindex := range ks.Vindexes {
+			lookupTable, ok := vindex.Params["table"]
+			if !ok {
+				continue
+			}
+			if v, err := vindexes.CreateVindex(vindex.Type, name, vindex.Params); err != nil {
+				continue
+			} else if _, ok := v.(vindexes.Lookup); !ok {
+				continue
+			}
+
+			lookupKeyspace, lookupTableName := keyspace, lookupTable
+			if i := strings.Index(lookupTable, "."); i >= 0 {
+				lookupKeyspace, lookupTableName = lookupTable[:i], lookupTable[i+1:]
+			}
+			lookupKsSchema, err := schemaOf(lookupKeyspace)
+			if err != nil {
+				return nil, nil, err
+			}
+			if lookupKsSchema.unavailable != nil {
+				addWarning(VindexEntityType, name, "lookup table %s is not validated: %v", lookupTable, lookupKsSchema.unavailable)
+				continue
+			}
+			lookupSchema := lookupKsSchema.schema
+			if lookupSchema == nil {
+				addError(VindexEntityType, name, "keyspace %s of lookup table %s does not exist", sqlescape.EscapeID(lookupKeyspace), lookupTable)
+				continue
+			}
+			t := lookupSchema.Table(lookupTableName)
+			if t == nil {
+				addError(VindexEntityType, name, "lookup table %s is not in the schema of keyspace %s", sqlescape.EscapeID(lookupTableName), sqlescape.EscapeID(lookupKeyspace))
+				continue
+			}
+			columns := tableColumns(t)
+			var lookupColumns []string
+			for _, from := range strings.Split(vindex.Params["from"], ",") {
+				lookupColumns = append(lookupColumns, strings.TrimSpace(from))
+			}
+			lookupColumns = append(lookupColumns, vindex.Params["to"])
+			for _, col := range lookupColumns {
+				if col != "" && !columns[strings.ToLower(col)] {
+					addError(VindexEntityType, name, "column %s is not in lookup table %s", sqlescape.EscapeID(col), lookupTable)
+				}
+			}
+		}
+	}
+
+	sortValidationErrors(validationErrors)
+	sortValidationErrors(warnings)
+	return validationErrors, warnings, nil
+}
+
+// sortValidationErrors sorts validation errors by entity type and name.
+func sortValidationErrors(validationErrors []*ValidationError) {
+	entityOrder := map[EntityType]int{KeyspaceEntityType: 0, VindexEntityType: 1, TableEntityType: 2}
+	sort.SliceStable(validationErrors, func(i, j int) bool {
+		if validationErrors[i].Entity != validationErrors[j].Entity {
+			return entityOrder[validationErrors[i].Entity] < entityOrder[validationErrors[j].Entity]
+		}
+		return validationErrors[i].Name < validationErrors[j].Name
+	})
+}
+
+// validateTable validates a table of a VSchema against the schema of its keyspace.
+func validateTable(schema *schemadiff.Schema, keyspace string, name string, table *vschemapb.Table, addError func(entity EntityType, name string, format string, args ...any)) {
+	t := schema.Table(name)
+	if t == nil {
+		if schema.View(name) == nil {
+			addError(TableEntityType, name, "table is not in the schema of keyspace %s", sqlescape.EscapeID(keyspace))
+		}
+		// we do not validate the columns of views
+		return
+	}
+	columns := tableColumns(t)
+	checkColumn := func(col string, usage string) {
+		if !columns[strings.ToLower(col)] {
+			addError(TableEntityType, name, "column %s of %s is not in the table", sqlescape.EscapeID(col), usage)
+		}
+	}
+	for _, cv := range table.ColumnVindexes {
+		if cv.Column != "" {
+			checkColumn(cv.Column, "vindex "+sqlescape.EscapeID(cv.Name))
+		}
+		for _, col := range cv.Columns {
+			checkColumn(col, "vindex "+sqlescape.EscapeID(cv.Name))
+		}
+	}
+	if table.AutoIncrement != nil {
+		checkColumn(table.AutoIncrement.Column, "the auto increment")
+	}
+	for _, col := range table.Columns {
+		checkColumn(col.Name, "the column list")
+	}
+}
+
+// usesVindex returns true when one of the column vindexes of the table is the given vindex.
+func usesVindex(table *vschemapb.Table, vindex string) bool {
+	for _, cv := range table.ColumnVindexes {
+		if cv.Name == vindex {
+			return true
+		}
+	}
+	return false
+}
+
+// tableColumns returns the lowercase names of the columns of a table.
+func tableColumns(t *schemadiff.CreateTableEntity) map[string]bool {
+	columns := map[string]bool{}
+	for _, col := range t.TableSpec.Columns {
+		columns[col.Name.Lowered()] = true
+	}
+	return columns
+}
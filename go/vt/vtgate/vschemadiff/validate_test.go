@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vschemadiff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/schemadiff"
+)
+
+func TestValidate(t *testing.T) {
+	schemas := map[string]string{
+		"ks": `
+			create table t1 (id bigint, name varchar(64), primary key (id));
+			create table t2 (id bigint, t1_id bigint, primary key (id));
+			create view v1 as select id from t1;
+		`,
+		"lookup": `
+			create table name_lookup (name varchar(64), keyspace_id varbinary(16), primary key (name));
+		`,
+	}
+	getSchema := func(keyspace string) (*schemadiff.Schema, error) {
+		sql, ok := schemas[keyspace]
+		if !ok {
+			return nil, nil
+		}
+		return schemadiff.NewSchemaFromSQL(sql)
+	}
+
+	tt := []struct {
+		name    string
+		vschema string
+		noDB    bool
+		errors  []string
+	}{
+		{
+			name: "valid",
+			vschema: `{
+				"sharded": true,
+				"vindexes": {
+					"hash": {"type": "hash"},
+					"name_lookup": {"type": "consistent_lookup_unique", "params": {"table": "lookup.name_lookup", "from": "name", "to": "keyspace_id"}, "owner": "t1"}
+				},
+				"tables": {
+					"t1": {"column_vindexes": [{"column": "id", "name": "hash"}, {"column": "name", "name": "name_lookup"}], "columns": [{"name": "id", "type": "INT64"}]},
+					"t2": {"column_vindexes": [{"column": "t1_id", "name": "hash"}]},
+					"v1": {"column_vindexes": [{"column": "id", "name": "hash"}]}
+				}
+			}`,
+		},
+		{
+			name: "vindex not found",
+			vschema: `{
+				"sharded": true,
+				"tables": {
+					"t1": {"column_vindexes": [{"column": "id", "name": "hash"}]}
+				}
+			}`,
+			errors: []string{"keyspace: vindex hash not found for table t1"},
+		},
+		{
+			name: "owner mistakes",
+			vschema: `{
+				"sharded": true,
+				"vindexes": {
+					"hash": {"type": "hash", "owner": "t1"},
+					"l1": {"type": "consistent_lookup_unique", "params": {"table": "lookup.name_lookup", "from": "name", "to": "keyspace_id"}, "owner": "t3"},
+					"l2": {"type": "consistent_lookup_unique", "params": {"table": "lookup.name_lookup", "from": "name", "to": "keyspace_id"}, "owner": "t2"}
+				},
+				"tables": {
+					"t1": {"column_vindexes": [{"column": "id", "name": "hash"}]},
+					"t2": {"column_vindexes": [{"column": "t1_id", "name": "hash"}]}
+				}
+			}`,
+			errors: []string{
+				"vindex `hash`: owner `t1` is set, but vindex type hash is not a lookup vindex",
+				"vindex `l1`: owner table `t3` is not in the VSchema",
+				"vindex `l2`: owner table `t2` does not use the vindex",
+			},
+		},
+		{
+			name: "missing tables and columns",
+			vschema: `{
+				"sharded": true,
+				"vindexes": {
+					"hash": {"type": "hash"}
+				},
+				"tables": {
+					"t1": {"column_vindexes": [{"column": "uid", "name": "hash"}], "auto_increment": {"column": "id", "sequence": "seq"}},
+					"t2": {"column_vindexes": [{"column": "id", "name": "hash"}], "columns": [{"name": "t3_id", "type": "INT64"}]},
+					"t3": {"column_vindexes": [{"column": "id", "name": "hash"}]}
+				}
+			}`,
+			errors: []string{
+				"table `t1`: column `uid` of vindex `hash` is not in the table",
+				"table `t2`: column `t3_id` of the column list is not in the table",
+				"table `t3`: table is not in the schema of keyspace `ks`",
+			},
+		},
+		{
+			name: "missing tables and columns without a schema",
+			noDB: true,
+			vschema: `{
+				"sharded": true,
+				"vindexes": {
+					"hash": {"type": "hash"}
+				},
+				"tables": {
+					"t3": {"column_vindexes": [{"column": "id", "name": "hash"}]}
+				}
+			}`,
+		},
+		{
+			name: "lookup table mistakes",
+			vschema: `{
+				"sharded": true,
+				"vindexes": {
+					"hash": {"type": "hash"},
+					"l1": {"type": "lookup_unique", "params": {"table": "lookup.name_lookups", "from": "name", "to": "keyspace_id"}},
+					"l2": {"type": "lookup_unique", "params": {"table": "lookups.name_lookup", "from": "name", "to": "keyspace_id"}},
+					"l3": {"type": "lookup_unique", "params": {"table": "lookup.name_lookup", "from": "nam", "to": "ksid"}},
+					"l4": {"type": "lookup_unique", "params": {"table": "t1", "from": "name", "to": "id"}}
+				},
+				"tables": {
+					"t1": {"column_vindexes": [{"column": "id", "name": "hash"}]}
+				}
+			}`,
+			errors: []string{
+				"vindex `l1`: lookup table `name_lookups` is not in the schema of keyspace `lookup`",
+				"vindex `l2`: keyspace `lookups` of lookup table lookups.name_lookup does not exist",
+				"vindex `l3`: column `nam` is not in lookup table lookup.name_lookup",
+				"vindex `l3`: column `ksid` is not in lookup table lookup.name_lookup",
+			},
+		},
+	}
+	for _, ts := range tt {
+		t.Run(ts.name, func(t *testing.T) {
+			var schemaFunc SchemaFunc = getSchema
+			if ts.noDB {
+				schemaFunc = nil
+			}
+			validationErrors, warnings, err := Validate("ks", parseKeyspace(t, ts.vschema), schemaFunc)
+			require.NoError(t, err)
+			assert.Empty(t, warnings)
+			var errorStrings []string
+			for _, validationError := range validationErrors {
+				errorStrings = append(errorStrings, validationError.Error())
+			}
+			assert.Equal(t, ts.errors, errorStrings)
+		})
+	}
+
+	t.Run("schema error", func(t *testing.T) {
+		_, _, err := Validate("ks", parseKeyspace(t, `{"tables": {"t1": {}}}`), func(keyspace string) (*schemadiff.Schema, error) {
+			return nil, errors.New("tablet is unreachable")
+		})
+		assert.EqualError(t, err, "tablet is unreachable")
+	})
+
+	t.Run("schema not available", func(t *testing.T) {
+		vschema := `{
+			"sharded": true,
+			"vindexes": {
+				"hash": {"type": "hash"},
+				"l1": {"type": "lookup_unique", "params": {"table": "lookup.name_lookup", "from": "name", "to": "keyspace_id"}}
+			},
+			"tables": {
+				"t1": {"column_vindexes": [{"column": "id", "name": "hash"}]}
+			}
+		}`
+		validationErrors, warnings, err := Validate("ks", parseKeyspace(t, vschema), func(keyspace string) (*schemadiff.Schema, error) {
+			return nil, fmt.Errorf("%w: no primary in keyspace %s", ErrSchemaUnavailable, keyspace)
+		})
+		require.NoError(t, err)
+		assert.Empty(t, validationErrors)
+		var warningStrings []string
+		for _, warning := range warnings {
+			warningStrings = append(warningStrings, warning.Error())
+		}
+		assert.Equal(t, []string{
+			"keyspace: the tables are not validated against the schema of keyspace `ks`: schema is not available: no primary in keyspace ks",
+			"vindex `l1`: lookup table lookup.name_lookup is not validated: schema is not available: no primary in keyspace lookup",
+		}, warningStrings)
+	})
+}
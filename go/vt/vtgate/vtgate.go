@@ -294,6 +294,7 @@ func Init(
 		if st != nil && *enableSchemaChangeSignal {
 			st.Stop()
 		}
+		executor.closeSnowflake()
 	})
 	rpcVTGate.registerDebugHealthHandler()
 	rpcVTGate.registerDebugEnvHandler()
@@ -303,6 +304,7 @@ func Init(
 	}
 
 	initAPI(gw.hc)
+	initDeadLettersAPI(executor)
 
 	return rpcVTGate
 }
@@ -19,11 +19,11 @@ package onlineddl
 import (
 	"context"
 	"encoding/json"
-	"strings"
 
 	"vitess.io/vitess/go/mysql"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/schemadiff"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 )
@@ -175,98 +175,17 @@ func analyzeAddRangePartition(alterTable *sqlparser.AlterTable, createTable *sql
 	return op
 }
 
-func alterOptionAvailableViaInstantDDL(alterOption sqlparser.AlterOption, createTable *sqlparser.CreateTable, capableOf mysql.CapableOf) (bool, error) {
-	findColumn := func(colName string) *sqlparser.ColumnDefinition {
-		if createTable == nil {
-			return nil
-		}
-		for _, col := range createTable.TableSpec.Columns {
-			if strings.EqualFold(colName, col.Name.String()) {
-				return col
-			}
-		}
-		return nil
-	}
-	isVirtualColumn := func(colName string) bool {
-		col := findColumn(colName)
-		if col == nil {
-			return false
-		}
-		if col.Type.Options == nil {
-			return false
-		}
-		if col.Type.Options.As == nil {
-			return false
-		}
-		return col.Type.Options.Storage == sqlparser.VirtualStorage
-	}
-	colStringWithoutDefault := func(col *sqlparser.ColumnDefinition) string {
-		colWithoutDefault := sqlparser.CloneRefOfColumnDefinition(col)
-		colWithoutDefault.Type.Options.Default = nil
-		return sqlparser.CanonicalString(colWithoutDefault)
-	}
-	// Up to 8.0.26 we could only ADD COLUMN as last column
-	switch opt := alterOption.(type) {
-	case *sqlparser.AddColumns:
-		if opt.First || opt.After != nil {
-			// not a "last" column. Only supported as of 8.0.29
-			return capableOf(mysql.InstantAddDropColumnFlavorCapability)
-		}
-		// Adding a *last* column is supported in 8.0
-		return capableOf(mysql.InstantAddLastColumnFlavorCapability)
-	case *sqlparser.DropColumn:
-		if isVirtualColumn(opt.Name.Name.String()) {
-			// supported by all 8.0 versions
-			return capableOf(mysql.InstantAddDropVirtualColumnFlavorCapability)
-		}
-		return capableOf(mysql.InstantAddDropColumnFlavorCapability)
-	case *sqlparser.ModifyColumn:
-		if col := findColumn(opt.NewColDefinition.Name.String()); col != nil {
-			// Check if only diff is change of default
-			// we temporarily remove the DEFAULT expression (if any) from both
-			// table and ALTER statement, and compare the columns: if they're otherwise equal,
-			// then the only change can be an addition/change/removal of DEFAULT, which
-			// is instant-table.
-			tableColDefinition := colStringWithoutDefault(col)
-			newColDefinition := colStringWithoutDefault(opt.NewColDefinition)
-			if tableColDefinition == newColDefinition {
-				return capableOf(mysql.InstantChangeColumnDefaultFlavorCapability)
-			}
-		}
-		return false, nil
-	default:
-		return false, nil
-	}
-}
-
 // AnalyzeInstantDDL takes declarative CreateTable and AlterTable, as well as a server version, and checks whether it is possible to run the ALTER
 // using ALGORITM=INSTANT for that version.
 // This function is INTENTIONALLY public, even though we do not guarantee that it will remain so.
 func AnalyzeInstantDDL(alterTable *sqlparser.AlterTable, createTable *sqlparser.CreateTable, capableOf mysql.CapableOf) (*SpecialAlterPlan, error) {
-	capable, err := capableOf(mysql.InstantDDLFlavorCapability)
+	capable, err := schemadiff.AlterTableCapableOfInstantDDL(alterTable, createTable, capableOf)
 	if err != nil {
 		return nil, err
 	}
 	if !capable {
 		return nil, nil
 	}
-	if alterTable.PartitionOption != nil {
-		// no INSTANT for partitions
-		return nil, nil
-	}
-	if alterTable.PartitionSpec != nil {
-		// no INSTANT for partitions
-		return nil, nil
-	}
-	for _, alterOption := range alterTable.AlterOptions {
-		instantOK, err := alterOptionAvailableViaInstantDDL(alterOption, createTable, capableOf)
-		if err != nil {
-			return nil, err
-		}
-		if !instantOK {
-			return nil, nil
-		}
-	}
 	op := NewSpecialAlterOperation(instantDDLSpecialOperation, alterTable, createTable)
 	return op, nil
 }
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package deadletter builds the queries for dead-lettered messages. They are
+// shared by the messager, which dead-letters messages on the tablets, and by
+// vtgate, which lists and requeues them.
+//
+// A dead-lettered message has neither a time_acked nor a time_next, and is
+// either in its message table or in the table's vt_dead_letter_table.
+package deadletter
+
+import (
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// MaxListLimit is the maximum number of dead letters listed by a query built
+// by BuildListQuery.
+const MaxListLimit = 1000
+
+// BuildMarkQueries builds the queries that dead-letter the messages with the
+// ids in ::ids, to be run in a single transaction. The messages are marked
+// failed by clearing their time_next and, if deadLetterTable is set, then
+// moved to it.
+func BuildMarkQueries(table sqlparser.IdentifierCS, deadLetterTable string) []*sqlparser.ParsedQuery {
+	queries := []*sqlparser.ParsedQuery{
+		sqlparser.BuildParsedQuery(
+			"update %v set time_next = null where id in %a and time_acked is null",
+			table, "::ids"),
+	}
+	if deadLetterTable == "" {
+		return queries
+	}
+
+	dlt := sqlparser.NewIdentifierCS(deadLetterTable)
+	return append(queries,
+		sqlparser.BuildParsedQuery(
+			"insert into %v select * from %v where id in %a and time_acked is null and time_next is null",
+			dlt, table, "::ids"),
+		sqlparser.BuildParsedQuery(
+			"delete from %v where id in %a and time_acked is null and time_next is null",
+			table, "::ids"),
+	)
+}
+
+// BuildListQuery builds the query that lists at most :limit dead letters of
+// table, which is a message table or a dead letter table, in id order. If
+// after is true, only the dead letters with an id greater than :after are
+// listed, to read the page that follows the one ending with that id.
+func BuildListQuery(table sqlparser.IdentifierCS, after bool) *sqlparser.ParsedQuery {
+	if after {
+		return sqlparser.BuildParsedQuery(
+			"select * from %v where time_acked is null and time_next is null and id > %a order by id asc limit %a",
+			table, ":after", ":limit")
+	}
+	return sqlparser.BuildParsedQuery(
+		"select * from %v where time_acked is null and time_next is null order by id asc limit %a",
+		table, ":limit")
+}
+
+// BuildRequeueQueries builds the queries that schedule the dead letters with
+// the ids in ::ids for delivery at :time_now, and reset their attempt count,
+// to be run in a single transaction. If into is set, table is a dead letter
+// table, and the messages are moved back to the message table into.
+func BuildRequeueQueries(table, into sqlparser.IdentifierCS) []*sqlparser.ParsedQuery {
+	if into.IsEmpty() {
+		return []*sqlparser.ParsedQuery{
+			sqlparser.BuildParsedQuery(
+				"update %v set time_next = %a, epoch = 0 where id in %a and time_acked is null and time_next is null",
+				table, ":time_now", "::ids"),
+		}
+	}
+
+	return []*sqlparser.ParsedQuery{
+		sqlparser.BuildParsedQuery(
+			"insert into %v select * from %v where id in %a and time_acked is null and time_next is null",
+			into, table, "::ids"),
+		sqlparser.BuildParsedQuery(
+			"update %v set time_next = %a, epoch = 0 where id in %a and time_acked is null and time_next is null",
+			into, ":time_now", "::ids"),
+		sqlparser.BuildParsedQuery(
+			"delete from %v where id in %a and time_acked is null and time_next is null",
+			table, "::ids"),
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"strings"
 	"sync"
 	"time"
 
@@ -35,6 +36,7 @@ import (
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/messager/deadletter"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 )
@@ -51,6 +53,7 @@ type QueryGenerator interface {
 	GenerateAckQuery(ids []string) (string, map[string]*querypb.BindVariable)
 	GeneratePostponeQuery(ids []string) (string, map[string]*querypb.BindVariable)
 	GeneratePurgeQuery(timeCutoff int64) (string, map[string]*querypb.BindVariable)
+	GenerateDeadLetterQueries(ids []string) ([]string, map[string]*querypb.BindVariable)
 }
 
 type messageReceiver struct {
@@ -164,6 +167,24 @@ type receiverWithStatus struct {
 // The Purge thread
 // This thread is mostly independent. It wakes up periodically
 // to delete old rows that were successfully acked.
+//
+// Dead letters
+// If the table sets a maximum number of attempts, a message that
+// comes up for sending after having been sent that many times
+// without an ack is not sent again. Instead, it is marked failed by
+// clearing its time_next, and optionally moved to a dead letter
+// table. Failed messages are neither polled nor purged.
+//
+// Message groups
+// If the table has a group column, the poller only loads the first
+// pending message of each group: the one that was already sent, if
+// any, or else the one with the lowest time_next and id. The next
+// message of a group is therefore not loaded until the previous one
+// is acked or dead-lettered, while messages of different groups are
+// sent in parallel. Since the vstream cannot tell whether a message
+// is first in its group, it does not add grouped messages to the
+// cache. Instead, it triggers the poller whenever a grouped message
+// changes.
 type messageManager struct {
 	tsv TabletService
 	vs  VStreamer
@@ -174,11 +195,16 @@ type messageManager struct {
 	purgeAfter   time.Duration
 	minBackoff   time.Duration
 	maxBackoff   time.Duration
+	maxAttempts  int
 	batchSize    int
 	pollerTicks  *timer.Timer
 	purgeTicks   *timer.Timer
 	postponeSema *sync2.Semaphore
 
+	// groupIdx is the position of the group column in the vstream
+	// rows, or -1 if messages are not grouped.
+	groupIdx int
+
 	mu     sync.Mutex
 	isOpen bool
 	// cond waits on curReceiver == -1 || cache.IsEmpty():
@@ -223,6 +249,11 @@ type messageManager struct {
 	// will be picked up during the next poller run.
 	lastPollPosition *mysql.Position
 
+	// pollRequested is set when the vstream has triggered the poller,
+	// and reset when the poller runs. It prevents the vstream from
+	// piling up triggers while the poller is busy.
+	pollRequested sync2.AtomicBool
+
 	// wg is for ensuring all running goroutines have returned
 	// before we can close the manager. You need to Add before
 	// launching any gorooutine while holding a lock on mu.
@@ -234,6 +265,7 @@ type messageManager struct {
 	ackQuery                  *sqlparser.ParsedQuery
 	postponeQuery             *sqlparser.ParsedQuery
 	purgeQuery                *sqlparser.ParsedQuery
+	deadLetterQueries         []*sqlparser.ParsedQuery
 }
 
 // newMessageManager creates a new message manager.
@@ -251,7 +283,9 @@ func newMessageManager(tsv TabletService, vs VStreamer, table *schema.Table, pos
 		purgeAfter:      table.MessageInfo.PurgeAfterDuration,
 		minBackoff:      table.MessageInfo.MinBackoff,
 		maxBackoff:      table.MessageInfo.MaxBackoff,
+		maxAttempts:     table.MessageInfo.MaxAttempts,
 		batchSize:       table.MessageInfo.BatchSize,
+		groupIdx:        -1,
 		cache:           newCache(table.MessageInfo.CacheSize),
 		pollerTicks:     timer.NewTimer(table.MessageInfo.PollInterval),
 		purgeTicks:      timer.NewTimer(table.MessageInfo.PollInterval),
@@ -268,11 +302,16 @@ func newMessageManager(tsv TabletService, vs VStreamer, table *schema.Table, pos
 			Filter: vsQuery,
 		}},
 	}
-	mm.readByPriorityAndTimeNext = sqlparser.BuildParsedQuery(
-		// There should be a poller_idx defined on (time_acked, priority, time_next desc)
-		// for this to be as effecient as possible
-		"select priority, time_next, epoch, time_acked, %s from %v where time_acked is null and time_next < %a order by priority, time_next desc limit %a",
-		columnList, mm.name, ":time_next", ":max")
+	if groupCol := table.MessageInfo.GroupColumn; groupCol != "" {
+		for i, field := range table.MessageInfo.Fields {
+			if strings.EqualFold(field.Name, groupCol) {
+				// The vstream rows start with priority, time_next, epoch and time_acked.
+				mm.groupIdx = i + 4
+				break
+			}
+		}
+	}
+	mm.readByPriorityAndTimeNext = buildReadQuery(mm.name, columnList, table.MessageInfo.GroupColumn)
 	mm.ackQuery = sqlparser.BuildParsedQuery(
 		"update %v set time_acked = %a, time_next = null where id in %a and time_acked is null",
 		mm.name, ":time_acked", "::ids")
@@ -280,10 +319,40 @@ func newMessageManager(tsv TabletService, vs VStreamer, table *schema.Table, pos
 		"delete from %v where time_acked < %a limit 500", mm.name, ":time_acked")
 
 	mm.postponeQuery = buildPostponeQuery(mm.name, mm.minBackoff, mm.maxBackoff)
+	mm.deadLetterQueries = deadletter.BuildMarkQueries(mm.name, table.MessageInfo.DeadLetterTable)
 
 	return mm
 }
 
+// buildReadQuery builds the poller query. If there is a group column,
+// messages that come after another pending message of their group are
+// left out. A pending message that was already sent comes first,
+// otherwise messages are ordered by time_next and id.
+func buildReadQuery(name sqlparser.IdentifierCS, columnList, groupColumn string) *sqlparser.ParsedQuery {
+	if groupColumn == "" {
+		return sqlparser.BuildParsedQuery(
+			// There should be a poller_idx defined on (time_acked, priority, time_next desc)
+			// for this to be as effecient as possible
+			"select priority, time_next, epoch, time_acked, %s from %v where time_acked is null and time_next < %a order by priority, time_next desc limit %a",
+			columnList, name, ":time_next", ":max")
+	}
+
+	// There should also be an index on (group column, time_acked)
+	// for the subquery to be effecient.
+	group := sqlparser.NewIdentifierCI(groupColumn)
+	return sqlparser.BuildParsedQuery(
+		"select priority, time_next, epoch, time_acked, %s from %v where time_acked is null and time_next < %a "+
+			"and not exists (select 1 from %v as prev where prev.%v = %v.%v and prev.time_acked is null and prev.time_next is not null "+
+			"and (ifnull(prev.epoch, 0) > ifnull(%v.epoch, 0) or ifnull(prev.epoch, 0) = ifnull(%v.epoch, 0) "+
+			"and (prev.time_next < %v.time_next or prev.time_next = %v.time_next and prev.id < %v.id))) "+
+			"order by priority, time_next desc limit %a",
+		columnList, name, ":time_next",
+		name, group, name, group,
+		name, name,
+		name, name, name,
+		":max")
+}
+
 func buildPostponeQuery(name sqlparser.IdentifierCS, minBackoff, maxBackoff time.Duration) *sqlparser.ParsedQuery {
 	var args []any
 
@@ -547,11 +616,17 @@ func (mm *messageManager) runSend() {
 
 			// Fetch rows from cache.
 			lateCount := int64(0)
+			var deadIDs []string
 			for i := 0; i < mm.batchSize; i++ {
 				mr := mm.cache.Pop()
 				if mr == nil {
 					break
 				}
+				if mm.maxAttempts > 0 && mr.Epoch >= int64(mm.maxAttempts) {
+					// The message was already sent maxAttempts times.
+					deadIDs = append(deadIDs, mr.Row[0].ToString())
+					continue
+				}
 				if mr.Epoch >= 1 {
 					lateCount++
 				}
@@ -559,6 +634,11 @@ func (mm *messageManager) runSend() {
 			}
 			MessageStats.Add([]string{mm.name.String(), "Delayed"}, lateCount)
 
+			if deadIDs != nil {
+				mm.wg.Add(1)
+				go mm.deadLetter(deadIDs) // calls the offsetting mm.wg.Done()
+			}
+
 			// If we have rows to send, break out of this loop.
 			if rows != nil {
 				break
@@ -634,6 +714,41 @@ func (mm *messageManager) postpone(tsv TabletService, ackWaitTime time.Duration,
 	}
 }
 
+// deadLetter marks the messages failed, or moves them to the dead letter
+// table, instead of sending them again.
+func (mm *messageManager) deadLetter(ids []string) {
+	defer func() {
+		mm.tsv.LogError()
+		mm.wg.Done()
+	}()
+
+	defer func() {
+		// Same as send: hold cacheManagementMu so that the poller cannot
+		// requeue a snapshot of the rows from before they were updated.
+		mm.cacheManagementMu.Lock()
+		defer mm.cacheManagementMu.Unlock()
+		mm.cache.Discard(ids)
+	}()
+
+	// Share the postpone semaphore to limit parallelism.
+	if !mm.postponeSema.Acquire() {
+		// Unreachable.
+		return
+	}
+	defer mm.postponeSema.Release()
+	ctx, cancel := context.WithTimeout(tabletenv.LocalContext(), mm.ackWaitTime)
+	defer cancel()
+	count, err := mm.tsv.DeadLetterMessages(ctx, nil, mm, ids)
+	if err != nil {
+		// The messages stay scheduled, and will be dead-lettered again
+		// the next time they come up.
+		MessageStats.Add([]string{mm.name.String(), "DeadLetterFailed"}, 1)
+		log.Errorf("Unable to dead-letter messages %v: %v", ids, err)
+		return
+	}
+	MessageStats.Add([]string{mm.name.String(), "DeadLettered"}, count)
+}
+
 func (mm *messageManager) startVStream() {
 	if mm.streamCancel != nil {
 		return
@@ -746,6 +861,14 @@ func (mm *messageManager) processRowEvent(fields []*querypb.Field, rowEvent *bin
 
 	now := time.Now().UnixNano()
 	for _, rc := range rowEvent.RowChanges {
+		if mm.groupIdx != -1 {
+			// Any change to a grouped message, including a delete, may
+			// make the next message of its group eligible.
+			if rc.After == nil || !sqltypes.MakeRowTrusted(fields, rc.After)[mm.groupIdx].IsNull() {
+				mm.requestPoll()
+				continue
+			}
+		}
 		if rc.After == nil {
 			continue
 		}
@@ -757,15 +880,32 @@ func (mm *messageManager) processRowEvent(fields []*querypb.Field, rowEvent *bin
 		if mr.TimeAcked != 0 || mr.TimeNext > now {
 			continue
 		}
+		if row[1].IsNull() && mm.maxAttempts > 0 && mr.Epoch >= int64(mm.maxAttempts) {
+			// The message was dead-lettered.
+			continue
+		}
 		mm.Add(mr)
 	}
 	return nil
 }
 
+// requestPoll triggers the poller, unless it was already triggered
+// and has not run yet.
+func (mm *messageManager) requestPoll() {
+	if !mm.pollRequested.CompareAndSwap(false, true) {
+		return
+	}
+	// The poller waits for cacheManagementMu, which the vstream
+	// holds, so the trigger has to be asynchronous.
+	go mm.pollerTicks.Trigger()
+}
+
 func (mm *messageManager) runPoller() {
 	// We need to get the flow control lock first
 	mm.cacheManagementMu.Lock()
 	defer mm.cacheManagementMu.Unlock()
+	// Changes from here on are visible to this run.
+	mm.pollRequested.Set(false)
 	// Now we can get the main/structure lock and ensure e.g. that the
 	// the receiver count does not change during the run
 	mm.mu.Lock()
@@ -893,6 +1033,29 @@ func (mm *messageManager) GeneratePurgeQuery(timeCutoff int64) (string, map[stri
 	}
 }
 
+// GenerateDeadLetterQueries returns the queries and bind vars for dead-lettering
+// messages. The queries must be run in a single transaction.
+func (mm *messageManager) GenerateDeadLetterQueries(ids []string) ([]string, map[string]*querypb.BindVariable) {
+	idbvs := &querypb.BindVariable{
+		Type:   querypb.Type_TUPLE,
+		Values: make([]*querypb.Value, 0, len(ids)),
+	}
+	for _, id := range ids {
+		idbvs.Values = append(idbvs.Values, &querypb.Value{
+			Type:  querypb.Type_VARBINARY,
+			Value: []byte(id),
+		})
+	}
+
+	queries := make([]string, 0, len(mm.deadLetterQueries))
+	for _, query := range mm.deadLetterQueries {
+		queries = append(queries, query.Query)
+	}
+	return queries, map[string]*querypb.BindVariable{
+		"ids": idbvs,
+	}
+}
+
 // BuildMessageRow builds a MessageRow from a db row.
 func BuildMessageRow(row []sqltypes.Value) (*MessageRow, error) {
 	mr := &MessageRow{Row: row[4:]}
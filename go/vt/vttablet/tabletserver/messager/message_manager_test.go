@@ -32,6 +32,7 @@ import (
 	"vitess.io/vitess/go/test/utils"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/sync2"
@@ -298,6 +299,121 @@ func TestMessageManagerSend(t *testing.T) {
 	<-r1.ch
 }
 
+func TestMessageManagerDeadLetter(t *testing.T) {
+	tsv := newFakeTabletServer()
+	table := newMMTable()
+	table.MessageInfo.MaxAttempts = 2
+	mm := newMessageManager(tsv, newFakeVStreamer(), table, sync2.NewSemaphore(1, 0))
+	mm.Open()
+	defer mm.Close()
+
+	r1 := newTestReceiver(1)
+	mm.Subscribe(context.Background(), r1.rcv)
+	<-r1.ch
+
+	ch := make(chan string, 20)
+	tsv.SetChannel(ch)
+
+	// A message that was sent fewer than max attempts times is sent.
+	mm.Add(&MessageRow{Epoch: 1, Row: []sqltypes.Value{sqltypes.NewVarBinary("1")}})
+	got := <-r1.ch
+	assert.Equal(t, "1", got.Rows[0][0].ToString())
+	assert.Equal(t, "postpone", <-ch)
+
+	// One that was sent max attempts times is dead-lettered instead.
+	mm.Add(&MessageRow{Epoch: 2, Row: []sqltypes.Value{sqltypes.NewVarBinary("2")}})
+	assert.Equal(t, "deadletter", <-ch)
+	assert.EqualValues(t, 1, tsv.deadLetterCount.Get())
+	// Only the fields and message 1 were received.
+	assert.EqualValues(t, 2, r1.count.Get())
+
+	// And removed from the cache.
+	require.Eventually(t, func() bool {
+		mm.cache.mu.Lock()
+		defer mm.cache.mu.Unlock()
+		_, inQueue := mm.cache.inQueue["2"]
+		_, inFlight := mm.cache.inFlight["2"]
+		return !inQueue && !inFlight
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestMessageManagerNullTimeNext(t *testing.T) {
+	table := newMMTable()
+	table.MessageInfo.MaxAttempts = 2
+	mm := newMessageManager(newFakeTabletServer(), newFakeVStreamer(), table, sync2.NewSemaphore(1, 0))
+	// Add drops messages if there are no receivers.
+	mm.receivers = []*receiverWithStatus{{}}
+
+	row := func(id, epoch int64) *querypb.Row {
+		return sqltypes.RowToProto3([]sqltypes.Value{
+			sqltypes.NewInt64(1),
+			sqltypes.NULL,
+			sqltypes.NewInt64(epoch),
+			sqltypes.NULL,
+			sqltypes.NewInt64(id),
+			sqltypes.NewVarBinary("a"),
+		})
+	}
+
+	// A dead-lettered message is not sent again.
+	err := mm.processRowEvent(testDBFields, &binlogdatapb.RowEvent{
+		TableName:  "foo",
+		RowChanges: []*binlogdatapb.RowChange{{After: row(1, 2)}},
+	})
+	require.NoError(t, err)
+	assert.True(t, mm.cache.IsEmpty())
+
+	// Other messages without a time_next are sent right away.
+	err = mm.processRowEvent(testDBFields, &binlogdatapb.RowEvent{
+		TableName:  "foo",
+		RowChanges: []*binlogdatapb.RowChange{{After: row(2, 1)}},
+	})
+	require.NoError(t, err)
+	assert.False(t, mm.cache.IsEmpty())
+}
+
+func TestMessageManagerGroups(t *testing.T) {
+	ti := newMMTable()
+	ti.MessageInfo.GroupColumn = "message"
+	mm := newMessageManager(newFakeTabletServer(), newFakeVStreamer(), ti, sync2.NewSemaphore(1, 0))
+	// Add drops messages if there are no receivers.
+	mm.receivers = []*receiverWithStatus{{}}
+
+	// Grouped messages are left to the poller.
+	err := mm.processRowEvent(testDBFields, &binlogdatapb.RowEvent{
+		TableName:  "foo",
+		RowChanges: []*binlogdatapb.RowChange{{After: newMMRow(1)}},
+	})
+	require.NoError(t, err)
+	assert.True(t, mm.cache.IsEmpty())
+	assert.True(t, mm.pollRequested.Get())
+
+	// Messages without a group are added as usual.
+	mm.pollRequested.Set(false)
+	err = mm.processRowEvent(testDBFields, &binlogdatapb.RowEvent{
+		TableName: "foo",
+		RowChanges: []*binlogdatapb.RowChange{{After: sqltypes.RowToProto3([]sqltypes.Value{
+			sqltypes.NewInt64(1),
+			sqltypes.NewInt64(1),
+			sqltypes.NewInt64(0),
+			sqltypes.NULL,
+			sqltypes.NewInt64(2),
+			sqltypes.NULL,
+		})}},
+	})
+	require.NoError(t, err)
+	assert.False(t, mm.cache.IsEmpty())
+	assert.False(t, mm.pollRequested.Get())
+
+	// Deletes may unblock a group.
+	err = mm.processRowEvent(testDBFields, &binlogdatapb.RowEvent{
+		TableName:  "foo",
+		RowChanges: []*binlogdatapb.RowChange{{Before: newMMRow(3)}},
+	})
+	require.NoError(t, err)
+	assert.True(t, mm.pollRequested.Get())
+}
+
 func TestMessageManagerPostponeThrottle(t *testing.T) {
 	tsv := newFakeTabletServer()
 	mm := newMessageManager(tsv, newFakeVStreamer(), newMMTable(), sync2.NewSemaphore(1, 0))
@@ -787,6 +903,41 @@ func TestMMGenerate(t *testing.T) {
 	}
 }
 
+func TestMMGenerateDeadLetter(t *testing.T) {
+	wantids := sqltypes.TestBindVariable([]any{[]byte{'1'}, []byte{'2'}})
+
+	table := newMMTable()
+	table.MessageInfo.MaxAttempts = 3
+	mm := newMessageManager(newFakeTabletServer(), newFakeVStreamer(), table, sync2.NewSemaphore(1, 0))
+	queries, bv := mm.GenerateDeadLetterQueries([]string{"1", "2"})
+	assert.Equal(t, []string{
+		"update foo set time_next = null where id in ::ids and time_acked is null",
+	}, queries)
+	utils.MustMatch(t, map[string]*querypb.BindVariable{"ids": wantids}, bv, "did not match")
+
+	table.MessageInfo.DeadLetterTable = "foo_dead"
+	mm = newMessageManager(newFakeTabletServer(), newFakeVStreamer(), table, sync2.NewSemaphore(1, 0))
+	queries, bv = mm.GenerateDeadLetterQueries([]string{"1", "2"})
+	assert.Equal(t, []string{
+		"update foo set time_next = null where id in ::ids and time_acked is null",
+		"insert into foo_dead select * from foo where id in ::ids and time_acked is null and time_next is null",
+		"delete from foo where id in ::ids and time_acked is null and time_next is null",
+	}, queries)
+	utils.MustMatch(t, map[string]*querypb.BindVariable{"ids": wantids}, bv, "did not match")
+}
+
+func TestMMGenerateGroupedRead(t *testing.T) {
+	table := newMMTable()
+	table.MessageInfo.GroupColumn = "message"
+	mm := newMessageManager(newFakeTabletServer(), newFakeVStreamer(), table, sync2.NewSemaphore(1, 0))
+	assert.Equal(t, "select priority, time_next, epoch, time_acked, id, message from foo where time_acked is null and time_next < :time_next "+
+		"and not exists (select 1 from foo as prev where prev.message = foo.message and prev.time_acked is null and prev.time_next is not null "+
+		"and (ifnull(prev.epoch, 0) > ifnull(foo.epoch, 0) or ifnull(prev.epoch, 0) = ifnull(foo.epoch, 0) "+
+		"and (prev.time_next < foo.time_next or prev.time_next = foo.time_next and prev.id < foo.id))) "+
+		"order by priority, time_next desc limit :max", mm.readByPriorityAndTimeNext.Query)
+	assert.Equal(t, 5, mm.groupIdx)
+}
+
 func TestMMGenerateWithBackoff(t *testing.T) {
 	mm := newMessageManager(newFakeTabletServer(), newFakeVStreamer(), newMMTableWithBackoff(), sync2.NewSemaphore(1, 0))
 	mm.Open()
@@ -827,6 +978,8 @@ type fakeTabletServer struct {
 	postponeCount sync2.AtomicInt64
 	purgeCount    sync2.AtomicInt64
 
+	deadLetterCount sync2.AtomicInt64
+
 	mu sync.Mutex
 	ch chan string
 }
@@ -857,6 +1010,17 @@ func (fts *fakeTabletServer) PostponeMessages(ctx context.Context, target *query
 	return 0, nil
 }
 
+func (fts *fakeTabletServer) DeadLetterMessages(ctx context.Context, target *querypb.Target, gen QueryGenerator, ids []string) (count int64, err error) {
+	fts.deadLetterCount.Add(1)
+	fts.mu.Lock()
+	ch := fts.ch
+	fts.mu.Unlock()
+	if ch != nil {
+		ch <- "deadletter"
+	}
+	return int64(len(ids)), nil
+}
+
 func (fts *fakeTabletServer) PurgeMessages(ctx context.Context, target *querypb.Target, gen QueryGenerator, timeCutoff int64) (count int64, err error) {
 	fts.purgeCount.Add(1)
 	fts.mu.Lock()
@@ -104,6 +104,12 @@ func loadMessageInfo(ta *Table, comment string) error {
 
 	ta.MessageInfo.MaxBackoff, _ = getDuration(keyvals, "vt_max_backoff")
 
+	ta.MessageInfo.MaxAttempts, _ = getNum(keyvals, "vt_max_attempts")
+	ta.MessageInfo.DeadLetterTable = keyvals["vt_dead_letter_table"]
+	if ta.MessageInfo.DeadLetterTable != "" && ta.MessageInfo.MaxAttempts == 0 {
+		return fmt.Errorf("vt_dead_letter_table requires vt_max_attempts: %s", ta.Name.String())
+	}
+
 	// these columns are required for message manager to function properly, but only
 	// id is required to be streamed to subscribers
 	requiredCols := []string{
@@ -154,6 +160,22 @@ func loadMessageInfo(ta *Table, comment string) error {
 		ta.MessageInfo.Fields = getDefaultMessageFields(ta.Fields, hiddenCols)
 	}
 
+	// the group column must be streamed, so that the message manager and
+	// subscribers can see which group a message belongs to
+	if groupCol := keyvals["vt_group_col"]; groupCol != "" {
+		found := false
+		for _, field := range ta.MessageInfo.Fields {
+			if strings.EqualFold(field.Name, groupCol) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("vt_group_col %s must be one of the message columns: %s", groupCol, ta.Name.String())
+		}
+		ta.MessageInfo.GroupColumn = groupCol
+	}
+
 	return nil
 }
 
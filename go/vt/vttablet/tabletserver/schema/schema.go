@@ -104,6 +104,24 @@ type MessageInfo struct {
 	// MaxBackoff specifies the longest duration message manager
 	// should wait before rescheduling a message
 	MaxBackoff time.Duration
+
+	// MaxAttempts specifies how many times a message is sent
+	// without being acked before it is dead-lettered. Zero
+	// means messages are retried until acked.
+	MaxAttempts int
+
+	// DeadLetterTable specifies the table dead-lettered messages
+	// are moved to. It must have the same columns as the message
+	// table. If empty, dead-lettered messages are left in place
+	// with a null time_next.
+	DeadLetterTable string
+
+	// GroupColumn specifies the column that groups messages.
+	// Messages of a group are delivered one at a time, in
+	// time_next and id order: a message is not sent until
+	// the messages before it are acked or dead-lettered.
+	// Messages with a null group are not ordered.
+	GroupColumn string
 }
 
 // NewTable creates a new Table.
@@ -22,10 +22,12 @@ import (
 
 // AppThrottle is the definition for an app throttling instruction
 // - Ratio: [0..1], 0 == no throttle, 1 == fully throttle
+// - Exempt: when true, the app is never throttled, even when metrics exceed thresholds
 type AppThrottle struct {
 	AppName  string
 	ExpireAt time.Time
 	Ratio    float64
+	Exempt   bool
 }
 
 // NewAppThrottle creates an AppThrottle struct
@@ -83,6 +83,9 @@ func (check *ThrottlerCheck) checkAppMetricResult(ctx context.Context, appName s
 	} else if err != nil {
 		// any error
 		statusCode = http.StatusInternalServerError // 500
+	} else if value > threshold && check.throttler.IsAppExempted(appName) {
+		// app is exempted from throttling
+		statusCode = http.StatusOK // 200
 	} else if value > threshold {
 		// casual throttling
 		statusCode = http.StatusTooManyRequests // 429
@@ -22,11 +22,13 @@ import (
 	"sync/atomic"
 	"time"
 
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sync2"
 	"vitess.io/vitess/go/textutil"
 	"vitess.io/vitess/go/timer"
 	"vitess.io/vitess/go/vt/log"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/connpool"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/heartbeat"
@@ -92,9 +94,10 @@ func init() {
 type Throttler struct {
 	keyspace string
 	shard    string
+	cell     string
 
 	check     *ThrottlerCheck
-	isEnabled bool
+	isEnabled int64
 	isLeader  int64
 	isOpen    int64
 
@@ -102,6 +105,7 @@ type Throttler struct {
 	pool            *connpool.Pool
 	tabletTypeFunc  func() topodatapb.TabletType
 	ts              *topo.Server
+	srvTopoServer   srvtopo.Server
 	heartbeatWriter heartbeat.HeartbeatWriter
 
 	throttleTabletTypesMap map[topodatapb.TabletType]bool
@@ -112,9 +116,9 @@ type Throttler struct {
 
 	mysqlInventory *mysql.Inventory
 
-	metricsQuery     string
+	metricsQuery     sync2.AtomicString
 	MetricsThreshold sync2.AtomicFloat64
-	metricsQueryType mysql.MetricsQueryType
+	checkAsCheckSelf sync2.AtomicBool
 
 	mysqlClusterThresholds *cache.Cache
 	aggregatedMetrics      *cache.Cache
@@ -128,6 +132,15 @@ type Throttler struct {
 	throttledAppsMutex sync.Mutex
 	tickers            [](*timer.SuspendableTicker)
 
+	// topoThrottledApps holds the names of apps whose throttling rules were
+	// last applied from the keyspace's ThrottlerConfig in the topo.
+	topoThrottledApps map[string]bool
+	// hasTopoConfig is set while the keyspace's ThrottlerConfig in the topo overrides the
+	// command line flags. It is only accessed by the SrvKeyspace watch.
+	hasTopoConfig bool
+	// cancelSrvKeyspaceWatch stops watching the keyspace's ThrottlerConfig, while the throttler is closed.
+	cancelSrvKeyspaceWatch context.CancelFunc
+
 	nonLowPriorityAppRequestsThrottled *cache.Cache
 	httpClient                         *http.Client
 }
@@ -137,6 +150,7 @@ type ThrottlerStatus struct {
 	Keyspace string
 	Shard    string
 
+	IsEnabled bool
 	IsLeader  bool
 	IsOpen    bool
 	IsDormant bool
@@ -146,13 +160,15 @@ type ThrottlerStatus struct {
 }
 
 // NewThrottler creates a Throttler
-func NewThrottler(env tabletenv.Env, ts *topo.Server, heartbeatWriter heartbeat.HeartbeatWriter, tabletTypeFunc func() topodatapb.TabletType) *Throttler {
+func NewThrottler(env tabletenv.Env, srvTopoServer srvtopo.Server, ts *topo.Server, cell string, heartbeatWriter heartbeat.HeartbeatWriter, tabletTypeFunc func() topodatapb.TabletType) *Throttler {
 	throttler := &Throttler{
 		isLeader: 0,
 		isOpen:   0,
 
+		cell:            cell,
 		env:             env,
 		tabletTypeFunc:  tabletTypeFunc,
+		srvTopoServer:   srvTopoServer,
 		ts:              ts,
 		heartbeatWriter: heartbeatWriter,
 		pool: connpool.NewPool(env, "ThrottlerPool", tabletenv.ConnPoolConfig{
@@ -161,42 +177,65 @@ func NewThrottler(env tabletenv.Env, ts *topo.Server, heartbeatWriter heartbeat.
 		}),
 	}
 
+	// The throttler may be enabled at runtime via the keyspace's ThrottlerConfig in the topo,
+	// so we always initialize its structures, even if it starts out disabled.
 	if env.Config().EnableLagThrottler {
-		throttler.isEnabled = true
-		throttler.mysqlThrottleMetricChan = make(chan *mysql.MySQLThrottleMetric)
-
-		throttler.mysqlInventoryChan = make(chan *mysql.Inventory, 1)
-		throttler.mysqlClusterProbesChan = make(chan *mysql.ClusterProbes)
-		throttler.mysqlInventory = mysql.NewInventory()
-
-		throttler.metricsQuery = replicationLagQuery
-		throttler.MetricsThreshold = sync2.NewAtomicFloat64(throttleThreshold.Seconds())
-
-		throttler.throttledApps = cache.New(cache.NoExpiration, 10*time.Second)
-		throttler.mysqlClusterThresholds = cache.New(cache.NoExpiration, 0)
-		throttler.aggregatedMetrics = cache.New(aggregatedMetricsExpiration, aggregatedMetricsCleanup)
-		throttler.recentApps = cache.New(recentAppsExpiration, time.Minute)
-		throttler.metricsHealth = cache.New(cache.NoExpiration, 0)
-
-		throttler.tickers = [](*timer.SuspendableTicker){}
-		throttler.nonLowPriorityAppRequestsThrottled = cache.New(nonDeprioritizedAppMapExpiration, nonDeprioritizedAppMapInterval)
-
-		throttler.httpClient = base.SetupHTTPClient(2 * mysqlCollectInterval)
-		throttler.initThrottleTabletTypes()
-		throttler.ThrottleApp("always-throttled-app", time.Now().Add(time.Hour*24*365*10), defaultThrottleRatio)
-		throttler.check = NewThrottlerCheck(throttler)
-		throttler.initConfig()
-		throttler.check.SelfChecks(context.Background())
-	} else {
-		// Create an empty cache, just so that it isn't nil
-		throttler.throttledApps = cache.New(cache.NoExpiration, 0)
+		throttler.isEnabled = 1
 	}
+	throttler.mysqlThrottleMetricChan = make(chan *mysql.MySQLThrottleMetric)
+
+	throttler.mysqlInventoryChan = make(chan *mysql.Inventory, 1)
+	throttler.mysqlClusterProbesChan = make(chan *mysql.ClusterProbes)
+	throttler.mysqlInventory = mysql.NewInventory()
+
+	throttler.metricsQuery.Set(replicationLagQuery)
+	throttler.MetricsThreshold = sync2.NewAtomicFloat64(throttleThreshold.Seconds())
+	throttler.checkAsCheckSelf.Set(*throttlerCheckAsCheckSelf)
+
+	throttler.throttledApps = cache.New(cache.NoExpiration, 10*time.Second)
+	throttler.mysqlClusterThresholds = cache.New(cache.NoExpiration, 0)
+	throttler.aggregatedMetrics = cache.New(aggregatedMetricsExpiration, aggregatedMetricsCleanup)
+	throttler.recentApps = cache.New(recentAppsExpiration, time.Minute)
+	throttler.metricsHealth = cache.New(cache.NoExpiration, 0)
+
+	throttler.tickers = [](*timer.SuspendableTicker){}
+	throttler.nonLowPriorityAppRequestsThrottled = cache.New(nonDeprioritizedAppMapExpiration, nonDeprioritizedAppMapInterval)
+	throttler.topoThrottledApps = make(map[string]bool)
+
+	throttler.httpClient = base.SetupHTTPClient(2 * mysqlCollectInterval)
+	throttler.initThrottleTabletTypes()
+	throttler.ThrottleApp("always-throttled-app", time.Now().Add(time.Hour*24*365*10), defaultThrottleRatio)
+	throttler.check = NewThrottlerCheck(throttler)
+	throttler.initConfig()
+	throttler.check.SelfChecks(context.Background())
+
 	return throttler
 }
 
+// IsEnabled returns true when the throttler is enabled, either via command line flag or via
+// the keyspace's ThrottlerConfig in the topo.
+func (throttler *Throttler) IsEnabled() bool {
+	return atomic.LoadInt64(&throttler.isEnabled) > 0
+}
+
+// SetEnabled enables or disables the throttler. When disabled, the throttler responds
+// to all checks with OK.
+func (throttler *Throttler) SetEnabled(enabled bool) {
+	newValue := int64(0)
+	if enabled {
+		newValue = 1
+	}
+	if atomic.SwapInt64(&throttler.isEnabled, newValue) != newValue {
+		log.Infof("Throttler: enabled=%v", enabled)
+		if enabled {
+			go throttler.heartbeatWriter.RequestHeartbeats()
+		}
+	}
+}
+
 // CheckIsReady checks if this throttler is ready to serve. If not, it returns an error
 func (throttler *Throttler) CheckIsReady() error {
-	if throttler.isEnabled && throttler.IsOpen() {
+	if throttler.IsEnabled() && throttler.IsOpen() {
 		// all good
 		return nil
 	}
@@ -223,8 +262,133 @@ func (throttler *Throttler) initThrottleTabletTypes() {
 func (throttler *Throttler) InitDBConfig(keyspace, shard string) {
 	throttler.keyspace = keyspace
 	throttler.shard = shard
-	if throttler.env.Config().EnableLagThrottler {
-		go throttler.Operate(context.Background())
+	go throttler.Operate(context.Background())
+}
+
+// watchSrvKeyspace watches the keyspace's SrvKeyspace for ThrottlerConfig changes, until the
+// throttler is closed. It is called with initMutex held.
+func (throttler *Throttler) watchSrvKeyspace() {
+	if throttler.srvTopoServer == nil || throttler.keyspace == "" {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	throttler.cancelSrvKeyspaceWatch = cancel
+	go throttler.srvTopoServer.WatchSrvKeyspace(ctx, throttler.cell, throttler.keyspace, func(srvks *topodatapb.SrvKeyspace, err error) bool {
+		if ctx.Err() != nil {
+			// Returning false removes the listener from the watch.
+			return false
+		}
+		return throttler.WatchSrvKeyspaceCallback(srvks, err)
+	})
+}
+
+// WatchSrvKeyspaceCallback gets called upon a change in SrvKeyspace, and applies the keyspace's
+// ThrottlerConfig, if any, to this throttler.
+func (throttler *Throttler) WatchSrvKeyspaceCallback(srvks *topodatapb.SrvKeyspace, err error) bool {
+	if err != nil {
+		if !topo.IsErrType(err, topo.NoNode) {
+			log.Errorf("Throttler: error watching SrvKeyspace for %v/%v: %v", throttler.cell, throttler.keyspace, err)
+		}
+		// Keep watching; the srvtopo watcher will retry.
+		return true
+	}
+	if throttlerConfig := srvks.GetThrottlerConfig(); throttlerConfig != nil {
+		throttler.applyThrottlerConfig(throttlerConfig)
+		throttler.hasTopoConfig = true
+	} else if throttler.hasTopoConfig {
+		// The ThrottlerConfig was removed from the topo.
+		throttler.applyFlagsConfig()
+		throttler.hasTopoConfig = false
+	}
+	return true
+}
+
+// applyThrottlerConfig applies a keyspace-wide ThrottlerConfig, as read from the topo. Once a keyspace
+// has a ThrottlerConfig, it takes precedence over the throttler's command line flags.
+func (throttler *Throttler) applyThrottlerConfig(throttlerConfig *topodatapb.ThrottlerConfig) {
+	if err := validateThrottlerConfig(throttlerConfig); err != nil {
+		log.Errorf("Throttler: rejecting topo config %+v: %v", throttlerConfig, err)
+		return
+	}
+	log.Infof("Throttler: applying topo config: %+v", throttlerConfig)
+	if throttlerConfig.CustomQuery == "" {
+		throttler.metricsQuery.Set(replicationLagQuery)
+	} else {
+		throttler.metricsQuery.Set(throttlerConfig.CustomQuery)
+	}
+	if throttlerConfig.Threshold == 0 {
+		// No threshold in the topo: the default replication lag check uses the flag's threshold.
+		throttler.MetricsThreshold.Set(throttleThreshold.Seconds())
+	} else {
+		throttler.MetricsThreshold.Set(throttlerConfig.Threshold)
+	}
+	throttler.checkAsCheckSelf.Set(throttlerConfig.CheckAsCheckSelf)
+	throttler.applyThrottledAppRules(throttlerConfig.ThrottledApps)
+	if throttlerConfig.Enabled != nil {
+		throttler.SetEnabled(*throttlerConfig.Enabled)
+	} else {
+		// The topo does not enable nor disable the throttler: --enable_lag_throttler does.
+		throttler.SetEnabled(throttler.env.Config().EnableLagThrottler)
+	}
+}
+
+// applyFlagsConfig reverts the throttler to its command line flags, once the keyspace's
+// ThrottlerConfig is removed from the topo.
+func (throttler *Throttler) applyFlagsConfig() {
+	log.Infof("Throttler: topo config removed, applying command line flags")
+	if *throttleMetricQuery != "" {
+		throttler.metricsQuery.Set(*throttleMetricQuery)
+	} else {
+		throttler.metricsQuery.Set(replicationLagQuery)
+	}
+	if *throttleMetricThreshold != math.MaxFloat64 {
+		throttler.MetricsThreshold.Set(*throttleMetricThreshold)
+	} else {
+		throttler.MetricsThreshold.Set(throttleThreshold.Seconds())
+	}
+	throttler.checkAsCheckSelf.Set(*throttlerCheckAsCheckSelf)
+	throttler.applyThrottledAppRules(nil)
+	throttler.SetEnabled(throttler.env.Config().EnableLagThrottler)
+}
+
+// validateThrottlerConfig returns an error if the ThrottlerConfig has no usable threshold: a threshold
+// must be positive, and a custom query must come with a threshold of its own.
+func validateThrottlerConfig(throttlerConfig *topodatapb.ThrottlerConfig) error {
+	if throttlerConfig.Threshold < 0 {
+		return fmt.Errorf("threshold must be positive, got %v", throttlerConfig.Threshold)
+	}
+	if throttlerConfig.CustomQuery != "" && throttlerConfig.Threshold == 0 {
+		return fmt.Errorf("custom query %q has no threshold", throttlerConfig.CustomQuery)
+	}
+	return nil
+}
+
+// applyThrottledAppRules throttles, or exempts, apps according to the given rules. Apps which were
+// previously configured via the topo, but which no longer have a rule, are unthrottled.
+func (throttler *Throttler) applyThrottledAppRules(rules map[string]*topodatapb.ThrottledAppRule) {
+	now := time.Now()
+	appliedApps := make(map[string]bool)
+	for appName, rule := range rules {
+		if appName == "" {
+			continue
+		}
+		expireAt := protoutil.TimeFromProto(rule.ExpiresAt)
+		if !expireAt.After(now) {
+			continue
+		}
+		throttler.throttleOrExemptApp(appName, expireAt, rule.Ratio, rule.Exempt)
+		appliedApps[appName] = true
+	}
+
+	throttler.throttledAppsMutex.Lock()
+	previousApps := throttler.topoThrottledApps
+	throttler.topoThrottledApps = appliedApps
+	throttler.throttledAppsMutex.Unlock()
+
+	for appName := range previousApps {
+		if !appliedApps[appName] {
+			throttler.UnthrottleApp(appName)
+		}
 	}
 }
 
@@ -240,20 +404,19 @@ func (throttler *Throttler) initConfig() {
 		},
 	}
 	if *throttleMetricQuery != "" {
-		throttler.metricsQuery = *throttleMetricQuery
+		throttler.metricsQuery.Set(*throttleMetricQuery)
 	}
 	if *throttleMetricThreshold != math.MaxFloat64 {
 		throttler.MetricsThreshold = sync2.NewAtomicFloat64(*throttleMetricThreshold)
 	}
-	throttler.metricsQueryType = mysql.GetMetricsQueryType(throttler.metricsQuery)
 
 	config.Instance.Stores.MySQL.Clusters[selfStoreName] = &config.MySQLClusterConfigurationSettings{
-		MetricQuery:       throttler.metricsQuery,
+		MetricQuery:       throttler.metricsQuery.Get(),
 		ThrottleThreshold: &throttler.MetricsThreshold,
 		IgnoreHostsCount:  0,
 	}
 	config.Instance.Stores.MySQL.Clusters[shardStoreName] = &config.MySQLClusterConfigurationSettings{
-		MetricQuery:       throttler.metricsQuery,
+		MetricQuery:       throttler.metricsQuery.Get(),
 		ThrottleThreshold: &throttler.MetricsThreshold,
 		IgnoreHostsCount:  0,
 	}
@@ -276,6 +439,7 @@ func (throttler *Throttler) Open() error {
 
 	throttler.pool.Open(throttler.env.Config().DB.AppWithDB(), throttler.env.Config().DB.DbaWithDB(), throttler.env.Config().DB.AppDebugWithDB())
 	atomic.StoreInt64(&throttler.isOpen, 1)
+	throttler.watchSrvKeyspace()
 
 	for _, t := range throttler.tickers {
 		t.Resume()
@@ -302,6 +466,10 @@ func (throttler *Throttler) Close() {
 		t.Suspend()
 	}
 	log.Infof("Throttler - finished suspending tickers")
+	if throttler.cancelSrvKeyspaceWatch != nil {
+		throttler.cancelSrvKeyspaceWatch()
+		throttler.cancelSrvKeyspaceWatch = nil
+	}
 	atomic.StoreInt64(&throttler.isLeader, 0)
 
 	log.Infof("Throttler - closing pool")
@@ -326,7 +494,8 @@ func (throttler *Throttler) readSelfMySQLThrottleMetric() *mysql.MySQLThrottleMe
 	}
 	defer conn.Recycle()
 
-	tm, err := conn.Exec(ctx, throttler.metricsQuery, 1, true)
+	metricsQuery := throttler.metricsQuery.Get()
+	tm, err := conn.Exec(ctx, metricsQuery, 1, true)
 	if err != nil {
 		metric.Err = err
 		return metric
@@ -337,7 +506,7 @@ func (throttler *Throttler) readSelfMySQLThrottleMetric() *mysql.MySQLThrottleMe
 		return metric
 	}
 
-	switch throttler.metricsQueryType {
+	switch mysql.GetMetricsQueryType(metricsQuery) {
 	case mysql.MetricsQueryTypeSelect:
 		// We expect a single row, single column result.
 		// The "for" iteration below is just a way to get first result without knowning column name
@@ -347,7 +516,7 @@ func (throttler *Throttler) readSelfMySQLThrottleMetric() *mysql.MySQLThrottleMe
 	case mysql.MetricsQueryTypeShowGlobal:
 		metric.Value, metric.Err = strconv.ParseFloat(row["Value"].ToString(), 64)
 	default:
-		metric.Err = fmt.Errorf("Unsupported metrics query type for query %s", throttler.metricsQuery)
+		metric.Err = fmt.Errorf("Unsupported metrics query type for query %s", metricsQuery)
 	}
 
 	return metric
@@ -429,7 +598,7 @@ func (throttler *Throttler) Operate(ctx context.Context) {
 			}
 		case <-mysqlCollectTicker.C:
 			{
-				if atomic.LoadInt64(&throttler.isOpen) > 0 {
+				if atomic.LoadInt64(&throttler.isOpen) > 0 && throttler.IsEnabled() {
 					// frequent
 					if !throttler.isDormant() {
 						throttler.collectMySQLMetrics(ctx)
@@ -438,7 +607,7 @@ func (throttler *Throttler) Operate(ctx context.Context) {
 			}
 		case <-mysqlDormantCollectTicker.C:
 			{
-				if atomic.LoadInt64(&throttler.isOpen) > 0 {
+				if atomic.LoadInt64(&throttler.isOpen) > 0 && throttler.IsEnabled() {
 					// infrequent
 					if throttler.isDormant() {
 						throttler.collectMySQLMetrics(ctx)
@@ -453,7 +622,7 @@ func (throttler *Throttler) Operate(ctx context.Context) {
 		case <-mysqlRefreshTicker.C:
 			{
 				// sparse
-				if atomic.LoadInt64(&throttler.isOpen) > 0 {
+				if atomic.LoadInt64(&throttler.isOpen) > 0 && throttler.IsEnabled() {
 					go throttler.refreshMySQLInventory(ctx)
 				}
 			}
@@ -464,7 +633,7 @@ func (throttler *Throttler) Operate(ctx context.Context) {
 			}
 		case <-mysqlAggregateTicker.C:
 			{
-				if atomic.LoadInt64(&throttler.isOpen) > 0 {
+				if atomic.LoadInt64(&throttler.isOpen) > 0 && throttler.IsEnabled() {
 					throttler.aggregateMySQLMetrics(ctx)
 				}
 			}
@@ -677,6 +846,12 @@ func (throttler *Throttler) expireThrottledApps() {
 
 // ThrottleApp instructs the throttler to begin throttling an app, to som eperiod and with some ratio.
 func (throttler *Throttler) ThrottleApp(appName string, expireAt time.Time, ratio float64) (appThrottle *base.AppThrottle) {
+	return throttler.throttleOrExemptApp(appName, expireAt, ratio, false)
+}
+
+// throttleOrExemptApp sets a throttling rule for an app. An exempted app is never throttled, even when
+// the throttler's metrics exceed their thresholds.
+func (throttler *Throttler) throttleOrExemptApp(appName string, expireAt time.Time, ratio float64, exempt bool) (appThrottle *base.AppThrottle) {
 	throttler.throttledAppsMutex.Lock()
 	defer throttler.throttledAppsMutex.Unlock()
 
@@ -689,6 +864,7 @@ func (throttler *Throttler) ThrottleApp(appName string, expireAt time.Time, rati
 		if ratio >= 0 {
 			appThrottle.Ratio = ratio
 		}
+		appThrottle.Exempt = exempt
 	} else {
 		if expireAt.IsZero() {
 			expireAt = now.Add(defaultThrottleTTLMinutes * time.Minute)
@@ -697,6 +873,7 @@ func (throttler *Throttler) ThrottleApp(appName string, expireAt time.Time, rati
 			ratio = defaultThrottleRatio
 		}
 		appThrottle = base.NewAppThrottle(appName, expireAt, ratio)
+		appThrottle.Exempt = exempt
 	}
 	if now.Before(appThrottle.ExpireAt) {
 		throttler.throttledApps.Set(appName, appThrottle, cache.DefaultExpiration)
@@ -746,6 +923,34 @@ func (throttler *Throttler) IsAppThrottled(appName string) bool {
 	return false
 }
 
+// IsAppExempted tells whether some app is exempted from throttling, i.e. should be allowed to
+// proceed even when the throttler's metrics exceed their thresholds.
+func (throttler *Throttler) IsAppExempted(appName string) bool {
+	isSingleAppNameExempted := func(singleAppName string) bool {
+		if object, found := throttler.throttledApps.Get(singleAppName); found {
+			appThrottle := object.(*base.AppThrottle)
+			if appThrottle.ExpireAt.Before(time.Now()) {
+				// throttling cleanup hasn't purged yet, but it is expired
+				return false
+			}
+			return appThrottle.Exempt
+		}
+		return false
+	}
+	if isSingleAppNameExempted(appName) {
+		return true
+	}
+	for _, singleAppName := range strings.Split(appName, ":") {
+		if singleAppName == "" {
+			continue
+		}
+		if isSingleAppNameExempted(singleAppName) {
+			return true
+		}
+	}
+	return false
+}
+
 // ThrottledAppsMap returns a (copy) map of currently throttled apps
 func (throttler *Throttler) ThrottledAppsMap() (result map[string](*base.AppThrottle)) {
 	result = make(map[string](*base.AppThrottle))
@@ -809,7 +1014,7 @@ func (throttler *Throttler) AppRequestMetricResult(ctx context.Context, appName
 
 // checkStore checks the aggregated value of given MySQL store
 func (throttler *Throttler) checkStore(ctx context.Context, appName string, storeName string, remoteAddr string, flags *CheckFlags) (checkResult *CheckResult) {
-	if !throttler.env.Config().EnableLagThrottler {
+	if !throttler.IsEnabled() {
 		return okMetricCheckResult
 	}
 	return throttler.check.Check(ctx, appName, "mysql", storeName, remoteAddr, flags)
@@ -832,7 +1037,7 @@ func (throttler *Throttler) CheckByType(ctx context.Context, appName string, rem
 	case ThrottleCheckSelf:
 		return throttler.checkSelf(ctx, appName, remoteAddr, flags)
 	case ThrottleCheckPrimaryWrite:
-		if *throttlerCheckAsCheckSelf {
+		if throttler.checkAsCheckSelf.Get() {
 			return throttler.checkSelf(ctx, appName, remoteAddr, flags)
 		}
 		return throttler.checkShard(ctx, appName, remoteAddr, flags)
@@ -847,6 +1052,7 @@ func (throttler *Throttler) Status() *ThrottlerStatus {
 		Keyspace: throttler.keyspace,
 		Shard:    throttler.shard,
 
+		IsEnabled: throttler.IsEnabled(),
 		IsLeader:  (atomic.LoadInt64(&throttler.isLeader) > 0),
 		IsOpen:    (atomic.LoadInt64(&throttler.isOpen) > 0),
 		IsDormant: throttler.isDormant(),
@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+type fakeHeartbeatWriter struct{}
+
+func (w fakeHeartbeatWriter) RequestHeartbeats() {}
+
+func newTestThrottler() *Throttler {
+	return &Throttler{
+		env:               tabletenv.NewEnv(tabletenv.NewDefaultConfig(), "ThrottlerTest"),
+		heartbeatWriter:   fakeHeartbeatWriter{},
+		throttledApps:     cache.New(cache.NoExpiration, 0),
+		topoThrottledApps: make(map[string]bool),
+	}
+}
+
+func TestApplyThrottlerConfig(t *testing.T) {
+	throttler := newTestThrottler()
+	throttler.ThrottleApp("local-app", time.Now().Add(time.Hour), 1)
+
+	future := protoutil.TimeToProto(time.Now().Add(time.Hour))
+	throttler.applyThrottlerConfig(&topodatapb.ThrottlerConfig{
+		Enabled:     proto.Bool(true),
+		Threshold:   3.5,
+		CustomQuery: "show global status like 'threads_running'",
+		ThrottledApps: map[string]*topodatapb.ThrottledAppRule{
+			"online-ddl": {Name: "online-ddl", Ratio: 1, ExpiresAt: future},
+			"workflow":   {Name: "workflow", Exempt: true, ExpiresAt: future},
+			"expired":    {Name: "expired", Ratio: 1, ExpiresAt: protoutil.TimeToProto(time.Now().Add(-time.Hour))},
+		},
+	})
+
+	assert.True(t, throttler.IsEnabled())
+	assert.Equal(t, 3.5, throttler.MetricsThreshold.Get())
+	assert.Equal(t, "show global status like 'threads_running'", throttler.metricsQuery.Get())
+
+	assert.True(t, throttler.IsAppThrottled("online-ddl"))
+	assert.True(t, throttler.IsAppThrottled("online-ddl:some-uuid"))
+	assert.False(t, throttler.IsAppThrottled("expired"))
+	assert.False(t, throttler.IsAppThrottled("workflow"))
+	assert.True(t, throttler.IsAppExempted("vreplication:workflow"))
+	assert.False(t, throttler.IsAppExempted("online-ddl"))
+	assert.True(t, throttler.IsAppThrottled("local-app"))
+
+	// Rules removed from the topo are unthrottled, locally set rules are kept.
+	throttler.applyThrottlerConfig(&topodatapb.ThrottlerConfig{
+		Enabled: proto.Bool(false),
+	})
+
+	assert.False(t, throttler.IsEnabled())
+	assert.Equal(t, replicationLagQuery, throttler.metricsQuery.Get())
+	assert.Equal(t, throttleThreshold.Seconds(), throttler.MetricsThreshold.Get())
+	assert.False(t, throttler.IsAppThrottled("online-ddl"))
+	assert.False(t, throttler.IsAppExempted("workflow"))
+	assert.True(t, throttler.IsAppThrottled("local-app"))
+}
+
+func TestApplyThrottlerConfigKeepsEnabledFlag(t *testing.T) {
+	throttler := newTestThrottler()
+	throttler.env.Config().EnableLagThrottler = true
+	throttler.SetEnabled(true)
+
+	// A config which only throttles apps does not disable a throttler enabled by --enable_lag_throttler.
+	throttler.applyThrottlerConfig(&topodatapb.ThrottlerConfig{
+		ThrottledApps: map[string]*topodatapb.ThrottledAppRule{
+			"online-ddl": {Name: "online-ddl", Ratio: 1, ExpiresAt: protoutil.TimeToProto(time.Now().Add(time.Hour))},
+		},
+	})
+	assert.True(t, throttler.IsEnabled())
+	assert.True(t, throttler.IsAppThrottled("online-ddl"))
+}
+
+func TestWatchSrvKeyspaceCallbackConfigRemoved(t *testing.T) {
+	throttler := newTestThrottler()
+	throttler.env.Config().EnableLagThrottler = true
+	throttler.SetEnabled(true)
+	throttler.metricsQuery.Set(replicationLagQuery)
+	throttler.MetricsThreshold.Set(throttleThreshold.Seconds())
+
+	assert.True(t, throttler.WatchSrvKeyspaceCallback(&topodatapb.SrvKeyspace{
+		ThrottlerConfig: &topodatapb.ThrottlerConfig{
+			Enabled:     proto.Bool(false),
+			Threshold:   5,
+			CustomQuery: "show global status like 'threads_running'",
+			ThrottledApps: map[string]*topodatapb.ThrottledAppRule{
+				"online-ddl": {Name: "online-ddl", Ratio: 1, ExpiresAt: protoutil.TimeToProto(time.Now().Add(time.Hour))},
+			},
+		},
+	}, nil))
+	assert.False(t, throttler.IsEnabled())
+	assert.True(t, throttler.IsAppThrottled("online-ddl"))
+
+	// Once the config is removed from the topo, the command line flags apply again.
+	assert.True(t, throttler.WatchSrvKeyspaceCallback(&topodatapb.SrvKeyspace{}, nil))
+	assert.True(t, throttler.IsEnabled())
+	assert.Equal(t, replicationLagQuery, throttler.metricsQuery.Get())
+	assert.Equal(t, throttleThreshold.Seconds(), throttler.MetricsThreshold.Get())
+	assert.False(t, throttler.IsAppThrottled("online-ddl"))
+}
+
+func TestApplyThrottlerConfigInvalidThreshold(t *testing.T) {
+	tests := []struct {
+		name            string
+		throttlerConfig *topodatapb.ThrottlerConfig
+	}{
+		{
+			name: "negative threshold",
+			throttlerConfig: &topodatapb.ThrottlerConfig{
+				Enabled:   proto.Bool(true),
+				Threshold: -1,
+			},
+		},
+		{
+			name: "custom query without threshold",
+			throttlerConfig: &topodatapb.ThrottlerConfig{
+				Enabled:     proto.Bool(true),
+				CustomQuery: "show global status like 'threads_running'",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			throttler := newTestThrottler()
+			throttler.metricsQuery.Set(replicationLagQuery)
+			throttler.MetricsThreshold.Set(2)
+
+			throttler.applyThrottlerConfig(tt.throttlerConfig)
+
+			assert.False(t, throttler.IsEnabled())
+			assert.Equal(t, replicationLagQuery, throttler.metricsQuery.Get())
+			assert.Equal(t, 2.0, throttler.MetricsThreshold.Get())
+		})
+	}
+}
+
+func TestCloseStopsSrvKeyspaceWatch(t *testing.T) {
+	srvTopo := &fakeSrvTopoServer{callbacks: make(chan func(*topodatapb.SrvKeyspace, error) bool, 1)}
+	throttler := newTestThrottler()
+	throttler.srvTopoServer = srvTopo
+	throttler.keyspace = "ks"
+
+	throttler.watchSrvKeyspace()
+	callback := <-srvTopo.callbacks
+	assert.True(t, callback(&topodatapb.SrvKeyspace{}, nil))
+
+	throttler.cancelSrvKeyspaceWatch()
+	assert.False(t, callback(&topodatapb.SrvKeyspace{}, nil), "the listener must be removed once the watch is canceled")
+}
+
+// fakeSrvTopoServer hands the SrvKeyspace watch callbacks over to the test.
+type fakeSrvTopoServer struct {
+	srvtopo.Server
+	callbacks chan func(*topodatapb.SrvKeyspace, error) bool
+}
+
+func (s *fakeSrvTopoServer) WatchSrvKeyspace(ctx context.Context, cell, keyspace string, callback func(*topodatapb.SrvKeyspace, error) bool) {
+	s.callbacks <- callback
+}
@@ -64,6 +64,19 @@ func (c *CheckpointWriter) UpdateTask(taskID string, status workflowpb.TaskState
 	return c.saveLocked()
 }
 
+// UpdateSetting updates a workflow setting in the checkpointing copy and
+// saves the full checkpoint to the topology server.
+func (c *CheckpointWriter) UpdateSetting(key, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.checkpoint.Settings == nil {
+		c.checkpoint.Settings = make(map[string]string)
+	}
+	c.checkpoint.Settings[key] = value
+	return c.saveLocked()
+}
+
 func (c *CheckpointWriter) saveLocked() error {
 	var err error
 	c.wi.Data, err = proto.Marshal(c.checkpoint)
@@ -0,0 +1,147 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollingrestart
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// healthChecker reads the health of the restarted tablets.
+type healthChecker interface {
+	// tabletHealth returns the current health record of a tablet.
+	tabletHealth(ctx context.Context, tablet *topodatapb.Tablet) (*querypb.StreamHealthResponse, error)
+	// vtgateHealth returns an error if the vtgate at the given URL
+	// does not see the tablet as serving.
+	vtgateHealth(ctx context.Context, url string, tablet *topodatapb.Tablet) error
+}
+
+// tabletHealthChecker streams the health of the tablets,
+// and reads the health check cache of vtgates from their API.
+type tabletHealthChecker struct {
+	tmc tmclient.TabletManagerClient
+}
+
+// tabletHealth is part of the healthChecker interface.
+func (hc *tabletHealthChecker) tabletHealth(ctx context.Context, tablet *topodatapb.Tablet) (*querypb.StreamHealthResponse, error) {
+	// Run an explicit healthcheck first, so the health record is not older
+	// than the restart.
+	if err := hc.tmc.RunHealthCheck(ctx, tablet); err != nil {
+		return nil, fmt.Errorf("failed to run explicit healthcheck: %v", err)
+	}
+
+	conn, err := tabletconn.GetDialer()(tablet, grpcclient.FailFast(true))
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to tablet: %v", err)
+	}
+	defer conn.Close(ctx)
+
+	var result *querypb.StreamHealthResponse
+	err = conn.StreamHealth(ctx, func(shr *querypb.StreamHealthResponse) error {
+		result = shr
+		return io.EOF
+	})
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if result == nil {
+		return nil, fmt.Errorf("health stream ended without a health record")
+	}
+	return result, nil
+}
+
+// vtgateTabletHealth is the part of a tablet health, as served by the
+// health-check API of vtgate, which the health gates look at.
+type vtgateTabletHealth struct {
+	Tablet *topodatapb.Tablet
+	// Target is the one the tablet serves, as reported by its health
+	// stream. The tablet type of the Tablet record can be older.
+	Target  *querypb.Target
+	Serving bool
+	Stats   *querypb.RealtimeStats
+}
+
+// vtgateCacheStatus is the health check cache status of vtgate, for a
+// keyspace, shard and tablet type.
+type vtgateCacheStatus struct {
+	TabletsStats []*vtgateTabletHealth
+}
+
+// vtgateHealth is part of the healthChecker interface.
+func (hc *tabletHealthChecker) vtgateHealth(ctx context.Context, url string, tablet *topodatapb.Tablet) error {
+	url = strings.TrimSuffix(url, "/") + "/api/health-check/keyspace/" + tablet.Keyspace
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health-check API returned status %v", resp.Status)
+	}
+
+	var cacheStatus []*vtgateCacheStatus
+	if err := json.NewDecoder(resp.Body).Decode(&cacheStatus); err != nil {
+		return fmt.Errorf("cannot decode the health-check API response: %v", err)
+	}
+	return checkVtgateTabletHealth(tablet, cacheStatus)
+}
+
+// checkVtgateTabletHealth looks for the tablet in the health check cache
+// of a vtgate, and returns an error if it is missing or not serving.
+func checkVtgateTabletHealth(tablet *topodatapb.Tablet, cacheStatus []*vtgateCacheStatus) error {
+	found := false
+	for _, status := range cacheStatus {
+		for _, th := range status.TabletsStats {
+			if th.Tablet == nil || !topoproto.TabletAliasEqual(th.Tablet.Alias, tablet.Alias) {
+				continue
+			}
+			found = true
+			// A tablet is in a status per tablet type it had. The restarted
+			// tablet must serve with its current one.
+			if th.Target == nil || th.Target.TabletType != tablet.Type {
+				continue
+			}
+			if !th.Serving {
+				return fmt.Errorf("tablet is not serving")
+			}
+			if th.Stats != nil && th.Stats.HealthError != "" {
+				return fmt.Errorf("tablet is not healthy: %v", th.Stats.HealthError)
+			}
+			return nil
+		}
+	}
+	if found {
+		return fmt.Errorf("tablet is not serving as %v", topoproto.TabletTypeLString(tablet.Type))
+	}
+	return fmt.Errorf("tablet is not in the health check cache")
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollingrestart
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/discovery"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestVtgateHealth(t *testing.T) {
+	newTablet := func(uid uint32, tabletType topodatapb.TabletType) *topodatapb.Tablet {
+		return &topodatapb.Tablet{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  uid,
+			},
+			Type:     tabletType,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		}
+	}
+
+	// The health check cache of vtgate, as served by its API.
+	cacheStatus := discovery.TabletsCacheStatusList{
+		{
+			Cell:   "zone1",
+			Target: &querypb.Target{Keyspace: "testkeyspace", Shard: "-", TabletType: topodatapb.TabletType_REPLICA},
+			TabletsStats: discovery.TabletStatsList{
+				{
+					Tablet:  newTablet(101, topodatapb.TabletType_REPLICA),
+					Target:  &querypb.Target{Keyspace: "testkeyspace", Shard: "-", TabletType: topodatapb.TabletType_REPLICA},
+					Serving: true,
+					Stats:   &querypb.RealtimeStats{},
+				},
+				{
+					Tablet:  newTablet(102, topodatapb.TabletType_REPLICA),
+					Target:  &querypb.Target{Keyspace: "testkeyspace", Shard: "-", TabletType: topodatapb.TabletType_REPLICA},
+					Serving: false,
+					Stats:   &querypb.RealtimeStats{},
+				},
+				{
+					Tablet:  newTablet(103, topodatapb.TabletType_REPLICA),
+					Target:  &querypb.Target{Keyspace: "testkeyspace", Shard: "-", TabletType: topodatapb.TabletType_REPLICA},
+					Serving: true,
+					Stats:   &querypb.RealtimeStats{HealthError: "mysqld is down"},
+				},
+				{
+					// The tablet record is older than the change of the
+					// tablet to RDONLY, which the target reports.
+					Tablet:  newTablet(104, topodatapb.TabletType_REPLICA),
+					Target:  &querypb.Target{Keyspace: "testkeyspace", Shard: "-", TabletType: topodatapb.TabletType_RDONLY},
+					Serving: true,
+					Stats:   &querypb.RealtimeStats{},
+				},
+			},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/health-check/keyspace/testkeyspace" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(cacheStatus))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		tablet *topodatapb.Tablet
+		url    string
+		err    string
+	}{
+		{
+			name:   "serving",
+			tablet: newTablet(101, topodatapb.TabletType_REPLICA),
+			url:    server.URL + "/",
+		},
+		{
+			name:   "not serving",
+			tablet: newTablet(102, topodatapb.TabletType_REPLICA),
+			url:    server.URL,
+			err:    "tablet is not serving",
+		},
+		{
+			name:   "health error",
+			tablet: newTablet(103, topodatapb.TabletType_REPLICA),
+			url:    server.URL,
+			err:    "tablet is not healthy: mysqld is down",
+		},
+		{
+			name:   "other tablet type",
+			tablet: newTablet(101, topodatapb.TabletType_RDONLY),
+			url:    server.URL,
+			err:    "tablet is not serving as rdonly",
+		},
+		{
+			name:   "tablet type changed",
+			tablet: newTablet(104, topodatapb.TabletType_REPLICA),
+			url:    server.URL,
+			err:    "tablet is not serving as replica",
+		},
+		{
+			name:   "missing tablet",
+			tablet: newTablet(105, topodatapb.TabletType_REPLICA),
+			url:    server.URL,
+			err:    "tablet is not in the health check cache",
+		},
+		{
+			name: "other keyspace",
+			tablet: &topodatapb.Tablet{
+				Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+				Keyspace: "otherkeyspace",
+			},
+			url: server.URL,
+			err: "health-check API returned status 404 Not Found",
+		},
+	}
+
+	hc := &tabletHealthChecker{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := hc.vtgateHealth(context.Background(), tt.url, tt.tablet)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
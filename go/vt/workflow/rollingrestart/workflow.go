@@ -0,0 +1,611 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rollingrestart contains a workflow that restarts the tablets
+// of a keyspace one at a time, for instance to upgrade them. In each
+// shard, it restarts the replicas first, then reparents away from the
+// primary with PlannedReparentShard, and restarts the former primary
+// last. A tablet is restarted by running a hook on it, through the
+// tabletmanager ExecuteHook RPC. The hook must block until the tablet is
+// restarted: the workflow cannot tell a restarted tablet from one which
+// is still to be restarted. It only moves on to the next tablet once the
+// restarted one passes the health gates: it must be serving, its
+// replication lag must be under a threshold, and the given vtgates must
+// see it as serving.
+package rollingrestart
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/vt/hook"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/workflow"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	workflowpb "vitess.io/vitess/go/vt/proto/workflow"
+)
+
+const (
+	rollingRestartFactoryName = "rolling_restart"
+
+	// codeVersion is the version of the checkpoint format. It must be
+	// increased when the tasks or settings change incompatibly.
+	codeVersion = 1
+
+	pauseAction  = "Pause"
+	resumeAction = "Resume"
+
+	restartAction  = "restart"
+	reparentAction = "reparent"
+)
+
+// Keys of the workflow settings in the checkpoint.
+const (
+	keyspaceSetting              = "keyspace"
+	shardsSetting                = "shards"
+	tasksSetting                 = "tasks"
+	hookSetting                  = "hook"
+	hookParamsSetting            = "hook_params"
+	maxReplicationLagSetting     = "max_replication_lag"
+	vtgateHealthCheckURLsSetting = "vtgate_health_check_urls"
+	healthCheckTimeoutSetting    = "health_check_timeout"
+	healthCheckIntervalSetting   = "health_check_interval"
+	waitBetweenTabletsSetting    = "wait_between_tablets"
+	waitReplicasTimeoutSetting   = "wait_replicas_timeout"
+	pausedSetting                = "paused"
+)
+
+// Keys of the task attributes in the checkpoint.
+const (
+	actionAttribute      = "action"
+	keyspaceAttribute    = "keyspace"
+	shardAttribute       = "shard"
+	tabletAliasAttribute = "tablet_alias"
+)
+
+// Register needs to be called to register the rolling restart factory.
+func Register() {
+	workflow.Register(rollingRestartFactoryName, &Factory{})
+}
+
+// Factory is the factory to create rolling restart workflows.
+type Factory struct{}
+
+// Init is part of the workflow.Factory interface. It computes the order
+// of the restarts from the topology, and saves it in the checkpoint.
+func (f *Factory) Init(m *workflow.Manager, w *workflowpb.Workflow, args []string) error {
+	subFlags := flag.NewFlagSet(rollingRestartFactoryName, flag.ContinueOnError)
+	keyspace := subFlags.String("keyspace", "", "Keyspace of the tablets to restart")
+	shards := subFlags.String("shards", "", "Comma-separated list of the shards to restart, one after the other. Defaults to all the shards of the keyspace")
+	hookName := subFlags.String("hook", "", "Name of the hook restarting a tablet, run with ExecuteHook. It must only return once the tablet is restarted, as the health gates are checked right after it")
+	hookParams := subFlags.String("hook_params", "", "Comma-separated list of parameters of the hook, as --name=value")
+	maxReplicationLag := subFlags.Duration("max_replication_lag", 30*time.Second, "Maximum replication lag of a restarted replica before the workflow moves on")
+	vtgateHealthCheckURLs := subFlags.String("vtgate_health_check_urls", "", "Comma-separated list of vtgate URLs, like http://vtgate:15001, which must see a restarted tablet as serving before the workflow moves on")
+	healthCheckTimeout := subFlags.Duration("health_check_timeout", 10*time.Minute, "How long to wait for a restarted tablet to pass the health gates")
+	healthCheckInterval := subFlags.Duration("health_check_interval", 5*time.Second, "Interval between the health checks of a restarted tablet")
+	waitBetweenTablets := subFlags.Duration("wait_between_tablets", 0, "How long to wait after a tablet is healthy before restarting the next one")
+	waitReplicasTimeout := subFlags.Duration("wait_replicas_timeout", 30*time.Second, "Time to wait for replicas to catch up in the PlannedReparentShard away from the primary")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if *keyspace == "" || *hookName == "" {
+		return fmt.Errorf("keyspace and hook name must be provided for the rolling restart")
+	}
+	if *healthCheckInterval <= 0 {
+		return fmt.Errorf("health_check_interval must be positive")
+	}
+
+	ctx := context.TODO()
+	ts := m.TopoServer()
+	var shardNames []string
+	if *shards != "" {
+		shardNames = strings.Split(*shards, ",")
+	} else {
+		var err error
+		shardNames, err = ts.GetShardNames(ctx, *keyspace)
+		if err != nil {
+			return err
+		}
+		sort.Strings(shardNames)
+	}
+	if len(shardNames) == 0 {
+		return fmt.Errorf("keyspace %v has no shards", *keyspace)
+	}
+
+	checkpoint := &workflowpb.WorkflowCheckpoint{
+		CodeVersion: codeVersion,
+		Tasks:       make(map[string]*workflowpb.Task),
+		Settings: map[string]string{
+			keyspaceSetting:              *keyspace,
+			shardsSetting:                strings.Join(shardNames, ","),
+			hookSetting:                  *hookName,
+			hookParamsSetting:            *hookParams,
+			maxReplicationLagSetting:     maxReplicationLag.String(),
+			vtgateHealthCheckURLsSetting: *vtgateHealthCheckURLs,
+			healthCheckTimeoutSetting:    healthCheckTimeout.String(),
+			healthCheckIntervalSetting:   healthCheckInterval.String(),
+			waitBetweenTabletsSetting:    waitBetweenTablets.String(),
+			waitReplicasTimeoutSetting:   waitReplicasTimeout.String(),
+			pausedSetting:                "false",
+		},
+	}
+	var taskIDs []string
+	for _, shard := range shardNames {
+		tasks, err := shardTasks(ctx, ts, *keyspace, shard)
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			checkpoint.Tasks[task.Id] = task
+			taskIDs = append(taskIDs, task.Id)
+		}
+	}
+	checkpoint.Settings[tasksSetting] = strings.Join(taskIDs, ",")
+
+	w.Name = fmt.Sprintf("Rolling restart of %v with hook %v", *keyspace, *hookName)
+	var err error
+	w.Data, err = proto.Marshal(checkpoint)
+	return err
+}
+
+// shardTasks returns the tasks of a shard, in order: the restarts of the
+// replicas, the ones which do not serve REPLICA traffic first, then the
+// reparent away from the primary, and then the restart of the former primary.
+// Tablets which are not in the serving graph (DRAINED, SPARE, BACKUP, ...)
+// are skipped, since they would never pass the health gates.
+func shardTasks(ctx context.Context, ts *topo.Server, keyspace, shard string) ([]*workflowpb.Task, error) {
+	si, err := ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	if !si.HasPrimary() {
+		return nil, fmt.Errorf("shard %v/%v has no primary", keyspace, shard)
+	}
+	tabletMap, err := ts.GetTabletMapForShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+
+	var replicas []*topodatapb.Tablet
+	hasReplica := false
+	for _, ti := range tabletMap {
+		if topoproto.TabletAliasEqual(ti.Alias, si.PrimaryAlias) || !topo.IsInServingGraph(ti.Type) {
+			continue
+		}
+		if ti.Type == topodatapb.TabletType_REPLICA {
+			hasReplica = true
+		}
+		replicas = append(replicas, ti.Tablet)
+	}
+	if !hasReplica {
+		return nil, fmt.Errorf("shard %v/%v has no REPLICA tablet to reparent to", keyspace, shard)
+	}
+	sort.Slice(replicas, func(i, j int) bool {
+		iReplica := replicas[i].Type == topodatapb.TabletType_REPLICA
+		jReplica := replicas[j].Type == topodatapb.TabletType_REPLICA
+		if iReplica != jReplica {
+			return jReplica
+		}
+		return topoproto.TabletAliasString(replicas[i].Alias) < topoproto.TabletAliasString(replicas[j].Alias)
+	})
+
+	newTask := func(id, action string, alias *topodatapb.TabletAlias) *workflowpb.Task {
+		return &workflowpb.Task{
+			Id:    path.Join(shard, id),
+			State: workflowpb.TaskState_TaskNotStarted,
+			Attributes: map[string]string{
+				actionAttribute:      action,
+				keyspaceAttribute:    keyspace,
+				shardAttribute:       shard,
+				tabletAliasAttribute: topoproto.TabletAliasString(alias),
+			},
+		}
+	}
+	var tasks []*workflowpb.Task
+	for _, replica := range replicas {
+		tasks = append(tasks, newTask(topoproto.TabletAliasString(replica.Alias), restartAction, replica.Alias))
+	}
+	tasks = append(tasks, newTask(reparentAction, reparentAction, si.PrimaryAlias))
+	tasks = append(tasks, newTask(topoproto.TabletAliasString(si.PrimaryAlias), restartAction, si.PrimaryAlias))
+	return tasks, nil
+}
+
+// Instantiate is part of the workflow.Factory interface.
+func (f *Factory) Instantiate(m *workflow.Manager, w *workflowpb.Workflow, rootNode *workflow.Node) (workflow.Workflow, error) {
+	rootNode.Message = "This workflow restarts the tablets of a keyspace one at a time, and reparents away from the primaries before restarting them."
+
+	checkpoint := &workflowpb.WorkflowCheckpoint{}
+	if err := proto.Unmarshal(w.Data, checkpoint); err != nil {
+		return nil, err
+	}
+	if checkpoint.CodeVersion != codeVersion {
+		return nil, fmt.Errorf("checkpoint of code version %v cannot be run by code version %v", checkpoint.CodeVersion, codeVersion)
+	}
+
+	rw := &Workflow{
+		topoServer: m.TopoServer(),
+		tmc:        tmclient.NewTabletManagerClient(),
+		logger:     logutil.NewMemoryLogger(),
+		checkpoint: checkpoint,
+		rootNode:   rootNode,
+		keyspace:   checkpoint.Settings[keyspaceSetting],
+		shards:     strings.Split(checkpoint.Settings[shardsSetting], ","),
+		taskIDs:    strings.Split(checkpoint.Settings[tasksSetting], ","),
+		hook:       hook.NewHook(checkpoint.Settings[hookSetting], nil),
+	}
+	rw.healthChecker = &tabletHealthChecker{tmc: rw.tmc}
+	if params := checkpoint.Settings[hookParamsSetting]; params != "" {
+		rw.hook.Parameters = strings.Split(params, ",")
+	}
+	if urls := checkpoint.Settings[vtgateHealthCheckURLsSetting]; urls != "" {
+		rw.vtgateHealthCheckURLs = strings.Split(urls, ",")
+	}
+	for setting, duration := range map[string]*time.Duration{
+		maxReplicationLagSetting:   &rw.maxReplicationLag,
+		healthCheckTimeoutSetting:  &rw.healthCheckTimeout,
+		healthCheckIntervalSetting: &rw.healthCheckInterval,
+		waitBetweenTabletsSetting:  &rw.waitBetweenTablets,
+		waitReplicasTimeoutSetting: &rw.waitReplicasTimeout,
+	} {
+		var err error
+		if *duration, err = time.ParseDuration(checkpoint.Settings[setting]); err != nil {
+			return nil, fmt.Errorf("invalid %v setting: %v", setting, err)
+		}
+	}
+	var err error
+	if rw.paused, err = strconv.ParseBool(checkpoint.Settings[pausedSetting]); err != nil {
+		return nil, fmt.Errorf("invalid %v setting: %v", pausedSetting, err)
+	}
+	if rw.paused {
+		rw.resumed = make(chan struct{})
+	}
+
+	for _, shard := range rw.shards {
+		shardNode := &workflow.Node{
+			Name:     fmt.Sprintf("Shard %v/%v", rw.keyspace, shard),
+			PathName: shard,
+		}
+		for _, task := range rw.shardTasks(shard) {
+			alias := task.Attributes[tabletAliasAttribute]
+			name := fmt.Sprintf("Restart %v", alias)
+			switch {
+			case task.Attributes[actionAttribute] == reparentAction:
+				name = fmt.Sprintf("PlannedReparentShard away from %v", alias)
+			case rw.isFormerPrimary(task):
+				name = fmt.Sprintf("Restart %v, the former primary", alias)
+			}
+			shardNode.Children = append(shardNode.Children, &workflow.Node{
+				Name:     name,
+				PathName: path.Base(task.Id),
+			})
+		}
+		rootNode.Children = append(rootNode.Children, shardNode)
+	}
+	return rw, nil
+}
+
+// Workflow is the rolling restart workflow. It implements workflow.Workflow.
+type Workflow struct {
+	topoServer    *topo.Server
+	tmc           tmclient.TabletManagerClient
+	healthChecker healthChecker
+	logger        *logutil.MemoryLogger
+
+	checkpoint       *workflowpb.WorkflowCheckpoint
+	checkpointWriter *workflow.CheckpointWriter
+	rootNode         *workflow.Node
+
+	keyspace              string
+	shards                []string
+	taskIDs               []string
+	hook                  *hook.Hook
+	maxReplicationLag     time.Duration
+	vtgateHealthCheckURLs []string
+	healthCheckTimeout    time.Duration
+	healthCheckInterval   time.Duration
+	waitBetweenTablets    time.Duration
+	waitReplicasTimeout   time.Duration
+
+	// mu protects paused and resumed, as both Run and Action can be
+	// called at the same time.
+	mu     sync.Mutex
+	paused bool
+	// resumed is closed when a paused workflow is resumed.
+	resumed chan struct{}
+}
+
+// Run is part of the workflow.Workflow interface. The shards are
+// restarted one after the other, and the tasks of a shard one at a time.
+func (rw *Workflow) Run(ctx context.Context, manager *workflow.Manager, wi *topo.WorkflowInfo) error {
+	rw.checkpointWriter = workflow.NewCheckpointWriter(rw.topoServer, rw.checkpoint, wi)
+
+	rw.mu.Lock()
+	rw.rootNode.Display = workflow.NodeDisplayNone
+	rw.rootNode.Listener = rw
+	rw.rootNode.Actions = []*workflow.Action{
+		{
+			Name:  pauseAction,
+			State: workflow.ActionStateEnabled,
+			Style: workflow.ActionStyleNormal,
+		},
+		{
+			Name:  resumeAction,
+			State: workflow.ActionStateDisabled,
+			Style: workflow.ActionStyleNormal,
+		},
+	}
+	rw.uiUpdateLocked()
+	rw.rootNode.BroadcastChanges(true /* updateChildren */)
+	rw.mu.Unlock()
+
+	for _, shard := range rw.shards {
+		runner := workflow.NewParallelRunner(ctx, rw.rootNode, rw.checkpointWriter, rw.shardTasks(shard), rw.runTask, workflow.Sequential, false /* enableApprovals */)
+		if err := runner.Run(); err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	rw.logger.Infof("All the tablets of keyspace %v have been restarted", rw.keyspace)
+	return nil
+}
+
+// Action is part of the workflow.ActionListener interface.
+// The pause state is saved in the checkpoint, so a paused workflow
+// stays paused if vtctld restarts.
+func (rw *Workflow) Action(ctx context.Context, path, name string) error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	paused := rw.paused
+	switch name {
+	case pauseAction:
+		if !paused {
+			rw.paused = true
+			rw.resumed = make(chan struct{})
+			rw.logger.Infof("Paused, the running task will complete")
+		}
+	case resumeAction:
+		if paused {
+			rw.paused = false
+			close(rw.resumed)
+			rw.logger.Infof("Resumed")
+		}
+	default:
+		return fmt.Errorf("unknown action: %v", name)
+	}
+	if paused == rw.paused {
+		return nil
+	}
+
+	rw.uiUpdateLocked()
+	rw.rootNode.BroadcastChanges(false /* updateChildren */)
+	return rw.checkpointWriter.UpdateSetting(pausedSetting, strconv.FormatBool(rw.paused))
+}
+
+// uiUpdateLocked updates the actions and log of the root node.
+// Needs to be called with the lock.
+func (rw *Workflow) uiUpdateLocked() {
+	rw.rootNode.Log = rw.logger.String()
+	rw.rootNode.Actions[0].State = workflow.ActionStateEnabled
+	rw.rootNode.Actions[1].State = workflow.ActionStateDisabled
+	rw.rootNode.ProgressMessage = ""
+	if rw.paused {
+		rw.rootNode.Actions[0].State = workflow.ActionStateDisabled
+		rw.rootNode.Actions[1].State = workflow.ActionStateEnabled
+		rw.rootNode.ProgressMessage = "paused"
+	}
+}
+
+// shardTasks returns the tasks of a shard, in order.
+func (rw *Workflow) shardTasks(shard string) []*workflowpb.Task {
+	var tasks []*workflowpb.Task
+	for _, id := range rw.taskIDs {
+		if path.Dir(id) == shard {
+			tasks = append(tasks, rw.checkpoint.Tasks[id])
+		}
+	}
+	return tasks
+}
+
+// isFormerPrimary returns whether a restart task is the one of the
+// primary the shard is reparented away from.
+func (rw *Workflow) isFormerPrimary(task *workflowpb.Task) bool {
+	reparentTask, ok := rw.checkpoint.Tasks[path.Join(path.Dir(task.Id), reparentAction)]
+	return ok && reparentTask.Attributes[tabletAliasAttribute] == task.Attributes[tabletAliasAttribute]
+}
+
+// waitWhilePaused blocks until the workflow is not paused.
+func (rw *Workflow) waitWhilePaused(ctx context.Context) error {
+	rw.mu.Lock()
+	paused, resumed := rw.paused, rw.resumed
+	rw.mu.Unlock()
+	if !paused {
+		return nil
+	}
+
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (rw *Workflow) runTask(ctx context.Context, task *workflowpb.Task) error {
+	if err := rw.waitWhilePaused(ctx); err != nil {
+		return err
+	}
+
+	alias, err := topoproto.ParseTabletAlias(task.Attributes[tabletAliasAttribute])
+	if err != nil {
+		return err
+	}
+	switch action := task.Attributes[actionAttribute]; action {
+	case restartAction:
+		err = rw.restartTablet(ctx, alias)
+	case reparentAction:
+		err = rw.reparentAway(ctx, task.Attributes[keyspaceAttribute], task.Attributes[shardAttribute], alias)
+	default:
+		err = fmt.Errorf("unknown action %v in task %v", action, task.Id)
+	}
+
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if err != nil {
+		rw.logger.Errorf("Task %v failed: %v", task.Id, err)
+	}
+	rw.rootNode.Log = rw.logger.String()
+	rw.rootNode.BroadcastChanges(false /* updateChildren */)
+	return err
+}
+
+// restartTablet runs the hook on the tablet, and waits for it to pass
+// the health gates. The hook returns once the tablet is restarted, so the
+// health gates are not checked against the tablet before its restart.
+// It refuses to restart the primary of the shard.
+func (rw *Workflow) restartTablet(ctx context.Context, alias *topodatapb.TabletAlias) error {
+	ti, err := rw.topoServer.GetTablet(ctx, alias)
+	if err != nil {
+		return err
+	}
+	si, err := rw.topoServer.GetShard(ctx, ti.Keyspace, ti.Shard)
+	if err != nil {
+		return err
+	}
+	if topoproto.TabletAliasEqual(si.PrimaryAlias, alias) {
+		return fmt.Errorf("tablet %v is the primary of %v/%v, it must be reparented away from before its restart", topoproto.TabletAliasString(alias), ti.Keyspace, ti.Shard)
+	}
+
+	rw.logger.Infof("Running hook %v on %v", rw.hook.Name, topoproto.TabletAliasString(alias))
+	hr, err := rw.tmc.ExecuteHook(ctx, ti.Tablet, rw.hook)
+	if err != nil {
+		return fmt.Errorf("hook %v failed on %v: %v", rw.hook.Name, topoproto.TabletAliasString(alias), err)
+	}
+	if hr.ExitStatus != hook.HOOK_SUCCESS {
+		return fmt.Errorf("hook %v failed on %v with exit status %v: %v", rw.hook.Name, topoproto.TabletAliasString(alias), hr.ExitStatus, hr.Stderr)
+	}
+
+	if err := rw.waitForHealthy(ctx, alias); err != nil {
+		return err
+	}
+	rw.logger.Infof("Tablet %v is healthy", topoproto.TabletAliasString(alias))
+
+	if rw.waitBetweenTablets > 0 {
+		select {
+		case <-time.After(rw.waitBetweenTablets):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// waitForHealthy checks the health gates of a tablet until they pass,
+// or until the health check timeout.
+func (rw *Workflow) waitForHealthy(ctx context.Context, alias *topodatapb.TabletAlias) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, rw.healthCheckTimeout)
+	defer cancel()
+
+	for {
+		err := rw.checkHealth(timeoutCtx, alias)
+		if err == nil {
+			return nil
+		}
+		log.Infof("Tablet %v is not healthy yet: %v", topoproto.TabletAliasString(alias), err)
+
+		select {
+		case <-time.After(rw.healthCheckInterval):
+		case <-timeoutCtx.Done():
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return fmt.Errorf("tablet %v is not healthy after %v: %v", topoproto.TabletAliasString(alias), rw.healthCheckTimeout, err)
+		}
+	}
+}
+
+// checkHealth checks the health gates of a tablet once. The tablet is
+// read again from the topo, as a restart can change its address.
+func (rw *Workflow) checkHealth(ctx context.Context, alias *topodatapb.TabletAlias) error {
+	ti, err := rw.topoServer.GetTablet(ctx, alias)
+	if err != nil {
+		return err
+	}
+
+	shr, err := rw.healthChecker.tabletHealth(ctx, ti.Tablet)
+	if err != nil {
+		return err
+	}
+	if !shr.Serving {
+		return fmt.Errorf("tablet is not serving")
+	}
+	if shr.RealtimeStats == nil {
+		return fmt.Errorf("health record does not include RealtimeStats")
+	}
+	if shr.RealtimeStats.HealthError != "" {
+		return fmt.Errorf("tablet is not healthy: %v", shr.RealtimeStats.HealthError)
+	}
+	if ti.Type != topodatapb.TabletType_PRIMARY {
+		if lag := time.Duration(shr.RealtimeStats.ReplicationLagSeconds) * time.Second; lag > rw.maxReplicationLag {
+			return fmt.Errorf("replication lag %v is above %v", lag, rw.maxReplicationLag)
+		}
+	}
+
+	for _, url := range rw.vtgateHealthCheckURLs {
+		if err := rw.healthChecker.vtgateHealth(ctx, url, ti.Tablet); err != nil {
+			return fmt.Errorf("vtgate %v: %v", url, err)
+		}
+	}
+	return nil
+}
+
+// reparentAway reparents a shard away from the given primary. It is a no-op
+// if the tablet is not the primary anymore, e.g. when the task is retried.
+func (rw *Workflow) reparentAway(ctx context.Context, keyspace, shard string, primary *topodatapb.TabletAlias) error {
+	rw.logger.Infof("PlannedReparentShard of %v/%v away from %v", keyspace, shard, topoproto.TabletAliasString(primary))
+	ev, err := reparentutil.NewPlannedReparenter(rw.topoServer, rw.tmc, rw.logger).ReparentShard(ctx, keyspace, shard, reparentutil.PlannedReparentOptions{
+		AvoidPrimaryAlias:   primary,
+		WaitReplicasTimeout: rw.waitReplicasTimeout,
+	})
+	if err != nil {
+		return err
+	}
+	if ev.NewPrimary != nil {
+		rw.logger.Infof("Tablet %v is the new primary of %v/%v", topoproto.TabletAliasString(ev.NewPrimary.Alias), keyspace, shard)
+	}
+	return nil
+}
+
+// Compile time interface check.
+var _ workflow.Factory = (*Factory)(nil)
+var _ workflow.Workflow = (*Workflow)(nil)
@@ -0,0 +1,358 @@
+/*
+Copyright 2022 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollingrestart
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/vt/hook"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver/testutil"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/workflow"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/proto/vttime"
+	workflowpb "vitess.io/vitess/go/vt/proto/workflow"
+)
+
+func init() {
+	// The workflows use the fake TabletManagerClient set by the tests.
+	*tmclient.TabletManagerProtocol = "rollingrestart.test"
+	tmclient.RegisterTabletManagerClientFactory("rollingrestart.test", func() tmclient.TabletManagerClient {
+		return nil
+	})
+	Register()
+}
+
+// fakeHealthChecker reports the tablets as healthy, except for the lag of
+// the first health checks of laggingTablet. It calls onHealthy the first
+// time a tablet is healthy.
+type fakeHealthChecker struct {
+	mu            sync.Mutex
+	laggingTablet string
+	lagChecks     int
+	healthy       []string
+	onHealthy     func(alias string)
+}
+
+func (hc *fakeHealthChecker) tabletHealth(ctx context.Context, tablet *topodatapb.Tablet) (*querypb.StreamHealthResponse, error) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	shr := &querypb.StreamHealthResponse{
+		Serving:       true,
+		RealtimeStats: &querypb.RealtimeStats{},
+	}
+	if topoproto.TabletAliasString(tablet.Alias) == hc.laggingTablet && hc.lagChecks > 0 {
+		hc.lagChecks--
+		shr.RealtimeStats.ReplicationLagSeconds = 3600
+	}
+	return shr, nil
+}
+
+func (hc *fakeHealthChecker) vtgateHealth(ctx context.Context, url string, tablet *topodatapb.Tablet) error {
+	hc.mu.Lock()
+	alias := topoproto.TabletAliasString(tablet.Alias)
+	hc.healthy = append(hc.healthy, alias)
+	onHealthy := hc.onHealthy
+	hc.mu.Unlock()
+
+	if onHealthy != nil {
+		onHealthy(alias)
+	}
+	return nil
+}
+
+func (hc *fakeHealthChecker) healthyTablets() []string {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	return append([]string(nil), hc.healthy...)
+}
+
+// promotingTabletManagerClient updates the shard record on PromoteReplica,
+// like a promoted tablet does.
+type promotingTabletManagerClient struct {
+	*testutil.TabletManagerClient
+	ts *topo.Server
+}
+
+func (tmc *promotingTabletManagerClient) PromoteReplica(ctx context.Context, tablet *topodatapb.Tablet, semiSync bool) (string, error) {
+	pos, err := tmc.TabletManagerClient.PromoteReplica(ctx, tablet, semiSync)
+	if err != nil {
+		return "", err
+	}
+	_, err = tmc.ts.UpdateShardFields(ctx, tablet.Keyspace, tablet.Shard, func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = tablet.Alias
+		return nil
+	})
+	return pos, err
+}
+
+func addTestTablets(ctx context.Context, t *testing.T, ts *topo.Server) {
+	tablet := func(uid uint32, tabletType topodatapb.TabletType) *topodatapb.Tablet {
+		return &topodatapb.Tablet{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  uid,
+			},
+			Type:     tabletType,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		}
+	}
+	primary := tablet(100, topodatapb.TabletType_PRIMARY)
+	primary.PrimaryTermStartTime = &vttime.Time{Seconds: 100}
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary: true,
+	},
+		primary,
+		tablet(101, topodatapb.TabletType_REPLICA),
+		tablet(102, topodatapb.TabletType_RDONLY),
+		tablet(103, topodatapb.TabletType_REPLICA),
+		// Not serving, so it is not restarted.
+		tablet(104, topodatapb.TabletType_DRAINED),
+	)
+}
+
+func readCheckpoint(ctx context.Context, t *testing.T, ts *topo.Server, uuid string) *workflowpb.WorkflowCheckpoint {
+	wi, err := ts.GetWorkflow(ctx, uuid)
+	require.NoError(t, err)
+	checkpoint := &workflowpb.WorkflowCheckpoint{}
+	require.NoError(t, proto.Unmarshal(wi.Data, checkpoint))
+	return checkpoint
+}
+
+// waitForTaskFinished blocks until the UI shows the task of the given node
+// as finished, which happens once its state is saved in the checkpoint.
+func waitForTaskFinished(t *testing.T, notifications chan []byte, pathName string) {
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case data, ok := <-notifications:
+			require.True(t, ok, "the UI notifications were closed")
+			update := &workflow.Update{}
+			require.NoError(t, json.Unmarshal(data, update))
+			for _, node := range update.Nodes {
+				if node.PathName == pathName && node.Message == "task finished" {
+					return
+				}
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for task %v to finish", pathName)
+		}
+	}
+}
+
+func TestInit(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("zone1")
+	addTestTablets(ctx, t, ts)
+
+	m := workflow.NewManager(ts)
+	uuid, err := m.Create(ctx, rollingRestartFactoryName, []string{"--keyspace=testkeyspace", "--hook=restart_tablet", "--hook_params=--version=15"})
+	require.NoError(t, err)
+
+	checkpoint := readCheckpoint(ctx, t, ts, uuid)
+	assert.Equal(t, "-/zone1-0000000102,-/zone1-0000000101,-/zone1-0000000103,-/reparent,-/zone1-0000000100", checkpoint.Settings[tasksSetting])
+	assert.Equal(t, map[string]string{
+		actionAttribute:      reparentAction,
+		keyspaceAttribute:    "testkeyspace",
+		shardAttribute:       "-",
+		tabletAliasAttribute: "zone1-0000000100",
+	}, checkpoint.Tasks["-/reparent"].Attributes)
+
+	w, err := m.WorkflowForTesting(uuid)
+	require.NoError(t, err)
+	rw := w.(*Workflow)
+	assert.Equal(t, hook.NewHook("restart_tablet", []string{"--version=15"}), rw.hook)
+	assert.Equal(t, 30*time.Second, rw.maxReplicationLag)
+
+	_, err = m.Create(ctx, rollingRestartFactoryName, []string{"--keyspace=testkeyspace"})
+	assert.EqualError(t, err, "keyspace and hook name must be provided for the rolling restart")
+	_, err = m.Create(ctx, rollingRestartFactoryName, []string{"--keyspace=testkeyspace", "--hook=restart_tablet", "--shards=-80"})
+	assert.Error(t, err)
+}
+
+func TestRollingRestart(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("zone1")
+	addTestTablets(ctx, t, ts)
+
+	m := workflow.NewManager(ts)
+	wg, _, cancel := workflow.StartManager(m)
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	uuid, err := m.Create(ctx, rollingRestartFactoryName, []string{
+		"--keyspace=testkeyspace",
+		"--hook=restart_tablet",
+		"--vtgate_health_check_urls=http://vtgate:15001",
+		"--health_check_interval=10ms",
+		"--wait_replicas_timeout=10ms",
+	})
+	require.NoError(t, err)
+
+	hookResults := make(map[string]struct {
+		Response *hook.HookResult
+		Error    error
+	})
+	for _, alias := range []string{"zone1-0000000100", "zone1-0000000101", "zone1-0000000102", "zone1-0000000103"} {
+		hookResults[alias] = struct {
+			Response *hook.HookResult
+			Error    error
+		}{
+			Response: &hook.HookResult{ExitStatus: hook.HOOK_SUCCESS},
+		}
+	}
+	tmc := &testutil.TabletManagerClient{
+		TopoServer:         ts,
+		ExecuteHookResults: hookResults,
+		DemotePrimaryResults: map[string]struct {
+			Status *replicationdatapb.PrimaryStatus
+			Error  error
+		}{
+			"zone1-0000000100": {
+				Status: &replicationdatapb.PrimaryStatus{
+					Position: "primary-demotion position",
+				},
+			},
+		},
+		PrimaryPositionResults: map[string]struct {
+			Position string
+			Error    error
+		}{
+			"zone1-0000000100": {
+				Position: "doesn't matter",
+			},
+		},
+		PopulateReparentJournalResults: map[string]error{
+			"zone1-0000000101": nil,
+		},
+		PromoteReplicaResults: map[string]struct {
+			Result string
+			Error  error
+		}{
+			"zone1-0000000101": {
+				Result: "promotion position",
+			},
+		},
+		ReplicationStatusResults: map[string]struct {
+			Position *replicationdatapb.Status
+			Error    error
+		}{
+			"zone1-0000000101": {
+				Position: &replicationdatapb.Status{
+					Position: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-10",
+				},
+			},
+			"zone1-0000000103": {
+				Position: &replicationdatapb.Status{
+					Position: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5",
+				},
+			},
+		},
+		SetReplicationSourceResults: map[string]error{
+			"zone1-0000000100": nil,
+			"zone1-0000000101": nil,
+			"zone1-0000000102": nil,
+			"zone1-0000000103": nil,
+			"zone1-0000000104": nil,
+		},
+		WaitForPositionResults: map[string]map[string]error{
+			"zone1-0000000101": {
+				"primary-demotion position": nil,
+			},
+		},
+	}
+
+	// Pause the workflow once the first tablet is healthy, before its
+	// task returns, so the next task must wait for the resume.
+	hc := &fakeHealthChecker{
+		laggingTablet: "zone1-0000000101",
+		lagChecks:     2,
+	}
+	hc.onHealthy = func(alias string) {
+		if alias != "zone1-0000000102" {
+			return
+		}
+		err := m.NodeManager().Action(ctx, &workflow.ActionParameters{
+			Path: "/" + uuid,
+			Name: pauseAction,
+		})
+		assert.NoError(t, err)
+	}
+
+	w, err := m.WorkflowForTesting(uuid)
+	require.NoError(t, err)
+	rw := w.(*Workflow)
+	rw.tmc = &promotingTabletManagerClient{
+		TabletManagerClient: tmc,
+		ts:                  ts,
+	}
+	rw.healthChecker = hc
+
+	notifications := make(chan []byte, 100)
+	_, index, err := m.NodeManager().GetAndWatchFullTree(notifications)
+	require.NoError(t, err)
+	require.NoError(t, m.Start(ctx, uuid))
+	waitForTaskFinished(t, notifications, "zone1-0000000102")
+	m.NodeManager().CloseWatcher(index)
+
+	assert.Equal(t, []string{"zone1-0000000102"}, hc.healthyTablets())
+	checkpoint := readCheckpoint(ctx, t, ts, uuid)
+	assert.Equal(t, "true", checkpoint.Settings[pausedSetting])
+	assert.Equal(t, workflowpb.TaskState_TaskDone, checkpoint.Tasks["-/zone1-0000000102"].State)
+
+	require.NoError(t, m.NodeManager().Action(ctx, &workflow.ActionParameters{
+		Path: "/" + uuid,
+		Name: resumeAction,
+	}))
+	waitCtx, waitCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer waitCancel()
+	require.NoError(t, m.Wait(waitCtx, uuid))
+
+	require.NoError(t, workflow.VerifyAllTasksDone(ctx, ts, uuid))
+	assert.Equal(t, []string{"zone1-0000000102", "zone1-0000000101", "zone1-0000000103", "zone1-0000000100"}, hc.healthyTablets())
+	checkpoint = readCheckpoint(ctx, t, ts, uuid)
+	assert.Equal(t, "false", checkpoint.Settings[pausedSetting])
+
+	si, err := ts.GetShard(ctx, "testkeyspace", "-")
+	require.NoError(t, err)
+	assert.Equal(t, "zone1-0000000101", topoproto.TabletAliasString(si.PrimaryAlias))
+
+	wi, err := ts.GetWorkflow(ctx, uuid)
+	require.NoError(t, err)
+	assert.Equal(t, workflowpb.WorkflowState_Done, wi.State)
+	assert.Empty(t, wi.Error)
+	assert.True(t, strings.Contains(rw.logger.String(), "Tablet zone1-0000000101 is the new primary of testkeyspace/-"), rw.logger.String())
+}
@@ -30,7 +30,7 @@ import (
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools"
-	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtctl/workflow"
 )
 
 const (
@@ -42,53 +42,8 @@ const (
 
 // validateNewWorkflow ensures that the specified workflow doesn't already exist
 // in the keyspace.
-func (wr *Wrangler) validateNewWorkflow(ctx context.Context, keyspace, workflow string) error {
-	allshards, err := wr.ts.FindAllShardsInKeyspace(ctx, keyspace)
-	if err != nil {
-		return err
-	}
-	var wg sync.WaitGroup
-	allErrors := &concurrency.AllErrorRecorder{}
-	for _, si := range allshards {
-		if si.PrimaryAlias == nil {
-			allErrors.RecordError(fmt.Errorf("shard has no primary: %v", si.ShardName()))
-			continue
-		}
-		wg.Add(1)
-		go func(si *topo.ShardInfo) {
-			defer wg.Done()
-
-			primary, err := wr.ts.GetTablet(ctx, si.PrimaryAlias)
-			if err != nil {
-				allErrors.RecordError(vterrors.Wrap(err, "validateWorkflowName.GetTablet"))
-				return
-			}
-			validations := []struct {
-				query string
-				msg   string
-			}{{
-				fmt.Sprintf("select 1 from _vt.vreplication where db_name=%s and workflow=%s", encodeString(primary.DbName()), encodeString(workflow)),
-				fmt.Sprintf("workflow %s already exists in keyspace %s on tablet %d", workflow, keyspace, primary.Alias.Uid),
-			}, {
-				fmt.Sprintf("select 1 from _vt.vreplication where db_name=%s and message='FROZEN'", encodeString(primary.DbName())),
-				fmt.Sprintf("found previous frozen workflow on tablet %d, please review and delete it first before creating a new workflow",
-					primary.Alias.Uid),
-			}}
-			for _, validation := range validations {
-				p3qr, err := wr.tmc.VReplicationExec(ctx, primary.Tablet, validation.query)
-				if err != nil {
-					allErrors.RecordError(vterrors.Wrap(err, "validateWorkflowName.VReplicationExec"))
-					return
-				}
-				if p3qr != nil && len(p3qr.Rows) != 0 {
-					allErrors.RecordError(vterrors.Wrap(fmt.Errorf(validation.msg), "validateWorkflowName.VReplicationExec"))
-					return
-				}
-			}
-		}(si)
-	}
-	wg.Wait()
-	return allErrors.AggrError(vterrors.Aggregate)
+func (wr *Wrangler) validateNewWorkflow(ctx context.Context, keyspace, workflowName string) error {
+	return workflow.ValidateNewWorkflow(ctx, wr.ts, wr.tmc, keyspace, workflowName)
 }
 
 func (wr *Wrangler) printShards(ctx context.Context, si []*topo.ShardInfo) error {
@@ -35,6 +35,7 @@ import (
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtctl/workflow"
 )
 
 const mzUpdateQuery = "update _vt.vreplication set state='Running' where db_name='vt_targetks' and workflow='workflow'"
@@ -566,7 +567,7 @@ func TestCreateLookupVindexCreateDDL(t *testing.T) {
 			delete(env.tmc.schema, ms.SourceKeyspace+".t1")
 		}
 
-		outms, _, _, err := env.wr.prepareCreateLookup(context.Background(), ms.SourceKeyspace, tcase.specs, false)
+		outms, _, _, err := workflow.PrepareCreateLookup(context.Background(), env.wr.ts, env.wr.tmc, ms.SourceKeyspace, tcase.specs, false)
 		if tcase.err != "" {
 			if err == nil || !strings.Contains(err.Error(), tcase.err) {
 				t.Errorf("prepareCreateLookup(%s) err: %v, must contain %v", tcase.description, err, tcase.err)
@@ -808,7 +809,7 @@ func TestCreateLookupVindexSourceVSchema(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		_, got, _, err := env.wr.prepareCreateLookup(context.Background(), ms.SourceKeyspace, specs, false)
+		_, got, _, err := workflow.PrepareCreateLookup(context.Background(), env.wr.ts, env.wr.tmc, ms.SourceKeyspace, specs, false)
 		require.NoError(t, err)
 		if !proto.Equal(got, tcase.out) {
 			t.Errorf("%s: got:\n%v, want\n%v", tcase.description, got, tcase.out)
@@ -1041,7 +1042,7 @@ func TestCreateLookupVindexTargetVSchema(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		_, _, got, err := env.wr.prepareCreateLookup(context.Background(), ms.SourceKeyspace, specs, false)
+		_, _, got, err := workflow.PrepareCreateLookup(context.Background(), env.wr.ts, env.wr.tmc, ms.SourceKeyspace, specs, false)
 		if tcase.err != "" {
 			if err == nil || !strings.Contains(err.Error(), tcase.err) {
 				t.Errorf("prepareCreateLookup(%s) err: %v, must contain %v", tcase.description, err, tcase.err)
@@ -1156,7 +1157,7 @@ func TestCreateLookupVindexSameKeyspace(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, got, _, err := env.wr.prepareCreateLookup(context.Background(), ms.SourceKeyspace, specs, false)
+	_, got, _, err := workflow.PrepareCreateLookup(context.Background(), env.wr.ts, env.wr.tmc, ms.SourceKeyspace, specs, false)
 	require.NoError(t, err)
 	if !proto.Equal(got, want) {
 		t.Errorf("same keyspace: got:\n%v, want\n%v", got, want)
@@ -1267,7 +1268,7 @@ func TestCreateCustomizedVindex(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, got, _, err := env.wr.prepareCreateLookup(context.Background(), ms.SourceKeyspace, specs, false)
+	_, got, _, err := workflow.PrepareCreateLookup(context.Background(), env.wr.ts, env.wr.tmc, ms.SourceKeyspace, specs, false)
 	require.NoError(t, err)
 	if !proto.Equal(got, want) {
 		t.Errorf("customize create lookup error same: got:\n%v, want\n%v", got, want)
@@ -1341,11 +1342,11 @@ func TestStopAfterCopyFlag(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	ms1, _, _, err := env.wr.prepareCreateLookup(context.Background(), ms.SourceKeyspace, specs, false)
+	ms1, _, _, err := workflow.PrepareCreateLookup(context.Background(), env.wr.ts, env.wr.tmc, ms.SourceKeyspace, specs, false)
 	require.NoError(t, err)
 	require.Equal(t, ms1.StopAfterCopy, true)
 
-	ms2, _, _, err := env.wr.prepareCreateLookup(context.Background(), ms.SourceKeyspace, specs, true)
+	ms2, _, _, err := workflow.PrepareCreateLookup(context.Background(), env.wr.ts, env.wr.tmc, ms.SourceKeyspace, specs, true)
 	require.NoError(t, err)
 	require.Equal(t, ms2.StopAfterCopy, false)
 }
@@ -2550,145 +2551,6 @@ func TestMaterializerNoVindexInExpression(t *testing.T) {
 	require.EqualError(t, err, "could not find vindex column c1")
 }
 
-func TestStripForeignKeys(t *testing.T) {
-	tcs := []struct {
-		desc string
-		ddl  string
-
-		hasErr bool
-		newDDL string
-	}{
-		{
-			desc: "has FK constraints",
-			ddl: "CREATE TABLE `table1` (\n" +
-				"`id` int(11) NOT NULL AUTO_INCREMENT,\n" +
-				"`foreign_id` int(11) CHECK (foreign_id>10),\n" +
-				"PRIMARY KEY (`id`),\n" +
-				"KEY `fk_table1_ref_foreign_id` (`foreign_id`),\n" +
-				"CONSTRAINT `fk_table1_ref_foreign_id` FOREIGN KEY (`foreign_id`) REFERENCES `foreign` (`id`)\n" +
-				") ENGINE=InnoDB DEFAULT CHARSET=latin1;",
-
-			newDDL: "create table table1 (\n" +
-				"\tid int(11) not null auto_increment,\n" +
-				"\tforeign_id int(11),\n" +
-				"\tPRIMARY KEY (id),\n" +
-				"\tKEY fk_table1_ref_foreign_id (foreign_id),\n" +
-				"\tcheck (foreign_id > 10)\n" +
-				") ENGINE InnoDB,\n" +
-				"  CHARSET latin1",
-
-			hasErr: false,
-		},
-		{
-			desc: "no FK constraints",
-			ddl: "CREATE TABLE `table1` (\n" +
-				"`id` int(11) NOT NULL AUTO_INCREMENT,\n" +
-				"`foreign_id` int(11) NOT NULL  CHECK (foreign_id>10),\n" +
-				"`user_id` int(11) NOT NULL,\n" +
-				"PRIMARY KEY (`id`),\n" +
-				"KEY `fk_table1_ref_foreign_id` (`foreign_id`),\n" +
-				"KEY `fk_table1_ref_user_id` (`user_id`)\n" +
-				") ENGINE=InnoDB DEFAULT CHARSET=latin1;",
-
-			newDDL: "create table table1 (\n" +
-				"\tid int(11) not null auto_increment,\n" +
-				"\tforeign_id int(11) not null,\n" +
-				"\tuser_id int(11) not null,\n" +
-				"\tPRIMARY KEY (id),\n" +
-				"\tKEY fk_table1_ref_foreign_id (foreign_id),\n" +
-				"\tKEY fk_table1_ref_user_id (user_id),\n" +
-				"\tcheck (foreign_id > 10)\n" +
-				") ENGINE InnoDB,\n" +
-				"  CHARSET latin1",
-		},
-	}
-
-	for _, tc := range tcs {
-		newDDL, err := stripTableForeignKeys(tc.ddl)
-		if tc.hasErr != (err != nil) {
-			t.Fatalf("hasErr does not match: err: %v, tc: %+v", err, tc)
-		}
-
-		if newDDL != tc.newDDL {
-			utils.MustMatch(t, tc.newDDL, newDDL, fmt.Sprintf("newDDL does not match. tc: %+v", tc))
-		}
-	}
-}
-
-func TestStripConstraints(t *testing.T) {
-	tcs := []struct {
-		desc string
-		ddl  string
-
-		hasErr bool
-		newDDL string
-	}{
-		{
-			desc: "constraints",
-			ddl: "CREATE TABLE `table1` (\n" +
-				"`id` int(11) NOT NULL AUTO_INCREMENT,\n" +
-				"`foreign_id` int(11) NOT NULL,\n" +
-				"`user_id` int(11) NOT NULL,\n" +
-				"PRIMARY KEY (`id`),\n" +
-				"KEY `fk_table1_ref_foreign_id` (`foreign_id`),\n" +
-				"KEY `fk_table1_ref_user_id` (`user_id`),\n" +
-				"CONSTRAINT `fk_table1_ref_foreign_id` FOREIGN KEY (`foreign_id`) REFERENCES `foreign` (`id`),\n" +
-				"CONSTRAINT `fk_table1_ref_user_id` FOREIGN KEY (`user_id`) REFERENCES `core_user` (`id`)\n" +
-				") ENGINE=InnoDB DEFAULT CHARSET=latin1;",
-
-			newDDL: "create table table1 (\n" +
-				"\tid int(11) not null auto_increment,\n" +
-				"\tforeign_id int(11) not null,\n" +
-				"\tuser_id int(11) not null,\n" +
-				"\tPRIMARY KEY (id),\n" +
-				"\tKEY fk_table1_ref_foreign_id (foreign_id),\n" +
-				"\tKEY fk_table1_ref_user_id (user_id)\n" +
-				") ENGINE InnoDB,\n" +
-				"  CHARSET latin1",
-
-			hasErr: false,
-		},
-		{
-			desc: "no constraints",
-			ddl: "CREATE TABLE `table1` (\n" +
-				"`id` int(11) NOT NULL AUTO_INCREMENT,\n" +
-				"`foreign_id` int(11) NOT NULL,\n" +
-				"`user_id` int(11) NOT NULL,\n" +
-				"PRIMARY KEY (`id`),\n" +
-				"KEY `fk_table1_ref_foreign_id` (`foreign_id`),\n" +
-				"KEY `fk_table1_ref_user_id` (`user_id`)\n" +
-				") ENGINE=InnoDB DEFAULT CHARSET=latin1;",
-
-			newDDL: "create table table1 (\n" +
-				"\tid int(11) not null auto_increment,\n" +
-				"\tforeign_id int(11) not null,\n" +
-				"\tuser_id int(11) not null,\n" +
-				"\tPRIMARY KEY (id),\n" +
-				"\tKEY fk_table1_ref_foreign_id (foreign_id),\n" +
-				"\tKEY fk_table1_ref_user_id (user_id)\n" +
-				") ENGINE InnoDB,\n" +
-				"  CHARSET latin1",
-		},
-		{
-			desc: "bad ddl has error",
-			ddl:  "bad ddl",
-
-			hasErr: true,
-		},
-	}
-
-	for _, tc := range tcs {
-		newDDL, err := stripTableConstraints(tc.ddl)
-		if tc.hasErr != (err != nil) {
-			t.Fatalf("hasErr does not match: err: %v, tc: %+v", err, tc)
-		}
-
-		if newDDL != tc.newDDL {
-			utils.MustMatch(t, tc.newDDL, newDDL, fmt.Sprintf("newDDL does not match. tc: %+v", tc))
-		}
-	}
-}
-
 func TestMaterializerManyToManySomeUnreachable(t *testing.T) {
 	ms := &vtctldatapb.MaterializeSettings{
 		Workflow:       "workflow",